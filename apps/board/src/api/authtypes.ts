@@ -0,0 +1,37 @@
+// Code generated by ggen. DO NOT EDIT.
+
+import type * as rpc from './rpc';
+
+export interface GetSessionRequest {
+}
+
+export type Role = 'editor' | 'owner' | 'viewer';
+
+export interface PageRole {
+  page_id: rpc.IntID;
+  role: Role;
+}
+
+export interface Principal {
+  email?: string;
+  name?: string;
+  api_key?: string;
+  workspace_id?: rpc.IntID;
+  admin?: boolean;
+  roles?: PageRole[];
+}
+
+export interface SessionResponse {
+  principal?: Principal;
+}
+
+export interface LoginRequest {
+  email: string;
+  password: string;
+}
+
+export interface LoginResponse {
+  token: string;
+  expires_at: rpc.Timestamp;
+  principal?: Principal;
+}
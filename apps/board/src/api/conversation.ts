@@ -0,0 +1,26 @@
+// Code generated by ggen. DO NOT EDIT.
+
+import type * as conversationtypes from './conversationtypes';
+import type * as rpc from './rpc';
+
+export interface ConversationService {
+  getTranscript(req: conversationtypes.GetTranscriptRequest): Promise<conversationtypes.TranscriptResponse>;
+  listConversations(req: conversationtypes.ListConversationsRequest): Promise<conversationtypes.ListConversationsResponse>;
+  searchMessages(req: conversationtypes.SearchMessagesRequest): Promise<conversationtypes.SearchMessagesResponse>;
+}
+
+export class ConversationServiceClient implements ConversationService {
+  constructor(readonly client: rpc.Client) {}
+
+  getTranscript(req: conversationtypes.GetTranscriptRequest): Promise<conversationtypes.TranscriptResponse> {
+    return this.client.call<conversationtypes.TranscriptResponse>('/api/conversation/GetTranscript', req);
+  }
+
+  listConversations(req: conversationtypes.ListConversationsRequest): Promise<conversationtypes.ListConversationsResponse> {
+    return this.client.call<conversationtypes.ListConversationsResponse>('/api/conversation/ListConversations', req);
+  }
+
+  searchMessages(req: conversationtypes.SearchMessagesRequest): Promise<conversationtypes.SearchMessagesResponse> {
+    return this.client.call<conversationtypes.SearchMessagesResponse>('/api/conversation/SearchMessages', req);
+  }
+}
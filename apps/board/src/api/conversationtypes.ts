@@ -0,0 +1,65 @@
+// Code generated by ggen. DO NOT EDIT.
+
+import type * as rpc from './rpc';
+
+export interface GetTranscriptRequest {
+  page_id: rpc.IntID;
+  psid: rpc.IntID;
+  offset: number;
+  limit: number;
+}
+
+export type Direction = 'in' | 'out';
+
+export type MessageStatus = 'delivered' | 'read' | 'received' | 'sent';
+
+export interface Message {
+  id: rpc.IntID;
+  workspace_id?: rpc.IntID;
+  page_id: rpc.IntID;
+  psid: rpc.IntID;
+  mid?: string;
+  direction: Direction;
+  text: string;
+  payload?: string;
+  flow_id?: rpc.IntID;
+  node_id?: rpc.IntID;
+  agent?: string;
+  status: MessageStatus;
+  created_at: rpc.Timestamp;
+  delivered_at?: rpc.Timestamp;
+  read_at?: rpc.Timestamp;
+}
+
+export interface TranscriptResponse {
+  messages: Message[];
+  total: number;
+  has_more: boolean;
+}
+
+export interface ListConversationsRequest {
+  page_id: rpc.IntID;
+}
+
+export interface Conversation {
+  page_id: rpc.IntID;
+  psid: rpc.IntID;
+  message_count: number;
+  last_message?: Message;
+  updated_at: rpc.Timestamp;
+}
+
+export interface ListConversationsResponse {
+  conversations: Conversation[];
+}
+
+export interface SearchMessagesRequest {
+  page_id: rpc.IntID;
+  psid: rpc.IntID;
+  query: string;
+  limit: number;
+}
+
+export interface SearchMessagesResponse {
+  messages: Message[];
+}
@@ -0,0 +1,36 @@
+// Code generated by ggen. DO NOT EDIT.
+
+import type * as eventhooktypes from './eventhooktypes';
+import type * as rpc from './rpc';
+
+export interface EventHookService {
+  createSubscription(req: eventhooktypes.CreateSubscriptionRequest): Promise<eventhooktypes.SubscriptionResponse>;
+  deleteSubscription(req: eventhooktypes.DeleteSubscriptionRequest): Promise<eventhooktypes.DeleteSubscriptionResponse>;
+  listDeliveries(req: eventhooktypes.ListDeliveriesRequest): Promise<eventhooktypes.ListDeliveriesResponse>;
+  listSubscriptions(req: eventhooktypes.ListSubscriptionsRequest): Promise<eventhooktypes.ListSubscriptionsResponse>;
+  updateSubscription(req: eventhooktypes.UpdateSubscriptionRequest): Promise<eventhooktypes.SubscriptionResponse>;
+}
+
+export class EventHookServiceClient implements EventHookService {
+  constructor(readonly client: rpc.Client) {}
+
+  createSubscription(req: eventhooktypes.CreateSubscriptionRequest): Promise<eventhooktypes.SubscriptionResponse> {
+    return this.client.call<eventhooktypes.SubscriptionResponse>('/api/eventhook/CreateSubscription', req);
+  }
+
+  deleteSubscription(req: eventhooktypes.DeleteSubscriptionRequest): Promise<eventhooktypes.DeleteSubscriptionResponse> {
+    return this.client.call<eventhooktypes.DeleteSubscriptionResponse>('/api/eventhook/DeleteSubscription', req);
+  }
+
+  listDeliveries(req: eventhooktypes.ListDeliveriesRequest): Promise<eventhooktypes.ListDeliveriesResponse> {
+    return this.client.call<eventhooktypes.ListDeliveriesResponse>('/api/eventhook/ListDeliveries', req);
+  }
+
+  listSubscriptions(req: eventhooktypes.ListSubscriptionsRequest): Promise<eventhooktypes.ListSubscriptionsResponse> {
+    return this.client.call<eventhooktypes.ListSubscriptionsResponse>('/api/eventhook/ListSubscriptions', req);
+  }
+
+  updateSubscription(req: eventhooktypes.UpdateSubscriptionRequest): Promise<eventhooktypes.SubscriptionResponse> {
+    return this.client.call<eventhooktypes.SubscriptionResponse>('/api/eventhook/UpdateSubscription', req);
+  }
+}
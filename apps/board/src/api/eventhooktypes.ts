@@ -0,0 +1,88 @@
+// Code generated by ggen. DO NOT EDIT.
+
+import type * as flowexectypes from './flowexectypes';
+import type * as rpc from './rpc';
+
+export interface CreateSubscriptionRequest {
+  page_id: rpc.IntID;
+  flow_id: rpc.IntID;
+  url: string;
+  secret: string;
+  event_types: flowexectypes.FlowEventType[];
+  disabled: boolean;
+}
+
+export interface Subscription {
+  id: rpc.IntID;
+  workspace_id?: rpc.IntID;
+  page_id: rpc.IntID;
+  flow_id?: rpc.IntID;
+  url: string;
+  secret: string;
+  event_types?: flowexectypes.FlowEventType[];
+  disabled?: boolean;
+  created_at: rpc.Timestamp;
+  updated_at: rpc.Timestamp;
+}
+
+export interface SubscriptionResponse {
+  subscription?: Subscription;
+}
+
+export interface DeleteSubscriptionRequest {
+  id: rpc.IntID;
+  page_id: rpc.IntID;
+}
+
+export interface DeleteSubscriptionResponse {
+  deleted: number;
+}
+
+export type DeliveryStatus = 'failed' | 'pending' | 'succeeded';
+
+export interface ListDeliveriesRequest {
+  page_id: rpc.IntID;
+  subscription_id: rpc.IntID;
+  status: DeliveryStatus;
+  limit: number;
+}
+
+export interface Delivery {
+  id: rpc.IntID;
+  workspace_id?: rpc.IntID;
+  subscription_id: rpc.IntID;
+  page_id: rpc.IntID;
+  event_id: rpc.IntID;
+  event_type: flowexectypes.FlowEventType;
+  url: string;
+  payload: string;
+  status: DeliveryStatus;
+  attempts: number;
+  status_code?: number;
+  error?: string;
+  next_attempt_at?: rpc.Timestamp;
+  created_at: rpc.Timestamp;
+  updated_at: rpc.Timestamp;
+}
+
+export interface ListDeliveriesResponse {
+  deliveries: Delivery[];
+}
+
+export interface ListSubscriptionsRequest {
+  page_id: rpc.IntID;
+}
+
+export interface ListSubscriptionsResponse {
+  subscriptions: Subscription[];
+}
+
+export interface UpdateSubscriptionRequest {
+  id: rpc.IntID;
+  page_id: rpc.IntID;
+  flow_id: rpc.IntID;
+  url: string;
+  secret: string;
+  event_types: flowexectypes.FlowEventType[];
+  disabled: boolean;
+}
@@ -0,0 +1,48 @@
+// Code generated by ggen. DO NOT EDIT.
+
+import type * as flowdeftypes from './flowdeftypes';
+import type * as rpc from './rpc';
+
+export interface EditorService {
+  analyzeFlow(req: flowdeftypes.AnalyzeFlowRequest): Promise<flowdeftypes.AnalyzeFlowResponse>;
+  createFlow(req: flowdeftypes.CreateFlowRequest): Promise<flowdeftypes.CreateFlowResponse>;
+  updateFlow(req: flowdeftypes.CreateFlowRequest): Promise<flowdeftypes.CreateFlowResponse>;
+}
+
+export class EditorServiceClient implements EditorService {
+  constructor(readonly client: rpc.Client) {}
+
+  analyzeFlow(req: flowdeftypes.AnalyzeFlowRequest): Promise<flowdeftypes.AnalyzeFlowResponse> {
+    return this.client.call<flowdeftypes.AnalyzeFlowResponse>('/api/flow/def/editor/AnalyzeFlow', req);
+  }
+
+  createFlow(req: flowdeftypes.CreateFlowRequest): Promise<flowdeftypes.CreateFlowResponse> {
+    return this.client.call<flowdeftypes.CreateFlowResponse>('/api/flow/def/editor/CreateFlow', req);
+  }
+
+  updateFlow(req: flowdeftypes.CreateFlowRequest): Promise<flowdeftypes.CreateFlowResponse> {
+    return this.client.call<flowdeftypes.CreateFlowResponse>('/api/flow/def/editor/UpdateFlow', req);
+  }
+}
+
+export interface QueryService {
+  getFlowByID(req: flowdeftypes.GetFlowByIDRequest): Promise<flowdeftypes.FlowResponse>;
+  getFlowByParam(req: flowdeftypes.GetFlowByParamRequest): Promise<flowdeftypes.FlowResponse>;
+  listFlows(req: flowdeftypes.ListFlowsRequest): Promise<flowdeftypes.ListFlowsResponse>;
+}
+
+export class QueryServiceClient implements QueryService {
+  constructor(readonly client: rpc.Client) {}
+
+  getFlowByID(req: flowdeftypes.GetFlowByIDRequest): Promise<flowdeftypes.FlowResponse> {
+    return this.client.call<flowdeftypes.FlowResponse>('/api/flow/def/query/GetFlowByID', req);
+  }
+
+  getFlowByParam(req: flowdeftypes.GetFlowByParamRequest): Promise<flowdeftypes.FlowResponse> {
+    return this.client.call<flowdeftypes.FlowResponse>('/api/flow/def/query/GetFlowByParam', req);
+  }
+
+  listFlows(req: flowdeftypes.ListFlowsRequest): Promise<flowdeftypes.ListFlowsResponse> {
+    return this.client.call<flowdeftypes.ListFlowsResponse>('/api/flow/def/query/ListFlows', req);
+  }
+}
@@ -0,0 +1,31 @@
+// Code generated by ggen. DO NOT EDIT.
+
+import type * as reviewtypes from './reviewtypes';
+import type * as rpc from './rpc';
+
+export interface ReviewService {
+  exportReviews(req: reviewtypes.ExportReviewsRequest): Promise<reviewtypes.ExportReviewsResponse>;
+  getReviewSummary(req: reviewtypes.GetReviewSummaryRequest): Promise<reviewtypes.ReviewSummaryResponse>;
+  listReviews(req: reviewtypes.ListReviewsRequest): Promise<reviewtypes.ListReviewsResponse>;
+  submitReview(req: reviewtypes.SubmitReviewRequest): Promise<reviewtypes.ReviewResponse>;
+}
+
+export class ReviewServiceClient implements ReviewService {
+  constructor(readonly client: rpc.Client) {}
+
+  exportReviews(req: reviewtypes.ExportReviewsRequest): Promise<reviewtypes.ExportReviewsResponse> {
+    return this.client.call<reviewtypes.ExportReviewsResponse>('/api/review/ExportReviews', req);
+  }
+
+  getReviewSummary(req: reviewtypes.GetReviewSummaryRequest): Promise<reviewtypes.ReviewSummaryResponse> {
+    return this.client.call<reviewtypes.ReviewSummaryResponse>('/api/review/GetReviewSummary', req);
+  }
+
+  listReviews(req: reviewtypes.ListReviewsRequest): Promise<reviewtypes.ListReviewsResponse> {
+    return this.client.call<reviewtypes.ListReviewsResponse>('/api/review/ListReviews', req);
+  }
+
+  submitReview(req: reviewtypes.SubmitReviewRequest): Promise<reviewtypes.ReviewResponse> {
+    return this.client.call<reviewtypes.ReviewResponse>('/api/review/SubmitReview', req);
+  }
+}
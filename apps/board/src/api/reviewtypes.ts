@@ -0,0 +1,68 @@
+// Code generated by ggen. DO NOT EDIT.
+
+import type * as rpc from './rpc';
+
+export type ExportFormat = 'csv' | 'json';
+
+export interface ExportReviewsRequest {
+  page_id: rpc.IntID;
+  format: ExportFormat;
+}
+
+export interface ExportReviewsResponse {
+  format: ExportFormat;
+  content_type: string;
+  content: string;
+}
+
+export interface GetReviewSummaryRequest {
+  page_id: rpc.IntID;
+}
+
+export interface ReviewSummaryResponse {
+  page_id: rpc.IntID;
+  count: number;
+  average: number;
+  distribution: number[];
+}
+
+export interface ListReviewsRequest {
+  page_id: rpc.IntID;
+  order_id: string;
+  min_rating: number;
+  max_rating: number;
+}
+
+export interface Review {
+  id: rpc.IntID;
+  workspace_id?: rpc.IntID;
+  page_id: rpc.IntID;
+  flow_id: rpc.IntID;
+  node_id: rpc.IntID;
+  psid: rpc.IntID;
+  order_id?: string;
+  customer_ref?: string;
+  rating: number;
+  comment?: string;
+  created_at: rpc.Timestamp;
+  updated_at: rpc.Timestamp;
+}
+
+export interface ListReviewsResponse {
+  reviews: Review[];
+}
+
+export interface SubmitReviewRequest {
+  page_id: rpc.IntID;
+  flow_id: rpc.IntID;
+  node_id: rpc.IntID;
+  psid: rpc.IntID;
+  order_id: string;
+  customer_ref: string;
+  rating: number;
+  comment: string;
+}
+
+export interface ReviewResponse {
+  review?: Review;
+}
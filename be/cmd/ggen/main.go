@@ -13,6 +13,7 @@ import (
 var flClean = flag.Bool("clean", false, "clean generated files without generating new files")
 var flPlugins = flag.String("plugins", "", "comma separated list of plugins for generating (default to all plugins)")
 var flNamespace = flag.String("namespace", "", "only parse and generate packages under this namespace (example: github.com/foo)")
+var flTSOut = flag.String("ts-out", "../apps/board/src/api", "output directory of the typescript plugin")
 
 func usage() {
 	const text = `
@@ -25,8 +26,12 @@ Options:
 }
 
 func main() {
+	flag.Parse()
 	Start(
 		genapi.New(),
+		genapi.NewTypeScript(*flTSOut),
+		genapi.NewOpenAPI(),
+		genapi.NewValidate(),
 	)
 }
 
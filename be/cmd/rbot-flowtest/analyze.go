@@ -0,0 +1,87 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/olvrng/rbot/be/com/flowdef/flowgraph"
+	"github.com/olvrng/rbot/be/com/flowdef/types"
+	"github.com/olvrng/rbot/be/com/flowexec/flowtest"
+	"github.com/olvrng/rbot/be/pkg/dot"
+)
+
+func analyzeUsage(fs *flag.FlagSet) func() {
+	return func() {
+		const text = `
+Usage: rbot-flowtest analyze [OPTION] FLOW_FILE
+
+Reports the cycles without waiting node, the dead ends, the unreachable nodes,
+the broken links and the longest path of the flow, or renders its graph.
+Exits with code 1 when the flow has a problem.
+
+Options:
+`
+		fmt.Print(text[1:])
+		fs.PrintDefaults()
+	}
+}
+
+func analyzeMain(args []string) {
+	fs := flag.NewFlagSet("analyze", flag.ExitOnError)
+	flFormat := fs.String("format", "text", "output format: text, dot or mermaid")
+	flFlowID := fs.Int64("flow-id", 0, "the flow to analyze, when the file has several flows")
+	fs.Usage = analyzeUsage(fs)
+	_ = fs.Parse(args)
+	if fs.NArg() != 1 {
+		fs.Usage()
+		os.Exit(2)
+	}
+
+	suite := &flowtest.Suite{Path: fs.Arg(0), FlowID: dot.IntID(*flFlowID)}
+	flow, err := suite.LoadFlow(fs.Arg(0))
+	must(err)
+
+	g := flowgraph.Build(flow)
+	report := g.Analyze()
+	if *flFormat == "text" {
+		printReport(flow, report)
+	} else {
+		graph, err := g.Render(types.GraphFormat(*flFormat))
+		must(err)
+		fmt.Print(graph)
+	}
+	if report.HasProblems() {
+		os.Exit(1)
+	}
+}
+
+func printReport(flow *types.Flow, r *types.FlowReport) {
+	fmt.Printf("flow %v: %v nodes, %v links\n", flow.ID, r.NodeCount, r.LinkCount)
+	for _, c := range r.Cycles {
+		if c.Waiting {
+			fmt.Printf("cycle: %v\n", joinIDs(c.NodeIDs, ", "))
+		} else {
+			fmt.Printf("cycle without waiting node: %v\n", joinIDs(c.NodeIDs, ", "))
+		}
+	}
+	if len(r.DeadEnds) != 0 {
+		fmt.Printf("dead ends: %v\n", joinIDs(r.DeadEnds, ", "))
+	}
+	if len(r.Unreachable) != 0 {
+		fmt.Printf("unreachable: %v\n", joinIDs(r.Unreachable, ", "))
+	}
+	for _, b := range r.BrokenLinks {
+		fmt.Printf("broken link: %v -> %v\n", b.NodeID, b.Link.NextID)
+	}
+	fmt.Printf("longest path: %v\n", joinIDs(r.LongestPath, " -> "))
+}
+
+func joinIDs(ids []dot.IntID, sep string) string {
+	s := make([]string, len(ids))
+	for i, id := range ids {
+		s[i] = fmt.Sprint(id)
+	}
+	return strings.Join(s, sep)
+}
@@ -0,0 +1,137 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/olvrng/rbot/be/com/flowexec/flowtest"
+)
+
+var flFlowFile = flag.String("flow-file", "", "default flow file, for the fixtures without flow_file")
+var flJUnit = flag.String("junit", "", "write the results to this file in the JUnit XML format")
+var flVerbose = flag.Bool("v", false, "print the passed cases too")
+
+func usage() {
+	const text = `
+Usage: rbot-flowtest [OPTION] FILE|DIR ...
+       rbot-flowtest analyze [OPTION] FLOW_FILE
+
+Runs the flow test fixtures (*.yaml, *.yml), or the fixtures in the directories.
+Exits with code 1 when a test fails.
+
+Options:
+`
+	fmt.Print(text[1:])
+	flag.PrintDefaults()
+}
+
+func main() {
+	if len(os.Args) > 1 && os.Args[1] == "analyze" {
+		analyzeMain(os.Args[2:])
+		return
+	}
+
+	flag.Usage = usage
+	flag.Parse()
+	if flag.NArg() == 0 {
+		usage()
+		os.Exit(2)
+	}
+
+	paths, err := listFixtures(flag.Args())
+	must(err)
+
+	ctx := context.Background()
+	var results []*flowtest.SuiteResult
+	failed := 0
+	for _, path := range paths {
+		result := runSuite(ctx, path)
+		results = append(results, result)
+		failed += printResult(result)
+	}
+
+	if *flJUnit != "" {
+		f, err := os.Create(*flJUnit)
+		must(err)
+		must(flowtest.WriteJUnit(f, results))
+		must(f.Close())
+	}
+	if failed != 0 {
+		fmt.Printf("FAIL (%v failed)\n", failed)
+		os.Exit(1)
+	}
+	fmt.Println("PASS")
+}
+
+func runSuite(ctx context.Context, path string) *flowtest.SuiteResult {
+	suite, err := flowtest.LoadSuite(path)
+	if err != nil {
+		name := strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+		return &flowtest.SuiteResult{Suite: &flowtest.Suite{Name: name, Path: path}, Error: err}
+	}
+	flow, err := suite.LoadFlow(*flFlowFile)
+	if err != nil {
+		return &flowtest.SuiteResult{Suite: suite, Error: err}
+	}
+	return flowtest.RunSuite(ctx, suite, flow)
+}
+
+// printResult prints the result like go test, and returns the number of
+// failures.
+func printResult(r *flowtest.SuiteResult) int {
+	if r.Error != nil {
+		fmt.Printf("--- ERROR: %v\n\t%v\n", r.Suite.Name, r.Error)
+		return 1
+	}
+	for _, c := range r.Cases {
+		switch {
+		case c.Failed():
+			fmt.Printf("--- FAIL: %v/%v (%.2fs)\n", r.Suite.Name, c.Case.Name, c.Duration.Seconds())
+			for _, failure := range c.Failures {
+				fmt.Printf("\t%v\n", failure)
+			}
+		case *flVerbose:
+			fmt.Printf("--- PASS: %v/%v (%.2fs)\n", r.Suite.Name, c.Case.Name, c.Duration.Seconds())
+		}
+	}
+	return r.Failed()
+}
+
+func listFixtures(args []string) ([]string, error) {
+	var paths []string
+	for _, arg := range args {
+		info, err := os.Stat(arg)
+		if err != nil {
+			return nil, err
+		}
+		if !info.IsDir() {
+			paths = append(paths, arg)
+			continue
+		}
+		err = filepath.Walk(arg, func(path string, info os.FileInfo, err error) error {
+			if err != nil {
+				return err
+			}
+			ext := filepath.Ext(path)
+			if !info.IsDir() && (ext == ".yaml" || ext == ".yml") {
+				paths = append(paths, path)
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+	return paths, nil
+}
+
+func must(err error) {
+	if err != nil {
+		fmt.Printf("%+v\n", err)
+		os.Exit(2)
+	}
+}
@@ -5,27 +5,74 @@ import (
 	"io/ioutil"
 	"os"
 	"path/filepath"
+	"strings"
 
 	"gopkg.in/yaml.v2"
 
+	authtypes "github.com/olvrng/rbot/be/com/auth/types"
 	"github.com/olvrng/rbot/be/pkg/xconfig"
 )
 
 type Config struct {
-	HTTP       HTTP      `yaml:"http"`
-	Messenger  Messenger `yaml:"messenger"`
-	StaticPath string    `yaml:"static_path"`
+	HTTP        HTTP        `yaml:"http"`
+	Messenger   Messenger   `yaml:"messenger"`
+	HTTPRequest HTTPRequest `yaml:"http_request"`
+	Auth        Auth        `yaml:"auth"`
+	StaticPath  string      `yaml:"static_path"`
 }
 
+// Messenger configures the Messenger Platform. RefSecret signs the refs of the
+// m.me links and the checkbox plugin, which link the customers of the shop to
+// their conversation.
 type Messenger struct {
 	VerifyToken     string `yaml:"verify_token"`
 	PageAccessToken string `yaml:"page_access_token"`
+	RefSecret       string `yaml:"ref_secret"`
 }
 
 func (m *Messenger) MustLoadEnv(prefix string) {
 	xconfig.EnvMap{
 		prefix + "_VERIFY_TOKEN":      &m.VerifyToken,
 		prefix + "_PAGE_ACCESS_TOKEN": &m.PageAccessToken,
+		prefix + "_REF_SECRET":        &m.RefSecret,
+	}.MustLoad()
+}
+
+// HTTPRequest configures the http_request nodes of the flows. The requests to
+// other hosts than AllowedHosts fail.
+type HTTPRequest struct {
+	AllowedHosts []string `yaml:"allowed_hosts"`
+}
+
+// MustLoadEnv loads the allowed hosts from a comma-separated list.
+func (h *HTTPRequest) MustLoadEnv(prefix string) {
+	var hosts string
+	xconfig.EnvMap{
+		prefix + "_ALLOWED_HOSTS": &hosts,
+	}.MustLoad()
+	if hosts != "" {
+		h.AllowedHosts = strings.Split(hosts, ",")
+	}
+}
+
+// Auth configures the users of the dashboard and the API keys of the servers.
+// The passwords are hashed with "rbot-server -hash-password". The API is open
+// when there is neither a JWT secret nor an API key, for local development.
+// Without a JWT secret, the users can not log in and the session tokens are
+// rejected.
+type Auth struct {
+	JWTSecret string              `yaml:"jwt_secret"`
+	Users     []*authtypes.User   `yaml:"users"`
+	APIKeys   []*authtypes.APIKey `yaml:"api_keys"`
+}
+
+func (a *Auth) Enabled() bool {
+	return a.JWTSecret != "" || len(a.APIKeys) != 0
+}
+
+func (a *Auth) MustLoadEnv(prefix string) {
+	xconfig.EnvMap{
+		prefix + "_JWT_SECRET": &a.JWTSecret,
 	}.MustLoad()
 }
 
@@ -74,5 +121,7 @@ func Load(filename string) (Config, error) {
 	}
 	cfg.HTTP.MustLoadEnv("HTTP")
 	cfg.Messenger.MustLoadEnv("MESSENGER")
+	cfg.HTTPRequest.MustLoadEnv("HTTP_REQUEST")
+	cfg.Auth.MustLoadEnv("AUTH")
 	return cfg, nil
 }
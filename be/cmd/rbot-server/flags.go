@@ -11,12 +11,32 @@ const usage = "Usage: rbot-server [args ...]"
 var flConfigFile = ""
 var flFlowFile = ""
 var flStateFile = ""
+var flOrderFile = ""
+var flLinkFile = ""
+var flReviewFile = ""
+var flTimerFile = ""
+var flHandoffFile = ""
+var flMessageFile = ""
+var flSubscriptionFile = ""
+var flDeliveryFile = ""
+var flWorkspaceFile = ""
+var flHashPassword = false
 var flHelp = false
 
 func initFlags() {
 	flag.StringVar(&flConfigFile, "config-file", "", "path to config file")
 	flag.StringVar(&flFlowFile, "flow-file", "./rbot-flow-data.json", "path to flow data file")
 	flag.StringVar(&flStateFile, "state-file", "./rbot-state-data.json", "path to state data file")
+	flag.StringVar(&flOrderFile, "order-file", "./rbot-order-data.json", "path to order data file")
+	flag.StringVar(&flLinkFile, "link-file", "./rbot-link-data.json", "path to customer link data file")
+	flag.StringVar(&flReviewFile, "review-file", "./rbot-review-data.json", "path to review data file")
+	flag.StringVar(&flTimerFile, "timer-file", "./rbot-timer-data.json", "path to timer data file")
+	flag.StringVar(&flHandoffFile, "handoff-file", "./rbot-handoff-data.json", "path to handoff data file")
+	flag.StringVar(&flMessageFile, "message-file", "./rbot-message-data.json", "path to message data file")
+	flag.StringVar(&flSubscriptionFile, "subscription-file", "./rbot-subscription-data.json", "path to event subscription data file")
+	flag.StringVar(&flDeliveryFile, "delivery-file", "./rbot-delivery-data.json", "path to event delivery log file")
+	flag.StringVar(&flWorkspaceFile, "workspace-file", "./rbot-workspace-data.json", "path to workspace data file")
+	flag.BoolVar(&flHashPassword, "hash-password", false, "read a password from stdin, print its hash for the auth config, then exit")
 	flag.BoolVar(&flHelp, "help", false, "")
 	flag.Parse()
 
@@ -1,8 +1,11 @@
 package main
 
 import (
+	"bufio"
 	"context"
+	"fmt"
 	"net/http"
+	"os"
 	"path/filepath"
 	"strings"
 	"time"
@@ -11,25 +14,52 @@ import (
 	"github.com/go-chi/chi/middleware"
 
 	"github.com/olvrng/rbot/be/cmd/rbot-server/config"
+	authservice "github.com/olvrng/rbot/be/com/auth/service"
+	authtypes "github.com/olvrng/rbot/be/com/auth/types"
+	conversationservice "github.com/olvrng/rbot/be/com/conversation/service"
+	conversationstore "github.com/olvrng/rbot/be/com/conversation/store"
+	eventhookservice "github.com/olvrng/rbot/be/com/eventhook/service"
+	eventhookstore "github.com/olvrng/rbot/be/com/eventhook/store"
 	"github.com/olvrng/rbot/be/com/flowdef/service"
 	flowdefstore "github.com/olvrng/rbot/be/com/flowdef/store"
 	flowexecservice "github.com/olvrng/rbot/be/com/flowexec/service"
 	"github.com/olvrng/rbot/be/com/flowexec/store"
 	"github.com/olvrng/rbot/be/com/integration/fbmsg"
 	"github.com/olvrng/rbot/be/com/integration/webhook"
+	reviewservice "github.com/olvrng/rbot/be/com/review/service"
+	reviewstore "github.com/olvrng/rbot/be/com/review/store"
+	workspaceservice "github.com/olvrng/rbot/be/com/workspace/service"
+	workspacestore "github.com/olvrng/rbot/be/com/workspace/store"
+	"github.com/olvrng/rbot/be/pkg/authn"
+	"github.com/olvrng/rbot/be/pkg/clock"
 	"github.com/olvrng/rbot/be/pkg/httprpc"
 	"github.com/olvrng/rbot/be/pkg/l"
 	"github.com/olvrng/rbot/be/pkg/lifecycle"
+	"github.com/olvrng/rbot/be/pkg/metrics"
+	"github.com/olvrng/rbot/be/pkg/openapi"
 )
 
 var ll = l.New()
 var ls = ll.Sugar()
 
+// metricsHooks are created once, as they register the metrics of the requests.
+var metricsHooks = httprpc.NewMetricsHooks(metrics.Default)
+
+var apiInfo = openapi.Info{
+	Title:       "rbot",
+	Description: "The api of the rbot server, generated from the httprpc services.",
+	Version:     "1.0.0",
+}
+
 func main() {
 	ll.Debug("enable debug log")
 
 	// load config
 	initFlags()
+	if flHashPassword {
+		hashPassword()
+		return
+	}
 	cfg, err := config.Load(flConfigFile)
 	ll.Must("can not load config", err)
 
@@ -38,7 +68,10 @@ func main() {
 	lifecycle.ListenForSignal(ctxCancel, 5*time.Second)
 
 	// messenger client, webhook
-	msgClient, err := fbmsg.NewClient(fbmsg.Config(cfg.Messenger))
+	msgClient, err := fbmsg.NewClient(fbmsg.Config{
+		VerifyToken:     cfg.Messenger.VerifyToken,
+		PageAccessToken: cfg.Messenger.PageAccessToken,
+	})
 	ll.Must("can not create messenger client", err)
 
 	// build server
@@ -46,28 +79,14 @@ func main() {
 	mux.Use(middleware.Logger)
 	mux.Use(middleware.Recoverer)
 
-	msgWebhook := buildAPIServer(cfg, mux, msgClient)
+	msgWebhook := buildAPIServer(ctx, cfg, mux, msgClient)
 	mux.Get("/api/webhook/messenger", msgWebhook.HandleVerification)
 	mux.Post("/api/webhook/messenger", msgWebhook.HandleWebhook)
 
-	httpMux := http.NewServeMux()
-	httpMux.Handle("/api/", mux)
-
-	// static file
-	fileHandler := http.FileServer(http.Dir(cfg.StaticPath))
-	httpMux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
-		path := r.URL.Path
-		if strings.Contains(path, ".") {
-			fileHandler.ServeHTTP(w, r)
-		} else {
-			http.ServeFile(w, r, filepath.Join(cfg.StaticPath, "index.html"))
-		}
-	})
-
 	// start http server
 	httpServer := &http.Server{
 		Addr:    cfg.HTTP.ListeningAddress(),
-		Handler: httpMux,
+		Handler: buildHTTPHandler(cfg, mux),
 	}
 	ll.Info("server is listening at " + cfg.HTTP.ListeningAddress())
 	go func() {
@@ -81,22 +100,112 @@ func main() {
 	ll.Info("server is shutting down...")
 }
 
-func buildAPIServer(cfg config.Config, m *chi.Mux, msgClient *fbmsg.Client) *webhook.WebhookService {
+// buildHTTPHandler serves the api, the metrics and the static files of the
+// board app.
+func buildHTTPHandler(cfg config.Config, apiMux http.Handler) http.Handler {
+	httpMux := http.NewServeMux()
+	httpMux.Handle("/api/", apiMux)
+	httpMux.Handle("/metrics", metrics.Handler(metrics.Default))
+
+	// static file
+	fileHandler := http.FileServer(http.Dir(cfg.StaticPath))
+	httpMux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		path := r.URL.Path
+		if strings.Contains(path, ".") {
+			fileHandler.ServeHTTP(w, r)
+		} else {
+			http.ServeFile(w, r, filepath.Join(cfg.StaticPath, "index.html"))
+		}
+	})
+	return httpMux
+}
+
+func buildAPIServer(ctx context.Context, cfg config.Config, m *chi.Mux, msgClient *fbmsg.Client) *webhook.WebhookService {
 	flowStore, err := flowdefstore.NewFlowFileStore(flFlowFile)
 	ll.Must("can not open flow data file", err)
 	stateStore, err := store.NewFlowStateStore(flStateFile)
 	ll.Must("can not open state data file", err)
-
-	actionExec := flowexecservice.NewActionExecutor(msgClient)
-	flowService := service.NewFlowEditorService(flowStore)
+	orderStore, err := store.NewOrderStore(flOrderFile)
+	ll.Must("can not open order data file", err)
+	linkStore, err := store.NewCustomerLinkStore(flLinkFile)
+	ll.Must("can not open link data file", err)
+	reviewStore, err := reviewstore.NewReviewStore(flReviewFile)
+	ll.Must("can not open review data file", err)
+	timerStore, err := store.NewTimerStore(flTimerFile)
+	ll.Must("can not open timer data file", err)
+	handoffStore, err := store.NewHandoffStore(flHandoffFile)
+	ll.Must("can not open handoff data file", err)
+	messageStore, err := conversationstore.NewMessageStore(flMessageFile)
+	ll.Must("can not open message data file", err)
+	subscriptionStore, err := eventhookstore.NewSubscriptionStore(flSubscriptionFile)
+	ll.Must("can not open subscription data file", err)
+	deliveryStore, err := eventhookstore.NewDeliveryStore(flDeliveryFile)
+	ll.Must("can not open delivery data file", err)
+	workspaceStore, err := workspacestore.NewWorkspaceStore(flWorkspaceFile)
+	ll.Must("can not open workspace data file", err)
+
+	// the flow events are posted to the subscribed webhooks
+	bus := flowexecservice.NewEventBus(clock.System)
+	eventHookService := eventhookservice.NewEventHookService(subscriptionStore, deliveryStore)
+	dispatcher := eventhookservice.NewDispatcher(subscriptionStore, deliveryStore, clock.System)
+	bus.Subscribe(dispatcher.HandleEvent)
+	go dispatcher.Run(ctx)
+
+	customerService := flowexecservice.NewCustomerService(linkStore, orderStore)
+	customerService.RefSecret = cfg.Messenger.RefSecret
+	if customerService.RefSecret == "" {
+		ll.Warn("the customers are not linked by the refs of the m.me links (set messenger.ref_secret in the config)")
+	}
+	reviewService := reviewservice.NewReviewService(reviewStore)
+	conversationService := conversationservice.NewConversationService(messageStore)
+	actionExec := flowexecservice.NewActionExecutor(msgClient, customerService, conversationService, bus)
+	actionExec.HTTP = flowexecservice.NewHTTPClient(cfg.HTTPRequest.AllowedHosts)
+	flowService := service.NewFlowEditorService(flowStore, workspaceStore)
 	flowQuery := service.NewFlowQueryService(flowStore)
-	orderService := flowexecservice.NewOrderService(flowQuery, stateStore, actionExec)
-	messengerService := flowexecservice.NewMessengerService(flowQuery, stateStore, actionExec)
-	msgWebhook := webhook.NewWebhookService(msgClient, cfg.Messenger.VerifyToken, messengerService)
-
-	servers := httprpc.MustNewServers(flowService, orderService, messengerService)
+	events := flowexecservice.NewEventHandler(reviewService, orderStore, handoffStore, bus)
+	scheduler := flowexecservice.NewScheduler(flowQuery, stateStore, timerStore, events, actionExec, clock.System)
+	handoffService := flowexecservice.NewHandoffService(flowQuery, stateStore, handoffStore, events, actionExec, scheduler)
+	orderService := flowexecservice.NewOrderService(flowQuery, stateStore, orderStore, customerService, handoffService, events, actionExec, scheduler)
+	messengerService := flowexecservice.NewMessengerService(flowQuery, stateStore, customerService, handoffService, events, actionExec, scheduler)
+	simulatorService := flowexecservice.NewSimulatorService(flowQuery, clock.System)
+	liveService := flowexecservice.NewLiveService(bus)
+	workspaceService := workspaceservice.NewWorkspaceService(workspaceStore, flowQuery)
+	authService := authservice.NewAuthService(cfg.Auth.JWTSecret, cfg.Auth.Users, cfg.Auth.APIKeys, clock.System)
+	go scheduler.Run(ctx)
+	msgWebhook := webhook.NewWebhookService(msgClient, cfg.Messenger.VerifyToken, messengerService, customerService, handoffService, conversationService, workspaceStore)
+
+	servers := httprpc.MustNewServers(flowService, flowQuery, orderService, messengerService, customerService, reviewService, handoffService, conversationService, simulatorService, liveService, eventHookService, workspaceService, authService)
+	servers = httprpc.WithHooks(servers, metricsHooks)
+	exportHandler := reviewService.HandleExport
+	if cfg.Auth.Enabled() {
+		authorizer := authservice.NewAuthorizer(authService, authservice.DefaultPolicies(flowQuery))
+		servers = httprpc.WithHooks(servers, authorizer)
+		exportHandler = authorizer.WrapHandler(authtypes.RoleViewer, exportHandler)
+		if cfg.Auth.JWTSecret == "" && len(cfg.Auth.Users) != 0 {
+			ll.Warn("the users can not log in without auth.jwt_secret, only the api keys are accepted")
+		}
+	} else {
+		ll.Warn("auth is disabled, the api is open to anyone (set auth.jwt_secret in the config)")
+	}
+	m.Get("/api/review/export", exportHandler)
+	m.Get("/api/openapi.json", openapi.Handler(apiInfo, false))
+	m.Get("/api/openapi.yaml", openapi.Handler(apiInfo, true))
 	for _, s := range servers {
 		m.Handle(s.PathPrefix()+"*", s)
 	}
 	return msgWebhook
 }
+
+// hashPassword prints the hash of the password read from stdin, for the users
+// of the auth config.
+func hashPassword() {
+	line, err := bufio.NewReader(os.Stdin).ReadString('\n')
+	password := strings.TrimRight(line, "\r\n")
+	if password == "" {
+		ll.Must("can not read password", err)
+		ll.Fatal("the password is empty")
+	}
+	hash, err := authn.HashPassword(password)
+	ll.Must("can not hash password", err)
+	fmt.Println(hash)
+}
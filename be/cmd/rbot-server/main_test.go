@@ -0,0 +1,48 @@
+package main
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/olvrng/rbot/be/cmd/rbot-server/config"
+)
+
+func TestHTTPHandler(t *testing.T) {
+	cfg := config.Config{StaticPath: t.TempDir()}
+	index := filepath.Join(cfg.StaticPath, "index.html")
+	require.NoError(t, ioutil.WriteFile(index, []byte("<html></html>"), 0644))
+	server := httptest.NewServer(buildHTTPHandler(cfg, newTestAPIServer(t)))
+	defer server.Close()
+
+	get := func(path string) (*http.Response, string) {
+		resp, err := http.Get(server.URL + path)
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		body, err := ioutil.ReadAll(resp.Body)
+		require.NoError(t, err)
+		return resp, string(body)
+	}
+
+	// a request of the api is counted
+	resp, err := http.Post(server.URL+"/api/flow/def/query/ListFlows", "application/json", strings.NewReader("{}"))
+	require.NoError(t, err)
+	resp.Body.Close()
+
+	resp, body := get("/metrics")
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	require.Contains(t, resp.Header.Get("Content-Type"), "text/plain; version=0.0.4")
+	require.Contains(t, body, "# TYPE rbot_http_requests_total counter\n")
+	require.Contains(t, body, `rbot_http_requests_total{route="/api/flow/def/query/ListFlows",code=`)
+	require.Contains(t, body, "# TYPE rbot_flow_transitions_total counter\n")
+
+	// the other paths are served by the board app
+	resp, body = get("/flows")
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	require.Equal(t, "<html></html>", body)
+}
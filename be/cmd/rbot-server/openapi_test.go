@@ -0,0 +1,115 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/go-chi/chi"
+	"github.com/stretchr/testify/require"
+
+	"github.com/olvrng/rbot/be/cmd/rbot-server/config"
+	"github.com/olvrng/rbot/be/pkg/openapi"
+)
+
+// newTestAPIServer builds the api server on the data files of a temporary
+// directory.
+func newTestAPIServer(t *testing.T) *chi.Mux {
+	dir := t.TempDir()
+	for i, fl := range []*string{
+		&flFlowFile, &flStateFile, &flOrderFile, &flLinkFile, &flReviewFile, &flTimerFile,
+		&flHandoffFile, &flMessageFile, &flSubscriptionFile, &flDeliveryFile, &flWorkspaceFile,
+	} {
+		*fl = filepath.Join(dir, string(rune('a'+i))+".json")
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+	m := chi.NewMux()
+	buildAPIServer(ctx, config.Config{}, m, nil)
+	return m
+}
+
+func TestOpenAPI(t *testing.T) {
+	m := newTestAPIServer(t)
+	server := httptest.NewServer(m)
+	defer server.Close()
+
+	resp, err := http.Get(server.URL + "/api/openapi.json")
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	var doc openapi.Document
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&doc))
+	require.Equal(t, openapi.Version, doc.OpenAPI)
+	require.NotEmpty(t, doc.Paths)
+
+	t.Run("every path is served", func(t *testing.T) {
+		for _, path := range doc.SortedPaths() {
+			// the malformed body is rejected after routing, without calling the
+			// service
+			resp, err := http.Post(server.URL+path, "application/json", strings.NewReader("{"))
+			require.NoError(t, err)
+			var body struct {
+				Code string `json:"code"`
+			}
+			err = json.NewDecoder(resp.Body).Decode(&body)
+			resp.Body.Close()
+			require.NoError(t, err, path)
+			require.NotEqual(t, "bad_route", body.Code, path)
+			require.NotEmpty(t, body.Code, path)
+		}
+	})
+	t.Run("every GET path is served", func(t *testing.T) {
+		for _, path := range doc.SortedPaths() {
+			if doc.Paths[path].Get == nil {
+				continue
+			}
+			// the invalid query is rejected after routing
+			resp, err := http.Get(server.URL + path + "?a[=1")
+			require.NoError(t, err)
+			var body struct {
+				Msg string `json:"msg"`
+			}
+			err = json.NewDecoder(resp.Body).Decode(&body)
+			resp.Body.Close()
+			require.NoError(t, err, path)
+			require.Equal(t, "the form request could not be decoded", body.Msg, path)
+		}
+	})
+	t.Run("every service is documented", func(t *testing.T) {
+		err := chi.Walk(m, func(method, route string, _ http.Handler, _ ...func(http.Handler) http.Handler) error {
+			if !strings.HasSuffix(route, "/*") || method != http.MethodPost {
+				return nil
+			}
+			prefix := strings.TrimSuffix(route, "*")
+			for _, path := range doc.SortedPaths() {
+				if strings.HasPrefix(path, prefix) {
+					return nil
+				}
+			}
+			t.Errorf("no documented path for %v", route)
+			return nil
+		})
+		require.NoError(t, err)
+	})
+	t.Run("every schema is declared", func(t *testing.T) {
+		data, err := json.Marshal(doc)
+		require.NoError(t, err)
+		const prefix = "#/components/schemas/"
+		for _, part := range strings.Split(string(data), `"$ref":"`+prefix)[1:] {
+			name := part[:strings.IndexByte(part, '"')]
+			require.Contains(t, doc.Components.Schemas, name)
+		}
+	})
+	t.Run("yaml", func(t *testing.T) {
+		resp, err := http.Get(server.URL + "/api/openapi.yaml")
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+		require.Equal(t, "application/yaml", resp.Header.Get("Content-Type"))
+	})
+}
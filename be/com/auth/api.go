@@ -0,0 +1,17 @@
+package auth
+
+import (
+	"context"
+
+	"github.com/olvrng/rbot/be/com/auth/types"
+)
+
+// +gen:api
+
+// +api:path=/api/auth
+type AuthService interface {
+	Login(ctx context.Context, req *types.LoginRequest) (*types.LoginResponse, error)
+
+	// +api:get
+	GetSession(ctx context.Context, req *types.GetSessionRequest) (*types.SessionResponse, error)
+}
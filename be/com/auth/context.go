@@ -0,0 +1,20 @@
+package auth
+
+import (
+	"context"
+
+	"github.com/olvrng/rbot/be/com/auth/types"
+)
+
+type principalKey struct{}
+
+// WithPrincipal returns a context which carries the authenticated principal.
+func WithPrincipal(ctx context.Context, p *types.Principal) context.Context {
+	return context.WithValue(ctx, principalKey{}, p)
+}
+
+// GetPrincipal returns the authenticated principal of the request, or nil.
+func GetPrincipal(ctx context.Context) *types.Principal {
+	p, _ := ctx.Value(principalKey{}).(*types.Principal)
+	return p
+}
@@ -0,0 +1,161 @@
+package service
+
+import (
+	"context"
+	"crypto/subtle"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/olvrng/rbot/be/com/auth"
+	"github.com/olvrng/rbot/be/com/auth/types"
+	"github.com/olvrng/rbot/be/pkg/authn"
+	"github.com/olvrng/rbot/be/pkg/clock"
+	"github.com/olvrng/rbot/be/pkg/dot"
+	"github.com/olvrng/rbot/be/pkg/l"
+	"github.com/olvrng/rbot/be/pkg/xerrors"
+)
+
+var ll = l.New()
+
+const DefaultTokenTTL = 24 * time.Hour
+
+// The credentials of the requests. The dashboard sends the session token in the
+// cookie, the other clients send it in the Authorization header. The servers
+// send their API key in the X-API-Key header.
+const (
+	SessionCookie = "rbot_session"
+	HeaderAPIKey  = "X-API-Key"
+)
+
+var _ auth.AuthService = (*AuthService)(nil)
+
+// AuthService logs in the users of the dashboard, and authenticates the
+// requests. The users and the API keys come from the config.
+type AuthService struct {
+	Secret   []byte
+	TokenTTL time.Duration
+	Users    []*types.User
+	APIKeys  []*types.APIKey
+	Clock    clock.Clock
+}
+
+func NewAuthService(secret string, users []*types.User, apiKeys []*types.APIKey, clk clock.Clock) *AuthService {
+	s := &AuthService{
+		Secret:   []byte(secret),
+		TokenTTL: DefaultTokenTTL,
+		Users:    users,
+		APIKeys:  apiKeys,
+		Clock:    clk,
+	}
+	return s
+}
+
+func (s *AuthService) Login(ctx context.Context, req *types.LoginRequest) (*types.LoginResponse, error) {
+	if req.Email == "" || req.Password == "" {
+		return nil, xerrors.Errorf(xerrors.InvalidArgument, nil, "email and password are required")
+	}
+	user := s.findUser(req.Email)
+	if user == nil || !authn.VerifyPassword(user.PasswordHash, req.Password) {
+		return nil, xerrors.Errorf(xerrors.Unauthenticated, nil, "invalid email or password")
+	}
+	if len(s.Secret) == 0 {
+		return nil, xerrors.Errorf(xerrors.FailedPrecondition, nil, "login is not configured")
+	}
+
+	now := s.Clock.Now()
+	resp := &types.LoginResponse{
+		Token:     authn.SignToken(s.Secret, user.Email, now, s.TokenTTL),
+		ExpiresAt: dot.ToTimestamp(now.Add(s.TokenTTL)),
+		Principal: userPrincipal(user),
+	}
+	return resp, nil
+}
+
+func (s *AuthService) GetSession(ctx context.Context, req *types.GetSessionRequest) (*types.SessionResponse, error) {
+	p := auth.GetPrincipal(ctx)
+	if p == nil {
+		return nil, xerrors.Errorf(xerrors.Unauthenticated, nil, "")
+	}
+	return &types.SessionResponse{Principal: p}, nil
+}
+
+// Authenticate returns the principal of the request, or nil when the request
+// has no credentials. The invalid credentials are rejected, even on the public
+// routes.
+func (s *AuthService) Authenticate(req *http.Request) (*types.Principal, error) {
+	if key := req.Header.Get(HeaderAPIKey); key != "" {
+		apiKey := s.findAPIKey(key)
+		if apiKey == nil {
+			return nil, xerrors.Errorf(xerrors.Unauthenticated, nil, "invalid api key")
+		}
+		return apiKeyPrincipal(apiKey), nil
+	}
+
+	token := ""
+	if h := req.Header.Get("Authorization"); h != "" {
+		const prefix = "Bearer "
+		if !strings.HasPrefix(h, prefix) {
+			return nil, xerrors.Errorf(xerrors.Unauthenticated, nil, "invalid authorization header")
+		}
+		token = strings.TrimSpace(h[len(prefix):])
+	} else if cookie, err := req.Cookie(SessionCookie); err == nil {
+		token = cookie.Value
+	}
+	if token == "" {
+		return nil, nil
+	}
+	// without a secret, anyone could sign a token for any user
+	if len(s.Secret) == 0 {
+		return nil, xerrors.Errorf(xerrors.Unauthenticated, nil, "login is not configured")
+	}
+	claims, err := authn.ParseToken(s.Secret, token, s.Clock.Now())
+	if err != nil {
+		return nil, xerrors.Errorf(xerrors.Unauthenticated, err, "invalid session")
+	}
+	// the roles are read again on each request, so that removing a user from
+	// the config revokes the sessions
+	user := s.findUser(claims.Subject)
+	if user == nil {
+		return nil, xerrors.Errorf(xerrors.Unauthenticated, nil, "invalid session")
+	}
+	return userPrincipal(user), nil
+}
+
+func (s *AuthService) findUser(email string) *types.User {
+	for _, user := range s.Users {
+		if strings.EqualFold(user.Email, email) {
+			return user
+		}
+	}
+	return nil
+}
+
+func (s *AuthService) findAPIKey(key string) *types.APIKey {
+	for _, apiKey := range s.APIKeys {
+		if apiKey.Key != "" && subtle.ConstantTimeCompare([]byte(apiKey.Key), []byte(key)) == 1 {
+			return apiKey
+		}
+	}
+	return nil
+}
+
+func userPrincipal(user *types.User) *types.Principal {
+	return &types.Principal{
+		Email:       user.Email,
+		Name:        user.Name,
+		WorkspaceID: user.WorkspaceID,
+		Admin:       user.Admin,
+		Roles:       user.Roles,
+	}
+}
+
+// apiKeyPrincipal gives the key the editor role on its pages. The routes which
+// do not allow API keys are still denied.
+func apiKeyPrincipal(apiKey *types.APIKey) *types.Principal {
+	p := &types.Principal{APIKey: apiKey.Name, WorkspaceID: apiKey.WorkspaceID}
+	for _, pageID := range apiKey.PageIDs {
+		p.Roles = append(p.Roles, &types.PageRole{PageID: pageID, Role: types.RoleEditor})
+	}
+	return p
+}
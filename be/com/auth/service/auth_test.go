@@ -0,0 +1,324 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/olvrng/rbot/be/com/auth"
+	"github.com/olvrng/rbot/be/com/auth/types"
+	flowdefservice "github.com/olvrng/rbot/be/com/flowdef/service"
+	flowdefstore "github.com/olvrng/rbot/be/com/flowdef/store"
+	flowdeftypes "github.com/olvrng/rbot/be/com/flowdef/types"
+	"github.com/olvrng/rbot/be/com/review"
+	reviewservice "github.com/olvrng/rbot/be/com/review/service"
+	reviewstore "github.com/olvrng/rbot/be/com/review/store"
+	reviewtypes "github.com/olvrng/rbot/be/com/review/types"
+	workspaceservice "github.com/olvrng/rbot/be/com/workspace/service"
+	workspacestore "github.com/olvrng/rbot/be/com/workspace/store"
+	workspacetypes "github.com/olvrng/rbot/be/com/workspace/types"
+	"github.com/olvrng/rbot/be/pkg/authn"
+	"github.com/olvrng/rbot/be/pkg/clock"
+	"github.com/olvrng/rbot/be/pkg/dot"
+	"github.com/olvrng/rbot/be/pkg/httprpc"
+	"github.com/olvrng/rbot/be/pkg/xerrors"
+)
+
+const (
+	pageA = 1001
+	pageB = 1002
+	pageC = 1003
+
+	workspaceBob = 2
+)
+
+type authTest struct {
+	server *httptest.Server
+	auth   *AuthService
+	flows  *flowdefstore.FlowFileStore
+}
+
+func newAuthTest(t *testing.T) *authTest {
+	hash, err := authn.HashPassword("p4ssw0rd")
+	require.NoError(t, err)
+	users := []*types.User{
+		{Email: "admin@example.com", PasswordHash: hash, Admin: true},
+		{Email: "ann@example.com", PasswordHash: hash, Roles: []*types.PageRole{
+			{PageID: pageA, Role: types.RoleEditor},
+			{PageID: pageB, Role: types.RoleViewer},
+		}},
+		// bob is in another workspace, and claims the page of ann
+		{Email: "bob@example.com", PasswordHash: hash, WorkspaceID: workspaceBob, Roles: []*types.PageRole{
+			{PageID: pageA, Role: types.RoleOwner},
+			{PageID: pageC, Role: types.RoleOwner},
+		}},
+	}
+	apiKeys := []*types.APIKey{{Name: "shop", Key: "shop-key", PageIDs: []dot.IntID{pageA}}}
+	clk := clock.NewMock(time.Date(2021, 6, 1, 10, 0, 0, 0, time.UTC))
+
+	flowStore, err := flowdefstore.NewFlowFileStore(filepath.Join(t.TempDir(), "flows.json"))
+	require.NoError(t, err)
+	reviewStore, err := reviewstore.NewReviewStore("")
+	require.NoError(t, err)
+	workspaceStore, err := workspacestore.NewWorkspaceStore("")
+	require.NoError(t, err)
+
+	at := &authTest{
+		auth:  NewAuthService("secret", users, apiKeys, clk),
+		flows: flowStore,
+	}
+	query := flowdefservice.NewFlowQueryService(flowStore)
+	servers := httprpc.MustNewServers(
+		flowdefservice.NewFlowEditorService(flowStore, workspaceStore),
+		reviewservice.NewReviewService(reviewStore),
+		workspaceservice.NewWorkspaceService(workspaceStore, query),
+		at.auth,
+	)
+	servers = httprpc.WithHooks(servers, NewAuthorizer(at.auth, DefaultPolicies(query)))
+	mux := http.NewServeMux()
+	for _, s := range servers {
+		mux.Handle(s.PathPrefix(), s)
+	}
+	at.server = httptest.NewServer(mux)
+	t.Cleanup(at.server.Close)
+	return at
+}
+
+// call posts the request with the given header, then returns the http status
+// and the decoded response.
+func (at *authTest) call(t *testing.T, path string, header http.Header, req, resp interface{}) int {
+	body, err := json.Marshal(req)
+	require.NoError(t, err)
+	httpReq, err := http.NewRequest(http.MethodPost, at.server.URL+path, bytes.NewReader(body))
+	require.NoError(t, err)
+	for k, v := range header {
+		httpReq.Header[k] = v
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+	httpResp, err := http.DefaultClient.Do(httpReq)
+	require.NoError(t, err)
+	defer httpResp.Body.Close()
+	if resp != nil && httpResp.StatusCode == http.StatusOK {
+		require.NoError(t, json.NewDecoder(httpResp.Body).Decode(resp))
+	}
+	return httpResp.StatusCode
+}
+
+func (at *authTest) login(t *testing.T, email string) http.Header {
+	token := authn.SignToken(at.auth.Secret, email, at.auth.Clock.Now(), time.Hour)
+	return http.Header{"Authorization": {"Bearer " + token}}
+}
+
+func TestLogin(t *testing.T) {
+	at := newAuthTest(t)
+
+	var loginResp types.LoginResponse
+	status := at.call(t, "/api/auth/Login", nil, &types.LoginRequest{Email: "Ann@example.com", Password: "p4ssw0rd"}, &loginResp)
+	require.Equal(t, http.StatusOK, status)
+	require.Equal(t, "ann@example.com", loginResp.Principal.Email)
+
+	status = at.call(t, "/api/auth/Login", nil, &types.LoginRequest{Email: "ann@example.com", Password: "password"}, nil)
+	require.Equal(t, http.StatusUnauthorized, status)
+
+	// the token is accepted in the header and in the cookie
+	var sessionResp types.SessionResponse
+	header := http.Header{"Authorization": {"Bearer " + loginResp.Token}}
+	require.Equal(t, http.StatusOK, at.call(t, "/api/auth/GetSession", header, &types.GetSessionRequest{}, &sessionResp))
+	require.Equal(t, types.RoleEditor, sessionResp.Principal.Role(pageA))
+
+	header = http.Header{"Cookie": {SessionCookie + "=" + loginResp.Token}}
+	require.Equal(t, http.StatusOK, at.call(t, "/api/auth/GetSession", header, &types.GetSessionRequest{}, nil))
+
+	require.Equal(t, http.StatusUnauthorized, at.call(t, "/api/auth/GetSession", nil, &types.GetSessionRequest{}, nil))
+	header = http.Header{"Authorization": {"Bearer " + loginResp.Token + "x"}}
+	require.Equal(t, http.StatusUnauthorized, at.call(t, "/api/auth/GetSession", header, &types.GetSessionRequest{}, nil))
+}
+
+func TestLoginWithoutSecret(t *testing.T) {
+	// the auth is enabled by the api keys alone
+	at := newAuthTest(t)
+	at.auth.Secret = nil
+
+	status := at.call(t, "/api/auth/Login", nil, &types.LoginRequest{Email: "admin@example.com", Password: "p4ssw0rd"}, nil)
+	require.Equal(t, http.StatusPreconditionFailed, status)
+	require.Equal(t, http.StatusUnauthorized, at.call(t, "/api/auth/GetSession", at.login(t, "admin@example.com"), &types.GetSessionRequest{}, nil))
+
+	header := http.Header{HeaderAPIKey: {"shop-key"}}
+	require.Equal(t, http.StatusOK, at.call(t, "/api/auth/GetSession", header, &types.GetSessionRequest{}, nil))
+}
+
+func TestClient(t *testing.T) {
+	at := newAuthTest(t)
+	ctx := context.Background()
+	client := auth.NewAuthServiceClient(at.server.URL, nil)
+
+	_, err := client.Login(ctx, &types.LoginRequest{Email: "ann@example.com", Password: "wrong"})
+	require.Equal(t, xerrors.Unauthenticated, xerrors.GetCode(err))
+	require.Equal(t, "invalid email or password", err.(*xerrors.APIError).Message)
+
+	resp, err := client.Login(ctx, &types.LoginRequest{Email: "ann@example.com", Password: "p4ssw0rd"})
+	require.NoError(t, err)
+	require.Equal(t, "ann@example.com", resp.Principal.Email)
+
+	session := auth.NewAuthServiceClient(at.server.URL, nil, httprpc.WithHeader("Authorization", "Bearer "+resp.Token))
+	sessionResp, err := session.GetSession(ctx, &types.GetSessionRequest{})
+	require.NoError(t, err)
+	require.Equal(t, "ann@example.com", sessionResp.Principal.Email)
+}
+
+func TestValidate(t *testing.T) {
+	at := newAuthTest(t)
+	ctx := context.Background()
+	invalid := &reviewtypes.SubmitReviewRequest{PageID: pageA, PSID: 1, Rating: 9, Comment: strings.Repeat("x", 2001)}
+
+	// the request is authorized before it is validated
+	anonymous := review.NewReviewServiceClient(at.server.URL, nil)
+	_, err := anonymous.SubmitReview(ctx, invalid)
+	require.Equal(t, xerrors.Unauthenticated, xerrors.GetCode(err))
+
+	client := review.NewReviewServiceClient(at.server.URL, nil, httprpc.WithHeader(HeaderAPIKey, "shop-key"))
+	_, err = client.SubmitReview(ctx, invalid)
+	require.Equal(t, xerrors.InvalidArgument, xerrors.GetCode(err))
+	xerr := err.(*xerrors.APIError)
+	require.Equal(t, "rating must be at most 5 (and 1 more)", xerr.Message)
+	require.Equal(t, map[string]string{
+		"rating":  "must be at most 5",
+		"comment": "must have at most 2000 characters",
+	}, xerr.Meta)
+
+	_, err = client.SubmitReview(ctx, &reviewtypes.SubmitReviewRequest{PageID: pageA, PSID: 1, Rating: 5})
+	require.NoError(t, err)
+}
+
+func TestLoginCookie(t *testing.T) {
+	at := newAuthTest(t)
+	body := `{"email": "ann@example.com", "password": "p4ssw0rd"}`
+	resp, err := http.Post(at.server.URL+"/api/auth/Login", "application/json", bytes.NewReader([]byte(body)))
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+
+	cookies := resp.Cookies()
+	require.Len(t, cookies, 1)
+	require.Equal(t, SessionCookie, cookies[0].Name)
+	require.True(t, cookies[0].HttpOnly)
+}
+
+func TestPageRoles(t *testing.T) {
+	at := newAuthTest(t)
+	ann := at.login(t, "ann@example.com")
+	admin := at.login(t, "admin@example.com")
+	shop := http.Header{HeaderAPIKey: {"shop-key"}}
+	submit := func(pageID dot.IntID) interface{} {
+		return map[string]interface{}{"page_id": pageID, "psid": "1", "rating": 5}
+	}
+	list := func(pageID dot.IntID) interface{} {
+		return map[string]interface{}{"page_id": pageID}
+	}
+
+	tests := []struct {
+		name   string
+		path   string
+		header http.Header
+		req    interface{}
+		want   int
+	}{
+		{"anonymous", "/api/review/ListReviews", nil, list(pageA), http.StatusUnauthorized},
+		{"viewer reads", "/api/review/ListReviews", ann, list(pageB), http.StatusOK},
+		{"no role", "/api/review/ListReviews", ann, list(9999), http.StatusForbidden},
+		{"admin", "/api/review/ListReviews", admin, list(9999), http.StatusOK},
+		{"missing page", "/api/review/ListReviews", ann, list(0), http.StatusBadRequest},
+		{"editor submits", "/api/review/SubmitReview", ann, submit(pageA), http.StatusOK},
+		{"viewer submits", "/api/review/SubmitReview", ann, submit(pageB), http.StatusForbidden},
+		{"api key submits", "/api/review/SubmitReview", shop, submit(pageA), http.StatusOK},
+		{"api key on other page", "/api/review/SubmitReview", shop, submit(pageB), http.StatusForbidden},
+		{"api key reads", "/api/review/ListReviews", shop, list(pageA), http.StatusForbidden},
+		{"invalid api key", "/api/review/SubmitReview", http.Header{HeaderAPIKey: {"x"}}, submit(pageA), http.StatusUnauthorized},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.Equal(t, tt.want, at.call(t, tt.path, tt.header, tt.req, nil))
+		})
+	}
+}
+
+func TestFlowPages(t *testing.T) {
+	at := newAuthTest(t)
+	ann := at.login(t, "ann@example.com")
+	flowB, err := at.flows.SaveFlow(context.Background(), &flowdeftypes.Flow{PageIDs: []dot.IntID{pageB}})
+	require.NoError(t, err)
+
+	var resp flowdeftypes.CreateFlowResponse
+	req := &flowdeftypes.CreateFlowRequest{Flow: &flowdeftypes.Flow{PageIDs: []dot.IntID{pageA}}}
+	require.Equal(t, http.StatusOK, at.call(t, "/api/flow/def/editor/CreateFlow", ann, req, &resp))
+
+	// ann is only a viewer of the page of flow B, so can not move it to page A
+	req = &flowdeftypes.CreateFlowRequest{Flow: &flowdeftypes.Flow{ID: flowB.ID, PageIDs: []dot.IntID{pageA}}}
+	require.Equal(t, http.StatusForbidden, at.call(t, "/api/flow/def/editor/UpdateFlow", ann, req, nil))
+
+	analyze := &flowdeftypes.AnalyzeFlowRequest{FlowID: flowB.ID}
+	require.Equal(t, http.StatusOK, at.call(t, "/api/flow/def/editor/AnalyzeFlow", ann, analyze, nil))
+}
+
+func TestWorkspaceIsolation(t *testing.T) {
+	at := newAuthTest(t)
+	ann := at.login(t, "ann@example.com")
+	bob := at.login(t, "bob@example.com")
+
+	var flowResp flowdeftypes.CreateFlowResponse
+	req := &flowdeftypes.CreateFlowRequest{Flow: &flowdeftypes.Flow{PageIDs: []dot.IntID{pageA}}}
+	require.Equal(t, http.StatusOK, at.call(t, "/api/flow/def/editor/CreateFlow", ann, req, &flowResp))
+	flowA := flowResp.Flow
+	submit := map[string]interface{}{"page_id": pageA, "psid": "1", "rating": 5}
+	require.Equal(t, http.StatusOK, at.call(t, "/api/review/SubmitReview", ann, submit, nil))
+
+	// the page belongs to the workspace of ann, whatever the roles of bob
+	req = &flowdeftypes.CreateFlowRequest{Flow: &flowdeftypes.Flow{PageIDs: []dot.IntID{pageA}}}
+	require.Equal(t, http.StatusForbidden, at.call(t, "/api/flow/def/editor/CreateFlow", bob, req, nil))
+	var reviewsResp reviewtypes.ListReviewsResponse
+	list := map[string]interface{}{"page_id": pageA}
+	require.Equal(t, http.StatusOK, at.call(t, "/api/review/ListReviews", bob, list, &reviewsResp))
+	require.Empty(t, reviewsResp.Reviews)
+	require.Equal(t, http.StatusOK, at.call(t, "/api/review/ListReviews", ann, list, &reviewsResp))
+	require.Len(t, reviewsResp.Reviews, 1)
+
+	// the flows of another workspace are not found
+	get := &flowdeftypes.GetFlowByIDRequest{ID: flowA.ID}
+	require.Equal(t, http.StatusNotFound, at.call(t, "/api/flow/def/query/GetFlowByID", bob, get, nil))
+	req = &flowdeftypes.CreateFlowRequest{Flow: &flowdeftypes.Flow{ID: flowA.ID, PageIDs: []dot.IntID{pageC}}}
+	require.Equal(t, http.StatusNotFound, at.call(t, "/api/flow/def/editor/UpdateFlow", bob, req, nil))
+
+	var wsResp workspacetypes.WorkspaceResponse
+	require.Equal(t, http.StatusOK, at.call(t, "/api/workspace/GetWorkspace", bob, &workspacetypes.GetWorkspaceRequest{}, &wsResp))
+	require.Empty(t, wsResp.Workspace.PageIDs)
+	require.Equal(t, http.StatusOK, at.call(t, "/api/workspace/GetWorkspace", ann, &workspacetypes.GetWorkspaceRequest{}, &wsResp))
+	require.Equal(t, []dot.IntID{pageA}, wsResp.Workspace.PageIDs)
+
+	// the page is released once no flow uses it
+	release := &workspacetypes.ReleasePageRequest{PageID: pageA}
+	require.Equal(t, http.StatusForbidden, at.call(t, "/api/workspace/ReleasePage", ann, release, nil))
+	admin := at.login(t, "admin@example.com")
+	require.Equal(t, http.StatusPreconditionFailed, at.call(t, "/api/workspace/ReleasePage", admin, release, nil))
+}
+
+func TestAuthorizeWithoutPolicy(t *testing.T) {
+	a := NewAuthorizer(nil, Policies{})
+	ctx := context.Background()
+	err := a.Authorize(ctx, "/api/secret/Get", nil)
+	require.Equal(t, xerrors.Unauthenticated, xerrors.GetCode(err))
+
+	owner := &types.Principal{Roles: []*types.PageRole{{PageID: pageA, Role: types.RoleOwner}}}
+	err = a.Authorize(auth.WithPrincipal(ctx, owner), "/api/secret/Get", nil)
+	require.Equal(t, xerrors.PermissionDenied, xerrors.GetCode(err))
+
+	err = a.Authorize(auth.WithPrincipal(ctx, &types.Principal{Admin: true}), "/api/secret/Get", nil)
+	require.NoError(t, err)
+}
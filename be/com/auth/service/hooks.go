@@ -0,0 +1,111 @@
+package service
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+
+	"github.com/olvrng/rbot/be/com/auth"
+	"github.com/olvrng/rbot/be/com/auth/types"
+	"github.com/olvrng/rbot/be/com/workspace"
+	"github.com/olvrng/rbot/be/pkg/dot"
+	"github.com/olvrng/rbot/be/pkg/httprpc"
+	"github.com/olvrng/rbot/be/pkg/xerrors"
+)
+
+var _ httprpc.HooksBuilder = (*Authorizer)(nil)
+
+// Authorizer checks the httprpc requests with hooks:
+//
+//   - RequestReceived authenticates the request and puts the principal in the
+//     context, see auth.GetPrincipal.
+//   - RequestRouted checks the policy of the route against the decoded request,
+//     since the pages are only known from its content.
+//   - ResponsePrepared sets the session cookie after a login.
+type Authorizer struct {
+	Auth     *AuthService
+	Policies Policies
+}
+
+func NewAuthorizer(authService *AuthService, policies Policies) *Authorizer {
+	a := &Authorizer{Auth: authService, Policies: policies}
+	return a
+}
+
+func (a *Authorizer) BuildHooks() httprpc.Hooks {
+	return httprpc.Hooks{
+		RequestReceived: func(ctx context.Context, info httprpc.HookInfo) (context.Context, error) {
+			p, err := a.Auth.Authenticate(info.HTTPRequest)
+			if err != nil {
+				return ctx, err
+			}
+			if p != nil {
+				ctx = withPrincipal(ctx, p)
+			}
+			return ctx, nil
+		},
+		RequestRouted: func(ctx context.Context, info httprpc.HookInfo) (context.Context, error) {
+			return ctx, a.Authorize(ctx, info.Route, info.Request)
+		},
+		ResponsePrepared: func(ctx context.Context, info httprpc.HookInfo, respHeaders http.Header) (context.Context, error) {
+			if resp, ok := info.Response.(*types.LoginResponse); ok {
+				cookie := &http.Cookie{
+					Name:     SessionCookie,
+					Value:    resp.Token,
+					Path:     "/",
+					Expires:  resp.ExpiresAt.ToTime(),
+					HttpOnly: true,
+					Secure:   info.HTTPRequest.TLS != nil,
+					SameSite: http.SameSiteLaxMode,
+				}
+				respHeaders.Add("Set-Cookie", cookie.String())
+			}
+			return ctx, nil
+		},
+	}
+}
+
+// Authorize checks the principal of the context against the policy of the
+// route. The routes without policy are only allowed to the admins.
+func (a *Authorizer) Authorize(ctx context.Context, route string, req interface{}) error {
+	policy := a.Policies[route]
+	if policy == nil {
+		policy = &Policy{AdminOnly: true}
+	}
+	return policy.Check(ctx, route, req)
+}
+
+// WrapHandler authorizes the plain http handlers, such as the downloads, with
+// the page from the "page_id" query parameter.
+func (a *Authorizer) WrapHandler(role types.Role, handler http.HandlerFunc) http.HandlerFunc {
+	policy := &Policy{Role: role, Pages: func(ctx context.Context, req interface{}) ([]dot.IntID, error) {
+		pageID, err := strconv.ParseInt(req.(*http.Request).URL.Query().Get("page_id"), 10, 64)
+		if err != nil || pageID == 0 {
+			return nil, xerrors.Errorf(xerrors.InvalidArgument, nil, "page_id is required")
+		}
+		return []dot.IntID{dot.IntID(pageID)}, nil
+	}}
+	return func(w http.ResponseWriter, req *http.Request) {
+		ctx := req.Context()
+		p, err := a.Auth.Authenticate(req)
+		if err == nil {
+			if p != nil {
+				ctx = withPrincipal(ctx, p)
+			}
+			err = policy.Check(ctx, req.URL.Path, req)
+		}
+		if err != nil {
+			code := xerrors.GetCode(err)
+			http.Error(w, code.String(), httprpc.ServerHTTPStatusFromErrorCode(code))
+			return
+		}
+		handler(w, req.WithContext(ctx))
+	}
+}
+
+// withPrincipal binds the principal and its workspace to the context, so that
+// the stores only see the data of the workspace.
+func withPrincipal(ctx context.Context, p *types.Principal) context.Context {
+	ctx = auth.WithPrincipal(ctx, p)
+	return workspace.WithID(ctx, p.WorkspaceID)
+}
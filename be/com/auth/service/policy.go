@@ -0,0 +1,205 @@
+package service
+
+import (
+	"context"
+	"reflect"
+
+	"github.com/olvrng/rbot/be/com/auth"
+	"github.com/olvrng/rbot/be/com/auth/types"
+	"github.com/olvrng/rbot/be/com/conversation"
+	"github.com/olvrng/rbot/be/com/eventhook"
+	"github.com/olvrng/rbot/be/com/flowdef"
+	flowdeftypes "github.com/olvrng/rbot/be/com/flowdef/types"
+	"github.com/olvrng/rbot/be/com/flowexec"
+	flowexectypes "github.com/olvrng/rbot/be/com/flowexec/types"
+	"github.com/olvrng/rbot/be/com/review"
+	"github.com/olvrng/rbot/be/com/workspace"
+	"github.com/olvrng/rbot/be/pkg/dot"
+	"github.com/olvrng/rbot/be/pkg/xerrors"
+)
+
+// PageResolver returns the pages which a request reads or changes.
+type PageResolver func(ctx context.Context, req interface{}) ([]dot.IntID, error)
+
+// Policy is the access rule of a route. The principal must have the role on
+// all the pages of the request, or on any page when the request has no page.
+// Any authenticated principal is allowed when the role is empty.
+type Policy struct {
+	Public    bool
+	AdminOnly bool
+	Role      types.Role
+
+	// APIKey allows the API keys on the route, for the server-to-server calls.
+	APIKey bool
+
+	// Pages returns the pages of the request, RequestPages by default.
+	Pages PageResolver
+}
+
+// Policies are the policies by route.
+type Policies map[string]*Policy
+
+// Check checks the principal of the context against the policy. It returns
+// Unauthenticated without principal, and PermissionDenied without the role.
+func (policy *Policy) Check(ctx context.Context, route string, req interface{}) error {
+	if policy.Public {
+		return nil
+	}
+	p := auth.GetPrincipal(ctx)
+	if p == nil {
+		return xerrors.Errorf(xerrors.Unauthenticated, nil, "")
+	}
+	if p.APIKey != "" && !policy.APIKey {
+		return xerrors.Errorf(xerrors.PermissionDenied, nil, "api keys are not allowed on %v", route)
+	}
+	switch {
+	case p.Admin:
+		return nil
+	case policy.AdminOnly:
+		return xerrors.Errorf(xerrors.PermissionDenied, nil, "")
+	case policy.Role == "":
+		return nil
+	}
+
+	resolve := policy.Pages
+	if resolve == nil {
+		resolve = RequestPages
+	}
+	pageIDs, err := resolve(ctx, req)
+	if err != nil {
+		return err
+	}
+	if len(pageIDs) == 0 && !p.HasRole(policy.Role) {
+		return xerrors.Errorf(xerrors.PermissionDenied, nil, "the %v role is required", policy.Role)
+	}
+	for _, pageID := range pageIDs {
+		if !p.Role(pageID).Includes(policy.Role) {
+			return xerrors.Errorf(xerrors.PermissionDenied, nil, "the %v role is required on page %v", policy.Role, pageID)
+		}
+	}
+	return nil
+}
+
+// RequestPages returns the PageID or the PageIDs field of the request.
+func RequestPages(ctx context.Context, req interface{}) ([]dot.IntID, error) {
+	v := reflect.Indirect(reflect.ValueOf(req))
+	if v.Kind() != reflect.Struct {
+		return nil, nil
+	}
+	if f := v.FieldByName("PageID"); f.IsValid() {
+		id, ok := f.Interface().(dot.IntID)
+		if ok && id != 0 {
+			return []dot.IntID{id}, nil
+		}
+		return nil, xerrors.Errorf(xerrors.InvalidArgument, nil, "page_id is required")
+	}
+	if f := v.FieldByName("PageIDs"); f.IsValid() {
+		ids, _ := f.Interface().([]dot.IntID)
+		return ids, nil
+	}
+	return nil, nil
+}
+
+// FlowPages returns the pages of the flow in the request, and of the stored flow
+// which it replaces or refers to: an editor of a page can not take over the
+// flow of another page.
+func FlowPages(query flowdef.QueryService) PageResolver {
+	return func(ctx context.Context, req interface{}) ([]dot.IntID, error) {
+		var flow *flowdeftypes.Flow
+		var flowID dot.IntID
+		switch r := req.(type) {
+		case *flowdeftypes.CreateFlowRequest:
+			flow = r.Flow
+			if flow != nil {
+				flowID = flow.ID
+			}
+		case *flowdeftypes.AnalyzeFlowRequest:
+			flow, flowID = r.Flow, r.FlowID
+		case *flowexectypes.SimulateFlowRequest:
+			flow, flowID = r.Flow, r.FlowID
+		default:
+			return RequestPages(ctx, req)
+		}
+
+		var pageIDs []dot.IntID
+		if flow != nil {
+			pageIDs = append(pageIDs, flow.PageIDs...)
+		}
+		if flowID != 0 {
+			resp, err := query.GetFlowByID(ctx, &flowdeftypes.GetFlowByIDRequest{ID: flowID})
+			switch {
+			case err == nil:
+				pageIDs = append(pageIDs, resp.Flow.PageIDs...)
+			case xerrors.GetCode(err) != xerrors.NotFound:
+				return nil, err
+			}
+		}
+		return pageIDs, nil
+	}
+}
+
+// DefaultPolicies are the policies of the routes of rbot. Reading requires the
+// viewer role, changing the flows and the conversations requires the editor
+// role, and the webhook subscriptions, which hold secrets, require the owner
+// role. The API keys are allowed on the routes which receive events.
+func DefaultPolicies(query flowdef.QueryService) Policies {
+	var (
+		public = &Policy{Public: true}
+		viewer = &Policy{Role: types.RoleViewer}
+		editor = &Policy{Role: types.RoleEditor}
+		owner  = &Policy{Role: types.RoleOwner}
+		server = &Policy{Role: types.RoleEditor, APIKey: true}
+
+		flowViewer = &Policy{Role: types.RoleViewer, Pages: FlowPages(query)}
+		flowEditor = &Policy{Role: types.RoleEditor, Pages: FlowPages(query)}
+	)
+	return Policies{
+		auth.Path_Auth_Login:      public,
+		auth.Path_Auth_GetSession: &Policy{APIKey: true},
+
+		flowdef.Path_Editor_CreateFlow:  flowEditor,
+		flowdef.Path_Editor_UpdateFlow:  flowEditor,
+		flowdef.Path_Editor_AnalyzeFlow: flowViewer,
+		flowdef.Path_Query_GetFlowByID:  flowViewer,
+		flowdef.Path_Query_ListFlows:    flowViewer,
+
+		flowexec.Path_Messenger_ReceivedMessage:  server,
+		flowexec.Path_Messenger_ReceivedPostback: server,
+		flowexec.Path_Messenger_ReceivedReferral: server,
+
+		flowexec.Path_Order_ReceivedOrderEvent:     server,
+		flowexec.Path_Order_ReceivedCompletedOrder: server,
+		flowexec.Path_Order_GetOrder:               &Policy{Role: types.RoleViewer, APIKey: true},
+
+		flowexec.Path_Customer_LinkCustomer:    server,
+		flowexec.Path_Customer_GetCustomerLink: &Policy{Role: types.RoleViewer, APIKey: true},
+
+		flowexec.Path_Handoff_StartHandoff:   editor,
+		flowexec.Path_Handoff_ListHandoffs:   viewer,
+		flowexec.Path_Handoff_GetHandoff:     viewer,
+		flowexec.Path_Handoff_ReplyHandoff:   editor,
+		flowexec.Path_Handoff_ResolveHandoff: editor,
+
+		flowexec.Path_Simulator_SimulateFlow: flowViewer,
+
+		flowexec.Path_Live_WatchEvents: viewer,
+
+		review.Path_Review_SubmitReview:     server,
+		review.Path_Review_ListReviews:      viewer,
+		review.Path_Review_GetReviewSummary: viewer,
+		review.Path_Review_ExportReviews:    viewer,
+
+		conversation.Path_Conversation_ListConversations: viewer,
+		conversation.Path_Conversation_GetTranscript:     viewer,
+		conversation.Path_Conversation_SearchMessages:    viewer,
+
+		eventhook.Path_EventHook_CreateSubscription: owner,
+		eventhook.Path_EventHook_UpdateSubscription: owner,
+		eventhook.Path_EventHook_DeleteSubscription: owner,
+		eventhook.Path_EventHook_ListSubscriptions:  owner,
+		eventhook.Path_EventHook_ListDeliveries:     owner,
+
+		workspace.Path_Workspace_GetWorkspace: &Policy{},
+		workspace.Path_Workspace_ReleasePage:  owner,
+	}
+}
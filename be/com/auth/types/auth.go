@@ -0,0 +1,114 @@
+package types
+
+import "github.com/olvrng/rbot/be/pkg/dot"
+
+// Role is the role of a user on a page. Each role includes the permissions of
+// the lower ones: an owner is also an editor and a viewer.
+type Role string
+
+const (
+	RoleViewer Role = "viewer"
+	RoleEditor Role = "editor"
+	RoleOwner  Role = "owner"
+)
+
+var roleRanks = map[Role]int{
+	RoleViewer: 1,
+	RoleEditor: 2,
+	RoleOwner:  3,
+}
+
+func (r Role) IsValid() bool {
+	return roleRanks[r] != 0
+}
+
+// Includes tells whether the role has the permissions of the other role.
+func (r Role) Includes(other Role) bool {
+	return r.IsValid() && roleRanks[r] >= roleRanks[other]
+}
+
+type PageRole struct {
+	PageID dot.IntID `json:"page_id" yaml:"page_id"`
+	Role   Role      `json:"role" yaml:"role"`
+}
+
+// User logs in the dashboard with email and password. The user only sees the
+// data of their workspace, and an admin is the owner of all its pages.
+type User struct {
+	Email        string      `json:"email" yaml:"email"`
+	Name         string      `json:"name,omitempty" yaml:"name"`
+	PasswordHash string      `json:"-" yaml:"password_hash"`
+	WorkspaceID  dot.IntID   `json:"workspace_id,omitempty" yaml:"workspace_id"`
+	Admin        bool        `json:"admin,omitempty" yaml:"admin"`
+	Roles        []*PageRole `json:"roles,omitempty" yaml:"roles"`
+}
+
+// APIKey authenticates the servers which send events to rbot, such as the
+// order events of the shop. It is only accepted on the routes which allow API
+// keys, for the given pages.
+type APIKey struct {
+	Name        string      `json:"name" yaml:"name"`
+	Key         string      `json:"-" yaml:"key"`
+	WorkspaceID dot.IntID   `json:"workspace_id,omitempty" yaml:"workspace_id"`
+	PageIDs     []dot.IntID `json:"page_ids" yaml:"page_ids"`
+}
+
+// Principal is the user or the API key which makes the request.
+type Principal struct {
+	Email       string      `json:"email,omitempty"`
+	Name        string      `json:"name,omitempty"`
+	APIKey      string      `json:"api_key,omitempty"` // the name of the key
+	WorkspaceID dot.IntID   `json:"workspace_id,omitempty"`
+	Admin       bool        `json:"admin,omitempty"`
+	Roles       []*PageRole `json:"roles,omitempty"`
+}
+
+// Role returns the role of the principal on the page, or an empty role.
+func (p *Principal) Role(pageID dot.IntID) Role {
+	if p.Admin {
+		return RoleOwner
+	}
+	for _, r := range p.Roles {
+		if r.PageID == pageID {
+			return r.Role
+		}
+	}
+	return ""
+}
+
+// HasRole tells whether the principal has the role on at least one page.
+func (p *Principal) HasRole(role Role) bool {
+	if p.Admin {
+		return true
+	}
+	for _, r := range p.Roles {
+		if r.Role.Includes(role) {
+			return true
+		}
+	}
+	return false
+}
+
+type LoginRequest struct {
+	// +validate:required +validate:max-len=254
+	Email string `json:"email"`
+	// +validate:required +validate:max-len=1024
+	Password string `json:"password"`
+}
+
+// LoginResponse returns the session token, which is sent in the header
+//
+//	Authorization: Bearer <token>
+//
+// The token is also set in the session cookie, for the dashboard.
+type LoginResponse struct {
+	Token     string        `json:"token"`
+	ExpiresAt dot.Timestamp `json:"expires_at"`
+	Principal *Principal    `json:"principal"`
+}
+
+type GetSessionRequest struct{}
+
+type SessionResponse struct {
+	Principal *Principal `json:"principal"`
+}
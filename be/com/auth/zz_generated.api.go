@@ -0,0 +1,171 @@
+// +build !generator
+
+// Code generated by generator api. DO NOT EDIT.
+
+package auth
+
+import (
+	context "context"
+	fmt "fmt"
+	http "net/http"
+
+	authtypes "github.com/olvrng/rbot/be/com/auth/types"
+	httprpc "github.com/olvrng/rbot/be/pkg/httprpc"
+)
+
+func init() {
+	httprpc.Register(NewServer)
+	httprpc.RegisterSchemas(
+		&httprpc.Schema{Name: "github.com/olvrng/rbot/be/com/auth/types.GetSessionRequest", Fields: []httprpc.Field{}},
+		&httprpc.Schema{Name: "github.com/olvrng/rbot/be/com/auth/types.LoginRequest", Fields: []httprpc.Field{
+			{Name: "email", Number: 1, Kind: httprpc.KindString},
+			{Name: "password", Number: 2, Kind: httprpc.KindString},
+		}},
+		&httprpc.Schema{Name: "github.com/olvrng/rbot/be/com/auth/types.LoginResponse", Fields: []httprpc.Field{
+			{Name: "token", Number: 1, Kind: httprpc.KindString},
+			{Name: "expires_at", Number: 2, Kind: httprpc.KindInt},
+			{Name: "principal", Number: 3, Kind: httprpc.KindMessage, Message: "github.com/olvrng/rbot/be/com/auth/types.Principal"},
+		}},
+		&httprpc.Schema{Name: "github.com/olvrng/rbot/be/com/auth/types.PageRole", Fields: []httprpc.Field{
+			{Name: "page_id", Number: 1, Kind: httprpc.KindInt},
+			{Name: "role", Number: 2, Kind: httprpc.KindString},
+		}},
+		&httprpc.Schema{Name: "github.com/olvrng/rbot/be/com/auth/types.Principal", Fields: []httprpc.Field{
+			{Name: "email", Number: 1, Kind: httprpc.KindString},
+			{Name: "name", Number: 2, Kind: httprpc.KindString},
+			{Name: "api_key", Number: 3, Kind: httprpc.KindString},
+			{Name: "workspace_id", Number: 4, Kind: httprpc.KindInt},
+			{Name: "admin", Number: 5, Kind: httprpc.KindBool},
+			{Name: "roles", Number: 6, Kind: httprpc.KindMessage, Repeated: true, Message: "github.com/olvrng/rbot/be/com/auth/types.PageRole"},
+		}},
+		&httprpc.Schema{Name: "github.com/olvrng/rbot/be/com/auth/types.SessionResponse", Fields: []httprpc.Field{
+			{Name: "principal", Number: 1, Kind: httprpc.KindMessage, Message: "github.com/olvrng/rbot/be/com/auth/types.Principal"},
+		}},
+	)
+}
+
+func NewServer(builder interface{}, hooks ...httprpc.HooksBuilder) (httprpc.Server, bool) {
+	switch builder := builder.(type) {
+	case func() AuthService:
+		return NewAuthServiceServer(builder, hooks...), true
+	case AuthService:
+		fn := func() AuthService { return builder }
+		return NewAuthServiceServer(fn, hooks...), true
+	default:
+		return nil, false
+	}
+}
+
+type AuthServiceServer struct {
+	hooks   httprpc.HooksBuilder
+	builder func() AuthService
+}
+
+func NewAuthServiceServer(builder func() AuthService, hooks ...httprpc.HooksBuilder) httprpc.Server {
+	return &AuthServiceServer{
+		hooks:   httprpc.ChainHooks(hooks...),
+		builder: builder,
+	}
+}
+
+const AuthServicePathPrefix = "/api/auth/"
+
+const Path_Auth_GetSession = "/api/auth/GetSession"
+const Path_Auth_Login = "/api/auth/Login"
+
+func (s *AuthServiceServer) PathPrefix() string {
+	return AuthServicePathPrefix
+}
+
+func (s *AuthServiceServer) WithHooks(hooks httprpc.HooksBuilder) httprpc.Server {
+	result := *s
+	result.hooks = httprpc.ChainHooks(s.hooks, hooks)
+	return &result
+}
+
+func (s *AuthServiceServer) ServeHTTP(resp http.ResponseWriter, req *http.Request) {
+	hooks := httprpc.WrapHooks(s.hooks)
+	ctx, info := req.Context(), &httprpc.HookInfo{Route: req.URL.Path, HTTPRequest: req}
+	ctx, err := hooks.RequestReceived(ctx, *info)
+	if err != nil {
+		httprpc.WriteError(ctx, resp, hooks, *info, err)
+		return
+	}
+	reqMsg, exec, allowGET, err := s.parseRoute(req.URL.Path, hooks, info)
+	if err != nil {
+		httprpc.WriteError(ctx, resp, hooks, *info, err)
+		return
+	}
+	serve, err := httprpc.ParseRequestHeader(req, allowGET)
+	if err != nil {
+		httprpc.WriteError(ctx, resp, hooks, *info, err)
+		return
+	}
+	serve(ctx, resp, req, hooks, info, reqMsg, exec)
+}
+
+func (s *AuthServiceServer) parseRoute(path string, hooks httprpc.Hooks, info *httprpc.HookInfo) (reqMsg httprpc.Message, _ httprpc.ExecFunc, allowGET bool, _ error) {
+	switch path {
+	case "/api/auth/GetSession":
+		msg := &authtypes.GetSessionRequest{}
+		fn := func(ctx context.Context) (newCtx context.Context, resp httprpc.Message, err error) {
+			inner := s.builder()
+			info.Request, info.Inner = msg, inner
+			newCtx, err = hooks.RequestRouted(ctx, *info)
+			if err != nil {
+				return
+			}
+			resp, err = inner.GetSession(newCtx, msg)
+			return
+		}
+		return msg, fn, true, nil
+	case "/api/auth/Login":
+		msg := &authtypes.LoginRequest{}
+		fn := func(ctx context.Context) (newCtx context.Context, resp httprpc.Message, err error) {
+			inner := s.builder()
+			info.Request, info.Inner = msg, inner
+			newCtx, err = hooks.RequestRouted(ctx, *info)
+			if err != nil {
+				return
+			}
+			if err = msg.Validate(); err != nil {
+				return
+			}
+			resp, err = inner.Login(newCtx, msg)
+			return
+		}
+		return msg, fn, false, nil
+	default:
+		msg := fmt.Sprintf("no handler for path %q", path)
+		return nil, nil, false, httprpc.BadRouteError(msg, "POST", path)
+	}
+}
+
+// AuthServiceClient calls AuthService on the server at baseURL.
+type AuthServiceClient struct {
+	client *httprpc.Client
+}
+
+var _ AuthService = &AuthServiceClient{}
+
+func NewAuthServiceClient(baseURL string, httpClient *http.Client, hooks ...httprpc.ClientHooks) *AuthServiceClient {
+	return &AuthServiceClient{
+		client: httprpc.NewClient(baseURL, httpClient, hooks...),
+	}
+}
+
+func (c *AuthServiceClient) GetSession(ctx context.Context, req *authtypes.GetSessionRequest) (*authtypes.SessionResponse, error) {
+	resp := &authtypes.SessionResponse{}
+	if err := c.client.Call(ctx, Path_Auth_GetSession, req, resp); err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+func (c *AuthServiceClient) Login(ctx context.Context, req *authtypes.LoginRequest) (*authtypes.LoginResponse, error) {
+	resp := &authtypes.LoginResponse{}
+	if err := c.client.Call(ctx, Path_Auth_Login, req, resp); err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
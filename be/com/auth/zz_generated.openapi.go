@@ -0,0 +1,281 @@
+// +build !generator
+
+// Code generated by generator openapi. DO NOT EDIT.
+
+package auth
+
+import (
+	openapi "github.com/olvrng/rbot/be/pkg/openapi"
+)
+
+func init() {
+	openapi.Register(openAPIFragment)
+}
+
+const openAPIFragment = `{
+  "paths": {
+    "/api/auth/GetSession": {
+      "get": {
+        "operationId": "Auth_GetSession_GET",
+        "tags": [
+          "AuthService"
+        ],
+        "responses": {
+          "200": {
+            "description": "OK",
+            "content": {
+              "application/json": {
+                "schema": {
+                  "$ref": "#/components/schemas/authtypes.SessionResponse"
+                }
+              },
+              "application/protobuf": {
+                "schema": {
+                  "type": "string",
+                  "format": "binary"
+                }
+              },
+              "application/x-www-form-urlencoded": {
+                "schema": {
+                  "$ref": "#/components/schemas/authtypes.SessionResponse"
+                }
+              }
+            }
+          },
+          "default": {
+            "description": "Error",
+            "content": {
+              "application/json": {
+                "schema": {
+                  "$ref": "#/components/schemas/Error"
+                }
+              }
+            }
+          }
+        }
+      },
+      "post": {
+        "operationId": "Auth_GetSession",
+        "tags": [
+          "AuthService"
+        ],
+        "requestBody": {
+          "required": true,
+          "content": {
+            "application/json": {
+              "schema": {
+                "$ref": "#/components/schemas/authtypes.GetSessionRequest"
+              }
+            },
+            "application/protobuf": {
+              "schema": {
+                "type": "string",
+                "format": "binary"
+              }
+            },
+            "application/x-www-form-urlencoded": {
+              "schema": {
+                "$ref": "#/components/schemas/authtypes.GetSessionRequest"
+              }
+            }
+          }
+        },
+        "responses": {
+          "200": {
+            "description": "OK",
+            "content": {
+              "application/json": {
+                "schema": {
+                  "$ref": "#/components/schemas/authtypes.SessionResponse"
+                }
+              },
+              "application/protobuf": {
+                "schema": {
+                  "type": "string",
+                  "format": "binary"
+                }
+              },
+              "application/x-www-form-urlencoded": {
+                "schema": {
+                  "$ref": "#/components/schemas/authtypes.SessionResponse"
+                }
+              }
+            }
+          },
+          "default": {
+            "description": "Error",
+            "content": {
+              "application/json": {
+                "schema": {
+                  "$ref": "#/components/schemas/Error"
+                }
+              }
+            }
+          }
+        }
+      }
+    },
+    "/api/auth/Login": {
+      "post": {
+        "operationId": "Auth_Login",
+        "tags": [
+          "AuthService"
+        ],
+        "requestBody": {
+          "required": true,
+          "content": {
+            "application/json": {
+              "schema": {
+                "$ref": "#/components/schemas/authtypes.LoginRequest"
+              }
+            },
+            "application/protobuf": {
+              "schema": {
+                "type": "string",
+                "format": "binary"
+              }
+            },
+            "application/x-www-form-urlencoded": {
+              "schema": {
+                "$ref": "#/components/schemas/authtypes.LoginRequest"
+              }
+            }
+          }
+        },
+        "responses": {
+          "200": {
+            "description": "OK",
+            "content": {
+              "application/json": {
+                "schema": {
+                  "$ref": "#/components/schemas/authtypes.LoginResponse"
+                }
+              },
+              "application/protobuf": {
+                "schema": {
+                  "type": "string",
+                  "format": "binary"
+                }
+              },
+              "application/x-www-form-urlencoded": {
+                "schema": {
+                  "$ref": "#/components/schemas/authtypes.LoginResponse"
+                }
+              }
+            }
+          },
+          "default": {
+            "description": "Error",
+            "content": {
+              "application/json": {
+                "schema": {
+                  "$ref": "#/components/schemas/Error"
+                }
+              }
+            }
+          }
+        }
+      }
+    }
+  },
+  "components": {
+    "schemas": {
+      "authtypes.GetSessionRequest": {
+        "type": "object"
+      },
+      "authtypes.LoginRequest": {
+        "type": "object",
+        "properties": {
+          "email": {
+            "type": "string"
+          },
+          "password": {
+            "type": "string"
+          }
+        },
+        "required": [
+          "email",
+          "password"
+        ]
+      },
+      "authtypes.LoginResponse": {
+        "type": "object",
+        "properties": {
+          "expires_at": {
+            "type": "string",
+            "description": "milliseconds from 1970"
+          },
+          "principal": {
+            "$ref": "#/components/schemas/authtypes.Principal"
+          },
+          "token": {
+            "type": "string"
+          }
+        },
+        "required": [
+          "token",
+          "expires_at"
+        ]
+      },
+      "authtypes.PageRole": {
+        "type": "object",
+        "properties": {
+          "page_id": {
+            "type": "string",
+            "format": "int64"
+          },
+          "role": {
+            "$ref": "#/components/schemas/authtypes.Role"
+          }
+        },
+        "required": [
+          "page_id",
+          "role"
+        ]
+      },
+      "authtypes.Principal": {
+        "type": "object",
+        "properties": {
+          "admin": {
+            "type": "boolean"
+          },
+          "api_key": {
+            "type": "string"
+          },
+          "email": {
+            "type": "string"
+          },
+          "name": {
+            "type": "string"
+          },
+          "roles": {
+            "type": "array",
+            "items": {
+              "$ref": "#/components/schemas/authtypes.PageRole"
+            }
+          },
+          "workspace_id": {
+            "type": "string",
+            "format": "int64"
+          }
+        }
+      },
+      "authtypes.Role": {
+        "type": "string",
+        "enum": [
+          "editor",
+          "owner",
+          "viewer"
+        ]
+      },
+      "authtypes.SessionResponse": {
+        "type": "object",
+        "properties": {
+          "principal": {
+            "$ref": "#/components/schemas/authtypes.Principal"
+          }
+        }
+      }
+    }
+  }
+}`
@@ -0,0 +1,21 @@
+package conversation
+
+import (
+	"context"
+
+	"github.com/olvrng/rbot/be/com/conversation/types"
+)
+
+// +gen:api
+
+// +api:path=/api/conversation
+type ConversationService interface {
+	// +api:get
+	ListConversations(ctx context.Context, req *types.ListConversationsRequest) (*types.ListConversationsResponse, error)
+
+	// +api:get
+	GetTranscript(ctx context.Context, req *types.GetTranscriptRequest) (*types.TranscriptResponse, error)
+
+	// +api:get
+	SearchMessages(ctx context.Context, req *types.SearchMessagesRequest) (*types.SearchMessagesResponse, error)
+}
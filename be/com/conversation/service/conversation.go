@@ -0,0 +1,173 @@
+package service
+
+import (
+	"context"
+	"strings"
+
+	"github.com/olvrng/rbot/be/com/conversation"
+	"github.com/olvrng/rbot/be/com/conversation/store"
+	"github.com/olvrng/rbot/be/com/conversation/types"
+	"github.com/olvrng/rbot/be/pkg/dot"
+	"github.com/olvrng/rbot/be/pkg/textnorm"
+	"github.com/olvrng/rbot/be/pkg/xerrors"
+)
+
+const (
+	DefaultLimit = 50
+	MaxLimit     = 500
+)
+
+var _ conversation.ConversationService = (*ConversationService)(nil)
+
+// ConversationService keeps the transcript of the conversations. The messages
+// are recorded by the webhook and the action executor, and are shown on the
+// board.
+type ConversationService struct {
+	Store *store.MessageStore
+}
+
+func NewConversationService(messageStore *store.MessageStore) *ConversationService {
+	s := &ConversationService{Store: messageStore}
+	return s
+}
+
+func (s *ConversationService) ListConversations(ctx context.Context, req *types.ListConversationsRequest) (*types.ListConversationsResponse, error) {
+	if req.PageID == 0 {
+		return nil, xerrors.Errorf(xerrors.InvalidArgument, nil, "page_id is required")
+	}
+	convs, err := s.Store.ListConversations(ctx, req.PageID)
+	if err != nil {
+		return nil, err
+	}
+	return &types.ListConversationsResponse{Conversations: convs}, nil
+}
+
+func (s *ConversationService) GetTranscript(ctx context.Context, req *types.GetTranscriptRequest) (*types.TranscriptResponse, error) {
+	if req.PageID == 0 || req.PSID == 0 {
+		return nil, xerrors.Errorf(xerrors.InvalidArgument, nil, "page_id and psid are required")
+	}
+	limit, err := checkLimit(req.Limit)
+	if err != nil {
+		return nil, err
+	}
+	if req.Offset < 0 {
+		return nil, xerrors.Errorf(xerrors.InvalidArgument, nil, "invalid offset")
+	}
+	msgs, err := s.Store.ListMessages(ctx, req.PageID, req.PSID)
+	if err != nil {
+		return nil, err
+	}
+
+	end := len(msgs) - req.Offset
+	if end < 0 {
+		end = 0
+	}
+	start := end - limit
+	if start < 0 {
+		start = 0
+	}
+	resp := &types.TranscriptResponse{
+		Messages: msgs[start:end],
+		Total:    len(msgs),
+		HasMore:  start > 0,
+	}
+	return resp, nil
+}
+
+func (s *ConversationService) SearchMessages(ctx context.Context, req *types.SearchMessagesRequest) (*types.SearchMessagesResponse, error) {
+	if req.PageID == 0 {
+		return nil, xerrors.Errorf(xerrors.InvalidArgument, nil, "page_id is required")
+	}
+	query := textnorm.Fold(req.Query)
+	if query == "" {
+		return nil, xerrors.Errorf(xerrors.InvalidArgument, nil, "query is required")
+	}
+	limit, err := checkLimit(req.Limit)
+	if err != nil {
+		return nil, err
+	}
+	msgs, err := s.Store.SearchMessages(ctx, req.PageID, limit, func(m *types.Message) bool {
+		return (req.PSID == 0 || m.PSID == req.PSID) &&
+			strings.Contains(textnorm.Fold(m.Text), query)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &types.SearchMessagesResponse{Messages: msgs}, nil
+}
+
+// RecordMessage adds the message to the transcript. The messages which are
+// received again by the webhook are ignored.
+func (s *ConversationService) RecordMessage(ctx context.Context, msg *types.Message) error {
+	if msg.PageID == 0 || msg.PSID == 0 {
+		return xerrors.Errorf(xerrors.InvalidArgument, nil, "page_id and psid are required")
+	}
+	if msg.ID == 0 {
+		msg.ID = dot.NewIntID()
+	}
+	if msg.CreatedAt == 0 {
+		msg.CreatedAt = dot.Now()
+	}
+	if msg.Status == "" {
+		msg.Status = types.StatusSent
+		if msg.Direction == types.Inbound {
+			msg.Status = types.StatusReceived
+		}
+	}
+	_, err := s.Store.AddMessage(ctx, msg)
+	return err
+}
+
+// MarkDelivered updates the outbound messages from the delivery event. The
+// messages are either listed by MID, or sent before the watermark.
+func (s *ConversationService) MarkDelivered(ctx context.Context, pageID, psid dot.IntID, mids []string, watermark dot.Timestamp) error {
+	now := dot.Now()
+	_, err := s.Store.UpdateOutbound(ctx, pageID, psid, func(m *types.Message) bool {
+		if m.Status != types.StatusSent {
+			return false
+		}
+		if !containsString(mids, m.MID) && (watermark == 0 || m.CreatedAt > watermark) {
+			return false
+		}
+		m.Status, m.DeliveredAt = types.StatusDelivered, now
+		return true
+	})
+	return err
+}
+
+// MarkRead updates the outbound messages which are sent before the watermark of
+// the read event.
+func (s *ConversationService) MarkRead(ctx context.Context, pageID, psid dot.IntID, watermark dot.Timestamp) error {
+	now := dot.Now()
+	_, err := s.Store.UpdateOutbound(ctx, pageID, psid, func(m *types.Message) bool {
+		if m.Status == types.StatusRead || m.CreatedAt > watermark {
+			return false
+		}
+		if m.DeliveredAt == 0 {
+			m.DeliveredAt = now
+		}
+		m.Status, m.ReadAt = types.StatusRead, now
+		return true
+	})
+	return err
+}
+
+func checkLimit(limit int) (int, error) {
+	switch {
+	case limit == 0:
+		return DefaultLimit, nil
+	case limit < 0 || limit > MaxLimit:
+		return 0, xerrors.Errorf(xerrors.InvalidArgument, nil, "limit must be from 1 to %v", MaxLimit)
+	default:
+		return limit, nil
+	}
+}
+
+func containsString(ss []string, s string) bool {
+	for _, item := range ss {
+		if item != "" && item == s {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,151 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/olvrng/rbot/be/com/conversation/store"
+	"github.com/olvrng/rbot/be/com/conversation/types"
+	"github.com/olvrng/rbot/be/com/workspace"
+	"github.com/olvrng/rbot/be/pkg/dot"
+	"github.com/olvrng/rbot/be/pkg/xerrors"
+)
+
+const testPageID, testPSID = 1000, 2000
+
+func newTestService(t *testing.T) *ConversationService {
+	messageStore, err := store.NewMessageStore(filepath.Join(t.TempDir(), "message.json"))
+	require.NoError(t, err)
+	return NewConversationService(messageStore)
+}
+
+func record(t *testing.T, s *ConversationService, psid dot.IntID, direction types.Direction, at dot.Timestamp, text string) {
+	msg := &types.Message{
+		PageID:    testPageID,
+		PSID:      psid,
+		MID:       fmt.Sprintf("mid.%v.%v", psid, int64(at)),
+		Direction: direction,
+		Text:      text,
+		CreatedAt: at,
+	}
+	require.NoError(t, s.RecordMessage(context.Background(), msg))
+}
+
+func texts(msgs []*types.Message) []string {
+	result := make([]string, len(msgs))
+	for i, m := range msgs {
+		result[i] = m.Text
+	}
+	return result
+}
+
+func TestGetTranscript(t *testing.T) {
+	ctx := context.Background()
+	s := newTestService(t)
+	for i := 1; i <= 5; i++ {
+		record(t, s, testPSID, types.Inbound, dot.Timestamp(i), fmt.Sprintf("msg %v", i))
+	}
+	// the webhook may receive the same message again
+	record(t, s, testPSID, types.Inbound, 5, "msg 5")
+
+	tests := []struct {
+		offset, limit int
+		expected      []string
+		hasMore       bool
+	}{
+		{0, 0, []string{"msg 1", "msg 2", "msg 3", "msg 4", "msg 5"}, false},
+		{0, 2, []string{"msg 4", "msg 5"}, true},
+		{2, 2, []string{"msg 2", "msg 3"}, true},
+		{4, 2, []string{"msg 1"}, false},
+		{10, 2, []string{}, false},
+	}
+	for _, tt := range tests {
+		t.Run(fmt.Sprintf("offset=%v limit=%v", tt.offset, tt.limit), func(t *testing.T) {
+			req := &types.GetTranscriptRequest{PageID: testPageID, PSID: testPSID, Offset: tt.offset, Limit: tt.limit}
+			resp, err := s.GetTranscript(ctx, req)
+			require.NoError(t, err)
+			require.Equal(t, tt.expected, texts(resp.Messages))
+			require.Equal(t, 5, resp.Total)
+			require.Equal(t, tt.hasMore, resp.HasMore)
+		})
+	}
+}
+
+func TestListConversations(t *testing.T) {
+	ctx := context.Background()
+	s := newTestService(t)
+	record(t, s, 2001, types.Inbound, 1, "hello")
+	record(t, s, 2002, types.Inbound, 2, "xin chào")
+	record(t, s, 2001, types.Outbound, 3, "Chào bạn!")
+
+	listResp, err := s.ListConversations(ctx, &types.ListConversationsRequest{PageID: testPageID})
+	require.NoError(t, err)
+	require.Len(t, listResp.Conversations, 2)
+	require.Equal(t, dot.IntID(2001), listResp.Conversations[0].PSID)
+	require.Equal(t, 2, listResp.Conversations[0].MessageCount)
+	require.Equal(t, "Chào bạn!", listResp.Conversations[0].LastMessage.Text)
+
+	searchReq := &types.SearchMessagesRequest{PageID: testPageID, Query: "CHAO"}
+	searchResp, err := s.SearchMessages(ctx, searchReq)
+	require.NoError(t, err)
+	require.Equal(t, []string{"Chào bạn!", "xin chào"}, texts(searchResp.Messages))
+
+	searchReq.PSID = 2002
+	searchResp, err = s.SearchMessages(ctx, searchReq)
+	require.NoError(t, err)
+	require.Equal(t, []string{"xin chào"}, texts(searchResp.Messages))
+}
+
+func TestDeliveryAndRead(t *testing.T) {
+	ctx := context.Background()
+	s := newTestService(t)
+	record(t, s, testPSID, types.Outbound, 10, "one")
+	record(t, s, testPSID, types.Inbound, 15, "hi")
+	record(t, s, testPSID, types.Outbound, 20, "two")
+	record(t, s, testPSID, types.Outbound, 30, "three")
+
+	status := func() []types.MessageStatus {
+		msgs, err := s.Store.ListMessages(ctx, testPageID, testPSID)
+		require.NoError(t, err)
+		var result []types.MessageStatus
+		for _, m := range msgs {
+			result = append(result, m.Status)
+		}
+		return result
+	}
+	require.Equal(t, []types.MessageStatus{"sent", "received", "sent", "sent"}, status())
+
+	require.NoError(t, s.MarkDelivered(ctx, testPageID, testPSID, []string{"mid.2000.20"}, 0))
+	require.Equal(t, []types.MessageStatus{"sent", "received", "delivered", "sent"}, status())
+
+	require.NoError(t, s.MarkDelivered(ctx, testPageID, testPSID, nil, 15))
+	require.Equal(t, []types.MessageStatus{"delivered", "received", "delivered", "sent"}, status())
+
+	require.NoError(t, s.MarkRead(ctx, testPageID, testPSID, 25))
+	require.Equal(t, []types.MessageStatus{"read", "received", "read", "sent"}, status())
+}
+
+func TestWorkspaceIsolation(t *testing.T) {
+	s := newTestService(t)
+	record(t, s, testPSID, types.Inbound, 1, "hello")
+	other := workspace.WithID(context.Background(), 2)
+
+	listResp, err := s.ListConversations(other, &types.ListConversationsRequest{PageID: testPageID})
+	require.NoError(t, err)
+	require.Empty(t, listResp.Conversations)
+	searchResp, err := s.SearchMessages(other, &types.SearchMessagesRequest{PageID: testPageID, Query: "hello"})
+	require.NoError(t, err)
+	require.Empty(t, searchResp.Messages)
+	transcriptResp, err := s.GetTranscript(other, &types.GetTranscriptRequest{PageID: testPageID, PSID: testPSID})
+	require.NoError(t, err)
+	require.Zero(t, transcriptResp.Total)
+
+	// the conversation belongs to the workspace of its first message
+	msg := &types.Message{PageID: testPageID, PSID: testPSID, MID: "mid.other", Direction: types.Inbound, Text: "hi"}
+	err = s.RecordMessage(other, msg)
+	require.Equal(t, xerrors.PermissionDenied, xerrors.GetCode(err))
+}
@@ -0,0 +1,180 @@
+package store
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"sort"
+	"sync"
+
+	"github.com/olvrng/rbot/be/com/conversation/types"
+	"github.com/olvrng/rbot/be/com/workspace"
+	"github.com/olvrng/rbot/be/pkg/dot"
+)
+
+type MessageFile struct {
+	// Conversations maps the conversation key to its messages, from the oldest
+	// to the newest.
+	Conversations map[string][]*types.Message `json:"conversations"`
+}
+
+type MessageStore struct {
+	FilePath string
+	Data     *MessageFile
+
+	m sync.Mutex
+}
+
+func NewMessageStore(filePath string) (*MessageStore, error) {
+	s := &MessageStore{
+		FilePath: filePath,
+	}
+
+	_, err := os.Stat(filePath)
+	switch {
+	case err == nil: // load from storage
+		data, err := ioutil.ReadFile(filePath)
+		if err != nil {
+			return nil, err
+		}
+		err = json.Unmarshal(data, &s.Data)
+		if err == nil && s.Data.Conversations == nil {
+			s.Data.Conversations = map[string][]*types.Message{}
+		}
+		return s, err
+
+	case os.IsNotExist(err): // try creating one
+		s.Data = &MessageFile{Conversations: map[string][]*types.Message{}}
+		err = storeFile(filePath, s.Data)
+		return s, err
+
+	default:
+		return nil, err
+	}
+}
+
+func conversationKey(pageID, psid dot.IntID) string {
+	return fmt.Sprintf("%v_%v", pageID, psid)
+}
+
+// AddMessage appends the message to its conversation. The message is ignored
+// when another message with the same MID exists.
+func (s *MessageStore) AddMessage(ctx context.Context, msg *types.Message) (ok bool, _ error) {
+	s.m.Lock()
+	defer s.m.Unlock()
+
+	key := conversationKey(msg.PageID, msg.PSID)
+	msgs := s.Data.Conversations[key]
+	if len(msgs) != 0 {
+		if err := workspace.CheckOwner(ctx, msgs[0].WorkspaceID); err != nil {
+			return false, err
+		}
+	}
+	msg.WorkspaceID = workspace.GetID(ctx)
+	if msg.MID != "" {
+		for _, m := range msgs {
+			if m.MID == msg.MID {
+				return false, nil
+			}
+		}
+	}
+	s.Data.Conversations[key] = append(msgs, msg)
+	return true, storeFile(s.FilePath, s.Data)
+}
+
+// UpdateOutbound calls fn on the outbound messages of the conversation, and
+// stores the messages which are updated.
+func (s *MessageStore) UpdateOutbound(ctx context.Context, pageID, psid dot.IntID, fn func(*types.Message) bool) (updated int, _ error) {
+	s.m.Lock()
+	defer s.m.Unlock()
+
+	for _, m := range s.Data.Conversations[conversationKey(pageID, psid)] {
+		if m.Direction == types.Outbound && workspace.Match(ctx, m.WorkspaceID) && fn(m) {
+			updated++
+		}
+	}
+	if updated == 0 {
+		return 0, nil
+	}
+	return updated, storeFile(s.FilePath, s.Data)
+}
+
+// ListMessages returns the messages of the conversation, from the oldest to the
+// newest.
+func (s *MessageStore) ListMessages(ctx context.Context, pageID, psid dot.IntID) ([]*types.Message, error) {
+	s.m.Lock()
+	defer s.m.Unlock()
+
+	var result []*types.Message
+	for _, m := range s.Data.Conversations[conversationKey(pageID, psid)] {
+		if workspace.Match(ctx, m.WorkspaceID) {
+			result = append(result, m)
+		}
+	}
+	return result, nil
+}
+
+// ListConversations returns the conversations of the page, the most recently
+// updated first.
+func (s *MessageStore) ListConversations(ctx context.Context, pageID dot.IntID) ([]*types.Conversation, error) {
+	s.m.Lock()
+	defer s.m.Unlock()
+
+	var result []*types.Conversation
+	for _, msgs := range s.Data.Conversations {
+		if len(msgs) == 0 || msgs[0].PageID != pageID || !workspace.Match(ctx, msgs[0].WorkspaceID) {
+			continue
+		}
+		last := msgs[len(msgs)-1]
+		result = append(result, &types.Conversation{
+			PageID:       last.PageID,
+			PSID:         last.PSID,
+			MessageCount: len(msgs),
+			LastMessage:  last,
+			UpdatedAt:    last.CreatedAt,
+		})
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].UpdatedAt != result[j].UpdatedAt {
+			return result[i].UpdatedAt > result[j].UpdatedAt
+		}
+		return result[i].PSID < result[j].PSID
+	})
+	return result, nil
+}
+
+// SearchMessages returns the messages of the page which pass the filter, newest
+// first. At most limit messages are returned, if limit is positive.
+func (s *MessageStore) SearchMessages(ctx context.Context, pageID dot.IntID, limit int, filter func(*types.Message) bool) ([]*types.Message, error) {
+	s.m.Lock()
+	defer s.m.Unlock()
+
+	var result []*types.Message
+	for _, msgs := range s.Data.Conversations {
+		for _, m := range msgs {
+			if m.PageID == pageID && workspace.Match(ctx, m.WorkspaceID) && filter(m) {
+				result = append(result, m)
+			}
+		}
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].CreatedAt != result[j].CreatedAt {
+			return result[i].CreatedAt > result[j].CreatedAt
+		}
+		return result[i].ID < result[j].ID
+	})
+	if limit > 0 && len(result) > limit {
+		result = result[:limit]
+	}
+	return result, nil
+}
+
+func storeFile(filePath string, data interface{}) error {
+	out, err := json.MarshalIndent(data, "", "\t")
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(filePath, out, 0644)
+}
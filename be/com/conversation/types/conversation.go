@@ -0,0 +1,98 @@
+package types
+
+import "github.com/olvrng/rbot/be/pkg/dot"
+
+type Direction string
+
+const (
+	Inbound  Direction = "in"
+	Outbound Direction = "out"
+)
+
+type MessageStatus string
+
+const (
+	StatusReceived  MessageStatus = "received"
+	StatusSent      MessageStatus = "sent"
+	StatusDelivered MessageStatus = "delivered"
+	StatusRead      MessageStatus = "read"
+)
+
+// Message is a message between the page and the customer. The outbound
+// messages are sent by the bot, or by an agent when the bot is paused.
+type Message struct {
+	ID          dot.IntID `json:"id"`
+	WorkspaceID dot.IntID `json:"workspace_id,omitempty"`
+	PageID      dot.IntID `json:"page_id"`
+	PSID        dot.IntID `json:"psid"`
+	MID         string    `json:"mid,omitempty"`
+	Direction   Direction `json:"direction"`
+
+	Text    string `json:"text"`
+	Payload string `json:"payload,omitempty"`
+
+	// FlowID and NodeID are the node which produced the message, if any.
+	FlowID dot.IntID `json:"flow_id,omitempty"`
+	NodeID dot.IntID `json:"node_id,omitempty"`
+	Agent  string    `json:"agent,omitempty"`
+
+	Status      MessageStatus `json:"status"`
+	CreatedAt   dot.Timestamp `json:"created_at"`
+	DeliveredAt dot.Timestamp `json:"delivered_at,omitempty"`
+	ReadAt      dot.Timestamp `json:"read_at,omitempty"`
+}
+
+type Conversation struct {
+	PageID       dot.IntID     `json:"page_id"`
+	PSID         dot.IntID     `json:"psid"`
+	MessageCount int           `json:"message_count"`
+	LastMessage  *Message      `json:"last_message"`
+	UpdatedAt    dot.Timestamp `json:"updated_at"`
+}
+
+type ListConversationsRequest struct {
+	// +validate:required
+	PageID dot.IntID `json:"page_id"`
+}
+
+type ListConversationsResponse struct {
+	Conversations []*Conversation `json:"conversations"`
+}
+
+// GetTranscriptRequest returns the latest Limit messages of the conversation.
+// Offset skips the newest messages, so the board loads the older messages by
+// increasing it.
+type GetTranscriptRequest struct {
+	// +validate:required
+	PageID dot.IntID `json:"page_id"`
+	// +validate:required
+	PSID dot.IntID `json:"psid"`
+	// +validate:min=0
+	Offset int `json:"offset"`
+	// +validate:min=1 +validate:max=500
+	Limit int `json:"limit"`
+}
+
+type TranscriptResponse struct {
+	// Messages are sorted from the oldest to the newest.
+	Messages []*Message `json:"messages"`
+	Total    int        `json:"total"`
+	HasMore  bool       `json:"has_more"`
+}
+
+// SearchMessagesRequest searches the text of the messages of the page. The
+// search ignores case and diacritics.
+type SearchMessagesRequest struct {
+	// +validate:required
+	PageID dot.IntID `json:"page_id"`
+	PSID   dot.IntID `json:"psid"`
+	// +validate:max-len=200
+	Query string `json:"query"`
+	// +validate:min=1 +validate:max=500
+	Limit int `json:"limit"`
+}
+
+type SearchMessagesResponse struct {
+	// Messages are sorted from the newest to the oldest.
+	Messages []*Message `json:"messages"`
+}
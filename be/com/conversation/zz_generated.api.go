@@ -0,0 +1,224 @@
+// +build !generator
+
+// Code generated by generator api. DO NOT EDIT.
+
+package conversation
+
+import (
+	context "context"
+	fmt "fmt"
+	http "net/http"
+
+	conversationtypes "github.com/olvrng/rbot/be/com/conversation/types"
+	httprpc "github.com/olvrng/rbot/be/pkg/httprpc"
+)
+
+func init() {
+	httprpc.Register(NewServer)
+	httprpc.RegisterSchemas(
+		&httprpc.Schema{Name: "github.com/olvrng/rbot/be/com/conversation/types.Conversation", Fields: []httprpc.Field{
+			{Name: "page_id", Number: 1, Kind: httprpc.KindInt},
+			{Name: "psid", Number: 2, Kind: httprpc.KindInt},
+			{Name: "message_count", Number: 3, Kind: httprpc.KindInt},
+			{Name: "last_message", Number: 4, Kind: httprpc.KindMessage, Message: "github.com/olvrng/rbot/be/com/conversation/types.Message"},
+			{Name: "updated_at", Number: 5, Kind: httprpc.KindInt},
+		}},
+		&httprpc.Schema{Name: "github.com/olvrng/rbot/be/com/conversation/types.GetTranscriptRequest", Fields: []httprpc.Field{
+			{Name: "page_id", Number: 1, Kind: httprpc.KindInt},
+			{Name: "psid", Number: 2, Kind: httprpc.KindInt},
+			{Name: "offset", Number: 3, Kind: httprpc.KindInt},
+			{Name: "limit", Number: 4, Kind: httprpc.KindInt},
+		}},
+		&httprpc.Schema{Name: "github.com/olvrng/rbot/be/com/conversation/types.ListConversationsRequest", Fields: []httprpc.Field{
+			{Name: "page_id", Number: 1, Kind: httprpc.KindInt},
+		}},
+		&httprpc.Schema{Name: "github.com/olvrng/rbot/be/com/conversation/types.ListConversationsResponse", Fields: []httprpc.Field{
+			{Name: "conversations", Number: 1, Kind: httprpc.KindMessage, Repeated: true, Message: "github.com/olvrng/rbot/be/com/conversation/types.Conversation"},
+		}},
+		&httprpc.Schema{Name: "github.com/olvrng/rbot/be/com/conversation/types.Message", Fields: []httprpc.Field{
+			{Name: "id", Number: 1, Kind: httprpc.KindInt},
+			{Name: "workspace_id", Number: 2, Kind: httprpc.KindInt},
+			{Name: "page_id", Number: 3, Kind: httprpc.KindInt},
+			{Name: "psid", Number: 4, Kind: httprpc.KindInt},
+			{Name: "mid", Number: 5, Kind: httprpc.KindString},
+			{Name: "direction", Number: 6, Kind: httprpc.KindString},
+			{Name: "text", Number: 7, Kind: httprpc.KindString},
+			{Name: "payload", Number: 8, Kind: httprpc.KindString},
+			{Name: "flow_id", Number: 9, Kind: httprpc.KindInt},
+			{Name: "node_id", Number: 10, Kind: httprpc.KindInt},
+			{Name: "agent", Number: 11, Kind: httprpc.KindString},
+			{Name: "status", Number: 12, Kind: httprpc.KindString},
+			{Name: "created_at", Number: 13, Kind: httprpc.KindInt},
+			{Name: "delivered_at", Number: 14, Kind: httprpc.KindInt},
+			{Name: "read_at", Number: 15, Kind: httprpc.KindInt},
+		}},
+		&httprpc.Schema{Name: "github.com/olvrng/rbot/be/com/conversation/types.SearchMessagesRequest", Fields: []httprpc.Field{
+			{Name: "page_id", Number: 1, Kind: httprpc.KindInt},
+			{Name: "psid", Number: 2, Kind: httprpc.KindInt},
+			{Name: "query", Number: 3, Kind: httprpc.KindString},
+			{Name: "limit", Number: 4, Kind: httprpc.KindInt},
+		}},
+		&httprpc.Schema{Name: "github.com/olvrng/rbot/be/com/conversation/types.SearchMessagesResponse", Fields: []httprpc.Field{
+			{Name: "messages", Number: 1, Kind: httprpc.KindMessage, Repeated: true, Message: "github.com/olvrng/rbot/be/com/conversation/types.Message"},
+		}},
+		&httprpc.Schema{Name: "github.com/olvrng/rbot/be/com/conversation/types.TranscriptResponse", Fields: []httprpc.Field{
+			{Name: "messages", Number: 1, Kind: httprpc.KindMessage, Repeated: true, Message: "github.com/olvrng/rbot/be/com/conversation/types.Message"},
+			{Name: "total", Number: 2, Kind: httprpc.KindInt},
+			{Name: "has_more", Number: 3, Kind: httprpc.KindBool},
+		}},
+	)
+}
+
+func NewServer(builder interface{}, hooks ...httprpc.HooksBuilder) (httprpc.Server, bool) {
+	switch builder := builder.(type) {
+	case func() ConversationService:
+		return NewConversationServiceServer(builder, hooks...), true
+	case ConversationService:
+		fn := func() ConversationService { return builder }
+		return NewConversationServiceServer(fn, hooks...), true
+	default:
+		return nil, false
+	}
+}
+
+type ConversationServiceServer struct {
+	hooks   httprpc.HooksBuilder
+	builder func() ConversationService
+}
+
+func NewConversationServiceServer(builder func() ConversationService, hooks ...httprpc.HooksBuilder) httprpc.Server {
+	return &ConversationServiceServer{
+		hooks:   httprpc.ChainHooks(hooks...),
+		builder: builder,
+	}
+}
+
+const ConversationServicePathPrefix = "/api/conversation/"
+
+const Path_Conversation_GetTranscript = "/api/conversation/GetTranscript"
+const Path_Conversation_ListConversations = "/api/conversation/ListConversations"
+const Path_Conversation_SearchMessages = "/api/conversation/SearchMessages"
+
+func (s *ConversationServiceServer) PathPrefix() string {
+	return ConversationServicePathPrefix
+}
+
+func (s *ConversationServiceServer) WithHooks(hooks httprpc.HooksBuilder) httprpc.Server {
+	result := *s
+	result.hooks = httprpc.ChainHooks(s.hooks, hooks)
+	return &result
+}
+
+func (s *ConversationServiceServer) ServeHTTP(resp http.ResponseWriter, req *http.Request) {
+	hooks := httprpc.WrapHooks(s.hooks)
+	ctx, info := req.Context(), &httprpc.HookInfo{Route: req.URL.Path, HTTPRequest: req}
+	ctx, err := hooks.RequestReceived(ctx, *info)
+	if err != nil {
+		httprpc.WriteError(ctx, resp, hooks, *info, err)
+		return
+	}
+	reqMsg, exec, allowGET, err := s.parseRoute(req.URL.Path, hooks, info)
+	if err != nil {
+		httprpc.WriteError(ctx, resp, hooks, *info, err)
+		return
+	}
+	serve, err := httprpc.ParseRequestHeader(req, allowGET)
+	if err != nil {
+		httprpc.WriteError(ctx, resp, hooks, *info, err)
+		return
+	}
+	serve(ctx, resp, req, hooks, info, reqMsg, exec)
+}
+
+func (s *ConversationServiceServer) parseRoute(path string, hooks httprpc.Hooks, info *httprpc.HookInfo) (reqMsg httprpc.Message, _ httprpc.ExecFunc, allowGET bool, _ error) {
+	switch path {
+	case "/api/conversation/GetTranscript":
+		msg := &conversationtypes.GetTranscriptRequest{}
+		fn := func(ctx context.Context) (newCtx context.Context, resp httprpc.Message, err error) {
+			inner := s.builder()
+			info.Request, info.Inner = msg, inner
+			newCtx, err = hooks.RequestRouted(ctx, *info)
+			if err != nil {
+				return
+			}
+			if err = msg.Validate(); err != nil {
+				return
+			}
+			resp, err = inner.GetTranscript(newCtx, msg)
+			return
+		}
+		return msg, fn, true, nil
+	case "/api/conversation/ListConversations":
+		msg := &conversationtypes.ListConversationsRequest{}
+		fn := func(ctx context.Context) (newCtx context.Context, resp httprpc.Message, err error) {
+			inner := s.builder()
+			info.Request, info.Inner = msg, inner
+			newCtx, err = hooks.RequestRouted(ctx, *info)
+			if err != nil {
+				return
+			}
+			if err = msg.Validate(); err != nil {
+				return
+			}
+			resp, err = inner.ListConversations(newCtx, msg)
+			return
+		}
+		return msg, fn, true, nil
+	case "/api/conversation/SearchMessages":
+		msg := &conversationtypes.SearchMessagesRequest{}
+		fn := func(ctx context.Context) (newCtx context.Context, resp httprpc.Message, err error) {
+			inner := s.builder()
+			info.Request, info.Inner = msg, inner
+			newCtx, err = hooks.RequestRouted(ctx, *info)
+			if err != nil {
+				return
+			}
+			if err = msg.Validate(); err != nil {
+				return
+			}
+			resp, err = inner.SearchMessages(newCtx, msg)
+			return
+		}
+		return msg, fn, true, nil
+	default:
+		msg := fmt.Sprintf("no handler for path %q", path)
+		return nil, nil, false, httprpc.BadRouteError(msg, "POST", path)
+	}
+}
+
+// ConversationServiceClient calls ConversationService on the server at baseURL.
+type ConversationServiceClient struct {
+	client *httprpc.Client
+}
+
+var _ ConversationService = &ConversationServiceClient{}
+
+func NewConversationServiceClient(baseURL string, httpClient *http.Client, hooks ...httprpc.ClientHooks) *ConversationServiceClient {
+	return &ConversationServiceClient{
+		client: httprpc.NewClient(baseURL, httpClient, hooks...),
+	}
+}
+
+func (c *ConversationServiceClient) GetTranscript(ctx context.Context, req *conversationtypes.GetTranscriptRequest) (*conversationtypes.TranscriptResponse, error) {
+	resp := &conversationtypes.TranscriptResponse{}
+	if err := c.client.Call(ctx, Path_Conversation_GetTranscript, req, resp); err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+func (c *ConversationServiceClient) ListConversations(ctx context.Context, req *conversationtypes.ListConversationsRequest) (*conversationtypes.ListConversationsResponse, error) {
+	resp := &conversationtypes.ListConversationsResponse{}
+	if err := c.client.Call(ctx, Path_Conversation_ListConversations, req, resp); err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+func (c *ConversationServiceClient) SearchMessages(ctx context.Context, req *conversationtypes.SearchMessagesRequest) (*conversationtypes.SearchMessagesResponse, error) {
+	resp := &conversationtypes.SearchMessagesResponse{}
+	if err := c.client.Call(ctx, Path_Conversation_SearchMessages, req, resp); err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
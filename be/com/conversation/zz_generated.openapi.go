@@ -0,0 +1,624 @@
+// +build !generator
+
+// Code generated by generator openapi. DO NOT EDIT.
+
+package conversation
+
+import (
+	openapi "github.com/olvrng/rbot/be/pkg/openapi"
+)
+
+func init() {
+	openapi.Register(openAPIFragment)
+}
+
+const openAPIFragment = `{
+  "paths": {
+    "/api/conversation/GetTranscript": {
+      "get": {
+        "operationId": "Conversation_GetTranscript_GET",
+        "tags": [
+          "ConversationService"
+        ],
+        "parameters": [
+          {
+            "name": "page_id",
+            "in": "query",
+            "schema": {
+              "type": "string",
+              "format": "int64"
+            }
+          },
+          {
+            "name": "psid",
+            "in": "query",
+            "schema": {
+              "type": "string",
+              "format": "int64"
+            }
+          },
+          {
+            "name": "offset",
+            "in": "query",
+            "schema": {
+              "type": "integer"
+            }
+          },
+          {
+            "name": "limit",
+            "in": "query",
+            "schema": {
+              "type": "integer"
+            }
+          }
+        ],
+        "responses": {
+          "200": {
+            "description": "OK",
+            "content": {
+              "application/json": {
+                "schema": {
+                  "$ref": "#/components/schemas/conversationtypes.TranscriptResponse"
+                }
+              },
+              "application/protobuf": {
+                "schema": {
+                  "type": "string",
+                  "format": "binary"
+                }
+              },
+              "application/x-www-form-urlencoded": {
+                "schema": {
+                  "$ref": "#/components/schemas/conversationtypes.TranscriptResponse"
+                }
+              }
+            }
+          },
+          "default": {
+            "description": "Error",
+            "content": {
+              "application/json": {
+                "schema": {
+                  "$ref": "#/components/schemas/Error"
+                }
+              }
+            }
+          }
+        }
+      },
+      "post": {
+        "operationId": "Conversation_GetTranscript",
+        "tags": [
+          "ConversationService"
+        ],
+        "requestBody": {
+          "required": true,
+          "content": {
+            "application/json": {
+              "schema": {
+                "$ref": "#/components/schemas/conversationtypes.GetTranscriptRequest"
+              }
+            },
+            "application/protobuf": {
+              "schema": {
+                "type": "string",
+                "format": "binary"
+              }
+            },
+            "application/x-www-form-urlencoded": {
+              "schema": {
+                "$ref": "#/components/schemas/conversationtypes.GetTranscriptRequest"
+              }
+            }
+          }
+        },
+        "responses": {
+          "200": {
+            "description": "OK",
+            "content": {
+              "application/json": {
+                "schema": {
+                  "$ref": "#/components/schemas/conversationtypes.TranscriptResponse"
+                }
+              },
+              "application/protobuf": {
+                "schema": {
+                  "type": "string",
+                  "format": "binary"
+                }
+              },
+              "application/x-www-form-urlencoded": {
+                "schema": {
+                  "$ref": "#/components/schemas/conversationtypes.TranscriptResponse"
+                }
+              }
+            }
+          },
+          "default": {
+            "description": "Error",
+            "content": {
+              "application/json": {
+                "schema": {
+                  "$ref": "#/components/schemas/Error"
+                }
+              }
+            }
+          }
+        }
+      }
+    },
+    "/api/conversation/ListConversations": {
+      "get": {
+        "operationId": "Conversation_ListConversations_GET",
+        "tags": [
+          "ConversationService"
+        ],
+        "parameters": [
+          {
+            "name": "page_id",
+            "in": "query",
+            "schema": {
+              "type": "string",
+              "format": "int64"
+            }
+          }
+        ],
+        "responses": {
+          "200": {
+            "description": "OK",
+            "content": {
+              "application/json": {
+                "schema": {
+                  "$ref": "#/components/schemas/conversationtypes.ListConversationsResponse"
+                }
+              },
+              "application/protobuf": {
+                "schema": {
+                  "type": "string",
+                  "format": "binary"
+                }
+              },
+              "application/x-www-form-urlencoded": {
+                "schema": {
+                  "$ref": "#/components/schemas/conversationtypes.ListConversationsResponse"
+                }
+              }
+            }
+          },
+          "default": {
+            "description": "Error",
+            "content": {
+              "application/json": {
+                "schema": {
+                  "$ref": "#/components/schemas/Error"
+                }
+              }
+            }
+          }
+        }
+      },
+      "post": {
+        "operationId": "Conversation_ListConversations",
+        "tags": [
+          "ConversationService"
+        ],
+        "requestBody": {
+          "required": true,
+          "content": {
+            "application/json": {
+              "schema": {
+                "$ref": "#/components/schemas/conversationtypes.ListConversationsRequest"
+              }
+            },
+            "application/protobuf": {
+              "schema": {
+                "type": "string",
+                "format": "binary"
+              }
+            },
+            "application/x-www-form-urlencoded": {
+              "schema": {
+                "$ref": "#/components/schemas/conversationtypes.ListConversationsRequest"
+              }
+            }
+          }
+        },
+        "responses": {
+          "200": {
+            "description": "OK",
+            "content": {
+              "application/json": {
+                "schema": {
+                  "$ref": "#/components/schemas/conversationtypes.ListConversationsResponse"
+                }
+              },
+              "application/protobuf": {
+                "schema": {
+                  "type": "string",
+                  "format": "binary"
+                }
+              },
+              "application/x-www-form-urlencoded": {
+                "schema": {
+                  "$ref": "#/components/schemas/conversationtypes.ListConversationsResponse"
+                }
+              }
+            }
+          },
+          "default": {
+            "description": "Error",
+            "content": {
+              "application/json": {
+                "schema": {
+                  "$ref": "#/components/schemas/Error"
+                }
+              }
+            }
+          }
+        }
+      }
+    },
+    "/api/conversation/SearchMessages": {
+      "get": {
+        "operationId": "Conversation_SearchMessages_GET",
+        "tags": [
+          "ConversationService"
+        ],
+        "parameters": [
+          {
+            "name": "page_id",
+            "in": "query",
+            "schema": {
+              "type": "string",
+              "format": "int64"
+            }
+          },
+          {
+            "name": "psid",
+            "in": "query",
+            "schema": {
+              "type": "string",
+              "format": "int64"
+            }
+          },
+          {
+            "name": "query",
+            "in": "query",
+            "schema": {
+              "type": "string"
+            }
+          },
+          {
+            "name": "limit",
+            "in": "query",
+            "schema": {
+              "type": "integer"
+            }
+          }
+        ],
+        "responses": {
+          "200": {
+            "description": "OK",
+            "content": {
+              "application/json": {
+                "schema": {
+                  "$ref": "#/components/schemas/conversationtypes.SearchMessagesResponse"
+                }
+              },
+              "application/protobuf": {
+                "schema": {
+                  "type": "string",
+                  "format": "binary"
+                }
+              },
+              "application/x-www-form-urlencoded": {
+                "schema": {
+                  "$ref": "#/components/schemas/conversationtypes.SearchMessagesResponse"
+                }
+              }
+            }
+          },
+          "default": {
+            "description": "Error",
+            "content": {
+              "application/json": {
+                "schema": {
+                  "$ref": "#/components/schemas/Error"
+                }
+              }
+            }
+          }
+        }
+      },
+      "post": {
+        "operationId": "Conversation_SearchMessages",
+        "tags": [
+          "ConversationService"
+        ],
+        "requestBody": {
+          "required": true,
+          "content": {
+            "application/json": {
+              "schema": {
+                "$ref": "#/components/schemas/conversationtypes.SearchMessagesRequest"
+              }
+            },
+            "application/protobuf": {
+              "schema": {
+                "type": "string",
+                "format": "binary"
+              }
+            },
+            "application/x-www-form-urlencoded": {
+              "schema": {
+                "$ref": "#/components/schemas/conversationtypes.SearchMessagesRequest"
+              }
+            }
+          }
+        },
+        "responses": {
+          "200": {
+            "description": "OK",
+            "content": {
+              "application/json": {
+                "schema": {
+                  "$ref": "#/components/schemas/conversationtypes.SearchMessagesResponse"
+                }
+              },
+              "application/protobuf": {
+                "schema": {
+                  "type": "string",
+                  "format": "binary"
+                }
+              },
+              "application/x-www-form-urlencoded": {
+                "schema": {
+                  "$ref": "#/components/schemas/conversationtypes.SearchMessagesResponse"
+                }
+              }
+            }
+          },
+          "default": {
+            "description": "Error",
+            "content": {
+              "application/json": {
+                "schema": {
+                  "$ref": "#/components/schemas/Error"
+                }
+              }
+            }
+          }
+        }
+      }
+    }
+  },
+  "components": {
+    "schemas": {
+      "conversationtypes.Conversation": {
+        "type": "object",
+        "properties": {
+          "last_message": {
+            "$ref": "#/components/schemas/conversationtypes.Message"
+          },
+          "message_count": {
+            "type": "integer"
+          },
+          "page_id": {
+            "type": "string",
+            "format": "int64"
+          },
+          "psid": {
+            "type": "string",
+            "format": "int64"
+          },
+          "updated_at": {
+            "type": "string",
+            "description": "milliseconds from 1970"
+          }
+        },
+        "required": [
+          "page_id",
+          "psid",
+          "message_count",
+          "updated_at"
+        ]
+      },
+      "conversationtypes.Direction": {
+        "type": "string",
+        "enum": [
+          "in",
+          "out"
+        ]
+      },
+      "conversationtypes.GetTranscriptRequest": {
+        "type": "object",
+        "properties": {
+          "limit": {
+            "type": "integer"
+          },
+          "offset": {
+            "type": "integer"
+          },
+          "page_id": {
+            "type": "string",
+            "format": "int64"
+          },
+          "psid": {
+            "type": "string",
+            "format": "int64"
+          }
+        },
+        "required": [
+          "page_id",
+          "psid",
+          "offset",
+          "limit"
+        ]
+      },
+      "conversationtypes.ListConversationsRequest": {
+        "type": "object",
+        "properties": {
+          "page_id": {
+            "type": "string",
+            "format": "int64"
+          }
+        },
+        "required": [
+          "page_id"
+        ]
+      },
+      "conversationtypes.ListConversationsResponse": {
+        "type": "object",
+        "properties": {
+          "conversations": {
+            "type": "array",
+            "items": {
+              "$ref": "#/components/schemas/conversationtypes.Conversation"
+            }
+          }
+        },
+        "required": [
+          "conversations"
+        ]
+      },
+      "conversationtypes.Message": {
+        "type": "object",
+        "properties": {
+          "agent": {
+            "type": "string"
+          },
+          "created_at": {
+            "type": "string",
+            "description": "milliseconds from 1970"
+          },
+          "delivered_at": {
+            "type": "string",
+            "description": "milliseconds from 1970"
+          },
+          "direction": {
+            "$ref": "#/components/schemas/conversationtypes.Direction"
+          },
+          "flow_id": {
+            "type": "string",
+            "format": "int64"
+          },
+          "id": {
+            "type": "string",
+            "format": "int64"
+          },
+          "mid": {
+            "type": "string"
+          },
+          "node_id": {
+            "type": "string",
+            "format": "int64"
+          },
+          "page_id": {
+            "type": "string",
+            "format": "int64"
+          },
+          "payload": {
+            "type": "string"
+          },
+          "psid": {
+            "type": "string",
+            "format": "int64"
+          },
+          "read_at": {
+            "type": "string",
+            "description": "milliseconds from 1970"
+          },
+          "status": {
+            "$ref": "#/components/schemas/conversationtypes.MessageStatus"
+          },
+          "text": {
+            "type": "string"
+          },
+          "workspace_id": {
+            "type": "string",
+            "format": "int64"
+          }
+        },
+        "required": [
+          "id",
+          "page_id",
+          "psid",
+          "direction",
+          "text",
+          "status",
+          "created_at"
+        ]
+      },
+      "conversationtypes.MessageStatus": {
+        "type": "string",
+        "enum": [
+          "delivered",
+          "read",
+          "received",
+          "sent"
+        ]
+      },
+      "conversationtypes.SearchMessagesRequest": {
+        "type": "object",
+        "properties": {
+          "limit": {
+            "type": "integer"
+          },
+          "page_id": {
+            "type": "string",
+            "format": "int64"
+          },
+          "psid": {
+            "type": "string",
+            "format": "int64"
+          },
+          "query": {
+            "type": "string"
+          }
+        },
+        "required": [
+          "page_id",
+          "psid",
+          "query",
+          "limit"
+        ]
+      },
+      "conversationtypes.SearchMessagesResponse": {
+        "type": "object",
+        "properties": {
+          "messages": {
+            "type": "array",
+            "items": {
+              "$ref": "#/components/schemas/conversationtypes.Message"
+            }
+          }
+        },
+        "required": [
+          "messages"
+        ]
+      },
+      "conversationtypes.TranscriptResponse": {
+        "type": "object",
+        "properties": {
+          "has_more": {
+            "type": "boolean"
+          },
+          "messages": {
+            "type": "array",
+            "items": {
+              "$ref": "#/components/schemas/conversationtypes.Message"
+            }
+          },
+          "total": {
+            "type": "integer"
+          }
+        },
+        "required": [
+          "messages",
+          "total",
+          "has_more"
+        ]
+      }
+    }
+  }
+}`
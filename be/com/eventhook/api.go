@@ -0,0 +1,24 @@
+package eventhook
+
+import (
+	"context"
+
+	"github.com/olvrng/rbot/be/com/eventhook/types"
+)
+
+// +gen:api
+
+// +api:path=/api/eventhook
+type EventHookService interface {
+	CreateSubscription(ctx context.Context, req *types.CreateSubscriptionRequest) (*types.SubscriptionResponse, error)
+
+	UpdateSubscription(ctx context.Context, req *types.UpdateSubscriptionRequest) (*types.SubscriptionResponse, error)
+
+	DeleteSubscription(ctx context.Context, req *types.DeleteSubscriptionRequest) (*types.DeleteSubscriptionResponse, error)
+
+	// +api:get
+	ListSubscriptions(ctx context.Context, req *types.ListSubscriptionsRequest) (*types.ListSubscriptionsResponse, error)
+
+	// +api:get
+	ListDeliveries(ctx context.Context, req *types.ListDeliveriesRequest) (*types.ListDeliveriesResponse, error)
+}
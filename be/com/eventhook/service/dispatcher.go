@@ -0,0 +1,236 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/olvrng/rbot/be/com/eventhook/store"
+	"github.com/olvrng/rbot/be/com/eventhook/types"
+	flowexectypes "github.com/olvrng/rbot/be/com/flowexec/types"
+	"github.com/olvrng/rbot/be/com/workspace"
+	"github.com/olvrng/rbot/be/pkg/clock"
+	"github.com/olvrng/rbot/be/pkg/dot"
+	"github.com/olvrng/rbot/be/pkg/l"
+	"github.com/olvrng/rbot/be/pkg/metrics"
+)
+
+// dueDeliveries is the number of the deliveries which were due at the last
+// dispatch, so a growing value means the subscribers are failing.
+var dueDeliveries = metrics.NewGauge(
+	"rbot_eventhook_due_deliveries",
+	"The deliveries which were due at the last dispatch.")
+
+const (
+	DefaultDispatchInterval = 10 * time.Second
+	DefaultDeliveryTimeout  = 10 * time.Second
+
+	// DefaultMaxAttempts is the number of attempts before a delivery fails.
+	// The delay between the attempts starts at DefaultRetryDelay then doubles:
+	// 30s, 1m, 2m, 4m.
+	DefaultMaxAttempts = 5
+	DefaultRetryDelay  = 30 * time.Second
+)
+
+// The headers of the deliveries. The receiver verifies the payload by
+// computing the HMAC-SHA256 of the body with the secret of the subscription.
+const (
+	HeaderEvent     = "X-Rbot-Event"
+	HeaderDelivery  = "X-Rbot-Delivery"
+	HeaderSignature = "X-Rbot-Signature"
+)
+
+// Dispatcher posts the flow events to the subscriptions. HandleEvent subscribes
+// to the event bus and logs a pending delivery for each matching subscription,
+// then Run delivers them, retrying with backoff until they succeed or fail
+// MaxAttempts times.
+type Dispatcher struct {
+	SubscriptionStore *store.SubscriptionStore
+	DeliveryStore     *store.DeliveryStore
+	Client            *http.Client
+	Clock             clock.Clock
+
+	// Interval is how often the due deliveries are checked, when no new event
+	// comes.
+	Interval    time.Duration
+	MaxAttempts int
+	RetryDelay  time.Duration
+
+	wake chan struct{}
+}
+
+func NewDispatcher(subscriptionStore *store.SubscriptionStore, deliveryStore *store.DeliveryStore, clk clock.Clock) *Dispatcher {
+	d := &Dispatcher{
+		SubscriptionStore: subscriptionStore,
+		DeliveryStore:     deliveryStore,
+		Client:            &http.Client{Timeout: DefaultDeliveryTimeout},
+		Clock:             clk,
+		Interval:          DefaultDispatchInterval,
+		MaxAttempts:       DefaultMaxAttempts,
+		RetryDelay:        DefaultRetryDelay,
+		wake:              make(chan struct{}, 1),
+	}
+	return d
+}
+
+// HandleEvent logs a pending delivery of the event for each matching
+// subscription. It does not post the event, so it does not block the
+// conversation.
+func (d *Dispatcher) HandleEvent(ctx context.Context, event *flowexectypes.FlowEvent) {
+	subs, err := d.SubscriptionStore.ListSubscriptions(ctx, event.PageID)
+	if err != nil {
+		ll.Error("eventhook: can not list subscriptions", l.Error(err))
+		return
+	}
+	var payload []byte
+	queued := false
+	for _, sub := range subs {
+		if !sub.Match(event) {
+			continue
+		}
+		if payload == nil {
+			if payload, err = json.Marshal(event); err != nil {
+				ll.Error("eventhook: can not encode event", l.Error(err))
+				return
+			}
+		}
+		now := dot.ToTimestamp(d.Clock.Now())
+		delivery := &types.Delivery{
+			ID:             dot.NewIntID(),
+			SubscriptionID: sub.ID,
+			PageID:         sub.PageID,
+			EventID:        event.ID,
+			EventType:      event.Type,
+			URL:            sub.URL,
+			Payload:        string(payload),
+			Status:         types.DeliveryPending,
+			NextAttemptAt:  now,
+			CreatedAt:      now,
+			UpdatedAt:      now,
+		}
+		if err = d.DeliveryStore.SaveDelivery(ctx, delivery); err != nil {
+			ll.Error("eventhook: can not save delivery", l.Error(err))
+			continue
+		}
+		queued = true
+	}
+	if queued {
+		select {
+		case d.wake <- struct{}{}:
+		default:
+		}
+	}
+}
+
+// Run delivers the due deliveries every Interval, or as soon as new events are
+// queued, until the context is cancelled.
+func (d *Dispatcher) Run(ctx context.Context) {
+	ticker := time.NewTicker(d.Interval)
+	defer ticker.Stop()
+	for {
+		if _, err := d.DeliverDue(ctx); err != nil {
+			ll.Error("eventhook: can not deliver events", l.Error(err))
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		case <-d.wake:
+		}
+	}
+}
+
+// DeliverDue attempts the deliveries which are due at the current time of the
+// clock. It returns the number of succeeded deliveries.
+func (d *Dispatcher) DeliverDue(ctx context.Context) (succeeded int, _ error) {
+	deliveries, err := d.DeliveryStore.ListDueDeliveries(ctx, dot.ToTimestamp(d.Clock.Now()))
+	if err != nil {
+		return 0, err
+	}
+	dueDeliveries.Set(float64(len(deliveries)))
+	for _, delivery := range deliveries {
+		// each delivery is attempted in the workspace of its subscription
+		ok, err := d.deliver(workspace.WithID(ctx, delivery.WorkspaceID), delivery)
+		if err != nil {
+			return succeeded, err
+		}
+		if ok {
+			succeeded++
+		}
+	}
+	return succeeded, nil
+}
+
+// deliver makes one attempt, then logs the result and schedules the next
+// attempt. The deliveries of a removed subscription fail.
+func (d *Dispatcher) deliver(ctx context.Context, delivery *types.Delivery) (ok bool, _ error) {
+	updated := *delivery
+	updated.Attempts++
+	sub, err := d.SubscriptionStore.GetSubscription(ctx, delivery.SubscriptionID)
+	switch {
+	case err != nil:
+		updated.Status, updated.Error = types.DeliveryFailed, "subscription not found"
+	case sub.Disabled:
+		updated.Status, updated.Error = types.DeliveryFailed, "subscription disabled"
+	default:
+		updated.StatusCode, err = d.post(ctx, sub, delivery)
+		updated.Error = ""
+		if err != nil {
+			updated.Error = err.Error()
+		}
+		switch {
+		case err == nil:
+			updated.Status = types.DeliverySucceeded
+		case updated.Attempts >= d.MaxAttempts:
+			updated.Status = types.DeliveryFailed
+		default:
+			backoff := d.RetryDelay << (updated.Attempts - 1)
+			updated.NextAttemptAt = dot.ToTimestamp(d.Clock.Now().Add(backoff))
+		}
+	}
+	if updated.Status == types.DeliveryFailed {
+		ll.Warn("eventhook: delivery failed", l.ID("delivery_id", delivery.ID), l.String("error", updated.Error))
+	}
+	updated.UpdatedAt = dot.ToTimestamp(d.Clock.Now())
+	return updated.Status == types.DeliverySucceeded, d.DeliveryStore.SaveDelivery(ctx, &updated)
+}
+
+func (d *Dispatcher) post(ctx context.Context, sub *types.Subscription, delivery *types.Delivery) (statusCode int, _ error) {
+	body := []byte(delivery.Payload)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, sub.URL, bytes.NewReader(body))
+	if err != nil {
+		return 0, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(HeaderEvent, string(delivery.EventType))
+	req.Header.Set(HeaderDelivery, strconv.FormatInt(int64(delivery.ID), 10))
+	req.Header.Set(HeaderSignature, Sign(sub.Secret, body))
+
+	resp, err := d.Client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(ioutil.Discard, resp.Body)
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return resp.StatusCode, fmt.Errorf("unexpected status %v", resp.StatusCode)
+	}
+	return resp.StatusCode, nil
+}
+
+// Sign returns the signature of the body, as sent in the X-Rbot-Signature
+// header: "sha256=" then the hex of HMAC-SHA256(secret, body).
+func Sign(secret string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
@@ -0,0 +1,281 @@
+package service
+
+import (
+	"context"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/olvrng/rbot/be/com/eventhook/store"
+	"github.com/olvrng/rbot/be/com/eventhook/types"
+	flowexectypes "github.com/olvrng/rbot/be/com/flowexec/types"
+	"github.com/olvrng/rbot/be/pkg/clock"
+	"github.com/olvrng/rbot/be/pkg/dot"
+	"github.com/olvrng/rbot/be/pkg/xerrors"
+)
+
+const testPageID = 1001
+
+// receiver is a webhook which fails the first requests.
+type receiver struct {
+	m        sync.Mutex
+	failures int
+	requests []*http.Request
+	bodies   []string
+}
+
+func (r *receiver) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	r.m.Lock()
+	defer r.m.Unlock()
+	body, _ := ioutil.ReadAll(req.Body)
+	r.requests = append(r.requests, req)
+	r.bodies = append(r.bodies, string(body))
+	if r.failures > 0 {
+		r.failures--
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}
+}
+
+type dispatcherTest struct {
+	service    *EventHookService
+	dispatcher *Dispatcher
+	clock      *clock.Mock
+	receiver   *receiver
+	server     *httptest.Server
+}
+
+func newDispatcherTest(t *testing.T) *dispatcherTest {
+	subscriptionStore, err := store.NewSubscriptionStore("")
+	require.NoError(t, err)
+	deliveryStore, err := store.NewDeliveryStore("")
+	require.NoError(t, err)
+
+	dt := &dispatcherTest{
+		service:  NewEventHookService(subscriptionStore, deliveryStore),
+		clock:    clock.NewMock(time.Date(2021, 6, 1, 10, 0, 0, 0, time.UTC)),
+		receiver: &receiver{},
+	}
+	dt.dispatcher = NewDispatcher(subscriptionStore, deliveryStore, dt.clock)
+	dt.server = httptest.NewServer(dt.receiver)
+	t.Cleanup(dt.server.Close)
+	return dt
+}
+
+func (dt *dispatcherTest) subscribe(t *testing.T, req *types.CreateSubscriptionRequest) *types.Subscription {
+	req.PageID = testPageID
+	req.URL = dt.server.URL
+	resp, err := dt.service.CreateSubscription(context.Background(), req)
+	require.NoError(t, err)
+	return resp.Subscription
+}
+
+func (dt *dispatcherTest) deliveries(t *testing.T) []*types.Delivery {
+	resp, err := dt.service.ListDeliveries(context.Background(), &types.ListDeliveriesRequest{PageID: testPageID})
+	require.NoError(t, err)
+	return resp.Deliveries
+}
+
+func newEvent(typ flowexectypes.FlowEventType, flowID dot.IntID) *flowexectypes.FlowEvent {
+	return &flowexectypes.FlowEvent{
+		ID:     dot.NewIntID(),
+		Type:   typ,
+		PageID: testPageID,
+		PSID:   2002,
+		FlowID: flowID,
+		NodeID: 3,
+	}
+}
+
+func TestDeliverSigned(t *testing.T) {
+	ctx := context.Background()
+	dt := newDispatcherTest(t)
+	sub := dt.subscribe(t, &types.CreateSubscriptionRequest{Secret: "s3cret"})
+
+	dt.dispatcher.HandleEvent(ctx, newEvent(flowexectypes.EventMessageSent, 1))
+	n, err := dt.dispatcher.DeliverDue(ctx)
+	require.NoError(t, err)
+	require.Equal(t, 1, n)
+
+	require.Len(t, dt.receiver.requests, 1)
+	req, body := dt.receiver.requests[0], dt.receiver.bodies[0]
+	require.Equal(t, "message.sent", req.Header.Get(HeaderEvent))
+	require.Equal(t, Sign("s3cret", []byte(body)), req.Header.Get(HeaderSignature))
+	require.Contains(t, body, `"type":"message.sent"`)
+
+	deliveries := dt.deliveries(t)
+	require.Len(t, deliveries, 1)
+	require.Equal(t, sub.ID, deliveries[0].SubscriptionID)
+	require.Equal(t, types.DeliverySucceeded, deliveries[0].Status)
+	require.Equal(t, http.StatusOK, deliveries[0].StatusCode)
+	require.Equal(t, 1, deliveries[0].Attempts)
+}
+
+func TestSign(t *testing.T) {
+	// echo -n '{"id":"1"}' | openssl dgst -sha256 -hmac secret
+	require.Equal(t,
+		"sha256=6146142a2ce0159e84c0767881e4ec80bc397da62526e7d19f70795eb79460c0",
+		Sign("secret", []byte(`{"id":"1"}`)))
+}
+
+func TestDeliverRetry(t *testing.T) {
+	ctx := context.Background()
+	dt := newDispatcherTest(t)
+	dt.subscribe(t, &types.CreateSubscriptionRequest{})
+	dt.receiver.failures = 2
+
+	dt.dispatcher.HandleEvent(ctx, newEvent(flowexectypes.EventFlowCompleted, 1))
+	n, err := dt.dispatcher.DeliverDue(ctx)
+	require.NoError(t, err)
+	require.Equal(t, 0, n)
+	delivery := dt.deliveries(t)[0]
+	require.Equal(t, types.DeliveryPending, delivery.Status)
+	require.Equal(t, http.StatusServiceUnavailable, delivery.StatusCode)
+	require.Equal(t, "unexpected status 503", delivery.Error)
+
+	// not due yet
+	dt.clock.Add(DefaultRetryDelay - time.Second)
+	_, err = dt.dispatcher.DeliverDue(ctx)
+	require.NoError(t, err)
+	require.Len(t, dt.receiver.requests, 1)
+
+	// the second attempt fails, then the delay doubles
+	dt.clock.Add(time.Second)
+	_, err = dt.dispatcher.DeliverDue(ctx)
+	require.NoError(t, err)
+	require.Len(t, dt.receiver.requests, 2)
+	delivery = dt.deliveries(t)[0]
+	require.Equal(t, dot.ToTimestamp(dt.clock.Now().Add(2*DefaultRetryDelay)), delivery.NextAttemptAt)
+
+	dt.clock.Add(2 * DefaultRetryDelay)
+	n, err = dt.dispatcher.DeliverDue(ctx)
+	require.NoError(t, err)
+	require.Equal(t, 1, n)
+	delivery = dt.deliveries(t)[0]
+	require.Equal(t, types.DeliverySucceeded, delivery.Status)
+	require.Equal(t, 3, delivery.Attempts)
+	require.Empty(t, delivery.Error)
+}
+
+func TestDeliverFailed(t *testing.T) {
+	ctx := context.Background()
+	dt := newDispatcherTest(t)
+	dt.subscribe(t, &types.CreateSubscriptionRequest{})
+	dt.receiver.failures = 100
+	dt.dispatcher.MaxAttempts = 3
+
+	dt.dispatcher.HandleEvent(ctx, newEvent(flowexectypes.EventFlowCompleted, 1))
+	for i := 0; i < 5; i++ {
+		_, err := dt.dispatcher.DeliverDue(ctx)
+		require.NoError(t, err)
+		dt.clock.Add(time.Hour)
+	}
+	require.Len(t, dt.receiver.requests, 3)
+	delivery := dt.deliveries(t)[0]
+	require.Equal(t, types.DeliveryFailed, delivery.Status)
+	require.Equal(t, 3, delivery.Attempts)
+}
+
+func TestMatchSubscriptions(t *testing.T) {
+	ctx := context.Background()
+	dt := newDispatcherTest(t)
+	all := dt.subscribe(t, &types.CreateSubscriptionRequest{})
+	flow2 := dt.subscribe(t, &types.CreateSubscriptionRequest{FlowID: 2})
+	messages := dt.subscribe(t, &types.CreateSubscriptionRequest{
+		EventTypes: []flowexectypes.FlowEventType{flowexectypes.EventMessageSent, flowexectypes.EventMessageFailed},
+	})
+	dt.subscribe(t, &types.CreateSubscriptionRequest{Disabled: true})
+
+	tests := []struct {
+		name  string
+		event *flowexectypes.FlowEvent
+		want  []dot.IntID
+	}{
+		{
+			name:  "message in flow 1",
+			event: newEvent(flowexectypes.EventMessageSent, 1),
+			want:  []dot.IntID{all.ID, messages.ID},
+		},
+		{
+			name:  "review in flow 2",
+			event: newEvent(flowexectypes.EventReviewSubmitted, 2),
+			want:  []dot.IntID{all.ID, flow2.ID},
+		},
+		{
+			name:  "other page",
+			event: &flowexectypes.FlowEvent{Type: flowexectypes.EventMessageSent, PageID: 9},
+			want:  nil,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dt.dispatcher.HandleEvent(ctx, tt.event)
+			var got []dot.IntID
+			for _, d := range dt.deliveries(t) {
+				if d.EventID == tt.event.ID {
+					got = append(got, d.SubscriptionID)
+				}
+			}
+			require.ElementsMatch(t, tt.want, got)
+		})
+	}
+}
+
+func TestCreateSubscription(t *testing.T) {
+	ctx := context.Background()
+	dt := newDispatcherTest(t)
+
+	sub := dt.subscribe(t, &types.CreateSubscriptionRequest{})
+	require.Len(t, sub.Secret, 48)
+
+	tests := []struct {
+		name string
+		req  *types.CreateSubscriptionRequest
+		want string
+	}{
+		{
+			name: "no page",
+			req:  &types.CreateSubscriptionRequest{URL: "https://example.com/hook"},
+			want: "page_id is required",
+		},
+		{
+			name: "scheme",
+			req:  &types.CreateSubscriptionRequest{PageID: testPageID, URL: "ftp://example.com/hook"},
+			want: `unsupported scheme "ftp"`,
+		},
+		{
+			name: "event type",
+			req: &types.CreateSubscriptionRequest{PageID: testPageID, URL: "https://example.com/hook",
+				EventTypes: []flowexectypes.FlowEventType{"order.paid"}},
+			want: `unknown event type "order.paid"`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := dt.service.CreateSubscription(ctx, tt.req)
+			require.Error(t, err)
+			require.Equal(t, xerrors.InvalidArgument, xerrors.GetCode(err))
+			require.Contains(t, err.Error(), tt.want)
+		})
+	}
+
+	resp, err := dt.service.UpdateSubscription(ctx, &types.UpdateSubscriptionRequest{ID: sub.ID, PageID: testPageID, URL: sub.URL, Disabled: true})
+	require.NoError(t, err)
+	require.True(t, resp.Subscription.Disabled)
+	require.Equal(t, sub.Secret, resp.Subscription.Secret)
+
+	// the subscription belongs to another page
+	_, err = dt.service.UpdateSubscription(ctx, &types.UpdateSubscriptionRequest{ID: sub.ID, PageID: 9, URL: sub.URL})
+	require.Equal(t, xerrors.NotFound, xerrors.GetCode(err))
+	delResp, err := dt.service.DeleteSubscription(ctx, &types.DeleteSubscriptionRequest{ID: sub.ID, PageID: 9})
+	require.NoError(t, err)
+	require.Equal(t, 0, delResp.Deleted)
+
+	delResp, err = dt.service.DeleteSubscription(ctx, &types.DeleteSubscriptionRequest{ID: sub.ID, PageID: testPageID})
+	require.NoError(t, err)
+	require.Equal(t, 1, delResp.Deleted)
+}
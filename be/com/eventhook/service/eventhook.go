@@ -0,0 +1,188 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"net/url"
+
+	"github.com/olvrng/rbot/be/com/eventhook"
+	"github.com/olvrng/rbot/be/com/eventhook/store"
+	"github.com/olvrng/rbot/be/com/eventhook/types"
+	flowexectypes "github.com/olvrng/rbot/be/com/flowexec/types"
+	"github.com/olvrng/rbot/be/pkg/dot"
+	"github.com/olvrng/rbot/be/pkg/l"
+	"github.com/olvrng/rbot/be/pkg/xerrors"
+)
+
+var ll = l.New()
+
+// DefaultDeliveryLimit is the number of deliveries listed when the request has
+// no limit.
+const DefaultDeliveryLimit = 100
+
+var _ eventhook.EventHookService = (*EventHookService)(nil)
+
+type EventHookService struct {
+	SubscriptionStore *store.SubscriptionStore
+	DeliveryStore     *store.DeliveryStore
+}
+
+func NewEventHookService(subscriptionStore *store.SubscriptionStore, deliveryStore *store.DeliveryStore) *EventHookService {
+	s := &EventHookService{
+		SubscriptionStore: subscriptionStore,
+		DeliveryStore:     deliveryStore,
+	}
+	return s
+}
+
+func (s *EventHookService) CreateSubscription(ctx context.Context, req *types.CreateSubscriptionRequest) (*types.SubscriptionResponse, error) {
+	if req.PageID == 0 {
+		return nil, xerrors.Errorf(xerrors.InvalidArgument, nil, "page_id is required")
+	}
+	if err := validateSubscription(req.URL, req.EventTypes); err != nil {
+		return nil, err
+	}
+	secret := req.Secret
+	if secret == "" {
+		secret = newSecret()
+	}
+
+	now := dot.Now()
+	sub := &types.Subscription{
+		ID:         dot.NewIntID(),
+		PageID:     req.PageID,
+		FlowID:     req.FlowID,
+		URL:        req.URL,
+		Secret:     secret,
+		EventTypes: req.EventTypes,
+		Disabled:   req.Disabled,
+		CreatedAt:  now,
+		UpdatedAt:  now,
+	}
+	if err := s.SubscriptionStore.SaveSubscription(ctx, sub); err != nil {
+		return nil, xerrors.Errorf(xerrors.Internal, err, "internal error")
+	}
+	return &types.SubscriptionResponse{Subscription: sub}, nil
+}
+
+func (s *EventHookService) UpdateSubscription(ctx context.Context, req *types.UpdateSubscriptionRequest) (*types.SubscriptionResponse, error) {
+	if req.ID == 0 || req.PageID == 0 {
+		return nil, xerrors.Errorf(xerrors.InvalidArgument, nil, "id and page_id are required")
+	}
+	if err := validateSubscription(req.URL, req.EventTypes); err != nil {
+		return nil, err
+	}
+	current, err := s.getSubscription(ctx, req.PageID, req.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	sub := *current
+	sub.FlowID = req.FlowID
+	sub.URL = req.URL
+	sub.EventTypes = req.EventTypes
+	sub.Disabled = req.Disabled
+	if req.Secret != "" {
+		sub.Secret = req.Secret
+	}
+	sub.UpdatedAt = dot.Now()
+	if err = s.SubscriptionStore.SaveSubscription(ctx, &sub); err != nil {
+		return nil, xerrors.Errorf(xerrors.Internal, err, "internal error")
+	}
+	return &types.SubscriptionResponse{Subscription: &sub}, nil
+}
+
+func (s *EventHookService) DeleteSubscription(ctx context.Context, req *types.DeleteSubscriptionRequest) (*types.DeleteSubscriptionResponse, error) {
+	if req.ID == 0 || req.PageID == 0 {
+		return nil, xerrors.Errorf(xerrors.InvalidArgument, nil, "id and page_id are required")
+	}
+	if _, err := s.getSubscription(ctx, req.PageID, req.ID); err != nil {
+		if xerrors.GetCode(err) == xerrors.NotFound {
+			return &types.DeleteSubscriptionResponse{}, nil
+		}
+		return nil, err
+	}
+	deleted, err := s.SubscriptionStore.DeleteSubscription(ctx, req.ID)
+	if err != nil {
+		return nil, xerrors.Errorf(xerrors.Internal, err, "internal error")
+	}
+	return &types.DeleteSubscriptionResponse{Deleted: deleted}, nil
+}
+
+func (s *EventHookService) ListSubscriptions(ctx context.Context, req *types.ListSubscriptionsRequest) (*types.ListSubscriptionsResponse, error) {
+	if req.PageID == 0 {
+		return nil, xerrors.Errorf(xerrors.InvalidArgument, nil, "page_id is required")
+	}
+	subs, err := s.SubscriptionStore.ListSubscriptions(ctx, req.PageID)
+	if err != nil {
+		return nil, err
+	}
+	return &types.ListSubscriptionsResponse{Subscriptions: subs}, nil
+}
+
+func (s *EventHookService) ListDeliveries(ctx context.Context, req *types.ListDeliveriesRequest) (*types.ListDeliveriesResponse, error) {
+	if req.PageID == 0 {
+		return nil, xerrors.Errorf(xerrors.InvalidArgument, nil, "page_id is required")
+	}
+	limit := req.Limit
+	if limit <= 0 {
+		limit = DefaultDeliveryLimit
+	}
+	deliveries, err := s.DeliveryStore.ListDeliveries(ctx, req.PageID, func(d *types.Delivery) bool {
+		return (req.SubscriptionID == 0 || d.SubscriptionID == req.SubscriptionID) &&
+			(req.Status == "" || d.Status == req.Status)
+	})
+	if err != nil {
+		return nil, err
+	}
+	if len(deliveries) > limit {
+		deliveries = deliveries[:limit]
+	}
+	return &types.ListDeliveriesResponse{Deliveries: deliveries}, nil
+}
+
+// getSubscription returns the subscription, which must belong to the page.
+func (s *EventHookService) getSubscription(ctx context.Context, pageID, id dot.IntID) (*types.Subscription, error) {
+	sub, err := s.SubscriptionStore.GetSubscription(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if sub.PageID != pageID {
+		return nil, xerrors.Errorf(xerrors.NotFound, nil, "subscription not found")
+	}
+	return sub, nil
+}
+
+func validateSubscription(rawURL string, eventTypes []flowexectypes.FlowEventType) error {
+	u, err := url.Parse(rawURL)
+	if err != nil || u.Host == "" {
+		return xerrors.Errorf(xerrors.InvalidArgument, err, "invalid url")
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return xerrors.Errorf(xerrors.InvalidArgument, nil, "unsupported scheme %q", u.Scheme)
+	}
+	for _, typ := range eventTypes {
+		if !validEventType(typ) {
+			return xerrors.Errorf(xerrors.InvalidArgument, nil, "unknown event type %q", typ)
+		}
+	}
+	return nil
+}
+
+func validEventType(typ flowexectypes.FlowEventType) bool {
+	for _, t := range flowexectypes.FlowEventTypes {
+		if t == typ {
+			return true
+		}
+	}
+	return false
+}
+
+func newSecret() string {
+	b := make([]byte, 24)
+	if _, err := rand.Read(b); err != nil {
+		panic(err)
+	}
+	return hex.EncodeToString(b)
+}
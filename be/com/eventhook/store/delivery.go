@@ -0,0 +1,109 @@
+package store
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"sort"
+	"sync"
+
+	"github.com/olvrng/rbot/be/com/eventhook/types"
+	"github.com/olvrng/rbot/be/com/workspace"
+	"github.com/olvrng/rbot/be/pkg/dot"
+)
+
+type DeliveryFile struct {
+	Deliveries []*types.Delivery `json:"deliveries"`
+}
+
+// DeliveryStore keeps the log of the deliveries.
+type DeliveryStore struct {
+	FilePath string
+	Data     *DeliveryFile
+
+	m sync.Mutex
+}
+
+func NewDeliveryStore(filePath string) (*DeliveryStore, error) {
+	s := &DeliveryStore{
+		FilePath: filePath,
+	}
+
+	_, err := os.Stat(filePath)
+	switch {
+	case err == nil: // load from storage
+		data, err := ioutil.ReadFile(filePath)
+		if err != nil {
+			return nil, err
+		}
+		err = json.Unmarshal(data, &s.Data)
+		return s, err
+
+	case os.IsNotExist(err): // try creating one
+		s.Data = &DeliveryFile{}
+		err = storeFile(filePath, s.Data)
+		return s, err
+
+	default:
+		return nil, err
+	}
+}
+
+// SaveDelivery creates the delivery, or replaces the delivery with the same id.
+func (s *DeliveryStore) SaveDelivery(ctx context.Context, delivery *types.Delivery) error {
+	s.m.Lock()
+	defer s.m.Unlock()
+
+	for i, item := range s.Data.Deliveries {
+		if item.ID == delivery.ID {
+			if err := workspace.CheckOwner(ctx, item.WorkspaceID); err != nil {
+				return err
+			}
+			delivery.WorkspaceID = item.WorkspaceID
+			s.Data.Deliveries[i] = delivery
+			return storeFile(s.FilePath, s.Data)
+		}
+	}
+	delivery.WorkspaceID = workspace.GetID(ctx)
+	s.Data.Deliveries = append(s.Data.Deliveries, delivery)
+	return storeFile(s.FilePath, s.Data)
+}
+
+// ListDeliveries returns the deliveries of the page which pass the filter,
+// newest first.
+func (s *DeliveryStore) ListDeliveries(ctx context.Context, pageID dot.IntID, filter func(*types.Delivery) bool) ([]*types.Delivery, error) {
+	s.m.Lock()
+	defer s.m.Unlock()
+
+	var result []*types.Delivery
+	for _, item := range s.Data.Deliveries {
+		if item.PageID == pageID && workspace.Match(ctx, item.WorkspaceID) && (filter == nil || filter(item)) {
+			result = append(result, item)
+		}
+	}
+	sort.SliceStable(result, func(i, j int) bool {
+		return result[i].CreatedAt > result[j].CreatedAt
+	})
+	return result, nil
+}
+
+// ListDueDeliveries returns the pending deliveries which should be attempted at
+// the given time, the earliest first. It lists the deliveries of all the
+// workspaces, for the dispatcher: each delivery must be attempted in the context
+// of its workspace.
+func (s *DeliveryStore) ListDueDeliveries(ctx context.Context, now dot.Timestamp) ([]*types.Delivery, error) {
+	s.m.Lock()
+	defer s.m.Unlock()
+
+	var result []*types.Delivery
+	for _, item := range s.Data.Deliveries {
+		if item.Status == types.DeliveryPending && !item.NextAttemptAt.After(now) {
+			result = append(result, item)
+		}
+	}
+	sort.SliceStable(result, func(i, j int) bool {
+		return result[i].NextAttemptAt < result[j].NextAttemptAt
+	})
+	return result, nil
+}
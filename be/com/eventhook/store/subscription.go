@@ -0,0 +1,133 @@
+package store
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"sort"
+	"sync"
+
+	"github.com/olvrng/rbot/be/com/eventhook/types"
+	"github.com/olvrng/rbot/be/com/workspace"
+	"github.com/olvrng/rbot/be/pkg/dot"
+	"github.com/olvrng/rbot/be/pkg/xerrors"
+)
+
+type SubscriptionFile struct {
+	Subscriptions []*types.Subscription `json:"subscriptions"`
+}
+
+type SubscriptionStore struct {
+	FilePath string
+	Data     *SubscriptionFile
+
+	m sync.Mutex
+}
+
+func NewSubscriptionStore(filePath string) (*SubscriptionStore, error) {
+	s := &SubscriptionStore{
+		FilePath: filePath,
+	}
+
+	_, err := os.Stat(filePath)
+	switch {
+	case err == nil: // load from storage
+		data, err := ioutil.ReadFile(filePath)
+		if err != nil {
+			return nil, err
+		}
+		err = json.Unmarshal(data, &s.Data)
+		return s, err
+
+	case os.IsNotExist(err): // try creating one
+		s.Data = &SubscriptionFile{}
+		err = storeFile(filePath, s.Data)
+		return s, err
+
+	default:
+		return nil, err
+	}
+}
+
+// SaveSubscription creates the subscription, or replaces the subscription with
+// the same id.
+func (s *SubscriptionStore) SaveSubscription(ctx context.Context, sub *types.Subscription) error {
+	s.m.Lock()
+	defer s.m.Unlock()
+
+	for i, item := range s.Data.Subscriptions {
+		if item.ID == sub.ID {
+			if err := workspace.CheckOwner(ctx, item.WorkspaceID); err != nil {
+				return err
+			}
+			sub.WorkspaceID = item.WorkspaceID
+			s.Data.Subscriptions[i] = sub
+			return storeFile(s.FilePath, s.Data)
+		}
+	}
+	sub.WorkspaceID = workspace.GetID(ctx)
+	s.Data.Subscriptions = append(s.Data.Subscriptions, sub)
+	return storeFile(s.FilePath, s.Data)
+}
+
+func (s *SubscriptionStore) GetSubscription(ctx context.Context, id dot.IntID) (*types.Subscription, error) {
+	s.m.Lock()
+	defer s.m.Unlock()
+
+	for _, item := range s.Data.Subscriptions {
+		if item.ID == id && workspace.Match(ctx, item.WorkspaceID) {
+			return item, nil
+		}
+	}
+	return nil, xerrors.Errorf(xerrors.NotFound, nil, "subscription not found")
+}
+
+func (s *SubscriptionStore) DeleteSubscription(ctx context.Context, id dot.IntID) (deleted int, _ error) {
+	s.m.Lock()
+	defer s.m.Unlock()
+
+	subs := s.Data.Subscriptions[:0]
+	for _, item := range s.Data.Subscriptions {
+		if item.ID == id && workspace.Match(ctx, item.WorkspaceID) {
+			deleted++
+			continue
+		}
+		subs = append(subs, item)
+	}
+	if deleted == 0 {
+		return 0, nil
+	}
+	s.Data.Subscriptions = subs
+	return deleted, storeFile(s.FilePath, s.Data)
+}
+
+// ListSubscriptions returns the subscriptions of the page, oldest first.
+func (s *SubscriptionStore) ListSubscriptions(ctx context.Context, pageID dot.IntID) ([]*types.Subscription, error) {
+	s.m.Lock()
+	defer s.m.Unlock()
+
+	var result []*types.Subscription
+	for _, item := range s.Data.Subscriptions {
+		if item.PageID == pageID && workspace.Match(ctx, item.WorkspaceID) {
+			result = append(result, item)
+		}
+	}
+	sort.SliceStable(result, func(i, j int) bool {
+		return result[i].CreatedAt < result[j].CreatedAt
+	})
+	return result, nil
+}
+
+// storeFile writes the data to the file. The store is kept in memory only when
+// the file path is empty.
+func storeFile(filePath string, data interface{}) error {
+	if filePath == "" {
+		return nil
+	}
+	out, err := json.MarshalIndent(data, "", "\t")
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(filePath, out, 0644)
+}
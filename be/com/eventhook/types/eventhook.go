@@ -0,0 +1,144 @@
+package types
+
+import (
+	flowexectypes "github.com/olvrng/rbot/be/com/flowexec/types"
+	"github.com/olvrng/rbot/be/pkg/dot"
+)
+
+// Subscription posts the events of a page, or of one flow of the page, to URL.
+// The payload is the flow event in JSON, signed with Secret:
+//
+//	X-Rbot-Signature: sha256=<hex of HMAC-SHA256(secret, body)>
+type Subscription struct {
+	ID          dot.IntID `json:"id"`
+	WorkspaceID dot.IntID `json:"workspace_id,omitempty"`
+	PageID      dot.IntID `json:"page_id"`
+	FlowID      dot.IntID `json:"flow_id,omitempty"` // all the flows of the page when 0
+	URL         string    `json:"url"`
+	Secret      string    `json:"secret"`
+
+	// EventTypes filters the events, all of them when empty.
+	EventTypes []flowexectypes.FlowEventType `json:"event_types,omitempty"`
+	Disabled   bool                          `json:"disabled,omitempty"`
+
+	CreatedAt dot.Timestamp `json:"created_at"`
+	UpdatedAt dot.Timestamp `json:"updated_at"`
+}
+
+// Match tells whether the event is delivered to the subscription.
+func (s *Subscription) Match(event *flowexectypes.FlowEvent) bool {
+	if s.Disabled || s.PageID != event.PageID {
+		return false
+	}
+	if s.FlowID != 0 && s.FlowID != event.FlowID {
+		return false
+	}
+	if len(s.EventTypes) == 0 {
+		return true
+	}
+	for _, typ := range s.EventTypes {
+		if typ == event.Type {
+			return true
+		}
+	}
+	return false
+}
+
+type DeliveryStatus string
+
+const (
+	DeliveryPending   DeliveryStatus = "pending"
+	DeliverySucceeded DeliveryStatus = "succeeded"
+	DeliveryFailed    DeliveryStatus = "failed"
+)
+
+// Delivery is the log of an event posted to a subscription. A pending delivery
+// is attempted again at NextAttemptAt, until it succeeds or fails too many
+// times.
+type Delivery struct {
+	ID             dot.IntID                   `json:"id"`
+	WorkspaceID    dot.IntID                   `json:"workspace_id,omitempty"`
+	SubscriptionID dot.IntID                   `json:"subscription_id"`
+	PageID         dot.IntID                   `json:"page_id"`
+	EventID        dot.IntID                   `json:"event_id"`
+	EventType      flowexectypes.FlowEventType `json:"event_type"`
+	URL            string                      `json:"url"`
+	Payload        string                      `json:"payload"`
+
+	Status        DeliveryStatus `json:"status"`
+	Attempts      int            `json:"attempts"`
+	StatusCode    int            `json:"status_code,omitempty"` // of the last attempt
+	Error         string         `json:"error,omitempty"`       // of the last attempt
+	NextAttemptAt dot.Timestamp  `json:"next_attempt_at,omitempty"`
+
+	CreatedAt dot.Timestamp `json:"created_at"`
+	UpdatedAt dot.Timestamp `json:"updated_at"`
+}
+
+// CreateSubscriptionRequest creates a subscription. A secret is generated when
+// it is empty.
+type CreateSubscriptionRequest struct {
+	// +validate:required
+	PageID dot.IntID `json:"page_id"`
+	FlowID dot.IntID `json:"flow_id"`
+	// +validate:required +validate:max-len=2048
+	URL        string                        `json:"url"`
+	Secret     string                        `json:"secret"`
+	EventTypes []flowexectypes.FlowEventType `json:"event_types"`
+	Disabled   bool                          `json:"disabled"`
+}
+
+// UpdateSubscriptionRequest replaces the fields of the subscription. The secret
+// is kept when it is empty.
+type UpdateSubscriptionRequest struct {
+	// +validate:required
+	ID dot.IntID `json:"id"`
+	// +validate:required
+	PageID dot.IntID `json:"page_id"`
+	FlowID dot.IntID `json:"flow_id"`
+	// +validate:max-len=2048
+	URL        string                        `json:"url"`
+	Secret     string                        `json:"secret"`
+	EventTypes []flowexectypes.FlowEventType `json:"event_types"`
+	Disabled   bool                          `json:"disabled"`
+}
+
+type SubscriptionResponse struct {
+	Subscription *Subscription `json:"subscription"`
+}
+
+type DeleteSubscriptionRequest struct {
+	// +validate:required
+	ID dot.IntID `json:"id"`
+	// +validate:required
+	PageID dot.IntID `json:"page_id"`
+}
+
+type DeleteSubscriptionResponse struct {
+	Deleted int `json:"deleted"`
+}
+
+type ListSubscriptionsRequest struct {
+	// +validate:required
+	PageID dot.IntID `json:"page_id"`
+}
+
+type ListSubscriptionsResponse struct {
+	Subscriptions []*Subscription `json:"subscriptions"`
+}
+
+// ListDeliveriesRequest lists the deliveries of a subscription, or of the page,
+// newest first.
+type ListDeliveriesRequest struct {
+	// +validate:required
+	PageID         dot.IntID `json:"page_id"`
+	SubscriptionID dot.IntID `json:"subscription_id"`
+	// +validate:enum
+	Status DeliveryStatus `json:"status"`
+	// +validate:min=1
+	Limit int `json:"limit"`
+}
+
+type ListDeliveriesResponse struct {
+	Deliveries []*Delivery `json:"deliveries"`
+}
@@ -0,0 +1,295 @@
+// +build !generator
+
+// Code generated by generator api. DO NOT EDIT.
+
+package eventhook
+
+import (
+	context "context"
+	fmt "fmt"
+	http "net/http"
+
+	eventhooktypes "github.com/olvrng/rbot/be/com/eventhook/types"
+	httprpc "github.com/olvrng/rbot/be/pkg/httprpc"
+)
+
+func init() {
+	httprpc.Register(NewServer)
+	httprpc.RegisterSchemas(
+		&httprpc.Schema{Name: "github.com/olvrng/rbot/be/com/eventhook/types.CreateSubscriptionRequest", Fields: []httprpc.Field{
+			{Name: "page_id", Number: 1, Kind: httprpc.KindInt},
+			{Name: "flow_id", Number: 2, Kind: httprpc.KindInt},
+			{Name: "url", Number: 3, Kind: httprpc.KindString},
+			{Name: "secret", Number: 4, Kind: httprpc.KindString},
+			{Name: "event_types", Number: 5, Kind: httprpc.KindString, Repeated: true},
+			{Name: "disabled", Number: 6, Kind: httprpc.KindBool},
+		}},
+		&httprpc.Schema{Name: "github.com/olvrng/rbot/be/com/eventhook/types.DeleteSubscriptionRequest", Fields: []httprpc.Field{
+			{Name: "id", Number: 1, Kind: httprpc.KindInt},
+			{Name: "page_id", Number: 2, Kind: httprpc.KindInt},
+		}},
+		&httprpc.Schema{Name: "github.com/olvrng/rbot/be/com/eventhook/types.DeleteSubscriptionResponse", Fields: []httprpc.Field{
+			{Name: "deleted", Number: 1, Kind: httprpc.KindInt},
+		}},
+		&httprpc.Schema{Name: "github.com/olvrng/rbot/be/com/eventhook/types.Delivery", Fields: []httprpc.Field{
+			{Name: "id", Number: 1, Kind: httprpc.KindInt},
+			{Name: "workspace_id", Number: 2, Kind: httprpc.KindInt},
+			{Name: "subscription_id", Number: 3, Kind: httprpc.KindInt},
+			{Name: "page_id", Number: 4, Kind: httprpc.KindInt},
+			{Name: "event_id", Number: 5, Kind: httprpc.KindInt},
+			{Name: "event_type", Number: 6, Kind: httprpc.KindString},
+			{Name: "url", Number: 7, Kind: httprpc.KindString},
+			{Name: "payload", Number: 8, Kind: httprpc.KindString},
+			{Name: "status", Number: 9, Kind: httprpc.KindString},
+			{Name: "attempts", Number: 10, Kind: httprpc.KindInt},
+			{Name: "status_code", Number: 11, Kind: httprpc.KindInt},
+			{Name: "error", Number: 12, Kind: httprpc.KindString},
+			{Name: "next_attempt_at", Number: 13, Kind: httprpc.KindInt},
+			{Name: "created_at", Number: 14, Kind: httprpc.KindInt},
+			{Name: "updated_at", Number: 15, Kind: httprpc.KindInt},
+		}},
+		&httprpc.Schema{Name: "github.com/olvrng/rbot/be/com/eventhook/types.ListDeliveriesRequest", Fields: []httprpc.Field{
+			{Name: "page_id", Number: 1, Kind: httprpc.KindInt},
+			{Name: "subscription_id", Number: 2, Kind: httprpc.KindInt},
+			{Name: "status", Number: 3, Kind: httprpc.KindString},
+			{Name: "limit", Number: 4, Kind: httprpc.KindInt},
+		}},
+		&httprpc.Schema{Name: "github.com/olvrng/rbot/be/com/eventhook/types.ListDeliveriesResponse", Fields: []httprpc.Field{
+			{Name: "deliveries", Number: 1, Kind: httprpc.KindMessage, Repeated: true, Message: "github.com/olvrng/rbot/be/com/eventhook/types.Delivery"},
+		}},
+		&httprpc.Schema{Name: "github.com/olvrng/rbot/be/com/eventhook/types.ListSubscriptionsRequest", Fields: []httprpc.Field{
+			{Name: "page_id", Number: 1, Kind: httprpc.KindInt},
+		}},
+		&httprpc.Schema{Name: "github.com/olvrng/rbot/be/com/eventhook/types.ListSubscriptionsResponse", Fields: []httprpc.Field{
+			{Name: "subscriptions", Number: 1, Kind: httprpc.KindMessage, Repeated: true, Message: "github.com/olvrng/rbot/be/com/eventhook/types.Subscription"},
+		}},
+		&httprpc.Schema{Name: "github.com/olvrng/rbot/be/com/eventhook/types.Subscription", Fields: []httprpc.Field{
+			{Name: "id", Number: 1, Kind: httprpc.KindInt},
+			{Name: "workspace_id", Number: 2, Kind: httprpc.KindInt},
+			{Name: "page_id", Number: 3, Kind: httprpc.KindInt},
+			{Name: "flow_id", Number: 4, Kind: httprpc.KindInt},
+			{Name: "url", Number: 5, Kind: httprpc.KindString},
+			{Name: "secret", Number: 6, Kind: httprpc.KindString},
+			{Name: "event_types", Number: 7, Kind: httprpc.KindString, Repeated: true},
+			{Name: "disabled", Number: 8, Kind: httprpc.KindBool},
+			{Name: "created_at", Number: 9, Kind: httprpc.KindInt},
+			{Name: "updated_at", Number: 10, Kind: httprpc.KindInt},
+		}},
+		&httprpc.Schema{Name: "github.com/olvrng/rbot/be/com/eventhook/types.SubscriptionResponse", Fields: []httprpc.Field{
+			{Name: "subscription", Number: 1, Kind: httprpc.KindMessage, Message: "github.com/olvrng/rbot/be/com/eventhook/types.Subscription"},
+		}},
+		&httprpc.Schema{Name: "github.com/olvrng/rbot/be/com/eventhook/types.UpdateSubscriptionRequest", Fields: []httprpc.Field{
+			{Name: "id", Number: 1, Kind: httprpc.KindInt},
+			{Name: "page_id", Number: 2, Kind: httprpc.KindInt},
+			{Name: "flow_id", Number: 3, Kind: httprpc.KindInt},
+			{Name: "url", Number: 4, Kind: httprpc.KindString},
+			{Name: "secret", Number: 5, Kind: httprpc.KindString},
+			{Name: "event_types", Number: 6, Kind: httprpc.KindString, Repeated: true},
+			{Name: "disabled", Number: 7, Kind: httprpc.KindBool},
+		}},
+	)
+}
+
+func NewServer(builder interface{}, hooks ...httprpc.HooksBuilder) (httprpc.Server, bool) {
+	switch builder := builder.(type) {
+	case func() EventHookService:
+		return NewEventHookServiceServer(builder, hooks...), true
+	case EventHookService:
+		fn := func() EventHookService { return builder }
+		return NewEventHookServiceServer(fn, hooks...), true
+	default:
+		return nil, false
+	}
+}
+
+type EventHookServiceServer struct {
+	hooks   httprpc.HooksBuilder
+	builder func() EventHookService
+}
+
+func NewEventHookServiceServer(builder func() EventHookService, hooks ...httprpc.HooksBuilder) httprpc.Server {
+	return &EventHookServiceServer{
+		hooks:   httprpc.ChainHooks(hooks...),
+		builder: builder,
+	}
+}
+
+const EventHookServicePathPrefix = "/api/eventhook/"
+
+const Path_EventHook_CreateSubscription = "/api/eventhook/CreateSubscription"
+const Path_EventHook_DeleteSubscription = "/api/eventhook/DeleteSubscription"
+const Path_EventHook_ListDeliveries = "/api/eventhook/ListDeliveries"
+const Path_EventHook_ListSubscriptions = "/api/eventhook/ListSubscriptions"
+const Path_EventHook_UpdateSubscription = "/api/eventhook/UpdateSubscription"
+
+func (s *EventHookServiceServer) PathPrefix() string {
+	return EventHookServicePathPrefix
+}
+
+func (s *EventHookServiceServer) WithHooks(hooks httprpc.HooksBuilder) httprpc.Server {
+	result := *s
+	result.hooks = httprpc.ChainHooks(s.hooks, hooks)
+	return &result
+}
+
+func (s *EventHookServiceServer) ServeHTTP(resp http.ResponseWriter, req *http.Request) {
+	hooks := httprpc.WrapHooks(s.hooks)
+	ctx, info := req.Context(), &httprpc.HookInfo{Route: req.URL.Path, HTTPRequest: req}
+	ctx, err := hooks.RequestReceived(ctx, *info)
+	if err != nil {
+		httprpc.WriteError(ctx, resp, hooks, *info, err)
+		return
+	}
+	reqMsg, exec, allowGET, err := s.parseRoute(req.URL.Path, hooks, info)
+	if err != nil {
+		httprpc.WriteError(ctx, resp, hooks, *info, err)
+		return
+	}
+	serve, err := httprpc.ParseRequestHeader(req, allowGET)
+	if err != nil {
+		httprpc.WriteError(ctx, resp, hooks, *info, err)
+		return
+	}
+	serve(ctx, resp, req, hooks, info, reqMsg, exec)
+}
+
+func (s *EventHookServiceServer) parseRoute(path string, hooks httprpc.Hooks, info *httprpc.HookInfo) (reqMsg httprpc.Message, _ httprpc.ExecFunc, allowGET bool, _ error) {
+	switch path {
+	case "/api/eventhook/CreateSubscription":
+		msg := &eventhooktypes.CreateSubscriptionRequest{}
+		fn := func(ctx context.Context) (newCtx context.Context, resp httprpc.Message, err error) {
+			inner := s.builder()
+			info.Request, info.Inner = msg, inner
+			newCtx, err = hooks.RequestRouted(ctx, *info)
+			if err != nil {
+				return
+			}
+			if err = msg.Validate(); err != nil {
+				return
+			}
+			resp, err = inner.CreateSubscription(newCtx, msg)
+			return
+		}
+		return msg, fn, false, nil
+	case "/api/eventhook/DeleteSubscription":
+		msg := &eventhooktypes.DeleteSubscriptionRequest{}
+		fn := func(ctx context.Context) (newCtx context.Context, resp httprpc.Message, err error) {
+			inner := s.builder()
+			info.Request, info.Inner = msg, inner
+			newCtx, err = hooks.RequestRouted(ctx, *info)
+			if err != nil {
+				return
+			}
+			if err = msg.Validate(); err != nil {
+				return
+			}
+			resp, err = inner.DeleteSubscription(newCtx, msg)
+			return
+		}
+		return msg, fn, false, nil
+	case "/api/eventhook/ListDeliveries":
+		msg := &eventhooktypes.ListDeliveriesRequest{}
+		fn := func(ctx context.Context) (newCtx context.Context, resp httprpc.Message, err error) {
+			inner := s.builder()
+			info.Request, info.Inner = msg, inner
+			newCtx, err = hooks.RequestRouted(ctx, *info)
+			if err != nil {
+				return
+			}
+			if err = msg.Validate(); err != nil {
+				return
+			}
+			resp, err = inner.ListDeliveries(newCtx, msg)
+			return
+		}
+		return msg, fn, true, nil
+	case "/api/eventhook/ListSubscriptions":
+		msg := &eventhooktypes.ListSubscriptionsRequest{}
+		fn := func(ctx context.Context) (newCtx context.Context, resp httprpc.Message, err error) {
+			inner := s.builder()
+			info.Request, info.Inner = msg, inner
+			newCtx, err = hooks.RequestRouted(ctx, *info)
+			if err != nil {
+				return
+			}
+			if err = msg.Validate(); err != nil {
+				return
+			}
+			resp, err = inner.ListSubscriptions(newCtx, msg)
+			return
+		}
+		return msg, fn, true, nil
+	case "/api/eventhook/UpdateSubscription":
+		msg := &eventhooktypes.UpdateSubscriptionRequest{}
+		fn := func(ctx context.Context) (newCtx context.Context, resp httprpc.Message, err error) {
+			inner := s.builder()
+			info.Request, info.Inner = msg, inner
+			newCtx, err = hooks.RequestRouted(ctx, *info)
+			if err != nil {
+				return
+			}
+			if err = msg.Validate(); err != nil {
+				return
+			}
+			resp, err = inner.UpdateSubscription(newCtx, msg)
+			return
+		}
+		return msg, fn, false, nil
+	default:
+		msg := fmt.Sprintf("no handler for path %q", path)
+		return nil, nil, false, httprpc.BadRouteError(msg, "POST", path)
+	}
+}
+
+// EventHookServiceClient calls EventHookService on the server at baseURL.
+type EventHookServiceClient struct {
+	client *httprpc.Client
+}
+
+var _ EventHookService = &EventHookServiceClient{}
+
+func NewEventHookServiceClient(baseURL string, httpClient *http.Client, hooks ...httprpc.ClientHooks) *EventHookServiceClient {
+	return &EventHookServiceClient{
+		client: httprpc.NewClient(baseURL, httpClient, hooks...),
+	}
+}
+
+func (c *EventHookServiceClient) CreateSubscription(ctx context.Context, req *eventhooktypes.CreateSubscriptionRequest) (*eventhooktypes.SubscriptionResponse, error) {
+	resp := &eventhooktypes.SubscriptionResponse{}
+	if err := c.client.Call(ctx, Path_EventHook_CreateSubscription, req, resp); err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+func (c *EventHookServiceClient) DeleteSubscription(ctx context.Context, req *eventhooktypes.DeleteSubscriptionRequest) (*eventhooktypes.DeleteSubscriptionResponse, error) {
+	resp := &eventhooktypes.DeleteSubscriptionResponse{}
+	if err := c.client.Call(ctx, Path_EventHook_DeleteSubscription, req, resp); err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+func (c *EventHookServiceClient) ListDeliveries(ctx context.Context, req *eventhooktypes.ListDeliveriesRequest) (*eventhooktypes.ListDeliveriesResponse, error) {
+	resp := &eventhooktypes.ListDeliveriesResponse{}
+	if err := c.client.Call(ctx, Path_EventHook_ListDeliveries, req, resp); err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+func (c *EventHookServiceClient) ListSubscriptions(ctx context.Context, req *eventhooktypes.ListSubscriptionsRequest) (*eventhooktypes.ListSubscriptionsResponse, error) {
+	resp := &eventhooktypes.ListSubscriptionsResponse{}
+	if err := c.client.Call(ctx, Path_EventHook_ListSubscriptions, req, resp); err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+func (c *EventHookServiceClient) UpdateSubscription(ctx context.Context, req *eventhooktypes.UpdateSubscriptionRequest) (*eventhooktypes.SubscriptionResponse, error) {
+	resp := &eventhooktypes.SubscriptionResponse{}
+	if err := c.client.Call(ctx, Path_EventHook_UpdateSubscription, req, resp); err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
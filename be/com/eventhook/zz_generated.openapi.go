@@ -0,0 +1,773 @@
+// +build !generator
+
+// Code generated by generator openapi. DO NOT EDIT.
+
+package eventhook
+
+import (
+	openapi "github.com/olvrng/rbot/be/pkg/openapi"
+)
+
+func init() {
+	openapi.Register(openAPIFragment)
+}
+
+const openAPIFragment = `{
+  "paths": {
+    "/api/eventhook/CreateSubscription": {
+      "post": {
+        "operationId": "EventHook_CreateSubscription",
+        "tags": [
+          "EventHookService"
+        ],
+        "requestBody": {
+          "required": true,
+          "content": {
+            "application/json": {
+              "schema": {
+                "$ref": "#/components/schemas/eventhooktypes.CreateSubscriptionRequest"
+              }
+            },
+            "application/protobuf": {
+              "schema": {
+                "type": "string",
+                "format": "binary"
+              }
+            },
+            "application/x-www-form-urlencoded": {
+              "schema": {
+                "$ref": "#/components/schemas/eventhooktypes.CreateSubscriptionRequest"
+              }
+            }
+          }
+        },
+        "responses": {
+          "200": {
+            "description": "OK",
+            "content": {
+              "application/json": {
+                "schema": {
+                  "$ref": "#/components/schemas/eventhooktypes.SubscriptionResponse"
+                }
+              },
+              "application/protobuf": {
+                "schema": {
+                  "type": "string",
+                  "format": "binary"
+                }
+              },
+              "application/x-www-form-urlencoded": {
+                "schema": {
+                  "$ref": "#/components/schemas/eventhooktypes.SubscriptionResponse"
+                }
+              }
+            }
+          },
+          "default": {
+            "description": "Error",
+            "content": {
+              "application/json": {
+                "schema": {
+                  "$ref": "#/components/schemas/Error"
+                }
+              }
+            }
+          }
+        }
+      }
+    },
+    "/api/eventhook/DeleteSubscription": {
+      "post": {
+        "operationId": "EventHook_DeleteSubscription",
+        "tags": [
+          "EventHookService"
+        ],
+        "requestBody": {
+          "required": true,
+          "content": {
+            "application/json": {
+              "schema": {
+                "$ref": "#/components/schemas/eventhooktypes.DeleteSubscriptionRequest"
+              }
+            },
+            "application/protobuf": {
+              "schema": {
+                "type": "string",
+                "format": "binary"
+              }
+            },
+            "application/x-www-form-urlencoded": {
+              "schema": {
+                "$ref": "#/components/schemas/eventhooktypes.DeleteSubscriptionRequest"
+              }
+            }
+          }
+        },
+        "responses": {
+          "200": {
+            "description": "OK",
+            "content": {
+              "application/json": {
+                "schema": {
+                  "$ref": "#/components/schemas/eventhooktypes.DeleteSubscriptionResponse"
+                }
+              },
+              "application/protobuf": {
+                "schema": {
+                  "type": "string",
+                  "format": "binary"
+                }
+              },
+              "application/x-www-form-urlencoded": {
+                "schema": {
+                  "$ref": "#/components/schemas/eventhooktypes.DeleteSubscriptionResponse"
+                }
+              }
+            }
+          },
+          "default": {
+            "description": "Error",
+            "content": {
+              "application/json": {
+                "schema": {
+                  "$ref": "#/components/schemas/Error"
+                }
+              }
+            }
+          }
+        }
+      }
+    },
+    "/api/eventhook/ListDeliveries": {
+      "get": {
+        "operationId": "EventHook_ListDeliveries_GET",
+        "tags": [
+          "EventHookService"
+        ],
+        "parameters": [
+          {
+            "name": "page_id",
+            "in": "query",
+            "schema": {
+              "type": "string",
+              "format": "int64"
+            }
+          },
+          {
+            "name": "subscription_id",
+            "in": "query",
+            "schema": {
+              "type": "string",
+              "format": "int64"
+            }
+          },
+          {
+            "name": "status",
+            "in": "query",
+            "schema": {
+              "$ref": "#/components/schemas/eventhooktypes.DeliveryStatus"
+            }
+          },
+          {
+            "name": "limit",
+            "in": "query",
+            "schema": {
+              "type": "integer"
+            }
+          }
+        ],
+        "responses": {
+          "200": {
+            "description": "OK",
+            "content": {
+              "application/json": {
+                "schema": {
+                  "$ref": "#/components/schemas/eventhooktypes.ListDeliveriesResponse"
+                }
+              },
+              "application/protobuf": {
+                "schema": {
+                  "type": "string",
+                  "format": "binary"
+                }
+              },
+              "application/x-www-form-urlencoded": {
+                "schema": {
+                  "$ref": "#/components/schemas/eventhooktypes.ListDeliveriesResponse"
+                }
+              }
+            }
+          },
+          "default": {
+            "description": "Error",
+            "content": {
+              "application/json": {
+                "schema": {
+                  "$ref": "#/components/schemas/Error"
+                }
+              }
+            }
+          }
+        }
+      },
+      "post": {
+        "operationId": "EventHook_ListDeliveries",
+        "tags": [
+          "EventHookService"
+        ],
+        "requestBody": {
+          "required": true,
+          "content": {
+            "application/json": {
+              "schema": {
+                "$ref": "#/components/schemas/eventhooktypes.ListDeliveriesRequest"
+              }
+            },
+            "application/protobuf": {
+              "schema": {
+                "type": "string",
+                "format": "binary"
+              }
+            },
+            "application/x-www-form-urlencoded": {
+              "schema": {
+                "$ref": "#/components/schemas/eventhooktypes.ListDeliveriesRequest"
+              }
+            }
+          }
+        },
+        "responses": {
+          "200": {
+            "description": "OK",
+            "content": {
+              "application/json": {
+                "schema": {
+                  "$ref": "#/components/schemas/eventhooktypes.ListDeliveriesResponse"
+                }
+              },
+              "application/protobuf": {
+                "schema": {
+                  "type": "string",
+                  "format": "binary"
+                }
+              },
+              "application/x-www-form-urlencoded": {
+                "schema": {
+                  "$ref": "#/components/schemas/eventhooktypes.ListDeliveriesResponse"
+                }
+              }
+            }
+          },
+          "default": {
+            "description": "Error",
+            "content": {
+              "application/json": {
+                "schema": {
+                  "$ref": "#/components/schemas/Error"
+                }
+              }
+            }
+          }
+        }
+      }
+    },
+    "/api/eventhook/ListSubscriptions": {
+      "get": {
+        "operationId": "EventHook_ListSubscriptions_GET",
+        "tags": [
+          "EventHookService"
+        ],
+        "parameters": [
+          {
+            "name": "page_id",
+            "in": "query",
+            "schema": {
+              "type": "string",
+              "format": "int64"
+            }
+          }
+        ],
+        "responses": {
+          "200": {
+            "description": "OK",
+            "content": {
+              "application/json": {
+                "schema": {
+                  "$ref": "#/components/schemas/eventhooktypes.ListSubscriptionsResponse"
+                }
+              },
+              "application/protobuf": {
+                "schema": {
+                  "type": "string",
+                  "format": "binary"
+                }
+              },
+              "application/x-www-form-urlencoded": {
+                "schema": {
+                  "$ref": "#/components/schemas/eventhooktypes.ListSubscriptionsResponse"
+                }
+              }
+            }
+          },
+          "default": {
+            "description": "Error",
+            "content": {
+              "application/json": {
+                "schema": {
+                  "$ref": "#/components/schemas/Error"
+                }
+              }
+            }
+          }
+        }
+      },
+      "post": {
+        "operationId": "EventHook_ListSubscriptions",
+        "tags": [
+          "EventHookService"
+        ],
+        "requestBody": {
+          "required": true,
+          "content": {
+            "application/json": {
+              "schema": {
+                "$ref": "#/components/schemas/eventhooktypes.ListSubscriptionsRequest"
+              }
+            },
+            "application/protobuf": {
+              "schema": {
+                "type": "string",
+                "format": "binary"
+              }
+            },
+            "application/x-www-form-urlencoded": {
+              "schema": {
+                "$ref": "#/components/schemas/eventhooktypes.ListSubscriptionsRequest"
+              }
+            }
+          }
+        },
+        "responses": {
+          "200": {
+            "description": "OK",
+            "content": {
+              "application/json": {
+                "schema": {
+                  "$ref": "#/components/schemas/eventhooktypes.ListSubscriptionsResponse"
+                }
+              },
+              "application/protobuf": {
+                "schema": {
+                  "type": "string",
+                  "format": "binary"
+                }
+              },
+              "application/x-www-form-urlencoded": {
+                "schema": {
+                  "$ref": "#/components/schemas/eventhooktypes.ListSubscriptionsResponse"
+                }
+              }
+            }
+          },
+          "default": {
+            "description": "Error",
+            "content": {
+              "application/json": {
+                "schema": {
+                  "$ref": "#/components/schemas/Error"
+                }
+              }
+            }
+          }
+        }
+      }
+    },
+    "/api/eventhook/UpdateSubscription": {
+      "post": {
+        "operationId": "EventHook_UpdateSubscription",
+        "tags": [
+          "EventHookService"
+        ],
+        "requestBody": {
+          "required": true,
+          "content": {
+            "application/json": {
+              "schema": {
+                "$ref": "#/components/schemas/eventhooktypes.UpdateSubscriptionRequest"
+              }
+            },
+            "application/protobuf": {
+              "schema": {
+                "type": "string",
+                "format": "binary"
+              }
+            },
+            "application/x-www-form-urlencoded": {
+              "schema": {
+                "$ref": "#/components/schemas/eventhooktypes.UpdateSubscriptionRequest"
+              }
+            }
+          }
+        },
+        "responses": {
+          "200": {
+            "description": "OK",
+            "content": {
+              "application/json": {
+                "schema": {
+                  "$ref": "#/components/schemas/eventhooktypes.SubscriptionResponse"
+                }
+              },
+              "application/protobuf": {
+                "schema": {
+                  "type": "string",
+                  "format": "binary"
+                }
+              },
+              "application/x-www-form-urlencoded": {
+                "schema": {
+                  "$ref": "#/components/schemas/eventhooktypes.SubscriptionResponse"
+                }
+              }
+            }
+          },
+          "default": {
+            "description": "Error",
+            "content": {
+              "application/json": {
+                "schema": {
+                  "$ref": "#/components/schemas/Error"
+                }
+              }
+            }
+          }
+        }
+      }
+    }
+  },
+  "components": {
+    "schemas": {
+      "eventhooktypes.CreateSubscriptionRequest": {
+        "type": "object",
+        "properties": {
+          "disabled": {
+            "type": "boolean"
+          },
+          "event_types": {
+            "type": "array",
+            "items": {
+              "$ref": "#/components/schemas/flowexectypes.FlowEventType"
+            }
+          },
+          "flow_id": {
+            "type": "string",
+            "format": "int64"
+          },
+          "page_id": {
+            "type": "string",
+            "format": "int64"
+          },
+          "secret": {
+            "type": "string"
+          },
+          "url": {
+            "type": "string"
+          }
+        },
+        "required": [
+          "page_id",
+          "flow_id",
+          "url",
+          "secret",
+          "event_types",
+          "disabled"
+        ]
+      },
+      "eventhooktypes.DeleteSubscriptionRequest": {
+        "type": "object",
+        "properties": {
+          "id": {
+            "type": "string",
+            "format": "int64"
+          },
+          "page_id": {
+            "type": "string",
+            "format": "int64"
+          }
+        },
+        "required": [
+          "id",
+          "page_id"
+        ]
+      },
+      "eventhooktypes.DeleteSubscriptionResponse": {
+        "type": "object",
+        "properties": {
+          "deleted": {
+            "type": "integer"
+          }
+        },
+        "required": [
+          "deleted"
+        ]
+      },
+      "eventhooktypes.Delivery": {
+        "type": "object",
+        "properties": {
+          "attempts": {
+            "type": "integer"
+          },
+          "created_at": {
+            "type": "string",
+            "description": "milliseconds from 1970"
+          },
+          "error": {
+            "type": "string"
+          },
+          "event_id": {
+            "type": "string",
+            "format": "int64"
+          },
+          "event_type": {
+            "$ref": "#/components/schemas/flowexectypes.FlowEventType"
+          },
+          "id": {
+            "type": "string",
+            "format": "int64"
+          },
+          "next_attempt_at": {
+            "type": "string",
+            "description": "milliseconds from 1970"
+          },
+          "page_id": {
+            "type": "string",
+            "format": "int64"
+          },
+          "payload": {
+            "type": "string"
+          },
+          "status": {
+            "$ref": "#/components/schemas/eventhooktypes.DeliveryStatus"
+          },
+          "status_code": {
+            "type": "integer"
+          },
+          "subscription_id": {
+            "type": "string",
+            "format": "int64"
+          },
+          "updated_at": {
+            "type": "string",
+            "description": "milliseconds from 1970"
+          },
+          "url": {
+            "type": "string"
+          },
+          "workspace_id": {
+            "type": "string",
+            "format": "int64"
+          }
+        },
+        "required": [
+          "id",
+          "subscription_id",
+          "page_id",
+          "event_id",
+          "event_type",
+          "url",
+          "payload",
+          "status",
+          "attempts",
+          "created_at",
+          "updated_at"
+        ]
+      },
+      "eventhooktypes.DeliveryStatus": {
+        "type": "string",
+        "enum": [
+          "failed",
+          "pending",
+          "succeeded"
+        ]
+      },
+      "eventhooktypes.ListDeliveriesRequest": {
+        "type": "object",
+        "properties": {
+          "limit": {
+            "type": "integer"
+          },
+          "page_id": {
+            "type": "string",
+            "format": "int64"
+          },
+          "status": {
+            "$ref": "#/components/schemas/eventhooktypes.DeliveryStatus"
+          },
+          "subscription_id": {
+            "type": "string",
+            "format": "int64"
+          }
+        },
+        "required": [
+          "page_id",
+          "subscription_id",
+          "status",
+          "limit"
+        ]
+      },
+      "eventhooktypes.ListDeliveriesResponse": {
+        "type": "object",
+        "properties": {
+          "deliveries": {
+            "type": "array",
+            "items": {
+              "$ref": "#/components/schemas/eventhooktypes.Delivery"
+            }
+          }
+        },
+        "required": [
+          "deliveries"
+        ]
+      },
+      "eventhooktypes.ListSubscriptionsRequest": {
+        "type": "object",
+        "properties": {
+          "page_id": {
+            "type": "string",
+            "format": "int64"
+          }
+        },
+        "required": [
+          "page_id"
+        ]
+      },
+      "eventhooktypes.ListSubscriptionsResponse": {
+        "type": "object",
+        "properties": {
+          "subscriptions": {
+            "type": "array",
+            "items": {
+              "$ref": "#/components/schemas/eventhooktypes.Subscription"
+            }
+          }
+        },
+        "required": [
+          "subscriptions"
+        ]
+      },
+      "eventhooktypes.Subscription": {
+        "type": "object",
+        "properties": {
+          "created_at": {
+            "type": "string",
+            "description": "milliseconds from 1970"
+          },
+          "disabled": {
+            "type": "boolean"
+          },
+          "event_types": {
+            "type": "array",
+            "items": {
+              "$ref": "#/components/schemas/flowexectypes.FlowEventType"
+            }
+          },
+          "flow_id": {
+            "type": "string",
+            "format": "int64"
+          },
+          "id": {
+            "type": "string",
+            "format": "int64"
+          },
+          "page_id": {
+            "type": "string",
+            "format": "int64"
+          },
+          "secret": {
+            "type": "string"
+          },
+          "updated_at": {
+            "type": "string",
+            "description": "milliseconds from 1970"
+          },
+          "url": {
+            "type": "string"
+          },
+          "workspace_id": {
+            "type": "string",
+            "format": "int64"
+          }
+        },
+        "required": [
+          "id",
+          "page_id",
+          "url",
+          "secret",
+          "created_at",
+          "updated_at"
+        ]
+      },
+      "eventhooktypes.SubscriptionResponse": {
+        "type": "object",
+        "properties": {
+          "subscription": {
+            "$ref": "#/components/schemas/eventhooktypes.Subscription"
+          }
+        }
+      },
+      "eventhooktypes.UpdateSubscriptionRequest": {
+        "type": "object",
+        "properties": {
+          "disabled": {
+            "type": "boolean"
+          },
+          "event_types": {
+            "type": "array",
+            "items": {
+              "$ref": "#/components/schemas/flowexectypes.FlowEventType"
+            }
+          },
+          "flow_id": {
+            "type": "string",
+            "format": "int64"
+          },
+          "id": {
+            "type": "string",
+            "format": "int64"
+          },
+          "page_id": {
+            "type": "string",
+            "format": "int64"
+          },
+          "secret": {
+            "type": "string"
+          },
+          "url": {
+            "type": "string"
+          }
+        },
+        "required": [
+          "id",
+          "page_id",
+          "flow_id",
+          "url",
+          "secret",
+          "event_types",
+          "disabled"
+        ]
+      },
+      "flowexectypes.FlowEventType": {
+        "type": "string",
+        "enum": [
+          "conversation.started",
+          "flow.completed",
+          "handoff.requested",
+          "message.failed",
+          "message.sent",
+          "node.entered",
+          "review.submitted"
+        ]
+      }
+    }
+  }
+}`
@@ -13,11 +13,18 @@ type EditorService interface {
 	CreateFlow(ctx context.Context, req *types.CreateFlowRequest) (*types.CreateFlowResponse, error)
 
 	UpdateFlow(ctx context.Context, req *types.CreateFlowRequest) (*types.CreateFlowResponse, error)
+
+	AnalyzeFlow(ctx context.Context, req *types.AnalyzeFlowRequest) (*types.AnalyzeFlowResponse, error)
 }
 
 // +api:path=/api/flow/def/query
 type QueryService interface {
+	// +api:get
 	GetFlowByID(ctx context.Context, req *types.GetFlowByIDRequest) (*types.FlowResponse, error)
 
+	// +api:get
 	GetFlowByParam(ctx context.Context, req *types.GetFlowByParamRequest) (*types.FlowResponse, error)
+
+	// +api:get
+	ListFlows(ctx context.Context, req *types.ListFlowsRequest) (*types.ListFlowsResponse, error)
 }
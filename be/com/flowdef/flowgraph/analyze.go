@@ -0,0 +1,212 @@
+package flowgraph
+
+import (
+	"github.com/olvrng/rbot/be/com/flowdef/types"
+	"github.com/olvrng/rbot/be/pkg/dot"
+)
+
+// Analyze builds the graph of the flow and reports its problems.
+func Analyze(flow *types.Flow) *types.FlowReport {
+	return Build(flow).Analyze()
+}
+
+func (g *Graph) Analyze() *types.FlowReport {
+	report := &types.FlowReport{
+		NodeCount:   len(g.Nodes),
+		LinkCount:   g.LinkCount(),
+		Cycles:      g.Cycles(),
+		DeadEnds:    g.DeadEnds(),
+		Unreachable: g.Unreachable(),
+		BrokenLinks: g.BrokenLinks,
+		LongestPath: g.LongestPath(),
+	}
+	if report.Cycles == nil {
+		report.Cycles = []*types.FlowCycle{}
+	}
+	if report.BrokenLinks == nil {
+		report.BrokenLinks = []*types.BrokenLink{}
+	}
+	return report
+}
+
+// DeadEnds returns the nodes which expect to continue but have no link. Only
+// send_message and handoff nodes may end the conversation, and goto_flow nodes
+// continue in another flow.
+func (g *Graph) DeadEnds() []dot.IntID {
+	result := []dot.IntID{}
+	for _, node := range g.Nodes {
+		if len(g.Links[node.ID]) != 0 {
+			continue
+		}
+		switch node.Payload.Type() {
+		case types.NodeSendMessage, types.NodeHandoff, types.NodeGotoFlow:
+		default:
+			result = append(result, node.ID)
+		}
+	}
+	return result
+}
+
+// Unreachable returns the nodes which can not be reached from the roots.
+func (g *Graph) Unreachable() []dot.IntID {
+	visited := map[dot.IntID]bool{}
+	queue := append([]dot.IntID(nil), g.Roots...)
+	for _, id := range queue {
+		visited[id] = true
+	}
+	for len(queue) != 0 {
+		id := queue[0]
+		queue = queue[1:]
+		for _, next := range g.successors(id) {
+			if !visited[next] {
+				visited[next] = true
+				queue = append(queue, next)
+			}
+		}
+	}
+
+	result := []dot.IntID{}
+	for _, node := range g.Nodes {
+		if !visited[node.ID] {
+			result = append(result, node.ID)
+		}
+	}
+	return result
+}
+
+// Cycles returns the strongly connected components which loop: with more than
+// one node, or with a node linking to itself. The nodes of each cycle are in
+// the order of the flow.
+func (g *Graph) Cycles() []*types.FlowCycle {
+	// Tarjan's algorithm
+	var (
+		index   = map[dot.IntID]int{}
+		lowlink = map[dot.IntID]int{}
+		onStack = map[dot.IntID]bool{}
+		stack   []dot.IntID
+		counter int
+		comps   [][]dot.IntID
+	)
+	var connect func(id dot.IntID)
+	connect = func(id dot.IntID) {
+		counter++
+		index[id], lowlink[id] = counter, counter
+		stack = append(stack, id)
+		onStack[id] = true
+		for _, next := range g.successors(id) {
+			switch {
+			case index[next] == 0:
+				connect(next)
+				lowlink[id] = min(lowlink[id], lowlink[next])
+			case onStack[next]:
+				lowlink[id] = min(lowlink[id], index[next])
+			}
+		}
+		if lowlink[id] != index[id] {
+			return
+		}
+		var comp []dot.IntID
+		for {
+			top := stack[len(stack)-1]
+			stack = stack[:len(stack)-1]
+			onStack[top] = false
+			comp = append(comp, top)
+			if top == id {
+				break
+			}
+		}
+		comps = append(comps, comp)
+	}
+	for _, node := range g.Nodes {
+		if index[node.ID] == 0 {
+			connect(node.ID)
+		}
+	}
+
+	var result []*types.FlowCycle
+	for _, node := range g.Nodes {
+		for _, comp := range comps {
+			if comp[len(comp)-1] != node.ID || !g.loops(comp) {
+				continue
+			}
+			cycle := &types.FlowCycle{}
+			members := map[dot.IntID]bool{}
+			for _, id := range comp {
+				members[id] = true
+			}
+			for _, n := range g.Nodes {
+				if members[n.ID] {
+					cycle.NodeIDs = append(cycle.NodeIDs, n.ID)
+					cycle.Waiting = cycle.Waiting || n.Payload.Waits()
+				}
+			}
+			result = append(result, cycle)
+		}
+	}
+	return result
+}
+
+func (g *Graph) loops(comp []dot.IntID) bool {
+	if len(comp) > 1 {
+		return true
+	}
+	for _, next := range g.successors(comp[0]) {
+		if next == comp[0] {
+			return true
+		}
+	}
+	return false
+}
+
+// LongestPath returns the longest path without loop from a root. The links
+// which close a loop, as found by a depth-first search from the roots, are
+// ignored.
+func (g *Graph) LongestPath() []dot.IntID {
+	const (
+		white = iota
+		gray
+		black
+	)
+	var (
+		color  = map[dot.IntID]int{}
+		length = map[dot.IntID]int{}
+		next   = map[dot.IntID]dot.IntID{}
+	)
+	var visit func(id dot.IntID)
+	visit = func(id dot.IntID) {
+		color[id] = gray
+		length[id] = 1
+		for _, succ := range g.successors(id) {
+			if color[succ] == white {
+				visit(succ)
+			}
+			if color[succ] == black && length[succ]+1 > length[id] {
+				length[id] = length[succ] + 1
+				next[id] = succ
+			}
+		}
+		color[id] = black
+	}
+
+	var start dot.IntID
+	for _, root := range g.Roots {
+		if color[root] == white {
+			visit(root)
+		}
+		if length[root] > length[start] {
+			start = root
+		}
+	}
+	path := []dot.IntID{}
+	for id := start; id != 0; id = next[id] {
+		path = append(path, id)
+	}
+	return path
+}
+
+func min(a, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
@@ -0,0 +1,117 @@
+package flowgraph
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/olvrng/rbot/be/com/flowdef/types"
+	"github.com/olvrng/rbot/be/pkg/dot"
+)
+
+const flowJSON = `{
+	"id": "1",
+	"nodes": [
+		{"id": "1", "payload": {"type": "trigger:received_message", "next_id": "2"}},
+		{"id": "2", "payload": {"type": "action:capture_input", "template": "What is your name?",
+			"variable": "name", "next_id": "3"}},
+		{"id": "3", "payload": {"type": "action:condition",
+			"rules": [{"variable": "name", "op": "eq", "value": "Bob", "next_id": "4"}], "next_id": "5"}},
+		{"id": "4", "payload": {"type": "action:set_variable",
+			"assignments": [{"variable": "name", "expr": "Ann"}], "next_id": "3"}},
+		{"id": "5", "payload": {"type": "action:send_message", "template": "Hello \"{{.name}}\"!",
+			"quick_replies": [{"text": "Again", "next_id": "2"}], "next_id": "99"}},
+		{"id": "6", "payload": {"type": "action:wait", "delay": "1h"}}
+	]
+}`
+
+func loadFlow(t *testing.T, s string) *types.Flow {
+	var flow types.Flow
+	require.NoError(t, json.Unmarshal([]byte(s), &flow))
+	return &flow
+}
+
+func TestAnalyze(t *testing.T) {
+	report := Analyze(loadFlow(t, flowJSON))
+	require.Equal(t, 6, report.NodeCount)
+	require.Equal(t, 6, report.LinkCount)
+	require.Equal(t, []*types.FlowCycle{
+		{NodeIDs: []dot.IntID{2, 3, 4, 5}, Waiting: true},
+	}, report.Cycles)
+	require.Equal(t, []dot.IntID{6}, report.DeadEnds)
+	require.Equal(t, []dot.IntID{6}, report.Unreachable)
+	require.Equal(t, []*types.BrokenLink{
+		{NodeID: 5, Link: &types.Link{NextID: 99}},
+	}, report.BrokenLinks)
+	require.Equal(t, []dot.IntID{1, 2, 3, 4}, report.LongestPath)
+	require.True(t, report.HasProblems())
+}
+
+func TestCycles(t *testing.T) {
+	tests := []struct {
+		name  string
+		nodes string
+		want  []*types.FlowCycle
+	}{
+		{
+			name: "no cycle",
+			nodes: `[
+				{"id": "1", "payload": {"type": "trigger:received_message", "next_id": "2"}},
+				{"id": "2", "payload": {"type": "action:send_message", "template": "Hi"}}
+			]`,
+			want: []*types.FlowCycle{},
+		},
+		{
+			name: "self loop",
+			nodes: `[
+				{"id": "1", "payload": {"type": "action:set_variable", "next_id": "1"}}
+			]`,
+			want: []*types.FlowCycle{{NodeIDs: []dot.IntID{1}}},
+		},
+		{
+			name: "two cycles",
+			nodes: `[
+				{"id": "1", "payload": {"type": "action:condition", "rules": [{"variable": "x", "op": "empty", "next_id": "2"}], "next_id": "3"}},
+				{"id": "2", "payload": {"type": "action:set_variable", "next_id": "1"}},
+				{"id": "3", "payload": {"type": "action:wait", "delay": "1d", "next_id": "4"}},
+				{"id": "4", "payload": {"type": "action:send_message", "template": "Hi", "next_id": "3"}}
+			]`,
+			want: []*types.FlowCycle{
+				{NodeIDs: []dot.IntID{1, 2}},
+				{NodeIDs: []dot.IntID{3, 4}, Waiting: true},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			flow := loadFlow(t, `{"id": "1", "nodes": `+tt.nodes+`}`)
+			require.Equal(t, tt.want, Analyze(flow).Cycles)
+		})
+	}
+}
+
+func TestRender(t *testing.T) {
+	g := Build(loadFlow(t, flowJSON))
+
+	out, err := g.Render(types.GraphDOT)
+	require.NoError(t, err)
+	require.Contains(t, out, `digraph "flow 1" {`)
+	require.Contains(t, out, `n1 [label="1: trigger:received_message", shape=ellipse];`)
+	require.Contains(t, out, `n5 [label="5: action:send_message\nHello \"{{.name}}\"!"];`)
+	require.Contains(t, out, `n3 -> n4 [label="name eq Bob"];`)
+	require.Contains(t, out, `n2 -> n3;`)
+	require.Contains(t, out, `n5 -> n99 [style=dashed, color=red];`)
+
+	out, err = g.Render(types.GraphMermaid)
+	require.NoError(t, err)
+	require.Contains(t, out, "flowchart LR\n")
+	require.Contains(t, out, `n1(["1: trigger:received_message"])`)
+	require.Contains(t, out, `n5["5: action:send_message<br/>Hello #quot;{{.name}}#quot;!"]`)
+	require.Contains(t, out, `n3 -->|"else"| n5`)
+	require.Contains(t, out, `n5 -.-> n99`)
+	require.Contains(t, out, `n99["99 (missing)"]:::missing`)
+
+	_, err = g.Render("svg")
+	require.EqualError(t, err, `unknown graph format "svg"`)
+}
@@ -0,0 +1,98 @@
+// Package flowgraph builds the graph of a flow from the links of its nodes, and
+// analyzes it: cycles, dead ends, unreachable nodes and the longest path. The
+// graph can be rendered to Graphviz DOT and Mermaid.
+package flowgraph
+
+import (
+	"github.com/olvrng/rbot/be/com/flowdef/types"
+	"github.com/olvrng/rbot/be/pkg/dot"
+)
+
+type Graph struct {
+	Flow *types.Flow
+
+	// Nodes are in the order of the flow.
+	Nodes []*types.Node
+
+	// Links are the outgoing links of the nodes, to existing nodes only.
+	Links map[dot.IntID][]*types.Link
+
+	// BrokenLinks link to nodes which do not exist.
+	BrokenLinks []*types.BrokenLink
+
+	// Roots are where the conversation may start: the triggers, the keyword
+	// entries and the fallback node.
+	Roots []dot.IntID
+
+	index map[dot.IntID]*types.Node
+}
+
+func Build(flow *types.Flow) *Graph {
+	g := &Graph{
+		Flow:  flow,
+		Links: map[dot.IntID][]*types.Link{},
+		index: map[dot.IntID]*types.Node{},
+	}
+	for _, node := range flow.Nodes {
+		if node.Payload == nil {
+			continue
+		}
+		g.Nodes = append(g.Nodes, node)
+		g.index[node.ID] = node
+	}
+
+	for _, node := range g.Nodes {
+		for _, link := range node.Payload.Links() {
+			if g.index[link.NextID] == nil {
+				g.BrokenLinks = append(g.BrokenLinks, &types.BrokenLink{NodeID: node.ID, Link: link})
+				continue
+			}
+			g.Links[node.ID] = append(g.Links[node.ID], link)
+		}
+	}
+
+	added := map[dot.IntID]bool{}
+	addRoot := func(id dot.IntID) {
+		if g.index[id] != nil && !added[id] {
+			added[id] = true
+			g.Roots = append(g.Roots, id)
+		}
+	}
+	for _, node := range g.Nodes {
+		if node.Payload.Type().IsTrigger() {
+			addRoot(node.ID)
+		}
+	}
+	for _, entry := range flow.Keywords {
+		addRoot(entry.NextID)
+	}
+	addRoot(flow.FallbackNodeID)
+	return g
+}
+
+func (g *Graph) Node(id dot.IntID) *types.Node {
+	return g.index[id]
+}
+
+// LinkCount counts the links to existing nodes.
+func (g *Graph) LinkCount() int {
+	count := 0
+	for _, links := range g.Links {
+		count += len(links)
+	}
+	return count
+}
+
+// successors returns the distinct nodes which the node links to, in the order
+// of the links.
+func (g *Graph) successors(id dot.IntID) []dot.IntID {
+	var result []dot.IntID
+	seen := map[dot.IntID]bool{}
+	for _, link := range g.Links[id] {
+		if !seen[link.NextID] {
+			seen[link.NextID] = true
+			result = append(result, link.NextID)
+		}
+	}
+	return result
+}
@@ -0,0 +1,180 @@
+package flowgraph
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+
+	"github.com/olvrng/rbot/be/com/flowdef/types"
+	"github.com/olvrng/rbot/be/pkg/dot"
+)
+
+// MaxSummaryLength truncates the text shown in the nodes of the rendered graph.
+const MaxSummaryLength = 40
+
+// Render renders the graph in the given format.
+func (g *Graph) Render(format types.GraphFormat) (string, error) {
+	switch format {
+	case types.GraphDOT:
+		return g.DOT(), nil
+	case types.GraphMermaid:
+		return g.Mermaid(), nil
+	default:
+		return "", fmt.Errorf("unknown graph format %q", format)
+	}
+}
+
+// DOT renders the graph to Graphviz DOT. Triggers are ellipses, and the broken
+// links point to red "missing" nodes.
+//
+//	dot -Tsvg -o flow.svg flow.dot
+func (g *Graph) DOT() string {
+	var b bytes.Buffer
+	fmt.Fprintf(&b, "digraph %v {\n", dotQuote(fmt.Sprintf("flow %v", g.Flow.ID)))
+	b.WriteString("\trankdir=LR;\n")
+	b.WriteString("\tnode [shape=box];\n")
+	for _, node := range g.Nodes {
+		attrs := "label=" + dotQuote(strings.Join(nodeLines(node), "\n"))
+		if node.Payload.Type().IsTrigger() {
+			attrs += ", shape=ellipse"
+		}
+		fmt.Fprintf(&b, "\tn%v [%v];\n", node.ID, attrs)
+	}
+	for _, id := range g.missing() {
+		fmt.Fprintf(&b, "\tn%v [label=%v, color=red];\n", id, dotQuote(fmt.Sprintf("%v\n(missing)", id)))
+	}
+	for _, node := range g.Nodes {
+		for _, link := range g.Links[node.ID] {
+			fmt.Fprintf(&b, "\tn%v -> n%v%v;\n", node.ID, link.NextID, dotLabel(link.Label, ""))
+		}
+	}
+	for _, broken := range g.BrokenLinks {
+		fmt.Fprintf(&b, "\tn%v -> n%v%v;\n", broken.NodeID, broken.Link.NextID, dotLabel(broken.Link.Label, "style=dashed, color=red"))
+	}
+	b.WriteString("}\n")
+	return b.String()
+}
+
+// Mermaid renders the graph to a Mermaid flowchart. Triggers are stadiums, and
+// the broken links point to "missing" nodes.
+func (g *Graph) Mermaid() string {
+	var b bytes.Buffer
+	b.WriteString("flowchart LR\n")
+	for _, node := range g.Nodes {
+		label := mermaidQuote(strings.Join(nodeLines(node), "<br/>"))
+		if node.Payload.Type().IsTrigger() {
+			fmt.Fprintf(&b, "\tn%v([%v])\n", node.ID, label)
+		} else {
+			fmt.Fprintf(&b, "\tn%v[%v]\n", node.ID, label)
+		}
+	}
+	for _, id := range g.missing() {
+		fmt.Fprintf(&b, "\tn%v[%v]:::missing\n", id, mermaidQuote(fmt.Sprintf("%v (missing)", id)))
+	}
+	for _, node := range g.Nodes {
+		for _, link := range g.Links[node.ID] {
+			fmt.Fprintf(&b, "\tn%v -->%v n%v\n", node.ID, mermaidLabel(link.Label), link.NextID)
+		}
+	}
+	for _, broken := range g.BrokenLinks {
+		fmt.Fprintf(&b, "\tn%v -.->%v n%v\n", broken.NodeID, mermaidLabel(broken.Link.Label), broken.Link.NextID)
+	}
+	if len(g.BrokenLinks) != 0 {
+		b.WriteString("\tclassDef missing stroke:#f00\n")
+	}
+	return b.String()
+}
+
+// missing returns the distinct node ids of the broken links.
+func (g *Graph) missing() []dot.IntID {
+	var result []dot.IntID
+	seen := map[dot.IntID]bool{}
+	for _, broken := range g.BrokenLinks {
+		if id := broken.Link.NextID; !seen[id] {
+			seen[id] = true
+			result = append(result, id)
+		}
+	}
+	return result
+}
+
+// nodeLines returns the id and type of the node, then a summary of what it
+// does, such as the template of the message.
+func nodeLines(node *types.Node) []string {
+	lines := []string{fmt.Sprintf("%v: %v", node.ID, node.Payload.Type())}
+	if summary := nodeSummary(node.Payload); summary != "" {
+		lines = append(lines, truncate(summary, MaxSummaryLength))
+	}
+	return lines
+}
+
+func nodeSummary(p *types.NodePayload) string {
+	switch data := p.Data.(type) {
+	case *types.SendMessageNodeData:
+		return data.Template
+	case *types.CaptureInputNodeData:
+		return data.Template
+	case *types.AskRatingNodeData:
+		return data.Template
+	case *types.HandoffNodeData:
+		return data.Template
+	case *types.WaitNodeData:
+		return data.Delay.String()
+	case *types.HTTPRequestNodeData:
+		method := data.Method
+		if method == "" {
+			method = "GET"
+		}
+		return method + " " + data.URL
+	case *types.GotoFlowNodeData:
+		return fmt.Sprintf("flow %v, node %v", data.FlowID, data.NodeID)
+	case *types.SetVariableNodeData:
+		names := make([]string, 0, len(data.Assignments))
+		for _, a := range data.Assignments {
+			names = append(names, a.Variable)
+		}
+		return strings.Join(names, ", ")
+	default:
+		return ""
+	}
+}
+
+func truncate(s string, n int) string {
+	s = strings.Join(strings.Fields(s), " ")
+	runes := []rune(s)
+	if len(runes) <= n {
+		return s
+	}
+	return string(runes[:n-1]) + "…"
+}
+
+func dotQuote(s string) string {
+	s = strings.ReplaceAll(s, `\`, `\\`)
+	s = strings.ReplaceAll(s, `"`, `\"`)
+	s = strings.ReplaceAll(s, "\n", `\n`)
+	return `"` + s + `"`
+}
+
+func dotLabel(label, attrs string) string {
+	if label != "" {
+		if attrs != "" {
+			attrs += ", "
+		}
+		attrs += "label=" + dotQuote(label)
+	}
+	if attrs == "" {
+		return ""
+	}
+	return " [" + attrs + "]"
+}
+
+func mermaidQuote(s string) string {
+	return `"` + strings.ReplaceAll(s, `"`, "#quot;") + `"`
+}
+
+func mermaidLabel(label string) string {
+	if label == "" {
+		return ""
+	}
+	return "|" + mermaidQuote(label) + "|"
+}
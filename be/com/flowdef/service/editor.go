@@ -4,33 +4,99 @@ import (
 	"context"
 
 	"github.com/olvrng/rbot/be/com/flowdef"
+	"github.com/olvrng/rbot/be/com/flowdef/flowgraph"
 	"github.com/olvrng/rbot/be/com/flowdef/store"
 	"github.com/olvrng/rbot/be/com/flowdef/types"
+	"github.com/olvrng/rbot/be/pkg/dot"
 	"github.com/olvrng/rbot/be/pkg/xerrors"
 )
 
 var _ flowdef.EditorService = (*FlowEditorService)(nil)
 
+// PageBinder binds the pages of the flows to the workspace of the context, see
+// the workspace store. It fails when a page belongs to another workspace.
+type PageBinder interface {
+	BindPages(ctx context.Context, pageIDs []dot.IntID) error
+}
+
 type FlowEditorService struct {
 	Store store.FlowStore
+	Pages PageBinder
 }
 
-func NewFlowEditorService(flowStore store.FlowStore) *FlowEditorService {
+func NewFlowEditorService(flowStore store.FlowStore, pages PageBinder) *FlowEditorService {
 	s := &FlowEditorService{
 		Store: flowStore,
+		Pages: pages,
 	}
 	return s
 }
 
 func (s *FlowEditorService) CreateFlow(ctx context.Context, req *types.CreateFlowRequest) (*types.CreateFlowResponse, error) {
-	flow := s.Store.SaveFlow(req.Flow)
-	return &types.CreateFlowResponse{Flow: flow}, nil
+	if err := validateFlow(req.Flow); err != nil {
+		return nil, err
+	}
+	return s.saveFlow(ctx, req.Flow)
 }
 
 func (s *FlowEditorService) UpdateFlow(ctx context.Context, req *types.CreateFlowRequest) (*types.CreateFlowResponse, error) {
 	if req.Flow.ID == 0 {
 		return nil, xerrors.Errorf(xerrors.InvalidArgument, nil, "id is required")
 	}
-	flow := s.Store.SaveFlow(req.Flow)
+	if err := validateFlow(req.Flow); err != nil {
+		return nil, err
+	}
+	// the flows of another workspace are not found, and their pages are not
+	// bound to this workspace
+	if s.Store.LoadFlowByID(ctx, req.Flow.ID) == nil {
+		return nil, xerrors.Errorf(xerrors.NotFound, nil, "flow not found")
+	}
+	return s.saveFlow(ctx, req.Flow)
+}
+
+func (s *FlowEditorService) saveFlow(ctx context.Context, flow *types.Flow) (*types.CreateFlowResponse, error) {
+	if err := s.Pages.BindPages(ctx, flow.PageIDs); err != nil {
+		return nil, err
+	}
+	flow, err := s.Store.SaveFlow(ctx, flow)
+	if err != nil {
+		return nil, err
+	}
 	return &types.CreateFlowResponse{Flow: flow}, nil
 }
+
+// AnalyzeFlow reports the cycles, dead ends and unreachable nodes of the flow,
+// and renders its graph when a format is requested.
+func (s *FlowEditorService) AnalyzeFlow(ctx context.Context, req *types.AnalyzeFlowRequest) (*types.AnalyzeFlowResponse, error) {
+	flow := req.Flow
+	if flow == nil {
+		if req.FlowID == 0 {
+			return nil, xerrors.Errorf(xerrors.InvalidArgument, nil, "flow_id or flow is required")
+		}
+		flow = s.Store.LoadFlowByID(ctx, req.FlowID)
+		if flow == nil {
+			return nil, xerrors.Errorf(xerrors.NotFound, nil, "flow not found")
+		}
+	}
+
+	g := flowgraph.Build(flow)
+	resp := &types.AnalyzeFlowResponse{Report: g.Analyze()}
+	if req.Format != "" {
+		graph, err := g.Render(req.Format)
+		if err != nil {
+			return nil, xerrors.Errorf(xerrors.InvalidArgument, err, "%v", err)
+		}
+		resp.Graph = graph
+	}
+	return resp, nil
+}
+
+func validateFlow(flow *types.Flow) error {
+	if flow == nil {
+		return xerrors.Errorf(xerrors.InvalidArgument, nil, "flow is required")
+	}
+	if err := flow.Validate(); err != nil {
+		return xerrors.Errorf(xerrors.InvalidArgument, err, "invalid flow: %v", err)
+	}
+	return nil
+}
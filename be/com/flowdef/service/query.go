@@ -23,22 +23,36 @@ func NewFlowQueryService(flowStore store.FlowStore) *FlowQueryService {
 }
 
 func (f *FlowQueryService) GetFlowByID(ctx context.Context, req *types.GetFlowByIDRequest) (*types.FlowResponse, error) {
-	flow := f.Store.LoadFlowByID(req.ID)
+	flow := f.Store.LoadFlowByID(ctx, req.ID)
 	if flow == nil {
 		return nil, xerrors.Errorf(xerrors.NotFound, nil, "not found")
 	}
 	return &types.FlowResponse{Flow: flow}, nil
 }
 
+// GetFlowByParam returns the flow of the page with the highest priority. The
+// events of the conversations are routed among all the flows of the page, see
+// ListFlows.
 func (f *FlowQueryService) GetFlowByParam(ctx context.Context, req *types.GetFlowByParamRequest) (*types.FlowResponse, error) {
 
 	if req.FBPageID == 0 {
 		return nil, xerrors.Errorf(xerrors.NotFound, nil, "not found")
 	}
 
-	flow := f.Store.LoadFlowByPageID(req.FBPageID)
-	if flow == nil {
+	flows := f.Store.LoadFlowsByPageID(ctx, req.FBPageID)
+	if len(flows) == 0 {
 		return nil, xerrors.Errorf(xerrors.NotFound, nil, "not found")
 	}
-	return &types.FlowResponse{Flow: flow}, nil
+	return &types.FlowResponse{Flow: flows[0]}, nil
+}
+
+func (f *FlowQueryService) ListFlows(ctx context.Context, req *types.ListFlowsRequest) (*types.ListFlowsResponse, error) {
+	if req.PageID == 0 {
+		return nil, xerrors.Errorf(xerrors.InvalidArgument, nil, "page_id is required")
+	}
+	flows := f.Store.LoadFlowsByPageID(ctx, req.PageID)
+	if flows == nil {
+		flows = []*types.Flow{}
+	}
+	return &types.ListFlowsResponse{Flows: flows}, nil
 }
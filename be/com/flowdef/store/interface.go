@@ -1,14 +1,20 @@
 package store
 
 import (
+	"context"
+
 	"github.com/olvrng/rbot/be/com/flowdef/types"
 	"github.com/olvrng/rbot/be/pkg/dot"
 )
 
+// FlowStore keeps the flows. The flows are scoped to the workspace of the
+// context.
 type FlowStore interface {
-	SaveFlow(flow *types.Flow) *types.Flow
+	SaveFlow(ctx context.Context, flow *types.Flow) (*types.Flow, error)
 
-	LoadFlowByID(id dot.IntID) *types.Flow
+	LoadFlowByID(ctx context.Context, id dot.IntID) *types.Flow
 
-	LoadFlowByPageID(pageID dot.IntID) *types.Flow
+	// LoadFlowsByPageID returns the flows of the page, the highest priority
+	// first.
+	LoadFlowsByPageID(ctx context.Context, pageID dot.IntID) []*types.Flow
 }
@@ -1,13 +1,17 @@
 package store
 
 import (
+	"context"
 	"encoding/json"
 	"io/ioutil"
 	"os"
+	"sort"
 
 	"github.com/olvrng/rbot/be/com/flowdef/types"
+	"github.com/olvrng/rbot/be/com/workspace"
 	"github.com/olvrng/rbot/be/pkg/dot"
 	"github.com/olvrng/rbot/be/pkg/l"
+	"github.com/olvrng/rbot/be/pkg/xerrors"
 )
 
 var ll = l.New()
@@ -25,15 +29,25 @@ func (ff *FlowFile) GetByID(id dot.IntID) *types.Flow {
 	return nil
 }
 
-func (ff *FlowFile) GetByPageID(pageID dot.IntID) *types.Flow {
+// ListByPageID returns the flows of the page in the workspace, the highest
+// priority first. The flows with the same priority are in creation order.
+func (ff *FlowFile) ListByPageID(workspaceID, pageID dot.IntID) []*types.Flow {
+	var result []*types.Flow
 	for _, flow := range ff.Flows {
+		if flow.WorkspaceID != workspaceID {
+			continue
+		}
 		for _, _pageID := range flow.PageIDs {
 			if _pageID == pageID {
-				return flow
+				result = append(result, flow)
+				break
 			}
 		}
 	}
-	return nil
+	sort.SliceStable(result, func(i, j int) bool {
+		return result[i].Priority > result[j].Priority
+	})
+	return result
 }
 
 var _ FlowStore = (*FlowFileStore)(nil)
@@ -62,6 +76,7 @@ func NewFlowFileStore(filePath string) (*FlowFileStore, error) {
 
 	case os.IsNotExist(err): // try creating one
 		flowFile = &FlowFile{}
+		s.FlowsData = flowFile
 		err = saveJson(filePath, flowFile)
 		return s, err
 
@@ -70,33 +85,36 @@ func NewFlowFileStore(filePath string) (*FlowFileStore, error) {
 	}
 }
 
-func (s *FlowFileStore) SaveFlow(flow *types.Flow) *types.Flow {
+// SaveFlow creates the flow in the workspace of the context, or replaces the
+// flow with the same id. The flows of the other workspaces are not found.
+func (s *FlowFileStore) SaveFlow(ctx context.Context, flow *types.Flow) (*types.Flow, error) {
+	workspaceID := workspace.GetID(ctx)
 	if flow.ID == 0 {
 		flow.ID = dot.NewIntID()
 	}
 	existingFlow := s.FlowsData.GetByID(flow.ID)
+	if existingFlow != nil && existingFlow.WorkspaceID != workspaceID {
+		return nil, xerrors.Errorf(xerrors.NotFound, nil, "flow not found")
+	}
+	flow.WorkspaceID = workspaceID
 	if existingFlow != nil {
 		*existingFlow = *flow // overwrite
 	} else {
 		s.FlowsData.Flows = append(s.FlowsData.Flows, flow)
 	}
-	return flow
+	return flow, nil
 }
 
-func (s *FlowFileStore) LoadFlowByID(id dot.IntID) *types.Flow {
-	return s.FlowsData.GetByID(id)
+func (s *FlowFileStore) LoadFlowByID(ctx context.Context, id dot.IntID) *types.Flow {
+	flow := s.FlowsData.GetByID(id)
+	if flow == nil || !workspace.Match(ctx, flow.WorkspaceID) {
+		return nil
+	}
+	return flow
 }
 
-func (s *FlowFileStore) LoadFlowByPageID(pageID dot.IntID) *types.Flow {
-	// mock: only implement page_id
-	for _, flow := range s.FlowsData.Flows {
-		for _, _pageID := range flow.PageIDs {
-			if _pageID == pageID {
-				return flow
-			}
-		}
-	}
-	return nil
+func (s *FlowFileStore) LoadFlowsByPageID(ctx context.Context, pageID dot.IntID) []*types.Flow {
+	return s.FlowsData.ListByPageID(workspace.GetID(ctx), pageID)
 }
 
 func loadJson(filePath string) (out *FlowFile, err error) {
@@ -0,0 +1,68 @@
+package types
+
+import "github.com/olvrng/rbot/be/pkg/dot"
+
+type GraphFormat string
+
+const (
+	GraphDOT     GraphFormat = "dot"
+	GraphMermaid GraphFormat = "mermaid"
+)
+
+// AnalyzeFlowRequest analyzes the stored flow FlowID, or Flow when it is set,
+// so the board can check unsaved changes. The graph is rendered when Format is
+// set.
+type AnalyzeFlowRequest struct {
+	FlowID dot.IntID `json:"flow_id"`
+	Flow   *Flow     `json:"flow"`
+	// +validate:enum
+	Format GraphFormat `json:"format"`
+}
+
+type AnalyzeFlowResponse struct {
+	Report *FlowReport `json:"report"`
+	Graph  string      `json:"graph,omitempty"`
+}
+
+type FlowReport struct {
+	NodeCount int `json:"node_count"`
+	LinkCount int `json:"link_count"`
+
+	// Cycles are the loops of the graph. A cycle without a waiting node loops
+	// forever, since the executor passes through all of its nodes.
+	Cycles []*FlowCycle `json:"cycles"`
+
+	// DeadEnds are the nodes which expect to continue, but have no link, such
+	// as a capture_input node without next node.
+	DeadEnds []dot.IntID `json:"dead_ends"`
+
+	// Unreachable nodes are not linked from any trigger, keyword or fallback.
+	Unreachable []dot.IntID `json:"unreachable"`
+
+	// BrokenLinks link to nodes which do not exist.
+	BrokenLinks []*BrokenLink `json:"broken_links"`
+
+	// LongestPath is the longest path without loop from a trigger.
+	LongestPath []dot.IntID `json:"longest_path"`
+}
+
+type FlowCycle struct {
+	NodeIDs []dot.IntID `json:"node_ids"`
+	Waiting bool        `json:"waiting"`
+}
+
+type BrokenLink struct {
+	NodeID dot.IntID `json:"node_id"`
+	Link   *Link     `json:"link"`
+}
+
+// HasProblems tells whether the flow has a cycle without waiting node, a dead
+// end, an unreachable node or a broken link.
+func (r *FlowReport) HasProblems() bool {
+	for _, c := range r.Cycles {
+		if !c.Waiting {
+			return true
+		}
+	}
+	return len(r.DeadEnds) != 0 || len(r.Unreachable) != 0 || len(r.BrokenLinks) != 0
+}
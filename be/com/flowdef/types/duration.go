@@ -0,0 +1,63 @@
+package types
+
+import (
+	"encoding/json"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/olvrng/rbot/be/pkg/xerrors"
+)
+
+const day = 24 * time.Hour
+
+// Duration is encoded as a string in JSON, such as "30m", "24h" or "3d".
+type Duration time.Duration
+
+func ParseDuration(s string) (Duration, error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return 0, nil
+	}
+	if strings.HasSuffix(s, "d") {
+		days, err := strconv.ParseFloat(strings.TrimSuffix(s, "d"), 64)
+		if err != nil {
+			return 0, xerrors.Errorf(xerrors.InvalidArgument, err, "invalid duration %q", s)
+		}
+		return Duration(days * float64(day)), nil
+	}
+	d, err := time.ParseDuration(s)
+	if err != nil {
+		return 0, xerrors.Errorf(xerrors.InvalidArgument, err, "invalid duration %q", s)
+	}
+	return Duration(d), nil
+}
+
+func (d Duration) Duration() time.Duration {
+	return time.Duration(d)
+}
+
+func (d Duration) String() string {
+	switch {
+	case d == 0:
+		return ""
+	case time.Duration(d)%day == 0:
+		return strconv.FormatInt(int64(time.Duration(d)/day), 10) + "d"
+	default:
+		return time.Duration(d).String()
+	}
+}
+
+func (d Duration) MarshalJSON() ([]byte, error) {
+	return json.Marshal(d.String())
+}
+
+func (d *Duration) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return err
+	}
+	v, err := ParseDuration(s)
+	*d = v
+	return err
+}
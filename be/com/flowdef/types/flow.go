@@ -5,6 +5,7 @@ import (
 )
 
 type CreateFlowRequest struct {
+	// +validate:required
 	Flow *Flow `json:"flow"`
 }
 
@@ -13,6 +14,7 @@ type CreateFlowResponse struct {
 }
 
 type UpdateFlowRequest struct {
+	// +validate:required
 	Flow *Flow `json:"flow"`
 }
 
@@ -21,6 +23,7 @@ type UpdateFlowResponse struct {
 }
 
 type GetFlowByIDRequest struct {
+	// +validate:required
 	ID dot.IntID `json:"id"`
 }
 
@@ -28,6 +31,16 @@ type GetFlowByParamRequest struct {
 	FBPageID dot.IntID `json:"id"`
 }
 
+type ListFlowsRequest struct {
+	// +validate:required
+	PageID dot.IntID `json:"page_id"`
+}
+
+// ListFlowsResponse returns the flows of the page, the highest priority first.
+type ListFlowsResponse struct {
+	Flows []*Flow `json:"flows"`
+}
+
 type FlowResponse struct {
 	Flow *Flow `json:"flow"`
 }
@@ -0,0 +1,135 @@
+package types
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/olvrng/rbot/be/pkg/dot"
+)
+
+// Link is an edge of the flow graph: the node may continue to NextID. Label
+// tells when, such as the title of a quick reply, or "timeout".
+type Link struct {
+	Label  string    `json:"label,omitempty"`
+	NextID dot.IntID `json:"next_id"`
+}
+
+// NodeLinksInterface lists all the nodes where a node may continue, unlike Next
+// which picks one of them for an event.
+type NodeLinksInterface interface {
+	Links() []*Link
+}
+
+func (n *NodePayload) Links() []*Link {
+	if n.Data == nil {
+		return nil
+	}
+	return n.Data.Links()
+}
+
+// Waits tells whether the executor stops at the node until the next event. The
+// executor passes through the nodes whose data implements NodeWaitsInterface
+// and returns false (such as set_variable and condition) immediately, see
+// flowcore.RegisterTransition.
+func (n *NodePayload) Waits() bool {
+	if waits, ok := n.Data.(NodeWaitsInterface); ok {
+		return waits.Waits()
+	}
+	return true
+}
+
+func (n *SetVariableNodeData) Waits() bool {
+	return false
+}
+
+func (n *ConditionNodeData) Waits() bool {
+	return false
+}
+
+func (n *HTTPRequestNodeData) Waits() bool {
+	return false
+}
+
+func (n *GotoFlowNodeData) Waits() bool {
+	return false
+}
+
+// appendLink appends the link when the node id is set.
+func appendLink(links []*Link, label string, nextID dot.IntID) []*Link {
+	if nextID == 0 {
+		return links
+	}
+	return append(links, &Link{Label: label, NextID: nextID})
+}
+
+func (n *CompletedOrderNodeData) Links() []*Link {
+	return appendLink(nil, "", n.NextID)
+}
+
+func (n *OrderEventNodeData) Links() []*Link {
+	return appendLink(nil, "", n.NextID)
+}
+
+func (n *SendMessageNodeData) Links() []*Link {
+	var result []*Link
+	for _, reply := range n.QuickReplies {
+		result = appendLink(result, reply.Text, reply.NextID)
+	}
+	result = appendLink(result, "", n.NextID)
+	return appendLink(result, "timeout", n.TimeoutNextID)
+}
+
+func (n *ReceivedMessageNodeData) Links() []*Link {
+	return appendLink(nil, "", n.NextID)
+}
+
+func (n *ReferralNodeData) Links() []*Link {
+	return appendLink(nil, "", n.NextID)
+}
+
+func (n *CaptureInputNodeData) Links() []*Link {
+	return appendLink(appendLink(nil, "", n.NextID), "timeout", n.TimeoutNextID)
+}
+
+func (n *SetVariableNodeData) Links() []*Link {
+	return appendLink(nil, "", n.NextID)
+}
+
+func (n *ConditionNodeData) Links() []*Link {
+	var result []*Link
+	for _, rule := range n.Rules {
+		label := strings.TrimSpace(fmt.Sprintf("%v %v %v", rule.Variable, rule.Op, rule.Value))
+		result = appendLink(result, label, rule.NextID)
+	}
+	return appendLink(result, "else", n.NextID)
+}
+
+func (n *AskRatingNodeData) Links() []*Link {
+	return appendLink(appendLink(nil, "", n.NextID), "timeout", n.TimeoutNextID)
+}
+
+func (n *WaitNodeData) Links() []*Link {
+	return appendLink(nil, "after "+n.Delay.String(), n.NextID)
+}
+
+func (n *KeywordNodeData) Links() []*Link {
+	return appendLink(nil, "", n.NextID)
+}
+
+func (n *HandoffNodeData) Links() []*Link {
+	return appendLink(nil, "resolved", n.NextID)
+}
+
+func (n *HTTPRequestNodeData) Links() []*Link {
+	var result []*Link
+	for _, route := range n.StatusRoutes {
+		result = appendLink(result, route.Status, route.NextID)
+	}
+	result = appendLink(result, "", n.NextID)
+	return appendLink(result, "error", n.ErrorNextID)
+}
+
+// Links returns no link, since the node continues in another flow.
+func (n *GotoFlowNodeData) Links() []*Link {
+	return nil
+}
@@ -0,0 +1,111 @@
+package types
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+
+	"github.com/olvrng/rbot/be/pkg/dot"
+)
+
+// NodeData is the data of a node type. Next is the transition logic: the node
+// where the conversation continues on an event. The node type may also
+// implement NodeTimerInterface and NodeValidatorInterface.
+//
+// The data is encoded as a JSON object with its type in "type". When the
+// struct has a Type field, it is set to NodeType() before encoding.
+type NodeData interface {
+	NodeType() NodeType
+	NodeNextInterface
+	NodeLinksInterface
+}
+
+var nodeRegistry = map[NodeType]func() NodeData{}
+
+// RegisterNode registers a node type, so that its JSON payload is decoded into
+// the data returned by newData. The transition logic and the action of the
+// node are registered with the executor (see flowexec/flowcore.RegisterTransition
+// and flowexec/service.RegisterAction). A package which defines node types
+// registers them in its init function.
+func RegisterNode(typ NodeType, newData func() NodeData) {
+	if typ == "" {
+		panic("register node: empty type")
+	}
+	if nodeRegistry[typ] != nil {
+		panic(fmt.Sprintf("register node: %v is already registered", typ))
+	}
+	nodeRegistry[typ] = newData
+}
+
+// NodeTypes returns the registered node types, sorted.
+func NodeTypes() []NodeType {
+	result := make([]NodeType, 0, len(nodeRegistry))
+	for typ := range nodeRegistry {
+		result = append(result, typ)
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i] < result[j] })
+	return result
+}
+
+func init() {
+	RegisterNode(NodeCompletedOrder, func() NodeData { return &CompletedOrderNodeData{} })
+	RegisterNode(NodeOrderCreated, func() NodeData { return &OrderEventNodeData{} })
+	RegisterNode(NodeOrderPaid, func() NodeData { return &OrderEventNodeData{} })
+	RegisterNode(NodeOrderShipped, func() NodeData { return &OrderEventNodeData{} })
+	RegisterNode(NodeOrderRefunded, func() NodeData { return &OrderEventNodeData{} })
+	RegisterNode(NodeReceivedMessage, func() NodeData { return &ReceivedMessageNodeData{} })
+	RegisterNode(NodeReferral, func() NodeData { return &ReferralNodeData{} })
+	RegisterNode(NodeKeyword, func() NodeData { return &KeywordNodeData{} })
+	RegisterNode(NodeSendMessage, func() NodeData { return &SendMessageNodeData{} })
+	RegisterNode(NodeCaptureInput, func() NodeData { return &CaptureInputNodeData{} })
+	RegisterNode(NodeSetVariable, func() NodeData { return &SetVariableNodeData{} })
+	RegisterNode(NodeCondition, func() NodeData { return &ConditionNodeData{} })
+	RegisterNode(NodeAskRating, func() NodeData { return &AskRatingNodeData{} })
+	RegisterNode(NodeWait, func() NodeData { return &WaitNodeData{} })
+	RegisterNode(NodeHandoff, func() NodeData { return &HandoffNodeData{} })
+	RegisterNode(NodeHTTPRequest, func() NodeData { return &HTTPRequestNodeData{} })
+	RegisterNode(NodeGotoFlow, func() NodeData { return &GotoFlowNodeData{} })
+}
+
+var nodeTypeType = reflect.TypeOf(NodeType(""))
+
+// setNodeType sets the Type field of the node data, if any, so that the nodes
+// created in code are encoded with their type.
+func setNodeType(data NodeData) {
+	v := reflect.ValueOf(data)
+	if v.Kind() != reflect.Ptr || v.IsNil() || v.Elem().Kind() != reflect.Struct {
+		return
+	}
+	field := v.Elem().FieldByName("Type")
+	if field.IsValid() && field.CanSet() && field.Type() == nodeTypeType {
+		field.Set(reflect.ValueOf(data.NodeType()))
+	}
+}
+
+// UnknownNodeData keeps the payload of a node type which is not registered, such
+// as a node added by a newer version of the board. It is encoded back as is,
+// and the executor does not continue from it.
+type UnknownNodeData struct {
+	Type NodeType
+	Raw  json.RawMessage
+}
+
+func (n *UnknownNodeData) NodeType() NodeType {
+	return n.Type
+}
+
+func (n *UnknownNodeData) Next(typ NodeType, data map[string]string) dot.IntID {
+	return 0
+}
+
+func (n *UnknownNodeData) Links() []*Link {
+	return nil
+}
+
+func (n *UnknownNodeData) MarshalJSON() ([]byte, error) {
+	if len(n.Raw) == 0 {
+		return json.Marshal(map[string]NodeType{"type": n.Type})
+	}
+	return n.Raw, nil
+}
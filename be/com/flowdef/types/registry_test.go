@@ -0,0 +1,112 @@
+package types
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/olvrng/rbot/be/pkg/dot"
+)
+
+const nodeCoupon = "action:coupon"
+
+type couponNodeData struct {
+	Type   NodeType  `json:"type"`
+	Code   string    `json:"code"`
+	NextID dot.IntID `json:"next_id,omitempty"`
+}
+
+func (n *couponNodeData) NodeType() NodeType {
+	return nodeCoupon
+}
+
+func (n *couponNodeData) Next(typ NodeType, data map[string]string) dot.IntID {
+	if typ == NodeReceivedMessage {
+		return n.NextID
+	}
+	return 0
+}
+
+func (n *couponNodeData) Links() []*Link {
+	return appendLink(nil, "", n.NextID)
+}
+
+func (n *couponNodeData) Validate() error {
+	if n.Code == "" {
+		return errors.New("coupon without code")
+	}
+	return nil
+}
+
+func init() {
+	RegisterNode(nodeCoupon, func() NodeData { return &couponNodeData{} })
+}
+
+func TestNodePayloadJSON(t *testing.T) {
+	tests := []struct {
+		name string
+		json string
+		want NodeData
+	}{
+		{
+			name: "builtin",
+			json: `{"type":"action:wait","delay":"3d","next_id":"2"}`,
+			want: &WaitNodeData{Type: NodeWait, Delay: Duration(3 * day), NextID: 2},
+		},
+		{
+			name: "order event",
+			json: `{"type":"trigger:order_paid","next_id":"2"}`,
+			want: &OrderEventNodeData{Type: NodeOrderPaid, NextID: 2},
+		},
+		{
+			name: "registered",
+			json: `{"type":"action:coupon","code":"SALE10","next_id":"2"}`,
+			want: &couponNodeData{Type: nodeCoupon, Code: "SALE10", NextID: 2},
+		},
+		{
+			name: "unknown",
+			json: `{"type":"action:lottery","prizes":[1,2,3],"next_id":"2"}`,
+			want: &UnknownNodeData{Type: "action:lottery", Raw: json.RawMessage(`{"type":"action:lottery","prizes":[1,2,3],"next_id":"2"}`)},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var payload NodePayload
+			require.NoError(t, json.Unmarshal([]byte(tt.json), &payload))
+			require.Equal(t, tt.want, payload.Data)
+			require.Equal(t, tt.want.NodeType(), payload.Type())
+
+			out, err := json.Marshal(&payload)
+			require.NoError(t, err)
+			require.JSONEq(t, tt.json, string(out))
+		})
+	}
+}
+
+func TestNodePayloadSetsType(t *testing.T) {
+	payload := &NodePayload{Data: &couponNodeData{Code: "SALE10", NextID: 2}}
+	out, err := json.Marshal(payload)
+	require.NoError(t, err)
+	require.JSONEq(t, `{"type":"action:coupon","code":"SALE10","next_id":"2"}`, string(out))
+	require.Equal(t, dot.IntID(2), payload.Next(NodeReceivedMessage, nil))
+}
+
+func TestValidateNodes(t *testing.T) {
+	flow := &Flow{Nodes: []*Node{
+		{ID: 1, Payload: &NodePayload{Data: &couponNodeData{}}},
+		{ID: 2, Payload: &NodePayload{Data: &UnknownNodeData{Type: "action:lottery"}}},
+	}}
+	require.EqualError(t, flow.Validate(), "node 1: coupon without code")
+
+	flow.Nodes[0].Payload.Data = &couponNodeData{Code: "SALE10"}
+	require.NoError(t, flow.Validate())
+}
+
+func TestRegisterNodeTwice(t *testing.T) {
+	require.Panics(t, func() {
+		RegisterNode(NodeWait, func() NodeData { return &WaitNodeData{} })
+	})
+	require.Contains(t, NodeTypes(), NodeType(nodeCoupon))
+}
@@ -2,25 +2,221 @@ package types
 
 import (
 	"encoding/json"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/pkg/errors"
 
 	"github.com/olvrng/rbot/be/pkg/dot"
+	"github.com/olvrng/rbot/be/pkg/textnorm"
 )
 
 type NodeType string
 
 const (
 	NodeCompletedOrder  = "trigger:completed_order"
+	NodeOrderCreated    = "trigger:order_created"
+	NodeOrderPaid       = "trigger:order_paid"
+	NodeOrderShipped    = "trigger:order_shipped"
+	NodeOrderRefunded   = "trigger:order_refunded"
 	NodeReceivedMessage = "trigger:received_message"
 	NodeReceivedReply   = "trigger:received_reply"
+	NodeReferral        = "trigger:referral"
+	NodeTimer           = "trigger:timer"
+	NodeKeyword         = "trigger:keyword"
 	NodeSendMessage     = "action:send_message"
+	NodeCaptureInput    = "action:capture_input"
+	NodeSetVariable     = "action:set_variable"
+	NodeCondition       = "action:condition"
+	NodeAskRating       = "action:ask_rating"
+	NodeWait            = "action:wait"
+	NodeHandoff         = "action:handoff"
+	NodeHTTPRequest     = "action:http_request"
+	NodeGotoFlow        = "action:goto_flow"
+)
+
+// IsTrigger tells whether the node starts the conversation on an event, rather
+// than acting.
+func (t NodeType) IsTrigger() bool {
+	return strings.HasPrefix(string(t), "trigger:")
+}
+
+// VarType is the type of a conversation variable.
+type VarType string
+
+const (
+	VarString VarType = "string"
+	VarNumber VarType = "number"
+	VarEmail  VarType = "email"
+	VarPhone  VarType = "phone"
+	VarBool   VarType = "bool"
+)
+
+// InputValidation defines how the input of a capture_input node is validated.
+type InputValidation string
+
+const (
+	ValidateNone   InputValidation = ""
+	ValidateEmail  InputValidation = "email"
+	ValidatePhone  InputValidation = "phone"
+	ValidateNumber InputValidation = "number"
+	ValidateRegex  InputValidation = "regex"
+)
+
+// ConditionOp is the comparison operator of a condition rule.
+type ConditionOp string
+
+const (
+	OpEqual        ConditionOp = "eq"
+	OpNotEqual     ConditionOp = "ne"
+	OpGreater      ConditionOp = "gt"
+	OpGreaterEqual ConditionOp = "gte"
+	OpLess         ConditionOp = "lt"
+	OpLessEqual    ConditionOp = "lte"
+	OpContains     ConditionOp = "contains"
+	OpMatches      ConditionOp = "matches"
+	OpEmpty        ConditionOp = "empty"
+	OpNotEmpty     ConditionOp = "not_empty"
+)
+
+// RestartPolicy decides what happens to the variables when the conversation
+// restarts: when an event is handled by an entry point, a keyword or the
+// fallback node instead of the current node.
+type RestartPolicy string
+
+const (
+	RestartKeepVars  RestartPolicy = ""
+	RestartResetVars RestartPolicy = "reset_vars"
 )
 
 type Flow struct {
-	ID      dot.IntID   `json:"id"`
-	PageIDs []dot.IntID `json:"page_ids"`
-	Nodes   []*Node     `json:"nodes"`
+	ID          dot.IntID   `json:"id"`
+	WorkspaceID dot.IntID   `json:"workspace_id,omitempty"`
+	PageIDs     []dot.IntID `json:"page_ids"`
+	Nodes       []*Node     `json:"nodes"`
+
+	// Priority orders the flows of a page. When several flows handle an event,
+	// such as two keyword triggers matching the same message, the flow with
+	// the highest priority handles it.
+	Priority int `json:"priority,omitempty"`
+
+	// EntryPoints maps a trigger type to the trigger node which starts the
+	// conversation when the current node does not handle the event. A trigger
+	// type without entry point uses the trigger node of that type, only if
+	// there is exactly one.
+	EntryPoints map[NodeType]dot.IntID `json:"entry_points,omitempty"`
+
+	// Keywords restart the conversation whenever a message matches, even in
+	// the middle of the flow.
+	Keywords []*KeywordEntry `json:"keywords,omitempty"`
+
+	// FallbackNodeID handles the messages and replies which nothing else
+	// handles, such as "Sorry, I do not understand".
+	FallbackNodeID dot.IntID `json:"fallback_node_id,omitempty"`
+
+	RestartPolicy RestartPolicy `json:"restart_policy,omitempty"`
+
+	// Intents are used by the intent rules of the keyword triggers, when the
+	// executor has no other IntentMatcher.
+	Intents []*Intent `json:"intents,omitempty"`
+}
+
+// Intent is recognized when a message is similar to one of the examples.
+type Intent struct {
+	Name     string   `json:"name"`
+	Examples []string `json:"examples"`
+}
+
+// KeywordEntry goes to NextID when the message is one of the keywords, such as
+// "start" or "menu". The comparison ignores case, diacritics and punctuation.
+type KeywordEntry struct {
+	Keywords []string  `json:"keywords"`
+	NextID   dot.IntID `json:"next_id"`
+}
+
+func (e *KeywordEntry) Match(message string) bool {
+	message = textnorm.Fold(message)
+	for _, kw := range e.Keywords {
+		if message == textnorm.Fold(kw) {
+			return true
+		}
+	}
+	return false
+}
+
+// EntryPoint returns the trigger node which starts the conversation for the
+// trigger type, or nil if there is none.
+func (f *Flow) EntryPoint(typ NodeType) *Node {
+	if nodeID, ok := f.EntryPoints[typ]; ok {
+		return f.NodeByID(nodeID)
+	}
+	var entry *Node
+	for _, node := range f.Nodes {
+		if node.Payload.Type() == typ {
+			if entry != nil {
+				return nil // ambiguous
+			}
+			entry = node
+		}
+	}
+	return entry
+}
+
+// MatchKeyword returns the first keyword entry which matches the message.
+func (f *Flow) MatchKeyword(message string) *KeywordEntry {
+	for _, entry := range f.Keywords {
+		if entry.Match(message) {
+			return entry
+		}
+	}
+	return nil
+}
+
+// Validate checks that the entry points, the keywords and the fallback refer to
+// existing nodes, and the data of the nodes which implement
+// NodeValidatorInterface.
+func (f *Flow) Validate() error {
+	for typ, nodeID := range f.EntryPoints {
+		node := f.NodeByID(nodeID)
+		if node == nil {
+			return errors.Errorf("entry point %v: node %v not found", typ, nodeID)
+		}
+		if node.Payload.Type() != typ {
+			return errors.Errorf("entry point %v: node %v is %v", typ, nodeID, node.Payload.Type())
+		}
+	}
+	for _, entry := range f.Keywords {
+		if len(entry.Keywords) == 0 {
+			return errors.Errorf("keyword entry to node %v: no keywords", entry.NextID)
+		}
+		if f.NodeByID(entry.NextID) == nil {
+			return errors.Errorf("keyword %q: node %v not found", entry.Keywords[0], entry.NextID)
+		}
+	}
+	for _, node := range f.Nodes {
+		if node.Payload == nil {
+			continue
+		}
+		validator, ok := node.Payload.Data.(NodeValidatorInterface)
+		if !ok {
+			continue
+		}
+		if err := validator.Validate(); err != nil {
+			return errors.Wrapf(err, "node %v", node.ID)
+		}
+	}
+	if f.FallbackNodeID != 0 && f.NodeByID(f.FallbackNodeID) == nil {
+		return errors.Errorf("fallback: node %v not found", f.FallbackNodeID)
+	}
+	switch f.RestartPolicy {
+	case RestartKeepVars, RestartResetVars:
+	default:
+		return errors.Errorf("unknown restart policy %q", f.RestartPolicy)
+	}
+	return nil
 }
 
 func (f *Flow) NodeByID(nodeID dot.IntID) *Node {
@@ -45,93 +241,142 @@ func (n *Node) String() string {
 	return string(out)
 }
 
+// NodePayload holds the data of the node, whose type is registered with
+// RegisterNode. The payload of an unregistered type is kept as UnknownNodeData,
+// so that it is not lost when the flow is saved again.
+//
+// +ts:union=RegisterNode
 type NodePayload struct {
-	// Type            NodeType `json:"type"`
-	CompletedOrder  *CompletedOrderNodeData
-	SendMessage     *SendMessageNodeData
-	ReceivedMessage *ReceivedMessageNodeData
+	Data NodeData
 }
 
 func (n *NodePayload) Type() NodeType {
-	if n.CompletedOrder != nil {
-		return NodeCompletedOrder
-	}
-	if n.SendMessage != nil {
-		return NodeSendMessage
-	}
-	if n.ReceivedMessage != nil {
-		return NodeReceivedMessage
+	if n.Data == nil {
+		return ""
 	}
-	return ""
+	return n.Data.NodeType()
 }
 
 func (n *NodePayload) MarshalJSON() ([]byte, error) {
-	switch {
-	case n.CompletedOrder != nil:
-		n.CompletedOrder.Type = NodeCompletedOrder
-		return json.Marshal(n.CompletedOrder)
-	case n.SendMessage != nil:
-		n.SendMessage.Type = NodeSendMessage
-		return json.Marshal(n.SendMessage)
-	case n.ReceivedMessage != nil:
-		n.ReceivedMessage.Type = NodeReceivedMessage
-		return json.Marshal(n.ReceivedMessage)
-	default:
-		return nil, nil
+	if n.Data == nil {
+		return []byte("null"), nil
 	}
+	setNodeType(n.Data)
+	return json.Marshal(n.Data)
 }
 
 func (n *NodePayload) UnmarshalJSON(data []byte) error {
 	var t struct {
-		Type string `json:"type"`
+		Type NodeType `json:"type"`
 	}
 	if err := json.Unmarshal(data, &t); err != nil {
 		return err
 	}
-	switch t.Type {
-	case NodeCompletedOrder:
-		return json.Unmarshal(data, &n.CompletedOrder)
-	case NodeSendMessage:
-		return json.Unmarshal(data, &n.SendMessage)
-	case NodeReceivedMessage:
-		return json.Unmarshal(data, &n.ReceivedMessage)
-	default:
-		return errors.New("unknown node")
+	newData := nodeRegistry[t.Type]
+	if newData == nil {
+		n.Data = &UnknownNodeData{Type: t.Type, Raw: append(json.RawMessage(nil), data...)}
+		return nil
 	}
+	nodeData := newData()
+	if err := json.Unmarshal(data, nodeData); err != nil {
+		return err
+	}
+	n.Data = nodeData
+	return nil
 }
 
 func (n *NodePayload) Next(typ NodeType, data map[string]string) dot.IntID {
-	switch {
-	case n.CompletedOrder != nil:
-		return n.CompletedOrder.Next(typ, data)
-	case n.SendMessage != nil:
-		return n.SendMessage.Next(typ, data)
-	case n.ReceivedMessage != nil:
-		return n.ReceivedMessage.Next(typ, data)
-	default:
+	if n.Data == nil {
 		return 0
 	}
+	return n.Data.Next(typ, data)
+}
+
+// Timer returns how long the node waits before a trigger:timer transition: the
+// delay of a wait node, or the timeout of a node waiting for the user. It
+// returns 0 when the node has no timer.
+func (n *NodePayload) Timer() time.Duration {
+	if timer, ok := n.Data.(NodeTimerInterface); ok {
+		return timer.Timer()
+	}
+	return 0
 }
 
 type NodeNextInterface interface {
 	Next(typ NodeType, data map[string]string) dot.IntID
 }
 
+// NodeTimerInterface is implemented by the nodes which continue on
+// trigger:timer, after the returned duration.
+type NodeTimerInterface interface {
+	Timer() time.Duration
+}
+
+// NodeValidatorInterface is implemented by the nodes which check their data
+// when the flow is validated.
+type NodeValidatorInterface interface {
+	Validate() error
+}
+
+// NodeWaitsInterface is implemented by the nodes which may not wait for the
+// user, see NodePayload.Waits.
+type NodeWaitsInterface interface {
+	Waits() bool
+}
+
 type CompletedOrderNodeData struct {
 	Type   NodeType  `json:"type"`
 	NextID dot.IntID `json:"next_id,omitempty"`
 	Fields []string  `json:"fields"`
 }
 
+func (n *CompletedOrderNodeData) NodeType() NodeType {
+	return NodeCompletedOrder
+}
+
 func (n *CompletedOrderNodeData) Next(typ NodeType, data map[string]string) dot.IntID {
 	return n.NextID
 }
 
+// OrderEventNodeData is the trigger for an order status other than completed
+// (which keeps using CompletedOrderNodeData). Type tells which status.
+type OrderEventNodeData struct {
+	Type   NodeType  `json:"type"`
+	NextID dot.IntID `json:"next_id,omitempty"`
+}
+
+func (n *OrderEventNodeData) NodeType() NodeType {
+	return n.Type
+}
+
+func (n *OrderEventNodeData) Next(typ NodeType, data map[string]string) dot.IntID {
+	if typ == n.Type {
+		return n.NextID
+	}
+	return 0
+}
+
 type SendMessageNodeData struct {
 	Type         NodeType          `json:"type"`
 	Template     string            `json:"template"`
 	NextID       dot.IntID         `json:"next_id,omitempty"`
 	QuickReplies []*QuickReplyItem `json:"quick_replies"`
+
+	// When the user does not reply within Timeout, the flow continues to
+	// TimeoutNextID, for example to send a reminder.
+	Timeout       Duration  `json:"timeout,omitempty"`
+	TimeoutNextID dot.IntID `json:"timeout_next_id,omitempty"`
+}
+
+func (n *SendMessageNodeData) Timer() time.Duration {
+	if n.TimeoutNextID == 0 {
+		return 0
+	}
+	return n.Timeout.Duration()
+}
+
+func (n *SendMessageNodeData) NodeType() NodeType {
+	return NodeSendMessage
 }
 
 func (n *SendMessageNodeData) Next(typ NodeType, data map[string]string) dot.IntID {
@@ -147,6 +392,9 @@ func (n *SendMessageNodeData) Next(typ NodeType, data map[string]string) dot.Int
 		}
 		return n.NextID
 
+	case NodeTimer:
+		return n.TimeoutNextID
+
 	default:
 		return 0
 	}
@@ -163,6 +411,10 @@ type ReceivedMessageNodeData struct {
 	NextID dot.IntID `json:"next_id"`
 }
 
+func (n *ReceivedMessageNodeData) NodeType() NodeType {
+	return NodeReceivedMessage
+}
+
 func (n *ReceivedMessageNodeData) Next(typ NodeType, data map[string]string) dot.IntID {
 	switch typ {
 	case NodeReceivedMessage:
@@ -170,3 +422,421 @@ func (n *ReceivedMessageNodeData) Next(typ NodeType, data map[string]string) dot
 	}
 	return 0
 }
+
+// ReferralNodeData starts a flow when the customer opens the conversation from
+// an m.me link. When Ref is set, only the refs with that prefix (such as
+// "order:") are accepted.
+type ReferralNodeData struct {
+	Type   NodeType  `json:"type"`
+	Ref    string    `json:"ref,omitempty"`
+	NextID dot.IntID `json:"next_id"`
+}
+
+func (n *ReferralNodeData) NodeType() NodeType {
+	return NodeReferral
+}
+
+func (n *ReferralNodeData) Next(typ NodeType, data map[string]string) dot.IntID {
+	if typ == NodeReferral && strings.HasPrefix(data["ref"], n.Ref) {
+		return n.NextID
+	}
+	return 0
+}
+
+// CaptureInputNodeData asks a question and stores the next message into a
+// conversation variable. The question is asked again (with RetryTemplate if
+// any) until the input passes the validation.
+type CaptureInputNodeData struct {
+	Type          NodeType        `json:"type"`
+	Template      string          `json:"template"`
+	RetryTemplate string          `json:"retry_template,omitempty"`
+	Variable      string          `json:"variable"`
+	Validation    InputValidation `json:"validation,omitempty"`
+	Pattern       string          `json:"pattern,omitempty"` // for ValidateRegex
+	NextID        dot.IntID       `json:"next_id,omitempty"`
+	Timeout       Duration        `json:"timeout,omitempty"`
+	TimeoutNextID dot.IntID       `json:"timeout_next_id,omitempty"`
+}
+
+func (n *CaptureInputNodeData) Timer() time.Duration {
+	if n.TimeoutNextID == 0 {
+		return 0
+	}
+	return n.Timeout.Duration()
+}
+
+func (n *CaptureInputNodeData) NodeType() NodeType {
+	return NodeCaptureInput
+}
+
+func (n *CaptureInputNodeData) Next(typ NodeType, data map[string]string) dot.IntID {
+	switch typ {
+	case NodeReceivedMessage:
+		return n.NextID
+	case NodeTimer:
+		return n.TimeoutNextID
+	}
+	return 0
+}
+
+// VarType returns the type of the captured variable, derived from the
+// validation.
+func (n *CaptureInputNodeData) VarType() VarType {
+	switch n.Validation {
+	case ValidateEmail:
+		return VarEmail
+	case ValidatePhone:
+		return VarPhone
+	case ValidateNumber:
+		return VarNumber
+	default:
+		return VarString
+	}
+}
+
+// SetVariableNodeData assigns conversation variables then immediately continues
+// to the next node without waiting for the user.
+type SetVariableNodeData struct {
+	Type        NodeType      `json:"type"`
+	Assignments []*Assignment `json:"assignments"`
+	NextID      dot.IntID     `json:"next_id,omitempty"`
+}
+
+func (n *SetVariableNodeData) NodeType() NodeType {
+	return NodeSetVariable
+}
+
+func (n *SetVariableNodeData) Next(typ NodeType, data map[string]string) dot.IntID {
+	return 0
+}
+
+// Assignment sets Variable to the result of Expr. Expr is a template (see
+// flowcore.RenderTemplate), which is then evaluated as an arithmetic expression
+// when the type is VarNumber.
+type Assignment struct {
+	Variable string  `json:"variable"`
+	VarType  VarType `json:"var_type,omitempty"`
+	Expr     string  `json:"expr"`
+}
+
+// ConditionNodeData branches to the first matching rule, or NextID when no rule
+// matches. Like SetVariableNodeData, it does not wait for the user.
+type ConditionNodeData struct {
+	Type   NodeType         `json:"type"`
+	Rules  []*ConditionRule `json:"rules"`
+	NextID dot.IntID        `json:"next_id,omitempty"`
+}
+
+func (n *ConditionNodeData) NodeType() NodeType {
+	return NodeCondition
+}
+
+func (n *ConditionNodeData) Next(typ NodeType, data map[string]string) dot.IntID {
+	return 0
+}
+
+type ConditionRule struct {
+	Variable string      `json:"variable"`
+	Op       ConditionOp `json:"op"`
+	Value    string      `json:"value,omitempty"`
+	NextID   dot.IntID   `json:"next_id"`
+}
+
+const (
+	MaxRating = 5
+
+	// RatingPayloadPrefix is the prefix of the quick reply payloads of an
+	// ask_rating node, such as "rating:5".
+	RatingPayloadPrefix = "rating:"
+	RatingPayloadSkip   = "rating:skip"
+
+	VarRating        = "rating"
+	VarRatingComment = "rating_comment"
+)
+
+// AskRatingNodeData asks the customer to rate from 1 to 5 stars with quick
+// replies, then asks for an optional comment when CommentTemplate is set. The
+// results are stored in the "rating" and "rating_comment" variables and saved
+// as a review of the order which triggered the flow. Use a condition node on
+// "rating" after it to branch the flow.
+type AskRatingNodeData struct {
+	Type            NodeType  `json:"type"`
+	Template        string    `json:"template"`
+	RetryTemplate   string    `json:"retry_template,omitempty"`
+	CommentTemplate string    `json:"comment_template,omitempty"`
+	SkipText        string    `json:"skip_text,omitempty"`
+	NextID          dot.IntID `json:"next_id,omitempty"`
+	Timeout         Duration  `json:"timeout,omitempty"`
+	TimeoutNextID   dot.IntID `json:"timeout_next_id,omitempty"`
+}
+
+func (n *AskRatingNodeData) Timer() time.Duration {
+	if n.TimeoutNextID == 0 {
+		return 0
+	}
+	return n.Timeout.Duration()
+}
+
+func (n *AskRatingNodeData) NodeType() NodeType {
+	return NodeAskRating
+}
+
+func (n *AskRatingNodeData) Next(typ NodeType, data map[string]string) dot.IntID {
+	switch typ {
+	case NodeReceivedMessage, NodeReceivedReply:
+		return n.NextID
+	case NodeTimer:
+		return n.TimeoutNextID
+	}
+	return 0
+}
+
+// WaitNodeData pauses the flow for Delay, then continues to NextID, for example
+// "3 days after the order is completed, ask for a review". The timer is
+// cancelled when the conversation moves on before that.
+type WaitNodeData struct {
+	Type   NodeType  `json:"type"`
+	Delay  Duration  `json:"delay"`
+	NextID dot.IntID `json:"next_id,omitempty"`
+}
+
+func (n *WaitNodeData) Timer() time.Duration {
+	return n.Delay.Duration()
+}
+
+func (n *WaitNodeData) NodeType() NodeType {
+	return NodeWait
+}
+
+func (n *WaitNodeData) Next(typ NodeType, data map[string]string) dot.IntID {
+	if typ == NodeTimer {
+		return n.NextID
+	}
+	return 0
+}
+
+// KeywordMatch is how a keyword rule matches a message. All of them ignore case
+// and diacritics.
+type KeywordMatch string
+
+const (
+	// MatchExact matches when the whole message is one of the values.
+	MatchExact KeywordMatch = "exact"
+
+	// MatchAny matches when the message contains one of the values as whole
+	// words.
+	MatchAny KeywordMatch = "any"
+
+	// MatchRegex matches the pattern against the message, with or without
+	// diacritics.
+	MatchRegex KeywordMatch = "regex"
+
+	// MatchIntent matches when the IntentMatcher of the executor recognizes
+	// the intent with at least MinScore.
+	MatchIntent KeywordMatch = "intent"
+)
+
+const DefaultIntentMinScore = 0.75
+
+// KeywordNodeData is a trigger which matches messages at any point of the
+// conversation, such as "cancel". The executor evaluates the keyword triggers
+// of the flow before the current node. When several rules match, the one with
+// the highest priority wins, then the first one in the flow.
+type KeywordNodeData struct {
+	Type   NodeType       `json:"type"`
+	Rules  []*KeywordRule `json:"rules"`
+	NextID dot.IntID      `json:"next_id,omitempty"`
+}
+
+func (n *KeywordNodeData) NodeType() NodeType {
+	return NodeKeyword
+}
+
+// Next returns 0, since the keyword triggers are matched by the executor.
+func (n *KeywordNodeData) Next(typ NodeType, data map[string]string) dot.IntID {
+	return 0
+}
+
+func (n *KeywordNodeData) Validate() error {
+	if len(n.Rules) == 0 {
+		return errors.New("keyword trigger without rules")
+	}
+	for _, rule := range n.Rules {
+		switch rule.Match {
+		case MatchExact, MatchAny:
+			if len(rule.Values) == 0 {
+				return errors.Errorf("%v rule without values", rule.Match)
+			}
+		case MatchRegex:
+			if _, err := regexp.Compile(rule.Pattern); err != nil {
+				return errors.Wrapf(err, "invalid pattern %q", rule.Pattern)
+			}
+		case MatchIntent:
+			if rule.Intent == "" {
+				return errors.New("intent rule without intent")
+			}
+		default:
+			return errors.Errorf("unknown match %q", rule.Match)
+		}
+	}
+	return nil
+}
+
+type KeywordRule struct {
+	Match    KeywordMatch `json:"match"`
+	Values   []string     `json:"values,omitempty"`  // for MatchExact and MatchAny
+	Pattern  string       `json:"pattern,omitempty"` // for MatchRegex
+	Intent   string       `json:"intent,omitempty"`  // for MatchIntent
+	MinScore float64      `json:"min_score,omitempty"`
+	Priority int          `json:"priority,omitempty"`
+}
+
+// HandoffNodeData pauses the bot and passes the conversation to a person. The
+// messages of the customer are kept in the inbox until an agent resolves the
+// handoff, then the flow resumes at the chosen node, or NextID by default.
+type HandoffNodeData struct {
+	Type     NodeType `json:"type"`
+	Template string   `json:"template,omitempty"`
+	Reason   string   `json:"reason,omitempty"`
+
+	// PassThreadControl passes the conversation to TargetAppID (the Page Inbox
+	// by default) with the Messenger Handover Protocol.
+	PassThreadControl bool      `json:"pass_thread_control,omitempty"`
+	TargetAppID       dot.IntID `json:"target_app_id,omitempty"`
+
+	NextID dot.IntID `json:"next_id,omitempty"`
+}
+
+func (n *HandoffNodeData) NodeType() NodeType {
+	return NodeHandoff
+}
+
+// Next returns 0, since the bot is paused until the handoff is resolved.
+func (n *HandoffNodeData) Next(typ NodeType, data map[string]string) dot.IntID {
+	return 0
+}
+
+// HTTPRequestNodeData calls an external service, such as the order system to
+// look up the shipping status, then immediately continues without waiting for
+// the user. The URL, the headers and the body are templates. The host of the
+// URL must be allowed in the config.
+//
+// The fields of the JSON response are stored into variables with
+// ResponseVars. The flow continues to the first status route which matches the
+// status code, or NextID for a 2xx status, or ErrorNextID when the request
+// fails or the status has no route.
+type HTTPRequestNodeData struct {
+	Type    NodeType          `json:"type"`
+	Method  string            `json:"method,omitempty"` // GET by default
+	URL     string            `json:"url"`
+	Headers map[string]string `json:"headers,omitempty"`
+	Body    string            `json:"body,omitempty"`
+
+	// Timeout applies to each attempt. The request is retried up to Retries
+	// times on a network error or a 5xx status.
+	Timeout Duration `json:"timeout,omitempty"`
+	Retries int      `json:"retries,omitempty"`
+
+	// StatusVariable stores the status code, when set.
+	StatusVariable string         `json:"status_variable,omitempty"`
+	ResponseVars   []*ResponseVar `json:"response_vars,omitempty"`
+	StatusRoutes   []*StatusRoute `json:"status_routes,omitempty"`
+
+	NextID      dot.IntID `json:"next_id,omitempty"`
+	ErrorNextID dot.IntID `json:"error_next_id,omitempty"`
+}
+
+const (
+	DefaultHTTPRequestTimeout = 5 * time.Second
+	MaxHTTPRequestTimeout     = 30 * time.Second
+	MaxHTTPRequestRetries     = 3
+)
+
+func (n *HTTPRequestNodeData) NodeType() NodeType {
+	return NodeHTTPRequest
+}
+
+// Next returns 0, since the executor makes the request and continues
+// immediately.
+func (n *HTTPRequestNodeData) Next(typ NodeType, data map[string]string) dot.IntID {
+	return 0
+}
+
+func (n *HTTPRequestNodeData) Validate() error {
+	if n.URL == "" {
+		return errors.New("http request without url")
+	}
+	switch n.Method {
+	case "", http.MethodGet, http.MethodPost, http.MethodPut, http.MethodPatch, http.MethodDelete:
+	default:
+		return errors.Errorf("unsupported method %q", n.Method)
+	}
+	if n.Timeout < 0 || n.Timeout.Duration() > MaxHTTPRequestTimeout {
+		return errors.Errorf("timeout must be at most %v", MaxHTTPRequestTimeout)
+	}
+	if n.Retries < 0 || n.Retries > MaxHTTPRequestRetries {
+		return errors.Errorf("retries must be between 0 and %v", MaxHTTPRequestRetries)
+	}
+	for _, v := range n.ResponseVars {
+		if v.Variable == "" || v.Path == "" {
+			return errors.New("response variable without name or path")
+		}
+	}
+	for _, route := range n.StatusRoutes {
+		if !reStatusPattern.MatchString(route.Status) {
+			return errors.Errorf("invalid status %q", route.Status)
+		}
+	}
+	return nil
+}
+
+// ResponseVar stores the field of the JSON response at Path, such as
+// "data.items.0.status", into Variable.
+type ResponseVar struct {
+	Variable string  `json:"variable"`
+	VarType  VarType `json:"var_type,omitempty"`
+	Path     string  `json:"path"`
+}
+
+var reStatusPattern = regexp.MustCompile(`^[1-5]([0-9]{2}|xx)$`)
+
+// StatusRoute goes to NextID when the status code matches Status, such as
+// "404" or "4xx".
+type StatusRoute struct {
+	Status string    `json:"status"`
+	NextID dot.IntID `json:"next_id"`
+}
+
+func (r *StatusRoute) Match(statusCode int) bool {
+	status := strconv.Itoa(statusCode)
+	if strings.HasSuffix(r.Status, "xx") {
+		return len(status) == 3 && status[0] == r.Status[0]
+	}
+	return status == r.Status
+}
+
+// GotoFlowNodeData hands the conversation over to another flow of the page:
+// the conversation continues at NodeID of FlowID, with the variables of the
+// current flow. The state of the current flow is kept, so the conversation may
+// come back to it later.
+type GotoFlowNodeData struct {
+	Type   NodeType  `json:"type"`
+	FlowID dot.IntID `json:"flow_id"`
+	NodeID dot.IntID `json:"node_id"`
+}
+
+func (n *GotoFlowNodeData) NodeType() NodeType {
+	return NodeGotoFlow
+}
+
+// Next returns 0, since the conversation continues in the other flow.
+func (n *GotoFlowNodeData) Next(typ NodeType, data map[string]string) dot.IntID {
+	return 0
+}
+
+func (n *GotoFlowNodeData) Validate() error {
+	if n.FlowID == 0 || n.NodeID == 0 {
+		return errors.New("goto flow without flow_id or node_id")
+	}
+	return nil
+}
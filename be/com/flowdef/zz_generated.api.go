@@ -15,6 +15,83 @@ import (
 
 func init() {
 	httprpc.Register(NewServer)
+	httprpc.RegisterSchemas(
+		&httprpc.Schema{Name: "github.com/olvrng/rbot/be/com/flowdef/types.AnalyzeFlowRequest", Fields: []httprpc.Field{
+			{Name: "flow_id", Number: 1, Kind: httprpc.KindInt},
+			{Name: "flow", Number: 2, Kind: httprpc.KindMessage, Message: "github.com/olvrng/rbot/be/com/flowdef/types.Flow"},
+			{Name: "format", Number: 3, Kind: httprpc.KindString},
+		}},
+		&httprpc.Schema{Name: "github.com/olvrng/rbot/be/com/flowdef/types.AnalyzeFlowResponse", Fields: []httprpc.Field{
+			{Name: "report", Number: 1, Kind: httprpc.KindMessage, Message: "github.com/olvrng/rbot/be/com/flowdef/types.FlowReport"},
+			{Name: "graph", Number: 2, Kind: httprpc.KindString},
+		}},
+		&httprpc.Schema{Name: "github.com/olvrng/rbot/be/com/flowdef/types.BrokenLink", Fields: []httprpc.Field{
+			{Name: "node_id", Number: 1, Kind: httprpc.KindInt},
+			{Name: "link", Number: 2, Kind: httprpc.KindMessage, Message: "github.com/olvrng/rbot/be/com/flowdef/types.Link"},
+		}},
+		&httprpc.Schema{Name: "github.com/olvrng/rbot/be/com/flowdef/types.CreateFlowRequest", Fields: []httprpc.Field{
+			{Name: "flow", Number: 1, Kind: httprpc.KindMessage, Message: "github.com/olvrng/rbot/be/com/flowdef/types.Flow"},
+		}},
+		&httprpc.Schema{Name: "github.com/olvrng/rbot/be/com/flowdef/types.CreateFlowResponse", Fields: []httprpc.Field{
+			{Name: "flow", Number: 1, Kind: httprpc.KindMessage, Message: "github.com/olvrng/rbot/be/com/flowdef/types.Flow"},
+		}},
+		&httprpc.Schema{Name: "github.com/olvrng/rbot/be/com/flowdef/types.Flow", Fields: []httprpc.Field{
+			{Name: "id", Number: 1, Kind: httprpc.KindInt},
+			{Name: "workspace_id", Number: 2, Kind: httprpc.KindInt},
+			{Name: "page_ids", Number: 3, Kind: httprpc.KindInt, Repeated: true},
+			{Name: "nodes", Number: 4, Kind: httprpc.KindMessage, Repeated: true, Message: "github.com/olvrng/rbot/be/com/flowdef/types.Node"},
+			{Name: "priority", Number: 5, Kind: httprpc.KindInt},
+			{Name: "entry_points", Number: 6, Kind: httprpc.KindInt, Map: true},
+			{Name: "keywords", Number: 7, Kind: httprpc.KindMessage, Repeated: true, Message: "github.com/olvrng/rbot/be/com/flowdef/types.KeywordEntry"},
+			{Name: "fallback_node_id", Number: 8, Kind: httprpc.KindInt},
+			{Name: "restart_policy", Number: 9, Kind: httprpc.KindString},
+			{Name: "intents", Number: 10, Kind: httprpc.KindMessage, Repeated: true, Message: "github.com/olvrng/rbot/be/com/flowdef/types.Intent"},
+		}},
+		&httprpc.Schema{Name: "github.com/olvrng/rbot/be/com/flowdef/types.FlowCycle", Fields: []httprpc.Field{
+			{Name: "node_ids", Number: 1, Kind: httprpc.KindInt, Repeated: true},
+			{Name: "waiting", Number: 2, Kind: httprpc.KindBool},
+		}},
+		&httprpc.Schema{Name: "github.com/olvrng/rbot/be/com/flowdef/types.FlowReport", Fields: []httprpc.Field{
+			{Name: "node_count", Number: 1, Kind: httprpc.KindInt},
+			{Name: "link_count", Number: 2, Kind: httprpc.KindInt},
+			{Name: "cycles", Number: 3, Kind: httprpc.KindMessage, Repeated: true, Message: "github.com/olvrng/rbot/be/com/flowdef/types.FlowCycle"},
+			{Name: "dead_ends", Number: 4, Kind: httprpc.KindInt, Repeated: true},
+			{Name: "unreachable", Number: 5, Kind: httprpc.KindInt, Repeated: true},
+			{Name: "broken_links", Number: 6, Kind: httprpc.KindMessage, Repeated: true, Message: "github.com/olvrng/rbot/be/com/flowdef/types.BrokenLink"},
+			{Name: "longest_path", Number: 7, Kind: httprpc.KindInt, Repeated: true},
+		}},
+		&httprpc.Schema{Name: "github.com/olvrng/rbot/be/com/flowdef/types.FlowResponse", Fields: []httprpc.Field{
+			{Name: "flow", Number: 1, Kind: httprpc.KindMessage, Message: "github.com/olvrng/rbot/be/com/flowdef/types.Flow"},
+		}},
+		&httprpc.Schema{Name: "github.com/olvrng/rbot/be/com/flowdef/types.GetFlowByIDRequest", Fields: []httprpc.Field{
+			{Name: "id", Number: 1, Kind: httprpc.KindInt},
+		}},
+		&httprpc.Schema{Name: "github.com/olvrng/rbot/be/com/flowdef/types.GetFlowByParamRequest", Fields: []httprpc.Field{
+			{Name: "id", Number: 1, Kind: httprpc.KindInt},
+		}},
+		&httprpc.Schema{Name: "github.com/olvrng/rbot/be/com/flowdef/types.Intent", Fields: []httprpc.Field{
+			{Name: "name", Number: 1, Kind: httprpc.KindString},
+			{Name: "examples", Number: 2, Kind: httprpc.KindString, Repeated: true},
+		}},
+		&httprpc.Schema{Name: "github.com/olvrng/rbot/be/com/flowdef/types.KeywordEntry", Fields: []httprpc.Field{
+			{Name: "keywords", Number: 1, Kind: httprpc.KindString, Repeated: true},
+			{Name: "next_id", Number: 2, Kind: httprpc.KindInt},
+		}},
+		&httprpc.Schema{Name: "github.com/olvrng/rbot/be/com/flowdef/types.Link", Fields: []httprpc.Field{
+			{Name: "label", Number: 1, Kind: httprpc.KindString},
+			{Name: "next_id", Number: 2, Kind: httprpc.KindInt},
+		}},
+		&httprpc.Schema{Name: "github.com/olvrng/rbot/be/com/flowdef/types.ListFlowsRequest", Fields: []httprpc.Field{
+			{Name: "page_id", Number: 1, Kind: httprpc.KindInt},
+		}},
+		&httprpc.Schema{Name: "github.com/olvrng/rbot/be/com/flowdef/types.ListFlowsResponse", Fields: []httprpc.Field{
+			{Name: "flows", Number: 1, Kind: httprpc.KindMessage, Repeated: true, Message: "github.com/olvrng/rbot/be/com/flowdef/types.Flow"},
+		}},
+		&httprpc.Schema{Name: "github.com/olvrng/rbot/be/com/flowdef/types.Node", Fields: []httprpc.Field{
+			{Name: "id", Number: 1, Kind: httprpc.KindInt},
+			{Name: "payload", Number: 2, Kind: httprpc.KindJSON},
+		}},
+	)
 }
 
 func NewServer(builder interface{}, hooks ...httprpc.HooksBuilder) (httprpc.Server, bool) {
@@ -48,6 +125,7 @@ func NewEditorServiceServer(builder func() EditorService, hooks ...httprpc.Hooks
 
 const EditorServicePathPrefix = "/api/flow/def/editor/"
 
+const Path_Editor_AnalyzeFlow = "/api/flow/def/editor/AnalyzeFlow"
 const Path_Editor_CreateFlow = "/api/flow/def/editor/CreateFlow"
 const Path_Editor_UpdateFlow = "/api/flow/def/editor/UpdateFlow"
 
@@ -69,12 +147,12 @@ func (s *EditorServiceServer) ServeHTTP(resp http.ResponseWriter, req *http.Requ
 		httprpc.WriteError(ctx, resp, hooks, *info, err)
 		return
 	}
-	serve, err := httprpc.ParseRequestHeader(req)
+	reqMsg, exec, allowGET, err := s.parseRoute(req.URL.Path, hooks, info)
 	if err != nil {
 		httprpc.WriteError(ctx, resp, hooks, *info, err)
 		return
 	}
-	reqMsg, exec, err := s.parseRoute(req.URL.Path, hooks, info)
+	serve, err := httprpc.ParseRequestHeader(req, allowGET)
 	if err != nil {
 		httprpc.WriteError(ctx, resp, hooks, *info, err)
 		return
@@ -82,8 +160,24 @@ func (s *EditorServiceServer) ServeHTTP(resp http.ResponseWriter, req *http.Requ
 	serve(ctx, resp, req, hooks, info, reqMsg, exec)
 }
 
-func (s *EditorServiceServer) parseRoute(path string, hooks httprpc.Hooks, info *httprpc.HookInfo) (reqMsg httprpc.Message, _ httprpc.ExecFunc, _ error) {
+func (s *EditorServiceServer) parseRoute(path string, hooks httprpc.Hooks, info *httprpc.HookInfo) (reqMsg httprpc.Message, _ httprpc.ExecFunc, allowGET bool, _ error) {
 	switch path {
+	case "/api/flow/def/editor/AnalyzeFlow":
+		msg := &flowdeftypes.AnalyzeFlowRequest{}
+		fn := func(ctx context.Context) (newCtx context.Context, resp httprpc.Message, err error) {
+			inner := s.builder()
+			info.Request, info.Inner = msg, inner
+			newCtx, err = hooks.RequestRouted(ctx, *info)
+			if err != nil {
+				return
+			}
+			if err = msg.Validate(); err != nil {
+				return
+			}
+			resp, err = inner.AnalyzeFlow(newCtx, msg)
+			return
+		}
+		return msg, fn, false, nil
 	case "/api/flow/def/editor/CreateFlow":
 		msg := &flowdeftypes.CreateFlowRequest{}
 		fn := func(ctx context.Context) (newCtx context.Context, resp httprpc.Message, err error) {
@@ -93,10 +187,13 @@ func (s *EditorServiceServer) parseRoute(path string, hooks httprpc.Hooks, info
 			if err != nil {
 				return
 			}
+			if err = msg.Validate(); err != nil {
+				return
+			}
 			resp, err = inner.CreateFlow(newCtx, msg)
 			return
 		}
-		return msg, fn, nil
+		return msg, fn, false, nil
 	case "/api/flow/def/editor/UpdateFlow":
 		msg := &flowdeftypes.CreateFlowRequest{}
 		fn := func(ctx context.Context) (newCtx context.Context, resp httprpc.Message, err error) {
@@ -106,14 +203,54 @@ func (s *EditorServiceServer) parseRoute(path string, hooks httprpc.Hooks, info
 			if err != nil {
 				return
 			}
+			if err = msg.Validate(); err != nil {
+				return
+			}
 			resp, err = inner.UpdateFlow(newCtx, msg)
 			return
 		}
-		return msg, fn, nil
+		return msg, fn, false, nil
 	default:
 		msg := fmt.Sprintf("no handler for path %q", path)
-		return nil, nil, httprpc.BadRouteError(msg, "POST", path)
+		return nil, nil, false, httprpc.BadRouteError(msg, "POST", path)
+	}
+}
+
+// EditorServiceClient calls EditorService on the server at baseURL.
+type EditorServiceClient struct {
+	client *httprpc.Client
+}
+
+var _ EditorService = &EditorServiceClient{}
+
+func NewEditorServiceClient(baseURL string, httpClient *http.Client, hooks ...httprpc.ClientHooks) *EditorServiceClient {
+	return &EditorServiceClient{
+		client: httprpc.NewClient(baseURL, httpClient, hooks...),
+	}
+}
+
+func (c *EditorServiceClient) AnalyzeFlow(ctx context.Context, req *flowdeftypes.AnalyzeFlowRequest) (*flowdeftypes.AnalyzeFlowResponse, error) {
+	resp := &flowdeftypes.AnalyzeFlowResponse{}
+	if err := c.client.Call(ctx, Path_Editor_AnalyzeFlow, req, resp); err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+func (c *EditorServiceClient) CreateFlow(ctx context.Context, req *flowdeftypes.CreateFlowRequest) (*flowdeftypes.CreateFlowResponse, error) {
+	resp := &flowdeftypes.CreateFlowResponse{}
+	if err := c.client.Call(ctx, Path_Editor_CreateFlow, req, resp); err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+func (c *EditorServiceClient) UpdateFlow(ctx context.Context, req *flowdeftypes.CreateFlowRequest) (*flowdeftypes.CreateFlowResponse, error) {
+	resp := &flowdeftypes.CreateFlowResponse{}
+	if err := c.client.Call(ctx, Path_Editor_UpdateFlow, req, resp); err != nil {
+		return nil, err
 	}
+	return resp, nil
 }
 
 type QueryServiceServer struct {
@@ -132,6 +269,7 @@ const QueryServicePathPrefix = "/api/flow/def/query/"
 
 const Path_Query_GetFlowByID = "/api/flow/def/query/GetFlowByID"
 const Path_Query_GetFlowByParam = "/api/flow/def/query/GetFlowByParam"
+const Path_Query_ListFlows = "/api/flow/def/query/ListFlows"
 
 func (s *QueryServiceServer) PathPrefix() string {
 	return QueryServicePathPrefix
@@ -151,12 +289,12 @@ func (s *QueryServiceServer) ServeHTTP(resp http.ResponseWriter, req *http.Reque
 		httprpc.WriteError(ctx, resp, hooks, *info, err)
 		return
 	}
-	serve, err := httprpc.ParseRequestHeader(req)
+	reqMsg, exec, allowGET, err := s.parseRoute(req.URL.Path, hooks, info)
 	if err != nil {
 		httprpc.WriteError(ctx, resp, hooks, *info, err)
 		return
 	}
-	reqMsg, exec, err := s.parseRoute(req.URL.Path, hooks, info)
+	serve, err := httprpc.ParseRequestHeader(req, allowGET)
 	if err != nil {
 		httprpc.WriteError(ctx, resp, hooks, *info, err)
 		return
@@ -164,7 +302,7 @@ func (s *QueryServiceServer) ServeHTTP(resp http.ResponseWriter, req *http.Reque
 	serve(ctx, resp, req, hooks, info, reqMsg, exec)
 }
 
-func (s *QueryServiceServer) parseRoute(path string, hooks httprpc.Hooks, info *httprpc.HookInfo) (reqMsg httprpc.Message, _ httprpc.ExecFunc, _ error) {
+func (s *QueryServiceServer) parseRoute(path string, hooks httprpc.Hooks, info *httprpc.HookInfo) (reqMsg httprpc.Message, _ httprpc.ExecFunc, allowGET bool, _ error) {
 	switch path {
 	case "/api/flow/def/query/GetFlowByID":
 		msg := &flowdeftypes.GetFlowByIDRequest{}
@@ -175,10 +313,13 @@ func (s *QueryServiceServer) parseRoute(path string, hooks httprpc.Hooks, info *
 			if err != nil {
 				return
 			}
+			if err = msg.Validate(); err != nil {
+				return
+			}
 			resp, err = inner.GetFlowByID(newCtx, msg)
 			return
 		}
-		return msg, fn, nil
+		return msg, fn, true, nil
 	case "/api/flow/def/query/GetFlowByParam":
 		msg := &flowdeftypes.GetFlowByParamRequest{}
 		fn := func(ctx context.Context) (newCtx context.Context, resp httprpc.Message, err error) {
@@ -191,9 +332,62 @@ func (s *QueryServiceServer) parseRoute(path string, hooks httprpc.Hooks, info *
 			resp, err = inner.GetFlowByParam(newCtx, msg)
 			return
 		}
-		return msg, fn, nil
+		return msg, fn, true, nil
+	case "/api/flow/def/query/ListFlows":
+		msg := &flowdeftypes.ListFlowsRequest{}
+		fn := func(ctx context.Context) (newCtx context.Context, resp httprpc.Message, err error) {
+			inner := s.builder()
+			info.Request, info.Inner = msg, inner
+			newCtx, err = hooks.RequestRouted(ctx, *info)
+			if err != nil {
+				return
+			}
+			if err = msg.Validate(); err != nil {
+				return
+			}
+			resp, err = inner.ListFlows(newCtx, msg)
+			return
+		}
+		return msg, fn, true, nil
 	default:
 		msg := fmt.Sprintf("no handler for path %q", path)
-		return nil, nil, httprpc.BadRouteError(msg, "POST", path)
+		return nil, nil, false, httprpc.BadRouteError(msg, "POST", path)
+	}
+}
+
+// QueryServiceClient calls QueryService on the server at baseURL.
+type QueryServiceClient struct {
+	client *httprpc.Client
+}
+
+var _ QueryService = &QueryServiceClient{}
+
+func NewQueryServiceClient(baseURL string, httpClient *http.Client, hooks ...httprpc.ClientHooks) *QueryServiceClient {
+	return &QueryServiceClient{
+		client: httprpc.NewClient(baseURL, httpClient, hooks...),
+	}
+}
+
+func (c *QueryServiceClient) GetFlowByID(ctx context.Context, req *flowdeftypes.GetFlowByIDRequest) (*flowdeftypes.FlowResponse, error) {
+	resp := &flowdeftypes.FlowResponse{}
+	if err := c.client.Call(ctx, Path_Query_GetFlowByID, req, resp); err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+func (c *QueryServiceClient) GetFlowByParam(ctx context.Context, req *flowdeftypes.GetFlowByParamRequest) (*flowdeftypes.FlowResponse, error) {
+	resp := &flowdeftypes.FlowResponse{}
+	if err := c.client.Call(ctx, Path_Query_GetFlowByParam, req, resp); err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+func (c *QueryServiceClient) ListFlows(ctx context.Context, req *flowdeftypes.ListFlowsRequest) (*flowdeftypes.ListFlowsResponse, error) {
+	resp := &flowdeftypes.ListFlowsResponse{}
+	if err := c.client.Call(ctx, Path_Query_ListFlows, req, resp); err != nil {
+		return nil, err
 	}
+	return resp, nil
 }
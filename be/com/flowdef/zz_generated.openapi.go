@@ -0,0 +1,1776 @@
+// +build !generator
+
+// Code generated by generator openapi. DO NOT EDIT.
+
+package flowdef
+
+import (
+	openapi "github.com/olvrng/rbot/be/pkg/openapi"
+)
+
+func init() {
+	openapi.Register(openAPIFragment)
+}
+
+const openAPIFragment = `{
+  "paths": {
+    "/api/flow/def/editor/AnalyzeFlow": {
+      "post": {
+        "operationId": "Editor_AnalyzeFlow",
+        "tags": [
+          "EditorService"
+        ],
+        "requestBody": {
+          "required": true,
+          "content": {
+            "application/json": {
+              "schema": {
+                "$ref": "#/components/schemas/flowdeftypes.AnalyzeFlowRequest"
+              }
+            },
+            "application/protobuf": {
+              "schema": {
+                "type": "string",
+                "format": "binary"
+              }
+            },
+            "application/x-www-form-urlencoded": {
+              "schema": {
+                "$ref": "#/components/schemas/flowdeftypes.AnalyzeFlowRequest"
+              }
+            }
+          }
+        },
+        "responses": {
+          "200": {
+            "description": "OK",
+            "content": {
+              "application/json": {
+                "schema": {
+                  "$ref": "#/components/schemas/flowdeftypes.AnalyzeFlowResponse"
+                }
+              },
+              "application/protobuf": {
+                "schema": {
+                  "type": "string",
+                  "format": "binary"
+                }
+              },
+              "application/x-www-form-urlencoded": {
+                "schema": {
+                  "$ref": "#/components/schemas/flowdeftypes.AnalyzeFlowResponse"
+                }
+              }
+            }
+          },
+          "default": {
+            "description": "Error",
+            "content": {
+              "application/json": {
+                "schema": {
+                  "$ref": "#/components/schemas/Error"
+                }
+              }
+            }
+          }
+        }
+      }
+    },
+    "/api/flow/def/editor/CreateFlow": {
+      "post": {
+        "operationId": "Editor_CreateFlow",
+        "tags": [
+          "EditorService"
+        ],
+        "requestBody": {
+          "required": true,
+          "content": {
+            "application/json": {
+              "schema": {
+                "$ref": "#/components/schemas/flowdeftypes.CreateFlowRequest"
+              }
+            },
+            "application/protobuf": {
+              "schema": {
+                "type": "string",
+                "format": "binary"
+              }
+            },
+            "application/x-www-form-urlencoded": {
+              "schema": {
+                "$ref": "#/components/schemas/flowdeftypes.CreateFlowRequest"
+              }
+            }
+          }
+        },
+        "responses": {
+          "200": {
+            "description": "OK",
+            "content": {
+              "application/json": {
+                "schema": {
+                  "$ref": "#/components/schemas/flowdeftypes.CreateFlowResponse"
+                }
+              },
+              "application/protobuf": {
+                "schema": {
+                  "type": "string",
+                  "format": "binary"
+                }
+              },
+              "application/x-www-form-urlencoded": {
+                "schema": {
+                  "$ref": "#/components/schemas/flowdeftypes.CreateFlowResponse"
+                }
+              }
+            }
+          },
+          "default": {
+            "description": "Error",
+            "content": {
+              "application/json": {
+                "schema": {
+                  "$ref": "#/components/schemas/Error"
+                }
+              }
+            }
+          }
+        }
+      }
+    },
+    "/api/flow/def/editor/UpdateFlow": {
+      "post": {
+        "operationId": "Editor_UpdateFlow",
+        "tags": [
+          "EditorService"
+        ],
+        "requestBody": {
+          "required": true,
+          "content": {
+            "application/json": {
+              "schema": {
+                "$ref": "#/components/schemas/flowdeftypes.CreateFlowRequest"
+              }
+            },
+            "application/protobuf": {
+              "schema": {
+                "type": "string",
+                "format": "binary"
+              }
+            },
+            "application/x-www-form-urlencoded": {
+              "schema": {
+                "$ref": "#/components/schemas/flowdeftypes.CreateFlowRequest"
+              }
+            }
+          }
+        },
+        "responses": {
+          "200": {
+            "description": "OK",
+            "content": {
+              "application/json": {
+                "schema": {
+                  "$ref": "#/components/schemas/flowdeftypes.CreateFlowResponse"
+                }
+              },
+              "application/protobuf": {
+                "schema": {
+                  "type": "string",
+                  "format": "binary"
+                }
+              },
+              "application/x-www-form-urlencoded": {
+                "schema": {
+                  "$ref": "#/components/schemas/flowdeftypes.CreateFlowResponse"
+                }
+              }
+            }
+          },
+          "default": {
+            "description": "Error",
+            "content": {
+              "application/json": {
+                "schema": {
+                  "$ref": "#/components/schemas/Error"
+                }
+              }
+            }
+          }
+        }
+      }
+    },
+    "/api/flow/def/query/GetFlowByID": {
+      "get": {
+        "operationId": "Query_GetFlowByID_GET",
+        "tags": [
+          "QueryService"
+        ],
+        "parameters": [
+          {
+            "name": "id",
+            "in": "query",
+            "schema": {
+              "type": "string",
+              "format": "int64"
+            }
+          }
+        ],
+        "responses": {
+          "200": {
+            "description": "OK",
+            "content": {
+              "application/json": {
+                "schema": {
+                  "$ref": "#/components/schemas/flowdeftypes.FlowResponse"
+                }
+              },
+              "application/protobuf": {
+                "schema": {
+                  "type": "string",
+                  "format": "binary"
+                }
+              },
+              "application/x-www-form-urlencoded": {
+                "schema": {
+                  "$ref": "#/components/schemas/flowdeftypes.FlowResponse"
+                }
+              }
+            }
+          },
+          "default": {
+            "description": "Error",
+            "content": {
+              "application/json": {
+                "schema": {
+                  "$ref": "#/components/schemas/Error"
+                }
+              }
+            }
+          }
+        }
+      },
+      "post": {
+        "operationId": "Query_GetFlowByID",
+        "tags": [
+          "QueryService"
+        ],
+        "requestBody": {
+          "required": true,
+          "content": {
+            "application/json": {
+              "schema": {
+                "$ref": "#/components/schemas/flowdeftypes.GetFlowByIDRequest"
+              }
+            },
+            "application/protobuf": {
+              "schema": {
+                "type": "string",
+                "format": "binary"
+              }
+            },
+            "application/x-www-form-urlencoded": {
+              "schema": {
+                "$ref": "#/components/schemas/flowdeftypes.GetFlowByIDRequest"
+              }
+            }
+          }
+        },
+        "responses": {
+          "200": {
+            "description": "OK",
+            "content": {
+              "application/json": {
+                "schema": {
+                  "$ref": "#/components/schemas/flowdeftypes.FlowResponse"
+                }
+              },
+              "application/protobuf": {
+                "schema": {
+                  "type": "string",
+                  "format": "binary"
+                }
+              },
+              "application/x-www-form-urlencoded": {
+                "schema": {
+                  "$ref": "#/components/schemas/flowdeftypes.FlowResponse"
+                }
+              }
+            }
+          },
+          "default": {
+            "description": "Error",
+            "content": {
+              "application/json": {
+                "schema": {
+                  "$ref": "#/components/schemas/Error"
+                }
+              }
+            }
+          }
+        }
+      }
+    },
+    "/api/flow/def/query/GetFlowByParam": {
+      "get": {
+        "operationId": "Query_GetFlowByParam_GET",
+        "tags": [
+          "QueryService"
+        ],
+        "parameters": [
+          {
+            "name": "id",
+            "in": "query",
+            "schema": {
+              "type": "string",
+              "format": "int64"
+            }
+          }
+        ],
+        "responses": {
+          "200": {
+            "description": "OK",
+            "content": {
+              "application/json": {
+                "schema": {
+                  "$ref": "#/components/schemas/flowdeftypes.FlowResponse"
+                }
+              },
+              "application/protobuf": {
+                "schema": {
+                  "type": "string",
+                  "format": "binary"
+                }
+              },
+              "application/x-www-form-urlencoded": {
+                "schema": {
+                  "$ref": "#/components/schemas/flowdeftypes.FlowResponse"
+                }
+              }
+            }
+          },
+          "default": {
+            "description": "Error",
+            "content": {
+              "application/json": {
+                "schema": {
+                  "$ref": "#/components/schemas/Error"
+                }
+              }
+            }
+          }
+        }
+      },
+      "post": {
+        "operationId": "Query_GetFlowByParam",
+        "tags": [
+          "QueryService"
+        ],
+        "requestBody": {
+          "required": true,
+          "content": {
+            "application/json": {
+              "schema": {
+                "$ref": "#/components/schemas/flowdeftypes.GetFlowByParamRequest"
+              }
+            },
+            "application/protobuf": {
+              "schema": {
+                "type": "string",
+                "format": "binary"
+              }
+            },
+            "application/x-www-form-urlencoded": {
+              "schema": {
+                "$ref": "#/components/schemas/flowdeftypes.GetFlowByParamRequest"
+              }
+            }
+          }
+        },
+        "responses": {
+          "200": {
+            "description": "OK",
+            "content": {
+              "application/json": {
+                "schema": {
+                  "$ref": "#/components/schemas/flowdeftypes.FlowResponse"
+                }
+              },
+              "application/protobuf": {
+                "schema": {
+                  "type": "string",
+                  "format": "binary"
+                }
+              },
+              "application/x-www-form-urlencoded": {
+                "schema": {
+                  "$ref": "#/components/schemas/flowdeftypes.FlowResponse"
+                }
+              }
+            }
+          },
+          "default": {
+            "description": "Error",
+            "content": {
+              "application/json": {
+                "schema": {
+                  "$ref": "#/components/schemas/Error"
+                }
+              }
+            }
+          }
+        }
+      }
+    },
+    "/api/flow/def/query/ListFlows": {
+      "get": {
+        "operationId": "Query_ListFlows_GET",
+        "tags": [
+          "QueryService"
+        ],
+        "parameters": [
+          {
+            "name": "page_id",
+            "in": "query",
+            "schema": {
+              "type": "string",
+              "format": "int64"
+            }
+          }
+        ],
+        "responses": {
+          "200": {
+            "description": "OK",
+            "content": {
+              "application/json": {
+                "schema": {
+                  "$ref": "#/components/schemas/flowdeftypes.ListFlowsResponse"
+                }
+              },
+              "application/protobuf": {
+                "schema": {
+                  "type": "string",
+                  "format": "binary"
+                }
+              },
+              "application/x-www-form-urlencoded": {
+                "schema": {
+                  "$ref": "#/components/schemas/flowdeftypes.ListFlowsResponse"
+                }
+              }
+            }
+          },
+          "default": {
+            "description": "Error",
+            "content": {
+              "application/json": {
+                "schema": {
+                  "$ref": "#/components/schemas/Error"
+                }
+              }
+            }
+          }
+        }
+      },
+      "post": {
+        "operationId": "Query_ListFlows",
+        "tags": [
+          "QueryService"
+        ],
+        "requestBody": {
+          "required": true,
+          "content": {
+            "application/json": {
+              "schema": {
+                "$ref": "#/components/schemas/flowdeftypes.ListFlowsRequest"
+              }
+            },
+            "application/protobuf": {
+              "schema": {
+                "type": "string",
+                "format": "binary"
+              }
+            },
+            "application/x-www-form-urlencoded": {
+              "schema": {
+                "$ref": "#/components/schemas/flowdeftypes.ListFlowsRequest"
+              }
+            }
+          }
+        },
+        "responses": {
+          "200": {
+            "description": "OK",
+            "content": {
+              "application/json": {
+                "schema": {
+                  "$ref": "#/components/schemas/flowdeftypes.ListFlowsResponse"
+                }
+              },
+              "application/protobuf": {
+                "schema": {
+                  "type": "string",
+                  "format": "binary"
+                }
+              },
+              "application/x-www-form-urlencoded": {
+                "schema": {
+                  "$ref": "#/components/schemas/flowdeftypes.ListFlowsResponse"
+                }
+              }
+            }
+          },
+          "default": {
+            "description": "Error",
+            "content": {
+              "application/json": {
+                "schema": {
+                  "$ref": "#/components/schemas/Error"
+                }
+              }
+            }
+          }
+        }
+      }
+    }
+  },
+  "components": {
+    "schemas": {
+      "flowdeftypes.AnalyzeFlowRequest": {
+        "type": "object",
+        "properties": {
+          "flow": {
+            "$ref": "#/components/schemas/flowdeftypes.Flow"
+          },
+          "flow_id": {
+            "type": "string",
+            "format": "int64"
+          },
+          "format": {
+            "$ref": "#/components/schemas/flowdeftypes.GraphFormat"
+          }
+        },
+        "required": [
+          "flow_id",
+          "format"
+        ]
+      },
+      "flowdeftypes.AnalyzeFlowResponse": {
+        "type": "object",
+        "properties": {
+          "graph": {
+            "type": "string"
+          },
+          "report": {
+            "$ref": "#/components/schemas/flowdeftypes.FlowReport"
+          }
+        }
+      },
+      "flowdeftypes.AskRatingNodeData": {
+        "type": "object",
+        "properties": {
+          "comment_template": {
+            "type": "string"
+          },
+          "next_id": {
+            "type": "string",
+            "format": "int64"
+          },
+          "retry_template": {
+            "type": "string"
+          },
+          "skip_text": {
+            "type": "string"
+          },
+          "template": {
+            "type": "string"
+          },
+          "timeout": {
+            "type": "string",
+            "description": "such as 30m, 24h or 3d"
+          },
+          "timeout_next_id": {
+            "type": "string",
+            "format": "int64"
+          },
+          "type": {
+            "$ref": "#/components/schemas/flowdeftypes.NodeType"
+          }
+        },
+        "required": [
+          "type",
+          "template"
+        ]
+      },
+      "flowdeftypes.Assignment": {
+        "type": "object",
+        "properties": {
+          "expr": {
+            "type": "string"
+          },
+          "var_type": {
+            "$ref": "#/components/schemas/flowdeftypes.VarType"
+          },
+          "variable": {
+            "type": "string"
+          }
+        },
+        "required": [
+          "variable",
+          "expr"
+        ]
+      },
+      "flowdeftypes.BrokenLink": {
+        "type": "object",
+        "properties": {
+          "link": {
+            "$ref": "#/components/schemas/flowdeftypes.Link"
+          },
+          "node_id": {
+            "type": "string",
+            "format": "int64"
+          }
+        },
+        "required": [
+          "node_id"
+        ]
+      },
+      "flowdeftypes.CaptureInputNodeData": {
+        "type": "object",
+        "properties": {
+          "next_id": {
+            "type": "string",
+            "format": "int64"
+          },
+          "pattern": {
+            "type": "string"
+          },
+          "retry_template": {
+            "type": "string"
+          },
+          "template": {
+            "type": "string"
+          },
+          "timeout": {
+            "type": "string",
+            "description": "such as 30m, 24h or 3d"
+          },
+          "timeout_next_id": {
+            "type": "string",
+            "format": "int64"
+          },
+          "type": {
+            "$ref": "#/components/schemas/flowdeftypes.NodeType"
+          },
+          "validation": {
+            "$ref": "#/components/schemas/flowdeftypes.InputValidation"
+          },
+          "variable": {
+            "type": "string"
+          }
+        },
+        "required": [
+          "type",
+          "template",
+          "variable"
+        ]
+      },
+      "flowdeftypes.CompletedOrderNodeData": {
+        "type": "object",
+        "properties": {
+          "fields": {
+            "type": "array",
+            "items": {
+              "type": "string"
+            }
+          },
+          "next_id": {
+            "type": "string",
+            "format": "int64"
+          },
+          "type": {
+            "$ref": "#/components/schemas/flowdeftypes.NodeType"
+          }
+        },
+        "required": [
+          "type",
+          "fields"
+        ]
+      },
+      "flowdeftypes.ConditionNodeData": {
+        "type": "object",
+        "properties": {
+          "next_id": {
+            "type": "string",
+            "format": "int64"
+          },
+          "rules": {
+            "type": "array",
+            "items": {
+              "$ref": "#/components/schemas/flowdeftypes.ConditionRule"
+            }
+          },
+          "type": {
+            "$ref": "#/components/schemas/flowdeftypes.NodeType"
+          }
+        },
+        "required": [
+          "type",
+          "rules"
+        ]
+      },
+      "flowdeftypes.ConditionOp": {
+        "type": "string",
+        "enum": [
+          "contains",
+          "empty",
+          "eq",
+          "gt",
+          "gte",
+          "lt",
+          "lte",
+          "matches",
+          "ne",
+          "not_empty"
+        ]
+      },
+      "flowdeftypes.ConditionRule": {
+        "type": "object",
+        "properties": {
+          "next_id": {
+            "type": "string",
+            "format": "int64"
+          },
+          "op": {
+            "$ref": "#/components/schemas/flowdeftypes.ConditionOp"
+          },
+          "value": {
+            "type": "string"
+          },
+          "variable": {
+            "type": "string"
+          }
+        },
+        "required": [
+          "variable",
+          "op",
+          "next_id"
+        ]
+      },
+      "flowdeftypes.CreateFlowRequest": {
+        "type": "object",
+        "properties": {
+          "flow": {
+            "$ref": "#/components/schemas/flowdeftypes.Flow"
+          }
+        }
+      },
+      "flowdeftypes.CreateFlowResponse": {
+        "type": "object",
+        "properties": {
+          "flow": {
+            "$ref": "#/components/schemas/flowdeftypes.Flow"
+          }
+        }
+      },
+      "flowdeftypes.Flow": {
+        "type": "object",
+        "properties": {
+          "entry_points": {
+            "type": "object",
+            "additionalProperties": {
+              "type": "string",
+              "format": "int64"
+            }
+          },
+          "fallback_node_id": {
+            "type": "string",
+            "format": "int64"
+          },
+          "id": {
+            "type": "string",
+            "format": "int64"
+          },
+          "intents": {
+            "type": "array",
+            "items": {
+              "$ref": "#/components/schemas/flowdeftypes.Intent"
+            }
+          },
+          "keywords": {
+            "type": "array",
+            "items": {
+              "$ref": "#/components/schemas/flowdeftypes.KeywordEntry"
+            }
+          },
+          "nodes": {
+            "type": "array",
+            "items": {
+              "$ref": "#/components/schemas/flowdeftypes.Node"
+            }
+          },
+          "page_ids": {
+            "type": "array",
+            "items": {
+              "type": "string",
+              "format": "int64"
+            }
+          },
+          "priority": {
+            "type": "integer"
+          },
+          "restart_policy": {
+            "$ref": "#/components/schemas/flowdeftypes.RestartPolicy"
+          },
+          "workspace_id": {
+            "type": "string",
+            "format": "int64"
+          }
+        },
+        "required": [
+          "id",
+          "page_ids",
+          "nodes"
+        ]
+      },
+      "flowdeftypes.FlowCycle": {
+        "type": "object",
+        "properties": {
+          "node_ids": {
+            "type": "array",
+            "items": {
+              "type": "string",
+              "format": "int64"
+            }
+          },
+          "waiting": {
+            "type": "boolean"
+          }
+        },
+        "required": [
+          "node_ids",
+          "waiting"
+        ]
+      },
+      "flowdeftypes.FlowReport": {
+        "type": "object",
+        "properties": {
+          "broken_links": {
+            "type": "array",
+            "items": {
+              "$ref": "#/components/schemas/flowdeftypes.BrokenLink"
+            }
+          },
+          "cycles": {
+            "type": "array",
+            "items": {
+              "$ref": "#/components/schemas/flowdeftypes.FlowCycle"
+            }
+          },
+          "dead_ends": {
+            "type": "array",
+            "items": {
+              "type": "string",
+              "format": "int64"
+            }
+          },
+          "link_count": {
+            "type": "integer"
+          },
+          "longest_path": {
+            "type": "array",
+            "items": {
+              "type": "string",
+              "format": "int64"
+            }
+          },
+          "node_count": {
+            "type": "integer"
+          },
+          "unreachable": {
+            "type": "array",
+            "items": {
+              "type": "string",
+              "format": "int64"
+            }
+          }
+        },
+        "required": [
+          "node_count",
+          "link_count",
+          "cycles",
+          "dead_ends",
+          "unreachable",
+          "broken_links",
+          "longest_path"
+        ]
+      },
+      "flowdeftypes.FlowResponse": {
+        "type": "object",
+        "properties": {
+          "flow": {
+            "$ref": "#/components/schemas/flowdeftypes.Flow"
+          }
+        }
+      },
+      "flowdeftypes.GetFlowByIDRequest": {
+        "type": "object",
+        "properties": {
+          "id": {
+            "type": "string",
+            "format": "int64"
+          }
+        },
+        "required": [
+          "id"
+        ]
+      },
+      "flowdeftypes.GetFlowByParamRequest": {
+        "type": "object",
+        "properties": {
+          "id": {
+            "type": "string",
+            "format": "int64"
+          }
+        },
+        "required": [
+          "id"
+        ]
+      },
+      "flowdeftypes.GotoFlowNodeData": {
+        "type": "object",
+        "properties": {
+          "flow_id": {
+            "type": "string",
+            "format": "int64"
+          },
+          "node_id": {
+            "type": "string",
+            "format": "int64"
+          },
+          "type": {
+            "$ref": "#/components/schemas/flowdeftypes.NodeType"
+          }
+        },
+        "required": [
+          "type",
+          "flow_id",
+          "node_id"
+        ]
+      },
+      "flowdeftypes.GraphFormat": {
+        "type": "string",
+        "enum": [
+          "dot",
+          "mermaid"
+        ]
+      },
+      "flowdeftypes.HTTPRequestNodeData": {
+        "type": "object",
+        "properties": {
+          "body": {
+            "type": "string"
+          },
+          "error_next_id": {
+            "type": "string",
+            "format": "int64"
+          },
+          "headers": {
+            "type": "object",
+            "additionalProperties": {
+              "type": "string"
+            }
+          },
+          "method": {
+            "type": "string"
+          },
+          "next_id": {
+            "type": "string",
+            "format": "int64"
+          },
+          "response_vars": {
+            "type": "array",
+            "items": {
+              "$ref": "#/components/schemas/flowdeftypes.ResponseVar"
+            }
+          },
+          "retries": {
+            "type": "integer"
+          },
+          "status_routes": {
+            "type": "array",
+            "items": {
+              "$ref": "#/components/schemas/flowdeftypes.StatusRoute"
+            }
+          },
+          "status_variable": {
+            "type": "string"
+          },
+          "timeout": {
+            "type": "string",
+            "description": "such as 30m, 24h or 3d"
+          },
+          "type": {
+            "$ref": "#/components/schemas/flowdeftypes.NodeType"
+          },
+          "url": {
+            "type": "string"
+          }
+        },
+        "required": [
+          "type",
+          "url"
+        ]
+      },
+      "flowdeftypes.HandoffNodeData": {
+        "type": "object",
+        "properties": {
+          "next_id": {
+            "type": "string",
+            "format": "int64"
+          },
+          "pass_thread_control": {
+            "type": "boolean"
+          },
+          "reason": {
+            "type": "string"
+          },
+          "target_app_id": {
+            "type": "string",
+            "format": "int64"
+          },
+          "template": {
+            "type": "string"
+          },
+          "type": {
+            "$ref": "#/components/schemas/flowdeftypes.NodeType"
+          }
+        },
+        "required": [
+          "type"
+        ]
+      },
+      "flowdeftypes.InputValidation": {
+        "type": "string",
+        "enum": [
+          "",
+          "email",
+          "number",
+          "phone",
+          "regex"
+        ]
+      },
+      "flowdeftypes.Intent": {
+        "type": "object",
+        "properties": {
+          "examples": {
+            "type": "array",
+            "items": {
+              "type": "string"
+            }
+          },
+          "name": {
+            "type": "string"
+          }
+        },
+        "required": [
+          "name",
+          "examples"
+        ]
+      },
+      "flowdeftypes.KeywordEntry": {
+        "type": "object",
+        "properties": {
+          "keywords": {
+            "type": "array",
+            "items": {
+              "type": "string"
+            }
+          },
+          "next_id": {
+            "type": "string",
+            "format": "int64"
+          }
+        },
+        "required": [
+          "keywords",
+          "next_id"
+        ]
+      },
+      "flowdeftypes.KeywordMatch": {
+        "type": "string",
+        "enum": [
+          "any",
+          "exact",
+          "intent",
+          "regex"
+        ]
+      },
+      "flowdeftypes.KeywordNodeData": {
+        "type": "object",
+        "properties": {
+          "next_id": {
+            "type": "string",
+            "format": "int64"
+          },
+          "rules": {
+            "type": "array",
+            "items": {
+              "$ref": "#/components/schemas/flowdeftypes.KeywordRule"
+            }
+          },
+          "type": {
+            "$ref": "#/components/schemas/flowdeftypes.NodeType"
+          }
+        },
+        "required": [
+          "type",
+          "rules"
+        ]
+      },
+      "flowdeftypes.KeywordRule": {
+        "type": "object",
+        "properties": {
+          "intent": {
+            "type": "string"
+          },
+          "match": {
+            "$ref": "#/components/schemas/flowdeftypes.KeywordMatch"
+          },
+          "min_score": {
+            "type": "number"
+          },
+          "pattern": {
+            "type": "string"
+          },
+          "priority": {
+            "type": "integer"
+          },
+          "values": {
+            "type": "array",
+            "items": {
+              "type": "string"
+            }
+          }
+        },
+        "required": [
+          "match"
+        ]
+      },
+      "flowdeftypes.Link": {
+        "type": "object",
+        "properties": {
+          "label": {
+            "type": "string"
+          },
+          "next_id": {
+            "type": "string",
+            "format": "int64"
+          }
+        },
+        "required": [
+          "next_id"
+        ]
+      },
+      "flowdeftypes.ListFlowsRequest": {
+        "type": "object",
+        "properties": {
+          "page_id": {
+            "type": "string",
+            "format": "int64"
+          }
+        },
+        "required": [
+          "page_id"
+        ]
+      },
+      "flowdeftypes.ListFlowsResponse": {
+        "type": "object",
+        "properties": {
+          "flows": {
+            "type": "array",
+            "items": {
+              "$ref": "#/components/schemas/flowdeftypes.Flow"
+            }
+          }
+        },
+        "required": [
+          "flows"
+        ]
+      },
+      "flowdeftypes.Node": {
+        "type": "object",
+        "properties": {
+          "id": {
+            "type": "string",
+            "format": "int64"
+          },
+          "payload": {
+            "$ref": "#/components/schemas/flowdeftypes.NodePayload"
+          }
+        }
+      },
+      "flowdeftypes.NodePayload": {
+        "oneOf": [
+          {
+            "allOf": [
+              {
+                "$ref": "#/components/schemas/flowdeftypes.CompletedOrderNodeData"
+              },
+              {
+                "type": "object",
+                "properties": {
+                  "type": {
+                    "type": "string",
+                    "enum": [
+                      "trigger:completed_order"
+                    ]
+                  }
+                },
+                "required": [
+                  "type"
+                ]
+              }
+            ]
+          },
+          {
+            "allOf": [
+              {
+                "$ref": "#/components/schemas/flowdeftypes.OrderEventNodeData"
+              },
+              {
+                "type": "object",
+                "properties": {
+                  "type": {
+                    "type": "string",
+                    "enum": [
+                      "trigger:order_created"
+                    ]
+                  }
+                },
+                "required": [
+                  "type"
+                ]
+              }
+            ]
+          },
+          {
+            "allOf": [
+              {
+                "$ref": "#/components/schemas/flowdeftypes.OrderEventNodeData"
+              },
+              {
+                "type": "object",
+                "properties": {
+                  "type": {
+                    "type": "string",
+                    "enum": [
+                      "trigger:order_paid"
+                    ]
+                  }
+                },
+                "required": [
+                  "type"
+                ]
+              }
+            ]
+          },
+          {
+            "allOf": [
+              {
+                "$ref": "#/components/schemas/flowdeftypes.OrderEventNodeData"
+              },
+              {
+                "type": "object",
+                "properties": {
+                  "type": {
+                    "type": "string",
+                    "enum": [
+                      "trigger:order_shipped"
+                    ]
+                  }
+                },
+                "required": [
+                  "type"
+                ]
+              }
+            ]
+          },
+          {
+            "allOf": [
+              {
+                "$ref": "#/components/schemas/flowdeftypes.OrderEventNodeData"
+              },
+              {
+                "type": "object",
+                "properties": {
+                  "type": {
+                    "type": "string",
+                    "enum": [
+                      "trigger:order_refunded"
+                    ]
+                  }
+                },
+                "required": [
+                  "type"
+                ]
+              }
+            ]
+          },
+          {
+            "allOf": [
+              {
+                "$ref": "#/components/schemas/flowdeftypes.ReceivedMessageNodeData"
+              },
+              {
+                "type": "object",
+                "properties": {
+                  "type": {
+                    "type": "string",
+                    "enum": [
+                      "trigger:received_message"
+                    ]
+                  }
+                },
+                "required": [
+                  "type"
+                ]
+              }
+            ]
+          },
+          {
+            "allOf": [
+              {
+                "$ref": "#/components/schemas/flowdeftypes.ReferralNodeData"
+              },
+              {
+                "type": "object",
+                "properties": {
+                  "type": {
+                    "type": "string",
+                    "enum": [
+                      "trigger:referral"
+                    ]
+                  }
+                },
+                "required": [
+                  "type"
+                ]
+              }
+            ]
+          },
+          {
+            "allOf": [
+              {
+                "$ref": "#/components/schemas/flowdeftypes.KeywordNodeData"
+              },
+              {
+                "type": "object",
+                "properties": {
+                  "type": {
+                    "type": "string",
+                    "enum": [
+                      "trigger:keyword"
+                    ]
+                  }
+                },
+                "required": [
+                  "type"
+                ]
+              }
+            ]
+          },
+          {
+            "allOf": [
+              {
+                "$ref": "#/components/schemas/flowdeftypes.SendMessageNodeData"
+              },
+              {
+                "type": "object",
+                "properties": {
+                  "type": {
+                    "type": "string",
+                    "enum": [
+                      "action:send_message"
+                    ]
+                  }
+                },
+                "required": [
+                  "type"
+                ]
+              }
+            ]
+          },
+          {
+            "allOf": [
+              {
+                "$ref": "#/components/schemas/flowdeftypes.CaptureInputNodeData"
+              },
+              {
+                "type": "object",
+                "properties": {
+                  "type": {
+                    "type": "string",
+                    "enum": [
+                      "action:capture_input"
+                    ]
+                  }
+                },
+                "required": [
+                  "type"
+                ]
+              }
+            ]
+          },
+          {
+            "allOf": [
+              {
+                "$ref": "#/components/schemas/flowdeftypes.SetVariableNodeData"
+              },
+              {
+                "type": "object",
+                "properties": {
+                  "type": {
+                    "type": "string",
+                    "enum": [
+                      "action:set_variable"
+                    ]
+                  }
+                },
+                "required": [
+                  "type"
+                ]
+              }
+            ]
+          },
+          {
+            "allOf": [
+              {
+                "$ref": "#/components/schemas/flowdeftypes.ConditionNodeData"
+              },
+              {
+                "type": "object",
+                "properties": {
+                  "type": {
+                    "type": "string",
+                    "enum": [
+                      "action:condition"
+                    ]
+                  }
+                },
+                "required": [
+                  "type"
+                ]
+              }
+            ]
+          },
+          {
+            "allOf": [
+              {
+                "$ref": "#/components/schemas/flowdeftypes.AskRatingNodeData"
+              },
+              {
+                "type": "object",
+                "properties": {
+                  "type": {
+                    "type": "string",
+                    "enum": [
+                      "action:ask_rating"
+                    ]
+                  }
+                },
+                "required": [
+                  "type"
+                ]
+              }
+            ]
+          },
+          {
+            "allOf": [
+              {
+                "$ref": "#/components/schemas/flowdeftypes.WaitNodeData"
+              },
+              {
+                "type": "object",
+                "properties": {
+                  "type": {
+                    "type": "string",
+                    "enum": [
+                      "action:wait"
+                    ]
+                  }
+                },
+                "required": [
+                  "type"
+                ]
+              }
+            ]
+          },
+          {
+            "allOf": [
+              {
+                "$ref": "#/components/schemas/flowdeftypes.HandoffNodeData"
+              },
+              {
+                "type": "object",
+                "properties": {
+                  "type": {
+                    "type": "string",
+                    "enum": [
+                      "action:handoff"
+                    ]
+                  }
+                },
+                "required": [
+                  "type"
+                ]
+              }
+            ]
+          },
+          {
+            "allOf": [
+              {
+                "$ref": "#/components/schemas/flowdeftypes.HTTPRequestNodeData"
+              },
+              {
+                "type": "object",
+                "properties": {
+                  "type": {
+                    "type": "string",
+                    "enum": [
+                      "action:http_request"
+                    ]
+                  }
+                },
+                "required": [
+                  "type"
+                ]
+              }
+            ]
+          },
+          {
+            "allOf": [
+              {
+                "$ref": "#/components/schemas/flowdeftypes.GotoFlowNodeData"
+              },
+              {
+                "type": "object",
+                "properties": {
+                  "type": {
+                    "type": "string",
+                    "enum": [
+                      "action:goto_flow"
+                    ]
+                  }
+                },
+                "required": [
+                  "type"
+                ]
+              }
+            ]
+          }
+        ]
+      },
+      "flowdeftypes.NodeType": {
+        "type": "string"
+      },
+      "flowdeftypes.OrderEventNodeData": {
+        "type": "object",
+        "properties": {
+          "next_id": {
+            "type": "string",
+            "format": "int64"
+          },
+          "type": {
+            "$ref": "#/components/schemas/flowdeftypes.NodeType"
+          }
+        },
+        "required": [
+          "type"
+        ]
+      },
+      "flowdeftypes.QuickReplyItem": {
+        "type": "object",
+        "properties": {
+          "code": {
+            "type": "string"
+          },
+          "next_id": {
+            "type": "string",
+            "format": "int64"
+          },
+          "text": {
+            "type": "string"
+          }
+        },
+        "required": [
+          "text",
+          "code",
+          "next_id"
+        ]
+      },
+      "flowdeftypes.ReceivedMessageNodeData": {
+        "type": "object",
+        "properties": {
+          "next_id": {
+            "type": "string",
+            "format": "int64"
+          },
+          "type": {
+            "$ref": "#/components/schemas/flowdeftypes.NodeType"
+          }
+        },
+        "required": [
+          "type",
+          "next_id"
+        ]
+      },
+      "flowdeftypes.ReferralNodeData": {
+        "type": "object",
+        "properties": {
+          "next_id": {
+            "type": "string",
+            "format": "int64"
+          },
+          "ref": {
+            "type": "string"
+          },
+          "type": {
+            "$ref": "#/components/schemas/flowdeftypes.NodeType"
+          }
+        },
+        "required": [
+          "type",
+          "next_id"
+        ]
+      },
+      "flowdeftypes.ResponseVar": {
+        "type": "object",
+        "properties": {
+          "path": {
+            "type": "string"
+          },
+          "var_type": {
+            "$ref": "#/components/schemas/flowdeftypes.VarType"
+          },
+          "variable": {
+            "type": "string"
+          }
+        },
+        "required": [
+          "variable",
+          "path"
+        ]
+      },
+      "flowdeftypes.RestartPolicy": {
+        "type": "string",
+        "enum": [
+          "",
+          "reset_vars"
+        ]
+      },
+      "flowdeftypes.SendMessageNodeData": {
+        "type": "object",
+        "properties": {
+          "next_id": {
+            "type": "string",
+            "format": "int64"
+          },
+          "quick_replies": {
+            "type": "array",
+            "items": {
+              "$ref": "#/components/schemas/flowdeftypes.QuickReplyItem"
+            }
+          },
+          "template": {
+            "type": "string"
+          },
+          "timeout": {
+            "type": "string",
+            "description": "such as 30m, 24h or 3d"
+          },
+          "timeout_next_id": {
+            "type": "string",
+            "format": "int64"
+          },
+          "type": {
+            "$ref": "#/components/schemas/flowdeftypes.NodeType"
+          }
+        },
+        "required": [
+          "type",
+          "template",
+          "quick_replies"
+        ]
+      },
+      "flowdeftypes.SetVariableNodeData": {
+        "type": "object",
+        "properties": {
+          "assignments": {
+            "type": "array",
+            "items": {
+              "$ref": "#/components/schemas/flowdeftypes.Assignment"
+            }
+          },
+          "next_id": {
+            "type": "string",
+            "format": "int64"
+          },
+          "type": {
+            "$ref": "#/components/schemas/flowdeftypes.NodeType"
+          }
+        },
+        "required": [
+          "type",
+          "assignments"
+        ]
+      },
+      "flowdeftypes.StatusRoute": {
+        "type": "object",
+        "properties": {
+          "next_id": {
+            "type": "string",
+            "format": "int64"
+          },
+          "status": {
+            "type": "string"
+          }
+        },
+        "required": [
+          "status",
+          "next_id"
+        ]
+      },
+      "flowdeftypes.VarType": {
+        "type": "string",
+        "enum": [
+          "bool",
+          "email",
+          "number",
+          "phone",
+          "string"
+        ]
+      },
+      "flowdeftypes.WaitNodeData": {
+        "type": "object",
+        "properties": {
+          "delay": {
+            "type": "string",
+            "description": "such as 30m, 24h or 3d"
+          },
+          "next_id": {
+            "type": "string",
+            "format": "int64"
+          },
+          "type": {
+            "$ref": "#/components/schemas/flowdeftypes.NodeType"
+          }
+        },
+        "required": [
+          "type",
+          "delay"
+        ]
+      }
+    }
+  }
+}`
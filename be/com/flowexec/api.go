@@ -13,9 +13,55 @@ type MessengerService interface {
 	ReceivedMessage(ctx context.Context, req *types.ReceivedMessageRequest) (*types.ReceivedMessageResponse, error)
 
 	ReceivedPostback(ctx context.Context, req *types.ReceivedPostbackRequest) (*types.ReceivedPostbackResponse, error)
+
+	ReceivedReferral(ctx context.Context, req *types.ReceivedReferralRequest) (*types.ReceivedReferralResponse, error)
 }
 
 // +api:path=/api/flow/exec/order
 type OrderService interface {
+	ReceivedOrderEvent(ctx context.Context, req *types.ReceivedOrderEventRequest) (*types.ReceivedOrderEventResponse, error)
+
 	ReceivedCompletedOrder(ctx context.Context, req *types.ReceivedCompletedOrderRequest) (*types.ReceivedCompletedOrderResponse, error)
+
+	// +api:get
+	GetOrder(ctx context.Context, req *types.GetOrderRequest) (*types.OrderResponse, error)
+}
+
+// +api:path=/api/flow/exec/customer
+type CustomerService interface {
+	LinkCustomer(ctx context.Context, req *types.LinkCustomerRequest) (*types.CustomerLinkResponse, error)
+
+	// +api:get
+	GetCustomerLink(ctx context.Context, req *types.GetCustomerLinkRequest) (*types.CustomerLinkResponse, error)
+}
+
+// +api:path=/api/flow/exec/handoff
+type HandoffService interface {
+	StartHandoff(ctx context.Context, req *types.StartHandoffRequest) (*types.HandoffResponse, error)
+
+	// +api:get
+	ListHandoffs(ctx context.Context, req *types.ListHandoffsRequest) (*types.ListHandoffsResponse, error)
+
+	// +api:get
+	GetHandoff(ctx context.Context, req *types.GetHandoffRequest) (*types.HandoffResponse, error)
+
+	ReplyHandoff(ctx context.Context, req *types.ReplyHandoffRequest) (*types.HandoffResponse, error)
+
+	ResolveHandoff(ctx context.Context, req *types.ResolveHandoffRequest) (*types.HandoffResponse, error)
+}
+
+// +api:path=/api/flow/exec/simulator
+type SimulatorService interface {
+	SimulateFlow(ctx context.Context, req *types.SimulateFlowRequest) (*types.SimulateFlowResponse, error)
+}
+
+// +api:path=/api/flow/exec/live
+type LiveService interface {
+	// WatchEvents streams the events of the pages as they happen, such as the
+	// state transitions, the messages sent and the handoffs requested. Only the
+	// events of the pages which the principal may view are sent.
+	//
+	// +api:stream
+	// +api:get
+	WatchEvents(ctx context.Context, req *types.WatchEventsRequest, send func(*types.FlowEvent) error) error
 }
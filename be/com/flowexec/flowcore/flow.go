@@ -2,6 +2,7 @@ package flowcore
 
 import (
 	"github.com/olvrng/rbot/be/com/flowdef/types"
+	"github.com/olvrng/rbot/be/pkg/dot"
 	"github.com/olvrng/rbot/be/pkg/l"
 	"github.com/olvrng/rbot/be/pkg/xerrors"
 )
@@ -12,6 +13,39 @@ var ls = ll.Sugar()
 type Executor struct {
 	Flow  *types.Flow
 	State *FlowState
+
+	// Events are emitted while executing the flow, for the services to handle
+	// after the transition.
+	Events []*Event
+
+	// Intents classifies the messages for the intent rules of the keyword
+	// triggers. It defaults to a LocalIntentMatcher of the flow intents.
+	Intents IntentMatcher
+
+	// HTTP makes the requests of the http_request nodes. Without it, they go
+	// to their error node.
+	HTTP HTTPRequester
+
+	// vars are set by the event, see SetVar.
+	vars map[string]*Variable
+}
+
+const (
+	EventReviewSubmitted     = "review_submitted"
+	EventHandoff             = "handoff"
+	EventConversationStarted = "conversation_started"
+	EventNodeEntered         = "node_entered"
+	EventFlowCompleted       = "flow_completed"
+
+	// EventGotoFlow hands the conversation over to another flow, with the
+	// flow_id and node_id in its data.
+	EventGotoFlow = "goto_flow"
+)
+
+type Event struct {
+	Type   string
+	NodeID dot.IntID
+	Data   map[string]string
 }
 
 func NewExecutor(flow *types.Flow, state *FlowState) *Executor {
@@ -22,6 +56,19 @@ func NewExecutor(flow *types.Flow, state *FlowState) *Executor {
 	return ex
 }
 
+// NextState handles an event of the conversation. The event is handled by the
+// first of:
+//
+//  1. a keyword trigger, then a keyword of the flow, when the event is a
+//     message;
+//  2. the current node of the conversation;
+//  3. the entry point of the event type (see types.Flow.EntryPoint);
+//  4. the fallback node of the flow, when the event is a message or a reply.
+//
+// Except for the current node, the conversation restarts: the variables are
+// kept or reset according to the restart policy of the flow. A timer only
+// applies to the current node. When nothing handles the event, it returns an
+// Aborted error.
 func (ex *Executor) NextState(nodeType types.NodeType, data map[string]string) (_nextState *FlowState, _nextNodes []*types.Node, _err error) {
 
 	defer func() {
@@ -32,59 +79,188 @@ func (ex *Executor) NextState(nodeType types.NodeType, data map[string]string) (
 	}()
 
 	flow, state := ex.Flow, ex.State
-	node := flow.NodeByID(state.NodeID)
-	if node != nil {
+	if nodeType == types.NodeReceivedMessage {
+		if match := ex.matchKeywordTrigger(data["message"]); match != nil {
+			if match.intent != "" {
+				data = withValue(data, "intent", match.intent)
+			}
+			nextState, nextNodes, ok := ex.follow(ex.next(state, data, true), match.payload.NextID)
+			if ok {
+				return ex.started(match.node.ID, nextState, nextNodes)
+			}
+		}
+		if entry := flow.MatchKeyword(data["message"]); entry != nil {
+			nextState, nextNodes, ok := ex.follow(ex.next(state, data, true), entry.NextID)
+			if ok {
+				return ex.started(entry.NextID, nextState, nextNodes)
+			}
+		}
+	}
+
+	if node := flow.NodeByID(state.NodeID); node != nil {
 		nextState, nextNodes, ok := ex.execNextNodes(state, node, nodeType, data)
 		if ok {
 			return nextState, nextNodes, nil
 		}
 	}
 
-	// fallback when no node detected
-	node, fallback := mockGetNodeByType(ex.Flow, nodeType)
-	if node == nil {
-		node = fallback
+	// a timer only applies to the node which started it
+	if nodeType == types.NodeTimer {
+		return nil, nil, xerrors.Errorf(xerrors.Aborted, nil, "timer does not apply to node %v", state.NodeID)
 	}
-	if node != nil {
+
+	if node := flow.EntryPoint(nodeType); node != nil && node.ID != state.NodeID {
 		nextState, nextNodes, ok := ex.execNextNodes(state, node, nodeType, data)
 		if ok {
-			return nextState, nextNodes, nil
+			return ex.started(node.ID, nextState, nextNodes)
+		}
+	}
+
+	switch nodeType {
+	case types.NodeReceivedMessage, types.NodeReceivedReply:
+		if flow.FallbackNodeID != 0 {
+			nextState, nextNodes, ok := ex.follow(ex.next(state, data, true), flow.FallbackNodeID)
+			if ok {
+				return ex.started(flow.FallbackNodeID, nextState, nextNodes)
+			}
 		}
 	}
 
 	return nil, nil, xerrors.Errorf(xerrors.Aborted, nil, "can not execute state")
 }
 
+// started emits the conversation_started event of the transition, before the
+// events of the nodes.
+func (ex *Executor) started(nodeID dot.IntID, nextState *FlowState, nextNodes []*types.Node) (*FlowState, []*types.Node, error) {
+	event := &Event{Type: EventConversationStarted, NodeID: nodeID}
+	ex.Events = append([]*Event{event}, ex.Events...)
+	return nextState, nextNodes, nil
+}
+
+// Goto moves the conversation to the given node, for example when an agent
+// resumes the flow after a handoff. The variables are kept.
+func (ex *Executor) Goto(nodeID dot.IntID, data map[string]string) (_nextState *FlowState, _nextNodes []*types.Node, _err error) {
+	nextState, nextNodes, ok := ex.follow(ex.next(ex.State, data, false), nodeID)
+	if !ok {
+		return nil, nil, xerrors.Errorf(xerrors.Aborted, nil, "can not go to node %v", nodeID)
+	}
+	return nextState, nextNodes, nil
+}
+
+// SetVar sets a variable which comes with the event, such as the order id of an
+// order event. It survives the restart of the conversation.
+func (ex *Executor) SetVar(name string, typ types.VarType, value string) {
+	if ex.vars == nil {
+		ex.vars = map[string]*Variable{}
+	}
+	if typ == "" {
+		typ = types.VarString
+	}
+	ex.vars[name] = &Variable{Type: typ, Value: value}
+}
+
+func withValue(data map[string]string, key, value string) map[string]string {
+	result := make(map[string]string, len(data)+1)
+	for k, v := range data {
+		result[k] = v
+	}
+	result[key] = value
+	return result
+}
+
+// next returns the state for handling the event. When the conversation
+// restarts, the restart policy applies.
+func (ex *Executor) next(state *FlowState, data map[string]string, restart bool) *FlowState {
+	nextState := state.Next(data)
+	if restart && ex.Flow.RestartPolicy == types.RestartResetVars {
+		nextState.Vars = map[string]*Variable{}
+	}
+	for name, v := range ex.vars {
+		_v := *v
+		nextState.Vars[name] = &_v
+	}
+	return nextState
+}
+
+// maxSteps limits the number of nodes which do not wait for the user (such as
+// set_variable and condition) that can be passed through in one transition.
+// It prevents a misconfigured flow from looping forever.
+const maxSteps = 100
+
 func (ex *Executor) execNextNodes(state *FlowState, node *types.Node, nodeType types.NodeType, data map[string]string) (_nextState *FlowState, _nodes []*types.Node, ok bool) {
 
-	flow := ex.Flow
-	nextNodeID := node.Payload.Next(nodeType, data)
-	if nextNodeID != 0 {
-		nextNode := flow.NodeByID(nextNodeID)
-		if nextNode == nil {
-			return nil, nil, false
+	if node.ID == state.NodeID && state.Phase == PhaseDone {
+		return nil, nil, false
+	}
+	nextState := ex.next(state, data, node.ID != state.NodeID)
+	if transition := transitionRegistry[node.Payload.Type()]; node.ID == state.NodeID && transition.handles(node, nodeType, data) {
+		if transition.Handle(ex, node, state, nextState, nodeType, data) {
+			return nextState, []*types.Node{node}, true
 		}
+		if nextState.Phase == PhaseDone {
+			return nextState, nil, true
+		}
+	}
 
-		nextState := NewFlowState(state.PageID, state.PSID, state.FlowID)
-		nextState.NodeID = nextNodeID
-		nextState.Extra = data
+	nextNodeID := node.Payload.Next(nodeType, data)
+	return ex.follow(nextState, nextNodeID)
+}
 
-		_nodes = append(_nodes, nextNode)
-		return nextState, _nodes, true
+// follow moves the state to the given node. It passes through the nodes which
+// do not wait for the user (set_variable, condition, http_request) and stops
+// at the first node which does, or at a node which ends the transition, such
+// as goto_flow (see Transition.Enter).
+func (ex *Executor) follow(state *FlowState, nodeID dot.IntID) (_nextState *FlowState, _nodes []*types.Node, ok bool) {
+	flow := ex.Flow
+	// the events are discarded when the transition fails
+	events := ex.Events
+	fail := func() (*FlowState, []*types.Node, bool) {
+		ex.Events = events
+		return nil, nil, false
 	}
-	return nil, nil, false
-}
+	for step := 0; nodeID != 0; step++ {
+		if step >= maxSteps {
+			ls.Errorf("flow %v: too many steps from node %v", flow.ID, state.LastNodeID)
+			return fail()
+		}
+		node := flow.NodeByID(nodeID)
+		if node == nil {
+			return fail()
+		}
+		state.NodeID = nodeID
+		ex.Events = append(ex.Events, &Event{Type: EventNodeEntered, NodeID: node.ID})
+
+		waits, end := node.Payload.Waits(), false
+		transition := transitionRegistry[node.Payload.Type()]
+		switch {
+		case transition != nil && transition.Enter != nil:
+			var err error
+			nodeID, end, err = transition.Enter(ex, node, state)
+			if err != nil {
+				ls.Errorf("flow %v: %v at node %v: %v", flow.ID, node.Payload.Type(), node.ID, err)
+				return fail()
+			}
+		case !waits:
+			ls.Errorf("flow %v: %v at node %v: no transition", flow.ID, node.Payload.Type(), node.ID)
+			return fail()
+		}
+		if !waits && !end {
+			continue
+		}
 
-func mockGetNodeByType(flow *types.Flow, nodeType types.NodeType) (node, fallback *types.Node) {
-	for _, nd := range flow.Nodes {
-		// fallback node
-		if nd.Payload.Type() == types.NodeReceivedMessage && fallback == nil {
-			fallback = nd
+		var nodes []*types.Node
+		if waits {
+			nodes = []*types.Node{node}
 		}
-		// real node
-		if nd.Payload.Type() == nodeType {
-			return nd, nil
+		if !end && len(node.Payload.Links()) == 0 {
+			ex.Events = append(ex.Events, &Event{Type: EventFlowCompleted, NodeID: node.ID})
 		}
+		return state, nodes, true
+	}
+	// the flow ends at a node which does not wait for the user
+	if state.NodeID != state.LastNodeID {
+		ex.Events = append(ex.Events, &Event{Type: EventFlowCompleted, NodeID: state.NodeID})
+		return state, nil, true
 	}
-	return nil, fallback
+	return fail()
 }
@@ -0,0 +1,281 @@
+package flowcore
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/olvrng/rbot/be/com/flowdef/types"
+	"github.com/olvrng/rbot/be/pkg/dot"
+	"github.com/olvrng/rbot/be/pkg/xerrors"
+)
+
+func trigger(id dot.IntID, typ types.NodeType, nextID dot.IntID) *types.Node {
+	payload := &types.NodePayload{}
+	switch typ {
+	case types.NodeReceivedMessage:
+		payload.Data = &types.ReceivedMessageNodeData{NextID: nextID}
+	case types.NodeCompletedOrder:
+		payload.Data = &types.CompletedOrderNodeData{NextID: nextID}
+	default:
+		panic("unsupported trigger")
+	}
+	return &types.Node{ID: id, Payload: payload}
+}
+
+func message(id dot.IntID, tpl string, nextID dot.IntID, replies ...*types.QuickReplyItem) *types.Node {
+	return &types.Node{ID: id, Payload: &types.NodePayload{
+		Data: &types.SendMessageNodeData{Template: tpl, NextID: nextID, QuickReplies: replies},
+	}}
+}
+
+// newTestFlow has two received_message triggers, so the entry point of
+// received_message is ambiguous unless it is set explicitly.
+func newTestFlow(opts ...func(*types.Flow)) *types.Flow {
+	flow := &types.Flow{
+		ID: 1,
+		Nodes: []*types.Node{
+			trigger(1, types.NodeReceivedMessage, 2),
+			message(2, "menu", 4, &types.QuickReplyItem{Text: "A", Code: "a", NextID: 3}),
+			message(3, "A", 0),
+			message(4, "got message", 0),
+			trigger(5, types.NodeCompletedOrder, 6),
+			message(6, "order", 0),
+			message(7, "fallback", 0),
+			message(8, "keyword", 0),
+			trigger(9, types.NodeReceivedMessage, 4),
+		},
+	}
+	for _, opt := range opts {
+		opt(flow)
+	}
+	return flow
+}
+
+func withEntryPoints(flow *types.Flow) {
+	flow.EntryPoints = map[types.NodeType]dot.IntID{types.NodeReceivedMessage: 1}
+}
+
+func withKeywords(flow *types.Flow) {
+	flow.Keywords = []*types.KeywordEntry{{Keywords: []string{"start", "Menu"}, NextID: 8}}
+}
+
+func withFallback(flow *types.Flow) {
+	flow.FallbackNodeID = 7
+}
+
+func withResetVars(flow *types.Flow) {
+	flow.RestartPolicy = types.RestartResetVars
+}
+
+func TestNextState(t *testing.T) {
+	msg := func(s string) map[string]string { return map[string]string{"message": s} }
+	reply := func(s string) map[string]string { return map[string]string{"reply_payload": s} }
+
+	tests := []struct {
+		name     string
+		flow     *types.Flow
+		nodeID   dot.IntID
+		nodeType types.NodeType
+		data     map[string]string
+		eventVar bool
+
+		expectedNodeID dot.IntID
+		expectedErr    xerrors.Code
+		expectedVars   map[string]string
+	}{
+		{
+			name:     "current node handles message",
+			flow:     newTestFlow(withEntryPoints, withKeywords, withFallback),
+			nodeID:   2,
+			nodeType: types.NodeReceivedMessage, data: msg("hello"),
+			expectedNodeID: 4,
+		},
+		{
+			name:     "current node handles reply",
+			flow:     newTestFlow(withEntryPoints, withKeywords, withFallback),
+			nodeID:   2,
+			nodeType: types.NodeReceivedReply, data: reply("a"),
+			expectedNodeID: 3,
+		},
+		{
+			name:     "keyword before current node",
+			flow:     newTestFlow(withEntryPoints, withKeywords, withFallback),
+			nodeID:   2,
+			nodeType: types.NodeReceivedMessage, data: msg(" START "),
+			expectedNodeID: 8,
+		},
+		{
+			name:     "keyword ignores case",
+			flow:     newTestFlow(withKeywords),
+			nodeID:   3,
+			nodeType: types.NodeReceivedMessage, data: msg("menu"),
+			expectedNodeID: 8,
+		},
+		{
+			name:     "keyword only applies to message",
+			flow:     newTestFlow(withKeywords, withFallback),
+			nodeID:   3,
+			nodeType: types.NodeReceivedReply, data: reply("start"),
+			expectedNodeID: 7,
+		},
+		{
+			name:     "no keyword",
+			flow:     newTestFlow(withEntryPoints),
+			nodeID:   2,
+			nodeType: types.NodeReceivedMessage, data: msg("start"),
+			expectedNodeID: 4,
+		},
+		{
+			name:     "explicit entry point",
+			flow:     newTestFlow(withEntryPoints, withFallback),
+			nodeID:   3,
+			nodeType: types.NodeReceivedMessage, data: msg("hello"),
+			expectedNodeID: 2,
+		},
+		{
+			name:     "new conversation starts from entry point",
+			flow:     newTestFlow(withEntryPoints),
+			nodeID:   0,
+			nodeType: types.NodeReceivedMessage, data: msg("hello"),
+			expectedNodeID: 2,
+		},
+		{
+			name:           "implicit entry point",
+			flow:           newTestFlow(),
+			nodeID:         3,
+			nodeType:       types.NodeCompletedOrder,
+			expectedNodeID: 6,
+		},
+		{
+			name:     "ambiguous entry point goes to fallback",
+			flow:     newTestFlow(withFallback),
+			nodeID:   3,
+			nodeType: types.NodeReceivedMessage, data: msg("hello"),
+			expectedNodeID: 7,
+		},
+		{
+			name:     "ambiguous entry point without fallback",
+			flow:     newTestFlow(),
+			nodeID:   3,
+			nodeType: types.NodeReceivedMessage, data: msg("hello"),
+			expectedErr: xerrors.Aborted,
+		},
+		{
+			name:     "fallback handles reply",
+			flow:     newTestFlow(withEntryPoints, withFallback),
+			nodeID:   3,
+			nodeType: types.NodeReceivedReply, data: reply("b"),
+			expectedNodeID: 7,
+		},
+		{
+			name:        "no fallback for other triggers",
+			flow:        newTestFlow(withEntryPoints, withFallback),
+			nodeID:      3,
+			nodeType:    types.NodeReferral,
+			expectedErr: xerrors.Aborted,
+		},
+		{
+			name:        "timer only applies to current node",
+			flow:        newTestFlow(withEntryPoints, withFallback),
+			nodeID:      3,
+			nodeType:    types.NodeTimer,
+			expectedErr: xerrors.Aborted,
+		},
+		{
+			name:     "restart keeps vars",
+			flow:     newTestFlow(withEntryPoints),
+			nodeID:   3,
+			nodeType: types.NodeReceivedMessage, data: msg("hello"),
+			expectedNodeID: 2,
+			expectedVars:   map[string]string{"name": "Alice"},
+		},
+		{
+			name:     "restart resets vars",
+			flow:     newTestFlow(withEntryPoints, withResetVars),
+			nodeID:   3,
+			nodeType: types.NodeReceivedMessage, data: msg("hello"),
+			expectedNodeID: 2,
+			expectedVars:   map[string]string{},
+		},
+		{
+			name:     "keyword resets vars",
+			flow:     newTestFlow(withKeywords, withResetVars),
+			nodeID:   2,
+			nodeType: types.NodeReceivedMessage, data: msg("start"),
+			expectedNodeID: 8,
+			expectedVars:   map[string]string{},
+		},
+		{
+			name:     "current node keeps vars with reset policy",
+			flow:     newTestFlow(withEntryPoints, withResetVars),
+			nodeID:   2,
+			nodeType: types.NodeReceivedMessage, data: msg("hello"),
+			expectedNodeID: 4,
+			expectedVars:   map[string]string{"name": "Alice"},
+		},
+		{
+			name:           "event vars survive reset",
+			flow:           newTestFlow(withResetVars),
+			nodeID:         3,
+			nodeType:       types.NodeCompletedOrder,
+			eventVar:       true,
+			expectedNodeID: 6,
+			expectedVars:   map[string]string{"order_id": "o1"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			state := NewFlowState(100, 200, tt.flow.ID)
+			state.NodeID = tt.nodeID
+			state.SetVar("name", types.VarString, "Alice")
+
+			ex := NewExecutor(tt.flow, state)
+			if tt.eventVar {
+				ex.SetVar("order_id", types.VarString, "o1")
+			}
+			nextState, nextNodes, err := ex.NextState(tt.nodeType, tt.data)
+			if tt.expectedErr != xerrors.NoError {
+				require.Equal(t, tt.expectedErr, xerrors.GetCode(err))
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tt.expectedNodeID, nextState.NodeID)
+			require.Len(t, nextNodes, 1)
+			require.Equal(t, tt.expectedNodeID, nextNodes[0].ID)
+			if tt.expectedVars != nil {
+				vars := map[string]string{}
+				for name, v := range nextState.Vars {
+					vars[name] = v.Value
+				}
+				require.Equal(t, tt.expectedVars, vars)
+			}
+		})
+	}
+}
+
+func TestFlowValidate(t *testing.T) {
+	tests := []struct {
+		name  string
+		opt   func(*types.Flow)
+		valid bool
+	}{
+		{"valid", func(f *types.Flow) { withEntryPoints(f); withKeywords(f); withFallback(f) }, true},
+		{"entry point not found", func(f *types.Flow) { f.EntryPoints = map[types.NodeType]dot.IntID{types.NodeReceivedMessage: 99} }, false},
+		{"entry point of wrong type", func(f *types.Flow) { f.EntryPoints = map[types.NodeType]dot.IntID{types.NodeCompletedOrder: 1} }, false},
+		{"keyword node not found", func(f *types.Flow) { f.Keywords = []*types.KeywordEntry{{Keywords: []string{"x"}, NextID: 99}} }, false},
+		{"keyword without keywords", func(f *types.Flow) { f.Keywords = []*types.KeywordEntry{{NextID: 8}} }, false},
+		{"fallback not found", func(f *types.Flow) { f.FallbackNodeID = 99 }, false},
+		{"unknown restart policy", func(f *types.Flow) { f.RestartPolicy = "foo" }, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := newTestFlow(tt.opt).Validate()
+			if tt.valid {
+				require.NoError(t, err)
+			} else {
+				require.Error(t, err)
+			}
+		})
+	}
+}
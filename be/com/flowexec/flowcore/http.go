@@ -0,0 +1,160 @@
+package flowcore
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/olvrng/rbot/be/com/flowdef/types"
+	"github.com/olvrng/rbot/be/pkg/dot"
+	"github.com/olvrng/rbot/be/pkg/xerrors"
+)
+
+// HTTPRequest is the request of an http_request node, rendered with the
+// conversation variables.
+type HTTPRequest struct {
+	Method  string
+	URL     string
+	Headers map[string]string
+	Body    string
+	Timeout time.Duration
+	Retries int
+}
+
+type HTTPResponse struct {
+	StatusCode int
+	Body       []byte
+}
+
+// HTTPRequester makes the requests of the http_request nodes, with the timeout
+// and the retries of the request.
+type HTTPRequester interface {
+	DoRequest(req *HTTPRequest) (*HTTPResponse, error)
+}
+
+// RenderHTTPRequest renders the request of the node.
+func RenderHTTPRequest(node *types.HTTPRequestNodeData, data map[string]string) (*HTTPRequest, error) {
+	req := &HTTPRequest{
+		Method:  node.Method,
+		Headers: map[string]string{},
+		Timeout: node.Timeout.Duration(),
+		Retries: node.Retries,
+	}
+	if req.Method == "" {
+		req.Method = http.MethodGet
+	}
+	if req.Timeout <= 0 {
+		req.Timeout = types.DefaultHTTPRequestTimeout
+	}
+	var err error
+	if req.URL, err = RenderTemplate(node.URL, data); err != nil {
+		return nil, err
+	}
+	if req.Body, err = RenderTemplate(node.Body, data); err != nil {
+		return nil, err
+	}
+	for key, tpl := range node.Headers {
+		if req.Headers[key], err = RenderTemplate(tpl, data); err != nil {
+			return nil, err
+		}
+	}
+	return req, nil
+}
+
+// execHTTPRequest makes the request of the node, stores the response into the
+// variables, then returns the next node according to the status.
+func (ex *Executor) execHTTPRequest(node *types.Node, payload *types.HTTPRequestNodeData, state *FlowState) dot.IntID {
+	if ex.HTTP == nil {
+		ls.Errorf("flow %v: http request at node %v: no http client", ex.Flow.ID, node.ID)
+		return payload.ErrorNextID
+	}
+	req, err := RenderHTTPRequest(payload, state.TemplateData())
+	if err != nil {
+		ls.Errorf("flow %v: http request at node %v: %v", ex.Flow.ID, node.ID, err)
+		return payload.ErrorNextID
+	}
+	resp, err := ex.HTTP.DoRequest(req)
+	if err != nil {
+		ls.Errorf("flow %v: http request at node %v: %v", ex.Flow.ID, node.ID, err)
+		return payload.ErrorNextID
+	}
+
+	if payload.StatusVariable != "" {
+		state.SetVar(payload.StatusVariable, types.VarNumber, strconv.Itoa(resp.StatusCode))
+	}
+	if len(payload.ResponseVars) != 0 {
+		// the body of an error response is often not json, then it is routed
+		// by status without the variables
+		err = SetResponseVars(payload.ResponseVars, resp.Body, state)
+		if err != nil && resp.StatusCode/100 == 2 {
+			ls.Errorf("flow %v: http request at node %v: %v", ex.Flow.ID, node.ID, err)
+			return payload.ErrorNextID
+		}
+	}
+	for _, route := range payload.StatusRoutes {
+		if route.Match(resp.StatusCode) {
+			return route.NextID
+		}
+	}
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		return payload.NextID
+	}
+	return payload.ErrorNextID
+}
+
+// SetResponseVars stores the fields of the JSON response into the variables. A
+// missing field is stored as an empty string.
+func SetResponseVars(vars []*types.ResponseVar, body []byte, state *FlowState) error {
+	var doc interface{}
+	if err := json.Unmarshal(body, &doc); err != nil {
+		return xerrors.Errorf(xerrors.InvalidArgument, err, "response is not json")
+	}
+	for _, v := range vars {
+		value, err := jsonPathValue(doc, v.Path)
+		if err != nil {
+			return err
+		}
+		state.SetVar(v.Variable, v.VarType, value)
+	}
+	return nil
+}
+
+// jsonPathValue returns the value at the dot-separated path, such as
+// "items.0.status", formatted as a variable value.
+func jsonPathValue(doc interface{}, path string) (string, error) {
+	value := doc
+	for _, key := range strings.Split(path, ".") {
+		switch v := value.(type) {
+		case map[string]interface{}:
+			value = v[key]
+		case []interface{}:
+			i, err := strconv.Atoi(key)
+			if err != nil || i < 0 || i >= len(v) {
+				value = nil
+			} else {
+				value = v[i]
+			}
+		default:
+			value = nil
+		}
+	}
+
+	switch v := value.(type) {
+	case nil:
+		return "", nil
+	case string:
+		return v, nil
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64), nil
+	case bool:
+		return strconv.FormatBool(v), nil
+	default:
+		data, err := json.Marshal(v)
+		if err != nil {
+			return "", xerrors.Errorf(xerrors.Internal, err, "can not encode %v", path)
+		}
+		return string(data), nil
+	}
+}
@@ -0,0 +1,182 @@
+package flowcore
+
+import (
+	"regexp"
+
+	"github.com/olvrng/rbot/be/com/flowdef/types"
+	"github.com/olvrng/rbot/be/pkg/textnorm"
+)
+
+// IntentMatcher classifies a free text message into an intent, such as
+// "cancel_order". The score is from 0 to 1.
+type IntentMatcher interface {
+	MatchIntent(message string) (intent string, score float64)
+}
+
+// LocalIntentMatcher is a rules-based IntentMatcher. The score of an intent is
+// the best ratio of the words of an example which are found in the message,
+// allowing a typo in the longer words.
+type LocalIntentMatcher struct {
+	examples map[string][][]string
+	order    []string
+}
+
+func NewLocalIntentMatcher(intents []*types.Intent) *LocalIntentMatcher {
+	m := &LocalIntentMatcher{examples: map[string][][]string{}}
+	for _, intent := range intents {
+		if _, ok := m.examples[intent.Name]; !ok {
+			m.order = append(m.order, intent.Name)
+		}
+		for _, example := range intent.Examples {
+			if words := textnorm.Words(example); len(words) != 0 {
+				m.examples[intent.Name] = append(m.examples[intent.Name], words)
+			}
+		}
+	}
+	return m
+}
+
+func (m *LocalIntentMatcher) MatchIntent(message string) (intent string, score float64) {
+	words := textnorm.Words(message)
+	if len(words) == 0 {
+		return "", 0
+	}
+	for _, name := range m.order {
+		for _, example := range m.examples[name] {
+			if s := matchWords(example, words); s > score {
+				intent, score = name, s
+			}
+		}
+	}
+	return intent, score
+}
+
+func matchWords(example, words []string) float64 {
+	found := 0
+	for _, e := range example {
+		for _, w := range words {
+			if similarWord(e, w) {
+				found++
+				break
+			}
+		}
+	}
+	return float64(found) / float64(len(example))
+}
+
+func similarWord(a, b string) bool {
+	if a == b {
+		return true
+	}
+	if len(a) < 4 || len(b) < 4 {
+		return false
+	}
+	return editDistance(a, b) <= 1
+}
+
+func editDistance(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+	prev := make([]int, len(rb)+1)
+	curr := make([]int, len(rb)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(ra); i++ {
+		curr[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			curr[j] = min(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+		}
+		prev, curr = curr, prev
+	}
+	return prev[len(rb)]
+}
+
+func min(values ...int) int {
+	m := values[0]
+	for _, v := range values[1:] {
+		if v < m {
+			m = v
+		}
+	}
+	return m
+}
+
+// keywordMatch is a keyword trigger which matches the message.
+type keywordMatch struct {
+	node    *types.Node
+	payload *types.KeywordNodeData
+	rule    *types.KeywordRule
+	intent  string
+}
+
+// matchKeywordTrigger returns the keyword trigger of the flow which matches the
+// message with the highest priority.
+func (ex *Executor) matchKeywordTrigger(message string) *keywordMatch {
+	var best *keywordMatch
+	for _, node := range ex.Flow.Nodes {
+		payload, ok := node.Payload.Data.(*types.KeywordNodeData)
+		if !ok {
+			continue
+		}
+		for _, rule := range payload.Rules {
+			if best != nil && rule.Priority <= best.rule.Priority {
+				continue
+			}
+			if ok, intent := ex.matchKeywordRule(rule, message); ok {
+				best = &keywordMatch{node: node, payload: payload, rule: rule, intent: intent}
+			}
+		}
+	}
+	return best
+}
+
+func (ex *Executor) matchKeywordRule(rule *types.KeywordRule, message string) (ok bool, intent string) {
+	switch rule.Match {
+	case types.MatchExact:
+		folded := textnorm.Fold(message)
+		for _, value := range rule.Values {
+			if folded == textnorm.Fold(value) {
+				return true, ""
+			}
+		}
+
+	case types.MatchAny:
+		for _, value := range rule.Values {
+			if textnorm.ContainsPhrase(message, value) {
+				return true, ""
+			}
+		}
+
+	case types.MatchRegex:
+		re, err := regexp.Compile("(?i)" + rule.Pattern)
+		if err != nil {
+			ls.Errorf("flow %v: invalid pattern %q: %v", ex.Flow.ID, rule.Pattern, err)
+			return false, ""
+		}
+		return re.MatchString(message) || re.MatchString(textnorm.Fold(message)), ""
+
+	case types.MatchIntent:
+		matcher := ex.intentMatcher()
+		if matcher == nil {
+			return false, ""
+		}
+		minScore := rule.MinScore
+		if minScore == 0 {
+			minScore = types.DefaultIntentMinScore
+		}
+		intent, score := matcher.MatchIntent(message)
+		return intent == rule.Intent && score >= minScore, intent
+	}
+	return false, ""
+}
+
+func (ex *Executor) intentMatcher() IntentMatcher {
+	if ex.Intents == nil && len(ex.Flow.Intents) != 0 {
+		ex.Intents = NewLocalIntentMatcher(ex.Flow.Intents)
+	}
+	return ex.Intents
+}
@@ -0,0 +1,120 @@
+package flowcore
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/olvrng/rbot/be/com/flowdef/types"
+	"github.com/olvrng/rbot/be/pkg/dot"
+)
+
+func keyword(id dot.IntID, nextID dot.IntID, rules ...*types.KeywordRule) *types.Node {
+	return &types.Node{ID: id, Payload: &types.NodePayload{
+		Data: &types.KeywordNodeData{Rules: rules, NextID: nextID},
+	}}
+}
+
+func newKeywordFlow() *types.Flow {
+	return &types.Flow{
+		ID: 1,
+		Nodes: []*types.Node{
+			trigger(1, types.NodeReceivedMessage, 2),
+			{ID: 2, Payload: &types.NodePayload{
+				Data: &types.CaptureInputNodeData{Template: "email?", Variable: "email", Validation: types.ValidateEmail, NextID: 3},
+			}},
+			message(3, "done", 0),
+			keyword(10, 11, &types.KeywordRule{Match: types.MatchExact, Values: []string{"hủy đơn", "cancel"}}),
+			message(11, "cancelled", 0),
+			keyword(20, 21, &types.KeywordRule{Match: types.MatchAny, Values: []string{"giá", "price"}}),
+			message(21, "price list", 0),
+			keyword(30, 31, &types.KeywordRule{Match: types.MatchRegex, Pattern: `^track\s+#?\d+$`}),
+			message(31, "tracking", 0),
+			keyword(40, 41,
+				&types.KeywordRule{Match: types.MatchAny, Values: []string{"gấp"}, Priority: 10},
+				&types.KeywordRule{Match: types.MatchIntent, Intent: "complaint"},
+			),
+			message(41, "urgent", 0),
+		},
+		EntryPoints: map[types.NodeType]dot.IntID{types.NodeReceivedMessage: 1},
+		Intents: []*types.Intent{
+			{Name: "complaint", Examples: []string{"sản phẩm bị hỏng", "I want to complain"}},
+		},
+	}
+}
+
+type mockIntentMatcher struct {
+	intent string
+	score  float64
+}
+
+func (m mockIntentMatcher) MatchIntent(message string) (string, float64) {
+	return m.intent, m.score
+}
+
+func TestKeywordTrigger(t *testing.T) {
+	tests := []struct {
+		name    string
+		message string
+		intents IntentMatcher
+
+		expectedNodeID dot.IntID
+		expectedIntent string
+	}{
+		{name: "exact with diacritics", message: "Hủy đơn", expectedNodeID: 11},
+		{name: "exact without diacritics", message: "huy don!", expectedNodeID: 11},
+		{name: "exact does not match longer text", message: "please cancel it", expectedNodeID: 2},
+		{name: "any of words", message: "cho hỏi GIA bao nhiêu?", expectedNodeID: 21},
+		{name: "any of words needs whole word", message: "prices@example.com", expectedNodeID: 3},
+		{name: "regex", message: "Track #12345", expectedNodeID: 31},
+		{
+			name:    "same priority, first in flow wins",
+			message: "giá",
+			intents: mockIntentMatcher{intent: "complaint", score: 0.9},
+
+			expectedNodeID: 21,
+		},
+		{name: "higher priority wins", message: "giá gấp", expectedNodeID: 41},
+		{name: "local intent", message: "san pham bi hong roi", expectedNodeID: 41, expectedIntent: "complaint"},
+		{name: "local intent with typo", message: "i want to complian", expectedNodeID: 41, expectedIntent: "complaint"},
+		{name: "local intent below score", message: "sản phẩm đẹp", expectedNodeID: 2},
+		{
+			name:    "custom intent matcher",
+			message: "anything",
+			intents: mockIntentMatcher{intent: "complaint", score: 0.9},
+
+			expectedNodeID: 41, expectedIntent: "complaint",
+		},
+		{
+			name:    "custom intent matcher below score",
+			message: "a@b.co",
+			intents: mockIntentMatcher{intent: "complaint", score: 0.5},
+
+			expectedNodeID: 3,
+		},
+		{name: "no keyword goes to current node", message: "a@b.co", expectedNodeID: 3},
+		{name: "invalid input stays at current node", message: "hello", expectedNodeID: 2},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// the conversation is waiting for an email, but keywords work anyway
+			state := NewFlowState(100, 200, 1)
+			state.NodeID = 2
+
+			ex := NewExecutor(newKeywordFlow(), state)
+			ex.Intents = tt.intents
+			nextState, _, err := ex.NextState(types.NodeReceivedMessage, map[string]string{"message": tt.message})
+			require.NoError(t, err)
+			require.Equal(t, tt.expectedNodeID, nextState.NodeID)
+			require.Equal(t, tt.expectedIntent, nextState.Extra["intent"])
+		})
+	}
+}
+
+func TestFlowValidateKeyword(t *testing.T) {
+	flow := newKeywordFlow()
+	require.NoError(t, flow.Validate())
+
+	flow.Nodes = append(flow.Nodes, keyword(50, 3, &types.KeywordRule{Match: types.MatchRegex, Pattern: "("}))
+	require.Error(t, flow.Validate())
+}
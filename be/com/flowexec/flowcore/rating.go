@@ -0,0 +1,85 @@
+package flowcore
+
+import (
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/olvrng/rbot/be/com/flowdef/types"
+	"github.com/olvrng/rbot/be/pkg/xerrors"
+)
+
+const PhaseRatingComment = "comment"
+
+// handlesAskRating handles the answers, while the timer continues to the
+// timeout node.
+func handlesAskRating(node *types.Node, nodeType types.NodeType, data map[string]string) bool {
+	return nodeType != types.NodeTimer
+}
+
+// handleAskRating handles the answer at an ask_rating node: first the rating,
+// then the comment if the node asks for one.
+func (ex *Executor) handleAskRating(node *types.Node, state, nextState *FlowState, nodeType types.NodeType, data map[string]string) (stay bool) {
+	payload := node.Payload.Data.(*types.AskRatingNodeData)
+	answer := data["message"]
+	if nodeType == types.NodeReceivedReply {
+		answer = data["reply_payload"]
+	}
+
+	switch state.Phase {
+	case PhaseRatingComment:
+		if answer != types.RatingPayloadSkip {
+			nextState.SetVar(types.VarRatingComment, types.VarString, strings.TrimSpace(answer))
+		}
+
+	default:
+		rating, err := ParseRating(answer)
+		if err != nil {
+			// stay at the current node and ask again
+			ls.Debugf("invalid rating at node %v: %v", node.ID, err)
+			nextState.Retries = state.Retries + 1
+			return true
+		}
+		nextState.SetVar(types.VarRating, types.VarNumber, strconv.Itoa(rating))
+		nextState.SetVar(types.VarRatingComment, types.VarString, "")
+		if payload.CommentTemplate != "" {
+			nextState.Phase = PhaseRatingComment
+			return true
+		}
+	}
+
+	ex.Events = append(ex.Events, &Event{
+		Type:   EventReviewSubmitted,
+		NodeID: node.ID,
+		Data: map[string]string{
+			types.VarRating:        nextState.GetVar(types.VarRating),
+			types.VarRatingComment: nextState.GetVar(types.VarRatingComment),
+			"order_id":             nextState.GetVar("order_id"),
+		},
+	})
+	if payload.NextID == 0 {
+		// the flow ends with the review
+		nextState.Phase = PhaseDone
+		ex.Events = append(ex.Events, &Event{Type: EventFlowCompleted, NodeID: node.ID})
+	}
+	return false
+}
+
+// ParseRating accepts the quick reply payload ("rating:4"), a number ("4") or
+// stars ("⭐⭐⭐⭐").
+func ParseRating(s string) (int, error) {
+	s = strings.TrimSpace(strings.TrimPrefix(s, types.RatingPayloadPrefix))
+	s = strings.ReplaceAll(s, "\ufe0f", "") // emoji variation selector
+	rating, err := strconv.Atoi(s)
+	if err != nil {
+		stars := strings.Count(s, "⭐") + strings.Count(s, "★")
+		if stars == 0 || stars != utf8.RuneCountInString(s) {
+			return 0, xerrors.Errorf(xerrors.InvalidArgument, nil, "invalid rating %q", s)
+		}
+		rating = stars
+	}
+	if rating < 1 || rating > types.MaxRating {
+		return 0, xerrors.Errorf(xerrors.InvalidArgument, nil, "rating must be from 1 to %v", types.MaxRating)
+	}
+	return rating, nil
+}
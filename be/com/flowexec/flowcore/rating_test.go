@@ -0,0 +1,101 @@
+package flowcore
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/olvrng/rbot/be/com/flowdef/types"
+	"github.com/olvrng/rbot/be/pkg/dot"
+	"github.com/olvrng/rbot/be/pkg/xerrors"
+)
+
+func askRating(id dot.IntID, data *types.AskRatingNodeData) *types.Node {
+	if data.Template == "" {
+		data.Template = "How was your order?"
+	}
+	return &types.Node{ID: id, Payload: &types.NodePayload{Data: data}}
+}
+
+func eventTypes(events []*Event) []string {
+	var result []string
+	for _, event := range events {
+		result = append(result, event.Type)
+	}
+	return result
+}
+
+func TestAskRatingLastNode(t *testing.T) {
+	flow := &types.Flow{
+		ID: 1,
+		Nodes: []*types.Node{
+			trigger(1, types.NodeReceivedMessage, 2),
+			askRating(2, &types.AskRatingNodeData{
+				Timeout: types.Duration(time.Hour), TimeoutNextID: 3,
+			}),
+			message(3, "timeout", 0),
+		},
+	}
+	state := NewFlowState(100, 200, flow.ID)
+	state.NodeID = 2
+	state.SetVar("order_id", types.VarString, "o1")
+
+	ex := NewExecutor(flow, state)
+	nextState, nextNodes, err := ex.NextState(types.NodeReceivedReply, map[string]string{"reply_payload": "rating:4"})
+	require.NoError(t, err)
+	require.Empty(t, nextNodes)
+	require.Equal(t, dot.IntID(2), nextState.NodeID)
+	require.Equal(t, PhaseDone, nextState.Phase)
+	require.Equal(t, "4", nextState.GetVar(types.VarRating))
+	require.Equal(t, []string{EventReviewSubmitted, EventFlowCompleted}, eventTypes(ex.Events))
+	require.Equal(t, map[string]string{
+		types.VarRating:        "4",
+		types.VarRatingComment: "",
+		"order_id":             "o1",
+	}, ex.Events[0].Data)
+
+	t.Run("the timer does not apply anymore", func(t *testing.T) {
+		_, _, err := NewExecutor(flow, nextState).NextState(types.NodeTimer, nil)
+		require.Equal(t, xerrors.Aborted, xerrors.GetCode(err))
+	})
+	t.Run("a new message restarts the flow", func(t *testing.T) {
+		ex := NewExecutor(flow, nextState)
+		restarted, nextNodes, err := ex.NextState(types.NodeReceivedMessage, map[string]string{"message": "5"})
+		require.NoError(t, err)
+		require.Equal(t, dot.IntID(2), restarted.NodeID)
+		require.Empty(t, restarted.Phase)
+		require.Len(t, nextNodes, 1)
+		require.Equal(t, []string{EventConversationStarted, EventNodeEntered}, eventTypes(ex.Events))
+	})
+}
+
+func TestParseRating(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected int
+	}{
+		{input: "rating:4", expected: 4},
+		{input: " 5 ", expected: 5},
+		{input: "⭐⭐⭐", expected: 3},
+		{input: "⭐️⭐️", expected: 2},
+		{input: "★", expected: 1},
+		{input: "rating:0"},
+		{input: "6"},
+		{input: "⭐⭐⭐⭐⭐⭐"},
+		{input: "⭐ great"},
+		{input: "good"},
+		{input: ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			rating, err := ParseRating(tt.input)
+			if tt.expected == 0 {
+				require.Equal(t, xerrors.InvalidArgument, xerrors.GetCode(err))
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tt.expected, rating)
+		})
+	}
+}
@@ -0,0 +1,74 @@
+package flowcore
+
+import (
+	"github.com/olvrng/rbot/be/com/flowdef/types"
+)
+
+// Route tells how a flow handles an event of the conversation, in the order of
+// NextState. When a page has several flows, the event goes to the flow with
+// the highest route.
+type Route int
+
+const (
+	RouteNone Route = iota
+	RouteFallback
+	RouteEntryPoint
+	RouteCurrentNode
+	RouteKeyword
+)
+
+func (r Route) String() string {
+	switch r {
+	case RouteNone:
+		return "none"
+	case RouteFallback:
+		return "fallback"
+	case RouteEntryPoint:
+		return "entry_point"
+	case RouteCurrentNode:
+		return "current_node"
+	case RouteKeyword:
+		return "keyword"
+	default:
+		return "unknown"
+	}
+}
+
+// Route returns how NextState would handle the event, without executing it.
+// The current node only counts when the state is at a node of the flow.
+func (ex *Executor) Route(nodeType types.NodeType, data map[string]string) Route {
+	flow, state := ex.Flow, ex.State
+	if nodeType == types.NodeReceivedMessage {
+		if ex.matchKeywordTrigger(data["message"]) != nil || flow.MatchKeyword(data["message"]) != nil {
+			return RouteKeyword
+		}
+	}
+	if node := flow.NodeByID(state.NodeID); node != nil && handlesAtNode(state, node, nodeType, data) {
+		return RouteCurrentNode
+	}
+	if nodeType == types.NodeTimer {
+		return RouteNone
+	}
+	if node := flow.EntryPoint(nodeType); node != nil && node.ID != state.NodeID {
+		return RouteEntryPoint
+	}
+	switch nodeType {
+	case types.NodeReceivedMessage, types.NodeReceivedReply:
+		if flow.FallbackNodeID != 0 {
+			return RouteFallback
+		}
+	}
+	return RouteNone
+}
+
+// handlesAtNode tells whether the node, as the current node, continues on the
+// event.
+func handlesAtNode(state *FlowState, node *types.Node, nodeType types.NodeType, data map[string]string) bool {
+	if state.Phase == PhaseDone {
+		return false
+	}
+	if transitionRegistry[node.Payload.Type()].handles(node, nodeType, data) {
+		return true
+	}
+	return node.Payload.Next(nodeType, data) != 0
+}
@@ -3,10 +3,13 @@ package flowcore
 import (
 	"encoding/json"
 
+	"github.com/olvrng/rbot/be/com/flowdef/types"
 	"github.com/olvrng/rbot/be/pkg/dot"
 )
 
 type FlowState struct {
+	WorkspaceID dot.IntID `json:"workspace_id,omitempty"`
+
 	PageID dot.IntID `json:"page_id"`
 
 	PSID dot.IntID `json:"psid"`
@@ -17,7 +20,28 @@ type FlowState struct {
 
 	NodeID dot.IntID `json:"node_id"`
 
+	// Extra holds the data of the latest event.
 	Extra map[string]string `json:"extra"`
+
+	// Vars holds the conversation variables, which persist across transitions.
+	Vars map[string]*Variable `json:"vars,omitempty"`
+
+	// Retries counts the invalid inputs received at the current node.
+	Retries int `json:"retries,omitempty"`
+
+	// Phase is the step inside the current node, for nodes which wait for the
+	// user more than once (such as ask_rating).
+	Phase string `json:"phase,omitempty"`
+}
+
+// PhaseDone is the phase of the node at which the flow ends after the user
+// answered, such as an ask_rating node without next node. The node does not
+// handle the events anymore, and its timer is cancelled.
+const PhaseDone = "done"
+
+type Variable struct {
+	Type  types.VarType `json:"type"`
+	Value string        `json:"value"`
 }
 
 func NewFlowState(pageID, psID, flowID dot.IntID) *FlowState {
@@ -26,7 +50,52 @@ func NewFlowState(pageID, psID, flowID dot.IntID) *FlowState {
 		PSID:   psID,
 		FlowID: flowID,
 		Extra:  map[string]string{},
+		Vars:   map[string]*Variable{},
+	}
+}
+
+// Next returns a new state which continues the conversation from the current
+// state with the given event data. The variables are carried over.
+func (s *FlowState) Next(data map[string]string) *FlowState {
+	next := NewFlowState(s.PageID, s.PSID, s.FlowID)
+	next.LastNodeID = s.NodeID
+	next.NodeID = s.NodeID
+	next.Extra = data
+	for name, v := range s.Vars {
+		_v := *v
+		next.Vars[name] = &_v
+	}
+	return next
+}
+
+func (s *FlowState) SetVar(name string, typ types.VarType, value string) {
+	if s.Vars == nil {
+		s.Vars = map[string]*Variable{}
+	}
+	if typ == "" {
+		typ = types.VarString
+	}
+	s.Vars[name] = &Variable{Type: typ, Value: value}
+}
+
+func (s *FlowState) GetVar(name string) string {
+	if v := s.Vars[name]; v != nil {
+		return v.Value
+	}
+	return ""
+}
+
+// TemplateData returns the data for rendering templates and evaluating
+// conditions: the event data overridden by the conversation variables.
+func (s *FlowState) TemplateData() map[string]string {
+	data := make(map[string]string, len(s.Extra)+len(s.Vars))
+	for k, v := range s.Extra {
+		data[k] = v
+	}
+	for k, v := range s.Vars {
+		data[k] = v.Value
 	}
+	return data
 }
 
 func (s *FlowState) String() string {
@@ -0,0 +1,138 @@
+package flowcore
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/olvrng/rbot/be/com/flowdef/types"
+	"github.com/olvrng/rbot/be/pkg/dot"
+)
+
+// Transition is the logic of a node type in the executor. The executor stops
+// at a node which waits for the user (see types.NodePayload.Waits), and passes
+// through the others with Enter.
+type Transition struct {
+	// Enter is called when the executor enters the node. A node which does not
+	// wait returns the node where the executor continues, or 0 when the flow
+	// ends there. It returns end to stop the transition at the node without
+	// completing the flow, such as goto_flow which continues in another flow.
+	// An error aborts the transition.
+	Enter func(ex *Executor, node *types.Node, state *FlowState) (nextID dot.IntID, end bool, err error)
+
+	// Handle is called on an event at the current node of the conversation,
+	// before the executor continues to the Next node of the event. It returns
+	// stay to keep the conversation at the node, such as capture_input which
+	// asks again for an invalid input. The flow ends at the node when it sets
+	// the phase of nextState to PhaseDone.
+	Handle func(ex *Executor, node *types.Node, state, nextState *FlowState, nodeType types.NodeType, data map[string]string) (stay bool)
+
+	// Handles tells whether Handle handles the event, without executing it, so
+	// that the event is routed to the flow whose current node handles it.
+	// Without Handles, Handle handles all the events.
+	Handles func(node *types.Node, nodeType types.NodeType, data map[string]string) bool
+}
+
+// handles tells whether the transition handles the event at the current node.
+func (t *Transition) handles(node *types.Node, nodeType types.NodeType, data map[string]string) bool {
+	return t != nil && t.Handle != nil && (t.Handles == nil || t.Handles(node, nodeType, data))
+}
+
+var transitionRegistry = map[types.NodeType]*Transition{}
+
+// RegisterTransition registers the transition logic of a node type, along with
+// types.RegisterNode. A node type which does not wait for the user must have
+// Enter.
+func RegisterTransition(typ types.NodeType, transition *Transition) {
+	if typ == "" {
+		panic("register transition: empty type")
+	}
+	if transitionRegistry[typ] != nil {
+		panic(fmt.Sprintf("register transition: %v is already registered", typ))
+	}
+	transitionRegistry[typ] = transition
+}
+
+func init() {
+	RegisterTransition(types.NodeSetVariable, &Transition{Enter: (*Executor).enterSetVariable})
+	RegisterTransition(types.NodeCondition, &Transition{Enter: (*Executor).enterCondition})
+	RegisterTransition(types.NodeHTTPRequest, &Transition{Enter: (*Executor).enterHTTPRequest})
+	RegisterTransition(types.NodeGotoFlow, &Transition{Enter: (*Executor).enterGotoFlow})
+	RegisterTransition(types.NodeHandoff, &Transition{Enter: (*Executor).enterHandoff})
+	RegisterTransition(types.NodeCaptureInput, &Transition{
+		Handle:  (*Executor).handleCaptureInput,
+		Handles: onEvent(types.NodeReceivedMessage),
+	})
+	RegisterTransition(types.NodeAskRating, &Transition{
+		Handle:  (*Executor).handleAskRating,
+		Handles: handlesAskRating,
+	})
+}
+
+// onEvent returns a Transition.Handles which handles the given event types.
+func onEvent(nodeTypes ...types.NodeType) func(*types.Node, types.NodeType, map[string]string) bool {
+	return func(node *types.Node, nodeType types.NodeType, data map[string]string) bool {
+		for _, typ := range nodeTypes {
+			if typ == nodeType {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+func (ex *Executor) enterSetVariable(node *types.Node, state *FlowState) (dot.IntID, bool, error) {
+	payload := node.Payload.Data.(*types.SetVariableNodeData)
+	if err := EvalAssignments(payload.Assignments, state); err != nil {
+		return 0, false, err
+	}
+	return payload.NextID, false, nil
+}
+
+func (ex *Executor) enterCondition(node *types.Node, state *FlowState) (dot.IntID, bool, error) {
+	payload := node.Payload.Data.(*types.ConditionNodeData)
+	return EvalCondition(payload, state.TemplateData()), false, nil
+}
+
+func (ex *Executor) enterHTTPRequest(node *types.Node, state *FlowState) (dot.IntID, bool, error) {
+	payload := node.Payload.Data.(*types.HTTPRequestNodeData)
+	return ex.execHTTPRequest(node, payload, state), false, nil
+}
+
+func (ex *Executor) enterGotoFlow(node *types.Node, state *FlowState) (dot.IntID, bool, error) {
+	payload := node.Payload.Data.(*types.GotoFlowNodeData)
+	ex.Events = append(ex.Events, &Event{
+		Type:   EventGotoFlow,
+		NodeID: node.ID,
+		Data: map[string]string{
+			"flow_id": strconv.FormatInt(int64(payload.FlowID), 10),
+			"node_id": strconv.FormatInt(int64(payload.NodeID), 10),
+		},
+	})
+	return 0, true, nil
+}
+
+// enterHandoff waits for an agent, who resumes the flow when the handoff is
+// resolved. The flow does not complete at the node.
+func (ex *Executor) enterHandoff(node *types.Node, state *FlowState) (dot.IntID, bool, error) {
+	payload := node.Payload.Data.(*types.HandoffNodeData)
+	ex.Events = append(ex.Events, &Event{
+		Type:   EventHandoff,
+		NodeID: node.ID,
+		Data:   map[string]string{"reason": payload.Reason},
+	})
+	return 0, true, nil
+}
+
+func (ex *Executor) handleCaptureInput(node *types.Node, state, nextState *FlowState, nodeType types.NodeType, data map[string]string) bool {
+	input := node.Payload.Data.(*types.CaptureInputNodeData)
+	value := strings.TrimSpace(data["message"])
+	if err := ValidateInput(input, value); err != nil {
+		// stay at the current node and ask again
+		ls.Debugf("invalid input at node %v: %v", node.ID, err)
+		nextState.Retries = state.Retries + 1
+		return true
+	}
+	nextState.SetVar(input.Variable, input.VarType(), value)
+	return false
+}
@@ -0,0 +1,149 @@
+package flowcore
+
+import (
+	"errors"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/olvrng/rbot/be/com/flowdef/types"
+	"github.com/olvrng/rbot/be/pkg/dot"
+	"github.com/olvrng/rbot/be/pkg/xerrors"
+)
+
+const nodeDiscount = "action:discount"
+
+// discountNodeData is a custom node type which does not wait for the user: it
+// sets the discount variable, then continues to NextID.
+type discountNodeData struct {
+	Type    types.NodeType `json:"type"`
+	Percent int            `json:"percent"`
+	NextID  dot.IntID      `json:"next_id,omitempty"`
+}
+
+func (n *discountNodeData) NodeType() types.NodeType {
+	return nodeDiscount
+}
+
+func (n *discountNodeData) Next(typ types.NodeType, data map[string]string) dot.IntID {
+	return 0
+}
+
+func (n *discountNodeData) Links() []*types.Link {
+	return []*types.Link{{NextID: n.NextID}}
+}
+
+func (n *discountNodeData) Waits() bool {
+	return false
+}
+
+func enterDiscount(ex *Executor, node *types.Node, state *FlowState) (dot.IntID, bool, error) {
+	payload := node.Payload.Data.(*discountNodeData)
+	if payload.Percent <= 0 {
+		return 0, false, errors.New("invalid percent")
+	}
+	state.SetVar("discount", types.VarNumber, strconv.Itoa(payload.Percent))
+	return payload.NextID, false, nil
+}
+
+const nodeConfirm = "action:confirm"
+
+// confirmNodeData is a custom node type which waits until the user answers
+// "yes", then continues to NextID.
+type confirmNodeData struct {
+	Type   types.NodeType `json:"type"`
+	NextID dot.IntID      `json:"next_id,omitempty"`
+}
+
+func (n *confirmNodeData) NodeType() types.NodeType {
+	return nodeConfirm
+}
+
+func (n *confirmNodeData) Next(typ types.NodeType, data map[string]string) dot.IntID {
+	if typ == types.NodeReceivedMessage && data["message"] == "yes" {
+		return n.NextID
+	}
+	return 0
+}
+
+func (n *confirmNodeData) Links() []*types.Link {
+	return []*types.Link{{Label: "yes", NextID: n.NextID}}
+}
+
+func handleConfirm(ex *Executor, node *types.Node, state, nextState *FlowState, nodeType types.NodeType, data map[string]string) bool {
+	return data["message"] != "yes"
+}
+
+func init() {
+	types.RegisterNode(nodeDiscount, func() types.NodeData { return &discountNodeData{} })
+	RegisterTransition(nodeDiscount, &Transition{Enter: enterDiscount})
+	types.RegisterNode(nodeConfirm, func() types.NodeData { return &confirmNodeData{} })
+	RegisterTransition(nodeConfirm, &Transition{Handle: handleConfirm, Handles: onEvent(types.NodeReceivedMessage)})
+}
+
+func TestCustomTransition(t *testing.T) {
+	newFlow := func(percent int) *types.Flow {
+		return &types.Flow{
+			ID: 1,
+			Nodes: []*types.Node{
+				trigger(1, types.NodeReceivedMessage, 2),
+				{ID: 2, Payload: &types.NodePayload{Data: &discountNodeData{Percent: percent, NextID: 3}}},
+				message(3, "{{.discount}}% off", 0),
+			},
+		}
+	}
+
+	t.Run("pass through the node", func(t *testing.T) {
+		flow := newFlow(10)
+		ex := NewExecutor(flow, NewFlowState(100, 200, flow.ID))
+		nextState, nextNodes, err := ex.NextState(types.NodeReceivedMessage, map[string]string{"message": "hi"})
+		require.NoError(t, err)
+		require.Len(t, nextNodes, 1)
+		require.Equal(t, dot.IntID(3), nextNodes[0].ID)
+		require.Equal(t, dot.IntID(3), nextState.NodeID)
+		require.Equal(t, "10", nextState.GetVar("discount"))
+		require.Equal(t, []string{EventConversationStarted, EventNodeEntered, EventNodeEntered, EventFlowCompleted}, eventTypes(ex.Events))
+	})
+
+	t.Run("abort on error", func(t *testing.T) {
+		flow := newFlow(0)
+		ex := NewExecutor(flow, NewFlowState(100, 200, flow.ID))
+		_, _, err := ex.NextState(types.NodeReceivedMessage, map[string]string{"message": "hi"})
+		require.Equal(t, xerrors.Aborted, xerrors.GetCode(err))
+		require.Empty(t, ex.Events)
+	})
+
+	t.Run("register twice", func(t *testing.T) {
+		require.Panics(t, func() {
+			RegisterTransition(nodeDiscount, &Transition{Enter: enterDiscount})
+		})
+	})
+}
+
+func TestCustomHandle(t *testing.T) {
+	flow := &types.Flow{
+		ID: 1,
+		Nodes: []*types.Node{
+			trigger(1, types.NodeReceivedMessage, 2),
+			{ID: 2, Payload: &types.NodePayload{Data: &confirmNodeData{NextID: 3}}},
+			message(3, "confirmed", 0),
+		},
+	}
+	state := NewFlowState(100, 200, flow.ID)
+	state.NodeID = 2
+
+	// the node handles the messages, even when it does not continue yet
+	require.Equal(t, RouteCurrentNode, NewExecutor(flow, state).Route(types.NodeReceivedMessage, map[string]string{"message": "no"}))
+	require.Equal(t, RouteNone, NewExecutor(flow, state).Route(types.NodeTimer, nil))
+
+	nextState, nextNodes, err := NewExecutor(flow, state).NextState(types.NodeReceivedMessage, map[string]string{"message": "no"})
+	require.NoError(t, err)
+	require.Equal(t, dot.IntID(2), nextState.NodeID)
+	require.Equal(t, dot.IntID(2), nextNodes[0].ID)
+
+	nextState, nextNodes, err = NewExecutor(flow, nextState).NextState(types.NodeReceivedMessage, map[string]string{"message": "yes"})
+	require.NoError(t, err)
+	require.Equal(t, dot.IntID(3), nextState.NodeID)
+	require.Equal(t, dot.IntID(3), nextNodes[0].ID)
+}
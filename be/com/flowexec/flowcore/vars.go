@@ -0,0 +1,295 @@
+package flowcore
+
+import (
+	"encoding/json"
+	"regexp"
+	"strconv"
+	"strings"
+	"text/template"
+
+	"github.com/olvrng/rbot/be/com/flowdef/types"
+	"github.com/olvrng/rbot/be/pkg/dot"
+	"github.com/olvrng/rbot/be/pkg/xerrors"
+)
+
+var reEmail = regexp.MustCompile(`^[^@\s]+@[^@\s]+\.[^@\s]+$`)
+var rePhone = regexp.MustCompile(`^\+?[0-9][0-9 .\-]{6,18}[0-9]$`)
+
+var templateFuncs = template.FuncMap{
+	"lower": strings.ToLower,
+	"upper": strings.ToUpper,
+	"trim":  strings.TrimSpace,
+	"default": func(def, value string) string {
+		if value == "" {
+			return def
+		}
+		return value
+	},
+	// json quotes the value for the body of an http_request node, such as
+	// {"name": {{json .name}}}.
+	"json": func(value string) string {
+		data, _ := json.Marshal(value)
+		return string(data)
+	},
+}
+
+// RenderTemplate renders a text/template with the given data, for example
+// "Thank you {{.name}}!". Missing variables are rendered as empty strings.
+func RenderTemplate(tpl string, data map[string]string) (string, error) {
+	if !strings.Contains(tpl, "{{") {
+		return tpl, nil
+	}
+	t, err := template.New("").Funcs(templateFuncs).Option("missingkey=zero").Parse(tpl)
+	if err != nil {
+		return "", xerrors.Errorf(xerrors.InvalidArgument, err, "invalid template")
+	}
+	var b strings.Builder
+	if err = t.Execute(&b, data); err != nil {
+		return "", xerrors.Errorf(xerrors.InvalidArgument, err, "can not render template")
+	}
+	return b.String(), nil
+}
+
+// ValidateInput checks the input of a capture_input node.
+func ValidateInput(node *types.CaptureInputNodeData, value string) error {
+	if value == "" {
+		return xerrors.Errorf(xerrors.InvalidArgument, nil, "empty input")
+	}
+	var ok bool
+	switch node.Validation {
+	case types.ValidateNone:
+		ok = true
+	case types.ValidateEmail:
+		ok = reEmail.MatchString(value)
+	case types.ValidatePhone:
+		ok = rePhone.MatchString(value)
+	case types.ValidateNumber:
+		_, err := strconv.ParseFloat(value, 64)
+		ok = err == nil
+	case types.ValidateRegex:
+		re, err := regexp.Compile(node.Pattern)
+		if err != nil {
+			return xerrors.Errorf(xerrors.InvalidArgument, err, "invalid pattern")
+		}
+		ok = re.MatchString(value)
+	default:
+		return xerrors.Errorf(xerrors.InvalidArgument, nil, "unknown validation %v", node.Validation)
+	}
+	if !ok {
+		return xerrors.Errorf(xerrors.InvalidArgument, nil, "invalid %v", node.Validation)
+	}
+	return nil
+}
+
+// EvalAssignments evaluates the assignments in order and stores the results
+// into the state variables. Later assignments can refer to earlier ones.
+func EvalAssignments(assignments []*types.Assignment, state *FlowState) error {
+	for _, a := range assignments {
+		if a.Variable == "" {
+			return xerrors.Errorf(xerrors.InvalidArgument, nil, "no variable name")
+		}
+		value, err := RenderTemplate(a.Expr, state.TemplateData())
+		if err != nil {
+			return err
+		}
+		if a.VarType == types.VarNumber {
+			num, err := EvalArithmetic(value)
+			if err != nil {
+				return err
+			}
+			value = strconv.FormatFloat(num, 'f', -1, 64)
+		}
+		state.SetVar(a.Variable, a.VarType, value)
+	}
+	return nil
+}
+
+// EvalCondition returns the next node of the first matching rule, or the
+// default next node when no rule matches.
+func EvalCondition(node *types.ConditionNodeData, data map[string]string) dot.IntID {
+	for _, rule := range node.Rules {
+		if MatchRule(rule, data) {
+			return rule.NextID
+		}
+	}
+	return node.NextID
+}
+
+func MatchRule(rule *types.ConditionRule, data map[string]string) bool {
+	actual := data[rule.Variable]
+	expected, err := RenderTemplate(rule.Value, data)
+	if err != nil {
+		return false
+	}
+	switch rule.Op {
+	case types.OpEqual:
+		return compare(actual, expected) == 0
+	case types.OpNotEqual:
+		return compare(actual, expected) != 0
+	case types.OpGreater:
+		return isNumbers(actual, expected) && compare(actual, expected) > 0
+	case types.OpGreaterEqual:
+		return isNumbers(actual, expected) && compare(actual, expected) >= 0
+	case types.OpLess:
+		return isNumbers(actual, expected) && compare(actual, expected) < 0
+	case types.OpLessEqual:
+		return isNumbers(actual, expected) && compare(actual, expected) <= 0
+	case types.OpContains:
+		return strings.Contains(strings.ToLower(actual), strings.ToLower(expected))
+	case types.OpMatches:
+		re, err := regexp.Compile(expected)
+		return err == nil && re.MatchString(actual)
+	case types.OpEmpty:
+		return actual == ""
+	case types.OpNotEmpty:
+		return actual != ""
+	default:
+		return false
+	}
+}
+
+// compare compares two values as numbers when both are numbers, or as strings
+// (case-insensitive) otherwise.
+func compare(a, b string) int {
+	fa, errA := strconv.ParseFloat(a, 64)
+	fb, errB := strconv.ParseFloat(b, 64)
+	if errA == nil && errB == nil {
+		switch {
+		case fa < fb:
+			return -1
+		case fa > fb:
+			return 1
+		default:
+			return 0
+		}
+	}
+	return strings.Compare(strings.ToLower(a), strings.ToLower(b))
+}
+
+func isNumbers(values ...string) bool {
+	for _, v := range values {
+		if _, err := strconv.ParseFloat(v, 64); err != nil {
+			return false
+		}
+	}
+	return true
+}
+
+// EvalArithmetic evaluates a simple arithmetic expression with numbers,
+// + - * / and parentheses, for example "(2 + 3) * 4".
+func EvalArithmetic(expr string) (float64, error) {
+	p := &arithParser{s: expr}
+	result, err := p.parseExpr()
+	if err != nil {
+		return 0, err
+	}
+	p.skipSpaces()
+	if p.i < len(p.s) {
+		return 0, xerrors.Errorf(xerrors.InvalidArgument, nil, "unexpected %q in expression %q", p.s[p.i:], expr)
+	}
+	return result, nil
+}
+
+type arithParser struct {
+	s string
+	i int
+}
+
+func (p *arithParser) skipSpaces() {
+	for p.i < len(p.s) && p.s[p.i] == ' ' {
+		p.i++
+	}
+}
+
+func (p *arithParser) peek() byte {
+	p.skipSpaces()
+	if p.i < len(p.s) {
+		return p.s[p.i]
+	}
+	return 0
+}
+
+func (p *arithParser) parseExpr() (float64, error) {
+	left, err := p.parseTerm()
+	if err != nil {
+		return 0, err
+	}
+	for {
+		switch p.peek() {
+		case '+':
+			p.i++
+			right, err := p.parseTerm()
+			if err != nil {
+				return 0, err
+			}
+			left += right
+		case '-':
+			p.i++
+			right, err := p.parseTerm()
+			if err != nil {
+				return 0, err
+			}
+			left -= right
+		default:
+			return left, nil
+		}
+	}
+}
+
+func (p *arithParser) parseTerm() (float64, error) {
+	left, err := p.parseFactor()
+	if err != nil {
+		return 0, err
+	}
+	for {
+		switch p.peek() {
+		case '*':
+			p.i++
+			right, err := p.parseFactor()
+			if err != nil {
+				return 0, err
+			}
+			left *= right
+		case '/':
+			p.i++
+			right, err := p.parseFactor()
+			if err != nil {
+				return 0, err
+			}
+			if right == 0 {
+				return 0, xerrors.Errorf(xerrors.InvalidArgument, nil, "division by zero")
+			}
+			left /= right
+		default:
+			return left, nil
+		}
+	}
+}
+
+func (p *arithParser) parseFactor() (float64, error) {
+	switch c := p.peek(); {
+	case c == '(':
+		p.i++
+		v, err := p.parseExpr()
+		if err != nil {
+			return 0, err
+		}
+		if p.peek() != ')' {
+			return 0, xerrors.Errorf(xerrors.InvalidArgument, nil, "missing ) in expression %q", p.s)
+		}
+		p.i++
+		return v, nil
+	case c == '-':
+		p.i++
+		v, err := p.parseFactor()
+		return -v, err
+	case c == '.' || c >= '0' && c <= '9':
+		start := p.i
+		for p.i < len(p.s) && (p.s[p.i] == '.' || p.s[p.i] >= '0' && p.s[p.i] <= '9') {
+			p.i++
+		}
+		return strconv.ParseFloat(p.s[start:p.i], 64)
+	default:
+		return 0, xerrors.Errorf(xerrors.InvalidArgument, nil, "invalid expression %q", p.s)
+	}
+}
@@ -0,0 +1,180 @@
+package flowcore
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/olvrng/rbot/be/com/flowdef/types"
+	"github.com/olvrng/rbot/be/pkg/dot"
+)
+
+func TestEvalArithmetic(t *testing.T) {
+	tests := []struct {
+		expr     string
+		expected float64
+		err      string
+	}{
+		{expr: "42", expected: 42},
+		{expr: " 1.5 ", expected: 1.5},
+		{expr: "2 + 3 * 4", expected: 14},
+		{expr: "(2 + 3) * 4", expected: 20},
+		{expr: "10 - 4 - 3", expected: 3},
+		{expr: "24 / 4 / 2", expected: 3},
+		{expr: "2 * 3 + 4 * 5", expected: 26},
+		{expr: "-2 * -3", expected: 6},
+		{expr: "-(1 + 2)", expected: -3},
+		{expr: "((1))", expected: 1},
+		{expr: "1 / 0", err: "division by zero"},
+		{expr: "1 / (2 - 2)", err: "division by zero"},
+		{expr: "", err: "invalid expression"},
+		{expr: "  ", err: "invalid expression"},
+		{expr: "1 +", err: "invalid expression"},
+		{expr: "(1 + 2", err: "missing )"},
+		{expr: "1 + 2)", err: `unexpected ")"`},
+		{expr: "2 3", err: `unexpected "3"`},
+		{expr: "abc", err: "invalid expression"},
+		{expr: "1.2.3", err: "invalid syntax"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.expr, func(t *testing.T) {
+			result, err := EvalArithmetic(tt.expr)
+			if tt.err != "" {
+				require.Error(t, err)
+				require.Contains(t, err.Error(), tt.err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tt.expected, result)
+		})
+	}
+}
+
+func TestValidateInput(t *testing.T) {
+	tests := []struct {
+		validation types.InputValidation
+		pattern    string
+		value      string
+		valid      bool
+	}{
+		{validation: types.ValidateNone, value: "anything", valid: true},
+		{validation: types.ValidateNone, value: "", valid: false},
+		{validation: types.ValidateEmail, value: "ann@example.com", valid: true},
+		{validation: types.ValidateEmail, value: "ann@example", valid: false},
+		{validation: types.ValidateEmail, value: "ann @example.com", valid: false},
+		{validation: types.ValidatePhone, value: "+84 912 345 678", valid: true},
+		{validation: types.ValidatePhone, value: "0912-345-678", valid: true},
+		{validation: types.ValidatePhone, value: "12345", valid: false},
+		{validation: types.ValidatePhone, value: "call me", valid: false},
+		{validation: types.ValidateNumber, value: "-1.5", valid: true},
+		{validation: types.ValidateNumber, value: "one", valid: false},
+		{validation: types.ValidateRegex, pattern: `^[A-Z]{3}\d{3}$`, value: "ABC123", valid: true},
+		{validation: types.ValidateRegex, pattern: `^[A-Z]{3}\d{3}$`, value: "abc123", valid: false},
+		{validation: types.ValidateRegex, pattern: `(`, value: "abc", valid: false},
+		{validation: "date", value: "2021-06-01", valid: false},
+	}
+	for _, tt := range tests {
+		t.Run(string(tt.validation)+" "+tt.value, func(t *testing.T) {
+			node := &types.CaptureInputNodeData{Validation: tt.validation, Pattern: tt.pattern}
+			err := ValidateInput(node, tt.value)
+			if tt.valid {
+				require.NoError(t, err)
+			} else {
+				require.Error(t, err)
+			}
+		})
+	}
+}
+
+func TestMatchRule(t *testing.T) {
+	data := map[string]string{"age": "18", "name": "Ann", "empty": "", "limit": "20"}
+	tests := []struct {
+		variable string
+		op       types.ConditionOp
+		value    string
+		expected bool
+	}{
+		{"age", types.OpEqual, "18.0", true},
+		{"name", types.OpEqual, "ann", true},
+		{"name", types.OpNotEqual, "Bob", true},
+		{"age", types.OpGreater, "17", true},
+		{"age", types.OpGreater, "18", false},
+		{"age", types.OpGreaterEqual, "18", true},
+		{"age", types.OpLess, "{{.limit}}", true},
+		{"age", types.OpLessEqual, "17", false},
+		{"name", types.OpGreater, "1", false}, // only numbers are ordered
+		{"name", types.OpContains, "AN", true},
+		{"name", types.OpMatches, "^A.n$", true},
+		{"name", types.OpMatches, "(", false},
+		{"empty", types.OpEmpty, "", true},
+		{"missing", types.OpEmpty, "", true},
+		{"name", types.OpNotEmpty, "", true},
+		{"name", "between", "", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.variable+" "+string(tt.op)+" "+tt.value, func(t *testing.T) {
+			rule := &types.ConditionRule{Variable: tt.variable, Op: tt.op, Value: tt.value}
+			require.Equal(t, tt.expected, MatchRule(rule, data))
+		})
+	}
+
+	node := &types.ConditionNodeData{
+		Rules: []*types.ConditionRule{
+			{Variable: "age", Op: types.OpLess, Value: "18", NextID: 2},
+			{Variable: "age", Op: types.OpLess, Value: "65", NextID: 3},
+		},
+		NextID: 4,
+	}
+	require.Equal(t, dot.IntID(3), EvalCondition(node, data))
+	require.Equal(t, dot.IntID(4), EvalCondition(node, map[string]string{"age": "70"}))
+}
+
+func TestEvalAssignments(t *testing.T) {
+	state := NewFlowState(1, 2, 3)
+	state.SetVar("price", types.VarNumber, "100")
+	err := EvalAssignments([]*types.Assignment{
+		{Variable: "qty", VarType: types.VarNumber, Expr: "2 + 1"},
+		{Variable: "total", VarType: types.VarNumber, Expr: "{{.price}} * {{.qty}} / 4"},
+		{Variable: "summary", Expr: "{{.qty}} items: {{.total}}"},
+	}, state)
+	require.NoError(t, err)
+	require.Equal(t, "3", state.GetVar("qty"))
+	require.Equal(t, "75", state.GetVar("total"))
+	require.Equal(t, "3 items: 75", state.GetVar("summary"))
+
+	err = EvalAssignments([]*types.Assignment{{Variable: "x", VarType: types.VarNumber, Expr: "{{.price}} / 0"}}, state)
+	require.Error(t, err)
+	err = EvalAssignments([]*types.Assignment{{Variable: "x", VarType: types.VarNumber, Expr: "{{.missing}}"}}, state)
+	require.Error(t, err)
+	err = EvalAssignments([]*types.Assignment{{Expr: "1"}}, state)
+	require.Error(t, err)
+}
+
+func TestCaptureInput(t *testing.T) {
+	flow := &types.Flow{
+		ID: 1,
+		Nodes: []*types.Node{
+			trigger(1, types.NodeReceivedMessage, 2),
+			{ID: 2, Payload: &types.NodePayload{
+				Data: &types.CaptureInputNodeData{Template: "email?", Variable: "email", Validation: types.ValidateEmail, NextID: 3},
+			}},
+			message(3, "thanks {{.email}}", 0),
+		},
+	}
+	state := NewFlowState(100, 200, flow.ID)
+	state.NodeID = 2
+
+	// an invalid input asks again
+	nextState, nextNodes, err := NewExecutor(flow, state).NextState(types.NodeReceivedMessage, map[string]string{"message": "ann"})
+	require.NoError(t, err)
+	require.Equal(t, dot.IntID(2), nextState.NodeID)
+	require.Equal(t, 1, nextState.Retries)
+	require.Equal(t, dot.IntID(2), nextNodes[0].ID)
+	require.Empty(t, nextState.GetVar("email"))
+
+	nextState, nextNodes, err = NewExecutor(flow, nextState).NextState(types.NodeReceivedMessage, map[string]string{"message": " ann@example.com "})
+	require.NoError(t, err)
+	require.Equal(t, dot.IntID(3), nextNodes[0].ID)
+	require.Zero(t, nextState.Retries)
+	require.Equal(t, "ann@example.com", nextState.GetVar("email"))
+}
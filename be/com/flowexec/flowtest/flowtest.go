@@ -0,0 +1,172 @@
+// Package flowtest runs the conversations of test fixtures against flows, and
+// reports the differences with the expected replies.
+//
+// A fixture is a YAML or JSON file:
+//
+//	name: review
+//	flow_file: ../rbot-flow-data.json   # relative to the fixture
+//	flow_id: 1234                       # optional when the file has one flow
+//	cases:
+//	  - name: happy customer
+//	    steps:
+//	      - event: order
+//	        expect:
+//	          node: 2
+//	          replies: ["Please tell us your experience?"]
+//	      - event: postback
+//	        payload: rating_5
+//	        expect:
+//	          replies: ["Thank you for your response!..."]
+//
+// The steps have the fields of types.SimulationStep. The expectations which are
+// not given are not checked, use "replies: []" to expect no reply. The calls to
+// the Handover Protocol are replies such as "<pass_thread_control>".
+package flowtest
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/yaml.v2"
+
+	flowdefstore "github.com/olvrng/rbot/be/com/flowdef/store"
+	flowdeftypes "github.com/olvrng/rbot/be/com/flowdef/types"
+	"github.com/olvrng/rbot/be/com/flowexec/types"
+	"github.com/olvrng/rbot/be/pkg/dot"
+)
+
+type Suite struct {
+	Name     string    `json:"name"`
+	FlowFile string    `json:"flow_file"`
+	FlowID   dot.IntID `json:"flow_id"`
+	Cases    []*Case   `json:"cases"`
+
+	// Path is the fixture file.
+	Path string `json:"-"`
+}
+
+type Case struct {
+	Name  string  `json:"name"`
+	Steps []*Step `json:"steps"`
+}
+
+type Step struct {
+	types.SimulationStep
+
+	Expect *Expect `json:"expect,omitempty"`
+}
+
+type Expect struct {
+	Node    dot.IntID         `json:"node,omitempty"`
+	Replies []string          `json:"replies,omitempty"`
+	Vars    map[string]string `json:"vars,omitempty"`
+	Paused  *bool             `json:"paused,omitempty"`
+
+	// Error is a part of the expected error, if any.
+	Error string `json:"error,omitempty"`
+}
+
+// LoadSuite loads the fixture file. YAML files are converted to JSON, so both
+// formats share the JSON field names and types.
+func LoadSuite(path string) (*Suite, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	ext := strings.ToLower(filepath.Ext(path))
+	if ext == ".yaml" || ext == ".yml" {
+		if data, err = yamlToJSON(data); err != nil {
+			return nil, fmt.Errorf("%v: %v", path, err)
+		}
+	}
+
+	var suite Suite
+	if err = json.Unmarshal(data, &suite); err != nil {
+		return nil, fmt.Errorf("%v: %v", path, err)
+	}
+	suite.Path = path
+	if suite.Name == "" {
+		suite.Name = strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+	}
+	return &suite, nil
+}
+
+// LoadFlow loads the flow of the suite from the flow file. The flow file is
+// relative to the fixture, and defaults to defaultFile.
+func (s *Suite) LoadFlow(defaultFile string) (*flowdeftypes.Flow, error) {
+	path := defaultFile
+	if s.FlowFile != "" {
+		path = filepath.Join(filepath.Dir(s.Path), s.FlowFile)
+	}
+	if path == "" {
+		return nil, fmt.Errorf("%v: no flow file", s.Path)
+	}
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var flowFile flowdefstore.FlowFile
+	if err = json.Unmarshal(data, &flowFile); err != nil {
+		return nil, fmt.Errorf("%v: %v", path, err)
+	}
+
+	switch {
+	case s.FlowID != 0:
+		if flow := flowFile.GetByID(s.FlowID); flow != nil {
+			return flow, nil
+		}
+		return nil, fmt.Errorf("%v: flow %v not found", path, s.FlowID)
+	case len(flowFile.Flows) == 1:
+		return flowFile.Flows[0], nil
+	default:
+		return nil, fmt.Errorf("%v: flow_id is required, the file has %v flows", path, len(flowFile.Flows))
+	}
+}
+
+func yamlToJSON(data []byte) ([]byte, error) {
+	var v interface{}
+	if err := yaml.Unmarshal(data, &v); err != nil {
+		return nil, err
+	}
+	v, err := convertYAML(v)
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(v)
+}
+
+// convertYAML converts the maps of yaml.v2 to maps with string keys.
+func convertYAML(v interface{}) (interface{}, error) {
+	switch v := v.(type) {
+	case map[interface{}]interface{}:
+		m := make(map[string]interface{}, len(v))
+		for key, value := range v {
+			k, ok := key.(string)
+			if !ok {
+				return nil, fmt.Errorf("invalid key %v", key)
+			}
+			value, err := convertYAML(value)
+			if err != nil {
+				return nil, err
+			}
+			m[k] = value
+		}
+		return m, nil
+
+	case []interface{}:
+		for i, item := range v {
+			item, err := convertYAML(item)
+			if err != nil {
+				return nil, err
+			}
+			v[i] = item
+		}
+		return v, nil
+
+	default:
+		return v, nil
+	}
+}
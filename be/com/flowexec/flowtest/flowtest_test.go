@@ -0,0 +1,45 @@
+package flowtest
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRunSuite(t *testing.T) {
+	suite, err := LoadSuite("testdata/greeting.yaml")
+	require.NoError(t, err)
+	flow, err := suite.LoadFlow("")
+	require.NoError(t, err)
+
+	result := RunSuite(context.Background(), suite, flow)
+	require.NoError(t, result.Error)
+	require.Len(t, result.Cases, 2)
+	require.Empty(t, result.Cases[0].Failures)
+	require.Equal(t, []string{
+		"step 1 (message): replies differ:\n\t+ \"What is your name?\"",
+		"step 2 (message): expected node 2, got 3",
+		"step 2 (message): expected var name=\"Bob\", got \"Ann\"",
+	}, result.Cases[1].Failures)
+	require.Equal(t, 1, result.Failed())
+
+	var b bytes.Buffer
+	require.NoError(t, WriteJUnit(&b, []*SuiteResult{result}))
+	out := b.String()
+	require.Contains(t, out, `<testsuite name="greeting" tests="2" failures="1" errors="0"`)
+	require.Contains(t, out, `<testcase name="pass" classname="greeting"`)
+	require.Contains(t, out, `<failure message="step 1 (message): replies differ:`)
+}
+
+func TestLoadFlow(t *testing.T) {
+	suite := &Suite{Path: "testdata/greeting.yaml", FlowID: 2}
+	_, err := suite.LoadFlow("testdata/flow.json")
+	require.EqualError(t, err, "testdata/flow.json: flow 2 not found")
+
+	suite.FlowID = 0
+	flow, err := suite.LoadFlow("testdata/flow.json")
+	require.NoError(t, err)
+	require.Len(t, flow.Nodes, 3)
+}
@@ -0,0 +1,78 @@
+package flowtest
+
+import (
+	"encoding/xml"
+	"io"
+	"strings"
+)
+
+// The JUnit XML format which is understood by most CI servers.
+type junitTestSuites struct {
+	XMLName xml.Name          `xml:"testsuites"`
+	Suites  []*junitTestSuite `xml:"testsuite"`
+}
+
+type junitTestSuite struct {
+	Name     string           `xml:"name,attr"`
+	Tests    int              `xml:"tests,attr"`
+	Failures int              `xml:"failures,attr"`
+	Errors   int              `xml:"errors,attr"`
+	Time     float64          `xml:"time,attr"`
+	Cases    []*junitTestCase `xml:"testcase"`
+	Error    *junitFailure    `xml:"error,omitempty"`
+}
+
+type junitTestCase struct {
+	Name      string        `xml:"name,attr"`
+	ClassName string        `xml:"classname,attr"`
+	Time      float64       `xml:"time,attr"`
+	Failure   *junitFailure `xml:"failure,omitempty"`
+}
+
+type junitFailure struct {
+	Message string `xml:"message,attr"`
+	Content string `xml:",chardata"`
+}
+
+// WriteJUnit writes the results in the JUnit XML format.
+func WriteJUnit(w io.Writer, results []*SuiteResult) error {
+	out := &junitTestSuites{}
+	for _, r := range results {
+		suite := &junitTestSuite{
+			Name:     r.Suite.Name,
+			Tests:    len(r.Cases),
+			Failures: r.Failed(),
+			Time:     r.Duration.Seconds(),
+		}
+		if r.Error != nil {
+			suite.Errors = 1
+			suite.Error = &junitFailure{Message: r.Error.Error()}
+		}
+		for _, c := range r.Cases {
+			tc := &junitTestCase{
+				Name:      c.Case.Name,
+				ClassName: r.Suite.Name,
+				Time:      c.Duration.Seconds(),
+			}
+			if c.Failed() {
+				tc.Failure = &junitFailure{
+					Message: c.Failures[0],
+					Content: strings.Join(c.Failures, "\n"),
+				}
+			}
+			suite.Cases = append(suite.Cases, tc)
+		}
+		out.Suites = append(out.Suites, suite)
+	}
+
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+	enc := xml.NewEncoder(w)
+	enc.Indent("", "  ")
+	if err := enc.Encode(out); err != nil {
+		return err
+	}
+	_, err := io.WriteString(w, "\n")
+	return err
+}
@@ -0,0 +1,169 @@
+package flowtest
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	flowdeftypes "github.com/olvrng/rbot/be/com/flowdef/types"
+	"github.com/olvrng/rbot/be/com/flowexec/service"
+	"github.com/olvrng/rbot/be/com/flowexec/types"
+	"github.com/olvrng/rbot/be/pkg/clock"
+)
+
+// Epoch is the time when the simulated conversations start, so the results do
+// not depend on the current time.
+var Epoch = time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC)
+
+type SuiteResult struct {
+	Suite *Suite
+	Cases []*CaseResult
+
+	// Error is set when the suite can not run, such as when the flow is
+	// invalid.
+	Error    error
+	Duration time.Duration
+}
+
+func (r *SuiteResult) Failed() int {
+	failed := 0
+	for _, c := range r.Cases {
+		if c.Failed() {
+			failed++
+		}
+	}
+	return failed
+}
+
+type CaseResult struct {
+	Case     *Case
+	Failures []string
+	Duration time.Duration
+}
+
+func (r *CaseResult) Failed() bool {
+	return len(r.Failures) != 0
+}
+
+// RunSuite runs the cases of the suite against the flow.
+func RunSuite(ctx context.Context, suite *Suite, flow *flowdeftypes.Flow) *SuiteResult {
+	start := time.Now()
+	result := &SuiteResult{Suite: suite}
+	if err := flow.Validate(); err != nil {
+		result.Error = fmt.Errorf("invalid flow: %v", err)
+		return result
+	}
+	for _, c := range suite.Cases {
+		result.Cases = append(result.Cases, RunCase(ctx, c, flow))
+	}
+	result.Duration = time.Since(start)
+	return result
+}
+
+// RunCase runs the steps of the case in a new conversation.
+func RunCase(ctx context.Context, c *Case, flow *flowdeftypes.Flow) *CaseResult {
+	start := time.Now()
+	result := &CaseResult{Case: c}
+	req := &types.SimulateFlowRequest{Flow: flow}
+	for _, step := range c.Steps {
+		step := step.SimulationStep
+		req.Steps = append(req.Steps, &step)
+	}
+
+	simulator := service.NewSimulatorService(nil, clock.NewMock(Epoch))
+	resp, err := simulator.SimulateFlow(ctx, req)
+	if err != nil {
+		result.Failures = append(result.Failures, err.Error())
+		result.Duration = time.Since(start)
+		return result
+	}
+	for i, step := range c.Steps {
+		if step.Expect == nil {
+			if r := resp.Steps[i]; r.Error != "" {
+				result.Failures = append(result.Failures, fmt.Sprintf("step %v (%v): unexpected error: %v", i+1, step.Event, r.Error))
+			}
+			continue
+		}
+		for _, failure := range step.Expect.Check(resp.Steps[i]) {
+			result.Failures = append(result.Failures, fmt.Sprintf("step %v (%v): %v", i+1, step.Event, failure))
+		}
+	}
+	result.Duration = time.Since(start)
+	return result
+}
+
+// Check compares the result of a step with the expectation.
+func (e *Expect) Check(r *types.SimulationStepResult) (failures []string) {
+	failf := func(format string, args ...interface{}) {
+		failures = append(failures, fmt.Sprintf(format, args...))
+	}
+	switch {
+	case e.Error == "" && r.Error != "":
+		failf("unexpected error: %v", r.Error)
+	case e.Error != "" && !strings.Contains(r.Error, e.Error):
+		failf("expected error %q, got %q", e.Error, r.Error)
+	}
+	if e.Node != 0 && e.Node != r.ToNodeID {
+		failf("expected node %v, got %v", e.Node, r.ToNodeID)
+	}
+	if e.Replies != nil {
+		replies := make([]string, len(r.Messages))
+		for i, msg := range r.Messages {
+			replies[i] = msg.Text
+			if msg.Action != "" {
+				replies[i] = "<" + msg.Action + ">"
+			}
+		}
+		if !equalStrings(e.Replies, replies) {
+			failf("replies differ:\n%v", diffLines(e.Replies, replies))
+		}
+	}
+	names := make([]string, 0, len(e.Vars))
+	for name := range e.Vars {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		if value, ok := r.Vars[name]; !ok || value != e.Vars[name] {
+			failf("expected var %v=%q, got %q", name, e.Vars[name], value)
+		}
+	}
+	if e.Paused != nil && *e.Paused != r.Paused {
+		failf("expected paused=%v, got %v", *e.Paused, r.Paused)
+	}
+	return failures
+}
+
+func equalStrings(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// diffLines shows the expected and actual lines side by side, prefixed by "-"
+// and "+" when they differ.
+func diffLines(expected, actual []string) string {
+	var b strings.Builder
+	for i := 0; i < len(expected) || i < len(actual); i++ {
+		switch {
+		case i < len(expected) && i < len(actual) && expected[i] == actual[i]:
+			fmt.Fprintf(&b, "\t  %q\n", expected[i])
+		default:
+			if i < len(expected) {
+				fmt.Fprintf(&b, "\t- %q\n", expected[i])
+			}
+			if i < len(actual) {
+				fmt.Fprintf(&b, "\t+ %q\n", actual[i])
+			}
+		}
+	}
+	return strings.TrimRight(b.String(), "\n")
+}
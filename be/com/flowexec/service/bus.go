@@ -0,0 +1,80 @@
+package service
+
+import (
+	"context"
+	"sync"
+
+	"github.com/olvrng/rbot/be/com/flowexec/types"
+	"github.com/olvrng/rbot/be/pkg/clock"
+	"github.com/olvrng/rbot/be/pkg/dot"
+)
+
+// EventSubscriber receives the events of the bus. It is called synchronously by
+// Publish, so it must not block: a slow subscriber queues the events instead.
+type EventSubscriber func(ctx context.Context, event *types.FlowEvent)
+
+// EventBus publishes the flow events of the services, such as "message.sent",
+// to the subscribers, such as the outgoing webhooks. A nil bus drops the
+// events.
+type EventBus struct {
+	Clock clock.Clock
+
+	m           sync.RWMutex
+	lastID      int
+	subscribers []subscription
+}
+
+type subscription struct {
+	id int
+	fn EventSubscriber
+}
+
+func NewEventBus(clk clock.Clock) *EventBus {
+	b := &EventBus{Clock: clk}
+	return b
+}
+
+// Subscribe adds the subscriber, until unsubscribe is called, such as by the
+// live streams when the client goes away.
+func (b *EventBus) Subscribe(fn EventSubscriber) (unsubscribe func()) {
+	b.m.Lock()
+	defer b.m.Unlock()
+	b.lastID++
+	id := b.lastID
+	b.subscribers = append(b.subscribers, subscription{id: id, fn: fn})
+	return func() { b.unsubscribe(id) }
+}
+
+func (b *EventBus) unsubscribe(id int) {
+	b.m.Lock()
+	defer b.m.Unlock()
+	// copy the subscribers, since Publish reads them without the lock
+	subscribers := make([]subscription, 0, len(b.subscribers))
+	for _, sub := range b.subscribers {
+		if sub.id != id {
+			subscribers = append(subscribers, sub)
+		}
+	}
+	b.subscribers = subscribers
+}
+
+// Publish sets the id and the time of the event, then calls the subscribers in
+// order.
+func (b *EventBus) Publish(ctx context.Context, event *types.FlowEvent) {
+	if b == nil {
+		return
+	}
+	if event.ID == 0 {
+		event.ID = dot.NewIntID()
+	}
+	if event.CreatedAt == 0 {
+		event.CreatedAt = dot.ToTimestamp(b.Clock.Now())
+	}
+
+	b.m.RLock()
+	subscribers := b.subscribers
+	b.m.RUnlock()
+	for _, sub := range subscribers {
+		sub.fn(ctx, event)
+	}
+}
@@ -0,0 +1,60 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/olvrng/rbot/be/com/flowexec/types"
+	"github.com/olvrng/rbot/be/pkg/dot"
+)
+
+type eventRecorder struct {
+	m      sync.Mutex
+	events []*types.FlowEvent
+}
+
+func (r *eventRecorder) record(ctx context.Context, event *types.FlowEvent) {
+	r.m.Lock()
+	defer r.m.Unlock()
+	r.events = append(r.events, event)
+}
+
+// Take returns the recorded events as "type node_id", and clears them.
+func (r *eventRecorder) Take() []string {
+	r.m.Lock()
+	defer r.m.Unlock()
+	result := []string{}
+	for _, e := range r.events {
+		result = append(result, fmt.Sprintf("%v %v", e.Type, e.NodeID))
+	}
+	r.events = nil
+	return result
+}
+
+func TestEventBus(t *testing.T) {
+	ctx := context.Background()
+	st := newServiceTest(t, testFlowJSON)
+	recorder := &eventRecorder{}
+	st.bus.Subscribe(recorder.record)
+
+	st.completeOrder(t)
+	event := recorder.events[0]
+	require.NotZero(t, event.ID)
+	require.Equal(t, st.clock.Now().UnixNano()/1e6, int64(event.CreatedAt))
+	require.Equal(t, dot.IntID(testPSID), event.PSID)
+	require.Equal(t, "trigger:completed_order", event.Data["node_type"])
+	require.Equal(t, []string{"conversation.started 1", "node.entered 2"}, recorder.Take())
+
+	st.fire(t, 3*24*time.Hour)
+	require.Equal(t, []string{"node.entered 3", "message.sent 3"}, recorder.Take())
+
+	req := &types.ReceivedMessageRequest{PageID: testPageID, PSID: testPSID, Message: "great"}
+	_, err := st.messenger.ReceivedMessage(ctx, req)
+	require.NoError(t, err)
+	require.Equal(t, []string{"node.entered 5", "flow.completed 5", "message.sent 5"}, recorder.Take())
+}
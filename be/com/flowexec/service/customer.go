@@ -0,0 +1,243 @@
+package service
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"strconv"
+	"strings"
+
+	"github.com/olvrng/rbot/be/com/flowexec"
+	"github.com/olvrng/rbot/be/com/flowexec/store"
+	"github.com/olvrng/rbot/be/com/flowexec/types"
+	"github.com/olvrng/rbot/be/pkg/dot"
+	"github.com/olvrng/rbot/be/pkg/xerrors"
+)
+
+var _ flowexec.CustomerService = (*CustomerService)(nil)
+
+const (
+	LinkSourceReferral = "referral"
+	LinkSourceCheckbox = "checkbox"
+	LinkSourceSendAPI  = "send_api"
+	LinkSourceOrder    = "order"
+)
+
+// CustomerService links the customers of the shop to their Messenger identity.
+type CustomerService struct {
+	LinkStore  *store.CustomerLinkStore
+	OrderStore *store.OrderStore
+
+	// RefSecret signs the refs of the m.me links and the checkbox plugin, see
+	// SignRef. The refs are not linked when it is empty.
+	RefSecret string
+}
+
+func NewCustomerService(linkStore *store.CustomerLinkStore, orderStore *store.OrderStore) *CustomerService {
+	s := &CustomerService{
+		LinkStore:  linkStore,
+		OrderStore: orderStore,
+	}
+	return s
+}
+
+func (s *CustomerService) LinkCustomer(ctx context.Context, req *types.LinkCustomerRequest) (*types.CustomerLinkResponse, error) {
+	if req.PageID == 0 {
+		return nil, xerrors.Errorf(xerrors.InvalidArgument, nil, "page_id is required")
+	}
+	if req.PSID == 0 && req.UserRef == "" {
+		return nil, xerrors.Errorf(xerrors.InvalidArgument, nil, "psid or user_ref is required")
+	}
+	link := &types.CustomerLink{
+		PageID:      req.PageID,
+		CustomerRef: req.CustomerRef,
+		PSID:        req.PSID,
+		UserRef:     req.UserRef,
+		Source:      req.Source,
+	}
+	link, err := s.LinkStore.SaveLink(ctx, link)
+	switch {
+	case xerrors.GetCode(err) == xerrors.AlreadyExists:
+		return nil, err
+	case err != nil:
+		return nil, xerrors.Errorf(xerrors.Internal, err, "internal error")
+	}
+	return &types.CustomerLinkResponse{Link: link}, nil
+}
+
+func (s *CustomerService) GetCustomerLink(ctx context.Context, req *types.GetCustomerLinkRequest) (*types.CustomerLinkResponse, error) {
+	var link *types.CustomerLink
+	var err error
+	switch {
+	case req.CustomerRef != "":
+		link, err = s.LinkStore.GetByCustomerRef(ctx, req.PageID, req.CustomerRef)
+	case req.PSID != 0:
+		link, err = s.LinkStore.GetByPSID(ctx, req.PageID, req.PSID)
+	default:
+		return nil, xerrors.Errorf(xerrors.InvalidArgument, nil, "customer_ref or psid is required")
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &types.CustomerLinkResponse{Link: link}, nil
+}
+
+// LinkRef links the PSID to the customer identified by the ref param of an m.me
+// link. The ref is either "order:<id>", "customer:<id>" or just the customer id,
+// signed with SignRef. It returns the order when the ref is an order. The PSID
+// of an order or a customer which is already linked to another PSID is kept.
+func (s *CustomerService) LinkRef(ctx context.Context, pageID, psid dot.IntID, ref, source string) (*types.Order, error) {
+	if strings.TrimSpace(ref) == "" {
+		return nil, nil
+	}
+	kind, id, err := s.verifyRef(pageID, ref)
+	if err != nil || id == "" {
+		return nil, err
+	}
+
+	var order *types.Order
+	customerRef := id
+	if kind == "order" {
+		order, err = s.OrderStore.LoadOrder(ctx, pageID, id)
+		if err != nil {
+			return nil, err
+		}
+		switch order.PSID {
+		case psid:
+		case 0:
+			order.PSID = psid
+			if err = s.OrderStore.SaveOrder(ctx, order); err != nil {
+				return nil, err
+			}
+		default:
+			return nil, xerrors.Errorf(xerrors.AlreadyExists, nil, "order %v is linked to another psid", order.ID)
+		}
+		customerRef = order.CustomerRef
+		if customerRef == "" {
+			return order, nil
+		}
+	}
+	link := &types.CustomerLink{
+		PageID:      pageID,
+		CustomerRef: customerRef,
+		PSID:        psid,
+		Source:      source,
+	}
+	_, err = s.LinkStore.SaveLink(ctx, link)
+	return order, err
+}
+
+// LinkOptin links the user_ref of the checkbox plugin to the customer given in
+// its ref param, which is signed like the refs of LinkRef.
+func (s *CustomerService) LinkOptin(ctx context.Context, pageID dot.IntID, userRef, ref string) error {
+	if userRef == "" {
+		return xerrors.Errorf(xerrors.InvalidArgument, nil, "user_ref is required")
+	}
+	kind, id, err := s.verifyRef(pageID, ref)
+	if err != nil {
+		return err
+	}
+	link := &types.CustomerLink{
+		PageID:  pageID,
+		UserRef: userRef,
+		Source:  LinkSourceCheckbox,
+	}
+	switch kind {
+	case "order":
+		order, err := s.OrderStore.LoadOrder(ctx, pageID, id)
+		if err != nil {
+			return err
+		}
+		if order.UserRef == "" {
+			order.UserRef = userRef
+			if err = s.OrderStore.SaveOrder(ctx, order); err != nil {
+				return err
+			}
+		}
+		link.CustomerRef = order.CustomerRef
+	default:
+		link.CustomerRef = id
+	}
+	_, err = s.LinkStore.SaveLink(ctx, link)
+	return err
+}
+
+// LinkUserRef records the PSID of a user_ref, which is known after the first
+// message is sent to the user_ref.
+func (s *CustomerService) LinkUserRef(ctx context.Context, pageID dot.IntID, userRef string, psid dot.IntID) error {
+	link := &types.CustomerLink{
+		PageID:  pageID,
+		PSID:    psid,
+		UserRef: userRef,
+		Source:  LinkSourceSendAPI,
+	}
+	_, err := s.LinkStore.SaveLink(ctx, link)
+	return err
+}
+
+// ResolveRecipient finds the Messenger recipient of a customer. It returns
+// either the PSID, or the user_ref when the customer has not talked to the page
+// yet.
+func (s *CustomerService) ResolveRecipient(ctx context.Context, pageID dot.IntID, customerRef, userRef string) (psid dot.IntID, _ string, _ error) {
+	if link, err := s.LinkStore.GetByCustomerRef(ctx, pageID, customerRef); err == nil {
+		if link.PSID != 0 {
+			return link.PSID, "", nil
+		}
+		if userRef == "" {
+			userRef = link.UserRef
+		}
+	}
+	if link, err := s.LinkStore.GetByUserRef(ctx, pageID, userRef); err == nil && link.PSID != 0 {
+		return link.PSID, "", nil
+	}
+	if psid, err := s.OrderStore.FindPSIDByCustomerRef(ctx, pageID, customerRef); err == nil {
+		return psid, "", nil
+	}
+	if userRef != "" {
+		return 0, userRef, nil
+	}
+	return 0, "", xerrors.Errorf(xerrors.NotFound, nil, "customer is not linked to messenger")
+}
+
+// SignRef returns the ref param of the links to an order ("order:<id>") or a
+// customer, as "<ref>.<signature>". The signature is the hex of
+// HMAC-SHA256(secret, "<page_id>:<ref>"), so the refs of the orders can not be
+// guessed from their ids.
+func SignRef(secret string, pageID dot.IntID, ref string) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(strconv.FormatInt(int64(pageID), 10) + ":" + ref))
+	return ref + "." + hex.EncodeToString(mac.Sum(nil))
+}
+
+// OrderRef returns the signed ref of the m.me links to the order, or "" when
+// there is no RefSecret.
+func (s *CustomerService) OrderRef(pageID dot.IntID, orderID string) string {
+	if s.RefSecret == "" {
+		return ""
+	}
+	return SignRef(s.RefSecret, pageID, "order:"+orderID)
+}
+
+// verifyRef checks the signature of the ref, then returns its kind and id.
+func (s *CustomerService) verifyRef(pageID dot.IntID, ref string) (kind, id string, _ error) {
+	if s.RefSecret == "" {
+		return "", "", xerrors.Errorf(xerrors.FailedPrecondition, nil, "the refs can not be verified (set messenger.ref_secret in the config)")
+	}
+	ref = strings.TrimSpace(ref)
+	i := strings.LastIndexByte(ref, '.')
+	if i < 0 || !hmac.Equal([]byte(SignRef(s.RefSecret, pageID, ref[:i])), []byte(ref)) {
+		return "", "", xerrors.Errorf(xerrors.PermissionDenied, nil, "invalid ref signature")
+	}
+	kind, id = parseRef(ref[:i])
+	return kind, id, nil
+}
+
+func parseRef(ref string) (kind, id string) {
+	ref = strings.TrimSpace(ref)
+	parts := strings.SplitN(ref, ":", 2)
+	if len(parts) == 2 && (parts[0] == "order" || parts[0] == "customer") {
+		return parts[0], strings.TrimSpace(parts[1])
+	}
+	return "customer", ref
+}
@@ -0,0 +1,158 @@
+package service
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/olvrng/rbot/be/com/flowexec/store"
+	"github.com/olvrng/rbot/be/com/flowexec/types"
+	"github.com/olvrng/rbot/be/pkg/dot"
+	"github.com/olvrng/rbot/be/pkg/xerrors"
+)
+
+const testRefSecret = "secret"
+
+func newTestCustomerService(t *testing.T) *CustomerService {
+	dir := t.TempDir()
+	linkStore, err := store.NewCustomerLinkStore(filepath.Join(dir, "link.json"))
+	require.NoError(t, err)
+	orderStore, err := store.NewOrderStore(filepath.Join(dir, "order.json"))
+	require.NoError(t, err)
+	s := NewCustomerService(linkStore, orderStore)
+	s.RefSecret = testRefSecret
+	return s
+}
+
+func TestLinkRef(t *testing.T) {
+	ctx := context.Background()
+	saveOrder := func(t *testing.T, s *CustomerService, psid dot.IntID) {
+		order := &types.Order{ID: "o1", PageID: testPageID, CustomerRef: "c1", PSID: psid}
+		require.NoError(t, s.OrderStore.SaveOrder(ctx, order))
+	}
+
+	t.Run("link the order and the customer", func(t *testing.T) {
+		s := newTestCustomerService(t)
+		saveOrder(t, s, 0)
+		ref := s.OrderRef(testPageID, "o1")
+		require.Equal(t, ref, SignRef(testRefSecret, testPageID, "order:o1"))
+
+		order, err := s.LinkRef(ctx, testPageID, testPSID, ref, LinkSourceReferral)
+		require.NoError(t, err)
+		require.Equal(t, dot.IntID(testPSID), order.PSID)
+		link, err := s.LinkStore.GetByCustomerRef(ctx, testPageID, "c1")
+		require.NoError(t, err)
+		require.Equal(t, dot.IntID(testPSID), link.PSID)
+
+		// the same customer opens the link again
+		_, err = s.LinkRef(ctx, testPageID, testPSID, ref, LinkSourceReferral)
+		require.NoError(t, err)
+	})
+
+	t.Run("keep the psid of a linked order", func(t *testing.T) {
+		s := newTestCustomerService(t)
+		saveOrder(t, s, testPSID)
+		_, err := s.LinkRef(ctx, testPageID, 3000, s.OrderRef(testPageID, "o1"), LinkSourceReferral)
+		require.Equal(t, xerrors.AlreadyExists, xerrors.GetCode(err))
+
+		order, err := s.OrderStore.LoadOrder(ctx, testPageID, "o1")
+		require.NoError(t, err)
+		require.Equal(t, dot.IntID(testPSID), order.PSID)
+	})
+
+	t.Run("keep the psid of a linked customer", func(t *testing.T) {
+		s := newTestCustomerService(t)
+		ref := SignRef(testRefSecret, testPageID, "customer:c1")
+		_, err := s.LinkRef(ctx, testPageID, testPSID, ref, LinkSourceReferral)
+		require.NoError(t, err)
+		_, err = s.LinkRef(ctx, testPageID, 3000, ref, LinkSourceReferral)
+		require.Equal(t, xerrors.AlreadyExists, xerrors.GetCode(err))
+
+		link, err := s.LinkStore.GetByCustomerRef(ctx, testPageID, "c1")
+		require.NoError(t, err)
+		require.Equal(t, dot.IntID(testPSID), link.PSID)
+	})
+
+	t.Run("reject the refs which are not signed", func(t *testing.T) {
+		s := newTestCustomerService(t)
+		saveOrder(t, s, 0)
+		for _, ref := range []string{
+			"order:o1",
+			"order:o1.0000",
+			SignRef("other", testPageID, "order:o1"),
+			SignRef(testRefSecret, 1001, "order:o1"),
+		} {
+			_, err := s.LinkRef(ctx, testPageID, testPSID, ref, LinkSourceReferral)
+			require.Equal(t, xerrors.PermissionDenied, xerrors.GetCode(err), ref)
+		}
+		order, err := s.OrderStore.LoadOrder(ctx, testPageID, "o1")
+		require.NoError(t, err)
+		require.Zero(t, order.PSID)
+
+		s.RefSecret = ""
+		_, err = s.LinkRef(ctx, testPageID, testPSID, SignRef("", testPageID, "order:o1"), LinkSourceReferral)
+		require.Equal(t, xerrors.FailedPrecondition, xerrors.GetCode(err))
+		require.Empty(t, s.OrderRef(testPageID, "o1"))
+	})
+
+	t.Run("no ref", func(t *testing.T) {
+		s := newTestCustomerService(t)
+		order, err := s.LinkRef(ctx, testPageID, testPSID, "", LinkSourceReferral)
+		require.NoError(t, err)
+		require.Nil(t, order)
+	})
+}
+
+func TestLinkOptin(t *testing.T) {
+	ctx := context.Background()
+	s := newTestCustomerService(t)
+	order := &types.Order{ID: "o1", PageID: testPageID, CustomerRef: "c1"}
+	require.NoError(t, s.OrderStore.SaveOrder(ctx, order))
+
+	err := s.LinkOptin(ctx, testPageID, "user-ref", "order:o1")
+	require.Equal(t, xerrors.PermissionDenied, xerrors.GetCode(err))
+	err = s.LinkOptin(ctx, testPageID, "", s.OrderRef(testPageID, "o1"))
+	require.Equal(t, xerrors.InvalidArgument, xerrors.GetCode(err))
+
+	require.NoError(t, s.LinkOptin(ctx, testPageID, "user-ref", s.OrderRef(testPageID, "o1")))
+	order, err = s.OrderStore.LoadOrder(ctx, testPageID, "o1")
+	require.NoError(t, err)
+	require.Equal(t, "user-ref", order.UserRef)
+	link, err := s.LinkStore.GetByUserRef(ctx, testPageID, "user-ref")
+	require.NoError(t, err)
+	require.Equal(t, "c1", link.CustomerRef)
+
+	// the PSID is known after the first message
+	psid, userRef, err := s.ResolveRecipient(ctx, testPageID, "c1", "")
+	require.NoError(t, err)
+	require.Zero(t, psid)
+	require.Equal(t, "user-ref", userRef)
+	require.NoError(t, s.LinkUserRef(ctx, testPageID, "user-ref", testPSID))
+	psid, _, err = s.ResolveRecipient(ctx, testPageID, "c1", "")
+	require.NoError(t, err)
+	require.Equal(t, dot.IntID(testPSID), psid)
+}
+
+func TestOrderOfLinkedCustomer(t *testing.T) {
+	ctx := context.Background()
+	st := newServiceTest(t, testFlowJSON)
+	send := func(orderID string, psid dot.IntID) {
+		req := &types.ReceivedOrderEventRequest{
+			PageID: testPageID, PSID: psid, CustomerRef: "c1", OrderID: orderID, Status: types.OrderCompleted,
+		}
+		_, err := st.orders.ReceivedOrderEvent(ctx, req)
+		require.NoError(t, err)
+	}
+	send("o1", testPSID)
+
+	// the shop knows another PSID of the customer
+	send("o2", 3000)
+	order, err := st.orders.OrderStore.LoadOrder(ctx, testPageID, "o2")
+	require.NoError(t, err)
+	require.Equal(t, dot.IntID(3000), order.PSID)
+	link, err := st.orders.Customers.LinkStore.GetByCustomerRef(ctx, testPageID, "c1")
+	require.NoError(t, err)
+	require.Equal(t, dot.IntID(testPSID), link.PSID)
+}
@@ -0,0 +1,148 @@
+package service
+
+import (
+	"context"
+	"strconv"
+
+	flowdeftypes "github.com/olvrng/rbot/be/com/flowdef/types"
+	"github.com/olvrng/rbot/be/com/flowexec/flowcore"
+	"github.com/olvrng/rbot/be/com/flowexec/store"
+	"github.com/olvrng/rbot/be/com/flowexec/types"
+	"github.com/olvrng/rbot/be/com/review"
+	reviewtypes "github.com/olvrng/rbot/be/com/review/types"
+	"github.com/olvrng/rbot/be/pkg/dot"
+	"github.com/olvrng/rbot/be/pkg/l"
+)
+
+// EventHandler handles the events emitted by the executor during a transition,
+// then publishes them on the bus.
+type EventHandler struct {
+	Reviews      review.ReviewService
+	OrderStore   *store.OrderStore
+	HandoffStore *store.HandoffStore
+	Bus          *EventBus
+}
+
+func NewEventHandler(
+	reviews review.ReviewService,
+	orderStore *store.OrderStore,
+	handoffStore *store.HandoffStore,
+	bus *EventBus,
+) *EventHandler {
+	h := &EventHandler{
+		Reviews:      reviews,
+		OrderStore:   orderStore,
+		HandoffStore: handoffStore,
+		Bus:          bus,
+	}
+	return h
+}
+
+// flowEventTypes maps the events of the executor to the published events.
+var flowEventTypes = map[string]types.FlowEventType{
+	flowcore.EventConversationStarted: types.EventConversationStarted,
+	flowcore.EventNodeEntered:         types.EventNodeEntered,
+	flowcore.EventReviewSubmitted:     types.EventReviewSubmitted,
+	flowcore.EventHandoff:             types.EventHandoffRequested,
+	flowcore.EventFlowCompleted:       types.EventFlowCompleted,
+}
+
+// Handle handles the events of the transition to the given state. The errors
+// are only logged, since the conversation should continue anyway.
+func (h *EventHandler) Handle(ctx context.Context, flow *flowdeftypes.Flow, state *flowcore.FlowState, events []*flowcore.Event) {
+	for _, event := range events {
+		var err error
+		switch event.Type {
+		case flowcore.EventReviewSubmitted:
+			err = h.submitReview(ctx, state, event)
+
+		case flowcore.EventHandoff:
+			err = h.openHandoff(ctx, flow, state, event)
+
+		case flowcore.EventNodeEntered:
+			if node := flow.NodeByID(event.NodeID); node != nil {
+				transitionsTotal.Inc(string(node.Payload.Type()))
+			}
+
+		case flowcore.EventConversationStarted, flowcore.EventFlowCompleted:
+			// only published
+
+		default:
+			ls.Errorf("unknown event %v", event.Type)
+		}
+		if err != nil {
+			ll.Error("can not handle event", l.String("type", event.Type), l.Error(err))
+		}
+		h.publish(ctx, flow, state, event)
+	}
+}
+
+func (h *EventHandler) publish(ctx context.Context, flow *flowdeftypes.Flow, state *flowcore.FlowState, event *flowcore.Event) {
+	typ, ok := flowEventTypes[event.Type]
+	if !ok {
+		return
+	}
+	data := map[string]string{}
+	for k, v := range event.Data {
+		data[k] = v
+	}
+	if node := flow.NodeByID(event.NodeID); node != nil {
+		data["node_type"] = string(node.Payload.Type())
+	}
+	h.Bus.Publish(ctx, &types.FlowEvent{
+		Type:   typ,
+		PageID: state.PageID,
+		PSID:   state.PSID,
+		FlowID: flow.ID,
+		NodeID: event.NodeID,
+		Data:   data,
+	})
+}
+
+func (h *EventHandler) submitReview(ctx context.Context, state *flowcore.FlowState, event *flowcore.Event) error {
+	rating, _ := strconv.Atoi(event.Data[flowdeftypes.VarRating])
+	req := &reviewtypes.SubmitReviewRequest{
+		PageID:  state.PageID,
+		FlowID:  state.FlowID,
+		NodeID:  event.NodeID,
+		PSID:    state.PSID,
+		OrderID: event.Data["order_id"],
+		Rating:  rating,
+		Comment: event.Data[flowdeftypes.VarRatingComment],
+	}
+	if req.OrderID != "" {
+		if order, err := h.OrderStore.LoadOrder(ctx, state.PageID, req.OrderID); err == nil {
+			req.CustomerRef = order.CustomerRef
+		}
+	}
+	_, err := h.Reviews.SubmitReview(ctx, req)
+	return err
+}
+
+func (h *EventHandler) openHandoff(ctx context.Context, flow *flowdeftypes.Flow, state *flowcore.FlowState, event *flowcore.Event) error {
+	if state.PSID == 0 {
+		return nil // the customer has not talked to the page yet
+	}
+	if _, err := h.HandoffStore.GetOpenHandoff(ctx, state.PageID, state.PSID); err == nil {
+		return nil // already paused
+	}
+
+	now := dot.Now()
+	handoff := &types.Handoff{
+		ID:        dot.NewIntID(),
+		PageID:    state.PageID,
+		PSID:      state.PSID,
+		FlowID:    state.FlowID,
+		NodeID:    event.NodeID,
+		Status:    types.HandoffOpen,
+		Reason:    event.Data["reason"],
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+	if node := flow.NodeByID(event.NodeID); node != nil {
+		if payload, ok := node.Payload.Data.(*flowdeftypes.HandoffNodeData); ok {
+			handoff.ThreadPassed = payload.PassThreadControl
+		}
+	}
+	return h.HandoffStore.SaveHandoff(ctx, handoff)
+}
@@ -2,10 +2,16 @@ package service
 
 import (
 	"context"
+	"fmt"
+	"strconv"
+	"strings"
 	"sync"
 	"time"
 
+	conversationtypes "github.com/olvrng/rbot/be/com/conversation/types"
 	"github.com/olvrng/rbot/be/com/flowdef/types"
+	"github.com/olvrng/rbot/be/com/flowexec/flowcore"
+	flowexectypes "github.com/olvrng/rbot/be/com/flowexec/types"
 	"github.com/olvrng/rbot/be/com/integration/fbmsg"
 	"github.com/olvrng/rbot/be/pkg/dot"
 	"github.com/olvrng/rbot/be/pkg/l"
@@ -16,27 +22,85 @@ var ll = l.New()
 var ls = ll.Sugar()
 
 type ActionState struct {
-	PageID dot.IntID
-	PSID   dot.IntID
-	Extra  map[string]string
+	PageID  dot.IntID
+	PSID    dot.IntID
+	FlowID  dot.IntID
+	UserRef string // used as the recipient when PSID is not known yet
+	Agent   string // the agent who replies when the bot is paused
+	Extra   map[string]string
+	Retries int
+	Phase   string
+
+	// Data is used for rendering templates. It contains the event data and the
+	// conversation variables.
+	Data map[string]string
+}
+
+func NewActionState(state *flowcore.FlowState) *ActionState {
+	return &ActionState{
+		PageID:  state.PageID,
+		PSID:    state.PSID,
+		FlowID:  state.FlowID,
+		Extra:   state.Extra,
+		Retries: state.Retries,
+		Phase:   state.Phase,
+		Data:    state.TemplateData(),
+	}
+}
+
+// UserRefLinker records the PSID of a user_ref after the first message is sent.
+type UserRefLinker interface {
+	LinkUserRef(ctx context.Context, pageID dot.IntID, userRef string, psid dot.IntID) error
+}
+
+// Messenger calls the Messenger Platform. It is implemented by fbmsg.Client,
+// and by CaptureMessenger when simulating flows.
+type Messenger interface {
+	SendMessage(ctx context.Context, req *fbmsg.SendRequest) (*fbmsg.SendResponse, error)
+
+	PassThreadControl(ctx context.Context, req *fbmsg.PassThreadControlRequest) error
+
+	TakeThreadControl(ctx context.Context, req *fbmsg.TakeThreadControlRequest) error
+}
+
+// MessageRecorder records the messages sent to the customer in the transcript.
+type MessageRecorder interface {
+	RecordMessage(ctx context.Context, msg *conversationtypes.Message) error
 }
 
 type ActionExecutor struct {
-	FBClient *fbmsg.Client
+	FBClient Messenger
+	Linker   UserRefLinker
+	Recorder MessageRecorder
+	Bus      *EventBus
+
+	// HTTP makes the requests of the http_request nodes, see NewExecutor.
+	HTTP flowcore.HTTPRequester
 }
 
-func NewActionExecutor(fbClient *fbmsg.Client) *ActionExecutor {
-	ex := &ActionExecutor{FBClient: fbClient}
+func NewActionExecutor(fbClient Messenger, linker UserRefLinker, recorder MessageRecorder, bus *EventBus) *ActionExecutor {
+	ex := &ActionExecutor{FBClient: fbClient, Linker: linker, Recorder: recorder, Bus: bus}
 	return ex
 }
 
-func (ex *ActionExecutor) ExecuteActions(nodes []*types.Node, state *ActionState) {
+// NewExecutor creates the executor of the flow, which makes the requests of the
+// http_request nodes with the HTTP client of the action executor.
+func (ex *ActionExecutor) NewExecutor(flow *types.Flow, state *flowcore.FlowState) *flowcore.Executor {
+	fex := flowcore.NewExecutor(flow, state)
+	fex.HTTP = ex.HTTP
+	return fex
+}
+
+// ExecuteActions executes the actions of the nodes concurrently. They run in
+// the workspace of the context, so the messages are recorded and published in
+// the workspace of the conversation.
+func (ex *ActionExecutor) ExecuteActions(ctx context.Context, nodes []*types.Node, state *ActionState) {
 	ls.Debug("execute actions: ", nodes)
 	if len(nodes) == 0 {
 		return
 	}
 
-	ctx, ctxCancel := context.WithTimeout(context.Background(), 10*time.Second)
+	ctx, ctxCancel := context.WithTimeout(ctx, 10*time.Second)
 	var wg sync.WaitGroup
 	wg.Add(len(nodes))
 	for _, node := range nodes {
@@ -49,6 +113,33 @@ func (ex *ActionExecutor) ExecuteActions(nodes []*types.Node, state *ActionState
 	ctxCancel()
 }
 
+// ActionHandler executes the action of a node type when the conversation
+// arrives at the node, such as sending a message. The methods of ActionExecutor
+// like Send are available to it.
+type ActionHandler func(ex *ActionExecutor, ctx context.Context, node *types.Node, state *ActionState) error
+
+var actionRegistry = map[types.NodeType]ActionHandler{}
+
+// RegisterAction registers the handler of a node type, along with
+// types.RegisterNode. A package which defines node types registers them in its
+// init function.
+func RegisterAction(typ types.NodeType, handler ActionHandler) {
+	if actionRegistry[typ] != nil {
+		panic(fmt.Sprintf("register action: %v is already registered", typ))
+	}
+	actionRegistry[typ] = handler
+}
+
+func init() {
+	RegisterAction(types.NodeSendMessage, (*ActionExecutor).execSendMessage)
+	RegisterAction(types.NodeCaptureInput, (*ActionExecutor).execCaptureInput)
+	RegisterAction(types.NodeAskRating, (*ActionExecutor).execAskRating)
+	RegisterAction(types.NodeHandoff, (*ActionExecutor).execHandoff)
+	RegisterAction(types.NodeWait, func(ex *ActionExecutor, ctx context.Context, node *types.Node, state *ActionState) error {
+		return nil // the scheduler continues the flow later
+	})
+}
+
 func (ex *ActionExecutor) ExecuteAction(ctx context.Context, node *types.Node, state *ActionState) (_err error) {
 	defer func() {
 		if re := recover(); re != nil {
@@ -59,22 +150,24 @@ func (ex *ActionExecutor) ExecuteAction(ctx context.Context, node *types.Node, s
 		}
 	}()
 
-	switch node.Payload.Type() {
-	case types.NodeSendMessage:
-		return ex.execSendMessage(ctx, node, state)
-
-	default:
+	handler := actionRegistry[node.Payload.Type()]
+	if handler == nil {
 		ls.Error("unknown node type ", node)
 		return xerrors.Errorf(xerrors.Internal, nil, "unknown node type")
 	}
+	return handler(ex, ctx, node, state)
 }
 
 func (ex *ActionExecutor) execSendMessage(ctx context.Context, node *types.Node, state *ActionState) error {
-	payload := node.Payload.SendMessage
+	payload := node.Payload.Data.(*types.SendMessageNodeData)
+	text, err := flowcore.RenderTemplate(payload.Template, state.Data)
+	if err != nil {
+		return err
+	}
 
 	respMsg := &fbmsg.SendMessageData{}
 	if len(payload.QuickReplies) == 0 {
-		respMsg.Text = payload.Template
+		respMsg.Text = text
 
 	} else {
 		buttons := make([]*fbmsg.ButtonItem, 0, len(payload.QuickReplies))
@@ -92,7 +185,7 @@ func (ex *ActionExecutor) execSendMessage(ctx context.Context, node *types.Node,
 				TemplateType: fbmsg.TemplateTypeGeneric,
 				Elements: []*fbmsg.ElementItem{
 					{
-						Title:         payload.Template,
+						Title:         text,
 						Subtitle:      "",
 						DefaultAction: nil,
 						Buttons:       buttons,
@@ -102,9 +195,168 @@ func (ex *ActionExecutor) execSendMessage(ctx context.Context, node *types.Node,
 		}
 	}
 
+	return ex.Send(ctx, node, state, respMsg)
+}
+
+func (ex *ActionExecutor) execCaptureInput(ctx context.Context, node *types.Node, state *ActionState) error {
+	payload := node.Payload.Data.(*types.CaptureInputNodeData)
+	tpl := payload.Template
+	if state.Retries > 0 && payload.RetryTemplate != "" {
+		tpl = payload.RetryTemplate
+	}
+	text, err := flowcore.RenderTemplate(tpl, state.Data)
+	if err != nil {
+		return err
+	}
+
+	return ex.Send(ctx, node, state, &fbmsg.SendMessageData{Text: text})
+}
+
+func (ex *ActionExecutor) execAskRating(ctx context.Context, node *types.Node, state *ActionState) error {
+	payload := node.Payload.Data.(*types.AskRatingNodeData)
+	if state.Phase == flowcore.PhaseRatingComment {
+		text, err := flowcore.RenderTemplate(payload.CommentTemplate, state.Data)
+		if err != nil {
+			return err
+		}
+		skipText := payload.SkipText
+		if skipText == "" {
+			skipText = "Skip"
+		}
+		msg := &fbmsg.SendMessageData{
+			Text: text,
+			QuickReplies: []*fbmsg.QuickReplyItem{
+				{ContentType: fbmsg.ContentTypeText, Title: skipText, Payload: types.RatingPayloadSkip},
+			},
+		}
+		return ex.Send(ctx, node, state, msg)
+	}
+
+	tpl := payload.Template
+	if state.Retries > 0 && payload.RetryTemplate != "" {
+		tpl = payload.RetryTemplate
+	}
+	text, err := flowcore.RenderTemplate(tpl, state.Data)
+	if err != nil {
+		return err
+	}
+	msg := &fbmsg.SendMessageData{Text: text}
+	for i := 1; i <= types.MaxRating; i++ {
+		msg.QuickReplies = append(msg.QuickReplies, &fbmsg.QuickReplyItem{
+			ContentType: fbmsg.ContentTypeText,
+			Title:       strings.Repeat("⭐", i),
+			Payload:     types.RatingPayloadPrefix + strconv.Itoa(i),
+		})
+	}
+	return ex.Send(ctx, node, state, msg)
+}
+
+func (ex *ActionExecutor) execHandoff(ctx context.Context, node *types.Node, state *ActionState) error {
+	payload := node.Payload.Data.(*types.HandoffNodeData)
+	if payload.Template != "" {
+		text, err := flowcore.RenderTemplate(payload.Template, state.Data)
+		if err != nil {
+			return err
+		}
+		if err = ex.Send(ctx, node, state, &fbmsg.SendMessageData{Text: text}); err != nil {
+			return err
+		}
+	}
+	if !payload.PassThreadControl || state.PSID == 0 {
+		return nil
+	}
+	appID := payload.TargetAppID
+	if appID == 0 {
+		appID = fbmsg.PageInboxAppID
+	}
+	req := &fbmsg.PassThreadControlRequest{
+		Recipient:   &fbmsg.SendRecipientData{ID: state.PSID},
+		TargetAppID: appID,
+		Metadata:    payload.Reason,
+	}
+	return ex.FBClient.PassThreadControl(ctx, req)
+}
+
+// Send sends the message produced by the node, records it in the transcript,
+// then publishes message.sent or message.failed. The node is nil when the
+// message is sent by an agent.
+func (ex *ActionExecutor) Send(ctx context.Context, node *types.Node, state *ActionState, msg *fbmsg.SendMessageData) (_err error) {
+	var mid string
+	defer func() {
+		ex.publishMessage(ctx, node, state, msg, mid, _err)
+	}()
+
+	recipient := &fbmsg.SendRecipientData{ID: state.PSID}
+	if state.PSID == 0 && state.UserRef != "" {
+		recipient = &fbmsg.SendRecipientData{UserRef: state.UserRef}
+	}
 	sendReq := &fbmsg.SendRequest{
-		Recipient: &fbmsg.SendRecipientData{ID: state.PSID},
-		Message:   respMsg,
+		Recipient: recipient,
+		Message:   msg,
+	}
+	resp, err := ex.FBClient.SendMessage(ctx, sendReq)
+	if err != nil {
+		return err
+	}
+	mid = resp.MessageID
+	if recipient.UserRef != "" && resp.RecipientID != 0 && ex.Linker != nil {
+		if err = ex.Linker.LinkUserRef(ctx, state.PageID, recipient.UserRef, resp.RecipientID); err != nil {
+			return err
+		}
+	}
+	if ex.Recorder == nil {
+		return nil
+	}
+	logMsg := &conversationtypes.Message{
+		PageID:    state.PageID,
+		PSID:      state.PSID,
+		MID:       resp.MessageID,
+		Direction: conversationtypes.Outbound,
+		Text:      messageText(msg),
+		Agent:     state.Agent,
+	}
+	if logMsg.PSID == 0 {
+		logMsg.PSID = resp.RecipientID
+	}
+	if node != nil {
+		logMsg.FlowID, logMsg.NodeID = state.FlowID, node.ID
+	}
+	return ex.Recorder.RecordMessage(ctx, logMsg)
+}
+
+func (ex *ActionExecutor) publishMessage(ctx context.Context, node *types.Node, state *ActionState, msg *fbmsg.SendMessageData, mid string, err error) {
+	event := &flowexectypes.FlowEvent{
+		Type:   flowexectypes.EventMessageSent,
+		PageID: state.PageID,
+		PSID:   state.PSID,
+		FlowID: state.FlowID,
+		Data:   map[string]string{"text": messageText(msg)},
+	}
+	if node != nil {
+		event.NodeID = node.ID
+	}
+	if state.Agent != "" {
+		event.Data["agent"] = state.Agent
+	}
+	if mid != "" {
+		event.Data["mid"] = mid
+	}
+	if err != nil {
+		event.Type = flowexectypes.EventMessageFailed
+		event.Data["error"] = err.Error()
+	}
+	ex.Bus.Publish(ctx, event)
+}
+
+// messageText returns the text which is shown to the customer, for the
+// transcript.
+func messageText(msg *fbmsg.SendMessageData) string {
+	if msg.Text != "" || msg.Attachment == nil || msg.Attachment.Payload == nil {
+		return msg.Text
+	}
+	var titles []string
+	for _, el := range msg.Attachment.Payload.Elements {
+		titles = append(titles, el.Title)
 	}
-	return ex.FBClient.CallSendAPI(ctx, sendReq)
+	return strings.Join(titles, "\n")
 }
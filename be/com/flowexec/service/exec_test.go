@@ -0,0 +1,143 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	conversationservice "github.com/olvrng/rbot/be/com/conversation/service"
+	conversationstore "github.com/olvrng/rbot/be/com/conversation/store"
+	conversationtypes "github.com/olvrng/rbot/be/com/conversation/types"
+	flowdeftypes "github.com/olvrng/rbot/be/com/flowdef/types"
+	"github.com/olvrng/rbot/be/com/flowexec/flowcore"
+	"github.com/olvrng/rbot/be/com/flowexec/types"
+	"github.com/olvrng/rbot/be/com/integration/fbmsg"
+	"github.com/olvrng/rbot/be/com/workspace"
+	"github.com/olvrng/rbot/be/pkg/clock"
+	"github.com/olvrng/rbot/be/pkg/dot"
+)
+
+const nodeCoupon = "action:coupon"
+
+// couponNodeData is a custom node type, which sends a coupon code then waits
+// for the next message.
+type couponNodeData struct {
+	Type   flowdeftypes.NodeType `json:"type"`
+	Code   string                `json:"code"`
+	NextID dot.IntID             `json:"next_id,omitempty"`
+}
+
+func (n *couponNodeData) NodeType() flowdeftypes.NodeType {
+	return nodeCoupon
+}
+
+func (n *couponNodeData) Next(typ flowdeftypes.NodeType, data map[string]string) dot.IntID {
+	if typ == flowdeftypes.NodeReceivedMessage {
+		return n.NextID
+	}
+	return 0
+}
+
+func (n *couponNodeData) Links() []*flowdeftypes.Link {
+	return []*flowdeftypes.Link{{NextID: n.NextID}}
+}
+
+func execCoupon(ex *ActionExecutor, ctx context.Context, node *flowdeftypes.Node, state *ActionState) error {
+	payload := node.Payload.Data.(*couponNodeData)
+	text, err := flowcore.RenderTemplate("{{.name}}, your code is "+payload.Code, state.Data)
+	if err != nil {
+		return err
+	}
+	return ex.Send(ctx, node, state, &fbmsg.SendMessageData{Text: text})
+}
+
+func init() {
+	flowdeftypes.RegisterNode(nodeCoupon, func() flowdeftypes.NodeData { return &couponNodeData{} })
+	RegisterAction(nodeCoupon, execCoupon)
+}
+
+const testCustomFlowJSON = `{
+	"id": "1",
+	"nodes": [
+		{"id": "1", "payload": {"type": "trigger:received_message", "next_id": "2"}},
+		{"id": "2", "payload": {"type": "action:set_variable",
+			"assignments": [{"variable": "name", "expr": "Ann"}], "next_id": "3"}},
+		{"id": "3", "payload": {"type": "action:coupon", "code": "SALE10", "next_id": "4"}},
+		{"id": "4", "payload": {"type": "action:lottery", "prizes": [1, 2, 3]}}
+	]
+}`
+
+func TestRegisterAction(t *testing.T) {
+	var flow flowdeftypes.Flow
+	require.NoError(t, json.Unmarshal([]byte(testCustomFlowJSON), &flow))
+	s := NewSimulatorService(nil, clock.NewMock(time.Date(2021, 6, 1, 10, 0, 0, 0, time.UTC)))
+	resp, err := s.SimulateFlow(context.Background(), &types.SimulateFlowRequest{
+		Flow: &flow,
+		Steps: []*types.SimulationStep{
+			{Event: types.SimulateMessage, Text: "hi"},
+			{Event: types.SimulateMessage, Text: "thanks"},
+		},
+	})
+	require.NoError(t, err)
+
+	results := resp.Steps
+	require.Equal(t, dot.IntID(3), results[0].ToNodeID)
+	require.Len(t, results[0].Messages, 1)
+	require.Equal(t, "Ann, your code is SALE10", results[0].Messages[0].Text)
+
+	// the unknown node is kept, but has no action
+	require.Equal(t, dot.IntID(4), results[1].ToNodeID)
+	require.Empty(t, results[1].Messages)
+
+	out, err := json.Marshal(flow.NodeByID(4))
+	require.NoError(t, err)
+	require.JSONEq(t, `{"id": "4", "payload": {"type": "action:lottery", "prizes": [1, 2, 3]}}`, string(out))
+}
+
+func TestExecuteActionsInWorkspace(t *testing.T) {
+	st := newServiceTest(t, testFlowJSON)
+	messageStore, err := conversationstore.NewMessageStore(filepath.Join(st.dir, "message.json"))
+	require.NoError(t, err)
+	conversations := conversationservice.NewConversationService(messageStore)
+	st.actionExec.Recorder = conversations
+	var m sync.Mutex
+	var events []string
+	st.bus.Subscribe(func(ctx context.Context, event *types.FlowEvent) {
+		if event.Type == types.EventMessageSent || event.Type == types.EventMessageFailed {
+			m.Lock()
+			events = append(events, fmt.Sprintf("%v %v", event.Type, workspace.GetID(ctx)))
+			m.Unlock()
+		}
+	})
+
+	// the timer sends the first message, then the reply of the customer
+	// sends the second one
+	ctx := workspace.WithID(context.Background(), 7)
+	req := &types.ReceivedCompletedOrderRequest{PageID: testPageID, PSID: testPSID, OrderID: "order-1"}
+	_, err = st.orders.ReceivedCompletedOrder(ctx, req)
+	require.NoError(t, err)
+	require.Equal(t, 1, st.fire(t, 3*24*time.Hour))
+	_, err = st.messenger.ReceivedMessage(ctx, &types.ReceivedMessageRequest{PageID: testPageID, PSID: testPSID, Message: "great"})
+	require.NoError(t, err)
+
+	require.Equal(t, []string{"message.sent 7", "message.sent 7"}, events)
+	transcriptReq := &conversationtypes.GetTranscriptRequest{PageID: testPageID, PSID: testPSID}
+	transcript, err := conversations.GetTranscript(ctx, transcriptReq)
+	require.NoError(t, err)
+	require.Len(t, transcript.Messages, 2)
+	for _, msg := range transcript.Messages {
+		require.Equal(t, dot.IntID(7), msg.WorkspaceID)
+	}
+	require.Equal(t, "Thank you!", transcript.Messages[1].Text)
+
+	// the default workspace does not see the transcript
+	transcript, err = conversations.GetTranscript(context.Background(), transcriptReq)
+	require.NoError(t, err)
+	require.Empty(t, transcript.Messages)
+}
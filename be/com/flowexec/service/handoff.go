@@ -0,0 +1,219 @@
+package service
+
+import (
+	"context"
+
+	"github.com/olvrng/rbot/be/com/flowdef"
+	flowdeftypes "github.com/olvrng/rbot/be/com/flowdef/types"
+	"github.com/olvrng/rbot/be/com/flowexec"
+	"github.com/olvrng/rbot/be/com/flowexec/flowcore"
+	"github.com/olvrng/rbot/be/com/flowexec/store"
+	"github.com/olvrng/rbot/be/com/flowexec/types"
+	"github.com/olvrng/rbot/be/com/integration/fbmsg"
+	"github.com/olvrng/rbot/be/pkg/dot"
+	"github.com/olvrng/rbot/be/pkg/l"
+	"github.com/olvrng/rbot/be/pkg/xerrors"
+)
+
+var _ flowexec.HandoffService = (*HandoffService)(nil)
+
+// HandoffService is the inbox of the conversations which are handled by people
+// instead of the bot.
+type HandoffService struct {
+	FlowQuery    flowdef.QueryService
+	StateStore   *store.FlowStateStore
+	HandoffStore *store.HandoffStore
+	Events       *EventHandler
+	ActionExec   *ActionExecutor
+	Scheduler    *Scheduler
+}
+
+func NewHandoffService(
+	query flowdef.QueryService,
+	stateStore *store.FlowStateStore,
+	handoffStore *store.HandoffStore,
+	events *EventHandler,
+	actionExec *ActionExecutor,
+	scheduler *Scheduler,
+) *HandoffService {
+	s := &HandoffService{
+		FlowQuery:    query,
+		StateStore:   stateStore,
+		HandoffStore: handoffStore,
+		Events:       events,
+		ActionExec:   actionExec,
+		Scheduler:    scheduler,
+	}
+	return s
+}
+
+func (s *HandoffService) StartHandoff(ctx context.Context, req *types.StartHandoffRequest) (*types.HandoffResponse, error) {
+	if req.PageID == 0 || req.PSID == 0 {
+		return nil, xerrors.Errorf(xerrors.InvalidArgument, nil, "page_id and psid are required")
+	}
+	if handoff, err := s.HandoffStore.GetOpenHandoff(ctx, req.PageID, req.PSID); err == nil {
+		return &types.HandoffResponse{Handoff: handoff}, nil
+	}
+
+	now := dot.Now()
+	handoff := &types.Handoff{
+		ID:        dot.NewIntID(),
+		PageID:    req.PageID,
+		PSID:      req.PSID,
+		Status:    types.HandoffOpen,
+		Reason:    req.Reason,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+	if state, err := s.StateStore.LoadActiveState(ctx, req.PageID, req.PSID); err == nil {
+		handoff.FlowID = state.FlowID
+	}
+	if err := s.HandoffStore.SaveHandoff(ctx, handoff); err != nil {
+		return nil, xerrors.Errorf(xerrors.Internal, err, "internal error")
+	}
+	// the handoffs started by the agents are published as the ones of the flows
+	s.Events.Bus.Publish(ctx, &types.FlowEvent{
+		Type:   types.EventHandoffRequested,
+		PageID: handoff.PageID,
+		PSID:   handoff.PSID,
+		FlowID: handoff.FlowID,
+		Data:   map[string]string{"reason": handoff.Reason},
+	})
+	return &types.HandoffResponse{Handoff: handoff}, nil
+}
+
+func (s *HandoffService) ListHandoffs(ctx context.Context, req *types.ListHandoffsRequest) (*types.ListHandoffsResponse, error) {
+	if req.PageID == 0 {
+		return nil, xerrors.Errorf(xerrors.InvalidArgument, nil, "page_id is required")
+	}
+	handoffs, err := s.HandoffStore.ListHandoffs(ctx, req.PageID, req.Status)
+	if err != nil {
+		return nil, err
+	}
+	return &types.ListHandoffsResponse{Handoffs: handoffs}, nil
+}
+
+func (s *HandoffService) GetHandoff(ctx context.Context, req *types.GetHandoffRequest) (*types.HandoffResponse, error) {
+	handoff, err := s.HandoffStore.GetOpenHandoff(ctx, req.PageID, req.PSID)
+	if err != nil {
+		return nil, err
+	}
+	return &types.HandoffResponse{Handoff: handoff}, nil
+}
+
+func (s *HandoffService) ReplyHandoff(ctx context.Context, req *types.ReplyHandoffRequest) (*types.HandoffResponse, error) {
+	if req.Text == "" {
+		return nil, xerrors.Errorf(xerrors.InvalidArgument, nil, "text is required")
+	}
+	handoff, err := s.HandoffStore.GetOpenHandoff(ctx, req.PageID, req.PSID)
+	if err != nil {
+		return nil, err
+	}
+
+	actionState := &ActionState{PageID: req.PageID, PSID: req.PSID, Agent: req.Agent}
+	if err = s.ActionExec.Send(ctx, nil, actionState, &fbmsg.SendMessageData{Text: req.Text}); err != nil {
+		return nil, xerrors.Errorf(xerrors.Internal, err, "can not send message")
+	}
+	msg := &types.HandoffMessage{
+		From:      types.SenderAgent,
+		Agent:     req.Agent,
+		Text:      req.Text,
+		CreatedAt: dot.Now(),
+	}
+	if _, err = s.HandoffStore.AddMessage(ctx, req.PageID, req.PSID, msg); err != nil {
+		return nil, xerrors.Errorf(xerrors.Internal, err, "internal error")
+	}
+	return &types.HandoffResponse{Handoff: handoff}, nil
+}
+
+func (s *HandoffService) ResolveHandoff(ctx context.Context, req *types.ResolveHandoffRequest) (*types.HandoffResponse, error) {
+	unlock := s.StateStore.LockConversation(req.PageID, req.PSID)
+	defer unlock()
+	handoff, err := s.HandoffStore.GetOpenHandoff(ctx, req.PageID, req.PSID)
+	if err != nil {
+		return nil, err
+	}
+
+	if handoff.ThreadPassed {
+		takeReq := &fbmsg.TakeThreadControlRequest{
+			Recipient: &fbmsg.SendRecipientData{ID: req.PSID},
+		}
+		if err = s.ActionExec.FBClient.TakeThreadControl(ctx, takeReq); err != nil {
+			// the thread may have been passed back already
+			ll.Warn("can not take thread control", l.ID("psid", req.PSID), l.Error(err))
+		}
+	}
+	handoff.Status = types.HandoffResolved
+	handoff.ResolvedAt = dot.Now()
+	handoff.ResolvedBy = req.Agent
+	handoff.UpdatedAt = handoff.ResolvedAt
+	if err = s.HandoffStore.SaveHandoff(ctx, handoff); err != nil {
+		return nil, xerrors.Errorf(xerrors.Internal, err, "internal error")
+	}
+
+	if err = s.resume(ctx, handoff, req.ResumeNodeID); err != nil {
+		return nil, err
+	}
+	return &types.HandoffResponse{Handoff: handoff}, nil
+}
+
+// resume continues the flow of the handoff at the given node, or at the next
+// node of the handoff node. Without any, the conversation waits for the next
+// message.
+func (s *HandoffService) resume(ctx context.Context, handoff *types.Handoff, nodeID dot.IntID) error {
+	var state *flowcore.FlowState
+	var err error
+	if handoff.FlowID != 0 {
+		state, err = s.StateStore.LoadState(ctx, handoff.PageID, handoff.PSID, handoff.FlowID)
+	} else {
+		state, err = s.StateStore.LoadActiveState(ctx, handoff.PageID, handoff.PSID)
+	}
+	if xerrors.GetCode(err) == xerrors.NotFound {
+		return nil
+	}
+	if err != nil {
+		return xerrors.Errorf(xerrors.Internal, err, "internal error")
+	}
+	flowReq := &flowdeftypes.GetFlowByIDRequest{ID: state.FlowID}
+	flowResp, err := s.FlowQuery.GetFlowByID(ctx, flowReq)
+	if err != nil {
+		return xerrors.Errorf(xerrors.NotFound, err, "flow not found")
+	}
+	flow := flowResp.Flow
+
+	if nodeID == 0 {
+		if node := flow.NodeByID(handoff.NodeID); node != nil {
+			if payload, ok := node.Payload.Data.(*flowdeftypes.HandoffNodeData); ok {
+				nodeID = payload.NextID
+			}
+		}
+	}
+	if nodeID == 0 {
+		return nil
+	}
+	if flow.NodeByID(nodeID) == nil {
+		return xerrors.Errorf(xerrors.InvalidArgument, nil, "node %v not found", nodeID)
+	}
+
+	ex := s.ActionExec.NewExecutor(flow, state)
+	nextState, nextNodes, err := ex.Goto(nodeID, nil)
+	if err != nil {
+		return err
+	}
+	s.Events.Handle(ctx, flow, nextState, ex.Events)
+	actionState := NewActionState(nextState)
+	s.ActionExec.ExecuteActions(ctx, nextNodes, actionState)
+
+	return saveState(ctx, s.StateStore, s.Scheduler, flow, nextState, ex.Events)
+}
+
+// StoreMessage keeps the message in the inbox when the bot is paused for the
+// conversation. It returns false when the bot is not paused.
+func (s *HandoffService) StoreMessage(ctx context.Context, pageID, psid dot.IntID, from types.MessageSender, text string) (paused bool, _ error) {
+	msg := &types.HandoffMessage{
+		From:      from,
+		Text:      text,
+		CreatedAt: dot.Now(),
+	}
+	return s.HandoffStore.AddMessage(ctx, pageID, psid, msg)
+}
@@ -0,0 +1,95 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/olvrng/rbot/be/com/flowexec/types"
+	"github.com/olvrng/rbot/be/pkg/dot"
+	"github.com/olvrng/rbot/be/pkg/xerrors"
+)
+
+const testHandoffFlowJSON = `{
+	"id": "1",
+	"page_ids": ["1000"],
+	"nodes": [
+		{"id": "1", "payload": {"type": "trigger:received_message", "next_id": "2"}},
+		{"id": "2", "payload": {"type": "action:handoff", "template": "An agent will reply soon",
+			"reason": "help", "pass_thread_control": true, "next_id": "3"}},
+		{"id": "3", "payload": {"type": "action:send_message", "template": "Welcome back!"}}
+	]
+}`
+
+func TestHandoff(t *testing.T) {
+	ctx := context.Background()
+	receive := func(t *testing.T, st *serviceTest, text string) {
+		req := &types.ReceivedMessageRequest{PageID: testPageID, PSID: testPSID, Message: text}
+		_, err := st.messenger.ReceivedMessage(ctx, req)
+		require.NoError(t, err)
+	}
+
+	t.Run("pause, reply and resume", func(t *testing.T) {
+		st := newServiceTest(t, testHandoffFlowJSON)
+		receive(t, st, "I need help")
+		require.Equal(t, []string{"An agent will reply soon", "pass_thread_control"}, st.transport.Sent())
+
+		// the bot is paused, the message is stored instead
+		receive(t, st, "anyone?")
+		require.Len(t, st.transport.Sent(), 2)
+		getReq := &types.GetHandoffRequest{PageID: testPageID, PSID: testPSID}
+		resp, err := st.handoffs.GetHandoff(ctx, getReq)
+		require.NoError(t, err)
+		require.Equal(t, "help", resp.Handoff.Reason)
+		require.Equal(t, dot.IntID(2), resp.Handoff.NodeID)
+		require.Len(t, resp.Handoff.Messages, 1)
+		require.Equal(t, "anyone?", resp.Handoff.Messages[0].Text)
+
+		replyReq := &types.ReplyHandoffRequest{PageID: testPageID, PSID: testPSID, Agent: "ann", Text: "Hi, I am Ann"}
+		_, err = st.handoffs.ReplyHandoff(ctx, replyReq)
+		require.NoError(t, err)
+
+		resolveReq := &types.ResolveHandoffRequest{PageID: testPageID, PSID: testPSID, Agent: "ann"}
+		resp, err = st.handoffs.ResolveHandoff(ctx, resolveReq)
+		require.NoError(t, err)
+		require.Equal(t, types.HandoffResolved, resp.Handoff.Status)
+		require.Len(t, resp.Handoff.Messages, 2)
+		require.Equal(t, []string{
+			"An agent will reply soon", "pass_thread_control",
+			"Hi, I am Ann", "take_thread_control", "Welcome back!",
+		}, st.transport.Sent())
+		require.Equal(t, dot.IntID(3), st.nodeID(t))
+
+		_, err = st.handoffs.GetHandoff(ctx, getReq)
+		require.Equal(t, xerrors.NotFound, xerrors.GetCode(err))
+		listReq := &types.ListHandoffsRequest{PageID: testPageID, Status: types.HandoffResolved}
+		listResp, err := st.handoffs.ListHandoffs(ctx, listReq)
+		require.NoError(t, err)
+		require.Len(t, listResp.Handoffs, 1)
+	})
+
+	t.Run("resume at the chosen node", func(t *testing.T) {
+		st := newServiceTest(t, testFlowJSON)
+		startReq := &types.StartHandoffRequest{PageID: testPageID, PSID: testPSID, Reason: "vip"}
+		_, err := st.handoffs.StartHandoff(ctx, startReq)
+		require.NoError(t, err)
+
+		receive(t, st, "hello")
+		require.Empty(t, st.transport.Sent())
+
+		st.start(t) // the handoff survives restart
+		resolveReq := &types.ResolveHandoffRequest{PageID: testPageID, PSID: testPSID, ResumeNodeID: 5}
+		_, err = st.handoffs.ResolveHandoff(ctx, resolveReq)
+		require.NoError(t, err)
+		require.Empty(t, st.transport.Sent(), "no state, nothing to resume")
+
+		st.completeOrder(t)
+		_, err = st.handoffs.StartHandoff(ctx, startReq)
+		require.NoError(t, err)
+		_, err = st.handoffs.ResolveHandoff(ctx, resolveReq)
+		require.NoError(t, err)
+		require.Equal(t, []string{"Thank you!"}, st.transport.Sent())
+		require.Equal(t, dot.IntID(5), st.nodeID(t))
+	})
+}
@@ -0,0 +1,140 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"io"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/olvrng/rbot/be/com/flowexec/flowcore"
+	"github.com/olvrng/rbot/be/pkg/xerrors"
+)
+
+// MaxHTTPResponseSize limits the body of the responses to the http_request
+// nodes.
+const MaxHTTPResponseSize = 1 << 20
+
+var _ flowcore.HTTPRequester = (*HTTPClient)(nil)
+
+// HTTPClient makes the requests of the http_request nodes, only to the allowed
+// hosts. A host is allowed when it is in AllowedHosts, with or without the
+// port, or matches a wildcard such as "*.example.com". The redirects are only
+// followed to the allowed hosts.
+type HTTPClient struct {
+	Client       *http.Client
+	AllowedHosts []string
+
+	// RetryDelay is the delay before the first retry, then doubles.
+	RetryDelay time.Duration
+}
+
+func NewHTTPClient(allowedHosts []string) *HTTPClient {
+	c := &HTTPClient{
+		AllowedHosts: allowedHosts,
+		RetryDelay:   500 * time.Millisecond,
+	}
+	c.Client = &http.Client{CheckRedirect: c.checkRedirect}
+	return c
+}
+
+func (c *HTTPClient) DoRequest(req *flowcore.HTTPRequest) (*flowcore.HTTPResponse, error) {
+	u, err := url.Parse(req.URL)
+	if err != nil {
+		return nil, xerrors.Errorf(xerrors.InvalidArgument, err, "invalid url")
+	}
+	if err = c.checkURL(u); err != nil {
+		return nil, err
+	}
+
+	delay := c.RetryDelay
+	for attempt := 0; ; attempt++ {
+		resp, err := c.do(req)
+		retry := xerrors.GetCode(err) == xerrors.Unavailable || err == nil && resp.StatusCode >= 500
+		if !retry || attempt >= req.Retries {
+			return resp, err
+		}
+		ls.Debugf("http request %v %v: attempt %v failed, retry", req.Method, req.URL, attempt+1)
+		time.Sleep(delay)
+		delay *= 2
+	}
+}
+
+func (c *HTTPClient) do(req *flowcore.HTTPRequest) (*flowcore.HTTPResponse, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), req.Timeout)
+	defer cancel()
+
+	var body io.Reader
+	if req.Body != "" {
+		body = strings.NewReader(req.Body)
+	}
+	httpReq, err := http.NewRequestWithContext(ctx, req.Method, req.URL, body)
+	if err != nil {
+		return nil, xerrors.Errorf(xerrors.InvalidArgument, err, "invalid request")
+	}
+	if req.Body != "" {
+		httpReq.Header.Set("Content-Type", "application/json")
+	}
+	for key, value := range req.Headers {
+		httpReq.Header.Set(key, value)
+	}
+
+	httpResp, err := c.Client.Do(httpReq)
+	if err != nil {
+		var xerr *xerrors.APIError
+		if errors.As(err, &xerr) {
+			return nil, xerr // the redirect is not allowed
+		}
+		return nil, xerrors.Errorf(xerrors.Unavailable, err, "http request failed")
+	}
+	defer func() { _ = httpResp.Body.Close() }()
+	data, err := ioutil.ReadAll(io.LimitReader(httpResp.Body, MaxHTTPResponseSize))
+	if err != nil {
+		return nil, xerrors.Errorf(xerrors.Unavailable, err, "can not read response")
+	}
+	return &flowcore.HTTPResponse{StatusCode: httpResp.StatusCode, Body: data}, nil
+}
+
+func (c *HTTPClient) checkURL(u *url.URL) error {
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return xerrors.Errorf(xerrors.InvalidArgument, nil, "unsupported scheme %q", u.Scheme)
+	}
+	if !c.allowed(u) {
+		return xerrors.Errorf(xerrors.PermissionDenied, nil, "host %v is not allowed", u.Host)
+	}
+	return nil
+}
+
+// checkRedirect checks each hop of the redirects, since an allowed host may
+// redirect to an internal address.
+func (c *HTTPClient) checkRedirect(req *http.Request, via []*http.Request) error {
+	if len(via) >= 10 {
+		return errors.New("stopped after 10 redirects")
+	}
+	return c.checkURL(req.URL)
+}
+
+func (c *HTTPClient) allowed(u *url.URL) bool {
+	hostname := strings.ToLower(u.Hostname())
+	for _, allowed := range c.AllowedHosts {
+		allowed = strings.ToLower(strings.TrimSpace(allowed))
+		switch {
+		case allowed == "":
+		case strings.HasPrefix(allowed, "*."):
+			if strings.HasSuffix(hostname, allowed[1:]) {
+				return true
+			}
+		case strings.Contains(allowed, ":") && net.ParseIP(allowed) == nil:
+			if strings.ToLower(u.Host) == allowed {
+				return true
+			}
+		case hostname == allowed:
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,197 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/olvrng/rbot/be/com/flowexec/flowcore"
+	"github.com/olvrng/rbot/be/com/flowexec/types"
+	"github.com/olvrng/rbot/be/pkg/xerrors"
+)
+
+func newTestHTTPClient(server *httptest.Server) *HTTPClient {
+	u, _ := url.Parse(server.URL)
+	c := NewHTTPClient([]string{u.Hostname()})
+	c.RetryDelay = time.Millisecond
+	return c
+}
+
+func TestHTTPClient(t *testing.T) {
+	var calls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := atomic.AddInt32(&calls, 1)
+		switch r.URL.Path {
+		case "/flaky":
+			if n < 3 {
+				w.WriteHeader(http.StatusBadGateway)
+				return
+			}
+		case "/slow":
+			time.Sleep(100 * time.Millisecond)
+		}
+		_, _ = w.Write([]byte(`{"ok":true}`))
+	}))
+	defer server.Close()
+	c := newTestHTTPClient(server)
+
+	t.Run("retry", func(t *testing.T) {
+		atomic.StoreInt32(&calls, 0)
+		resp, err := c.DoRequest(&flowcore.HTTPRequest{Method: "GET", URL: server.URL + "/flaky", Timeout: time.Second, Retries: 1})
+		require.NoError(t, err)
+		require.Equal(t, http.StatusBadGateway, resp.StatusCode)
+
+		atomic.StoreInt32(&calls, 0)
+		resp, err = c.DoRequest(&flowcore.HTTPRequest{Method: "GET", URL: server.URL + "/flaky", Timeout: time.Second, Retries: 2})
+		require.NoError(t, err)
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+		require.Equal(t, int32(3), atomic.LoadInt32(&calls))
+	})
+
+	t.Run("timeout", func(t *testing.T) {
+		_, err := c.DoRequest(&flowcore.HTTPRequest{Method: "GET", URL: server.URL + "/slow", Timeout: 10 * time.Millisecond})
+		require.Equal(t, xerrors.Unavailable, xerrors.GetCode(err))
+	})
+
+	t.Run("host not allowed", func(t *testing.T) {
+		_, err := c.DoRequest(&flowcore.HTTPRequest{Method: "GET", URL: "http://example.com/", Timeout: time.Second})
+		require.Equal(t, xerrors.PermissionDenied, xerrors.GetCode(err))
+
+		_, err = c.DoRequest(&flowcore.HTTPRequest{Method: "GET", URL: "file:///etc/passwd", Timeout: time.Second})
+		require.Equal(t, xerrors.InvalidArgument, xerrors.GetCode(err))
+	})
+}
+
+func TestHTTPClientRedirect(t *testing.T) {
+	internal := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"secret":true}`))
+	}))
+	defer internal.Close()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/internal":
+			http.Redirect(w, r, internal.URL, http.StatusFound)
+		case "/moved":
+			http.Redirect(w, r, "/ok", http.StatusFound)
+		default:
+			_, _ = w.Write([]byte(`{"ok":true}`))
+		}
+	}))
+	defer server.Close()
+
+	// only the host and port of the server are allowed
+	u, _ := url.Parse(server.URL)
+	c := NewHTTPClient([]string{u.Host})
+	c.RetryDelay = time.Millisecond
+
+	resp, err := c.DoRequest(&flowcore.HTTPRequest{Method: "GET", URL: server.URL + "/moved", Timeout: time.Second})
+	require.NoError(t, err)
+	require.Equal(t, `{"ok":true}`, string(resp.Body))
+
+	_, err = c.DoRequest(&flowcore.HTTPRequest{Method: "GET", URL: server.URL + "/internal", Timeout: time.Second, Retries: 2})
+	require.Equal(t, xerrors.PermissionDenied, xerrors.GetCode(err))
+}
+
+func TestHTTPClientAllowed(t *testing.T) {
+	c := NewHTTPClient([]string{"api.example.com", "*.shop.vn", "localhost:8080"})
+	tests := []struct {
+		url  string
+		want bool
+	}{
+		{"https://api.example.com/orders", true},
+		{"https://API.example.com:8443/orders", true},
+		{"https://example.com/", false},
+		{"https://track.shop.vn/", true},
+		{"https://shop.vn/", false},
+		{"http://localhost:8080/", true},
+		{"http://localhost:9090/", false},
+	}
+	for _, tt := range tests {
+		u, err := url.Parse(tt.url)
+		require.NoError(t, err)
+		require.Equal(t, tt.want, c.allowed(u), tt.url)
+	}
+}
+
+const testHTTPFlowJSON = `{
+	"id": "1",
+	"page_ids": ["1000"],
+	"nodes": [
+		{"id": "1", "payload": {"type": "trigger:received_message", "next_id": "2"}},
+		{"id": "2", "payload": {"type": "action:http_request", "method": "POST",
+			"url": "{{.base_url}}/shipping",
+			"headers": {"Authorization": "Bearer secret"},
+			"body": "{\"order\": {{json .message}}}",
+			"retries": 1,
+			"status_variable": "status",
+			"response_vars": [
+				{"variable": "shipping_status", "path": "shipment.status"},
+				{"variable": "eta", "path": "shipment.events.0.eta", "var_type": "number"}
+			],
+			"status_routes": [{"status": "404", "next_id": "4"}],
+			"next_id": "3", "error_next_id": "5"}},
+		{"id": "3", "payload": {"type": "action:send_message", "template": "Your order is {{.shipping_status}}, {{.eta}} days left"}},
+		{"id": "4", "payload": {"type": "action:send_message", "template": "Order {{.message}} not found"}},
+		{"id": "5", "payload": {"type": "action:send_message", "template": "Sorry, try again later ({{.status}})"}}
+	]
+}`
+
+func TestHTTPRequestNode(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.URL.Path != "/shipping" || r.Header.Get("Authorization") != "Bearer secret" {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		var req struct{ Order string }
+		body, _ := ioutil.ReadAll(r.Body)
+		_ = json.Unmarshal(body, &req)
+		switch {
+		case req.Order == "A1":
+			_, _ = w.Write([]byte(`{"shipment": {"status": "shipping", "events": [{"eta": 2}]}}`))
+		case strings.HasPrefix(req.Order, "B"):
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte(`not found`))
+		default:
+			w.WriteHeader(http.StatusInternalServerError)
+		}
+	}))
+	defer server.Close()
+
+	ctx := context.Background()
+	tests := []struct {
+		message string
+		want    string
+	}{
+		{"A1", "Your order is shipping, 2 days left"},
+		{"B2", "Order B2 not found"},
+		{`"C3`, "Sorry, try again later (500)"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.message, func(t *testing.T) {
+			st := newServiceTest(t, strings.ReplaceAll(testHTTPFlowJSON, "{{.base_url}}", server.URL))
+			st.actionExec.HTTP = newTestHTTPClient(server)
+
+			req := &types.ReceivedMessageRequest{PageID: testPageID, PSID: testPSID, Message: tt.message}
+			_, err := st.messenger.ReceivedMessage(ctx, req)
+			require.NoError(t, err)
+			require.Equal(t, []string{tt.want}, st.transport.Sent())
+		})
+	}
+
+	t.Run("no http client", func(t *testing.T) {
+		st := newServiceTest(t, testHTTPFlowJSON)
+		req := &types.ReceivedMessageRequest{PageID: testPageID, PSID: testPSID, Message: "A1"}
+		_, err := st.messenger.ReceivedMessage(ctx, req)
+		require.NoError(t, err)
+		require.Equal(t, []string{"Sorry, try again later ()"}, st.transport.Sent())
+	})
+}
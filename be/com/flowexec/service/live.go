@@ -0,0 +1,125 @@
+package service
+
+import (
+	"context"
+	"sync"
+
+	"github.com/olvrng/rbot/be/com/auth"
+	authtypes "github.com/olvrng/rbot/be/com/auth/types"
+	"github.com/olvrng/rbot/be/com/flowexec"
+	"github.com/olvrng/rbot/be/com/flowexec/types"
+	"github.com/olvrng/rbot/be/com/workspace"
+	"github.com/olvrng/rbot/be/pkg/dot"
+	"github.com/olvrng/rbot/be/pkg/xerrors"
+)
+
+var _ flowexec.LiveService = (*LiveService)(nil)
+
+// LiveService streams the events of the bus to the dashboard, so it does not
+// have to poll for the new conversations and handoffs.
+type LiveService struct {
+	Bus *EventBus
+
+	// Buffer is the number of events queued for a slow client. The stream ends
+	// with ResourceExhausted when it is full, and the client watches again.
+	Buffer int
+}
+
+func NewLiveService(bus *EventBus) *LiveService {
+	s := &LiveService{
+		Bus:    bus,
+		Buffer: 64,
+	}
+	return s
+}
+
+func (s *LiveService) WatchEvents(ctx context.Context, req *types.WatchEventsRequest, send func(*types.FlowEvent) error) error {
+	for _, typ := range req.Types {
+		if !validEventType(typ) {
+			return xerrors.Errorf(xerrors.InvalidArgument, nil, "unknown event type %q", typ)
+		}
+	}
+	filter := &eventFilter{
+		WorkspaceID: workspace.GetID(ctx),
+		Principal:   auth.GetPrincipal(ctx),
+		PageIDs:     req.PageIDs,
+		Types:       req.Types,
+	}
+
+	events := make(chan *types.FlowEvent, s.Buffer)
+	overflow := make(chan struct{})
+	var once sync.Once
+	unsubscribe := s.Bus.Subscribe(func(ctx context.Context, event *types.FlowEvent) {
+		if !filter.Match(ctx, event) {
+			return
+		}
+		select {
+		case events <- event:
+		default:
+			once.Do(func() { close(overflow) })
+		}
+	})
+	defer unsubscribe()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil // the client goes away
+		case <-overflow:
+			return xerrors.Errorf(xerrors.ResourceExhausted, nil, "the events are not read fast enough")
+		case event := <-events:
+			if err := send(event); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// eventFilter matches the events published in the workspace of the watcher,
+// on the pages which it may view. Without principal, such as when the auth is
+// disabled, all the pages of the workspace are watched.
+type eventFilter struct {
+	WorkspaceID dot.IntID
+	Principal   *authtypes.Principal
+	PageIDs     []dot.IntID
+	Types       []types.FlowEventType
+}
+
+func (f *eventFilter) Match(ctx context.Context, event *types.FlowEvent) bool {
+	if !workspace.Match(ctx, f.WorkspaceID) {
+		return false
+	}
+	if f.Principal != nil && !f.Principal.Role(event.PageID).Includes(authtypes.RoleViewer) {
+		return false
+	}
+	if len(f.PageIDs) != 0 && !containsID(f.PageIDs, event.PageID) {
+		return false
+	}
+	if len(f.Types) == 0 {
+		return true
+	}
+	for _, typ := range f.Types {
+		if typ == event.Type {
+			return true
+		}
+	}
+	return false
+}
+
+func validEventType(typ types.FlowEventType) bool {
+	for _, t := range types.FlowEventTypes {
+		if t == typ {
+			return true
+		}
+	}
+	return false
+}
+
+func containsID(ids []dot.IntID, id dot.IntID) bool {
+	for _, x := range ids {
+		if x == id {
+			return true
+		}
+	}
+	return false
+}
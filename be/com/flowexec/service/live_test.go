@@ -0,0 +1,120 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/olvrng/rbot/be/com/auth"
+	authtypes "github.com/olvrng/rbot/be/com/auth/types"
+	"github.com/olvrng/rbot/be/com/flowexec/types"
+	"github.com/olvrng/rbot/be/com/workspace"
+	"github.com/olvrng/rbot/be/pkg/dot"
+	"github.com/olvrng/rbot/be/pkg/xerrors"
+)
+
+// watch runs WatchEvents until the test ends, and returns the events as
+// "type page_id".
+func watch(t *testing.T, ctx context.Context, live *LiveService, req *types.WatchEventsRequest) <-chan string {
+	ctx, cancel := context.WithCancel(ctx)
+	t.Cleanup(cancel)
+	events := make(chan string, 100)
+	subscribers := func() int {
+		live.Bus.m.RLock()
+		defer live.Bus.m.RUnlock()
+		return len(live.Bus.subscribers)
+	}
+	n := subscribers()
+	go func() {
+		_ = live.WatchEvents(ctx, req, func(event *types.FlowEvent) error {
+			events <- fmt.Sprintf("%v %v", event.Type, event.PageID)
+			return nil
+		})
+	}()
+	require.Eventually(t, func() bool { return subscribers() > n }, time.Second, time.Millisecond)
+	return events
+}
+
+func TestLiveService(t *testing.T) {
+	ctx := context.Background()
+	viewer := auth.WithPrincipal(ctx, &authtypes.Principal{
+		Email: "ann@example.com",
+		Roles: []*authtypes.PageRole{{PageID: testPageID, Role: authtypes.RoleViewer}},
+	})
+	next := func(t *testing.T, events <-chan string) string {
+		select {
+		case event := <-events:
+			return event
+		case <-time.After(time.Second):
+			t.Fatal("no event")
+			return ""
+		}
+	}
+
+	t.Run("stream the events of the pages of the principal", func(t *testing.T) {
+		st := newServiceTest(t, testFlowJSON)
+		events := watch(t, viewer, NewLiveService(st.bus), &types.WatchEventsRequest{})
+
+		st.bus.Publish(ctx, &types.FlowEvent{Type: types.EventNodeEntered, PageID: 1001})
+		st.bus.Publish(workspace.WithID(ctx, 99), &types.FlowEvent{Type: types.EventNodeEntered, PageID: testPageID})
+		st.completeOrder(t)
+		require.Equal(t, "conversation.started 1000", next(t, events))
+		require.Equal(t, "node.entered 1000", next(t, events))
+
+		_, err := st.handoffs.StartHandoff(ctx, &types.StartHandoffRequest{PageID: testPageID, PSID: testPSID})
+		require.NoError(t, err)
+		require.Equal(t, "handoff.requested 1000", next(t, events))
+	})
+
+	t.Run("filter by page and type", func(t *testing.T) {
+		st := newServiceTest(t, testFlowJSON)
+		req := &types.WatchEventsRequest{
+			PageIDs: []dot.IntID{testPageID},
+			Types:   []types.FlowEventType{types.EventMessageSent},
+		}
+		events := watch(t, ctx, NewLiveService(st.bus), req)
+
+		st.bus.Publish(ctx, &types.FlowEvent{Type: types.EventMessageSent, PageID: 1001})
+		st.completeOrder(t)
+		st.fire(t, 3*24*time.Hour)
+		require.Equal(t, "message.sent 1000", next(t, events))
+	})
+
+	t.Run("stop when the client is too slow", func(t *testing.T) {
+		st := newServiceTest(t, testFlowJSON)
+		live := &LiveService{Bus: st.bus, Buffer: 1}
+		release := make(chan struct{})
+		done := make(chan error, 1)
+		go func() {
+			done <- live.WatchEvents(ctx, &types.WatchEventsRequest{}, func(event *types.FlowEvent) error {
+				<-release
+				return nil
+			})
+		}()
+		require.Eventually(t, func() bool {
+			st.bus.m.RLock()
+			defer st.bus.m.RUnlock()
+			return len(st.bus.subscribers) == 1
+		}, time.Second, time.Millisecond)
+
+		for i := 0; i < 3; i++ {
+			st.bus.Publish(ctx, &types.FlowEvent{Type: types.EventNodeEntered, PageID: testPageID})
+		}
+		close(release)
+		err := <-done
+		require.Equal(t, xerrors.ResourceExhausted, err.(*xerrors.APIError).Code)
+
+		// the stream is unsubscribed
+		require.Empty(t, st.bus.subscribers)
+	})
+
+	t.Run("unknown type", func(t *testing.T) {
+		st := newServiceTest(t, testFlowJSON)
+		req := &types.WatchEventsRequest{Types: []types.FlowEventType{"order.paid"}}
+		err := NewLiveService(st.bus).WatchEvents(ctx, req, nil)
+		require.Equal(t, xerrors.InvalidArgument, err.(*xerrors.APIError).Code)
+	})
+}
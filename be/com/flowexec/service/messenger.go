@@ -2,14 +2,14 @@ package service
 
 import (
 	"context"
+	"fmt"
 
 	"github.com/olvrng/rbot/be/com/flowdef"
 	flowdeftypes "github.com/olvrng/rbot/be/com/flowdef/types"
 	"github.com/olvrng/rbot/be/com/flowexec"
-	"github.com/olvrng/rbot/be/com/flowexec/flowcore"
 	"github.com/olvrng/rbot/be/com/flowexec/store"
 	"github.com/olvrng/rbot/be/com/flowexec/types"
-	"github.com/olvrng/rbot/be/pkg/xerrors"
+	"github.com/olvrng/rbot/be/pkg/l"
 )
 
 var _ flowexec.MessengerService = (*MessengerService)(nil)
@@ -17,87 +17,148 @@ var _ flowexec.MessengerService = (*MessengerService)(nil)
 type MessengerService struct {
 	FlowQuery  flowdef.QueryService
 	StateStore *store.FlowStateStore
+	Customers  *CustomerService
+	Handoffs   *HandoffService
+	Events     *EventHandler
 	ActionExec *ActionExecutor
+	Scheduler  *Scheduler
 }
 
 func NewMessengerService(
 	query flowdef.QueryService,
 	stateStore *store.FlowStateStore,
+	customers *CustomerService,
+	handoffs *HandoffService,
+	events *EventHandler,
 	actionExec *ActionExecutor,
+	scheduler *Scheduler,
 ) *MessengerService {
 	s := &MessengerService{
 		FlowQuery:  query,
 		StateStore: stateStore,
+		Customers:  customers,
+		Handoffs:   handoffs,
+		Events:     events,
 		ActionExec: actionExec,
+		Scheduler:  scheduler,
 	}
 	return s
 }
 
 func (s *MessengerService) ReceivedMessage(ctx context.Context, req *types.ReceivedMessageRequest) (*types.ReceivedMessageResponse, error) {
-	flowReq := &flowdeftypes.GetFlowByParamRequest{FBPageID: req.PageID}
-	flowResp, err := s.FlowQuery.GetFlowByParam(ctx, flowReq)
-	if err != nil {
-		return nil, xerrors.Errorf(xerrors.NotFound, err, "page_id not found")
+	unlock := s.StateStore.LockConversation(req.PageID, req.PSID)
+	defer unlock()
+	paused, err := s.Handoffs.StoreMessage(ctx, req.PageID, req.PSID, types.SenderCustomer, req.Message)
+	if err != nil || paused {
+		return &types.ReceivedMessageResponse{}, err
 	}
 
-	flow, pageID, psid := flowResp.Flow, req.PageID, req.PSID
-	state, _, err := loadOrCreateState(ctx, s.StateStore, pageID, psid, flow.ID)
+	flows, err := listPageFlows(ctx, s.FlowQuery, req.PageID)
 	if err != nil {
 		return nil, err
 	}
-
-	ex := flowcore.NewExecutor(flow, state)
 	stateData := map[string]string{
 		"message": req.Message,
 	}
-	nextState, nextNodes, err := ex.NextState(flowdeftypes.NodeReceivedMessage, stateData)
+	flow, state, err := routeEvent(ctx, s.StateStore, s.ActionExec, flows, req.PageID, req.PSID, flowdeftypes.NodeReceivedMessage, stateData)
 	if err != nil {
 		return nil, err
 	}
 
-	actionState := &ActionState{
-		PageID: pageID,
-		PSID:   psid,
-		Extra:  stateData,
+	ex := s.ActionExec.NewExecutor(flow, state)
+	nextState, nextNodes, err := execNextState(ex, flowdeftypes.NodeReceivedMessage, stateData)
+	if err != nil {
+		return nil, err
 	}
-	s.ActionExec.ExecuteActions(nextNodes, actionState)
 
-	err = s.StateStore.SaveState(nextState)
+	s.Events.Handle(ctx, flow, nextState, ex.Events)
+	actionState := NewActionState(nextState)
+	s.ActionExec.ExecuteActions(ctx, nextNodes, actionState)
+
+	err = saveState(ctx, s.StateStore, s.Scheduler, flow, nextState, ex.Events)
 	resp := &types.ReceivedMessageResponse{}
 	return resp, err
 }
 
 func (s *MessengerService) ReceivedPostback(ctx context.Context, req *types.ReceivedPostbackRequest) (*types.ReceivedPostbackResponse, error) {
-	flowReq := &flowdeftypes.GetFlowByParamRequest{FBPageID: req.PageID}
-	flowResp, err := s.FlowQuery.GetFlowByParam(ctx, flowReq)
-	if err != nil {
-		return nil, xerrors.Errorf(xerrors.NotFound, err, "page_id not found")
+	unlock := s.StateStore.LockConversation(req.PageID, req.PSID)
+	defer unlock()
+	paused, err := s.Handoffs.StoreMessage(ctx, req.PageID, req.PSID, types.SenderCustomer, req.PostbackTitle)
+	if err != nil || paused {
+		return &types.ReceivedPostbackResponse{}, err
 	}
 
-	flow, pageID, psid := flowResp.Flow, req.PageID, req.PSID
-	state, _, err := loadOrCreateState(ctx, s.StateStore, pageID, psid, flow.ID)
+	flows, err := listPageFlows(ctx, s.FlowQuery, req.PageID)
 	if err != nil {
 		return nil, err
 	}
-
-	ex := flowcore.NewExecutor(flow, state)
 	stateData := map[string]string{
 		"reply_title":   req.PostbackTitle,
 		"reply_payload": req.PostbackPayload,
 	}
-	nextState, nextNodes, err := ex.NextState(flowdeftypes.NodeReceivedReply, stateData)
+	flow, state, err := routeEvent(ctx, s.StateStore, s.ActionExec, flows, req.PageID, req.PSID, flowdeftypes.NodeReceivedReply, stateData)
 	if err != nil {
 		return nil, err
 	}
 
-	actionState := &ActionState{
-		PageID: pageID,
-		PSID:   psid,
-		Extra:  stateData,
+	ex := s.ActionExec.NewExecutor(flow, state)
+	nextState, nextNodes, err := execNextState(ex, flowdeftypes.NodeReceivedReply, stateData)
+	if err != nil {
+		return nil, err
 	}
-	s.ActionExec.ExecuteActions(nextNodes, actionState)
 
-	err = s.StateStore.SaveState(nextState)
+	s.Events.Handle(ctx, flow, nextState, ex.Events)
+	actionState := NewActionState(nextState)
+	s.ActionExec.ExecuteActions(ctx, nextNodes, actionState)
+
+	err = saveState(ctx, s.StateStore, s.Scheduler, flow, nextState, ex.Events)
 	resp := &types.ReceivedPostbackResponse{}
 	return resp, err
 }
+
+func (s *MessengerService) ReceivedReferral(ctx context.Context, req *types.ReceivedReferralRequest) (*types.ReceivedReferralResponse, error) {
+	unlock := s.StateStore.LockConversation(req.PageID, req.PSID)
+	defer unlock()
+	flows, err := listPageFlows(ctx, s.FlowQuery, req.PageID)
+	if err != nil {
+		return nil, err
+	}
+	pageID, psid := req.PageID, req.PSID
+	stateData := map[string]string{
+		"ref":        req.Ref,
+		"ref_source": req.Source,
+		"ref_type":   req.Type,
+	}
+	flow, state, err := routeEvent(ctx, s.StateStore, s.ActionExec, flows, pageID, psid, flowdeftypes.NodeReferral, stateData)
+	if err != nil {
+		return nil, err
+	}
+
+	order, err := s.Customers.LinkRef(ctx, pageID, psid, req.Ref, LinkSourceReferral)
+	if err != nil {
+		// still continue the conversation, the customer can be linked later
+		ll.Error("can not link ref", l.String("ref", req.Ref), l.Error(err))
+	}
+	text := fmt.Sprintf("opened the conversation from a link (ref %q)", req.Ref)
+	paused, err := s.Handoffs.StoreMessage(ctx, pageID, psid, types.SenderSystem, text)
+	if err != nil || paused {
+		return &types.ReceivedReferralResponse{}, err
+	}
+
+	ex := s.ActionExec.NewExecutor(flow, state)
+	if order != nil {
+		ex.SetVar("order_id", flowdeftypes.VarString, order.ID)
+	}
+	nextState, nextNodes, err := execNextState(ex, flowdeftypes.NodeReferral, stateData)
+	if err != nil {
+		return nil, err
+	}
+
+	s.Events.Handle(ctx, flow, nextState, ex.Events)
+	actionState := NewActionState(nextState)
+	s.ActionExec.ExecuteActions(ctx, nextNodes, actionState)
+
+	err = saveState(ctx, s.StateStore, s.Scheduler, flow, nextState, ex.Events)
+	resp := &types.ReceivedReferralResponse{}
+	return resp, err
+}
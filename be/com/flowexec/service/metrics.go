@@ -0,0 +1,34 @@
+package service
+
+import (
+	flowdeftypes "github.com/olvrng/rbot/be/com/flowdef/types"
+	"github.com/olvrng/rbot/be/com/flowexec/flowcore"
+	"github.com/olvrng/rbot/be/pkg/metrics"
+	"github.com/olvrng/rbot/be/pkg/xerrors"
+)
+
+var (
+	transitionsTotal = metrics.NewCounter(
+		"rbot_flow_transitions_total",
+		"The nodes entered by the conversations, by node type.",
+		"node_type")
+
+	abortsTotal = metrics.NewCounter(
+		"rbot_flow_aborts_total",
+		"The events which the flow can not handle (\"can not execute state\"), by event node type.",
+		"event")
+
+	dueTimers = metrics.NewGauge(
+		"rbot_scheduler_due_timers",
+		"The timers which were due at the last run of the scheduler.")
+)
+
+// execNextState runs the transition of the event, and counts the events which
+// the flow can not handle.
+func execNextState(ex *flowcore.Executor, nodeType flowdeftypes.NodeType, data map[string]string) (*flowcore.FlowState, []*flowdeftypes.Node, error) {
+	nextState, nextNodes, err := ex.NextState(nodeType, data)
+	if xerrors.GetCode(err) == xerrors.Aborted {
+		abortsTotal.Inc(string(nodeType))
+	}
+	return nextState, nextNodes, err
+}
@@ -3,8 +3,6 @@ package service
 import (
 	"context"
 	"fmt"
-	"strconv"
-	"strings"
 
 	"github.com/olvrng/rbot/be/com/flowdef"
 	flowdeftypes "github.com/olvrng/rbot/be/com/flowdef/types"
@@ -22,84 +20,273 @@ var _ flowexec.OrderService = (*OrderService)(nil)
 type OrderService struct {
 	FlowQuery  flowdef.QueryService
 	StateStore *store.FlowStateStore
+	OrderStore *store.OrderStore
+	Customers  *CustomerService
+	Handoffs   *HandoffService
+	Events     *EventHandler
 	ActionExec *ActionExecutor
+	Scheduler  *Scheduler
 }
 
 func NewOrderService(
 	query flowdef.QueryService,
 	stateStore *store.FlowStateStore,
+	orderStore *store.OrderStore,
+	customers *CustomerService,
+	handoffs *HandoffService,
+	events *EventHandler,
 	actionExec *ActionExecutor,
+	scheduler *Scheduler,
 ) *OrderService {
 	s := &OrderService{
 		FlowQuery:  query,
 		StateStore: stateStore,
+		OrderStore: orderStore,
+		Customers:  customers,
+		Handoffs:   handoffs,
+		Events:     events,
 		ActionExec: actionExec,
+		Scheduler:  scheduler,
 	}
 	return s
 }
 
-func (s *OrderService) ReceivedCompletedOrder(ctx context.Context, req *types.ReceivedCompletedOrderRequest) (*types.ReceivedCompletedOrderResponse, error) {
-	flowReq := &flowdeftypes.GetFlowByParamRequest{FBPageID: req.PageID}
-	flowResp, err := s.FlowQuery.GetFlowByParam(ctx, flowReq)
+func (s *OrderService) ReceivedOrderEvent(ctx context.Context, req *types.ReceivedOrderEventRequest) (_ *types.ReceivedOrderEventResponse, _err error) {
+	if req.PageID == 0 {
+		return nil, xerrors.Errorf(xerrors.InvalidArgument, nil, "page_id is required")
+	}
+	if req.OrderID == "" {
+		return nil, xerrors.Errorf(xerrors.InvalidArgument, nil, "order_id is required")
+	}
+	triggerType := req.Status.TriggerType()
+	if triggerType == "" {
+		return nil, xerrors.Errorf(xerrors.InvalidArgument, nil, "invalid status %q", req.Status)
+	}
+
+	flows, err := listPageFlows(ctx, s.FlowQuery, req.PageID)
 	if err != nil {
 		ll.Error("page_id not found", l.ID("page_id", req.PageID))
-		return nil, xerrors.Errorf(xerrors.NotFound, err, "page_id not found")
+		return nil, err
 	}
-	flow := flowResp.Flow
 
-	psid, err := mockGetPSIDFromOrderID(req.OrderID)
+	key := req.IdempotencyKey
+	if key == "" {
+		key = fmt.Sprintf("%v:%v", req.OrderID, req.Status)
+	}
+	duplicated, err := s.OrderStore.MarkEvent(ctx, req.PageID, key)
 	if err != nil {
-		return nil, xerrors.Errorf(xerrors.NotFound, err, "psid not found")
+		return nil, xerrors.Errorf(xerrors.Internal, err, "internal error")
 	}
-	state, _, err := loadOrCreateState(ctx, s.StateStore, req.PageID, psid, flow.ID)
+	if duplicated {
+		order, err := s.OrderStore.LoadOrder(ctx, req.PageID, req.OrderID)
+		if err != nil {
+			return nil, err
+		}
+		resp := &types.ReceivedOrderEventResponse{Order: order, Duplicated: true, Ref: s.Customers.OrderRef(req.PageID, order.ID)}
+		return resp, nil
+	}
+	defer func() {
+		// allow the sender to retry
+		if _err != nil {
+			s.OrderStore.UnmarkEvent(ctx, req.PageID, key)
+		}
+	}()
+
+	order, err := s.saveOrder(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	psid, userRef, err := s.resolveRecipient(ctx, order)
 	if err != nil {
 		return nil, err
 	}
+	resp := &types.ReceivedOrderEventResponse{Order: order, Ref: s.Customers.OrderRef(req.PageID, order.ID)}
+	if psid != 0 {
+		// the customer may be talking to the page at the same time
+		unlock := s.StateStore.LockConversation(req.PageID, psid)
+		defer unlock()
+
+		// the bot is paused, let the agent know about the order instead
+		text := fmt.Sprintf("order %v is %v", order.ID, order.Status)
+		paused, err := s.Handoffs.StoreMessage(ctx, req.PageID, psid, types.SenderSystem, text)
+		if err != nil || paused {
+			return resp, err
+		}
+	}
 
-	ex := flowcore.NewExecutor(flow, state)
-	stateData := map[string]string{
-		"order_id": req.OrderID,
-		"desc":     req.Desc,
-		"amount":   fmt.Sprint(req.Amount),
+	// without psid, the customer has not talked to the page yet, and a new
+	// conversation starts
+	eventData := order.EventData()
+	flow, state, err := routeEvent(ctx, s.StateStore, s.ActionExec, flows, req.PageID, psid, triggerType, eventData)
+	if err != nil {
+		return nil, err
 	}
-	nextState, nextNodes, err := ex.NextState(flowdeftypes.NodeCompletedOrder, stateData)
+	ex := s.ActionExec.NewExecutor(flow, state)
+	// remember the order, so later nodes (such as reviews) can refer to it
+	ex.SetVar("order_id", flowdeftypes.VarString, order.ID)
+	nextState, nextNodes, err := execNextState(ex, triggerType, eventData)
 	if err != nil {
 		return nil, err
 	}
 
-	actionState := &ActionState{
-		PageID: req.PageID,
-		PSID:   psid,
-		Extra:  stateData,
-	}
-	s.ActionExec.ExecuteActions(nextNodes, actionState)
+	s.Events.Handle(ctx, flow, nextState, ex.Events)
+	actionState := NewActionState(nextState)
+	actionState.UserRef = userRef
+	s.ActionExec.ExecuteActions(ctx, nextNodes, actionState)
 
-	err = s.StateStore.SaveState(nextState)
-	resp := &types.ReceivedCompletedOrderResponse{}
+	if psid == 0 {
+		// the PSID is known after sending the first message to the user_ref
+		psid, _, _ = s.Customers.ResolveRecipient(ctx, req.PageID, order.CustomerRef, userRef)
+		if psid == 0 {
+			ll.Warn("can not resolve psid from user_ref", l.String("order_id", order.ID))
+			return resp, nil
+		}
+		order.PSID, nextState.PSID = psid, psid
+		if err = s.OrderStore.SaveOrder(ctx, order); err != nil {
+			return nil, err
+		}
+	}
+	err = saveState(ctx, s.StateStore, s.Scheduler, flow, nextState, ex.Events)
 	return resp, err
 }
 
-func mockGetPSIDFromOrderID(orderID string) (dot.IntID, error) {
-	parts := strings.Split(orderID, "_")
-	id, err := strconv.ParseInt(parts[0], 10, 64)
-	return dot.IntID(id), err
+func (s *OrderService) ReceivedCompletedOrder(ctx context.Context, req *types.ReceivedCompletedOrderRequest) (*types.ReceivedCompletedOrderResponse, error) {
+	eventReq := &types.ReceivedOrderEventRequest{
+		PageID:      req.PageID,
+		OrderID:     req.OrderID,
+		CustomerRef: req.CustomerRef,
+		PSID:        req.PSID,
+		Status:      types.OrderCompleted,
+		Currency:    req.Currency,
+		Amount:      req.Amount,
+		Desc:        req.Desc,
+	}
+	_, err := s.ReceivedOrderEvent(ctx, eventReq)
+	if err != nil {
+		return nil, err
+	}
+	return &types.ReceivedCompletedOrderResponse{}, nil
 }
 
-func loadOrCreateState(
-	ctx context.Context,
-	stateStore *store.FlowStateStore,
-	pageID, psid, flowID dot.IntID,
-) (state *flowcore.FlowState, isNew bool, _ error) {
-	state, err := stateStore.LoadState(ctx, pageID, psid)
+func (s *OrderService) GetOrder(ctx context.Context, req *types.GetOrderRequest) (*types.OrderResponse, error) {
+	order, err := s.OrderStore.LoadOrder(ctx, req.PageID, req.OrderID)
+	if err != nil {
+		return nil, err
+	}
+	return &types.OrderResponse{Order: order}, nil
+}
+
+// saveOrder creates the order or updates it with the new event. Fields which
+// are not provided by the event are kept.
+func (s *OrderService) saveOrder(ctx context.Context, req *types.ReceivedOrderEventRequest) (*types.Order, error) {
+	now := dot.Now()
+	order, err := s.OrderStore.LoadOrder(ctx, req.PageID, req.OrderID)
 	switch xerrors.GetCode(err) {
 	case xerrors.NoError:
-		return state, false, nil
-
+		// continue
 	case xerrors.NotFound:
-		state = flowcore.NewFlowState(pageID, psid, flowID)
-		return state, true, nil
-
+		order = &types.Order{
+			ID:        req.OrderID,
+			PageID:    req.PageID,
+			CreatedAt: now,
+		}
 	default:
-		return nil, false, xerrors.Errorf(xerrors.Internal, err, "internal error")
+		return nil, xerrors.Errorf(xerrors.Internal, err, "internal error")
+	}
+
+	order.Status = req.Status
+	order.UpdatedAt = now
+	if req.CustomerRef != "" {
+		order.CustomerRef = req.CustomerRef
+	}
+	if req.PSID != 0 {
+		order.PSID = req.PSID
+	}
+	if req.UserRef != "" {
+		order.UserRef = req.UserRef
+	}
+	if req.Currency != "" {
+		order.Currency = req.Currency
+	}
+	if req.Amount != 0 {
+		order.Amount = req.Amount
+	}
+	if len(req.Items) != 0 {
+		order.Items = req.Items
+	}
+	if req.Desc != "" {
+		order.Desc = req.Desc
+	}
+	if err = s.OrderStore.SaveOrder(ctx, order); err != nil {
+		return nil, xerrors.Errorf(xerrors.Internal, err, "internal error")
+	}
+	return order, nil
+}
+
+// resolveRecipient finds the Messenger customer of the order: either given by
+// the order itself, or linked to the customer. When the customer has only
+// opted in with the checkbox plugin, it returns the user_ref instead.
+func (s *OrderService) resolveRecipient(ctx context.Context, order *types.Order) (psid dot.IntID, userRef string, _ error) {
+	if order.PSID != 0 {
+		if order.CustomerRef != "" {
+			link := &types.CustomerLink{
+				PageID:      order.PageID,
+				CustomerRef: order.CustomerRef,
+				PSID:        order.PSID,
+				Source:      LinkSourceOrder,
+			}
+			_, err := s.Customers.LinkStore.SaveLink(ctx, link)
+			switch {
+			case xerrors.GetCode(err) == xerrors.AlreadyExists:
+				// the order is sent to its PSID, but the customer keeps the
+				// conversation which it is linked to
+				ll.Warn("the customer is linked to another psid", l.String("order_id", order.ID))
+			case err != nil:
+				return 0, "", xerrors.Errorf(xerrors.Internal, err, "internal error")
+			}
+		}
+		return order.PSID, "", nil
+	}
+	psid, userRef, err := s.Customers.ResolveRecipient(ctx, order.PageID, order.CustomerRef, order.UserRef)
+	if err != nil {
+		return 0, "", err
+	}
+	if psid != 0 {
+		order.PSID = psid
+		return psid, "", s.OrderStore.SaveOrder(ctx, order)
+	}
+	return 0, userRef, nil
+}
+
+// saveState saves the state after a transition and starts the timer of the
+// new node, if any. When the transition reaches a goto_flow node, the
+// conversation continues in the other flow, which becomes the active one.
+func saveState(
+	ctx context.Context,
+	stateStore *store.FlowStateStore,
+	scheduler *Scheduler,
+	flow *flowdeftypes.Flow,
+	state *flowcore.FlowState,
+	events []*flowcore.Event,
+) error {
+	for hops := 0; ; hops++ {
+		if err := stateStore.SaveState(ctx, state); err != nil {
+			return err
+		}
+		if err := scheduler.Schedule(ctx, flow, state); err != nil {
+			return err
+		}
+		event := gotoFlowEvent(events)
+		if event == nil {
+			return nil
+		}
+		if hops >= maxGotoFlows {
+			return xerrors.Errorf(xerrors.Aborted, nil, "too many goto_flow nodes from flow %v", flow.ID)
+		}
+		var err error
+		flow, state, events, err = enterFlow(ctx, scheduler.FlowQuery, stateStore, scheduler.Events, scheduler.ActionExec, state, event)
+		if err != nil {
+			return err
+		}
 	}
 }
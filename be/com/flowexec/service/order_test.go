@@ -0,0 +1,54 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/olvrng/rbot/be/com/flowexec/types"
+	"github.com/olvrng/rbot/be/com/workspace"
+	"github.com/olvrng/rbot/be/pkg/dot"
+)
+
+func TestReceivedOrderEvent(t *testing.T) {
+	ctx := context.Background()
+	send := func(t *testing.T, st *serviceTest, ctx context.Context, pageID, psid dot.IntID, orderID, key string) bool {
+		req := &types.ReceivedOrderEventRequest{
+			PageID:         pageID,
+			PSID:           psid,
+			OrderID:        orderID,
+			Status:         types.OrderCompleted,
+			IdempotencyKey: key,
+		}
+		resp, err := st.orders.ReceivedOrderEvent(ctx, req)
+		require.NoError(t, err)
+		require.Equal(t, orderID, resp.Order.ID)
+		return resp.Duplicated
+	}
+
+	t.Run("ignore the duplicated events", func(t *testing.T) {
+		st := newServiceTest(t, testFlowJSON)
+		require.False(t, send(t, st, ctx, testPageID, testPSID, "o1", "key-1"))
+		require.True(t, send(t, st, ctx, testPageID, testPSID, "o1", "key-1"))
+		require.False(t, send(t, st, ctx, testPageID, testPSID, "o1", "key-2"))
+
+		// the default key is the order and its status
+		require.False(t, send(t, st, ctx, testPageID, testPSID, "o2", ""))
+		require.True(t, send(t, st, ctx, testPageID, testPSID, "o2", ""))
+	})
+
+	t.Run("scope the keys by workspace and page", func(t *testing.T) {
+		st := newServiceTest(t, testFlowJSON)
+		// the workspace 7 has its own customer, as the conversations of the
+		// workspaces are kept apart
+		ctx7 := workspace.WithID(ctx, 7)
+		require.False(t, send(t, st, ctx, testPageID, testPSID, "o1", "key-1"))
+		require.False(t, send(t, st, ctx7, testPageID, 3000, "o2", "key-1"))
+		require.False(t, send(t, st, ctx, 1001, testPSID, "o1", "key-1"))
+		require.False(t, send(t, st, ctx, 1001, testPSID, "o1", ""))
+
+		require.True(t, send(t, st, ctx7, testPageID, 3000, "o2", "key-1"))
+		require.True(t, send(t, st, ctx, 1001, testPSID, "o1", "key-1"))
+	})
+}
@@ -0,0 +1,154 @@
+package service
+
+import (
+	"context"
+	"strconv"
+
+	"github.com/olvrng/rbot/be/com/flowdef"
+	flowdeftypes "github.com/olvrng/rbot/be/com/flowdef/types"
+	"github.com/olvrng/rbot/be/com/flowexec/flowcore"
+	"github.com/olvrng/rbot/be/com/flowexec/store"
+	"github.com/olvrng/rbot/be/pkg/dot"
+	"github.com/olvrng/rbot/be/pkg/xerrors"
+)
+
+// maxGotoFlows limits the goto_flow nodes which are followed after one event,
+// so that two flows which go to each other do not loop forever.
+const maxGotoFlows = 10
+
+// listPageFlows returns the flows of the page, the highest priority first.
+func listPageFlows(ctx context.Context, query flowdef.QueryService, pageID dot.IntID) ([]*flowdeftypes.Flow, error) {
+	resp, err := query.ListFlows(ctx, &flowdeftypes.ListFlowsRequest{PageID: pageID})
+	if err != nil {
+		return nil, xerrors.Errorf(xerrors.NotFound, err, "page_id not found")
+	}
+	if len(resp.Flows) == 0 {
+		return nil, xerrors.Errorf(xerrors.NotFound, nil, "page_id not found")
+	}
+	return resp.Flows, nil
+}
+
+// routeEvent picks the flow which handles the event of the conversation, and
+// returns it with the state of the conversation in that flow. The event goes
+// to the flow with the highest route (see flowcore.Route): a keyword, then the
+// current node, then an entry point, then a fallback. On equal routes, the
+// active flow of the conversation wins, then the first flow in the order of
+// priority. When no flow handles the event, it returns the active flow, or the
+// first one.
+func routeEvent(
+	ctx context.Context,
+	stateStore *store.FlowStateStore,
+	actionExec *ActionExecutor,
+	flows []*flowdeftypes.Flow,
+	pageID, psid dot.IntID,
+	nodeType flowdeftypes.NodeType,
+	data map[string]string,
+) (*flowdeftypes.Flow, *flowcore.FlowState, error) {
+	states := map[dot.IntID]*flowcore.FlowState{}
+	var activeFlowID dot.IntID
+	if psid != 0 {
+		list, err := stateStore.ListStates(ctx, pageID, psid)
+		if err != nil {
+			return nil, nil, xerrors.Errorf(xerrors.Internal, err, "internal error")
+		}
+		for _, state := range list {
+			states[state.FlowID] = state
+		}
+		active, err := stateStore.LoadActiveState(ctx, pageID, psid)
+		switch xerrors.GetCode(err) {
+		case xerrors.NoError:
+			activeFlowID = active.FlowID
+		case xerrors.NotFound:
+		default:
+			return nil, nil, xerrors.Errorf(xerrors.Internal, err, "internal error")
+		}
+	}
+
+	var best *flowdeftypes.Flow
+	var bestState *flowcore.FlowState
+	bestRoute := flowcore.RouteNone
+	for _, flow := range flows {
+		state := states[flow.ID]
+		if state == nil {
+			state = flowcore.NewFlowState(pageID, psid, flow.ID)
+		}
+		route := actionExec.NewExecutor(flow, state).Route(nodeType, data)
+		switch {
+		case best == nil,
+			route > bestRoute,
+			route == bestRoute && flow.ID == activeFlowID:
+			best, bestState, bestRoute = flow, state, route
+		}
+	}
+	if bestRoute == flowcore.RouteNone {
+		for _, flow := range flows {
+			if flow.ID == activeFlowID {
+				return flow, states[flow.ID], nil
+			}
+		}
+	}
+	return best, bestState, nil
+}
+
+// enterFlow follows the goto_flow event: the conversation continues at the
+// node of the other flow, with the variables of the current flow.
+func enterFlow(
+	ctx context.Context,
+	query flowdef.QueryService,
+	stateStore *store.FlowStateStore,
+	events *EventHandler,
+	actionExec *ActionExecutor,
+	from *flowcore.FlowState,
+	event *flowcore.Event,
+) (*flowdeftypes.Flow, *flowcore.FlowState, []*flowcore.Event, error) {
+	flowID, _ := strconv.ParseInt(event.Data["flow_id"], 10, 64)
+	nodeID, _ := strconv.ParseInt(event.Data["node_id"], 10, 64)
+	flowResp, err := query.GetFlowByID(ctx, &flowdeftypes.GetFlowByIDRequest{ID: dot.IntID(flowID)})
+	if err != nil {
+		return nil, nil, nil, xerrors.Errorf(xerrors.NotFound, err, "flow %v not found", flowID)
+	}
+	flow := flowResp.Flow
+	if len(flow.PageIDs) != 0 && !hasPage(flow, from.PageID) {
+		return nil, nil, nil, xerrors.Errorf(xerrors.FailedPrecondition, nil, "flow %v is not a flow of page %v", flowID, from.PageID)
+	}
+
+	state, err := stateStore.LoadState(ctx, from.PageID, from.PSID, flow.ID)
+	switch xerrors.GetCode(err) {
+	case xerrors.NoError:
+	case xerrors.NotFound:
+		state = flowcore.NewFlowState(from.PageID, from.PSID, flow.ID)
+	default:
+		return nil, nil, nil, xerrors.Errorf(xerrors.Internal, err, "internal error")
+	}
+
+	ex := actionExec.NewExecutor(flow, state)
+	for name, v := range from.Vars {
+		ex.SetVar(name, v.Type, v.Value)
+	}
+	nextState, nextNodes, err := ex.Goto(dot.IntID(nodeID), nil)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	events.Handle(ctx, flow, nextState, ex.Events)
+	actionState := NewActionState(nextState)
+	actionExec.ExecuteActions(ctx, nextNodes, actionState)
+	return flow, nextState, ex.Events, nil
+}
+
+func gotoFlowEvent(events []*flowcore.Event) *flowcore.Event {
+	for _, event := range events {
+		if event.Type == flowcore.EventGotoFlow {
+			return event
+		}
+	}
+	return nil
+}
+
+func hasPage(flow *flowdeftypes.Flow, pageID dot.IntID) bool {
+	for _, id := range flow.PageIDs {
+		if id == pageID {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,107 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/olvrng/rbot/be/com/flowexec/flowcore"
+	"github.com/olvrng/rbot/be/com/flowexec/types"
+	"github.com/olvrng/rbot/be/pkg/dot"
+	"github.com/olvrng/rbot/be/pkg/xerrors"
+)
+
+const testSupportFlowJSON = `{
+	"id": "2",
+	"page_ids": ["1000"],
+	"keywords": [{"keywords": ["help"], "next_id": "11"}],
+	"nodes": [
+		{"id": "11", "payload": {"type": "action:capture_input", "template": "What is your name?",
+			"variable": "name", "next_id": "12"}},
+		{"id": "12", "payload": {"type": "action:goto_flow", "flow_id": "3", "node_id": "21"}}
+	]
+}`
+
+const testGreetingFlowJSON = `{
+	"id": "3",
+	"page_ids": ["1000"],
+	"nodes": [
+		{"id": "20", "payload": {"type": "trigger:received_message", "next_id": "21"}},
+		{"id": "21", "payload": {"type": "action:send_message", "template": "Hi {{.name}}"}}
+	]
+}`
+
+const testLoopFlowJSON = `{
+	"id": "4",
+	"page_ids": ["1000"],
+	"nodes": [
+		{"id": "30", "payload": {"type": "trigger:received_message", "next_id": "31"}},
+		{"id": "31", "payload": {"type": "action:goto_flow", "flow_id": "4", "node_id": "31"}}
+	]
+}`
+
+func TestRouteEvent(t *testing.T) {
+	ctx := context.Background()
+	receive := func(t *testing.T, st *serviceTest, text string) error {
+		req := &types.ReceivedMessageRequest{PageID: testPageID, PSID: testPSID, Message: text}
+		_, err := st.messenger.ReceivedMessage(ctx, req)
+		return err
+	}
+	activeFlowID := func(t *testing.T, st *serviceTest) dot.IntID {
+		state, err := st.stateStore.LoadActiveState(ctx, testPageID, testPSID)
+		require.NoError(t, err)
+		return state.FlowID
+	}
+
+	t.Run("keyword in another flow, timers keep running", func(t *testing.T) {
+		st := newServiceTest(t, testFlowJSON, testSupportFlowJSON)
+		st.completeOrder(t)
+		require.Equal(t, dot.IntID(1), activeFlowID(t, st))
+
+		require.NoError(t, receive(t, st, "help"))
+		require.Equal(t, dot.IntID(2), activeFlowID(t, st))
+		require.Equal(t, dot.IntID(11), st.nodeID(t))
+		require.Equal(t, []string{"What is your name?"}, st.transport.Sent())
+
+		// the wait of the order flow still fires
+		require.Equal(t, 1, st.fire(t, 3*24*time.Hour))
+		require.Equal(t, dot.IntID(1), activeFlowID(t, st))
+		require.Equal(t, []string{"What is your name?", "How was your order?"}, st.transport.Sent())
+
+		states, err := st.stateStore.ListStates(ctx, testPageID, testPSID)
+		require.NoError(t, err)
+		require.Len(t, states, 2)
+	})
+
+	t.Run("the active flow wins on equal routes", func(t *testing.T) {
+		st := newServiceTest(t, testGreetingFlowJSON, testHandoffFlowJSON)
+		require.NoError(t, receive(t, st, "hello"))
+		require.Equal(t, dot.IntID(3), activeFlowID(t, st))
+
+		// the conversation ended in the handoff flow, both flows start again
+		// on a message
+		state := flowcore.NewFlowState(testPageID, testPSID, 1)
+		state.NodeID = 3
+		require.NoError(t, st.stateStore.SaveState(ctx, state))
+		require.NoError(t, receive(t, st, "hello again"))
+		require.Equal(t, dot.IntID(1), activeFlowID(t, st))
+		require.Equal(t, []string{"Hi ", "An agent will reply soon", "pass_thread_control"}, st.transport.Sent())
+	})
+
+	t.Run("goto flow with the variables", func(t *testing.T) {
+		st := newServiceTest(t, testSupportFlowJSON, testGreetingFlowJSON)
+		require.NoError(t, receive(t, st, "help"))
+		require.NoError(t, receive(t, st, "Ann"))
+		require.Equal(t, dot.IntID(3), activeFlowID(t, st))
+		require.Equal(t, dot.IntID(21), st.nodeID(t))
+		require.Equal(t, []string{"What is your name?", "Hi Ann"}, st.transport.Sent())
+	})
+
+	t.Run("stop a goto loop", func(t *testing.T) {
+		st := newServiceTest(t, testLoopFlowJSON)
+		err := receive(t, st, "hello")
+		require.Equal(t, xerrors.Aborted, xerrors.GetCode(err))
+	})
+}
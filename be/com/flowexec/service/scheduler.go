@@ -0,0 +1,158 @@
+package service
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	"github.com/olvrng/rbot/be/com/flowdef"
+	flowdeftypes "github.com/olvrng/rbot/be/com/flowdef/types"
+	"github.com/olvrng/rbot/be/com/flowexec/flowcore"
+	"github.com/olvrng/rbot/be/com/flowexec/store"
+	"github.com/olvrng/rbot/be/com/flowexec/types"
+	"github.com/olvrng/rbot/be/com/workspace"
+	"github.com/olvrng/rbot/be/pkg/clock"
+	"github.com/olvrng/rbot/be/pkg/dot"
+	"github.com/olvrng/rbot/be/pkg/l"
+	"github.com/olvrng/rbot/be/pkg/xerrors"
+)
+
+const DefaultSchedulerInterval = 10 * time.Second
+
+// Scheduler fires the trigger:timer transitions of wait nodes and of nodes with
+// a timeout. The timers are persisted, so the pending ones still fire after a
+// restart.
+type Scheduler struct {
+	FlowQuery  flowdef.QueryService
+	StateStore *store.FlowStateStore
+	TimerStore *store.TimerStore
+	Events     *EventHandler
+	ActionExec *ActionExecutor
+	Clock      clock.Clock
+
+	// Interval is how often the due timers are checked.
+	Interval time.Duration
+}
+
+func NewScheduler(
+	query flowdef.QueryService,
+	stateStore *store.FlowStateStore,
+	timerStore *store.TimerStore,
+	events *EventHandler,
+	actionExec *ActionExecutor,
+	clk clock.Clock,
+) *Scheduler {
+	s := &Scheduler{
+		FlowQuery:  query,
+		StateStore: stateStore,
+		TimerStore: timerStore,
+		Events:     events,
+		ActionExec: actionExec,
+		Clock:      clk,
+		Interval:   DefaultSchedulerInterval,
+	}
+	return s
+}
+
+// Schedule must be called after each transition of a conversation. It cancels
+// the pending timer, then starts a new one if the current node has a timer.
+func (s *Scheduler) Schedule(ctx context.Context, flow *flowdeftypes.Flow, state *flowcore.FlowState) error {
+	node := flow.NodeByID(state.NodeID)
+	if node == nil || node.Payload.Timer() <= 0 || state.Phase == flowcore.PhaseDone {
+		return s.TimerStore.CancelTimer(ctx, state.PageID, state.PSID, flow.ID)
+	}
+
+	now := s.Clock.Now()
+	timer := &types.Timer{
+		ID:        dot.NewIntID(),
+		PageID:    state.PageID,
+		PSID:      state.PSID,
+		FlowID:    flow.ID,
+		NodeID:    node.ID,
+		FireAt:    dot.ToTimestamp(now.Add(node.Payload.Timer())),
+		CreatedAt: dot.ToTimestamp(now),
+	}
+	return s.TimerStore.SaveTimer(ctx, timer)
+}
+
+// Run fires the due timers every Interval until the context is cancelled.
+func (s *Scheduler) Run(ctx context.Context) {
+	ticker := time.NewTicker(s.Interval)
+	defer ticker.Stop()
+	for {
+		if _, err := s.FireDueTimers(ctx); err != nil {
+			ll.Error("scheduler: can not fire timers", l.Error(err))
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// FireDueTimers fires the timers which are due at the current time of the
+// clock. It returns the number of transitions.
+func (s *Scheduler) FireDueTimers(ctx context.Context) (fired int, _ error) {
+	now := dot.ToTimestamp(s.Clock.Now())
+	timers, err := s.TimerStore.ListDueTimers(ctx, now)
+	if err != nil {
+		return 0, err
+	}
+	dueTimers.Set(float64(len(timers)))
+	for _, timer := range timers {
+		// each timer fires in the workspace of its conversation
+		ctx := workspace.WithID(ctx, timer.WorkspaceID)
+		ok, err := s.fire(ctx, timer)
+		if err != nil {
+			ll.Error("scheduler: can not fire timer", l.ID("timer_id", timer.ID), l.Error(err))
+		}
+		if ok {
+			fired++
+		}
+		// the timer only fires once, even when the transition fails
+		if err = s.TimerStore.DeleteTimer(ctx, timer); err != nil {
+			return fired, err
+		}
+	}
+	return fired, nil
+}
+
+func (s *Scheduler) fire(ctx context.Context, timer *types.Timer) (ok bool, _ error) {
+	unlock := s.StateStore.LockConversation(timer.PageID, timer.PSID)
+	defer unlock()
+	state, err := s.StateStore.LoadState(ctx, timer.PageID, timer.PSID, timer.FlowID)
+	if err != nil {
+		return false, err
+	}
+	if state.NodeID != timer.NodeID {
+		ll.Debug("scheduler: the conversation has moved on", l.ID("timer_id", timer.ID))
+		return false, nil
+	}
+	if _, err = s.Events.HandoffStore.GetOpenHandoff(ctx, timer.PageID, timer.PSID); err == nil {
+		ll.Debug("scheduler: the bot is paused", l.ID("timer_id", timer.ID))
+		return false, nil
+	}
+
+	flowReq := &flowdeftypes.GetFlowByIDRequest{ID: timer.FlowID}
+	flowResp, err := s.FlowQuery.GetFlowByID(ctx, flowReq)
+	if err != nil {
+		return false, xerrors.Errorf(xerrors.NotFound, err, "flow not found")
+	}
+	flow := flowResp.Flow
+
+	ex := s.ActionExec.NewExecutor(flow, state)
+	stateData := map[string]string{
+		"timer_id": strconv.FormatInt(int64(timer.ID), 10),
+	}
+	nextState, nextNodes, err := execNextState(ex, flowdeftypes.NodeTimer, stateData)
+	if err != nil {
+		return false, err
+	}
+
+	s.Events.Handle(ctx, flow, nextState, ex.Events)
+	actionState := NewActionState(nextState)
+	s.ActionExec.ExecuteActions(ctx, nextNodes, actionState)
+
+	return true, saveState(ctx, s.StateStore, s, flow, nextState, ex.Events)
+}
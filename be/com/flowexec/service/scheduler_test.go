@@ -0,0 +1,304 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"path"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/go-resty/resty/v2"
+	"github.com/stretchr/testify/require"
+
+	flowdeftypes "github.com/olvrng/rbot/be/com/flowdef/types"
+	"github.com/olvrng/rbot/be/com/flowexec/store"
+	"github.com/olvrng/rbot/be/com/flowexec/types"
+	"github.com/olvrng/rbot/be/com/integration/fbmsg"
+	"github.com/olvrng/rbot/be/com/workspace"
+	"github.com/olvrng/rbot/be/pkg/clock"
+	"github.com/olvrng/rbot/be/pkg/dot"
+	"github.com/olvrng/rbot/be/pkg/xerrors"
+)
+
+const testPageID, testPSID = 1000, 2000
+
+const testFlowJSON = `{
+	"id": "1",
+	"page_ids": ["1000"],
+	"nodes": [
+		{"id": "1", "payload": {"type": "trigger:completed_order", "next_id": "2"}},
+		{"id": "2", "payload": {"type": "action:wait", "delay": "3d", "next_id": "3"}},
+		{"id": "3", "payload": {"type": "action:send_message", "template": "How was your order?",
+			"next_id": "5", "timeout": "24h", "timeout_next_id": "4"}},
+		{"id": "4", "payload": {"type": "action:send_message", "template": "Reminder: how was your order?"}},
+		{"id": "5", "payload": {"type": "action:send_message", "template": "Thank you!"}}
+	]
+}`
+
+// mockFlowQuery serves the flows of the test page, in the order of priority.
+type mockFlowQuery struct {
+	flows []*flowdeftypes.Flow
+}
+
+func (q *mockFlowQuery) GetFlowByID(ctx context.Context, req *flowdeftypes.GetFlowByIDRequest) (*flowdeftypes.FlowResponse, error) {
+	for _, flow := range q.flows {
+		if flow.ID == req.ID {
+			return &flowdeftypes.FlowResponse{Flow: flow}, nil
+		}
+	}
+	return nil, xerrors.Errorf(xerrors.NotFound, nil, "flow not found")
+}
+
+func (q *mockFlowQuery) GetFlowByParam(ctx context.Context, req *flowdeftypes.GetFlowByParamRequest) (*flowdeftypes.FlowResponse, error) {
+	return &flowdeftypes.FlowResponse{Flow: q.flows[0]}, nil
+}
+
+func (q *mockFlowQuery) ListFlows(ctx context.Context, req *flowdeftypes.ListFlowsRequest) (*flowdeftypes.ListFlowsResponse, error) {
+	return &flowdeftypes.ListFlowsResponse{Flows: q.flows}, nil
+}
+
+// mockTransport records the messages sent to the Send API, and the name of the
+// other APIs which are called.
+type mockTransport struct {
+	m    sync.Mutex
+	sent []string
+}
+
+func (t *mockTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	sent := path.Base(req.URL.Path)
+	if sent == "messages" {
+		var sendReq fbmsg.SendRequest
+		body, _ := ioutil.ReadAll(req.Body)
+		if err := json.Unmarshal(body, &sendReq); err != nil {
+			return nil, err
+		}
+		sent = sendReq.Message.Text
+	}
+	t.m.Lock()
+	t.sent = append(t.sent, sent)
+	n := len(t.sent)
+	t.m.Unlock()
+
+	resp := fmt.Sprintf(`{"recipient_id":"2000","message_id":"mid.%v"}`, n)
+	return &http.Response{
+		StatusCode: 200,
+		Header:     http.Header{"Content-Type": {"application/json"}},
+		Body:       ioutil.NopCloser(strings.NewReader(resp)),
+		Request:    req,
+	}, nil
+}
+
+func (t *mockTransport) Sent() []string {
+	t.m.Lock()
+	defer t.m.Unlock()
+	return append([]string(nil), t.sent...)
+}
+
+type serviceTest struct {
+	dir       string
+	clock     *clock.Mock
+	transport *mockTransport
+	query     *mockFlowQuery
+	bus       *EventBus
+
+	stateStore *store.FlowStateStore
+	actionExec *ActionExecutor
+	scheduler  *Scheduler
+	orders     *OrderService
+	messenger  *MessengerService
+	handoffs   *HandoffService
+}
+
+// newServiceTest serves the flows to the test page, the first one with the
+// highest priority.
+func newServiceTest(t *testing.T, flowJSONs ...string) *serviceTest {
+	query := &mockFlowQuery{}
+	for _, flowJSON := range flowJSONs {
+		var flow flowdeftypes.Flow
+		require.NoError(t, json.Unmarshal([]byte(flowJSON), &flow))
+		query.flows = append(query.flows, &flow)
+	}
+
+	st := &serviceTest{
+		dir:       t.TempDir(),
+		clock:     clock.NewMock(time.Date(2021, 6, 1, 10, 0, 0, 0, time.UTC)),
+		transport: &mockTransport{},
+		query:     query,
+	}
+	st.bus = NewEventBus(st.clock)
+	st.start(t)
+	return st
+}
+
+// start creates the services from the data files, like restarting the server.
+func (st *serviceTest) start(t *testing.T) {
+	dataPath := func(name string) string { return filepath.Join(st.dir, name) }
+	stateStore, err := store.NewFlowStateStore(dataPath("state.json"))
+	require.NoError(t, err)
+	orderStore, err := store.NewOrderStore(dataPath("order.json"))
+	require.NoError(t, err)
+	linkStore, err := store.NewCustomerLinkStore(dataPath("link.json"))
+	require.NoError(t, err)
+	timerStore, err := store.NewTimerStore(dataPath("timer.json"))
+	require.NoError(t, err)
+	handoffStore, err := store.NewHandoffStore(dataPath("handoff.json"))
+	require.NoError(t, err)
+
+	fbClient := &fbmsg.Client{HTTP: resty.New().SetTransport(st.transport)}
+	customers := NewCustomerService(linkStore, orderStore)
+	actionExec := NewActionExecutor(fbClient, customers, nil, st.bus)
+	events := NewEventHandler(nil, orderStore, handoffStore, st.bus)
+	st.stateStore = stateStore
+	st.actionExec = actionExec
+	st.scheduler = NewScheduler(st.query, stateStore, timerStore, events, actionExec, st.clock)
+	st.handoffs = NewHandoffService(st.query, stateStore, handoffStore, events, actionExec, st.scheduler)
+	st.orders = NewOrderService(st.query, stateStore, orderStore, customers, st.handoffs, events, actionExec, st.scheduler)
+	st.messenger = NewMessengerService(st.query, stateStore, customers, st.handoffs, events, actionExec, st.scheduler)
+}
+
+func (st *serviceTest) completeOrder(t *testing.T) {
+	req := &types.ReceivedCompletedOrderRequest{PageID: testPageID, PSID: testPSID, OrderID: "order-1"}
+	_, err := st.orders.ReceivedCompletedOrder(context.Background(), req)
+	require.NoError(t, err)
+}
+
+func (st *serviceTest) fire(t *testing.T, d time.Duration) int {
+	st.clock.Add(d)
+	fired, err := st.scheduler.FireDueTimers(context.Background())
+	require.NoError(t, err)
+	return fired
+}
+
+// nodeID returns the current node of the conversation, in the active flow.
+func (st *serviceTest) nodeID(t *testing.T) dot.IntID {
+	state, err := st.stateStore.LoadActiveState(context.Background(), testPageID, testPSID)
+	require.NoError(t, err)
+	return state.NodeID
+}
+
+func TestScheduler(t *testing.T) {
+	t.Run("wait, then timeout", func(t *testing.T) {
+		st := newServiceTest(t, testFlowJSON)
+		st.completeOrder(t)
+		require.Equal(t, dot.IntID(2), st.nodeID(t))
+		require.Empty(t, st.transport.Sent())
+
+		require.Equal(t, 0, st.fire(t, 2*24*time.Hour))
+		require.Equal(t, 1, st.fire(t, 24*time.Hour))
+		require.Equal(t, dot.IntID(3), st.nodeID(t))
+		require.Equal(t, []string{"How was your order?"}, st.transport.Sent())
+
+		require.Equal(t, 0, st.fire(t, 23*time.Hour))
+		require.Equal(t, 1, st.fire(t, time.Hour))
+		require.Equal(t, dot.IntID(4), st.nodeID(t))
+		require.Equal(t, []string{"How was your order?", "Reminder: how was your order?"}, st.transport.Sent())
+
+		// no more timer
+		require.Equal(t, 0, st.fire(t, 30*24*time.Hour))
+	})
+
+	t.Run("survive restart", func(t *testing.T) {
+		st := newServiceTest(t, testFlowJSON)
+		st.completeOrder(t)
+
+		st.start(t)
+		require.Equal(t, 1, st.fire(t, 3*24*time.Hour))
+		require.Equal(t, dot.IntID(3), st.nodeID(t))
+	})
+
+	t.Run("cancel when the conversation moves on", func(t *testing.T) {
+		st := newServiceTest(t, testFlowJSON)
+		st.completeOrder(t)
+		require.Equal(t, 1, st.fire(t, 3*24*time.Hour))
+
+		req := &types.ReceivedMessageRequest{PageID: testPageID, PSID: testPSID, Message: "great"}
+		_, err := st.messenger.ReceivedMessage(context.Background(), req)
+		require.NoError(t, err)
+		require.Equal(t, dot.IntID(5), st.nodeID(t))
+
+		require.Equal(t, 0, st.fire(t, 24*time.Hour))
+		require.Equal(t, []string{"How was your order?", "Thank you!"}, st.transport.Sent())
+	})
+
+	t.Run("ignore the timer of another node", func(t *testing.T) {
+		st := newServiceTest(t, testFlowJSON)
+		st.completeOrder(t)
+
+		// the state moves without going through the services
+		state, err := st.stateStore.LoadActiveState(context.Background(), testPageID, testPSID)
+		require.NoError(t, err)
+		next := state.Next(nil)
+		next.NodeID = 5
+		require.NoError(t, st.stateStore.SaveState(context.Background(), next))
+
+		require.Equal(t, 0, st.fire(t, 3*24*time.Hour))
+		require.Empty(t, st.transport.Sent())
+	})
+
+	t.Run("wait for the transition of the conversation", func(t *testing.T) {
+		st := newServiceTest(t, testFlowJSON)
+		st.completeOrder(t)
+
+		// a message of the conversation is being handled
+		unlock := st.stateStore.LockConversation(testPageID, testPSID)
+		st.clock.Add(3 * 24 * time.Hour)
+		fired := make(chan int, 1)
+		go func() {
+			n, _ := st.scheduler.FireDueTimers(context.Background())
+			fired <- n
+		}()
+		require.Never(t, func() bool { return len(fired) != 0 }, 100*time.Millisecond, 10*time.Millisecond)
+
+		// the message moves the conversation, then the timer does not fire
+		state, err := st.stateStore.LoadActiveState(context.Background(), testPageID, testPSID)
+		require.NoError(t, err)
+		next := state.Next(nil)
+		next.NodeID = 5
+		require.NoError(t, st.stateStore.SaveState(context.Background(), next))
+		unlock()
+
+		require.Equal(t, 0, <-fired)
+		require.Equal(t, dot.IntID(5), st.nodeID(t))
+		require.Empty(t, st.transport.Sent())
+	})
+
+	t.Run("fire in the workspace of the timer", func(t *testing.T) {
+		st := newServiceTest(t, testFlowJSON)
+		ctx := workspace.WithID(context.Background(), 7)
+		req := &types.ReceivedCompletedOrderRequest{PageID: testPageID, PSID: testPSID, OrderID: "order-1"}
+		_, err := st.orders.ReceivedCompletedOrder(ctx, req)
+		require.NoError(t, err)
+
+		require.Equal(t, 1, st.fire(t, 3*24*time.Hour))
+		state, err := st.stateStore.LoadActiveState(ctx, testPageID, testPSID)
+		require.NoError(t, err)
+		require.Equal(t, dot.IntID(3), state.NodeID)
+
+		// the other workspaces neither see nor replace the conversation
+		_, err = st.stateStore.LoadActiveState(context.Background(), testPageID, testPSID)
+		require.Equal(t, xerrors.NotFound, xerrors.GetCode(err))
+		err = st.stateStore.SaveState(context.Background(), state.Next(nil))
+		require.Equal(t, xerrors.PermissionDenied, xerrors.GetCode(err))
+	})
+	t.Run("count the transitions and the due timers", func(t *testing.T) {
+		// the metrics are shared by the tests, so only the deltas are checked
+		waits := transitionsTotal.Value(flowdeftypes.NodeWait)
+		sends := transitionsTotal.Value(flowdeftypes.NodeSendMessage)
+		st := newServiceTest(t, testFlowJSON)
+		st.completeOrder(t)
+		require.Equal(t, waits+1, transitionsTotal.Value(flowdeftypes.NodeWait))
+
+		require.Equal(t, 1, st.fire(t, 3*24*time.Hour))
+		require.Equal(t, float64(1), dueTimers.Value())
+		require.Equal(t, sends+1, transitionsTotal.Value(flowdeftypes.NodeSendMessage))
+
+		require.Equal(t, 0, st.fire(t, time.Hour))
+		require.Equal(t, float64(0), dueTimers.Value())
+	})
+}
@@ -0,0 +1,309 @@
+package service
+
+import (
+	"context"
+	"strconv"
+	"sync"
+
+	"github.com/olvrng/rbot/be/com/flowdef"
+	flowdeftypes "github.com/olvrng/rbot/be/com/flowdef/types"
+	"github.com/olvrng/rbot/be/com/flowexec"
+	"github.com/olvrng/rbot/be/com/flowexec/store"
+	"github.com/olvrng/rbot/be/com/flowexec/types"
+	"github.com/olvrng/rbot/be/com/integration/fbmsg"
+	reviewservice "github.com/olvrng/rbot/be/com/review/service"
+	reviewstore "github.com/olvrng/rbot/be/com/review/store"
+	"github.com/olvrng/rbot/be/pkg/clock"
+	"github.com/olvrng/rbot/be/pkg/dot"
+	"github.com/olvrng/rbot/be/pkg/xerrors"
+)
+
+// The virtual conversation is between this customer and the first page of the
+// flow, or SimulatedPageID when the flow has no page.
+const (
+	SimulatedPageID dot.IntID = 1
+	SimulatedPSID   dot.IntID = 1
+
+	MaxSimulationSteps = 100
+)
+
+// simulatorRefSecret signs the refs of the simulated referrals, which are given
+// unsigned in the steps.
+const simulatorRefSecret = "simulator"
+
+var _ flowexec.SimulatorService = (*SimulatorService)(nil)
+
+// SimulatorService runs flows against virtual conversations. Each simulation
+// has the same services as the webhook, but with in-memory stores, a mock clock
+// and a CaptureMessenger instead of the Send API.
+type SimulatorService struct {
+	FlowQuery flowdef.QueryService
+	Clock     clock.Clock
+}
+
+func NewSimulatorService(query flowdef.QueryService, clock clock.Clock) *SimulatorService {
+	s := &SimulatorService{
+		FlowQuery: query,
+		Clock:     clock,
+	}
+	return s
+}
+
+func (s *SimulatorService) SimulateFlow(ctx context.Context, req *types.SimulateFlowRequest) (*types.SimulateFlowResponse, error) {
+	if len(req.Steps) == 0 {
+		return nil, xerrors.Errorf(xerrors.InvalidArgument, nil, "steps are required")
+	}
+	if len(req.Steps) > MaxSimulationSteps {
+		return nil, xerrors.Errorf(xerrors.InvalidArgument, nil, "too many steps (max %v)", MaxSimulationSteps)
+	}
+	flow := req.Flow
+	if flow == nil {
+		flowResp, err := s.FlowQuery.GetFlowByID(ctx, &flowdeftypes.GetFlowByIDRequest{ID: req.FlowID})
+		if err != nil {
+			return nil, err
+		}
+		flow = flowResp.Flow
+	}
+	if err := flow.Validate(); err != nil {
+		return nil, xerrors.Errorf(xerrors.InvalidArgument, err, "invalid flow: %v", err)
+	}
+
+	sim, err := newSimulation(flow, s.FlowQuery, s.Clock)
+	if err != nil {
+		return nil, xerrors.Errorf(xerrors.Internal, err, "internal error")
+	}
+	resp := &types.SimulateFlowResponse{}
+	for _, step := range req.Steps {
+		resp.Steps = append(resp.Steps, sim.run(ctx, step))
+	}
+	return resp, nil
+}
+
+// simulation is a virtual conversation with its own services.
+type simulation struct {
+	pageID dot.IntID
+	psid   dot.IntID
+
+	clock        *clock.Mock
+	messenger    *CaptureMessenger
+	stateStore   *store.FlowStateStore
+	handoffStore *store.HandoffStore
+	scheduler    *Scheduler
+	orders       *OrderService
+	messages     *MessengerService
+}
+
+func newSimulation(flow *flowdeftypes.Flow, flowQuery flowdef.QueryService, clk clock.Clock) (*simulation, error) {
+	sim := &simulation{
+		pageID:    SimulatedPageID,
+		psid:      SimulatedPSID,
+		clock:     clock.NewMock(clk.Now()),
+		messenger: &CaptureMessenger{},
+	}
+	if len(flow.PageIDs) != 0 {
+		sim.pageID = flow.PageIDs[0]
+	}
+
+	// the stores are kept in memory without file path
+	stateStore, err := store.NewFlowStateStore("")
+	if err != nil {
+		return nil, err
+	}
+	orderStore, err := store.NewOrderStore("")
+	if err != nil {
+		return nil, err
+	}
+	linkStore, err := store.NewCustomerLinkStore("")
+	if err != nil {
+		return nil, err
+	}
+	timerStore, err := store.NewTimerStore("")
+	if err != nil {
+		return nil, err
+	}
+	handoffStore, err := store.NewHandoffStore("")
+	if err != nil {
+		return nil, err
+	}
+	reviewStore, err := reviewstore.NewReviewStore("")
+	if err != nil {
+		return nil, err
+	}
+
+	query := &simulatedFlowQuery{flow: flow, query: flowQuery}
+	customers := NewCustomerService(linkStore, orderStore)
+	customers.RefSecret = simulatorRefSecret
+	events := NewEventHandler(reviewservice.NewReviewService(reviewStore), orderStore, handoffStore, nil)
+	actionExec := NewActionExecutor(sim.messenger, customers, nil, nil)
+	sim.stateStore = stateStore
+	sim.handoffStore = handoffStore
+	sim.scheduler = NewScheduler(query, stateStore, timerStore, events, actionExec, sim.clock)
+	handoffs := NewHandoffService(query, stateStore, handoffStore, events, actionExec, sim.scheduler)
+	sim.orders = NewOrderService(query, stateStore, orderStore, customers, handoffs, events, actionExec, sim.scheduler)
+	sim.messages = NewMessengerService(query, stateStore, customers, handoffs, events, actionExec, sim.scheduler)
+	return sim, nil
+}
+
+func (sim *simulation) run(ctx context.Context, step *types.SimulationStep) *types.SimulationStepResult {
+	result := &types.SimulationStepResult{Step: step}
+	if state, err := sim.stateStore.LoadActiveState(ctx, sim.pageID, sim.psid); err == nil {
+		result.FromNodeID = state.NodeID
+	}
+	if err := sim.dispatch(ctx, step); err != nil {
+		result.Error = err.Error()
+	}
+
+	result.Messages = sim.messenger.Take()
+	result.Vars = map[string]string{}
+	if state, err := sim.stateStore.LoadActiveState(ctx, sim.pageID, sim.psid); err == nil {
+		result.ToNodeID = state.NodeID
+		result.ToFlowID = state.FlowID
+		for name, v := range state.Vars {
+			result.Vars[name] = v.Value
+		}
+	}
+	_, err := sim.handoffStore.GetOpenHandoff(ctx, sim.pageID, sim.psid)
+	result.Paused = err == nil
+	return result
+}
+
+func (sim *simulation) dispatch(ctx context.Context, step *types.SimulationStep) error {
+	switch step.Event {
+	case types.SimulateMessage:
+		req := &types.ReceivedMessageRequest{PageID: sim.pageID, PSID: sim.psid, Message: step.Text}
+		_, err := sim.messages.ReceivedMessage(ctx, req)
+		return err
+
+	case types.SimulatePostback:
+		req := &types.ReceivedPostbackRequest{
+			PageID:          sim.pageID,
+			PSID:            sim.psid,
+			PostbackTitle:   step.Text,
+			PostbackPayload: step.Payload,
+		}
+		_, err := sim.messages.ReceivedPostback(ctx, req)
+		return err
+
+	case types.SimulateReferral:
+		// the ref is signed like the links of the shop
+		ref := SignRef(simulatorRefSecret, sim.pageID, step.Payload)
+		req := &types.ReceivedReferralRequest{PageID: sim.pageID, PSID: sim.psid, Ref: ref}
+		_, err := sim.messages.ReceivedReferral(ctx, req)
+		return err
+
+	case types.SimulateOrder:
+		req := &types.ReceivedOrderEventRequest{
+			PageID:  sim.pageID,
+			PSID:    sim.psid,
+			OrderID: step.OrderID,
+			Status:  step.OrderStatus,
+		}
+		if req.OrderID == "" {
+			req.OrderID = "simulated-order"
+		}
+		if req.Status == "" {
+			req.Status = types.OrderCompleted
+		}
+		_, err := sim.orders.ReceivedOrderEvent(ctx, req)
+		return err
+
+	case types.SimulateWait:
+		if step.Delay <= 0 {
+			return xerrors.Errorf(xerrors.InvalidArgument, nil, "delay is required")
+		}
+		sim.clock.Add(step.Delay.Duration())
+		_, err := sim.scheduler.FireDueTimers(ctx)
+		return err
+
+	default:
+		return xerrors.Errorf(xerrors.InvalidArgument, nil, "unknown event %q", step.Event)
+	}
+}
+
+// simulatedFlowQuery routes all the events to the simulated flow. The other
+// flows are only entered with goto_flow nodes, from the stored flows.
+type simulatedFlowQuery struct {
+	flow  *flowdeftypes.Flow
+	query flowdef.QueryService
+}
+
+func (q *simulatedFlowQuery) GetFlowByID(ctx context.Context, req *flowdeftypes.GetFlowByIDRequest) (*flowdeftypes.FlowResponse, error) {
+	if q.query != nil && req.ID != q.flow.ID {
+		return q.query.GetFlowByID(ctx, req)
+	}
+	return &flowdeftypes.FlowResponse{Flow: q.flow}, nil
+}
+
+func (q *simulatedFlowQuery) GetFlowByParam(ctx context.Context, req *flowdeftypes.GetFlowByParamRequest) (*flowdeftypes.FlowResponse, error) {
+	return &flowdeftypes.FlowResponse{Flow: q.flow}, nil
+}
+
+func (q *simulatedFlowQuery) ListFlows(ctx context.Context, req *flowdeftypes.ListFlowsRequest) (*flowdeftypes.ListFlowsResponse, error) {
+	return &flowdeftypes.ListFlowsResponse{Flows: []*flowdeftypes.Flow{q.flow}}, nil
+}
+
+// CaptureMessenger records the messages instead of sending them.
+type CaptureMessenger struct {
+	m        sync.Mutex
+	messages []*types.SimulatedMessage
+	count    int
+}
+
+var _ Messenger = (*CaptureMessenger)(nil)
+
+func (c *CaptureMessenger) SendMessage(ctx context.Context, req *fbmsg.SendRequest) (*fbmsg.SendResponse, error) {
+	msg := &types.SimulatedMessage{Text: messageText(req.Message)}
+	for _, reply := range req.Message.QuickReplies {
+		msg.Buttons = append(msg.Buttons, &types.SimulatedButton{Title: reply.Title, Payload: reply.Payload})
+	}
+	if att := req.Message.Attachment; att != nil && att.Payload != nil {
+		for _, el := range att.Payload.Elements {
+			for _, btn := range el.Buttons {
+				if btn.PostbackButton != nil {
+					button := &types.SimulatedButton{Title: btn.PostbackButton.Title, Payload: btn.PostbackButton.Payload}
+					msg.Buttons = append(msg.Buttons, button)
+				}
+			}
+		}
+	}
+	count := c.add(msg)
+
+	resp := &fbmsg.SendResponse{
+		RecipientID: req.Recipient.ID,
+		MessageID:   "mid.simulated." + strconv.Itoa(count),
+	}
+	if resp.RecipientID == 0 {
+		resp.RecipientID = SimulatedPSID
+	}
+	return resp, nil
+}
+
+func (c *CaptureMessenger) PassThreadControl(ctx context.Context, req *fbmsg.PassThreadControlRequest) error {
+	c.add(&types.SimulatedMessage{Action: "pass_thread_control"})
+	return nil
+}
+
+func (c *CaptureMessenger) TakeThreadControl(ctx context.Context, req *fbmsg.TakeThreadControlRequest) error {
+	c.add(&types.SimulatedMessage{Action: "take_thread_control"})
+	return nil
+}
+
+func (c *CaptureMessenger) add(msg *types.SimulatedMessage) int {
+	c.m.Lock()
+	defer c.m.Unlock()
+	c.messages = append(c.messages, msg)
+	c.count++
+	return c.count
+}
+
+// Take returns the messages which are captured since the last call.
+func (c *CaptureMessenger) Take() []*types.SimulatedMessage {
+	c.m.Lock()
+	defer c.m.Unlock()
+	msgs := c.messages
+	c.messages = nil
+	if msgs == nil {
+		msgs = []*types.SimulatedMessage{}
+	}
+	return msgs
+}
@@ -0,0 +1,73 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	flowdeftypes "github.com/olvrng/rbot/be/com/flowdef/types"
+	"github.com/olvrng/rbot/be/com/flowexec/types"
+	"github.com/olvrng/rbot/be/pkg/clock"
+	"github.com/olvrng/rbot/be/pkg/dot"
+)
+
+func TestSimulateFlow(t *testing.T) {
+	ctx := context.Background()
+	simulate := func(t *testing.T, flowJSON string, steps ...*types.SimulationStep) []*types.SimulationStepResult {
+		var flow flowdeftypes.Flow
+		require.NoError(t, json.Unmarshal([]byte(flowJSON), &flow))
+		s := NewSimulatorService(nil, clock.NewMock(time.Date(2021, 6, 1, 10, 0, 0, 0, time.UTC)))
+		resp, err := s.SimulateFlow(ctx, &types.SimulateFlowRequest{Flow: &flow, Steps: steps})
+		require.NoError(t, err)
+		require.Len(t, resp.Steps, len(steps))
+		return resp.Steps
+	}
+	texts := func(msgs []*types.SimulatedMessage) []string {
+		result := []string{}
+		for _, msg := range msgs {
+			result = append(result, msg.Text+msg.Action)
+		}
+		return result
+	}
+
+	t.Run("order, wait and reply", func(t *testing.T) {
+		results := simulate(t, testFlowJSON,
+			&types.SimulationStep{Event: types.SimulateOrder, OrderID: "order-1"},
+			&types.SimulationStep{Event: types.SimulateWait, Delay: flowdeftypes.Duration(3 * 24 * time.Hour)},
+			&types.SimulationStep{Event: types.SimulateMessage, Text: "great"},
+		)
+		require.Equal(t, dot.IntID(2), results[0].ToNodeID)
+		require.Empty(t, results[0].Messages)
+		require.Equal(t, "order-1", results[0].Vars["order_id"])
+
+		require.Equal(t, dot.IntID(2), results[1].FromNodeID)
+		require.Equal(t, dot.IntID(3), results[1].ToNodeID)
+		require.Equal(t, []string{"How was your order?"}, texts(results[1].Messages))
+
+		require.Equal(t, dot.IntID(5), results[2].ToNodeID)
+		require.Equal(t, []string{"Thank you!"}, texts(results[2].Messages))
+	})
+
+	t.Run("handoff", func(t *testing.T) {
+		results := simulate(t, testHandoffFlowJSON,
+			&types.SimulationStep{Event: types.SimulateMessage, Text: "help"},
+			&types.SimulationStep{Event: types.SimulateMessage, Text: "anyone?"},
+		)
+		require.Equal(t, []string{"An agent will reply soon", "pass_thread_control"}, texts(results[0].Messages))
+		require.True(t, results[0].Paused)
+		require.Empty(t, results[1].Messages)
+		require.True(t, results[1].Paused)
+	})
+
+	t.Run("invalid step", func(t *testing.T) {
+		results := simulate(t, testFlowJSON,
+			&types.SimulationStep{Event: "unknown"},
+			&types.SimulationStep{Event: types.SimulateWait},
+		)
+		require.Contains(t, results[0].Error, "unknown event")
+		require.Contains(t, results[1].Error, "delay is required")
+	})
+}
@@ -0,0 +1,140 @@
+package store
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"sort"
+	"sync"
+
+	"github.com/olvrng/rbot/be/com/flowexec/types"
+	"github.com/olvrng/rbot/be/com/workspace"
+	"github.com/olvrng/rbot/be/pkg/dot"
+	"github.com/olvrng/rbot/be/pkg/xerrors"
+)
+
+type HandoffFile struct {
+	// Open holds the open handoff of each conversation.
+	Open map[string]*types.Handoff `json:"open"`
+
+	Resolved []*types.Handoff `json:"resolved"`
+}
+
+type HandoffStore struct {
+	FilePath string
+	Data     *HandoffFile
+
+	m sync.Mutex
+}
+
+func NewHandoffStore(filePath string) (*HandoffStore, error) {
+	s := &HandoffStore{
+		FilePath: filePath,
+	}
+
+	_, err := os.Stat(filePath)
+	switch {
+	case err == nil: // load from storage
+		data, err := ioutil.ReadFile(filePath)
+		if err != nil {
+			return nil, err
+		}
+		if err = json.Unmarshal(data, &s.Data); err != nil {
+			return nil, err
+		}
+		if s.Data.Open == nil {
+			s.Data.Open = make(map[string]*types.Handoff)
+		}
+		return s, nil
+
+	case os.IsNotExist(err): // try creating one
+		s.Data = &HandoffFile{Open: make(map[string]*types.Handoff)}
+		err = storeFile(filePath, s.Data)
+		return s, err
+
+	default:
+		return nil, err
+	}
+}
+
+// SaveHandoff saves the open handoff of the conversation. A resolved handoff is
+// moved to the history.
+func (s *HandoffStore) SaveHandoff(ctx context.Context, handoff *types.Handoff) error {
+	s.m.Lock()
+	defer s.m.Unlock()
+
+	key := mockEncodeRunID(handoff.PageID, handoff.PSID)
+	if open := s.Data.Open[key]; open != nil {
+		if err := workspace.CheckOwner(ctx, open.WorkspaceID); err != nil {
+			return err
+		}
+	}
+	handoff.WorkspaceID = workspace.GetID(ctx)
+	switch handoff.Status {
+	case types.HandoffOpen:
+		s.Data.Open[key] = handoff
+	case types.HandoffResolved:
+		if open := s.Data.Open[key]; open != nil && open.ID == handoff.ID {
+			delete(s.Data.Open, key)
+		}
+		s.Data.Resolved = append(s.Data.Resolved, handoff)
+	default:
+		return xerrors.Errorf(xerrors.InvalidArgument, nil, "invalid status %v", handoff.Status)
+	}
+	return storeFile(s.FilePath, s.Data)
+}
+
+// GetOpenHandoff returns the open handoff of the conversation.
+func (s *HandoffStore) GetOpenHandoff(ctx context.Context, pageID, psid dot.IntID) (*types.Handoff, error) {
+	s.m.Lock()
+	defer s.m.Unlock()
+
+	handoff := s.Data.Open[mockEncodeRunID(pageID, psid)]
+	if handoff == nil || !workspace.Match(ctx, handoff.WorkspaceID) {
+		return nil, xerrors.Errorf(xerrors.NotFound, nil, "handoff not found")
+	}
+	return handoff, nil
+}
+
+// AddMessage appends the message to the open handoff of the conversation. It
+// returns false when the conversation has no open handoff.
+func (s *HandoffStore) AddMessage(ctx context.Context, pageID, psid dot.IntID, msg *types.HandoffMessage) (ok bool, _ error) {
+	s.m.Lock()
+	defer s.m.Unlock()
+
+	handoff := s.Data.Open[mockEncodeRunID(pageID, psid)]
+	if handoff == nil || !workspace.Match(ctx, handoff.WorkspaceID) {
+		return false, nil
+	}
+	handoff.Messages = append(handoff.Messages, msg)
+	handoff.UpdatedAt = msg.CreatedAt
+	return true, storeFile(s.FilePath, s.Data)
+}
+
+// ListHandoffs returns the handoffs of the page, the most recently updated
+// first. An empty status returns both open and resolved handoffs.
+func (s *HandoffStore) ListHandoffs(ctx context.Context, pageID dot.IntID, status types.HandoffStatus) ([]*types.Handoff, error) {
+	s.m.Lock()
+	defer s.m.Unlock()
+
+	var result []*types.Handoff
+	if status == "" || status == types.HandoffOpen {
+		for _, handoff := range s.Data.Open {
+			if handoff.PageID == pageID && workspace.Match(ctx, handoff.WorkspaceID) {
+				result = append(result, handoff)
+			}
+		}
+	}
+	if status == "" || status == types.HandoffResolved {
+		for _, handoff := range s.Data.Resolved {
+			if handoff.PageID == pageID && workspace.Match(ctx, handoff.WorkspaceID) {
+				result = append(result, handoff)
+			}
+		}
+	}
+	sort.SliceStable(result, func(i, j int) bool {
+		return result[i].UpdatedAt > result[j].UpdatedAt
+	})
+	return result, nil
+}
@@ -0,0 +1,141 @@
+package store
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"sync"
+
+	"github.com/olvrng/rbot/be/com/flowexec/types"
+	"github.com/olvrng/rbot/be/com/workspace"
+	"github.com/olvrng/rbot/be/pkg/dot"
+	"github.com/olvrng/rbot/be/pkg/xerrors"
+)
+
+type LinkFile struct {
+	Links []*types.CustomerLink `json:"links"`
+}
+
+type CustomerLinkStore struct {
+	FilePath string
+	Data     *LinkFile
+
+	m sync.Mutex
+}
+
+func NewCustomerLinkStore(filePath string) (*CustomerLinkStore, error) {
+	s := &CustomerLinkStore{
+		FilePath: filePath,
+	}
+
+	_, err := os.Stat(filePath)
+	switch {
+	case err == nil: // load from storage
+		data, err := ioutil.ReadFile(filePath)
+		if err != nil {
+			return nil, err
+		}
+		err = json.Unmarshal(data, &s.Data)
+		return s, err
+
+	case os.IsNotExist(err): // try creating one
+		s.Data = &LinkFile{}
+		err = storeFile(filePath, s.Data)
+		return s, err
+
+	default:
+		return nil, err
+	}
+}
+
+// SaveLink creates or updates the link. An existing link is matched by the
+// customer ref, the user_ref or the PSID, in that order. The PSID of an
+// existing link is never replaced by another one.
+func (s *CustomerLinkStore) SaveLink(ctx context.Context, link *types.CustomerLink) (*types.CustomerLink, error) {
+	s.m.Lock()
+	defer s.m.Unlock()
+
+	now := dot.Now()
+	existing := s.findLink(ctx, link.PageID, func(l *types.CustomerLink) bool {
+		return link.CustomerRef != "" && l.CustomerRef == link.CustomerRef
+	})
+	if existing == nil && link.UserRef != "" {
+		existing = s.findLink(ctx, link.PageID, func(l *types.CustomerLink) bool {
+			return l.UserRef == link.UserRef
+		})
+	}
+	if existing == nil && link.PSID != 0 {
+		existing = s.findLink(ctx, link.PageID, func(l *types.CustomerLink) bool {
+			return l.PSID == link.PSID && (l.CustomerRef == "" || link.CustomerRef == "")
+		})
+	}
+	if existing == nil {
+		link.WorkspaceID = workspace.GetID(ctx)
+		link.CreatedAt, link.UpdatedAt = now, now
+		s.Data.Links = append(s.Data.Links, link)
+		return link, storeFile(s.FilePath, s.Data)
+	}
+
+	if link.PSID != 0 && existing.PSID != 0 && existing.PSID != link.PSID {
+		return nil, xerrors.Errorf(xerrors.AlreadyExists, nil, "the customer is linked to another psid")
+	}
+	if link.CustomerRef != "" {
+		existing.CustomerRef = link.CustomerRef
+	}
+	if link.PSID != 0 {
+		existing.PSID = link.PSID
+	}
+	if link.UserRef != "" {
+		existing.UserRef = link.UserRef
+	}
+	if link.Source != "" {
+		existing.Source = link.Source
+	}
+	existing.UpdatedAt = now
+	return existing, storeFile(s.FilePath, s.Data)
+}
+
+func (s *CustomerLinkStore) GetByCustomerRef(ctx context.Context, pageID dot.IntID, customerRef string) (*types.CustomerLink, error) {
+	s.m.Lock()
+	defer s.m.Unlock()
+
+	return notFoundIfNil(s.findLink(ctx, pageID, func(l *types.CustomerLink) bool {
+		return customerRef != "" && l.CustomerRef == customerRef
+	}))
+}
+
+func (s *CustomerLinkStore) GetByUserRef(ctx context.Context, pageID dot.IntID, userRef string) (*types.CustomerLink, error) {
+	s.m.Lock()
+	defer s.m.Unlock()
+
+	return notFoundIfNil(s.findLink(ctx, pageID, func(l *types.CustomerLink) bool {
+		return userRef != "" && l.UserRef == userRef
+	}))
+}
+
+func (s *CustomerLinkStore) GetByPSID(ctx context.Context, pageID dot.IntID, psid dot.IntID) (*types.CustomerLink, error) {
+	s.m.Lock()
+	defer s.m.Unlock()
+
+	return notFoundIfNil(s.findLink(ctx, pageID, func(l *types.CustomerLink) bool {
+		return psid != 0 && l.PSID == psid
+	}))
+}
+
+// findLink returns the link of the page in the workspace of the context.
+func (s *CustomerLinkStore) findLink(ctx context.Context, pageID dot.IntID, fn func(*types.CustomerLink) bool) *types.CustomerLink {
+	for _, l := range s.Data.Links {
+		if l.PageID == pageID && workspace.Match(ctx, l.WorkspaceID) && fn(l) {
+			return l
+		}
+	}
+	return nil
+}
+
+func notFoundIfNil(link *types.CustomerLink) (*types.CustomerLink, error) {
+	if link == nil {
+		return nil, xerrors.Errorf(xerrors.NotFound, nil, "link not found")
+	}
+	return link, nil
+}
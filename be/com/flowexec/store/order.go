@@ -0,0 +1,144 @@
+package store
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"sync"
+
+	"github.com/olvrng/rbot/be/com/flowexec/types"
+	"github.com/olvrng/rbot/be/com/workspace"
+	"github.com/olvrng/rbot/be/pkg/dot"
+	"github.com/olvrng/rbot/be/pkg/xerrors"
+)
+
+type OrderFile struct {
+	Orders map[string]*types.Order `json:"orders"`
+
+	// Events records the idempotency keys of processed order events.
+	Events map[string]dot.Timestamp `json:"events"`
+}
+
+func NewOrderFile() *OrderFile {
+	return &OrderFile{
+		Orders: make(map[string]*types.Order),
+		Events: make(map[string]dot.Timestamp),
+	}
+}
+
+type OrderStore struct {
+	FilePath string
+	Data     *OrderFile
+
+	m sync.Mutex
+}
+
+func NewOrderStore(filePath string) (*OrderStore, error) {
+	s := &OrderStore{
+		FilePath: filePath,
+	}
+
+	_, err := os.Stat(filePath)
+	switch {
+	case err == nil: // load from storage
+		data, err := ioutil.ReadFile(filePath)
+		if err != nil {
+			return nil, err
+		}
+		if err = json.Unmarshal(data, &s.Data); err != nil {
+			return nil, err
+		}
+		if s.Data.Orders == nil {
+			s.Data.Orders = make(map[string]*types.Order)
+		}
+		if s.Data.Events == nil {
+			s.Data.Events = make(map[string]dot.Timestamp)
+		}
+		return s, nil
+
+	case os.IsNotExist(err): // try creating one
+		s.Data = NewOrderFile()
+		err = storeFile(filePath, s.Data)
+		return s, err
+
+	default:
+		return nil, err
+	}
+}
+
+func (s *OrderStore) SaveOrder(ctx context.Context, order *types.Order) error {
+	s.m.Lock()
+	defer s.m.Unlock()
+
+	key := encodeOrderKey(order.PageID, order.ID)
+	if existing := s.Data.Orders[key]; existing != nil {
+		if err := workspace.CheckOwner(ctx, existing.WorkspaceID); err != nil {
+			return err
+		}
+	}
+	order.WorkspaceID = workspace.GetID(ctx)
+	s.Data.Orders[key] = order
+	return storeFile(s.FilePath, s.Data)
+}
+
+func (s *OrderStore) LoadOrder(ctx context.Context, pageID dot.IntID, orderID string) (*types.Order, error) {
+	s.m.Lock()
+	defer s.m.Unlock()
+
+	order := s.Data.Orders[encodeOrderKey(pageID, orderID)]
+	if order == nil || !workspace.Match(ctx, order.WorkspaceID) {
+		return nil, xerrors.Errorf(xerrors.NotFound, nil, "order not found")
+	}
+	return order, nil
+}
+
+// FindPSIDByCustomerRef looks for a previous order of the customer which is
+// linked to Messenger.
+func (s *OrderStore) FindPSIDByCustomerRef(ctx context.Context, pageID dot.IntID, customerRef string) (dot.IntID, error) {
+	s.m.Lock()
+	defer s.m.Unlock()
+
+	if customerRef != "" {
+		for _, order := range s.Data.Orders {
+			if order.PageID == pageID && order.CustomerRef == customerRef && order.PSID != 0 &&
+				workspace.Match(ctx, order.WorkspaceID) {
+				return order.PSID, nil
+			}
+		}
+	}
+	return 0, xerrors.Errorf(xerrors.NotFound, nil, "customer not found")
+}
+
+// MarkEvent records the idempotency key of an order event. The keys are scoped
+// by the workspace of the context and by the page, so the senders do not
+// suppress the events of each other. It returns true if the key was already
+// recorded.
+func (s *OrderStore) MarkEvent(ctx context.Context, pageID dot.IntID, key string) (duplicated bool, _ error) {
+	s.m.Lock()
+	defer s.m.Unlock()
+
+	key = encodeEventKey(workspace.GetID(ctx), pageID, key)
+	if _, ok := s.Data.Events[key]; ok {
+		return true, nil
+	}
+	s.Data.Events[key] = dot.Now()
+	return false, storeFile(s.FilePath, s.Data)
+}
+
+func (s *OrderStore) UnmarkEvent(ctx context.Context, pageID dot.IntID, key string) {
+	s.m.Lock()
+	defer s.m.Unlock()
+
+	delete(s.Data.Events, encodeEventKey(workspace.GetID(ctx), pageID, key))
+	_ = storeFile(s.FilePath, s.Data)
+}
+
+func encodeOrderKey(pageID dot.IntID, orderID string) string {
+	return fmt.Sprintf("order:%v_%v", pageID, orderID)
+}
+
+func encodeEventKey(workspaceID, pageID dot.IntID, key string) string {
+	return fmt.Sprintf("event:%v_%v_%v", workspaceID, pageID, key)
+}
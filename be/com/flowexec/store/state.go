@@ -6,27 +6,70 @@ import (
 	"fmt"
 	"io/ioutil"
 	"os"
+	"sort"
+	"sync"
 
 	"github.com/olvrng/rbot/be/com/flowexec/flowcore"
+	"github.com/olvrng/rbot/be/com/workspace"
 	"github.com/olvrng/rbot/be/pkg/dot"
 	"github.com/olvrng/rbot/be/pkg/xerrors"
 )
 
+// FlowFile keeps the states of the conversations, one for each flow which the
+// conversation has entered. Active is the flow which handled the latest event
+// of each conversation.
 type FlowFile struct {
 	Last    map[string]*flowcore.FlowState   `json:"last"`
 	History map[string][]*flowcore.FlowState `json:"history"`
+	Active  map[string]dot.IntID             `json:"active"`
 }
 
 func NewFlowFile() *FlowFile {
 	return &FlowFile{
 		Last:    make(map[string]*flowcore.FlowState),
 		History: make(map[string][]*flowcore.FlowState),
+		Active:  make(map[string]dot.IntID),
 	}
 }
 
+// migrate keys the states by flow. The files of the previous versions keep one
+// state for each conversation, which becomes the active one.
+func (f *FlowFile) migrate() {
+	if f.Active == nil {
+		f.Active = make(map[string]dot.IntID)
+	}
+	last := make(map[string]*flowcore.FlowState, len(f.Last))
+	for _, state := range f.Last {
+		last[mockEncodeFlowRunID(state.PageID, state.PSID, state.FlowID)] = state
+		convKey := mockEncodeRunID(state.PageID, state.PSID)
+		if _, ok := f.Active[convKey]; !ok {
+			f.Active[convKey] = state.FlowID
+		}
+	}
+	history := make(map[string][]*flowcore.FlowState, len(f.History))
+	for _, states := range f.History {
+		for _, state := range states {
+			key := mockEncodeFlowRunID(state.PageID, state.PSID, state.FlowID)
+			history[key] = append(history[key], state)
+		}
+	}
+	f.Last, f.History = last, history
+}
+
 type FlowStateStore struct {
 	FilePath string
 	Data     *FlowFile
+
+	// the scheduler runs concurrently with the webhook
+	m     sync.Mutex
+	locks map[string]*conversationLock
+}
+
+// conversationLock serializes the transitions of a conversation. refs counts
+// the holder and the waiters, so that the lock is removed after the last one.
+type conversationLock struct {
+	sync.Mutex
+	refs int
 }
 
 func NewFlowStateStore(filePath string) (*FlowStateStore, error) {
@@ -46,12 +89,7 @@ func NewFlowStateStore(filePath string) (*FlowStateStore, error) {
 		if err != nil {
 			return nil, err
 		}
-		if s.Data.Last == nil {
-			s.Data.Last = make(map[string]*flowcore.FlowState)
-		}
-		if s.Data.History == nil {
-			s.Data.History = make(map[string][]*flowcore.FlowState)
-		}
+		s.Data.migrate()
 		return s, err
 
 	case os.IsNotExist(err): // try creating one
@@ -64,30 +102,123 @@ func NewFlowStateStore(filePath string) (*FlowStateStore, error) {
 	}
 }
 
-func (s *FlowStateStore) SaveState(state *flowcore.FlowState) error {
-	runID := mockEncodeRunID(state.PageID, state.PSID)
+// SaveState saves the state of the conversation in its flow, in the workspace
+// of the context. The flow becomes the active flow of the conversation.
+func (s *FlowStateStore) SaveState(ctx context.Context, state *flowcore.FlowState) error {
+	s.m.Lock()
+	defer s.m.Unlock()
+
 	data := s.Data
+	convKey := mockEncodeRunID(state.PageID, state.PSID)
+	runID := mockEncodeFlowRunID(state.PageID, state.PSID, state.FlowID)
+	for _, key := range []string{runID, mockEncodeFlowRunID(state.PageID, state.PSID, data.Active[convKey])} {
+		if last := data.Last[key]; last != nil {
+			if err := workspace.CheckOwner(ctx, last.WorkspaceID); err != nil {
+				return err
+			}
+		}
+	}
+	state.WorkspaceID = workspace.GetID(ctx)
 	data.Last[runID] = state
 	data.History[runID] = append(data.History[runID], state)
+	data.Active[convKey] = state.FlowID
 
 	return storeFile(s.FilePath, data)
 }
 
-func (s *FlowStateStore) LoadState(ctx context.Context, pageID, psid dot.IntID) (*flowcore.FlowState, error) {
-	data := s.Data
-	runID := mockEncodeRunID(pageID, psid)
-	state := data.Last[runID]
-	if state == nil {
+// LoadState returns the state of the conversation in the flow.
+func (s *FlowStateStore) LoadState(ctx context.Context, pageID, psid, flowID dot.IntID) (*flowcore.FlowState, error) {
+	s.m.Lock()
+	defer s.m.Unlock()
+
+	return s.load(ctx, mockEncodeFlowRunID(pageID, psid, flowID))
+}
+
+// LoadActiveState returns the state of the conversation in the flow which
+// handled its latest event.
+func (s *FlowStateStore) LoadActiveState(ctx context.Context, pageID, psid dot.IntID) (*flowcore.FlowState, error) {
+	s.m.Lock()
+	defer s.m.Unlock()
+
+	flowID, ok := s.Data.Active[mockEncodeRunID(pageID, psid)]
+	if !ok {
+		return nil, xerrors.Errorf(xerrors.NotFound, nil, "not found")
+	}
+	return s.load(ctx, mockEncodeFlowRunID(pageID, psid, flowID))
+}
+
+// ListStates returns the states of the conversation in all its flows, sorted
+// by flow.
+func (s *FlowStateStore) ListStates(ctx context.Context, pageID, psid dot.IntID) ([]*flowcore.FlowState, error) {
+	s.m.Lock()
+	defer s.m.Unlock()
+
+	var result []*flowcore.FlowState
+	for _, state := range s.Data.Last {
+		if state.PageID == pageID && state.PSID == psid && workspace.Match(ctx, state.WorkspaceID) {
+			result = append(result, state)
+		}
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].FlowID < result[j].FlowID
+	})
+	return result, nil
+}
+
+// LockConversation blocks until no other transition of the conversation is
+// running, such as a message and a timer of the same conversation, which would
+// both load the state and save their own next state. It returns the func which
+// unlocks the conversation.
+func (s *FlowStateStore) LockConversation(pageID, psid dot.IntID) (unlock func()) {
+	key := mockEncodeRunID(pageID, psid)
+	s.m.Lock()
+	if s.locks == nil {
+		s.locks = make(map[string]*conversationLock)
+	}
+	lock := s.locks[key]
+	if lock == nil {
+		lock = &conversationLock{}
+		s.locks[key] = lock
+	}
+	lock.refs++
+	s.m.Unlock()
+
+	lock.Lock()
+	return func() {
+		lock.Unlock()
+		s.m.Lock()
+		lock.refs--
+		if lock.refs == 0 {
+			delete(s.locks, key)
+		}
+		s.m.Unlock()
+	}
+}
+
+func (s *FlowStateStore) load(ctx context.Context, runID string) (*flowcore.FlowState, error) {
+	state := s.Data.Last[runID]
+	if state == nil || !workspace.Match(ctx, state.WorkspaceID) {
 		return nil, xerrors.Errorf(xerrors.NotFound, nil, "not found")
 	}
 	return state, nil
 }
 
+// mockEncodeRunID is the key of a conversation.
 func mockEncodeRunID(pageID, psID dot.IntID) string {
 	return fmt.Sprintf("run:%v_%v", pageID, psID)
 }
 
-func storeFile(filePath string, data *FlowFile) error {
+// mockEncodeFlowRunID is the key of a conversation in a flow.
+func mockEncodeFlowRunID(pageID, psID, flowID dot.IntID) string {
+	return fmt.Sprintf("run:%v_%v_%v", pageID, psID, flowID)
+}
+
+// storeFile writes the data to the file. The store is kept in memory only when
+// the file path is empty, for example when simulating flows.
+func storeFile(filePath string, data interface{}) error {
+	if filePath == "" {
+		return nil
+	}
 	out, err := json.MarshalIndent(data, "", "\t")
 	if err != nil {
 		return err
@@ -0,0 +1,138 @@
+package store
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"sort"
+	"sync"
+
+	"github.com/olvrng/rbot/be/com/flowexec/types"
+	"github.com/olvrng/rbot/be/com/workspace"
+	"github.com/olvrng/rbot/be/pkg/dot"
+	"github.com/olvrng/rbot/be/pkg/xerrors"
+)
+
+// TimerFile keeps the pending timer of each conversation in each flow.
+type TimerFile struct {
+	Timers map[string]*types.Timer `json:"timers"`
+}
+
+// migrate keys the timers by flow, like the states.
+func (f *TimerFile) migrate() {
+	timers := make(map[string]*types.Timer, len(f.Timers))
+	for _, timer := range f.Timers {
+		timers[mockEncodeFlowRunID(timer.PageID, timer.PSID, timer.FlowID)] = timer
+	}
+	f.Timers = timers
+}
+
+type TimerStore struct {
+	FilePath string
+	Data     *TimerFile
+
+	m sync.Mutex
+}
+
+func NewTimerStore(filePath string) (*TimerStore, error) {
+	s := &TimerStore{
+		FilePath: filePath,
+	}
+
+	_, err := os.Stat(filePath)
+	switch {
+	case err == nil: // load from storage
+		data, err := ioutil.ReadFile(filePath)
+		if err != nil {
+			return nil, err
+		}
+		if err = json.Unmarshal(data, &s.Data); err != nil {
+			return nil, err
+		}
+		s.Data.migrate()
+		return s, nil
+
+	case os.IsNotExist(err): // try creating one
+		s.Data = &TimerFile{Timers: make(map[string]*types.Timer)}
+		err = storeFile(filePath, s.Data)
+		return s, err
+
+	default:
+		return nil, err
+	}
+}
+
+// SaveTimer replaces the pending timer of the conversation in the flow of the
+// timer.
+func (s *TimerStore) SaveTimer(ctx context.Context, timer *types.Timer) error {
+	s.m.Lock()
+	defer s.m.Unlock()
+
+	key := mockEncodeFlowRunID(timer.PageID, timer.PSID, timer.FlowID)
+	if existing := s.Data.Timers[key]; existing != nil {
+		if err := workspace.CheckOwner(ctx, existing.WorkspaceID); err != nil {
+			return err
+		}
+	}
+	timer.WorkspaceID = workspace.GetID(ctx)
+	s.Data.Timers[key] = timer
+	return storeFile(s.FilePath, s.Data)
+}
+
+func (s *TimerStore) GetTimer(ctx context.Context, pageID, psid, flowID dot.IntID) (*types.Timer, error) {
+	s.m.Lock()
+	defer s.m.Unlock()
+
+	timer := s.Data.Timers[mockEncodeFlowRunID(pageID, psid, flowID)]
+	if timer == nil || !workspace.Match(ctx, timer.WorkspaceID) {
+		return nil, xerrors.Errorf(xerrors.NotFound, nil, "timer not found")
+	}
+	return timer, nil
+}
+
+// CancelTimer removes the pending timer of the conversation in the flow, if
+// any. The timers of the other flows are kept.
+func (s *TimerStore) CancelTimer(ctx context.Context, pageID, psid, flowID dot.IntID) error {
+	s.m.Lock()
+	defer s.m.Unlock()
+
+	key := mockEncodeFlowRunID(pageID, psid, flowID)
+	if t := s.Data.Timers[key]; t == nil || !workspace.Match(ctx, t.WorkspaceID) {
+		return nil
+	}
+	delete(s.Data.Timers, key)
+	return storeFile(s.FilePath, s.Data)
+}
+
+// DeleteTimer removes the timer, unless it has been replaced by another one.
+func (s *TimerStore) DeleteTimer(ctx context.Context, timer *types.Timer) error {
+	s.m.Lock()
+	defer s.m.Unlock()
+
+	key := mockEncodeFlowRunID(timer.PageID, timer.PSID, timer.FlowID)
+	if t := s.Data.Timers[key]; t == nil || t.ID != timer.ID || !workspace.Match(ctx, t.WorkspaceID) {
+		return nil
+	}
+	delete(s.Data.Timers, key)
+	return storeFile(s.FilePath, s.Data)
+}
+
+// ListDueTimers returns the timers which should fire at the given time, the
+// earliest first. It lists the timers of all the workspaces, for the scheduler:
+// each timer must fire in the context of its workspace.
+func (s *TimerStore) ListDueTimers(ctx context.Context, now dot.Timestamp) ([]*types.Timer, error) {
+	s.m.Lock()
+	defer s.m.Unlock()
+
+	var result []*types.Timer
+	for _, timer := range s.Data.Timers {
+		if !timer.FireAt.After(now) {
+			result = append(result, timer)
+		}
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].FireAt < result[j].FireAt
+	})
+	return result, nil
+}
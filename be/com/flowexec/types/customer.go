@@ -0,0 +1,46 @@
+package types
+
+import "github.com/olvrng/rbot/be/pkg/dot"
+
+// CustomerLink ties a customer of the shop to their Messenger identity.
+type CustomerLink struct {
+	WorkspaceID dot.IntID `json:"workspace_id,omitempty"`
+	PageID      dot.IntID `json:"page_id"`
+
+	// CustomerRef is the customer id in the shop system.
+	CustomerRef string `json:"customer_ref,omitempty"`
+
+	// PSID is the page-scoped id of the customer on Messenger.
+	PSID dot.IntID `json:"psid,omitempty"`
+
+	// UserRef is given by the checkbox plugin. It can be used as the recipient
+	// of the first message, before the PSID is known.
+	UserRef string `json:"user_ref,omitempty"`
+
+	// Source tells how the link was made, such as "referral" or "checkbox".
+	Source string `json:"source,omitempty"`
+
+	CreatedAt dot.Timestamp `json:"created_at"`
+	UpdatedAt dot.Timestamp `json:"updated_at"`
+}
+
+type LinkCustomerRequest struct {
+	// +validate:required
+	PageID dot.IntID `json:"page_id"`
+	// +validate:max-len=200
+	CustomerRef string    `json:"customer_ref"`
+	PSID        dot.IntID `json:"psid"`
+	UserRef     string    `json:"user_ref"`
+	Source      string    `json:"source"`
+}
+
+type GetCustomerLinkRequest struct {
+	// +validate:required
+	PageID      dot.IntID `json:"page_id"`
+	CustomerRef string    `json:"customer_ref"`
+	PSID        dot.IntID `json:"psid"`
+}
+
+type CustomerLinkResponse struct {
+	Link *CustomerLink `json:"link"`
+}
@@ -0,0 +1,57 @@
+package types
+
+import "github.com/olvrng/rbot/be/pkg/dot"
+
+type FlowEventType string
+
+const (
+	// EventConversationStarted is published when the conversation starts or
+	// restarts at a trigger, a keyword or the fallback node.
+	EventConversationStarted FlowEventType = "conversation.started"
+
+	// EventNodeEntered is published for each node the conversation arrives at,
+	// including the nodes which do not wait for the user.
+	EventNodeEntered FlowEventType = "node.entered"
+
+	EventMessageSent   FlowEventType = "message.sent"
+	EventMessageFailed FlowEventType = "message.failed"
+
+	EventReviewSubmitted  FlowEventType = "review.submitted"
+	EventHandoffRequested FlowEventType = "handoff.requested"
+
+	// EventFlowCompleted is published when the conversation arrives at a node
+	// without next node.
+	EventFlowCompleted FlowEventType = "flow.completed"
+)
+
+// FlowEventTypes are all the event types, for validating the subscriptions.
+var FlowEventTypes = []FlowEventType{
+	EventConversationStarted,
+	EventNodeEntered,
+	EventMessageSent,
+	EventMessageFailed,
+	EventReviewSubmitted,
+	EventHandoffRequested,
+	EventFlowCompleted,
+}
+
+// FlowEvent tells what happens in a conversation, for the systems outside of
+// rbot. It is published on the EventBus of the services.
+type FlowEvent struct {
+	ID        dot.IntID         `json:"id"`
+	Type      FlowEventType     `json:"type"`
+	PageID    dot.IntID         `json:"page_id"`
+	PSID      dot.IntID         `json:"psid,omitempty"`
+	FlowID    dot.IntID         `json:"flow_id,omitempty"`
+	NodeID    dot.IntID         `json:"node_id,omitempty"`
+	Data      map[string]string `json:"data,omitempty"`
+	CreatedAt dot.Timestamp     `json:"created_at"`
+}
+
+type WatchEventsRequest struct {
+	// PageIDs are the pages to watch, all the pages of the principal by default.
+	PageIDs []dot.IntID `json:"page_ids"`
+
+	// Types are the event types to watch, all the types by default.
+	Types []FlowEventType `json:"types"`
+}
@@ -0,0 +1,101 @@
+package types
+
+import "github.com/olvrng/rbot/be/pkg/dot"
+
+type HandoffStatus string
+
+const (
+	HandoffOpen     HandoffStatus = "open"
+	HandoffResolved HandoffStatus = "resolved"
+)
+
+type MessageSender string
+
+const (
+	SenderCustomer MessageSender = "customer"
+	SenderAgent    MessageSender = "agent"
+	SenderSystem   MessageSender = "system"
+)
+
+// Handoff pauses the bot for a conversation, until an agent resolves it.
+type Handoff struct {
+	ID          dot.IntID `json:"id"`
+	WorkspaceID dot.IntID `json:"workspace_id,omitempty"`
+
+	PageID dot.IntID     `json:"page_id"`
+	PSID   dot.IntID     `json:"psid"`
+	FlowID dot.IntID     `json:"flow_id"`
+	NodeID dot.IntID     `json:"node_id,omitempty"` // the handoff node, if any
+	Status HandoffStatus `json:"status"`
+	Reason string        `json:"reason,omitempty"`
+
+	// ThreadPassed tells that the thread control has been passed to another
+	// app, and must be taken back when resolved.
+	ThreadPassed bool `json:"thread_passed,omitempty"`
+
+	Messages []*HandoffMessage `json:"messages"`
+
+	CreatedAt  dot.Timestamp `json:"created_at"`
+	UpdatedAt  dot.Timestamp `json:"updated_at"`
+	ResolvedAt dot.Timestamp `json:"resolved_at,omitempty"`
+	ResolvedBy string        `json:"resolved_by,omitempty"`
+}
+
+type HandoffMessage struct {
+	From      MessageSender `json:"from"`
+	Agent     string        `json:"agent,omitempty"`
+	Text      string        `json:"text"`
+	CreatedAt dot.Timestamp `json:"created_at"`
+}
+
+type StartHandoffRequest struct {
+	// +validate:required
+	PageID dot.IntID `json:"page_id"`
+	// +validate:required
+	PSID dot.IntID `json:"psid"`
+	// +validate:max-len=500
+	Reason string `json:"reason"`
+}
+
+type ListHandoffsRequest struct {
+	// +validate:required
+	PageID dot.IntID `json:"page_id"`
+	// +validate:enum
+	Status HandoffStatus `json:"status"`
+}
+
+type ListHandoffsResponse struct {
+	Handoffs []*Handoff `json:"handoffs"`
+}
+
+type GetHandoffRequest struct {
+	// +validate:required
+	PageID dot.IntID `json:"page_id"`
+	// +validate:required
+	PSID dot.IntID `json:"psid"`
+}
+
+type ReplyHandoffRequest struct {
+	// +validate:required
+	PageID dot.IntID `json:"page_id"`
+	// +validate:required
+	PSID  dot.IntID `json:"psid"`
+	Agent string    `json:"agent"`
+	// +validate:required +validate:max-len=2000
+	Text string `json:"text"`
+}
+
+// ResolveHandoffRequest resumes the bot. The flow continues at ResumeNodeID,
+// or at the next node of the handoff node when it is not set.
+type ResolveHandoffRequest struct {
+	// +validate:required
+	PageID dot.IntID `json:"page_id"`
+	// +validate:required
+	PSID         dot.IntID `json:"psid"`
+	Agent        string    `json:"agent"`
+	ResumeNodeID dot.IntID `json:"resume_node_id"`
+}
+
+type HandoffResponse struct {
+	Handoff *Handoff `json:"handoff"`
+}
@@ -20,3 +20,20 @@ type ReceivedPostbackRequest struct {
 
 type ReceivedPostbackResponse struct {
 }
+
+// ReceivedReferralRequest is sent when the customer opens the conversation from
+// an m.me link with a ref param, either as a messaging_referrals event or with
+// the Get Started postback.
+type ReceivedReferralRequest struct {
+	PageID fbmsg.IntID `json:"page_id"`
+	PSID   fbmsg.IntID `json:"psid"`
+
+	// Ref is an order id ("order:<id>") or a customer id ("customer:<id>" or
+	// just "<id>").
+	Ref    string `json:"ref"`
+	Source string `json:"source"`
+	Type   string `json:"type"`
+}
+
+type ReceivedReferralResponse struct {
+}
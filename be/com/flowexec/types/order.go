@@ -1,17 +1,164 @@
 package types
 
-import "github.com/olvrng/rbot/be/pkg/dot"
+import (
+	"fmt"
+	"strings"
 
+	flowdeftypes "github.com/olvrng/rbot/be/com/flowdef/types"
+	"github.com/olvrng/rbot/be/pkg/dot"
+)
+
+type OrderStatus string
+
+const (
+	OrderCreated   OrderStatus = "created"
+	OrderPaid      OrderStatus = "paid"
+	OrderShipped   OrderStatus = "shipped"
+	OrderCompleted OrderStatus = "completed"
+	OrderRefunded  OrderStatus = "refunded"
+)
+
+// TriggerType returns the trigger node type which is fired when an order
+// changes to the status.
+func (s OrderStatus) TriggerType() flowdeftypes.NodeType {
+	switch s {
+	case OrderCreated:
+		return flowdeftypes.NodeOrderCreated
+	case OrderPaid:
+		return flowdeftypes.NodeOrderPaid
+	case OrderShipped:
+		return flowdeftypes.NodeOrderShipped
+	case OrderCompleted:
+		return flowdeftypes.NodeCompletedOrder
+	case OrderRefunded:
+		return flowdeftypes.NodeOrderRefunded
+	default:
+		return ""
+	}
+}
+
+type Order struct {
+	ID          string    `json:"id"`
+	WorkspaceID dot.IntID `json:"workspace_id,omitempty"`
+	PageID      dot.IntID `json:"page_id"`
+
+	// CustomerRef is the customer id in the shop system.
+	CustomerRef string `json:"customer_ref,omitempty"`
+
+	// PSID is the page-scoped id of the customer on Messenger, when known.
+	PSID dot.IntID `json:"psid,omitempty"`
+
+	// UserRef is the user_ref from the checkbox plugin at checkout.
+	UserRef string `json:"user_ref,omitempty"`
+
+	Status OrderStatus `json:"status"`
+
+	// Currency is the ISO 4217 currency code, for example "VND" or "USD".
+	Currency string `json:"currency"`
+
+	// Amount is the total amount in the smallest unit of the currency.
+	Amount int64 `json:"amount"`
+
+	Items []*OrderItem `json:"items"`
+	Desc  string       `json:"desc,omitempty"`
+
+	CreatedAt dot.Timestamp `json:"created_at"`
+	UpdatedAt dot.Timestamp `json:"updated_at"`
+}
+
+type OrderItem struct {
+	SKU      string `json:"sku,omitempty"`
+	Name     string `json:"name"`
+	Quantity int    `json:"quantity"`
+
+	// Price is the unit price in the smallest unit of the currency.
+	Price int64 `json:"price"`
+}
+
+// EventData returns the data which is available to the flow when the order
+// event is received.
+func (o *Order) EventData() map[string]string {
+	items := make([]string, len(o.Items))
+	for i, item := range o.Items {
+		items[i] = fmt.Sprintf("%vx %v", item.Quantity, item.Name)
+	}
+	return map[string]string{
+		"order_id":       o.ID,
+		"order_status":   string(o.Status),
+		"order_amount":   fmt.Sprint(o.Amount),
+		"order_currency": o.Currency,
+		"order_items":    strings.Join(items, ", "),
+		"customer_ref":   o.CustomerRef,
+		"desc":           o.Desc,
+		"amount":         fmt.Sprint(o.Amount),
+	}
+}
+
+type ReceivedOrderEventRequest struct {
+	// +validate:required
+	PageID dot.IntID `json:"page_id"`
+
+	// IdempotencyKey prevents processing the same event of the page twice. It
+	// defaults to the combination of order_id and status.
+	// +validate:max-len=200
+	IdempotencyKey string `json:"idempotency_key"`
+
+	// +validate:required +validate:max-len=200
+	OrderID     string    `json:"order_id"`
+	CustomerRef string    `json:"customer_ref"`
+	PSID        dot.IntID `json:"psid"`
+	UserRef     string    `json:"user_ref"`
+	// +validate:required +validate:enum
+	Status OrderStatus `json:"status"`
+	// +validate:regex: ^[A-Z]{3}$
+	Currency string `json:"currency"`
+	// +validate:min=0
+	Amount int64        `json:"amount"`
+	Items  []*OrderItem `json:"items"`
+	Desc   string       `json:"desc"`
+}
+
+type ReceivedOrderEventResponse struct {
+	Order *Order `json:"order"`
+
+	// Duplicated is true when the event was already processed.
+	Duplicated bool `json:"duplicated"`
+
+	// Ref is the ref param of the m.me links to the order, which links the
+	// customer who opens the link to the order. It is empty when the server has
+	// no ref secret.
+	Ref string `json:"ref,omitempty"`
+}
+
+// ReceivedCompletedOrderRequest is kept for compatibility. New integrations
+// should use ReceivedOrderEventRequest.
 type ReceivedCompletedOrderRequest struct {
+	// +validate:required
 	PageID dot.IntID `json:"page_id"`
 
-	// mock: psid_orderid
-	OrderID string `json:"order_id"`
+	// +validate:required +validate:max-len=200
+	OrderID     string    `json:"order_id"`
+	CustomerRef string    `json:"customer_ref"`
+	PSID        dot.IntID `json:"psid"`
 
-	Desc string `json:"string"`
+	Desc string `json:"desc"`
 
-	Amount int `json:"amount"`
+	// +validate:regex: ^[A-Z]{3}$
+	Currency string `json:"currency"`
+	// +validate:min=0
+	Amount int64 `json:"amount"`
 }
 
 type ReceivedCompletedOrderResponse struct {
 }
+
+type GetOrderRequest struct {
+	// +validate:required
+	PageID dot.IntID `json:"page_id"`
+	// +validate:required
+	OrderID string `json:"order_id"`
+}
+
+type OrderResponse struct {
+	Order *Order `json:"order"`
+}
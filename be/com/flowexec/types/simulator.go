@@ -0,0 +1,81 @@
+package types
+
+import (
+	flowdeftypes "github.com/olvrng/rbot/be/com/flowdef/types"
+	"github.com/olvrng/rbot/be/pkg/dot"
+)
+
+type SimulationEvent string
+
+const (
+	SimulateMessage  SimulationEvent = "message"
+	SimulatePostback SimulationEvent = "postback"
+	SimulateReferral SimulationEvent = "referral"
+	SimulateOrder    SimulationEvent = "order"
+
+	// SimulateWait moves the clock forward by Delay and fires the due timers.
+	SimulateWait SimulationEvent = "wait"
+)
+
+// SimulationStep is an event of the virtual conversation.
+type SimulationStep struct {
+	Event SimulationEvent `json:"event"`
+
+	// Text is the message text, or the title of the postback.
+	Text string `json:"text,omitempty"`
+
+	// Payload is the postback code, or the ref of the referral.
+	Payload string `json:"payload,omitempty"`
+
+	OrderID     string      `json:"order_id,omitempty"`
+	OrderStatus OrderStatus `json:"order_status,omitempty"` // default to completed
+
+	Delay flowdeftypes.Duration `json:"delay,omitempty"`
+}
+
+// SimulateFlowRequest runs the flow against a virtual conversation. Flow is
+// used instead of the stored flow FlowID when it is set, so the board can
+// preview unsaved changes. Nothing is sent to Messenger or stored.
+type SimulateFlowRequest struct {
+	FlowID dot.IntID          `json:"flow_id"`
+	Flow   *flowdeftypes.Flow `json:"flow"`
+	// +validate:required +validate:max-len=100
+	Steps []*SimulationStep `json:"steps"`
+}
+
+type SimulateFlowResponse struct {
+	Steps []*SimulationStepResult `json:"steps"`
+}
+
+type SimulationStepResult struct {
+	Step *SimulationStep `json:"step"`
+
+	FromNodeID dot.IntID `json:"from_node_id"`
+	ToNodeID   dot.IntID `json:"to_node_id"`
+
+	// ToFlowID is the flow of ToNodeID, which differs from the simulated flow
+	// after a goto_flow node.
+	ToFlowID dot.IntID `json:"to_flow_id,omitempty"`
+
+	// Messages are the messages which would be sent to the customer.
+	Messages []*SimulatedMessage `json:"messages"`
+
+	Vars   map[string]string `json:"vars"`
+	Paused bool              `json:"paused,omitempty"` // the bot is handed off
+	Error  string            `json:"error,omitempty"`
+}
+
+type SimulatedMessage struct {
+	Text    string             `json:"text,omitempty"`
+	Buttons []*SimulatedButton `json:"buttons,omitempty"`
+
+	// Action is the Messenger API which is called instead of sending a
+	// message, such as "pass_thread_control".
+	Action string `json:"action,omitempty"`
+}
+
+// SimulatedButton is a quick reply or a postback button.
+type SimulatedButton struct {
+	Title   string `json:"title"`
+	Payload string `json:"payload"`
+}
@@ -0,0 +1,18 @@
+package types
+
+import "github.com/olvrng/rbot/be/pkg/dot"
+
+// Timer fires a trigger:timer transition of a conversation at FireAt. There is
+// at most one pending timer per conversation: it belongs to the node where the
+// conversation currently is.
+type Timer struct {
+	ID          dot.IntID `json:"id"`
+	WorkspaceID dot.IntID `json:"workspace_id,omitempty"`
+	PageID      dot.IntID `json:"page_id"`
+	PSID        dot.IntID `json:"psid"`
+	FlowID      dot.IntID `json:"flow_id"`
+	NodeID      dot.IntID `json:"node_id"`
+
+	FireAt    dot.Timestamp `json:"fire_at"`
+	CreatedAt dot.Timestamp `json:"created_at"`
+}
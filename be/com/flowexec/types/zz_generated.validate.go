@@ -0,0 +1,168 @@
+// +build !generator
+
+// Code generated by generator validate. DO NOT EDIT.
+
+package types
+
+import (
+	regexp "regexp"
+	utf8 "unicode/utf8"
+
+	validate "github.com/olvrng/rbot/be/pkg/validate"
+)
+
+var validateRegexp0 = regexp.MustCompile(`^[A-Z]{3}$`)
+
+func (m *GetCustomerLinkRequest) Validate() error {
+	errs := &validate.Errors{}
+	if m.PageID == 0 {
+		errs.Add("page_id", "is required")
+	}
+	return errs.Err()
+}
+
+func (m *GetHandoffRequest) Validate() error {
+	errs := &validate.Errors{}
+	if m.PageID == 0 {
+		errs.Add("page_id", "is required")
+	}
+	if m.PSID == 0 {
+		errs.Add("psid", "is required")
+	}
+	return errs.Err()
+}
+
+func (m *GetOrderRequest) Validate() error {
+	errs := &validate.Errors{}
+	if m.PageID == 0 {
+		errs.Add("page_id", "is required")
+	}
+	if m.OrderID == "" {
+		errs.Add("order_id", "is required")
+	}
+	return errs.Err()
+}
+
+func (m *LinkCustomerRequest) Validate() error {
+	errs := &validate.Errors{}
+	if m.PageID == 0 {
+		errs.Add("page_id", "is required")
+	}
+	if utf8.RuneCountInString(m.CustomerRef) > 200 {
+		errs.Add("customer_ref", "must have at most 200 characters")
+	}
+	return errs.Err()
+}
+
+func (m *ListHandoffsRequest) Validate() error {
+	errs := &validate.Errors{}
+	if m.PageID == 0 {
+		errs.Add("page_id", "is required")
+	}
+	if m.Status != "" && m.Status != "open" && m.Status != "resolved" {
+		errs.Add("status", "must be one of open, resolved")
+	}
+	return errs.Err()
+}
+
+func (m *ReceivedCompletedOrderRequest) Validate() error {
+	errs := &validate.Errors{}
+	if m.PageID == 0 {
+		errs.Add("page_id", "is required")
+	}
+	if m.OrderID == "" {
+		errs.Add("order_id", "is required")
+	}
+	if utf8.RuneCountInString(m.OrderID) > 200 {
+		errs.Add("order_id", "must have at most 200 characters")
+	}
+	if m.Currency != "" && !validateRegexp0.MatchString(m.Currency) {
+		errs.Add("currency", "must match ^[A-Z]{3}$")
+	}
+	if m.Amount != 0 && m.Amount < 0 {
+		errs.Add("amount", "must be at least 0")
+	}
+	return errs.Err()
+}
+
+func (m *ReceivedOrderEventRequest) Validate() error {
+	errs := &validate.Errors{}
+	if m.PageID == 0 {
+		errs.Add("page_id", "is required")
+	}
+	if utf8.RuneCountInString(m.IdempotencyKey) > 200 {
+		errs.Add("idempotency_key", "must have at most 200 characters")
+	}
+	if m.OrderID == "" {
+		errs.Add("order_id", "is required")
+	}
+	if utf8.RuneCountInString(m.OrderID) > 200 {
+		errs.Add("order_id", "must have at most 200 characters")
+	}
+	if m.Status == "" {
+		errs.Add("status", "is required")
+	}
+	if m.Status != "" && m.Status != "completed" && m.Status != "created" && m.Status != "paid" && m.Status != "refunded" && m.Status != "shipped" {
+		errs.Add("status", "must be one of completed, created, paid, refunded, shipped")
+	}
+	if m.Currency != "" && !validateRegexp0.MatchString(m.Currency) {
+		errs.Add("currency", "must match ^[A-Z]{3}$")
+	}
+	if m.Amount != 0 && m.Amount < 0 {
+		errs.Add("amount", "must be at least 0")
+	}
+	return errs.Err()
+}
+
+func (m *ReplyHandoffRequest) Validate() error {
+	errs := &validate.Errors{}
+	if m.PageID == 0 {
+		errs.Add("page_id", "is required")
+	}
+	if m.PSID == 0 {
+		errs.Add("psid", "is required")
+	}
+	if m.Text == "" {
+		errs.Add("text", "is required")
+	}
+	if utf8.RuneCountInString(m.Text) > 2000 {
+		errs.Add("text", "must have at most 2000 characters")
+	}
+	return errs.Err()
+}
+
+func (m *ResolveHandoffRequest) Validate() error {
+	errs := &validate.Errors{}
+	if m.PageID == 0 {
+		errs.Add("page_id", "is required")
+	}
+	if m.PSID == 0 {
+		errs.Add("psid", "is required")
+	}
+	return errs.Err()
+}
+
+func (m *SimulateFlowRequest) Validate() error {
+	errs := &validate.Errors{}
+	if len(m.Steps) == 0 {
+		errs.Add("steps", "is required")
+	}
+	if len(m.Steps) > 100 {
+		errs.Add("steps", "must have at most 100 items")
+	}
+	return errs.Err()
+}
+
+func (m *StartHandoffRequest) Validate() error {
+	errs := &validate.Errors{}
+	if m.PageID == 0 {
+		errs.Add("page_id", "is required")
+	}
+	if m.PSID == 0 {
+		errs.Add("psid", "is required")
+	}
+	if utf8.RuneCountInString(m.Reason) > 500 {
+		errs.Add("reason", "must have at most 500 characters")
+	}
+	return errs.Err()
+}
@@ -15,10 +15,255 @@ import (
 
 func init() {
 	httprpc.Register(NewServer)
+	httprpc.RegisterSchemas(
+		&httprpc.Schema{Name: "github.com/olvrng/rbot/be/com/flowdef/types.Flow", Fields: []httprpc.Field{
+			{Name: "id", Number: 1, Kind: httprpc.KindInt},
+			{Name: "workspace_id", Number: 2, Kind: httprpc.KindInt},
+			{Name: "page_ids", Number: 3, Kind: httprpc.KindInt, Repeated: true},
+			{Name: "nodes", Number: 4, Kind: httprpc.KindMessage, Repeated: true, Message: "github.com/olvrng/rbot/be/com/flowdef/types.Node"},
+			{Name: "priority", Number: 5, Kind: httprpc.KindInt},
+			{Name: "entry_points", Number: 6, Kind: httprpc.KindInt, Map: true},
+			{Name: "keywords", Number: 7, Kind: httprpc.KindMessage, Repeated: true, Message: "github.com/olvrng/rbot/be/com/flowdef/types.KeywordEntry"},
+			{Name: "fallback_node_id", Number: 8, Kind: httprpc.KindInt},
+			{Name: "restart_policy", Number: 9, Kind: httprpc.KindString},
+			{Name: "intents", Number: 10, Kind: httprpc.KindMessage, Repeated: true, Message: "github.com/olvrng/rbot/be/com/flowdef/types.Intent"},
+		}},
+		&httprpc.Schema{Name: "github.com/olvrng/rbot/be/com/flowdef/types.Intent", Fields: []httprpc.Field{
+			{Name: "name", Number: 1, Kind: httprpc.KindString},
+			{Name: "examples", Number: 2, Kind: httprpc.KindString, Repeated: true},
+		}},
+		&httprpc.Schema{Name: "github.com/olvrng/rbot/be/com/flowdef/types.KeywordEntry", Fields: []httprpc.Field{
+			{Name: "keywords", Number: 1, Kind: httprpc.KindString, Repeated: true},
+			{Name: "next_id", Number: 2, Kind: httprpc.KindInt},
+		}},
+		&httprpc.Schema{Name: "github.com/olvrng/rbot/be/com/flowdef/types.Node", Fields: []httprpc.Field{
+			{Name: "id", Number: 1, Kind: httprpc.KindInt},
+			{Name: "payload", Number: 2, Kind: httprpc.KindJSON},
+		}},
+		&httprpc.Schema{Name: "github.com/olvrng/rbot/be/com/flowexec/types.CustomerLink", Fields: []httprpc.Field{
+			{Name: "workspace_id", Number: 1, Kind: httprpc.KindInt},
+			{Name: "page_id", Number: 2, Kind: httprpc.KindInt},
+			{Name: "customer_ref", Number: 3, Kind: httprpc.KindString},
+			{Name: "psid", Number: 4, Kind: httprpc.KindInt},
+			{Name: "user_ref", Number: 5, Kind: httprpc.KindString},
+			{Name: "source", Number: 6, Kind: httprpc.KindString},
+			{Name: "created_at", Number: 7, Kind: httprpc.KindInt},
+			{Name: "updated_at", Number: 8, Kind: httprpc.KindInt},
+		}},
+		&httprpc.Schema{Name: "github.com/olvrng/rbot/be/com/flowexec/types.CustomerLinkResponse", Fields: []httprpc.Field{
+			{Name: "link", Number: 1, Kind: httprpc.KindMessage, Message: "github.com/olvrng/rbot/be/com/flowexec/types.CustomerLink"},
+		}},
+		&httprpc.Schema{Name: "github.com/olvrng/rbot/be/com/flowexec/types.FlowEvent", Fields: []httprpc.Field{
+			{Name: "id", Number: 1, Kind: httprpc.KindInt},
+			{Name: "type", Number: 2, Kind: httprpc.KindString},
+			{Name: "page_id", Number: 3, Kind: httprpc.KindInt},
+			{Name: "psid", Number: 4, Kind: httprpc.KindInt},
+			{Name: "flow_id", Number: 5, Kind: httprpc.KindInt},
+			{Name: "node_id", Number: 6, Kind: httprpc.KindInt},
+			{Name: "data", Number: 7, Kind: httprpc.KindString, Map: true},
+			{Name: "created_at", Number: 8, Kind: httprpc.KindInt},
+		}},
+		&httprpc.Schema{Name: "github.com/olvrng/rbot/be/com/flowexec/types.GetCustomerLinkRequest", Fields: []httprpc.Field{
+			{Name: "page_id", Number: 1, Kind: httprpc.KindInt},
+			{Name: "customer_ref", Number: 2, Kind: httprpc.KindString},
+			{Name: "psid", Number: 3, Kind: httprpc.KindInt},
+		}},
+		&httprpc.Schema{Name: "github.com/olvrng/rbot/be/com/flowexec/types.GetHandoffRequest", Fields: []httprpc.Field{
+			{Name: "page_id", Number: 1, Kind: httprpc.KindInt},
+			{Name: "psid", Number: 2, Kind: httprpc.KindInt},
+		}},
+		&httprpc.Schema{Name: "github.com/olvrng/rbot/be/com/flowexec/types.GetOrderRequest", Fields: []httprpc.Field{
+			{Name: "page_id", Number: 1, Kind: httprpc.KindInt},
+			{Name: "order_id", Number: 2, Kind: httprpc.KindString},
+		}},
+		&httprpc.Schema{Name: "github.com/olvrng/rbot/be/com/flowexec/types.Handoff", Fields: []httprpc.Field{
+			{Name: "id", Number: 1, Kind: httprpc.KindInt},
+			{Name: "workspace_id", Number: 2, Kind: httprpc.KindInt},
+			{Name: "page_id", Number: 3, Kind: httprpc.KindInt},
+			{Name: "psid", Number: 4, Kind: httprpc.KindInt},
+			{Name: "flow_id", Number: 5, Kind: httprpc.KindInt},
+			{Name: "node_id", Number: 6, Kind: httprpc.KindInt},
+			{Name: "status", Number: 7, Kind: httprpc.KindString},
+			{Name: "reason", Number: 8, Kind: httprpc.KindString},
+			{Name: "thread_passed", Number: 9, Kind: httprpc.KindBool},
+			{Name: "messages", Number: 10, Kind: httprpc.KindMessage, Repeated: true, Message: "github.com/olvrng/rbot/be/com/flowexec/types.HandoffMessage"},
+			{Name: "created_at", Number: 11, Kind: httprpc.KindInt},
+			{Name: "updated_at", Number: 12, Kind: httprpc.KindInt},
+			{Name: "resolved_at", Number: 13, Kind: httprpc.KindInt},
+			{Name: "resolved_by", Number: 14, Kind: httprpc.KindString},
+		}},
+		&httprpc.Schema{Name: "github.com/olvrng/rbot/be/com/flowexec/types.HandoffMessage", Fields: []httprpc.Field{
+			{Name: "from", Number: 1, Kind: httprpc.KindString},
+			{Name: "agent", Number: 2, Kind: httprpc.KindString},
+			{Name: "text", Number: 3, Kind: httprpc.KindString},
+			{Name: "created_at", Number: 4, Kind: httprpc.KindInt},
+		}},
+		&httprpc.Schema{Name: "github.com/olvrng/rbot/be/com/flowexec/types.HandoffResponse", Fields: []httprpc.Field{
+			{Name: "handoff", Number: 1, Kind: httprpc.KindMessage, Message: "github.com/olvrng/rbot/be/com/flowexec/types.Handoff"},
+		}},
+		&httprpc.Schema{Name: "github.com/olvrng/rbot/be/com/flowexec/types.LinkCustomerRequest", Fields: []httprpc.Field{
+			{Name: "page_id", Number: 1, Kind: httprpc.KindInt},
+			{Name: "customer_ref", Number: 2, Kind: httprpc.KindString},
+			{Name: "psid", Number: 3, Kind: httprpc.KindInt},
+			{Name: "user_ref", Number: 4, Kind: httprpc.KindString},
+			{Name: "source", Number: 5, Kind: httprpc.KindString},
+		}},
+		&httprpc.Schema{Name: "github.com/olvrng/rbot/be/com/flowexec/types.ListHandoffsRequest", Fields: []httprpc.Field{
+			{Name: "page_id", Number: 1, Kind: httprpc.KindInt},
+			{Name: "status", Number: 2, Kind: httprpc.KindString},
+		}},
+		&httprpc.Schema{Name: "github.com/olvrng/rbot/be/com/flowexec/types.ListHandoffsResponse", Fields: []httprpc.Field{
+			{Name: "handoffs", Number: 1, Kind: httprpc.KindMessage, Repeated: true, Message: "github.com/olvrng/rbot/be/com/flowexec/types.Handoff"},
+		}},
+		&httprpc.Schema{Name: "github.com/olvrng/rbot/be/com/flowexec/types.Order", Fields: []httprpc.Field{
+			{Name: "id", Number: 1, Kind: httprpc.KindString},
+			{Name: "workspace_id", Number: 2, Kind: httprpc.KindInt},
+			{Name: "page_id", Number: 3, Kind: httprpc.KindInt},
+			{Name: "customer_ref", Number: 4, Kind: httprpc.KindString},
+			{Name: "psid", Number: 5, Kind: httprpc.KindInt},
+			{Name: "user_ref", Number: 6, Kind: httprpc.KindString},
+			{Name: "status", Number: 7, Kind: httprpc.KindString},
+			{Name: "currency", Number: 8, Kind: httprpc.KindString},
+			{Name: "amount", Number: 9, Kind: httprpc.KindInt},
+			{Name: "items", Number: 10, Kind: httprpc.KindMessage, Repeated: true, Message: "github.com/olvrng/rbot/be/com/flowexec/types.OrderItem"},
+			{Name: "desc", Number: 11, Kind: httprpc.KindString},
+			{Name: "created_at", Number: 12, Kind: httprpc.KindInt},
+			{Name: "updated_at", Number: 13, Kind: httprpc.KindInt},
+		}},
+		&httprpc.Schema{Name: "github.com/olvrng/rbot/be/com/flowexec/types.OrderItem", Fields: []httprpc.Field{
+			{Name: "sku", Number: 1, Kind: httprpc.KindString},
+			{Name: "name", Number: 2, Kind: httprpc.KindString},
+			{Name: "quantity", Number: 3, Kind: httprpc.KindInt},
+			{Name: "price", Number: 4, Kind: httprpc.KindInt},
+		}},
+		&httprpc.Schema{Name: "github.com/olvrng/rbot/be/com/flowexec/types.OrderResponse", Fields: []httprpc.Field{
+			{Name: "order", Number: 1, Kind: httprpc.KindMessage, Message: "github.com/olvrng/rbot/be/com/flowexec/types.Order"},
+		}},
+		&httprpc.Schema{Name: "github.com/olvrng/rbot/be/com/flowexec/types.ReceivedCompletedOrderRequest", Fields: []httprpc.Field{
+			{Name: "page_id", Number: 1, Kind: httprpc.KindInt},
+			{Name: "order_id", Number: 2, Kind: httprpc.KindString},
+			{Name: "customer_ref", Number: 3, Kind: httprpc.KindString},
+			{Name: "psid", Number: 4, Kind: httprpc.KindInt},
+			{Name: "desc", Number: 5, Kind: httprpc.KindString},
+			{Name: "currency", Number: 6, Kind: httprpc.KindString},
+			{Name: "amount", Number: 7, Kind: httprpc.KindInt},
+		}},
+		&httprpc.Schema{Name: "github.com/olvrng/rbot/be/com/flowexec/types.ReceivedCompletedOrderResponse", Fields: []httprpc.Field{}},
+		&httprpc.Schema{Name: "github.com/olvrng/rbot/be/com/flowexec/types.ReceivedMessageRequest", Fields: []httprpc.Field{
+			{Name: "page_id", Number: 1, Kind: httprpc.KindInt},
+			{Name: "psid", Number: 2, Kind: httprpc.KindInt},
+			{Name: "message", Number: 3, Kind: httprpc.KindString},
+		}},
+		&httprpc.Schema{Name: "github.com/olvrng/rbot/be/com/flowexec/types.ReceivedMessageResponse", Fields: []httprpc.Field{}},
+		&httprpc.Schema{Name: "github.com/olvrng/rbot/be/com/flowexec/types.ReceivedOrderEventRequest", Fields: []httprpc.Field{
+			{Name: "page_id", Number: 1, Kind: httprpc.KindInt},
+			{Name: "idempotency_key", Number: 2, Kind: httprpc.KindString},
+			{Name: "order_id", Number: 3, Kind: httprpc.KindString},
+			{Name: "customer_ref", Number: 4, Kind: httprpc.KindString},
+			{Name: "psid", Number: 5, Kind: httprpc.KindInt},
+			{Name: "user_ref", Number: 6, Kind: httprpc.KindString},
+			{Name: "status", Number: 7, Kind: httprpc.KindString},
+			{Name: "currency", Number: 8, Kind: httprpc.KindString},
+			{Name: "amount", Number: 9, Kind: httprpc.KindInt},
+			{Name: "items", Number: 10, Kind: httprpc.KindMessage, Repeated: true, Message: "github.com/olvrng/rbot/be/com/flowexec/types.OrderItem"},
+			{Name: "desc", Number: 11, Kind: httprpc.KindString},
+		}},
+		&httprpc.Schema{Name: "github.com/olvrng/rbot/be/com/flowexec/types.ReceivedOrderEventResponse", Fields: []httprpc.Field{
+			{Name: "order", Number: 1, Kind: httprpc.KindMessage, Message: "github.com/olvrng/rbot/be/com/flowexec/types.Order"},
+			{Name: "duplicated", Number: 2, Kind: httprpc.KindBool},
+			{Name: "ref", Number: 3, Kind: httprpc.KindString},
+		}},
+		&httprpc.Schema{Name: "github.com/olvrng/rbot/be/com/flowexec/types.ReceivedPostbackRequest", Fields: []httprpc.Field{
+			{Name: "page_id", Number: 1, Kind: httprpc.KindInt},
+			{Name: "psid", Number: 2, Kind: httprpc.KindInt},
+			{Name: "postback_title", Number: 3, Kind: httprpc.KindString},
+			{Name: "postback_payload", Number: 4, Kind: httprpc.KindString},
+		}},
+		&httprpc.Schema{Name: "github.com/olvrng/rbot/be/com/flowexec/types.ReceivedPostbackResponse", Fields: []httprpc.Field{}},
+		&httprpc.Schema{Name: "github.com/olvrng/rbot/be/com/flowexec/types.ReceivedReferralRequest", Fields: []httprpc.Field{
+			{Name: "page_id", Number: 1, Kind: httprpc.KindInt},
+			{Name: "psid", Number: 2, Kind: httprpc.KindInt},
+			{Name: "ref", Number: 3, Kind: httprpc.KindString},
+			{Name: "source", Number: 4, Kind: httprpc.KindString},
+			{Name: "type", Number: 5, Kind: httprpc.KindString},
+		}},
+		&httprpc.Schema{Name: "github.com/olvrng/rbot/be/com/flowexec/types.ReceivedReferralResponse", Fields: []httprpc.Field{}},
+		&httprpc.Schema{Name: "github.com/olvrng/rbot/be/com/flowexec/types.ReplyHandoffRequest", Fields: []httprpc.Field{
+			{Name: "page_id", Number: 1, Kind: httprpc.KindInt},
+			{Name: "psid", Number: 2, Kind: httprpc.KindInt},
+			{Name: "agent", Number: 3, Kind: httprpc.KindString},
+			{Name: "text", Number: 4, Kind: httprpc.KindString},
+		}},
+		&httprpc.Schema{Name: "github.com/olvrng/rbot/be/com/flowexec/types.ResolveHandoffRequest", Fields: []httprpc.Field{
+			{Name: "page_id", Number: 1, Kind: httprpc.KindInt},
+			{Name: "psid", Number: 2, Kind: httprpc.KindInt},
+			{Name: "agent", Number: 3, Kind: httprpc.KindString},
+			{Name: "resume_node_id", Number: 4, Kind: httprpc.KindInt},
+		}},
+		&httprpc.Schema{Name: "github.com/olvrng/rbot/be/com/flowexec/types.SimulateFlowRequest", Fields: []httprpc.Field{
+			{Name: "flow_id", Number: 1, Kind: httprpc.KindInt},
+			{Name: "flow", Number: 2, Kind: httprpc.KindMessage, Message: "github.com/olvrng/rbot/be/com/flowdef/types.Flow"},
+			{Name: "steps", Number: 3, Kind: httprpc.KindMessage, Repeated: true, Message: "github.com/olvrng/rbot/be/com/flowexec/types.SimulationStep"},
+		}},
+		&httprpc.Schema{Name: "github.com/olvrng/rbot/be/com/flowexec/types.SimulateFlowResponse", Fields: []httprpc.Field{
+			{Name: "steps", Number: 1, Kind: httprpc.KindMessage, Repeated: true, Message: "github.com/olvrng/rbot/be/com/flowexec/types.SimulationStepResult"},
+		}},
+		&httprpc.Schema{Name: "github.com/olvrng/rbot/be/com/flowexec/types.SimulatedButton", Fields: []httprpc.Field{
+			{Name: "title", Number: 1, Kind: httprpc.KindString},
+			{Name: "payload", Number: 2, Kind: httprpc.KindString},
+		}},
+		&httprpc.Schema{Name: "github.com/olvrng/rbot/be/com/flowexec/types.SimulatedMessage", Fields: []httprpc.Field{
+			{Name: "text", Number: 1, Kind: httprpc.KindString},
+			{Name: "buttons", Number: 2, Kind: httprpc.KindMessage, Repeated: true, Message: "github.com/olvrng/rbot/be/com/flowexec/types.SimulatedButton"},
+			{Name: "action", Number: 3, Kind: httprpc.KindString},
+		}},
+		&httprpc.Schema{Name: "github.com/olvrng/rbot/be/com/flowexec/types.SimulationStep", Fields: []httprpc.Field{
+			{Name: "event", Number: 1, Kind: httprpc.KindString},
+			{Name: "text", Number: 2, Kind: httprpc.KindString},
+			{Name: "payload", Number: 3, Kind: httprpc.KindString},
+			{Name: "order_id", Number: 4, Kind: httprpc.KindString},
+			{Name: "order_status", Number: 5, Kind: httprpc.KindString},
+			{Name: "delay", Number: 6, Kind: httprpc.KindString},
+		}},
+		&httprpc.Schema{Name: "github.com/olvrng/rbot/be/com/flowexec/types.SimulationStepResult", Fields: []httprpc.Field{
+			{Name: "step", Number: 1, Kind: httprpc.KindMessage, Message: "github.com/olvrng/rbot/be/com/flowexec/types.SimulationStep"},
+			{Name: "from_node_id", Number: 2, Kind: httprpc.KindInt},
+			{Name: "to_node_id", Number: 3, Kind: httprpc.KindInt},
+			{Name: "to_flow_id", Number: 4, Kind: httprpc.KindInt},
+			{Name: "messages", Number: 5, Kind: httprpc.KindMessage, Repeated: true, Message: "github.com/olvrng/rbot/be/com/flowexec/types.SimulatedMessage"},
+			{Name: "vars", Number: 6, Kind: httprpc.KindString, Map: true},
+			{Name: "paused", Number: 7, Kind: httprpc.KindBool},
+			{Name: "error", Number: 8, Kind: httprpc.KindString},
+		}},
+		&httprpc.Schema{Name: "github.com/olvrng/rbot/be/com/flowexec/types.StartHandoffRequest", Fields: []httprpc.Field{
+			{Name: "page_id", Number: 1, Kind: httprpc.KindInt},
+			{Name: "psid", Number: 2, Kind: httprpc.KindInt},
+			{Name: "reason", Number: 3, Kind: httprpc.KindString},
+		}},
+		&httprpc.Schema{Name: "github.com/olvrng/rbot/be/com/flowexec/types.WatchEventsRequest", Fields: []httprpc.Field{
+			{Name: "page_ids", Number: 1, Kind: httprpc.KindInt, Repeated: true},
+			{Name: "types", Number: 2, Kind: httprpc.KindString, Repeated: true},
+		}},
+	)
 }
 
 func NewServer(builder interface{}, hooks ...httprpc.HooksBuilder) (httprpc.Server, bool) {
 	switch builder := builder.(type) {
+	case func() CustomerService:
+		return NewCustomerServiceServer(builder, hooks...), true
+	case CustomerService:
+		fn := func() CustomerService { return builder }
+		return NewCustomerServiceServer(fn, hooks...), true
+	case func() HandoffService:
+		return NewHandoffServiceServer(builder, hooks...), true
+	case HandoffService:
+		fn := func() HandoffService { return builder }
+		return NewHandoffServiceServer(fn, hooks...), true
+	case func() LiveService:
+		return NewLiveServiceServer(builder, hooks...), true
+	case LiveService:
+		fn := func() LiveService { return builder }
+		return NewLiveServiceServer(fn, hooks...), true
 	case func() MessengerService:
 		return NewMessengerServiceServer(builder, hooks...), true
 	case MessengerService:
@@ -29,11 +274,419 @@ func NewServer(builder interface{}, hooks ...httprpc.HooksBuilder) (httprpc.Serv
 	case OrderService:
 		fn := func() OrderService { return builder }
 		return NewOrderServiceServer(fn, hooks...), true
+	case func() SimulatorService:
+		return NewSimulatorServiceServer(builder, hooks...), true
+	case SimulatorService:
+		fn := func() SimulatorService { return builder }
+		return NewSimulatorServiceServer(fn, hooks...), true
 	default:
 		return nil, false
 	}
 }
 
+type CustomerServiceServer struct {
+	hooks   httprpc.HooksBuilder
+	builder func() CustomerService
+}
+
+func NewCustomerServiceServer(builder func() CustomerService, hooks ...httprpc.HooksBuilder) httprpc.Server {
+	return &CustomerServiceServer{
+		hooks:   httprpc.ChainHooks(hooks...),
+		builder: builder,
+	}
+}
+
+const CustomerServicePathPrefix = "/api/flow/exec/customer/"
+
+const Path_Customer_GetCustomerLink = "/api/flow/exec/customer/GetCustomerLink"
+const Path_Customer_LinkCustomer = "/api/flow/exec/customer/LinkCustomer"
+
+func (s *CustomerServiceServer) PathPrefix() string {
+	return CustomerServicePathPrefix
+}
+
+func (s *CustomerServiceServer) WithHooks(hooks httprpc.HooksBuilder) httprpc.Server {
+	result := *s
+	result.hooks = httprpc.ChainHooks(s.hooks, hooks)
+	return &result
+}
+
+func (s *CustomerServiceServer) ServeHTTP(resp http.ResponseWriter, req *http.Request) {
+	hooks := httprpc.WrapHooks(s.hooks)
+	ctx, info := req.Context(), &httprpc.HookInfo{Route: req.URL.Path, HTTPRequest: req}
+	ctx, err := hooks.RequestReceived(ctx, *info)
+	if err != nil {
+		httprpc.WriteError(ctx, resp, hooks, *info, err)
+		return
+	}
+	reqMsg, exec, allowGET, err := s.parseRoute(req.URL.Path, hooks, info)
+	if err != nil {
+		httprpc.WriteError(ctx, resp, hooks, *info, err)
+		return
+	}
+	serve, err := httprpc.ParseRequestHeader(req, allowGET)
+	if err != nil {
+		httprpc.WriteError(ctx, resp, hooks, *info, err)
+		return
+	}
+	serve(ctx, resp, req, hooks, info, reqMsg, exec)
+}
+
+func (s *CustomerServiceServer) parseRoute(path string, hooks httprpc.Hooks, info *httprpc.HookInfo) (reqMsg httprpc.Message, _ httprpc.ExecFunc, allowGET bool, _ error) {
+	switch path {
+	case "/api/flow/exec/customer/GetCustomerLink":
+		msg := &flowexectypes.GetCustomerLinkRequest{}
+		fn := func(ctx context.Context) (newCtx context.Context, resp httprpc.Message, err error) {
+			inner := s.builder()
+			info.Request, info.Inner = msg, inner
+			newCtx, err = hooks.RequestRouted(ctx, *info)
+			if err != nil {
+				return
+			}
+			if err = msg.Validate(); err != nil {
+				return
+			}
+			resp, err = inner.GetCustomerLink(newCtx, msg)
+			return
+		}
+		return msg, fn, true, nil
+	case "/api/flow/exec/customer/LinkCustomer":
+		msg := &flowexectypes.LinkCustomerRequest{}
+		fn := func(ctx context.Context) (newCtx context.Context, resp httprpc.Message, err error) {
+			inner := s.builder()
+			info.Request, info.Inner = msg, inner
+			newCtx, err = hooks.RequestRouted(ctx, *info)
+			if err != nil {
+				return
+			}
+			if err = msg.Validate(); err != nil {
+				return
+			}
+			resp, err = inner.LinkCustomer(newCtx, msg)
+			return
+		}
+		return msg, fn, false, nil
+	default:
+		msg := fmt.Sprintf("no handler for path %q", path)
+		return nil, nil, false, httprpc.BadRouteError(msg, "POST", path)
+	}
+}
+
+// CustomerServiceClient calls CustomerService on the server at baseURL.
+type CustomerServiceClient struct {
+	client *httprpc.Client
+}
+
+var _ CustomerService = &CustomerServiceClient{}
+
+func NewCustomerServiceClient(baseURL string, httpClient *http.Client, hooks ...httprpc.ClientHooks) *CustomerServiceClient {
+	return &CustomerServiceClient{
+		client: httprpc.NewClient(baseURL, httpClient, hooks...),
+	}
+}
+
+func (c *CustomerServiceClient) GetCustomerLink(ctx context.Context, req *flowexectypes.GetCustomerLinkRequest) (*flowexectypes.CustomerLinkResponse, error) {
+	resp := &flowexectypes.CustomerLinkResponse{}
+	if err := c.client.Call(ctx, Path_Customer_GetCustomerLink, req, resp); err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+func (c *CustomerServiceClient) LinkCustomer(ctx context.Context, req *flowexectypes.LinkCustomerRequest) (*flowexectypes.CustomerLinkResponse, error) {
+	resp := &flowexectypes.CustomerLinkResponse{}
+	if err := c.client.Call(ctx, Path_Customer_LinkCustomer, req, resp); err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+type HandoffServiceServer struct {
+	hooks   httprpc.HooksBuilder
+	builder func() HandoffService
+}
+
+func NewHandoffServiceServer(builder func() HandoffService, hooks ...httprpc.HooksBuilder) httprpc.Server {
+	return &HandoffServiceServer{
+		hooks:   httprpc.ChainHooks(hooks...),
+		builder: builder,
+	}
+}
+
+const HandoffServicePathPrefix = "/api/flow/exec/handoff/"
+
+const Path_Handoff_GetHandoff = "/api/flow/exec/handoff/GetHandoff"
+const Path_Handoff_ListHandoffs = "/api/flow/exec/handoff/ListHandoffs"
+const Path_Handoff_ReplyHandoff = "/api/flow/exec/handoff/ReplyHandoff"
+const Path_Handoff_ResolveHandoff = "/api/flow/exec/handoff/ResolveHandoff"
+const Path_Handoff_StartHandoff = "/api/flow/exec/handoff/StartHandoff"
+
+func (s *HandoffServiceServer) PathPrefix() string {
+	return HandoffServicePathPrefix
+}
+
+func (s *HandoffServiceServer) WithHooks(hooks httprpc.HooksBuilder) httprpc.Server {
+	result := *s
+	result.hooks = httprpc.ChainHooks(s.hooks, hooks)
+	return &result
+}
+
+func (s *HandoffServiceServer) ServeHTTP(resp http.ResponseWriter, req *http.Request) {
+	hooks := httprpc.WrapHooks(s.hooks)
+	ctx, info := req.Context(), &httprpc.HookInfo{Route: req.URL.Path, HTTPRequest: req}
+	ctx, err := hooks.RequestReceived(ctx, *info)
+	if err != nil {
+		httprpc.WriteError(ctx, resp, hooks, *info, err)
+		return
+	}
+	reqMsg, exec, allowGET, err := s.parseRoute(req.URL.Path, hooks, info)
+	if err != nil {
+		httprpc.WriteError(ctx, resp, hooks, *info, err)
+		return
+	}
+	serve, err := httprpc.ParseRequestHeader(req, allowGET)
+	if err != nil {
+		httprpc.WriteError(ctx, resp, hooks, *info, err)
+		return
+	}
+	serve(ctx, resp, req, hooks, info, reqMsg, exec)
+}
+
+func (s *HandoffServiceServer) parseRoute(path string, hooks httprpc.Hooks, info *httprpc.HookInfo) (reqMsg httprpc.Message, _ httprpc.ExecFunc, allowGET bool, _ error) {
+	switch path {
+	case "/api/flow/exec/handoff/GetHandoff":
+		msg := &flowexectypes.GetHandoffRequest{}
+		fn := func(ctx context.Context) (newCtx context.Context, resp httprpc.Message, err error) {
+			inner := s.builder()
+			info.Request, info.Inner = msg, inner
+			newCtx, err = hooks.RequestRouted(ctx, *info)
+			if err != nil {
+				return
+			}
+			if err = msg.Validate(); err != nil {
+				return
+			}
+			resp, err = inner.GetHandoff(newCtx, msg)
+			return
+		}
+		return msg, fn, true, nil
+	case "/api/flow/exec/handoff/ListHandoffs":
+		msg := &flowexectypes.ListHandoffsRequest{}
+		fn := func(ctx context.Context) (newCtx context.Context, resp httprpc.Message, err error) {
+			inner := s.builder()
+			info.Request, info.Inner = msg, inner
+			newCtx, err = hooks.RequestRouted(ctx, *info)
+			if err != nil {
+				return
+			}
+			if err = msg.Validate(); err != nil {
+				return
+			}
+			resp, err = inner.ListHandoffs(newCtx, msg)
+			return
+		}
+		return msg, fn, true, nil
+	case "/api/flow/exec/handoff/ReplyHandoff":
+		msg := &flowexectypes.ReplyHandoffRequest{}
+		fn := func(ctx context.Context) (newCtx context.Context, resp httprpc.Message, err error) {
+			inner := s.builder()
+			info.Request, info.Inner = msg, inner
+			newCtx, err = hooks.RequestRouted(ctx, *info)
+			if err != nil {
+				return
+			}
+			if err = msg.Validate(); err != nil {
+				return
+			}
+			resp, err = inner.ReplyHandoff(newCtx, msg)
+			return
+		}
+		return msg, fn, false, nil
+	case "/api/flow/exec/handoff/ResolveHandoff":
+		msg := &flowexectypes.ResolveHandoffRequest{}
+		fn := func(ctx context.Context) (newCtx context.Context, resp httprpc.Message, err error) {
+			inner := s.builder()
+			info.Request, info.Inner = msg, inner
+			newCtx, err = hooks.RequestRouted(ctx, *info)
+			if err != nil {
+				return
+			}
+			if err = msg.Validate(); err != nil {
+				return
+			}
+			resp, err = inner.ResolveHandoff(newCtx, msg)
+			return
+		}
+		return msg, fn, false, nil
+	case "/api/flow/exec/handoff/StartHandoff":
+		msg := &flowexectypes.StartHandoffRequest{}
+		fn := func(ctx context.Context) (newCtx context.Context, resp httprpc.Message, err error) {
+			inner := s.builder()
+			info.Request, info.Inner = msg, inner
+			newCtx, err = hooks.RequestRouted(ctx, *info)
+			if err != nil {
+				return
+			}
+			if err = msg.Validate(); err != nil {
+				return
+			}
+			resp, err = inner.StartHandoff(newCtx, msg)
+			return
+		}
+		return msg, fn, false, nil
+	default:
+		msg := fmt.Sprintf("no handler for path %q", path)
+		return nil, nil, false, httprpc.BadRouteError(msg, "POST", path)
+	}
+}
+
+// HandoffServiceClient calls HandoffService on the server at baseURL.
+type HandoffServiceClient struct {
+	client *httprpc.Client
+}
+
+var _ HandoffService = &HandoffServiceClient{}
+
+func NewHandoffServiceClient(baseURL string, httpClient *http.Client, hooks ...httprpc.ClientHooks) *HandoffServiceClient {
+	return &HandoffServiceClient{
+		client: httprpc.NewClient(baseURL, httpClient, hooks...),
+	}
+}
+
+func (c *HandoffServiceClient) GetHandoff(ctx context.Context, req *flowexectypes.GetHandoffRequest) (*flowexectypes.HandoffResponse, error) {
+	resp := &flowexectypes.HandoffResponse{}
+	if err := c.client.Call(ctx, Path_Handoff_GetHandoff, req, resp); err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+func (c *HandoffServiceClient) ListHandoffs(ctx context.Context, req *flowexectypes.ListHandoffsRequest) (*flowexectypes.ListHandoffsResponse, error) {
+	resp := &flowexectypes.ListHandoffsResponse{}
+	if err := c.client.Call(ctx, Path_Handoff_ListHandoffs, req, resp); err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+func (c *HandoffServiceClient) ReplyHandoff(ctx context.Context, req *flowexectypes.ReplyHandoffRequest) (*flowexectypes.HandoffResponse, error) {
+	resp := &flowexectypes.HandoffResponse{}
+	if err := c.client.Call(ctx, Path_Handoff_ReplyHandoff, req, resp); err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+func (c *HandoffServiceClient) ResolveHandoff(ctx context.Context, req *flowexectypes.ResolveHandoffRequest) (*flowexectypes.HandoffResponse, error) {
+	resp := &flowexectypes.HandoffResponse{}
+	if err := c.client.Call(ctx, Path_Handoff_ResolveHandoff, req, resp); err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+func (c *HandoffServiceClient) StartHandoff(ctx context.Context, req *flowexectypes.StartHandoffRequest) (*flowexectypes.HandoffResponse, error) {
+	resp := &flowexectypes.HandoffResponse{}
+	if err := c.client.Call(ctx, Path_Handoff_StartHandoff, req, resp); err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+type LiveServiceServer struct {
+	hooks   httprpc.HooksBuilder
+	builder func() LiveService
+}
+
+func NewLiveServiceServer(builder func() LiveService, hooks ...httprpc.HooksBuilder) httprpc.Server {
+	return &LiveServiceServer{
+		hooks:   httprpc.ChainHooks(hooks...),
+		builder: builder,
+	}
+}
+
+const LiveServicePathPrefix = "/api/flow/exec/live/"
+
+const Path_Live_WatchEvents = "/api/flow/exec/live/WatchEvents"
+
+func (s *LiveServiceServer) PathPrefix() string {
+	return LiveServicePathPrefix
+}
+
+func (s *LiveServiceServer) WithHooks(hooks httprpc.HooksBuilder) httprpc.Server {
+	result := *s
+	result.hooks = httprpc.ChainHooks(s.hooks, hooks)
+	return &result
+}
+
+func (s *LiveServiceServer) ServeHTTP(resp http.ResponseWriter, req *http.Request) {
+	hooks := httprpc.WrapHooks(s.hooks)
+	ctx, info := req.Context(), &httprpc.HookInfo{Route: req.URL.Path, HTTPRequest: req}
+	ctx, err := hooks.RequestReceived(ctx, *info)
+	if err != nil {
+		httprpc.WriteError(ctx, resp, hooks, *info, err)
+		return
+	}
+	reqMsg, exec, allowGET, err := s.parseRoute(req.URL.Path, hooks, info)
+	if err != nil {
+		httprpc.WriteError(ctx, resp, hooks, *info, err)
+		return
+	}
+	serve, err := httprpc.ParseRequestHeader(req, allowGET)
+	if err != nil {
+		httprpc.WriteError(ctx, resp, hooks, *info, err)
+		return
+	}
+	serve(ctx, resp, req, hooks, info, reqMsg, exec)
+}
+
+func (s *LiveServiceServer) parseRoute(path string, hooks httprpc.Hooks, info *httprpc.HookInfo) (reqMsg httprpc.Message, _ httprpc.ExecFunc, allowGET bool, _ error) {
+	switch path {
+	case "/api/flow/exec/live/WatchEvents":
+		msg := &flowexectypes.WatchEventsRequest{}
+		fn := func(ctx context.Context) (newCtx context.Context, resp httprpc.Message, err error) {
+			inner := s.builder()
+			info.Request, info.Inner = msg, inner
+			newCtx, err = hooks.RequestRouted(ctx, *info)
+			if err != nil {
+				return
+			}
+			stream, err := httprpc.StartStream(newCtx)
+			if err != nil {
+				return
+			}
+			err = inner.WatchEvents(newCtx, msg, func(event *flowexectypes.FlowEvent) error {
+				return stream.Send(event)
+			})
+			return
+		}
+		return msg, fn, true, nil
+	default:
+		msg := fmt.Sprintf("no handler for path %q", path)
+		return nil, nil, false, httprpc.BadRouteError(msg, "POST", path)
+	}
+}
+
+// LiveServiceClient calls LiveService on the server at baseURL.
+type LiveServiceClient struct {
+	client *httprpc.Client
+}
+
+var _ LiveService = &LiveServiceClient{}
+
+func NewLiveServiceClient(baseURL string, httpClient *http.Client, hooks ...httprpc.ClientHooks) *LiveServiceClient {
+	return &LiveServiceClient{
+		client: httprpc.NewClient(baseURL, httpClient, hooks...),
+	}
+}
+
+func (c *LiveServiceClient) WatchEvents(ctx context.Context, req *flowexectypes.WatchEventsRequest, send func(*flowexectypes.FlowEvent) error) error {
+	newEvent := func() httprpc.Message { return &flowexectypes.FlowEvent{} }
+	return c.client.Stream(ctx, Path_Live_WatchEvents, req, newEvent, func(event httprpc.Message) error {
+		return send(event.(*flowexectypes.FlowEvent))
+	})
+}
+
 type MessengerServiceServer struct {
 	hooks   httprpc.HooksBuilder
 	builder func() MessengerService
@@ -50,6 +703,7 @@ const MessengerServicePathPrefix = "/api/flow/exec/messenger/"
 
 const Path_Messenger_ReceivedMessage = "/api/flow/exec/messenger/ReceivedMessage"
 const Path_Messenger_ReceivedPostback = "/api/flow/exec/messenger/ReceivedPostback"
+const Path_Messenger_ReceivedReferral = "/api/flow/exec/messenger/ReceivedReferral"
 
 func (s *MessengerServiceServer) PathPrefix() string {
 	return MessengerServicePathPrefix
@@ -69,12 +723,12 @@ func (s *MessengerServiceServer) ServeHTTP(resp http.ResponseWriter, req *http.R
 		httprpc.WriteError(ctx, resp, hooks, *info, err)
 		return
 	}
-	serve, err := httprpc.ParseRequestHeader(req)
+	reqMsg, exec, allowGET, err := s.parseRoute(req.URL.Path, hooks, info)
 	if err != nil {
 		httprpc.WriteError(ctx, resp, hooks, *info, err)
 		return
 	}
-	reqMsg, exec, err := s.parseRoute(req.URL.Path, hooks, info)
+	serve, err := httprpc.ParseRequestHeader(req, allowGET)
 	if err != nil {
 		httprpc.WriteError(ctx, resp, hooks, *info, err)
 		return
@@ -82,7 +736,7 @@ func (s *MessengerServiceServer) ServeHTTP(resp http.ResponseWriter, req *http.R
 	serve(ctx, resp, req, hooks, info, reqMsg, exec)
 }
 
-func (s *MessengerServiceServer) parseRoute(path string, hooks httprpc.Hooks, info *httprpc.HookInfo) (reqMsg httprpc.Message, _ httprpc.ExecFunc, _ error) {
+func (s *MessengerServiceServer) parseRoute(path string, hooks httprpc.Hooks, info *httprpc.HookInfo) (reqMsg httprpc.Message, _ httprpc.ExecFunc, allowGET bool, _ error) {
 	switch path {
 	case "/api/flow/exec/messenger/ReceivedMessage":
 		msg := &flowexectypes.ReceivedMessageRequest{}
@@ -96,7 +750,7 @@ func (s *MessengerServiceServer) parseRoute(path string, hooks httprpc.Hooks, in
 			resp, err = inner.ReceivedMessage(newCtx, msg)
 			return
 		}
-		return msg, fn, nil
+		return msg, fn, false, nil
 	case "/api/flow/exec/messenger/ReceivedPostback":
 		msg := &flowexectypes.ReceivedPostbackRequest{}
 		fn := func(ctx context.Context) (newCtx context.Context, resp httprpc.Message, err error) {
@@ -109,11 +763,61 @@ func (s *MessengerServiceServer) parseRoute(path string, hooks httprpc.Hooks, in
 			resp, err = inner.ReceivedPostback(newCtx, msg)
 			return
 		}
-		return msg, fn, nil
+		return msg, fn, false, nil
+	case "/api/flow/exec/messenger/ReceivedReferral":
+		msg := &flowexectypes.ReceivedReferralRequest{}
+		fn := func(ctx context.Context) (newCtx context.Context, resp httprpc.Message, err error) {
+			inner := s.builder()
+			info.Request, info.Inner = msg, inner
+			newCtx, err = hooks.RequestRouted(ctx, *info)
+			if err != nil {
+				return
+			}
+			resp, err = inner.ReceivedReferral(newCtx, msg)
+			return
+		}
+		return msg, fn, false, nil
 	default:
 		msg := fmt.Sprintf("no handler for path %q", path)
-		return nil, nil, httprpc.BadRouteError(msg, "POST", path)
+		return nil, nil, false, httprpc.BadRouteError(msg, "POST", path)
+	}
+}
+
+// MessengerServiceClient calls MessengerService on the server at baseURL.
+type MessengerServiceClient struct {
+	client *httprpc.Client
+}
+
+var _ MessengerService = &MessengerServiceClient{}
+
+func NewMessengerServiceClient(baseURL string, httpClient *http.Client, hooks ...httprpc.ClientHooks) *MessengerServiceClient {
+	return &MessengerServiceClient{
+		client: httprpc.NewClient(baseURL, httpClient, hooks...),
+	}
+}
+
+func (c *MessengerServiceClient) ReceivedMessage(ctx context.Context, req *flowexectypes.ReceivedMessageRequest) (*flowexectypes.ReceivedMessageResponse, error) {
+	resp := &flowexectypes.ReceivedMessageResponse{}
+	if err := c.client.Call(ctx, Path_Messenger_ReceivedMessage, req, resp); err != nil {
+		return nil, err
 	}
+	return resp, nil
+}
+
+func (c *MessengerServiceClient) ReceivedPostback(ctx context.Context, req *flowexectypes.ReceivedPostbackRequest) (*flowexectypes.ReceivedPostbackResponse, error) {
+	resp := &flowexectypes.ReceivedPostbackResponse{}
+	if err := c.client.Call(ctx, Path_Messenger_ReceivedPostback, req, resp); err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+func (c *MessengerServiceClient) ReceivedReferral(ctx context.Context, req *flowexectypes.ReceivedReferralRequest) (*flowexectypes.ReceivedReferralResponse, error) {
+	resp := &flowexectypes.ReceivedReferralResponse{}
+	if err := c.client.Call(ctx, Path_Messenger_ReceivedReferral, req, resp); err != nil {
+		return nil, err
+	}
+	return resp, nil
 }
 
 type OrderServiceServer struct {
@@ -130,7 +834,9 @@ func NewOrderServiceServer(builder func() OrderService, hooks ...httprpc.HooksBu
 
 const OrderServicePathPrefix = "/api/flow/exec/order/"
 
+const Path_Order_GetOrder = "/api/flow/exec/order/GetOrder"
 const Path_Order_ReceivedCompletedOrder = "/api/flow/exec/order/ReceivedCompletedOrder"
+const Path_Order_ReceivedOrderEvent = "/api/flow/exec/order/ReceivedOrderEvent"
 
 func (s *OrderServiceServer) PathPrefix() string {
 	return OrderServicePathPrefix
@@ -150,12 +856,12 @@ func (s *OrderServiceServer) ServeHTTP(resp http.ResponseWriter, req *http.Reque
 		httprpc.WriteError(ctx, resp, hooks, *info, err)
 		return
 	}
-	serve, err := httprpc.ParseRequestHeader(req)
+	reqMsg, exec, allowGET, err := s.parseRoute(req.URL.Path, hooks, info)
 	if err != nil {
 		httprpc.WriteError(ctx, resp, hooks, *info, err)
 		return
 	}
-	reqMsg, exec, err := s.parseRoute(req.URL.Path, hooks, info)
+	serve, err := httprpc.ParseRequestHeader(req, allowGET)
 	if err != nil {
 		httprpc.WriteError(ctx, resp, hooks, *info, err)
 		return
@@ -163,8 +869,24 @@ func (s *OrderServiceServer) ServeHTTP(resp http.ResponseWriter, req *http.Reque
 	serve(ctx, resp, req, hooks, info, reqMsg, exec)
 }
 
-func (s *OrderServiceServer) parseRoute(path string, hooks httprpc.Hooks, info *httprpc.HookInfo) (reqMsg httprpc.Message, _ httprpc.ExecFunc, _ error) {
+func (s *OrderServiceServer) parseRoute(path string, hooks httprpc.Hooks, info *httprpc.HookInfo) (reqMsg httprpc.Message, _ httprpc.ExecFunc, allowGET bool, _ error) {
 	switch path {
+	case "/api/flow/exec/order/GetOrder":
+		msg := &flowexectypes.GetOrderRequest{}
+		fn := func(ctx context.Context) (newCtx context.Context, resp httprpc.Message, err error) {
+			inner := s.builder()
+			info.Request, info.Inner = msg, inner
+			newCtx, err = hooks.RequestRouted(ctx, *info)
+			if err != nil {
+				return
+			}
+			if err = msg.Validate(); err != nil {
+				return
+			}
+			resp, err = inner.GetOrder(newCtx, msg)
+			return
+		}
+		return msg, fn, true, nil
 	case "/api/flow/exec/order/ReceivedCompletedOrder":
 		msg := &flowexectypes.ReceivedCompletedOrderRequest{}
 		fn := func(ctx context.Context) (newCtx context.Context, resp httprpc.Message, err error) {
@@ -174,12 +896,160 @@ func (s *OrderServiceServer) parseRoute(path string, hooks httprpc.Hooks, info *
 			if err != nil {
 				return
 			}
+			if err = msg.Validate(); err != nil {
+				return
+			}
 			resp, err = inner.ReceivedCompletedOrder(newCtx, msg)
 			return
 		}
-		return msg, fn, nil
+		return msg, fn, false, nil
+	case "/api/flow/exec/order/ReceivedOrderEvent":
+		msg := &flowexectypes.ReceivedOrderEventRequest{}
+		fn := func(ctx context.Context) (newCtx context.Context, resp httprpc.Message, err error) {
+			inner := s.builder()
+			info.Request, info.Inner = msg, inner
+			newCtx, err = hooks.RequestRouted(ctx, *info)
+			if err != nil {
+				return
+			}
+			if err = msg.Validate(); err != nil {
+				return
+			}
+			resp, err = inner.ReceivedOrderEvent(newCtx, msg)
+			return
+		}
+		return msg, fn, false, nil
+	default:
+		msg := fmt.Sprintf("no handler for path %q", path)
+		return nil, nil, false, httprpc.BadRouteError(msg, "POST", path)
+	}
+}
+
+// OrderServiceClient calls OrderService on the server at baseURL.
+type OrderServiceClient struct {
+	client *httprpc.Client
+}
+
+var _ OrderService = &OrderServiceClient{}
+
+func NewOrderServiceClient(baseURL string, httpClient *http.Client, hooks ...httprpc.ClientHooks) *OrderServiceClient {
+	return &OrderServiceClient{
+		client: httprpc.NewClient(baseURL, httpClient, hooks...),
+	}
+}
+
+func (c *OrderServiceClient) GetOrder(ctx context.Context, req *flowexectypes.GetOrderRequest) (*flowexectypes.OrderResponse, error) {
+	resp := &flowexectypes.OrderResponse{}
+	if err := c.client.Call(ctx, Path_Order_GetOrder, req, resp); err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+func (c *OrderServiceClient) ReceivedCompletedOrder(ctx context.Context, req *flowexectypes.ReceivedCompletedOrderRequest) (*flowexectypes.ReceivedCompletedOrderResponse, error) {
+	resp := &flowexectypes.ReceivedCompletedOrderResponse{}
+	if err := c.client.Call(ctx, Path_Order_ReceivedCompletedOrder, req, resp); err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+func (c *OrderServiceClient) ReceivedOrderEvent(ctx context.Context, req *flowexectypes.ReceivedOrderEventRequest) (*flowexectypes.ReceivedOrderEventResponse, error) {
+	resp := &flowexectypes.ReceivedOrderEventResponse{}
+	if err := c.client.Call(ctx, Path_Order_ReceivedOrderEvent, req, resp); err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+type SimulatorServiceServer struct {
+	hooks   httprpc.HooksBuilder
+	builder func() SimulatorService
+}
+
+func NewSimulatorServiceServer(builder func() SimulatorService, hooks ...httprpc.HooksBuilder) httprpc.Server {
+	return &SimulatorServiceServer{
+		hooks:   httprpc.ChainHooks(hooks...),
+		builder: builder,
+	}
+}
+
+const SimulatorServicePathPrefix = "/api/flow/exec/simulator/"
+
+const Path_Simulator_SimulateFlow = "/api/flow/exec/simulator/SimulateFlow"
+
+func (s *SimulatorServiceServer) PathPrefix() string {
+	return SimulatorServicePathPrefix
+}
+
+func (s *SimulatorServiceServer) WithHooks(hooks httprpc.HooksBuilder) httprpc.Server {
+	result := *s
+	result.hooks = httprpc.ChainHooks(s.hooks, hooks)
+	return &result
+}
+
+func (s *SimulatorServiceServer) ServeHTTP(resp http.ResponseWriter, req *http.Request) {
+	hooks := httprpc.WrapHooks(s.hooks)
+	ctx, info := req.Context(), &httprpc.HookInfo{Route: req.URL.Path, HTTPRequest: req}
+	ctx, err := hooks.RequestReceived(ctx, *info)
+	if err != nil {
+		httprpc.WriteError(ctx, resp, hooks, *info, err)
+		return
+	}
+	reqMsg, exec, allowGET, err := s.parseRoute(req.URL.Path, hooks, info)
+	if err != nil {
+		httprpc.WriteError(ctx, resp, hooks, *info, err)
+		return
+	}
+	serve, err := httprpc.ParseRequestHeader(req, allowGET)
+	if err != nil {
+		httprpc.WriteError(ctx, resp, hooks, *info, err)
+		return
+	}
+	serve(ctx, resp, req, hooks, info, reqMsg, exec)
+}
+
+func (s *SimulatorServiceServer) parseRoute(path string, hooks httprpc.Hooks, info *httprpc.HookInfo) (reqMsg httprpc.Message, _ httprpc.ExecFunc, allowGET bool, _ error) {
+	switch path {
+	case "/api/flow/exec/simulator/SimulateFlow":
+		msg := &flowexectypes.SimulateFlowRequest{}
+		fn := func(ctx context.Context) (newCtx context.Context, resp httprpc.Message, err error) {
+			inner := s.builder()
+			info.Request, info.Inner = msg, inner
+			newCtx, err = hooks.RequestRouted(ctx, *info)
+			if err != nil {
+				return
+			}
+			if err = msg.Validate(); err != nil {
+				return
+			}
+			resp, err = inner.SimulateFlow(newCtx, msg)
+			return
+		}
+		return msg, fn, false, nil
 	default:
 		msg := fmt.Sprintf("no handler for path %q", path)
-		return nil, nil, httprpc.BadRouteError(msg, "POST", path)
+		return nil, nil, false, httprpc.BadRouteError(msg, "POST", path)
+	}
+}
+
+// SimulatorServiceClient calls SimulatorService on the server at baseURL.
+type SimulatorServiceClient struct {
+	client *httprpc.Client
+}
+
+var _ SimulatorService = &SimulatorServiceClient{}
+
+func NewSimulatorServiceClient(baseURL string, httpClient *http.Client, hooks ...httprpc.ClientHooks) *SimulatorServiceClient {
+	return &SimulatorServiceClient{
+		client: httprpc.NewClient(baseURL, httpClient, hooks...),
+	}
+}
+
+func (c *SimulatorServiceClient) SimulateFlow(ctx context.Context, req *flowexectypes.SimulateFlowRequest) (*flowexectypes.SimulateFlowResponse, error) {
+	resp := &flowexectypes.SimulateFlowResponse{}
+	if err := c.client.Call(ctx, Path_Simulator_SimulateFlow, req, resp); err != nil {
+		return nil, err
 	}
+	return resp, nil
 }
@@ -0,0 +1,3128 @@
+// +build !generator
+
+// Code generated by generator openapi. DO NOT EDIT.
+
+package flowexec
+
+import (
+	openapi "github.com/olvrng/rbot/be/pkg/openapi"
+)
+
+func init() {
+	openapi.Register(openAPIFragment)
+}
+
+const openAPIFragment = `{
+  "paths": {
+    "/api/flow/exec/customer/GetCustomerLink": {
+      "get": {
+        "operationId": "Customer_GetCustomerLink_GET",
+        "tags": [
+          "CustomerService"
+        ],
+        "parameters": [
+          {
+            "name": "page_id",
+            "in": "query",
+            "schema": {
+              "type": "string",
+              "format": "int64"
+            }
+          },
+          {
+            "name": "customer_ref",
+            "in": "query",
+            "schema": {
+              "type": "string"
+            }
+          },
+          {
+            "name": "psid",
+            "in": "query",
+            "schema": {
+              "type": "string",
+              "format": "int64"
+            }
+          }
+        ],
+        "responses": {
+          "200": {
+            "description": "OK",
+            "content": {
+              "application/json": {
+                "schema": {
+                  "$ref": "#/components/schemas/flowexectypes.CustomerLinkResponse"
+                }
+              },
+              "application/protobuf": {
+                "schema": {
+                  "type": "string",
+                  "format": "binary"
+                }
+              },
+              "application/x-www-form-urlencoded": {
+                "schema": {
+                  "$ref": "#/components/schemas/flowexectypes.CustomerLinkResponse"
+                }
+              }
+            }
+          },
+          "default": {
+            "description": "Error",
+            "content": {
+              "application/json": {
+                "schema": {
+                  "$ref": "#/components/schemas/Error"
+                }
+              }
+            }
+          }
+        }
+      },
+      "post": {
+        "operationId": "Customer_GetCustomerLink",
+        "tags": [
+          "CustomerService"
+        ],
+        "requestBody": {
+          "required": true,
+          "content": {
+            "application/json": {
+              "schema": {
+                "$ref": "#/components/schemas/flowexectypes.GetCustomerLinkRequest"
+              }
+            },
+            "application/protobuf": {
+              "schema": {
+                "type": "string",
+                "format": "binary"
+              }
+            },
+            "application/x-www-form-urlencoded": {
+              "schema": {
+                "$ref": "#/components/schemas/flowexectypes.GetCustomerLinkRequest"
+              }
+            }
+          }
+        },
+        "responses": {
+          "200": {
+            "description": "OK",
+            "content": {
+              "application/json": {
+                "schema": {
+                  "$ref": "#/components/schemas/flowexectypes.CustomerLinkResponse"
+                }
+              },
+              "application/protobuf": {
+                "schema": {
+                  "type": "string",
+                  "format": "binary"
+                }
+              },
+              "application/x-www-form-urlencoded": {
+                "schema": {
+                  "$ref": "#/components/schemas/flowexectypes.CustomerLinkResponse"
+                }
+              }
+            }
+          },
+          "default": {
+            "description": "Error",
+            "content": {
+              "application/json": {
+                "schema": {
+                  "$ref": "#/components/schemas/Error"
+                }
+              }
+            }
+          }
+        }
+      }
+    },
+    "/api/flow/exec/customer/LinkCustomer": {
+      "post": {
+        "operationId": "Customer_LinkCustomer",
+        "tags": [
+          "CustomerService"
+        ],
+        "requestBody": {
+          "required": true,
+          "content": {
+            "application/json": {
+              "schema": {
+                "$ref": "#/components/schemas/flowexectypes.LinkCustomerRequest"
+              }
+            },
+            "application/protobuf": {
+              "schema": {
+                "type": "string",
+                "format": "binary"
+              }
+            },
+            "application/x-www-form-urlencoded": {
+              "schema": {
+                "$ref": "#/components/schemas/flowexectypes.LinkCustomerRequest"
+              }
+            }
+          }
+        },
+        "responses": {
+          "200": {
+            "description": "OK",
+            "content": {
+              "application/json": {
+                "schema": {
+                  "$ref": "#/components/schemas/flowexectypes.CustomerLinkResponse"
+                }
+              },
+              "application/protobuf": {
+                "schema": {
+                  "type": "string",
+                  "format": "binary"
+                }
+              },
+              "application/x-www-form-urlencoded": {
+                "schema": {
+                  "$ref": "#/components/schemas/flowexectypes.CustomerLinkResponse"
+                }
+              }
+            }
+          },
+          "default": {
+            "description": "Error",
+            "content": {
+              "application/json": {
+                "schema": {
+                  "$ref": "#/components/schemas/Error"
+                }
+              }
+            }
+          }
+        }
+      }
+    },
+    "/api/flow/exec/handoff/GetHandoff": {
+      "get": {
+        "operationId": "Handoff_GetHandoff_GET",
+        "tags": [
+          "HandoffService"
+        ],
+        "parameters": [
+          {
+            "name": "page_id",
+            "in": "query",
+            "schema": {
+              "type": "string",
+              "format": "int64"
+            }
+          },
+          {
+            "name": "psid",
+            "in": "query",
+            "schema": {
+              "type": "string",
+              "format": "int64"
+            }
+          }
+        ],
+        "responses": {
+          "200": {
+            "description": "OK",
+            "content": {
+              "application/json": {
+                "schema": {
+                  "$ref": "#/components/schemas/flowexectypes.HandoffResponse"
+                }
+              },
+              "application/protobuf": {
+                "schema": {
+                  "type": "string",
+                  "format": "binary"
+                }
+              },
+              "application/x-www-form-urlencoded": {
+                "schema": {
+                  "$ref": "#/components/schemas/flowexectypes.HandoffResponse"
+                }
+              }
+            }
+          },
+          "default": {
+            "description": "Error",
+            "content": {
+              "application/json": {
+                "schema": {
+                  "$ref": "#/components/schemas/Error"
+                }
+              }
+            }
+          }
+        }
+      },
+      "post": {
+        "operationId": "Handoff_GetHandoff",
+        "tags": [
+          "HandoffService"
+        ],
+        "requestBody": {
+          "required": true,
+          "content": {
+            "application/json": {
+              "schema": {
+                "$ref": "#/components/schemas/flowexectypes.GetHandoffRequest"
+              }
+            },
+            "application/protobuf": {
+              "schema": {
+                "type": "string",
+                "format": "binary"
+              }
+            },
+            "application/x-www-form-urlencoded": {
+              "schema": {
+                "$ref": "#/components/schemas/flowexectypes.GetHandoffRequest"
+              }
+            }
+          }
+        },
+        "responses": {
+          "200": {
+            "description": "OK",
+            "content": {
+              "application/json": {
+                "schema": {
+                  "$ref": "#/components/schemas/flowexectypes.HandoffResponse"
+                }
+              },
+              "application/protobuf": {
+                "schema": {
+                  "type": "string",
+                  "format": "binary"
+                }
+              },
+              "application/x-www-form-urlencoded": {
+                "schema": {
+                  "$ref": "#/components/schemas/flowexectypes.HandoffResponse"
+                }
+              }
+            }
+          },
+          "default": {
+            "description": "Error",
+            "content": {
+              "application/json": {
+                "schema": {
+                  "$ref": "#/components/schemas/Error"
+                }
+              }
+            }
+          }
+        }
+      }
+    },
+    "/api/flow/exec/handoff/ListHandoffs": {
+      "get": {
+        "operationId": "Handoff_ListHandoffs_GET",
+        "tags": [
+          "HandoffService"
+        ],
+        "parameters": [
+          {
+            "name": "page_id",
+            "in": "query",
+            "schema": {
+              "type": "string",
+              "format": "int64"
+            }
+          },
+          {
+            "name": "status",
+            "in": "query",
+            "schema": {
+              "$ref": "#/components/schemas/flowexectypes.HandoffStatus"
+            }
+          }
+        ],
+        "responses": {
+          "200": {
+            "description": "OK",
+            "content": {
+              "application/json": {
+                "schema": {
+                  "$ref": "#/components/schemas/flowexectypes.ListHandoffsResponse"
+                }
+              },
+              "application/protobuf": {
+                "schema": {
+                  "type": "string",
+                  "format": "binary"
+                }
+              },
+              "application/x-www-form-urlencoded": {
+                "schema": {
+                  "$ref": "#/components/schemas/flowexectypes.ListHandoffsResponse"
+                }
+              }
+            }
+          },
+          "default": {
+            "description": "Error",
+            "content": {
+              "application/json": {
+                "schema": {
+                  "$ref": "#/components/schemas/Error"
+                }
+              }
+            }
+          }
+        }
+      },
+      "post": {
+        "operationId": "Handoff_ListHandoffs",
+        "tags": [
+          "HandoffService"
+        ],
+        "requestBody": {
+          "required": true,
+          "content": {
+            "application/json": {
+              "schema": {
+                "$ref": "#/components/schemas/flowexectypes.ListHandoffsRequest"
+              }
+            },
+            "application/protobuf": {
+              "schema": {
+                "type": "string",
+                "format": "binary"
+              }
+            },
+            "application/x-www-form-urlencoded": {
+              "schema": {
+                "$ref": "#/components/schemas/flowexectypes.ListHandoffsRequest"
+              }
+            }
+          }
+        },
+        "responses": {
+          "200": {
+            "description": "OK",
+            "content": {
+              "application/json": {
+                "schema": {
+                  "$ref": "#/components/schemas/flowexectypes.ListHandoffsResponse"
+                }
+              },
+              "application/protobuf": {
+                "schema": {
+                  "type": "string",
+                  "format": "binary"
+                }
+              },
+              "application/x-www-form-urlencoded": {
+                "schema": {
+                  "$ref": "#/components/schemas/flowexectypes.ListHandoffsResponse"
+                }
+              }
+            }
+          },
+          "default": {
+            "description": "Error",
+            "content": {
+              "application/json": {
+                "schema": {
+                  "$ref": "#/components/schemas/Error"
+                }
+              }
+            }
+          }
+        }
+      }
+    },
+    "/api/flow/exec/handoff/ReplyHandoff": {
+      "post": {
+        "operationId": "Handoff_ReplyHandoff",
+        "tags": [
+          "HandoffService"
+        ],
+        "requestBody": {
+          "required": true,
+          "content": {
+            "application/json": {
+              "schema": {
+                "$ref": "#/components/schemas/flowexectypes.ReplyHandoffRequest"
+              }
+            },
+            "application/protobuf": {
+              "schema": {
+                "type": "string",
+                "format": "binary"
+              }
+            },
+            "application/x-www-form-urlencoded": {
+              "schema": {
+                "$ref": "#/components/schemas/flowexectypes.ReplyHandoffRequest"
+              }
+            }
+          }
+        },
+        "responses": {
+          "200": {
+            "description": "OK",
+            "content": {
+              "application/json": {
+                "schema": {
+                  "$ref": "#/components/schemas/flowexectypes.HandoffResponse"
+                }
+              },
+              "application/protobuf": {
+                "schema": {
+                  "type": "string",
+                  "format": "binary"
+                }
+              },
+              "application/x-www-form-urlencoded": {
+                "schema": {
+                  "$ref": "#/components/schemas/flowexectypes.HandoffResponse"
+                }
+              }
+            }
+          },
+          "default": {
+            "description": "Error",
+            "content": {
+              "application/json": {
+                "schema": {
+                  "$ref": "#/components/schemas/Error"
+                }
+              }
+            }
+          }
+        }
+      }
+    },
+    "/api/flow/exec/handoff/ResolveHandoff": {
+      "post": {
+        "operationId": "Handoff_ResolveHandoff",
+        "tags": [
+          "HandoffService"
+        ],
+        "requestBody": {
+          "required": true,
+          "content": {
+            "application/json": {
+              "schema": {
+                "$ref": "#/components/schemas/flowexectypes.ResolveHandoffRequest"
+              }
+            },
+            "application/protobuf": {
+              "schema": {
+                "type": "string",
+                "format": "binary"
+              }
+            },
+            "application/x-www-form-urlencoded": {
+              "schema": {
+                "$ref": "#/components/schemas/flowexectypes.ResolveHandoffRequest"
+              }
+            }
+          }
+        },
+        "responses": {
+          "200": {
+            "description": "OK",
+            "content": {
+              "application/json": {
+                "schema": {
+                  "$ref": "#/components/schemas/flowexectypes.HandoffResponse"
+                }
+              },
+              "application/protobuf": {
+                "schema": {
+                  "type": "string",
+                  "format": "binary"
+                }
+              },
+              "application/x-www-form-urlencoded": {
+                "schema": {
+                  "$ref": "#/components/schemas/flowexectypes.HandoffResponse"
+                }
+              }
+            }
+          },
+          "default": {
+            "description": "Error",
+            "content": {
+              "application/json": {
+                "schema": {
+                  "$ref": "#/components/schemas/Error"
+                }
+              }
+            }
+          }
+        }
+      }
+    },
+    "/api/flow/exec/handoff/StartHandoff": {
+      "post": {
+        "operationId": "Handoff_StartHandoff",
+        "tags": [
+          "HandoffService"
+        ],
+        "requestBody": {
+          "required": true,
+          "content": {
+            "application/json": {
+              "schema": {
+                "$ref": "#/components/schemas/flowexectypes.StartHandoffRequest"
+              }
+            },
+            "application/protobuf": {
+              "schema": {
+                "type": "string",
+                "format": "binary"
+              }
+            },
+            "application/x-www-form-urlencoded": {
+              "schema": {
+                "$ref": "#/components/schemas/flowexectypes.StartHandoffRequest"
+              }
+            }
+          }
+        },
+        "responses": {
+          "200": {
+            "description": "OK",
+            "content": {
+              "application/json": {
+                "schema": {
+                  "$ref": "#/components/schemas/flowexectypes.HandoffResponse"
+                }
+              },
+              "application/protobuf": {
+                "schema": {
+                  "type": "string",
+                  "format": "binary"
+                }
+              },
+              "application/x-www-form-urlencoded": {
+                "schema": {
+                  "$ref": "#/components/schemas/flowexectypes.HandoffResponse"
+                }
+              }
+            }
+          },
+          "default": {
+            "description": "Error",
+            "content": {
+              "application/json": {
+                "schema": {
+                  "$ref": "#/components/schemas/Error"
+                }
+              }
+            }
+          }
+        }
+      }
+    },
+    "/api/flow/exec/live/WatchEvents": {
+      "get": {
+        "operationId": "Live_WatchEvents_GET",
+        "tags": [
+          "LiveService"
+        ],
+        "summary": "WatchEvents streams the events of the pages as they happen, such as the state transitions, the messages sent and the handoffs requested.",
+        "description": "WatchEvents streams the events of the pages as they happen, such as the\nstate transitions, the messages sent and the handoffs requested. Only the\nevents of the pages which the principal may view are sent.",
+        "parameters": [
+          {
+            "name": "page_ids",
+            "in": "query",
+            "schema": {
+              "type": "array",
+              "items": {
+                "type": "string",
+                "format": "int64"
+              }
+            }
+          },
+          {
+            "name": "types",
+            "in": "query",
+            "schema": {
+              "type": "array",
+              "items": {
+                "$ref": "#/components/schemas/flowexectypes.FlowEventType"
+              }
+            }
+          }
+        ],
+        "responses": {
+          "200": {
+            "description": "The server-sent events, each with the json of a message as data. An error event ends the stream.",
+            "content": {
+              "text/event-stream": {
+                "schema": {
+                  "$ref": "#/components/schemas/flowexectypes.FlowEvent"
+                }
+              }
+            }
+          },
+          "default": {
+            "description": "Error",
+            "content": {
+              "application/json": {
+                "schema": {
+                  "$ref": "#/components/schemas/Error"
+                }
+              }
+            }
+          }
+        }
+      },
+      "post": {
+        "operationId": "Live_WatchEvents",
+        "tags": [
+          "LiveService"
+        ],
+        "summary": "WatchEvents streams the events of the pages as they happen, such as the state transitions, the messages sent and the handoffs requested.",
+        "description": "WatchEvents streams the events of the pages as they happen, such as the\nstate transitions, the messages sent and the handoffs requested. Only the\nevents of the pages which the principal may view are sent.",
+        "requestBody": {
+          "required": true,
+          "content": {
+            "application/json": {
+              "schema": {
+                "$ref": "#/components/schemas/flowexectypes.WatchEventsRequest"
+              }
+            },
+            "application/protobuf": {
+              "schema": {
+                "type": "string",
+                "format": "binary"
+              }
+            },
+            "application/x-www-form-urlencoded": {
+              "schema": {
+                "$ref": "#/components/schemas/flowexectypes.WatchEventsRequest"
+              }
+            }
+          }
+        },
+        "responses": {
+          "200": {
+            "description": "The server-sent events, each with the json of a message as data. An error event ends the stream.",
+            "content": {
+              "text/event-stream": {
+                "schema": {
+                  "$ref": "#/components/schemas/flowexectypes.FlowEvent"
+                }
+              }
+            }
+          },
+          "default": {
+            "description": "Error",
+            "content": {
+              "application/json": {
+                "schema": {
+                  "$ref": "#/components/schemas/Error"
+                }
+              }
+            }
+          }
+        }
+      }
+    },
+    "/api/flow/exec/messenger/ReceivedMessage": {
+      "post": {
+        "operationId": "Messenger_ReceivedMessage",
+        "tags": [
+          "MessengerService"
+        ],
+        "requestBody": {
+          "required": true,
+          "content": {
+            "application/json": {
+              "schema": {
+                "$ref": "#/components/schemas/flowexectypes.ReceivedMessageRequest"
+              }
+            },
+            "application/protobuf": {
+              "schema": {
+                "type": "string",
+                "format": "binary"
+              }
+            },
+            "application/x-www-form-urlencoded": {
+              "schema": {
+                "$ref": "#/components/schemas/flowexectypes.ReceivedMessageRequest"
+              }
+            }
+          }
+        },
+        "responses": {
+          "200": {
+            "description": "OK",
+            "content": {
+              "application/json": {
+                "schema": {
+                  "$ref": "#/components/schemas/flowexectypes.ReceivedMessageResponse"
+                }
+              },
+              "application/protobuf": {
+                "schema": {
+                  "type": "string",
+                  "format": "binary"
+                }
+              },
+              "application/x-www-form-urlencoded": {
+                "schema": {
+                  "$ref": "#/components/schemas/flowexectypes.ReceivedMessageResponse"
+                }
+              }
+            }
+          },
+          "default": {
+            "description": "Error",
+            "content": {
+              "application/json": {
+                "schema": {
+                  "$ref": "#/components/schemas/Error"
+                }
+              }
+            }
+          }
+        }
+      }
+    },
+    "/api/flow/exec/messenger/ReceivedPostback": {
+      "post": {
+        "operationId": "Messenger_ReceivedPostback",
+        "tags": [
+          "MessengerService"
+        ],
+        "requestBody": {
+          "required": true,
+          "content": {
+            "application/json": {
+              "schema": {
+                "$ref": "#/components/schemas/flowexectypes.ReceivedPostbackRequest"
+              }
+            },
+            "application/protobuf": {
+              "schema": {
+                "type": "string",
+                "format": "binary"
+              }
+            },
+            "application/x-www-form-urlencoded": {
+              "schema": {
+                "$ref": "#/components/schemas/flowexectypes.ReceivedPostbackRequest"
+              }
+            }
+          }
+        },
+        "responses": {
+          "200": {
+            "description": "OK",
+            "content": {
+              "application/json": {
+                "schema": {
+                  "$ref": "#/components/schemas/flowexectypes.ReceivedPostbackResponse"
+                }
+              },
+              "application/protobuf": {
+                "schema": {
+                  "type": "string",
+                  "format": "binary"
+                }
+              },
+              "application/x-www-form-urlencoded": {
+                "schema": {
+                  "$ref": "#/components/schemas/flowexectypes.ReceivedPostbackResponse"
+                }
+              }
+            }
+          },
+          "default": {
+            "description": "Error",
+            "content": {
+              "application/json": {
+                "schema": {
+                  "$ref": "#/components/schemas/Error"
+                }
+              }
+            }
+          }
+        }
+      }
+    },
+    "/api/flow/exec/messenger/ReceivedReferral": {
+      "post": {
+        "operationId": "Messenger_ReceivedReferral",
+        "tags": [
+          "MessengerService"
+        ],
+        "requestBody": {
+          "required": true,
+          "content": {
+            "application/json": {
+              "schema": {
+                "$ref": "#/components/schemas/flowexectypes.ReceivedReferralRequest"
+              }
+            },
+            "application/protobuf": {
+              "schema": {
+                "type": "string",
+                "format": "binary"
+              }
+            },
+            "application/x-www-form-urlencoded": {
+              "schema": {
+                "$ref": "#/components/schemas/flowexectypes.ReceivedReferralRequest"
+              }
+            }
+          }
+        },
+        "responses": {
+          "200": {
+            "description": "OK",
+            "content": {
+              "application/json": {
+                "schema": {
+                  "$ref": "#/components/schemas/flowexectypes.ReceivedReferralResponse"
+                }
+              },
+              "application/protobuf": {
+                "schema": {
+                  "type": "string",
+                  "format": "binary"
+                }
+              },
+              "application/x-www-form-urlencoded": {
+                "schema": {
+                  "$ref": "#/components/schemas/flowexectypes.ReceivedReferralResponse"
+                }
+              }
+            }
+          },
+          "default": {
+            "description": "Error",
+            "content": {
+              "application/json": {
+                "schema": {
+                  "$ref": "#/components/schemas/Error"
+                }
+              }
+            }
+          }
+        }
+      }
+    },
+    "/api/flow/exec/order/GetOrder": {
+      "get": {
+        "operationId": "Order_GetOrder_GET",
+        "tags": [
+          "OrderService"
+        ],
+        "parameters": [
+          {
+            "name": "page_id",
+            "in": "query",
+            "schema": {
+              "type": "string",
+              "format": "int64"
+            }
+          },
+          {
+            "name": "order_id",
+            "in": "query",
+            "schema": {
+              "type": "string"
+            }
+          }
+        ],
+        "responses": {
+          "200": {
+            "description": "OK",
+            "content": {
+              "application/json": {
+                "schema": {
+                  "$ref": "#/components/schemas/flowexectypes.OrderResponse"
+                }
+              },
+              "application/protobuf": {
+                "schema": {
+                  "type": "string",
+                  "format": "binary"
+                }
+              },
+              "application/x-www-form-urlencoded": {
+                "schema": {
+                  "$ref": "#/components/schemas/flowexectypes.OrderResponse"
+                }
+              }
+            }
+          },
+          "default": {
+            "description": "Error",
+            "content": {
+              "application/json": {
+                "schema": {
+                  "$ref": "#/components/schemas/Error"
+                }
+              }
+            }
+          }
+        }
+      },
+      "post": {
+        "operationId": "Order_GetOrder",
+        "tags": [
+          "OrderService"
+        ],
+        "requestBody": {
+          "required": true,
+          "content": {
+            "application/json": {
+              "schema": {
+                "$ref": "#/components/schemas/flowexectypes.GetOrderRequest"
+              }
+            },
+            "application/protobuf": {
+              "schema": {
+                "type": "string",
+                "format": "binary"
+              }
+            },
+            "application/x-www-form-urlencoded": {
+              "schema": {
+                "$ref": "#/components/schemas/flowexectypes.GetOrderRequest"
+              }
+            }
+          }
+        },
+        "responses": {
+          "200": {
+            "description": "OK",
+            "content": {
+              "application/json": {
+                "schema": {
+                  "$ref": "#/components/schemas/flowexectypes.OrderResponse"
+                }
+              },
+              "application/protobuf": {
+                "schema": {
+                  "type": "string",
+                  "format": "binary"
+                }
+              },
+              "application/x-www-form-urlencoded": {
+                "schema": {
+                  "$ref": "#/components/schemas/flowexectypes.OrderResponse"
+                }
+              }
+            }
+          },
+          "default": {
+            "description": "Error",
+            "content": {
+              "application/json": {
+                "schema": {
+                  "$ref": "#/components/schemas/Error"
+                }
+              }
+            }
+          }
+        }
+      }
+    },
+    "/api/flow/exec/order/ReceivedCompletedOrder": {
+      "post": {
+        "operationId": "Order_ReceivedCompletedOrder",
+        "tags": [
+          "OrderService"
+        ],
+        "requestBody": {
+          "required": true,
+          "content": {
+            "application/json": {
+              "schema": {
+                "$ref": "#/components/schemas/flowexectypes.ReceivedCompletedOrderRequest"
+              }
+            },
+            "application/protobuf": {
+              "schema": {
+                "type": "string",
+                "format": "binary"
+              }
+            },
+            "application/x-www-form-urlencoded": {
+              "schema": {
+                "$ref": "#/components/schemas/flowexectypes.ReceivedCompletedOrderRequest"
+              }
+            }
+          }
+        },
+        "responses": {
+          "200": {
+            "description": "OK",
+            "content": {
+              "application/json": {
+                "schema": {
+                  "$ref": "#/components/schemas/flowexectypes.ReceivedCompletedOrderResponse"
+                }
+              },
+              "application/protobuf": {
+                "schema": {
+                  "type": "string",
+                  "format": "binary"
+                }
+              },
+              "application/x-www-form-urlencoded": {
+                "schema": {
+                  "$ref": "#/components/schemas/flowexectypes.ReceivedCompletedOrderResponse"
+                }
+              }
+            }
+          },
+          "default": {
+            "description": "Error",
+            "content": {
+              "application/json": {
+                "schema": {
+                  "$ref": "#/components/schemas/Error"
+                }
+              }
+            }
+          }
+        }
+      }
+    },
+    "/api/flow/exec/order/ReceivedOrderEvent": {
+      "post": {
+        "operationId": "Order_ReceivedOrderEvent",
+        "tags": [
+          "OrderService"
+        ],
+        "requestBody": {
+          "required": true,
+          "content": {
+            "application/json": {
+              "schema": {
+                "$ref": "#/components/schemas/flowexectypes.ReceivedOrderEventRequest"
+              }
+            },
+            "application/protobuf": {
+              "schema": {
+                "type": "string",
+                "format": "binary"
+              }
+            },
+            "application/x-www-form-urlencoded": {
+              "schema": {
+                "$ref": "#/components/schemas/flowexectypes.ReceivedOrderEventRequest"
+              }
+            }
+          }
+        },
+        "responses": {
+          "200": {
+            "description": "OK",
+            "content": {
+              "application/json": {
+                "schema": {
+                  "$ref": "#/components/schemas/flowexectypes.ReceivedOrderEventResponse"
+                }
+              },
+              "application/protobuf": {
+                "schema": {
+                  "type": "string",
+                  "format": "binary"
+                }
+              },
+              "application/x-www-form-urlencoded": {
+                "schema": {
+                  "$ref": "#/components/schemas/flowexectypes.ReceivedOrderEventResponse"
+                }
+              }
+            }
+          },
+          "default": {
+            "description": "Error",
+            "content": {
+              "application/json": {
+                "schema": {
+                  "$ref": "#/components/schemas/Error"
+                }
+              }
+            }
+          }
+        }
+      }
+    },
+    "/api/flow/exec/simulator/SimulateFlow": {
+      "post": {
+        "operationId": "Simulator_SimulateFlow",
+        "tags": [
+          "SimulatorService"
+        ],
+        "requestBody": {
+          "required": true,
+          "content": {
+            "application/json": {
+              "schema": {
+                "$ref": "#/components/schemas/flowexectypes.SimulateFlowRequest"
+              }
+            },
+            "application/protobuf": {
+              "schema": {
+                "type": "string",
+                "format": "binary"
+              }
+            },
+            "application/x-www-form-urlencoded": {
+              "schema": {
+                "$ref": "#/components/schemas/flowexectypes.SimulateFlowRequest"
+              }
+            }
+          }
+        },
+        "responses": {
+          "200": {
+            "description": "OK",
+            "content": {
+              "application/json": {
+                "schema": {
+                  "$ref": "#/components/schemas/flowexectypes.SimulateFlowResponse"
+                }
+              },
+              "application/protobuf": {
+                "schema": {
+                  "type": "string",
+                  "format": "binary"
+                }
+              },
+              "application/x-www-form-urlencoded": {
+                "schema": {
+                  "$ref": "#/components/schemas/flowexectypes.SimulateFlowResponse"
+                }
+              }
+            }
+          },
+          "default": {
+            "description": "Error",
+            "content": {
+              "application/json": {
+                "schema": {
+                  "$ref": "#/components/schemas/Error"
+                }
+              }
+            }
+          }
+        }
+      }
+    }
+  },
+  "components": {
+    "schemas": {
+      "flowdeftypes.AskRatingNodeData": {
+        "type": "object",
+        "properties": {
+          "comment_template": {
+            "type": "string"
+          },
+          "next_id": {
+            "type": "string",
+            "format": "int64"
+          },
+          "retry_template": {
+            "type": "string"
+          },
+          "skip_text": {
+            "type": "string"
+          },
+          "template": {
+            "type": "string"
+          },
+          "timeout": {
+            "type": "string",
+            "description": "such as 30m, 24h or 3d"
+          },
+          "timeout_next_id": {
+            "type": "string",
+            "format": "int64"
+          },
+          "type": {
+            "$ref": "#/components/schemas/flowdeftypes.NodeType"
+          }
+        },
+        "required": [
+          "type",
+          "template"
+        ]
+      },
+      "flowdeftypes.Assignment": {
+        "type": "object",
+        "properties": {
+          "expr": {
+            "type": "string"
+          },
+          "var_type": {
+            "$ref": "#/components/schemas/flowdeftypes.VarType"
+          },
+          "variable": {
+            "type": "string"
+          }
+        },
+        "required": [
+          "variable",
+          "expr"
+        ]
+      },
+      "flowdeftypes.CaptureInputNodeData": {
+        "type": "object",
+        "properties": {
+          "next_id": {
+            "type": "string",
+            "format": "int64"
+          },
+          "pattern": {
+            "type": "string"
+          },
+          "retry_template": {
+            "type": "string"
+          },
+          "template": {
+            "type": "string"
+          },
+          "timeout": {
+            "type": "string",
+            "description": "such as 30m, 24h or 3d"
+          },
+          "timeout_next_id": {
+            "type": "string",
+            "format": "int64"
+          },
+          "type": {
+            "$ref": "#/components/schemas/flowdeftypes.NodeType"
+          },
+          "validation": {
+            "$ref": "#/components/schemas/flowdeftypes.InputValidation"
+          },
+          "variable": {
+            "type": "string"
+          }
+        },
+        "required": [
+          "type",
+          "template",
+          "variable"
+        ]
+      },
+      "flowdeftypes.CompletedOrderNodeData": {
+        "type": "object",
+        "properties": {
+          "fields": {
+            "type": "array",
+            "items": {
+              "type": "string"
+            }
+          },
+          "next_id": {
+            "type": "string",
+            "format": "int64"
+          },
+          "type": {
+            "$ref": "#/components/schemas/flowdeftypes.NodeType"
+          }
+        },
+        "required": [
+          "type",
+          "fields"
+        ]
+      },
+      "flowdeftypes.ConditionNodeData": {
+        "type": "object",
+        "properties": {
+          "next_id": {
+            "type": "string",
+            "format": "int64"
+          },
+          "rules": {
+            "type": "array",
+            "items": {
+              "$ref": "#/components/schemas/flowdeftypes.ConditionRule"
+            }
+          },
+          "type": {
+            "$ref": "#/components/schemas/flowdeftypes.NodeType"
+          }
+        },
+        "required": [
+          "type",
+          "rules"
+        ]
+      },
+      "flowdeftypes.ConditionOp": {
+        "type": "string",
+        "enum": [
+          "contains",
+          "empty",
+          "eq",
+          "gt",
+          "gte",
+          "lt",
+          "lte",
+          "matches",
+          "ne",
+          "not_empty"
+        ]
+      },
+      "flowdeftypes.ConditionRule": {
+        "type": "object",
+        "properties": {
+          "next_id": {
+            "type": "string",
+            "format": "int64"
+          },
+          "op": {
+            "$ref": "#/components/schemas/flowdeftypes.ConditionOp"
+          },
+          "value": {
+            "type": "string"
+          },
+          "variable": {
+            "type": "string"
+          }
+        },
+        "required": [
+          "variable",
+          "op",
+          "next_id"
+        ]
+      },
+      "flowdeftypes.Flow": {
+        "type": "object",
+        "properties": {
+          "entry_points": {
+            "type": "object",
+            "additionalProperties": {
+              "type": "string",
+              "format": "int64"
+            }
+          },
+          "fallback_node_id": {
+            "type": "string",
+            "format": "int64"
+          },
+          "id": {
+            "type": "string",
+            "format": "int64"
+          },
+          "intents": {
+            "type": "array",
+            "items": {
+              "$ref": "#/components/schemas/flowdeftypes.Intent"
+            }
+          },
+          "keywords": {
+            "type": "array",
+            "items": {
+              "$ref": "#/components/schemas/flowdeftypes.KeywordEntry"
+            }
+          },
+          "nodes": {
+            "type": "array",
+            "items": {
+              "$ref": "#/components/schemas/flowdeftypes.Node"
+            }
+          },
+          "page_ids": {
+            "type": "array",
+            "items": {
+              "type": "string",
+              "format": "int64"
+            }
+          },
+          "priority": {
+            "type": "integer"
+          },
+          "restart_policy": {
+            "$ref": "#/components/schemas/flowdeftypes.RestartPolicy"
+          },
+          "workspace_id": {
+            "type": "string",
+            "format": "int64"
+          }
+        },
+        "required": [
+          "id",
+          "page_ids",
+          "nodes"
+        ]
+      },
+      "flowdeftypes.GotoFlowNodeData": {
+        "type": "object",
+        "properties": {
+          "flow_id": {
+            "type": "string",
+            "format": "int64"
+          },
+          "node_id": {
+            "type": "string",
+            "format": "int64"
+          },
+          "type": {
+            "$ref": "#/components/schemas/flowdeftypes.NodeType"
+          }
+        },
+        "required": [
+          "type",
+          "flow_id",
+          "node_id"
+        ]
+      },
+      "flowdeftypes.HTTPRequestNodeData": {
+        "type": "object",
+        "properties": {
+          "body": {
+            "type": "string"
+          },
+          "error_next_id": {
+            "type": "string",
+            "format": "int64"
+          },
+          "headers": {
+            "type": "object",
+            "additionalProperties": {
+              "type": "string"
+            }
+          },
+          "method": {
+            "type": "string"
+          },
+          "next_id": {
+            "type": "string",
+            "format": "int64"
+          },
+          "response_vars": {
+            "type": "array",
+            "items": {
+              "$ref": "#/components/schemas/flowdeftypes.ResponseVar"
+            }
+          },
+          "retries": {
+            "type": "integer"
+          },
+          "status_routes": {
+            "type": "array",
+            "items": {
+              "$ref": "#/components/schemas/flowdeftypes.StatusRoute"
+            }
+          },
+          "status_variable": {
+            "type": "string"
+          },
+          "timeout": {
+            "type": "string",
+            "description": "such as 30m, 24h or 3d"
+          },
+          "type": {
+            "$ref": "#/components/schemas/flowdeftypes.NodeType"
+          },
+          "url": {
+            "type": "string"
+          }
+        },
+        "required": [
+          "type",
+          "url"
+        ]
+      },
+      "flowdeftypes.HandoffNodeData": {
+        "type": "object",
+        "properties": {
+          "next_id": {
+            "type": "string",
+            "format": "int64"
+          },
+          "pass_thread_control": {
+            "type": "boolean"
+          },
+          "reason": {
+            "type": "string"
+          },
+          "target_app_id": {
+            "type": "string",
+            "format": "int64"
+          },
+          "template": {
+            "type": "string"
+          },
+          "type": {
+            "$ref": "#/components/schemas/flowdeftypes.NodeType"
+          }
+        },
+        "required": [
+          "type"
+        ]
+      },
+      "flowdeftypes.InputValidation": {
+        "type": "string",
+        "enum": [
+          "",
+          "email",
+          "number",
+          "phone",
+          "regex"
+        ]
+      },
+      "flowdeftypes.Intent": {
+        "type": "object",
+        "properties": {
+          "examples": {
+            "type": "array",
+            "items": {
+              "type": "string"
+            }
+          },
+          "name": {
+            "type": "string"
+          }
+        },
+        "required": [
+          "name",
+          "examples"
+        ]
+      },
+      "flowdeftypes.KeywordEntry": {
+        "type": "object",
+        "properties": {
+          "keywords": {
+            "type": "array",
+            "items": {
+              "type": "string"
+            }
+          },
+          "next_id": {
+            "type": "string",
+            "format": "int64"
+          }
+        },
+        "required": [
+          "keywords",
+          "next_id"
+        ]
+      },
+      "flowdeftypes.KeywordMatch": {
+        "type": "string",
+        "enum": [
+          "any",
+          "exact",
+          "intent",
+          "regex"
+        ]
+      },
+      "flowdeftypes.KeywordNodeData": {
+        "type": "object",
+        "properties": {
+          "next_id": {
+            "type": "string",
+            "format": "int64"
+          },
+          "rules": {
+            "type": "array",
+            "items": {
+              "$ref": "#/components/schemas/flowdeftypes.KeywordRule"
+            }
+          },
+          "type": {
+            "$ref": "#/components/schemas/flowdeftypes.NodeType"
+          }
+        },
+        "required": [
+          "type",
+          "rules"
+        ]
+      },
+      "flowdeftypes.KeywordRule": {
+        "type": "object",
+        "properties": {
+          "intent": {
+            "type": "string"
+          },
+          "match": {
+            "$ref": "#/components/schemas/flowdeftypes.KeywordMatch"
+          },
+          "min_score": {
+            "type": "number"
+          },
+          "pattern": {
+            "type": "string"
+          },
+          "priority": {
+            "type": "integer"
+          },
+          "values": {
+            "type": "array",
+            "items": {
+              "type": "string"
+            }
+          }
+        },
+        "required": [
+          "match"
+        ]
+      },
+      "flowdeftypes.Node": {
+        "type": "object",
+        "properties": {
+          "id": {
+            "type": "string",
+            "format": "int64"
+          },
+          "payload": {
+            "$ref": "#/components/schemas/flowdeftypes.NodePayload"
+          }
+        }
+      },
+      "flowdeftypes.NodePayload": {
+        "oneOf": [
+          {
+            "allOf": [
+              {
+                "$ref": "#/components/schemas/flowdeftypes.CompletedOrderNodeData"
+              },
+              {
+                "type": "object",
+                "properties": {
+                  "type": {
+                    "type": "string",
+                    "enum": [
+                      "trigger:completed_order"
+                    ]
+                  }
+                },
+                "required": [
+                  "type"
+                ]
+              }
+            ]
+          },
+          {
+            "allOf": [
+              {
+                "$ref": "#/components/schemas/flowdeftypes.OrderEventNodeData"
+              },
+              {
+                "type": "object",
+                "properties": {
+                  "type": {
+                    "type": "string",
+                    "enum": [
+                      "trigger:order_created"
+                    ]
+                  }
+                },
+                "required": [
+                  "type"
+                ]
+              }
+            ]
+          },
+          {
+            "allOf": [
+              {
+                "$ref": "#/components/schemas/flowdeftypes.OrderEventNodeData"
+              },
+              {
+                "type": "object",
+                "properties": {
+                  "type": {
+                    "type": "string",
+                    "enum": [
+                      "trigger:order_paid"
+                    ]
+                  }
+                },
+                "required": [
+                  "type"
+                ]
+              }
+            ]
+          },
+          {
+            "allOf": [
+              {
+                "$ref": "#/components/schemas/flowdeftypes.OrderEventNodeData"
+              },
+              {
+                "type": "object",
+                "properties": {
+                  "type": {
+                    "type": "string",
+                    "enum": [
+                      "trigger:order_shipped"
+                    ]
+                  }
+                },
+                "required": [
+                  "type"
+                ]
+              }
+            ]
+          },
+          {
+            "allOf": [
+              {
+                "$ref": "#/components/schemas/flowdeftypes.OrderEventNodeData"
+              },
+              {
+                "type": "object",
+                "properties": {
+                  "type": {
+                    "type": "string",
+                    "enum": [
+                      "trigger:order_refunded"
+                    ]
+                  }
+                },
+                "required": [
+                  "type"
+                ]
+              }
+            ]
+          },
+          {
+            "allOf": [
+              {
+                "$ref": "#/components/schemas/flowdeftypes.ReceivedMessageNodeData"
+              },
+              {
+                "type": "object",
+                "properties": {
+                  "type": {
+                    "type": "string",
+                    "enum": [
+                      "trigger:received_message"
+                    ]
+                  }
+                },
+                "required": [
+                  "type"
+                ]
+              }
+            ]
+          },
+          {
+            "allOf": [
+              {
+                "$ref": "#/components/schemas/flowdeftypes.ReferralNodeData"
+              },
+              {
+                "type": "object",
+                "properties": {
+                  "type": {
+                    "type": "string",
+                    "enum": [
+                      "trigger:referral"
+                    ]
+                  }
+                },
+                "required": [
+                  "type"
+                ]
+              }
+            ]
+          },
+          {
+            "allOf": [
+              {
+                "$ref": "#/components/schemas/flowdeftypes.KeywordNodeData"
+              },
+              {
+                "type": "object",
+                "properties": {
+                  "type": {
+                    "type": "string",
+                    "enum": [
+                      "trigger:keyword"
+                    ]
+                  }
+                },
+                "required": [
+                  "type"
+                ]
+              }
+            ]
+          },
+          {
+            "allOf": [
+              {
+                "$ref": "#/components/schemas/flowdeftypes.SendMessageNodeData"
+              },
+              {
+                "type": "object",
+                "properties": {
+                  "type": {
+                    "type": "string",
+                    "enum": [
+                      "action:send_message"
+                    ]
+                  }
+                },
+                "required": [
+                  "type"
+                ]
+              }
+            ]
+          },
+          {
+            "allOf": [
+              {
+                "$ref": "#/components/schemas/flowdeftypes.CaptureInputNodeData"
+              },
+              {
+                "type": "object",
+                "properties": {
+                  "type": {
+                    "type": "string",
+                    "enum": [
+                      "action:capture_input"
+                    ]
+                  }
+                },
+                "required": [
+                  "type"
+                ]
+              }
+            ]
+          },
+          {
+            "allOf": [
+              {
+                "$ref": "#/components/schemas/flowdeftypes.SetVariableNodeData"
+              },
+              {
+                "type": "object",
+                "properties": {
+                  "type": {
+                    "type": "string",
+                    "enum": [
+                      "action:set_variable"
+                    ]
+                  }
+                },
+                "required": [
+                  "type"
+                ]
+              }
+            ]
+          },
+          {
+            "allOf": [
+              {
+                "$ref": "#/components/schemas/flowdeftypes.ConditionNodeData"
+              },
+              {
+                "type": "object",
+                "properties": {
+                  "type": {
+                    "type": "string",
+                    "enum": [
+                      "action:condition"
+                    ]
+                  }
+                },
+                "required": [
+                  "type"
+                ]
+              }
+            ]
+          },
+          {
+            "allOf": [
+              {
+                "$ref": "#/components/schemas/flowdeftypes.AskRatingNodeData"
+              },
+              {
+                "type": "object",
+                "properties": {
+                  "type": {
+                    "type": "string",
+                    "enum": [
+                      "action:ask_rating"
+                    ]
+                  }
+                },
+                "required": [
+                  "type"
+                ]
+              }
+            ]
+          },
+          {
+            "allOf": [
+              {
+                "$ref": "#/components/schemas/flowdeftypes.WaitNodeData"
+              },
+              {
+                "type": "object",
+                "properties": {
+                  "type": {
+                    "type": "string",
+                    "enum": [
+                      "action:wait"
+                    ]
+                  }
+                },
+                "required": [
+                  "type"
+                ]
+              }
+            ]
+          },
+          {
+            "allOf": [
+              {
+                "$ref": "#/components/schemas/flowdeftypes.HandoffNodeData"
+              },
+              {
+                "type": "object",
+                "properties": {
+                  "type": {
+                    "type": "string",
+                    "enum": [
+                      "action:handoff"
+                    ]
+                  }
+                },
+                "required": [
+                  "type"
+                ]
+              }
+            ]
+          },
+          {
+            "allOf": [
+              {
+                "$ref": "#/components/schemas/flowdeftypes.HTTPRequestNodeData"
+              },
+              {
+                "type": "object",
+                "properties": {
+                  "type": {
+                    "type": "string",
+                    "enum": [
+                      "action:http_request"
+                    ]
+                  }
+                },
+                "required": [
+                  "type"
+                ]
+              }
+            ]
+          },
+          {
+            "allOf": [
+              {
+                "$ref": "#/components/schemas/flowdeftypes.GotoFlowNodeData"
+              },
+              {
+                "type": "object",
+                "properties": {
+                  "type": {
+                    "type": "string",
+                    "enum": [
+                      "action:goto_flow"
+                    ]
+                  }
+                },
+                "required": [
+                  "type"
+                ]
+              }
+            ]
+          }
+        ]
+      },
+      "flowdeftypes.NodeType": {
+        "type": "string"
+      },
+      "flowdeftypes.OrderEventNodeData": {
+        "type": "object",
+        "properties": {
+          "next_id": {
+            "type": "string",
+            "format": "int64"
+          },
+          "type": {
+            "$ref": "#/components/schemas/flowdeftypes.NodeType"
+          }
+        },
+        "required": [
+          "type"
+        ]
+      },
+      "flowdeftypes.QuickReplyItem": {
+        "type": "object",
+        "properties": {
+          "code": {
+            "type": "string"
+          },
+          "next_id": {
+            "type": "string",
+            "format": "int64"
+          },
+          "text": {
+            "type": "string"
+          }
+        },
+        "required": [
+          "text",
+          "code",
+          "next_id"
+        ]
+      },
+      "flowdeftypes.ReceivedMessageNodeData": {
+        "type": "object",
+        "properties": {
+          "next_id": {
+            "type": "string",
+            "format": "int64"
+          },
+          "type": {
+            "$ref": "#/components/schemas/flowdeftypes.NodeType"
+          }
+        },
+        "required": [
+          "type",
+          "next_id"
+        ]
+      },
+      "flowdeftypes.ReferralNodeData": {
+        "type": "object",
+        "properties": {
+          "next_id": {
+            "type": "string",
+            "format": "int64"
+          },
+          "ref": {
+            "type": "string"
+          },
+          "type": {
+            "$ref": "#/components/schemas/flowdeftypes.NodeType"
+          }
+        },
+        "required": [
+          "type",
+          "next_id"
+        ]
+      },
+      "flowdeftypes.ResponseVar": {
+        "type": "object",
+        "properties": {
+          "path": {
+            "type": "string"
+          },
+          "var_type": {
+            "$ref": "#/components/schemas/flowdeftypes.VarType"
+          },
+          "variable": {
+            "type": "string"
+          }
+        },
+        "required": [
+          "variable",
+          "path"
+        ]
+      },
+      "flowdeftypes.RestartPolicy": {
+        "type": "string",
+        "enum": [
+          "",
+          "reset_vars"
+        ]
+      },
+      "flowdeftypes.SendMessageNodeData": {
+        "type": "object",
+        "properties": {
+          "next_id": {
+            "type": "string",
+            "format": "int64"
+          },
+          "quick_replies": {
+            "type": "array",
+            "items": {
+              "$ref": "#/components/schemas/flowdeftypes.QuickReplyItem"
+            }
+          },
+          "template": {
+            "type": "string"
+          },
+          "timeout": {
+            "type": "string",
+            "description": "such as 30m, 24h or 3d"
+          },
+          "timeout_next_id": {
+            "type": "string",
+            "format": "int64"
+          },
+          "type": {
+            "$ref": "#/components/schemas/flowdeftypes.NodeType"
+          }
+        },
+        "required": [
+          "type",
+          "template",
+          "quick_replies"
+        ]
+      },
+      "flowdeftypes.SetVariableNodeData": {
+        "type": "object",
+        "properties": {
+          "assignments": {
+            "type": "array",
+            "items": {
+              "$ref": "#/components/schemas/flowdeftypes.Assignment"
+            }
+          },
+          "next_id": {
+            "type": "string",
+            "format": "int64"
+          },
+          "type": {
+            "$ref": "#/components/schemas/flowdeftypes.NodeType"
+          }
+        },
+        "required": [
+          "type",
+          "assignments"
+        ]
+      },
+      "flowdeftypes.StatusRoute": {
+        "type": "object",
+        "properties": {
+          "next_id": {
+            "type": "string",
+            "format": "int64"
+          },
+          "status": {
+            "type": "string"
+          }
+        },
+        "required": [
+          "status",
+          "next_id"
+        ]
+      },
+      "flowdeftypes.VarType": {
+        "type": "string",
+        "enum": [
+          "bool",
+          "email",
+          "number",
+          "phone",
+          "string"
+        ]
+      },
+      "flowdeftypes.WaitNodeData": {
+        "type": "object",
+        "properties": {
+          "delay": {
+            "type": "string",
+            "description": "such as 30m, 24h or 3d"
+          },
+          "next_id": {
+            "type": "string",
+            "format": "int64"
+          },
+          "type": {
+            "$ref": "#/components/schemas/flowdeftypes.NodeType"
+          }
+        },
+        "required": [
+          "type",
+          "delay"
+        ]
+      },
+      "flowexectypes.CustomerLink": {
+        "type": "object",
+        "properties": {
+          "created_at": {
+            "type": "string",
+            "description": "milliseconds from 1970"
+          },
+          "customer_ref": {
+            "type": "string"
+          },
+          "page_id": {
+            "type": "string",
+            "format": "int64"
+          },
+          "psid": {
+            "type": "string",
+            "format": "int64"
+          },
+          "source": {
+            "type": "string"
+          },
+          "updated_at": {
+            "type": "string",
+            "description": "milliseconds from 1970"
+          },
+          "user_ref": {
+            "type": "string"
+          },
+          "workspace_id": {
+            "type": "string",
+            "format": "int64"
+          }
+        },
+        "required": [
+          "page_id",
+          "created_at",
+          "updated_at"
+        ]
+      },
+      "flowexectypes.CustomerLinkResponse": {
+        "type": "object",
+        "properties": {
+          "link": {
+            "$ref": "#/components/schemas/flowexectypes.CustomerLink"
+          }
+        }
+      },
+      "flowexectypes.FlowEvent": {
+        "type": "object",
+        "properties": {
+          "created_at": {
+            "type": "string",
+            "description": "milliseconds from 1970"
+          },
+          "data": {
+            "type": "object",
+            "additionalProperties": {
+              "type": "string"
+            }
+          },
+          "flow_id": {
+            "type": "string",
+            "format": "int64"
+          },
+          "id": {
+            "type": "string",
+            "format": "int64"
+          },
+          "node_id": {
+            "type": "string",
+            "format": "int64"
+          },
+          "page_id": {
+            "type": "string",
+            "format": "int64"
+          },
+          "psid": {
+            "type": "string",
+            "format": "int64"
+          },
+          "type": {
+            "$ref": "#/components/schemas/flowexectypes.FlowEventType"
+          }
+        },
+        "required": [
+          "id",
+          "type",
+          "page_id",
+          "created_at"
+        ]
+      },
+      "flowexectypes.FlowEventType": {
+        "type": "string",
+        "enum": [
+          "conversation.started",
+          "flow.completed",
+          "handoff.requested",
+          "message.failed",
+          "message.sent",
+          "node.entered",
+          "review.submitted"
+        ]
+      },
+      "flowexectypes.GetCustomerLinkRequest": {
+        "type": "object",
+        "properties": {
+          "customer_ref": {
+            "type": "string"
+          },
+          "page_id": {
+            "type": "string",
+            "format": "int64"
+          },
+          "psid": {
+            "type": "string",
+            "format": "int64"
+          }
+        },
+        "required": [
+          "page_id",
+          "customer_ref",
+          "psid"
+        ]
+      },
+      "flowexectypes.GetHandoffRequest": {
+        "type": "object",
+        "properties": {
+          "page_id": {
+            "type": "string",
+            "format": "int64"
+          },
+          "psid": {
+            "type": "string",
+            "format": "int64"
+          }
+        },
+        "required": [
+          "page_id",
+          "psid"
+        ]
+      },
+      "flowexectypes.GetOrderRequest": {
+        "type": "object",
+        "properties": {
+          "order_id": {
+            "type": "string"
+          },
+          "page_id": {
+            "type": "string",
+            "format": "int64"
+          }
+        },
+        "required": [
+          "page_id",
+          "order_id"
+        ]
+      },
+      "flowexectypes.Handoff": {
+        "type": "object",
+        "properties": {
+          "created_at": {
+            "type": "string",
+            "description": "milliseconds from 1970"
+          },
+          "flow_id": {
+            "type": "string",
+            "format": "int64"
+          },
+          "id": {
+            "type": "string",
+            "format": "int64"
+          },
+          "messages": {
+            "type": "array",
+            "items": {
+              "$ref": "#/components/schemas/flowexectypes.HandoffMessage"
+            }
+          },
+          "node_id": {
+            "type": "string",
+            "format": "int64"
+          },
+          "page_id": {
+            "type": "string",
+            "format": "int64"
+          },
+          "psid": {
+            "type": "string",
+            "format": "int64"
+          },
+          "reason": {
+            "type": "string"
+          },
+          "resolved_at": {
+            "type": "string",
+            "description": "milliseconds from 1970"
+          },
+          "resolved_by": {
+            "type": "string"
+          },
+          "status": {
+            "$ref": "#/components/schemas/flowexectypes.HandoffStatus"
+          },
+          "thread_passed": {
+            "type": "boolean"
+          },
+          "updated_at": {
+            "type": "string",
+            "description": "milliseconds from 1970"
+          },
+          "workspace_id": {
+            "type": "string",
+            "format": "int64"
+          }
+        },
+        "required": [
+          "id",
+          "page_id",
+          "psid",
+          "flow_id",
+          "status",
+          "messages",
+          "created_at",
+          "updated_at"
+        ]
+      },
+      "flowexectypes.HandoffMessage": {
+        "type": "object",
+        "properties": {
+          "agent": {
+            "type": "string"
+          },
+          "created_at": {
+            "type": "string",
+            "description": "milliseconds from 1970"
+          },
+          "from": {
+            "$ref": "#/components/schemas/flowexectypes.MessageSender"
+          },
+          "text": {
+            "type": "string"
+          }
+        },
+        "required": [
+          "from",
+          "text",
+          "created_at"
+        ]
+      },
+      "flowexectypes.HandoffResponse": {
+        "type": "object",
+        "properties": {
+          "handoff": {
+            "$ref": "#/components/schemas/flowexectypes.Handoff"
+          }
+        }
+      },
+      "flowexectypes.HandoffStatus": {
+        "type": "string",
+        "enum": [
+          "open",
+          "resolved"
+        ]
+      },
+      "flowexectypes.LinkCustomerRequest": {
+        "type": "object",
+        "properties": {
+          "customer_ref": {
+            "type": "string"
+          },
+          "page_id": {
+            "type": "string",
+            "format": "int64"
+          },
+          "psid": {
+            "type": "string",
+            "format": "int64"
+          },
+          "source": {
+            "type": "string"
+          },
+          "user_ref": {
+            "type": "string"
+          }
+        },
+        "required": [
+          "page_id",
+          "customer_ref",
+          "psid",
+          "user_ref",
+          "source"
+        ]
+      },
+      "flowexectypes.ListHandoffsRequest": {
+        "type": "object",
+        "properties": {
+          "page_id": {
+            "type": "string",
+            "format": "int64"
+          },
+          "status": {
+            "$ref": "#/components/schemas/flowexectypes.HandoffStatus"
+          }
+        },
+        "required": [
+          "page_id",
+          "status"
+        ]
+      },
+      "flowexectypes.ListHandoffsResponse": {
+        "type": "object",
+        "properties": {
+          "handoffs": {
+            "type": "array",
+            "items": {
+              "$ref": "#/components/schemas/flowexectypes.Handoff"
+            }
+          }
+        },
+        "required": [
+          "handoffs"
+        ]
+      },
+      "flowexectypes.MessageSender": {
+        "type": "string",
+        "enum": [
+          "agent",
+          "customer",
+          "system"
+        ]
+      },
+      "flowexectypes.Order": {
+        "type": "object",
+        "properties": {
+          "amount": {
+            "type": "integer",
+            "format": "int64"
+          },
+          "created_at": {
+            "type": "string",
+            "description": "milliseconds from 1970"
+          },
+          "currency": {
+            "type": "string"
+          },
+          "customer_ref": {
+            "type": "string"
+          },
+          "desc": {
+            "type": "string"
+          },
+          "id": {
+            "type": "string"
+          },
+          "items": {
+            "type": "array",
+            "items": {
+              "$ref": "#/components/schemas/flowexectypes.OrderItem"
+            }
+          },
+          "page_id": {
+            "type": "string",
+            "format": "int64"
+          },
+          "psid": {
+            "type": "string",
+            "format": "int64"
+          },
+          "status": {
+            "$ref": "#/components/schemas/flowexectypes.OrderStatus"
+          },
+          "updated_at": {
+            "type": "string",
+            "description": "milliseconds from 1970"
+          },
+          "user_ref": {
+            "type": "string"
+          },
+          "workspace_id": {
+            "type": "string",
+            "format": "int64"
+          }
+        },
+        "required": [
+          "id",
+          "page_id",
+          "status",
+          "currency",
+          "amount",
+          "items",
+          "created_at",
+          "updated_at"
+        ]
+      },
+      "flowexectypes.OrderItem": {
+        "type": "object",
+        "properties": {
+          "name": {
+            "type": "string"
+          },
+          "price": {
+            "type": "integer",
+            "format": "int64"
+          },
+          "quantity": {
+            "type": "integer"
+          },
+          "sku": {
+            "type": "string"
+          }
+        },
+        "required": [
+          "name",
+          "quantity",
+          "price"
+        ]
+      },
+      "flowexectypes.OrderResponse": {
+        "type": "object",
+        "properties": {
+          "order": {
+            "$ref": "#/components/schemas/flowexectypes.Order"
+          }
+        }
+      },
+      "flowexectypes.OrderStatus": {
+        "type": "string",
+        "enum": [
+          "completed",
+          "created",
+          "paid",
+          "refunded",
+          "shipped"
+        ]
+      },
+      "flowexectypes.ReceivedCompletedOrderRequest": {
+        "type": "object",
+        "properties": {
+          "amount": {
+            "type": "integer",
+            "format": "int64"
+          },
+          "currency": {
+            "type": "string"
+          },
+          "customer_ref": {
+            "type": "string"
+          },
+          "desc": {
+            "type": "string"
+          },
+          "order_id": {
+            "type": "string"
+          },
+          "page_id": {
+            "type": "string",
+            "format": "int64"
+          },
+          "psid": {
+            "type": "string",
+            "format": "int64"
+          }
+        },
+        "required": [
+          "page_id",
+          "order_id",
+          "customer_ref",
+          "psid",
+          "desc",
+          "currency",
+          "amount"
+        ]
+      },
+      "flowexectypes.ReceivedCompletedOrderResponse": {
+        "type": "object"
+      },
+      "flowexectypes.ReceivedMessageRequest": {
+        "type": "object",
+        "properties": {
+          "message": {
+            "type": "string"
+          },
+          "page_id": {
+            "type": "string",
+            "format": "int64"
+          },
+          "psid": {
+            "type": "string",
+            "format": "int64"
+          }
+        },
+        "required": [
+          "page_id",
+          "psid",
+          "message"
+        ]
+      },
+      "flowexectypes.ReceivedMessageResponse": {
+        "type": "object"
+      },
+      "flowexectypes.ReceivedOrderEventRequest": {
+        "type": "object",
+        "properties": {
+          "amount": {
+            "type": "integer",
+            "format": "int64"
+          },
+          "currency": {
+            "type": "string"
+          },
+          "customer_ref": {
+            "type": "string"
+          },
+          "desc": {
+            "type": "string"
+          },
+          "idempotency_key": {
+            "type": "string"
+          },
+          "items": {
+            "type": "array",
+            "items": {
+              "$ref": "#/components/schemas/flowexectypes.OrderItem"
+            }
+          },
+          "order_id": {
+            "type": "string"
+          },
+          "page_id": {
+            "type": "string",
+            "format": "int64"
+          },
+          "psid": {
+            "type": "string",
+            "format": "int64"
+          },
+          "status": {
+            "$ref": "#/components/schemas/flowexectypes.OrderStatus"
+          },
+          "user_ref": {
+            "type": "string"
+          }
+        },
+        "required": [
+          "page_id",
+          "idempotency_key",
+          "order_id",
+          "customer_ref",
+          "psid",
+          "user_ref",
+          "status",
+          "currency",
+          "amount",
+          "items",
+          "desc"
+        ]
+      },
+      "flowexectypes.ReceivedOrderEventResponse": {
+        "type": "object",
+        "properties": {
+          "duplicated": {
+            "type": "boolean"
+          },
+          "order": {
+            "$ref": "#/components/schemas/flowexectypes.Order"
+          },
+          "ref": {
+            "type": "string"
+          }
+        },
+        "required": [
+          "duplicated"
+        ]
+      },
+      "flowexectypes.ReceivedPostbackRequest": {
+        "type": "object",
+        "properties": {
+          "page_id": {
+            "type": "string",
+            "format": "int64"
+          },
+          "postback_payload": {
+            "type": "string"
+          },
+          "postback_title": {
+            "type": "string"
+          },
+          "psid": {
+            "type": "string",
+            "format": "int64"
+          }
+        },
+        "required": [
+          "page_id",
+          "psid",
+          "postback_title",
+          "postback_payload"
+        ]
+      },
+      "flowexectypes.ReceivedPostbackResponse": {
+        "type": "object"
+      },
+      "flowexectypes.ReceivedReferralRequest": {
+        "type": "object",
+        "properties": {
+          "page_id": {
+            "type": "string",
+            "format": "int64"
+          },
+          "psid": {
+            "type": "string",
+            "format": "int64"
+          },
+          "ref": {
+            "type": "string"
+          },
+          "source": {
+            "type": "string"
+          },
+          "type": {
+            "type": "string"
+          }
+        },
+        "required": [
+          "page_id",
+          "psid",
+          "ref",
+          "source",
+          "type"
+        ]
+      },
+      "flowexectypes.ReceivedReferralResponse": {
+        "type": "object"
+      },
+      "flowexectypes.ReplyHandoffRequest": {
+        "type": "object",
+        "properties": {
+          "agent": {
+            "type": "string"
+          },
+          "page_id": {
+            "type": "string",
+            "format": "int64"
+          },
+          "psid": {
+            "type": "string",
+            "format": "int64"
+          },
+          "text": {
+            "type": "string"
+          }
+        },
+        "required": [
+          "page_id",
+          "psid",
+          "agent",
+          "text"
+        ]
+      },
+      "flowexectypes.ResolveHandoffRequest": {
+        "type": "object",
+        "properties": {
+          "agent": {
+            "type": "string"
+          },
+          "page_id": {
+            "type": "string",
+            "format": "int64"
+          },
+          "psid": {
+            "type": "string",
+            "format": "int64"
+          },
+          "resume_node_id": {
+            "type": "string",
+            "format": "int64"
+          }
+        },
+        "required": [
+          "page_id",
+          "psid",
+          "agent",
+          "resume_node_id"
+        ]
+      },
+      "flowexectypes.SimulateFlowRequest": {
+        "type": "object",
+        "properties": {
+          "flow": {
+            "$ref": "#/components/schemas/flowdeftypes.Flow"
+          },
+          "flow_id": {
+            "type": "string",
+            "format": "int64"
+          },
+          "steps": {
+            "type": "array",
+            "items": {
+              "$ref": "#/components/schemas/flowexectypes.SimulationStep"
+            }
+          }
+        },
+        "required": [
+          "flow_id",
+          "steps"
+        ]
+      },
+      "flowexectypes.SimulateFlowResponse": {
+        "type": "object",
+        "properties": {
+          "steps": {
+            "type": "array",
+            "items": {
+              "$ref": "#/components/schemas/flowexectypes.SimulationStepResult"
+            }
+          }
+        },
+        "required": [
+          "steps"
+        ]
+      },
+      "flowexectypes.SimulatedButton": {
+        "type": "object",
+        "properties": {
+          "payload": {
+            "type": "string"
+          },
+          "title": {
+            "type": "string"
+          }
+        },
+        "required": [
+          "title",
+          "payload"
+        ]
+      },
+      "flowexectypes.SimulatedMessage": {
+        "type": "object",
+        "properties": {
+          "action": {
+            "type": "string"
+          },
+          "buttons": {
+            "type": "array",
+            "items": {
+              "$ref": "#/components/schemas/flowexectypes.SimulatedButton"
+            }
+          },
+          "text": {
+            "type": "string"
+          }
+        }
+      },
+      "flowexectypes.SimulationEvent": {
+        "type": "string",
+        "enum": [
+          "message",
+          "order",
+          "postback",
+          "referral",
+          "wait"
+        ]
+      },
+      "flowexectypes.SimulationStep": {
+        "type": "object",
+        "properties": {
+          "delay": {
+            "type": "string",
+            "description": "such as 30m, 24h or 3d"
+          },
+          "event": {
+            "$ref": "#/components/schemas/flowexectypes.SimulationEvent"
+          },
+          "order_id": {
+            "type": "string"
+          },
+          "order_status": {
+            "$ref": "#/components/schemas/flowexectypes.OrderStatus"
+          },
+          "payload": {
+            "type": "string"
+          },
+          "text": {
+            "type": "string"
+          }
+        },
+        "required": [
+          "event"
+        ]
+      },
+      "flowexectypes.SimulationStepResult": {
+        "type": "object",
+        "properties": {
+          "error": {
+            "type": "string"
+          },
+          "from_node_id": {
+            "type": "string",
+            "format": "int64"
+          },
+          "messages": {
+            "type": "array",
+            "items": {
+              "$ref": "#/components/schemas/flowexectypes.SimulatedMessage"
+            }
+          },
+          "paused": {
+            "type": "boolean"
+          },
+          "step": {
+            "$ref": "#/components/schemas/flowexectypes.SimulationStep"
+          },
+          "to_flow_id": {
+            "type": "string",
+            "format": "int64"
+          },
+          "to_node_id": {
+            "type": "string",
+            "format": "int64"
+          },
+          "vars": {
+            "type": "object",
+            "additionalProperties": {
+              "type": "string"
+            }
+          }
+        },
+        "required": [
+          "from_node_id",
+          "to_node_id",
+          "messages",
+          "vars"
+        ]
+      },
+      "flowexectypes.StartHandoffRequest": {
+        "type": "object",
+        "properties": {
+          "page_id": {
+            "type": "string",
+            "format": "int64"
+          },
+          "psid": {
+            "type": "string",
+            "format": "int64"
+          },
+          "reason": {
+            "type": "string"
+          }
+        },
+        "required": [
+          "page_id",
+          "psid",
+          "reason"
+        ]
+      },
+      "flowexectypes.WatchEventsRequest": {
+        "type": "object",
+        "properties": {
+          "page_ids": {
+            "type": "array",
+            "items": {
+              "type": "string",
+              "format": "int64"
+            }
+          },
+          "types": {
+            "type": "array",
+            "items": {
+              "$ref": "#/components/schemas/flowexectypes.FlowEventType"
+            }
+          }
+        },
+        "required": [
+          "page_ids",
+          "types"
+        ]
+      }
+    }
+  }
+}`
@@ -4,19 +4,35 @@ import (
 	"context"
 	"encoding/json"
 	"net/http"
+	"path"
 	"time"
 
 	"github.com/go-resty/resty/v2"
 
 	"github.com/olvrng/rbot/be/pkg/l"
+	"github.com/olvrng/rbot/be/pkg/metrics"
 	"github.com/olvrng/rbot/be/pkg/xerrors"
 )
 
 var ll = l.New()
 var ls = ll.Sugar()
 
+// apiCallsTotal counts the calls by the last segment of their url, such as
+// "messages" for the Send API, and by result: "ok", "http_error" when the
+// platform rejects the call, or "network_error".
+var apiCallsTotal = metrics.NewCounter(
+	"rbot_messenger_api_calls_total",
+	"The calls of the Messenger Platform APIs, by api and result.",
+	"api", "result")
+
 const EndpointURL = "https://graph.facebook.com/v2.6/me/messages"
 
+// https://developers.facebook.com/docs/messenger-platform/handover-protocol
+const (
+	PassThreadControlURL = "https://graph.facebook.com/v2.6/me/pass_thread_control"
+	TakeThreadControlURL = "https://graph.facebook.com/v2.6/me/take_thread_control"
+)
+
 type Config struct {
 	VerifyToken     string
 	PageAccessToken string
@@ -52,7 +68,7 @@ func NewClient(cfg Config) (*Client, error) {
 	return c, nil
 }
 
-func (c *Client) callAPI(ctx context.Context, body interface{}) (*resty.Response, error) {
+func (c *Client) callAPI(ctx context.Context, url string, body interface{}) (*resty.Response, error) {
 
 	data, err := json.Marshal(body)
 	if err != nil {
@@ -63,15 +79,18 @@ func (c *Client) callAPI(ctx context.Context, body interface{}) (*resty.Response
 	r.SetHeader("Content-Type", "application/json")
 	r.SetQueryParam("access_token", c.PageAccessToken)
 	r.SetBody(data)
-	resp, err := r.Post(EndpointURL)
+	resp, err := r.Post(url)
 	if err != nil {
-		ll.Error("messenger: can not call api", l.String("url", EndpointURL), l.Error(err))
+		apiCallsTotal.Inc(path.Base(url), "network_error")
+		ll.Error("messenger: can not call api", l.String("url", url), l.Error(err))
 		return nil, err
 	}
 	if resp.StatusCode() >= 200 && resp.StatusCode() < 300 {
-		ll.Debug("messenger: call api successfully", l.String("url", EndpointURL))
+		apiCallsTotal.Inc(path.Base(url), "ok")
+		ll.Debug("messenger: call api successfully", l.String("url", url))
 		return resp, nil
 	}
+	apiCallsTotal.Inc(path.Base(url), "http_error")
 
 	ls.Errorf("messenger: call api error, code=%v request=%s response=%s", resp.StatusCode(), data, resp.String())
 	err = xerrors.Errorf(xerrors.Internal, nil, "messenger: response %v", resp.StatusCode())
@@ -79,6 +98,33 @@ func (c *Client) callAPI(ctx context.Context, body interface{}) (*resty.Response
 }
 
 func (c *Client) CallSendAPI(ctx context.Context, req *SendRequest) error {
-	_, err := c.callAPI(ctx, req)
+	_, err := c.SendMessage(ctx, req)
+	return err
+}
+
+// SendMessage calls the Send API. The response contains the PSID of the
+// recipient, which is useful when sending to a user_ref.
+func (c *Client) SendMessage(ctx context.Context, req *SendRequest) (*SendResponse, error) {
+	resp, err := c.callAPI(ctx, EndpointURL, req)
+	if err != nil {
+		return nil, err
+	}
+	var sendResp SendResponse
+	if err = json.Unmarshal(resp.Body(), &sendResp); err != nil {
+		return nil, xerrors.Errorf(xerrors.Internal, err, "messenger: can not decode response")
+	}
+	return &sendResp, nil
+}
+
+// PassThreadControl passes the conversation to another app, such as the Page
+// Inbox, so that a person can reply.
+func (c *Client) PassThreadControl(ctx context.Context, req *PassThreadControlRequest) error {
+	_, err := c.callAPI(ctx, PassThreadControlURL, req)
+	return err
+}
+
+// TakeThreadControl takes the conversation back from the app which controls it.
+func (c *Client) TakeThreadControl(ctx context.Context, req *TakeThreadControlRequest) error {
+	_, err := c.callAPI(ctx, TakeThreadControlURL, req)
 	return err
 }
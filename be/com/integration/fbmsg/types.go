@@ -83,11 +83,27 @@ type SendAttachmentData struct {
 	Payload *PayloadData       `json:"payload,omitempty"`
 }
 
+// https://developers.facebook.com/docs/messenger-platform/send-messages/quick-replies
 type QuickReplyItem struct {
-	ContentType string       `json:"content_type,omitempty"`
-	Title       string       `json:"title,omitempty"`
-	Payload     *PayloadData `json:"payload,omitempty"`
-	ImageURL    string       `json:"image_url,omitempty"`
+	ContentType ContentType `json:"content_type,omitempty"`
+	Title       string      `json:"title,omitempty"`
+	Payload     string      `json:"payload,omitempty"`
+	ImageURL    string      `json:"image_url,omitempty"`
+}
+
+// PageInboxAppID is the app id of the Page Inbox, for passing the conversation
+// to the people of the page.
+const PageInboxAppID IntID = 263902037430900
+
+type PassThreadControlRequest struct {
+	Recipient   *SendRecipientData `json:"recipient"`
+	TargetAppID IntID              `json:"target_app_id"`
+	Metadata    string             `json:"metadata,omitempty"`
+}
+
+type TakeThreadControlRequest struct {
+	Recipient *SendRecipientData `json:"recipient"`
+	Metadata  string             `json:"metadata,omitempty"`
 }
 
 type SendResponse struct {
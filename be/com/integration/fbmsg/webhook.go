@@ -22,6 +22,11 @@ type EntryMessaging struct {
 	Reaction  *ReactionData `json:"reaction,omitempty"`
 	Read      *ReadData     `json:"read,omitempty"`
 	Delivery  *DeliveryData `json:"delivery,omitempty"`
+	Referral  *ReferralData `json:"referral,omitempty"`
+	Optin     *OptinData    `json:"optin,omitempty"`
+
+	PassThreadControl *PassThreadControlData `json:"pass_thread_control,omitempty"`
+	TakeThreadControl *TakeThreadControlData `json:"take_thread_control,omitempty"`
 }
 
 type SenderID struct {
@@ -58,12 +63,16 @@ type ReactionData struct {
 	Reaction string `json:"reaction,omitempty"`
 }
 
+// ReadData tells that all the messages sent before the watermark are read.
 type ReadData struct {
-	Watermark IntID `json:"watermark,omitempty"`
+	Watermark Timestamp `json:"watermark,omitempty"`
 }
 
+// DeliveryData tells that the messages are delivered. MIDs may be missing, the
+// messages sent before the watermark are delivered.
 type DeliveryData struct {
-	MIDs []StrID `json:"mids"`
+	MIDs      []StrID   `json:"mids"`
+	Watermark Timestamp `json:"watermark,omitempty"`
 }
 
 type QuickReplyData struct {
@@ -84,6 +93,9 @@ type AttachmentPayloadData struct {
 }
 
 type MessageReferralData struct {
+	Ref    string `json:"ref,omitempty"`
+	Source string `json:"source,omitempty"`
+	Type   string `json:"type,omitempty"`
 }
 
 type PostbackReferralData struct {
@@ -93,3 +105,40 @@ type PostbackReferralData struct {
 	RefererURI  string `json:"referer_uri"`
 	IsGuestUser string `json:"is_guest_user"`
 }
+
+// ReferralData is sent with messaging_referrals events, when an existing
+// conversation is opened from an m.me link.
+//
+// https://developers.facebook.com/docs/messenger-platform/reference/webhook-events/messaging_referrals
+type ReferralData struct {
+	Ref         string `json:"ref"`
+	Source      string `json:"source"`
+	Type        string `json:"type"`
+	RefererURI  string `json:"referer_uri,omitempty"`
+	IsGuestUser string `json:"is_guest_user,omitempty"`
+}
+
+// OptinData is sent with messaging_optins events, for example when the customer
+// ticks the checkbox plugin. The sender only has user_ref, not the PSID.
+//
+// https://developers.facebook.com/docs/messenger-platform/reference/webhook-events/messaging_optins
+type OptinData struct {
+	Ref     string `json:"ref,omitempty"`
+	UserRef string `json:"user_ref,omitempty"`
+}
+
+// PassThreadControlData is sent when another app passes the conversation to
+// this app.
+//
+// https://developers.facebook.com/docs/messenger-platform/reference/webhook-events/messaging_handovers
+type PassThreadControlData struct {
+	NewOwnerAppID IntID  `json:"new_owner_app_id"`
+	Metadata      string `json:"metadata,omitempty"`
+}
+
+// TakeThreadControlData is sent when another app takes the conversation from
+// this app.
+type TakeThreadControlData struct {
+	PreviousOwnerAppID IntID  `json:"previous_owner_app_id"`
+	Metadata           string `json:"metadata,omitempty"`
+}
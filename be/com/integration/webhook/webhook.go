@@ -9,29 +9,60 @@ import (
 	"io/ioutil"
 	"net/http"
 
+	conversationservice "github.com/olvrng/rbot/be/com/conversation/service"
+	conversationtypes "github.com/olvrng/rbot/be/com/conversation/types"
 	"github.com/olvrng/rbot/be/com/flowexec/service"
 	"github.com/olvrng/rbot/be/com/flowexec/types"
 	"github.com/olvrng/rbot/be/com/integration/fbmsg"
+	"github.com/olvrng/rbot/be/com/workspace"
+	workspacetypes "github.com/olvrng/rbot/be/com/workspace/types"
 	"github.com/olvrng/rbot/be/pkg/l"
+	"github.com/olvrng/rbot/be/pkg/metrics"
+	"github.com/olvrng/rbot/be/pkg/xerrors"
 )
 
 var ll = l.New()
 var ls = ll.Sugar()
 
+// eventsTotal counts the messaging events by type, such as "message" or
+// "postback", and by result: "ok" or "error".
+var eventsTotal = metrics.NewCounter(
+	"rbot_webhook_events_total",
+	"The messaging events of the Messenger webhook, by type and result.",
+	"type", "result")
+
+// WorkspaceResolver returns the workspace which owns the page, see the
+// workspace store.
+type WorkspaceResolver interface {
+	GetWorkspaceByPage(ctx context.Context, pageID fbmsg.IntID) (*workspacetypes.Workspace, error)
+}
+
 type WebhookService struct {
 	Client           *fbmsg.Client
 	VerifyToken      string
 	MessengerService *service.MessengerService
+	CustomerService  *service.CustomerService
+	HandoffService   *service.HandoffService
+	Conversations    *conversationservice.ConversationService
+	Workspaces       WorkspaceResolver
 }
 
 func NewWebhookService(
 	client *fbmsg.Client, token string,
 	messengerService *service.MessengerService,
+	customerService *service.CustomerService,
+	handoffService *service.HandoffService,
+	conversations *conversationservice.ConversationService,
+	workspaces WorkspaceResolver,
 ) *WebhookService {
 	s := &WebhookService{
 		Client:           client,
 		VerifyToken:      token,
 		MessengerService: messengerService,
+		CustomerService:  customerService,
+		HandoffService:   handoffService,
+		Conversations:    conversations,
+		Workspaces:       workspaces,
 	}
 	return s
 }
@@ -87,7 +118,6 @@ func (s *WebhookService) HandleWebhook(w http.ResponseWriter, req *http.Request)
 		return
 	}
 
-	var ctx = req.Context()
 	var err error
 	switch body.Object {
 	case "page":
@@ -96,22 +126,66 @@ func (s *WebhookService) HandleWebhook(w http.ResponseWriter, req *http.Request)
 				continue
 			}
 			pageID := entry.ID
+			ctx, err2 := s.pageContext(req.Context(), pageID)
+			if err2 != nil {
+				ll.Error("webhook: can not get workspace", l.ID("page_id", pageID), l.Error(err2))
+				err = err2
+				continue
+			}
 			// Get the webhook event. entry.messaging is an array, but
 			// will only ever contain one event, so we get index 0
 			event := entry.Messaging[0]
 			senderPSID := event.Sender.ID
 			ll.Debug("received webhook", l.ID("senderPSID", senderPSID))
+			if err = s.RecordMessage(ctx, pageID, event); err != nil {
+				ll.Error("webhook: can not record message", l.Error(err))
+			}
 
+			typ := "ignored"
 			switch {
 			case event.Message != nil:
+				typ = "message"
 				err = s.HandleMessage(ctx, pageID, event.Sender, event.Message)
 
+			case event.Postback != nil && event.Postback.Referral != nil:
+				// the Get Started button of a new conversation from an m.me link
+				ref := event.Postback.Referral
+				typ = "referral"
+				err = s.HandleReferral(ctx, pageID, event.Sender, ref.Ref, ref.Source, ref.Type)
+
 			case event.Postback != nil:
+				typ = "postback"
 				err = s.HandlePostback(ctx, pageID, event.Sender, event.Postback)
 
+			case event.Referral != nil:
+				ref := event.Referral
+				typ = "referral"
+				err = s.HandleReferral(ctx, pageID, event.Sender, ref.Ref, ref.Source, ref.Type)
+
+			case event.Optin != nil:
+				typ = "optin"
+				err = s.HandleOptin(ctx, pageID, event.Sender, event.Optin)
+
+			case event.Delivery != nil:
+				typ = "delivery"
+				err = s.HandleDelivery(ctx, pageID, event.Sender, event.Delivery)
+
+			case event.Read != nil:
+				typ = "read"
+				err = s.Conversations.MarkRead(ctx, pageID, event.Sender.ID, event.Read.Watermark)
+
+			case event.PassThreadControl != nil:
+				typ = "pass_thread_control"
+				err = s.HandlePassThreadControl(ctx, pageID, event.Sender, event.PassThreadControl)
+
 			default:
 				ll.Debug("webhook: ignore message", l.ID("entry.id", entry.ID))
 			}
+			if err != nil {
+				eventsTotal.Inc(typ, "error")
+			} else {
+				eventsTotal.Inc(typ, "ok")
+			}
 		}
 
 	default:
@@ -127,12 +201,41 @@ func (s *WebhookService) HandleWebhook(w http.ResponseWriter, req *http.Request)
 	w.WriteHeader(200)
 }
 
+// pageContext binds the context to the workspace of the page. The pages which
+// are not bound to a workspace use the default one.
+func (s *WebhookService) pageContext(ctx context.Context, pageID fbmsg.IntID) (context.Context, error) {
+	if s.Workspaces == nil {
+		return ctx, nil
+	}
+	w, err := s.Workspaces.GetWorkspaceByPage(ctx, pageID)
+	switch {
+	case err == nil:
+		return workspace.WithID(ctx, w.ID), nil
+	case xerrors.GetCode(err) == xerrors.NotFound:
+		return workspace.WithID(ctx, workspace.DefaultID), nil
+	default:
+		return nil, err
+	}
+}
+
 func (s *WebhookService) HandleMessage(ctx context.Context, pageID fbmsg.IntID, sender fbmsg.SenderID, msg *fbmsg.MessageData) error {
 	if msg.IsEcho {
 		ll.Debug("webhook: echo message, ignore")
 		return nil
 	}
 
+	if msg.QuickReply != nil {
+		// quick replies are handled like postback buttons
+		req := &types.ReceivedPostbackRequest{
+			PageID:          pageID,
+			PSID:            sender.ID,
+			PostbackTitle:   msg.Text,
+			PostbackPayload: msg.QuickReply.Payload,
+		}
+		_, err := s.MessengerService.ReceivedPostback(ctx, req)
+		return err
+	}
+
 	req := &types.ReceivedMessageRequest{
 		PageID:  pageID,
 		PSID:    sender.ID,
@@ -153,3 +256,74 @@ func (s *WebhookService) HandlePostback(ctx context.Context, pageID fbmsg.IntID,
 	_, err := s.MessengerService.ReceivedPostback(ctx, req)
 	return err
 }
+
+func (s *WebhookService) HandleReferral(ctx context.Context, pageID fbmsg.IntID, sender fbmsg.SenderID, ref, source, typ string) error {
+	req := &types.ReceivedReferralRequest{
+		PageID: pageID,
+		PSID:   sender.ID,
+		Ref:    ref,
+		Source: source,
+		Type:   typ,
+	}
+	_, err := s.MessengerService.ReceivedReferral(ctx, req)
+	return err
+}
+
+func (s *WebhookService) HandleOptin(ctx context.Context, pageID fbmsg.IntID, sender fbmsg.SenderID, optin *fbmsg.OptinData) error {
+	userRef := optin.UserRef
+	if userRef == "" {
+		userRef = sender.UserRef
+	}
+	return s.CustomerService.LinkOptin(ctx, pageID, userRef, optin.Ref)
+}
+
+// RecordMessage records the message or postback from the customer in the
+// transcript.
+func (s *WebhookService) RecordMessage(ctx context.Context, pageID fbmsg.IntID, event *fbmsg.EntryMessaging) error {
+	msg := &conversationtypes.Message{
+		PageID:    pageID,
+		PSID:      event.Sender.ID,
+		Direction: conversationtypes.Inbound,
+		CreatedAt: event.Timestamp,
+	}
+	switch {
+	case event.Message != nil && !event.Message.IsEcho:
+		msg.MID, msg.Text = string(event.Message.MID), event.Message.Text
+		if event.Message.QuickReply != nil {
+			msg.Payload = event.Message.QuickReply.Payload
+		}
+		if msg.Text == "" && len(event.Message.Attachments) != 0 {
+			msg.Text = fmt.Sprintf("[%v]", event.Message.Attachments[0].Type)
+		}
+
+	case event.Postback != nil:
+		msg.MID, msg.Text, msg.Payload = string(event.Postback.MID), event.Postback.Title, event.Postback.Payload
+
+	default:
+		return nil
+	}
+	return s.Conversations.RecordMessage(ctx, msg)
+}
+
+func (s *WebhookService) HandleDelivery(ctx context.Context, pageID fbmsg.IntID, sender fbmsg.SenderID, delivery *fbmsg.DeliveryData) error {
+	mids := make([]string, len(delivery.MIDs))
+	for i, mid := range delivery.MIDs {
+		mids[i] = string(mid)
+	}
+	return s.Conversations.MarkDelivered(ctx, pageID, sender.ID, mids, delivery.Watermark)
+}
+
+// HandlePassThreadControl resolves the handoff when the page inbox passes the
+// conversation back to the bot.
+func (s *WebhookService) HandlePassThreadControl(ctx context.Context, pageID fbmsg.IntID, sender fbmsg.SenderID, data *fbmsg.PassThreadControlData) error {
+	req := &types.ResolveHandoffRequest{
+		PageID: pageID,
+		PSID:   sender.ID,
+		Agent:  "page_inbox",
+	}
+	_, err := s.HandoffService.ResolveHandoff(ctx, req)
+	if xerrors.GetCode(err) == xerrors.NotFound {
+		return nil // not paused by the bot
+	}
+	return err
+}
@@ -0,0 +1,23 @@
+package review
+
+import (
+	"context"
+
+	"github.com/olvrng/rbot/be/com/review/types"
+)
+
+// +gen:api
+
+// +api:path=/api/review
+type ReviewService interface {
+	SubmitReview(ctx context.Context, req *types.SubmitReviewRequest) (*types.ReviewResponse, error)
+
+	// +api:get
+	ListReviews(ctx context.Context, req *types.ListReviewsRequest) (*types.ListReviewsResponse, error)
+
+	// +api:get
+	GetReviewSummary(ctx context.Context, req *types.GetReviewSummaryRequest) (*types.ReviewSummaryResponse, error)
+
+	// +api:get
+	ExportReviews(ctx context.Context, req *types.ExportReviewsRequest) (*types.ExportReviewsResponse, error)
+}
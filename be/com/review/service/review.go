@@ -0,0 +1,194 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"math"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/olvrng/rbot/be/com/review"
+	"github.com/olvrng/rbot/be/com/review/store"
+	"github.com/olvrng/rbot/be/com/review/types"
+	"github.com/olvrng/rbot/be/pkg/dot"
+	"github.com/olvrng/rbot/be/pkg/l"
+	"github.com/olvrng/rbot/be/pkg/xerrors"
+)
+
+var ll = l.New()
+
+var _ review.ReviewService = (*ReviewService)(nil)
+
+type ReviewService struct {
+	Store *store.ReviewStore
+}
+
+func NewReviewService(reviewStore *store.ReviewStore) *ReviewService {
+	s := &ReviewService{Store: reviewStore}
+	return s
+}
+
+func (s *ReviewService) SubmitReview(ctx context.Context, req *types.SubmitReviewRequest) (*types.ReviewResponse, error) {
+	if req.PageID == 0 {
+		return nil, xerrors.Errorf(xerrors.InvalidArgument, nil, "page_id is required")
+	}
+	if req.Rating < 1 || req.Rating > 5 {
+		return nil, xerrors.Errorf(xerrors.InvalidArgument, nil, "rating must be from 1 to 5")
+	}
+
+	now := dot.Now()
+	r, err := s.Store.FindReview(ctx, req.PageID, req.PSID, req.OrderID)
+	switch {
+	case err == nil && req.OrderID != "":
+		// the customer reviews the same order again, update the review
+	case err == nil || xerrors.GetCode(err) == xerrors.NotFound:
+		r = &types.Review{
+			ID:        dot.NewIntID(),
+			PageID:    req.PageID,
+			PSID:      req.PSID,
+			OrderID:   req.OrderID,
+			CreatedAt: now,
+		}
+	default:
+		return nil, xerrors.Errorf(xerrors.Internal, err, "internal error")
+	}
+	r.FlowID = req.FlowID
+	r.NodeID = req.NodeID
+	r.CustomerRef = req.CustomerRef
+	r.Rating = req.Rating
+	r.Comment = req.Comment
+	r.UpdatedAt = now
+	if err = s.Store.SaveReview(ctx, r); err != nil {
+		return nil, xerrors.Errorf(xerrors.Internal, err, "internal error")
+	}
+	return &types.ReviewResponse{Review: r}, nil
+}
+
+func (s *ReviewService) ListReviews(ctx context.Context, req *types.ListReviewsRequest) (*types.ListReviewsResponse, error) {
+	if req.PageID == 0 {
+		return nil, xerrors.Errorf(xerrors.InvalidArgument, nil, "page_id is required")
+	}
+	reviews, err := s.Store.ListReviews(ctx, req.PageID, func(r *types.Review) bool {
+		return (req.OrderID == "" || r.OrderID == req.OrderID) &&
+			(req.MinRating == 0 || r.Rating >= req.MinRating) &&
+			(req.MaxRating == 0 || r.Rating <= req.MaxRating)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &types.ListReviewsResponse{Reviews: reviews}, nil
+}
+
+func (s *ReviewService) GetReviewSummary(ctx context.Context, req *types.GetReviewSummaryRequest) (*types.ReviewSummaryResponse, error) {
+	if req.PageID == 0 {
+		return nil, xerrors.Errorf(xerrors.InvalidArgument, nil, "page_id is required")
+	}
+	reviews, err := s.Store.ListReviews(ctx, req.PageID, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &types.ReviewSummaryResponse{
+		PageID:       req.PageID,
+		Count:        len(reviews),
+		Distribution: make([]int, 5),
+	}
+	sum := 0
+	for _, r := range reviews {
+		sum += r.Rating
+		resp.Distribution[r.Rating-1]++
+	}
+	if len(reviews) != 0 {
+		avg := float64(sum) / float64(len(reviews))
+		resp.Average = math.Round(avg*100) / 100
+	}
+	return resp, nil
+}
+
+func (s *ReviewService) ExportReviews(ctx context.Context, req *types.ExportReviewsRequest) (*types.ExportReviewsResponse, error) {
+	listResp, err := s.ListReviews(ctx, &types.ListReviewsRequest{PageID: req.PageID})
+	if err != nil {
+		return nil, err
+	}
+	reviews := listResp.Reviews
+	if reviews == nil {
+		reviews = []*types.Review{}
+	}
+
+	resp := &types.ExportReviewsResponse{Format: req.Format}
+	switch req.Format {
+	case types.ExportJSON:
+		out, err := json.MarshalIndent(reviews, "", "  ")
+		if err != nil {
+			return nil, xerrors.Errorf(xerrors.Internal, err, "internal error")
+		}
+		resp.ContentType, resp.Content = "application/json", string(out)
+
+	case types.ExportCSV, "":
+		out, err := encodeCSV(reviews)
+		if err != nil {
+			return nil, xerrors.Errorf(xerrors.Internal, err, "internal error")
+		}
+		resp.Format, resp.ContentType, resp.Content = types.ExportCSV, "text/csv", string(out)
+
+	default:
+		return nil, xerrors.Errorf(xerrors.InvalidArgument, nil, "unsupported format %q", req.Format)
+	}
+	return resp, nil
+}
+
+// HandleExport downloads the reviews as a file:
+//
+//	GET /api/review/export?page_id=<id>&format=csv|json
+func (s *ReviewService) HandleExport(w http.ResponseWriter, req *http.Request) {
+	pageID, err := strconv.ParseInt(req.URL.Query().Get("page_id"), 10, 64)
+	if err != nil {
+		http.Error(w, "invalid page_id", http.StatusBadRequest)
+		return
+	}
+	exportReq := &types.ExportReviewsRequest{
+		PageID: dot.IntID(pageID),
+		Format: types.ExportFormat(req.URL.Query().Get("format")),
+	}
+	resp, err := s.ExportReviews(req.Context(), exportReq)
+	if err != nil {
+		ll.Error("export reviews", l.Error(err))
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	filename := fmt.Sprintf("reviews-%v.%v", pageID, resp.Format)
+	w.Header().Set("Content-Type", resp.ContentType)
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", filename))
+	_, _ = w.Write([]byte(resp.Content))
+}
+
+func encodeCSV(reviews []*types.Review) ([]byte, error) {
+	var b bytes.Buffer
+	w := csv.NewWriter(&b)
+	header := []string{"id", "page_id", "flow_id", "psid", "order_id", "customer_ref", "rating", "comment", "created_at"}
+	if err := w.Write(header); err != nil {
+		return nil, err
+	}
+	for _, r := range reviews {
+		row := []string{
+			strconv.FormatInt(int64(r.ID), 10),
+			strconv.FormatInt(int64(r.PageID), 10),
+			strconv.FormatInt(int64(r.FlowID), 10),
+			strconv.FormatInt(int64(r.PSID), 10),
+			r.OrderID,
+			r.CustomerRef,
+			strconv.Itoa(r.Rating),
+			r.Comment,
+			r.CreatedAt.ToTime().UTC().Format(time.RFC3339),
+		}
+		if err := w.Write(row); err != nil {
+			return nil, err
+		}
+	}
+	w.Flush()
+	return b.Bytes(), w.Error()
+}
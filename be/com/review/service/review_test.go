@@ -0,0 +1,141 @@
+package service
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/olvrng/rbot/be/com/review/store"
+	"github.com/olvrng/rbot/be/com/review/types"
+	"github.com/olvrng/rbot/be/com/workspace"
+	"github.com/olvrng/rbot/be/pkg/dot"
+	"github.com/olvrng/rbot/be/pkg/xerrors"
+)
+
+const testPageID = 1000
+
+func newTestReviewService(t *testing.T) *ReviewService {
+	reviewStore, err := store.NewReviewStore("")
+	require.NoError(t, err)
+	return NewReviewService(reviewStore)
+}
+
+func submit(t *testing.T, s *ReviewService, ctx context.Context, psid dot.IntID, orderID string, rating int, comment string) *types.Review {
+	req := &types.SubmitReviewRequest{
+		PageID:  testPageID,
+		PSID:    psid,
+		OrderID: orderID,
+		Rating:  rating,
+		Comment: comment,
+	}
+	resp, err := s.SubmitReview(ctx, req)
+	require.NoError(t, err)
+	return resp.Review
+}
+
+func TestSubmitReview(t *testing.T) {
+	ctx := context.Background()
+	s := newTestReviewService(t)
+
+	for _, rating := range []int{0, 6, -1} {
+		_, err := s.SubmitReview(ctx, &types.SubmitReviewRequest{PageID: testPageID, PSID: 1, Rating: rating})
+		require.Equal(t, xerrors.InvalidArgument, xerrors.GetCode(err))
+	}
+	_, err := s.SubmitReview(ctx, &types.SubmitReviewRequest{PSID: 1, Rating: 5})
+	require.Equal(t, xerrors.InvalidArgument, xerrors.GetCode(err))
+
+	// the customer reviews the same order again
+	r1 := submit(t, s, ctx, 1, "o1", 2, "late")
+	r2 := submit(t, s, ctx, 1, "o1", 4, "ok")
+	require.Equal(t, r1.ID, r2.ID)
+
+	// the reviews without order are kept apart
+	r3 := submit(t, s, ctx, 1, "", 5, "")
+	r4 := submit(t, s, ctx, 1, "", 3, "")
+	require.NotEqual(t, r3.ID, r4.ID)
+
+	resp, err := s.ListReviews(ctx, &types.ListReviewsRequest{PageID: testPageID})
+	require.NoError(t, err)
+	require.Len(t, resp.Reviews, 3)
+	resp, err = s.ListReviews(ctx, &types.ListReviewsRequest{PageID: testPageID, MinRating: 4})
+	require.NoError(t, err)
+	require.Len(t, resp.Reviews, 2)
+	resp, err = s.ListReviews(ctx, &types.ListReviewsRequest{PageID: testPageID, OrderID: "o1"})
+	require.NoError(t, err)
+	require.Len(t, resp.Reviews, 1)
+	require.Equal(t, "ok", resp.Reviews[0].Comment)
+}
+
+func TestGetReviewSummary(t *testing.T) {
+	ctx := context.Background()
+	s := newTestReviewService(t)
+
+	resp, err := s.GetReviewSummary(ctx, &types.GetReviewSummaryRequest{PageID: testPageID})
+	require.NoError(t, err)
+	require.Zero(t, resp.Count)
+	require.Zero(t, resp.Average)
+	require.Equal(t, []int{0, 0, 0, 0, 0}, resp.Distribution)
+
+	submit(t, s, ctx, 1, "o1", 5, "")
+	submit(t, s, ctx, 2, "o2", 4, "")
+	submit(t, s, ctx, 3, "o3", 4, "")
+	// the reviews of another workspace are not counted
+	submit(t, s, workspace.WithID(ctx, 7), 4, "o4", 1, "")
+
+	resp, err = s.GetReviewSummary(ctx, &types.GetReviewSummaryRequest{PageID: testPageID})
+	require.NoError(t, err)
+	require.Equal(t, 3, resp.Count)
+	require.Equal(t, 4.33, resp.Average)
+	require.Equal(t, []int{0, 0, 0, 2, 1}, resp.Distribution)
+}
+
+func TestExportReviews(t *testing.T) {
+	ctx := context.Background()
+	s := newTestReviewService(t)
+
+	t.Run("no reviews", func(t *testing.T) {
+		resp, err := s.ExportReviews(ctx, &types.ExportReviewsRequest{PageID: testPageID})
+		require.NoError(t, err)
+		require.Equal(t, types.ExportCSV, resp.Format)
+		require.Equal(t, "id,page_id,flow_id,psid,order_id,customer_ref,rating,comment,created_at\n", resp.Content)
+
+		resp, err = s.ExportReviews(ctx, &types.ExportReviewsRequest{PageID: testPageID, Format: types.ExportJSON})
+		require.NoError(t, err)
+		require.Equal(t, "[]", resp.Content)
+	})
+
+	r := submit(t, s, ctx, 1, "o1", 4, "good, \"fast\"\nthanks")
+
+	t.Run("csv", func(t *testing.T) {
+		resp, err := s.ExportReviews(ctx, &types.ExportReviewsRequest{PageID: testPageID, Format: types.ExportCSV})
+		require.NoError(t, err)
+		require.Equal(t, "text/csv", resp.ContentType)
+
+		rows, err := csv.NewReader(strings.NewReader(resp.Content)).ReadAll()
+		require.NoError(t, err)
+		require.Len(t, rows, 2)
+		require.Equal(t, []string{"o1", "", "4", "good, \"fast\"\nthanks"}, rows[1][4:8])
+		require.Equal(t, r.CreatedAt.ToTime().UTC().Format(time.RFC3339), rows[1][8])
+	})
+
+	t.Run("json", func(t *testing.T) {
+		resp, err := s.ExportReviews(ctx, &types.ExportReviewsRequest{PageID: testPageID, Format: types.ExportJSON})
+		require.NoError(t, err)
+		require.Equal(t, "application/json", resp.ContentType)
+
+		var reviews []*types.Review
+		require.NoError(t, json.Unmarshal([]byte(resp.Content), &reviews))
+		require.Len(t, reviews, 1)
+		require.Equal(t, r.ID, reviews[0].ID)
+	})
+
+	t.Run("unsupported format", func(t *testing.T) {
+		_, err := s.ExportReviews(ctx, &types.ExportReviewsRequest{PageID: testPageID, Format: "xlsx"})
+		require.Equal(t, xerrors.InvalidArgument, xerrors.GetCode(err))
+	})
+}
@@ -0,0 +1,115 @@
+package store
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"sort"
+	"sync"
+
+	"github.com/olvrng/rbot/be/com/review/types"
+	"github.com/olvrng/rbot/be/com/workspace"
+	"github.com/olvrng/rbot/be/pkg/dot"
+	"github.com/olvrng/rbot/be/pkg/xerrors"
+)
+
+type ReviewFile struct {
+	Reviews []*types.Review `json:"reviews"`
+}
+
+type ReviewStore struct {
+	FilePath string
+	Data     *ReviewFile
+
+	m sync.Mutex
+}
+
+func NewReviewStore(filePath string) (*ReviewStore, error) {
+	s := &ReviewStore{
+		FilePath: filePath,
+	}
+
+	_, err := os.Stat(filePath)
+	switch {
+	case err == nil: // load from storage
+		data, err := ioutil.ReadFile(filePath)
+		if err != nil {
+			return nil, err
+		}
+		err = json.Unmarshal(data, &s.Data)
+		return s, err
+
+	case os.IsNotExist(err): // try creating one
+		s.Data = &ReviewFile{}
+		err = storeFile(filePath, s.Data)
+		return s, err
+
+	default:
+		return nil, err
+	}
+}
+
+// SaveReview creates the review, or replaces the review with the same id.
+func (s *ReviewStore) SaveReview(ctx context.Context, review *types.Review) error {
+	s.m.Lock()
+	defer s.m.Unlock()
+
+	for i, r := range s.Data.Reviews {
+		if r.ID == review.ID {
+			if err := workspace.CheckOwner(ctx, r.WorkspaceID); err != nil {
+				return err
+			}
+			review.WorkspaceID = r.WorkspaceID
+			s.Data.Reviews[i] = review
+			return storeFile(s.FilePath, s.Data)
+		}
+	}
+	review.WorkspaceID = workspace.GetID(ctx)
+	s.Data.Reviews = append(s.Data.Reviews, review)
+	return storeFile(s.FilePath, s.Data)
+}
+
+// FindReview returns the review of the customer for the order.
+func (s *ReviewStore) FindReview(ctx context.Context, pageID, psid dot.IntID, orderID string) (*types.Review, error) {
+	s.m.Lock()
+	defer s.m.Unlock()
+
+	for _, r := range s.Data.Reviews {
+		if r.PageID == pageID && r.PSID == psid && r.OrderID == orderID && workspace.Match(ctx, r.WorkspaceID) {
+			return r, nil
+		}
+	}
+	return nil, xerrors.Errorf(xerrors.NotFound, nil, "review not found")
+}
+
+// ListReviews returns the reviews of the page which pass the filter, newest
+// first.
+func (s *ReviewStore) ListReviews(ctx context.Context, pageID dot.IntID, filter func(*types.Review) bool) ([]*types.Review, error) {
+	s.m.Lock()
+	defer s.m.Unlock()
+
+	var result []*types.Review
+	for _, r := range s.Data.Reviews {
+		if r.PageID == pageID && workspace.Match(ctx, r.WorkspaceID) && (filter == nil || filter(r)) {
+			result = append(result, r)
+		}
+	}
+	sort.SliceStable(result, func(i, j int) bool {
+		return result[i].CreatedAt > result[j].CreatedAt
+	})
+	return result, nil
+}
+
+// storeFile writes the data to the file. The store is kept in memory only when
+// the file path is empty, for example when simulating flows.
+func storeFile(filePath string, data interface{}) error {
+	if filePath == "" {
+		return nil
+	}
+	out, err := json.MarshalIndent(data, "", "\t")
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(filePath, out, 0644)
+}
@@ -0,0 +1,87 @@
+package types
+
+import "github.com/olvrng/rbot/be/pkg/dot"
+
+type ExportFormat string
+
+const (
+	ExportCSV  ExportFormat = "csv"
+	ExportJSON ExportFormat = "json"
+)
+
+// Review is collected by an ask_rating node. It is linked to the order which
+// triggered the flow, if any.
+type Review struct {
+	ID          dot.IntID `json:"id"`
+	WorkspaceID dot.IntID `json:"workspace_id,omitempty"`
+	PageID      dot.IntID `json:"page_id"`
+	FlowID      dot.IntID `json:"flow_id"`
+	NodeID      dot.IntID `json:"node_id"`
+	PSID        dot.IntID `json:"psid"`
+
+	OrderID     string `json:"order_id,omitempty"`
+	CustomerRef string `json:"customer_ref,omitempty"`
+
+	// Rating is from 1 to 5.
+	Rating  int    `json:"rating"`
+	Comment string `json:"comment,omitempty"`
+
+	CreatedAt dot.Timestamp `json:"created_at"`
+	UpdatedAt dot.Timestamp `json:"updated_at"`
+}
+
+// SubmitReviewRequest creates a review, or updates the review of the same
+// customer for the same order.
+type SubmitReviewRequest struct {
+	// +validate:required
+	PageID      dot.IntID `json:"page_id"`
+	FlowID      dot.IntID `json:"flow_id"`
+	NodeID      dot.IntID `json:"node_id"`
+	PSID        dot.IntID `json:"psid"`
+	OrderID     string    `json:"order_id"`
+	CustomerRef string    `json:"customer_ref"`
+	// +validate:required +validate:min=1 +validate:max=5
+	Rating int `json:"rating"`
+	// +validate:max-len=2000
+	Comment string `json:"comment"`
+}
+
+type ReviewResponse struct {
+	Review *Review `json:"review"`
+}
+
+type ListReviewsRequest struct {
+	PageID    dot.IntID `json:"page_id"`
+	OrderID   string    `json:"order_id"`
+	MinRating int       `json:"min_rating"`
+	MaxRating int       `json:"max_rating"`
+}
+
+type ListReviewsResponse struct {
+	Reviews []*Review `json:"reviews"`
+}
+
+type GetReviewSummaryRequest struct {
+	PageID dot.IntID `json:"page_id"`
+}
+
+type ReviewSummaryResponse struct {
+	PageID  dot.IntID `json:"page_id"`
+	Count   int       `json:"count"`
+	Average float64   `json:"average"`
+
+	// Distribution counts the reviews by rating: Distribution[0] is the number
+	// of 1-star reviews.
+	Distribution []int `json:"distribution"`
+}
+
+type ExportReviewsRequest struct {
+	PageID dot.IntID    `json:"page_id"`
+	Format ExportFormat `json:"format"`
+}
+
+type ExportReviewsResponse struct {
+	Format      ExportFormat `json:"format"`
+	ContentType string       `json:"content_type"`
+	Content     string       `json:"content"`
+}
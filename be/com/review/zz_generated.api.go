@@ -0,0 +1,244 @@
+// +build !generator
+
+// Code generated by generator api. DO NOT EDIT.
+
+package review
+
+import (
+	context "context"
+	fmt "fmt"
+	http "net/http"
+
+	reviewtypes "github.com/olvrng/rbot/be/com/review/types"
+	httprpc "github.com/olvrng/rbot/be/pkg/httprpc"
+)
+
+func init() {
+	httprpc.Register(NewServer)
+	httprpc.RegisterSchemas(
+		&httprpc.Schema{Name: "github.com/olvrng/rbot/be/com/review/types.ExportReviewsRequest", Fields: []httprpc.Field{
+			{Name: "page_id", Number: 1, Kind: httprpc.KindInt},
+			{Name: "format", Number: 2, Kind: httprpc.KindString},
+		}},
+		&httprpc.Schema{Name: "github.com/olvrng/rbot/be/com/review/types.ExportReviewsResponse", Fields: []httprpc.Field{
+			{Name: "format", Number: 1, Kind: httprpc.KindString},
+			{Name: "content_type", Number: 2, Kind: httprpc.KindString},
+			{Name: "content", Number: 3, Kind: httprpc.KindString},
+		}},
+		&httprpc.Schema{Name: "github.com/olvrng/rbot/be/com/review/types.GetReviewSummaryRequest", Fields: []httprpc.Field{
+			{Name: "page_id", Number: 1, Kind: httprpc.KindInt},
+		}},
+		&httprpc.Schema{Name: "github.com/olvrng/rbot/be/com/review/types.ListReviewsRequest", Fields: []httprpc.Field{
+			{Name: "page_id", Number: 1, Kind: httprpc.KindInt},
+			{Name: "order_id", Number: 2, Kind: httprpc.KindString},
+			{Name: "min_rating", Number: 3, Kind: httprpc.KindInt},
+			{Name: "max_rating", Number: 4, Kind: httprpc.KindInt},
+		}},
+		&httprpc.Schema{Name: "github.com/olvrng/rbot/be/com/review/types.ListReviewsResponse", Fields: []httprpc.Field{
+			{Name: "reviews", Number: 1, Kind: httprpc.KindMessage, Repeated: true, Message: "github.com/olvrng/rbot/be/com/review/types.Review"},
+		}},
+		&httprpc.Schema{Name: "github.com/olvrng/rbot/be/com/review/types.Review", Fields: []httprpc.Field{
+			{Name: "id", Number: 1, Kind: httprpc.KindInt},
+			{Name: "workspace_id", Number: 2, Kind: httprpc.KindInt},
+			{Name: "page_id", Number: 3, Kind: httprpc.KindInt},
+			{Name: "flow_id", Number: 4, Kind: httprpc.KindInt},
+			{Name: "node_id", Number: 5, Kind: httprpc.KindInt},
+			{Name: "psid", Number: 6, Kind: httprpc.KindInt},
+			{Name: "order_id", Number: 7, Kind: httprpc.KindString},
+			{Name: "customer_ref", Number: 8, Kind: httprpc.KindString},
+			{Name: "rating", Number: 9, Kind: httprpc.KindInt},
+			{Name: "comment", Number: 10, Kind: httprpc.KindString},
+			{Name: "created_at", Number: 11, Kind: httprpc.KindInt},
+			{Name: "updated_at", Number: 12, Kind: httprpc.KindInt},
+		}},
+		&httprpc.Schema{Name: "github.com/olvrng/rbot/be/com/review/types.ReviewResponse", Fields: []httprpc.Field{
+			{Name: "review", Number: 1, Kind: httprpc.KindMessage, Message: "github.com/olvrng/rbot/be/com/review/types.Review"},
+		}},
+		&httprpc.Schema{Name: "github.com/olvrng/rbot/be/com/review/types.ReviewSummaryResponse", Fields: []httprpc.Field{
+			{Name: "page_id", Number: 1, Kind: httprpc.KindInt},
+			{Name: "count", Number: 2, Kind: httprpc.KindInt},
+			{Name: "average", Number: 3, Kind: httprpc.KindFloat},
+			{Name: "distribution", Number: 4, Kind: httprpc.KindInt, Repeated: true},
+		}},
+		&httprpc.Schema{Name: "github.com/olvrng/rbot/be/com/review/types.SubmitReviewRequest", Fields: []httprpc.Field{
+			{Name: "page_id", Number: 1, Kind: httprpc.KindInt},
+			{Name: "flow_id", Number: 2, Kind: httprpc.KindInt},
+			{Name: "node_id", Number: 3, Kind: httprpc.KindInt},
+			{Name: "psid", Number: 4, Kind: httprpc.KindInt},
+			{Name: "order_id", Number: 5, Kind: httprpc.KindString},
+			{Name: "customer_ref", Number: 6, Kind: httprpc.KindString},
+			{Name: "rating", Number: 7, Kind: httprpc.KindInt},
+			{Name: "comment", Number: 8, Kind: httprpc.KindString},
+		}},
+	)
+}
+
+func NewServer(builder interface{}, hooks ...httprpc.HooksBuilder) (httprpc.Server, bool) {
+	switch builder := builder.(type) {
+	case func() ReviewService:
+		return NewReviewServiceServer(builder, hooks...), true
+	case ReviewService:
+		fn := func() ReviewService { return builder }
+		return NewReviewServiceServer(fn, hooks...), true
+	default:
+		return nil, false
+	}
+}
+
+type ReviewServiceServer struct {
+	hooks   httprpc.HooksBuilder
+	builder func() ReviewService
+}
+
+func NewReviewServiceServer(builder func() ReviewService, hooks ...httprpc.HooksBuilder) httprpc.Server {
+	return &ReviewServiceServer{
+		hooks:   httprpc.ChainHooks(hooks...),
+		builder: builder,
+	}
+}
+
+const ReviewServicePathPrefix = "/api/review/"
+
+const Path_Review_ExportReviews = "/api/review/ExportReviews"
+const Path_Review_GetReviewSummary = "/api/review/GetReviewSummary"
+const Path_Review_ListReviews = "/api/review/ListReviews"
+const Path_Review_SubmitReview = "/api/review/SubmitReview"
+
+func (s *ReviewServiceServer) PathPrefix() string {
+	return ReviewServicePathPrefix
+}
+
+func (s *ReviewServiceServer) WithHooks(hooks httprpc.HooksBuilder) httprpc.Server {
+	result := *s
+	result.hooks = httprpc.ChainHooks(s.hooks, hooks)
+	return &result
+}
+
+func (s *ReviewServiceServer) ServeHTTP(resp http.ResponseWriter, req *http.Request) {
+	hooks := httprpc.WrapHooks(s.hooks)
+	ctx, info := req.Context(), &httprpc.HookInfo{Route: req.URL.Path, HTTPRequest: req}
+	ctx, err := hooks.RequestReceived(ctx, *info)
+	if err != nil {
+		httprpc.WriteError(ctx, resp, hooks, *info, err)
+		return
+	}
+	reqMsg, exec, allowGET, err := s.parseRoute(req.URL.Path, hooks, info)
+	if err != nil {
+		httprpc.WriteError(ctx, resp, hooks, *info, err)
+		return
+	}
+	serve, err := httprpc.ParseRequestHeader(req, allowGET)
+	if err != nil {
+		httprpc.WriteError(ctx, resp, hooks, *info, err)
+		return
+	}
+	serve(ctx, resp, req, hooks, info, reqMsg, exec)
+}
+
+func (s *ReviewServiceServer) parseRoute(path string, hooks httprpc.Hooks, info *httprpc.HookInfo) (reqMsg httprpc.Message, _ httprpc.ExecFunc, allowGET bool, _ error) {
+	switch path {
+	case "/api/review/ExportReviews":
+		msg := &reviewtypes.ExportReviewsRequest{}
+		fn := func(ctx context.Context) (newCtx context.Context, resp httprpc.Message, err error) {
+			inner := s.builder()
+			info.Request, info.Inner = msg, inner
+			newCtx, err = hooks.RequestRouted(ctx, *info)
+			if err != nil {
+				return
+			}
+			resp, err = inner.ExportReviews(newCtx, msg)
+			return
+		}
+		return msg, fn, true, nil
+	case "/api/review/GetReviewSummary":
+		msg := &reviewtypes.GetReviewSummaryRequest{}
+		fn := func(ctx context.Context) (newCtx context.Context, resp httprpc.Message, err error) {
+			inner := s.builder()
+			info.Request, info.Inner = msg, inner
+			newCtx, err = hooks.RequestRouted(ctx, *info)
+			if err != nil {
+				return
+			}
+			resp, err = inner.GetReviewSummary(newCtx, msg)
+			return
+		}
+		return msg, fn, true, nil
+	case "/api/review/ListReviews":
+		msg := &reviewtypes.ListReviewsRequest{}
+		fn := func(ctx context.Context) (newCtx context.Context, resp httprpc.Message, err error) {
+			inner := s.builder()
+			info.Request, info.Inner = msg, inner
+			newCtx, err = hooks.RequestRouted(ctx, *info)
+			if err != nil {
+				return
+			}
+			resp, err = inner.ListReviews(newCtx, msg)
+			return
+		}
+		return msg, fn, true, nil
+	case "/api/review/SubmitReview":
+		msg := &reviewtypes.SubmitReviewRequest{}
+		fn := func(ctx context.Context) (newCtx context.Context, resp httprpc.Message, err error) {
+			inner := s.builder()
+			info.Request, info.Inner = msg, inner
+			newCtx, err = hooks.RequestRouted(ctx, *info)
+			if err != nil {
+				return
+			}
+			if err = msg.Validate(); err != nil {
+				return
+			}
+			resp, err = inner.SubmitReview(newCtx, msg)
+			return
+		}
+		return msg, fn, false, nil
+	default:
+		msg := fmt.Sprintf("no handler for path %q", path)
+		return nil, nil, false, httprpc.BadRouteError(msg, "POST", path)
+	}
+}
+
+// ReviewServiceClient calls ReviewService on the server at baseURL.
+type ReviewServiceClient struct {
+	client *httprpc.Client
+}
+
+var _ ReviewService = &ReviewServiceClient{}
+
+func NewReviewServiceClient(baseURL string, httpClient *http.Client, hooks ...httprpc.ClientHooks) *ReviewServiceClient {
+	return &ReviewServiceClient{
+		client: httprpc.NewClient(baseURL, httpClient, hooks...),
+	}
+}
+
+func (c *ReviewServiceClient) ExportReviews(ctx context.Context, req *reviewtypes.ExportReviewsRequest) (*reviewtypes.ExportReviewsResponse, error) {
+	resp := &reviewtypes.ExportReviewsResponse{}
+	if err := c.client.Call(ctx, Path_Review_ExportReviews, req, resp); err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+func (c *ReviewServiceClient) GetReviewSummary(ctx context.Context, req *reviewtypes.GetReviewSummaryRequest) (*reviewtypes.ReviewSummaryResponse, error) {
+	resp := &reviewtypes.ReviewSummaryResponse{}
+	if err := c.client.Call(ctx, Path_Review_GetReviewSummary, req, resp); err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+func (c *ReviewServiceClient) ListReviews(ctx context.Context, req *reviewtypes.ListReviewsRequest) (*reviewtypes.ListReviewsResponse, error) {
+	resp := &reviewtypes.ListReviewsResponse{}
+	if err := c.client.Call(ctx, Path_Review_ListReviews, req, resp); err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+func (c *ReviewServiceClient) SubmitReview(ctx context.Context, req *reviewtypes.SubmitReviewRequest) (*reviewtypes.ReviewResponse, error) {
+	resp := &reviewtypes.ReviewResponse{}
+	if err := c.client.Call(ctx, Path_Review_SubmitReview, req, resp); err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
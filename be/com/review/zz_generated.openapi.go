@@ -0,0 +1,674 @@
+// +build !generator
+
+// Code generated by generator openapi. DO NOT EDIT.
+
+package review
+
+import (
+	openapi "github.com/olvrng/rbot/be/pkg/openapi"
+)
+
+func init() {
+	openapi.Register(openAPIFragment)
+}
+
+const openAPIFragment = `{
+  "paths": {
+    "/api/review/ExportReviews": {
+      "get": {
+        "operationId": "Review_ExportReviews_GET",
+        "tags": [
+          "ReviewService"
+        ],
+        "parameters": [
+          {
+            "name": "page_id",
+            "in": "query",
+            "schema": {
+              "type": "string",
+              "format": "int64"
+            }
+          },
+          {
+            "name": "format",
+            "in": "query",
+            "schema": {
+              "$ref": "#/components/schemas/reviewtypes.ExportFormat"
+            }
+          }
+        ],
+        "responses": {
+          "200": {
+            "description": "OK",
+            "content": {
+              "application/json": {
+                "schema": {
+                  "$ref": "#/components/schemas/reviewtypes.ExportReviewsResponse"
+                }
+              },
+              "application/protobuf": {
+                "schema": {
+                  "type": "string",
+                  "format": "binary"
+                }
+              },
+              "application/x-www-form-urlencoded": {
+                "schema": {
+                  "$ref": "#/components/schemas/reviewtypes.ExportReviewsResponse"
+                }
+              }
+            }
+          },
+          "default": {
+            "description": "Error",
+            "content": {
+              "application/json": {
+                "schema": {
+                  "$ref": "#/components/schemas/Error"
+                }
+              }
+            }
+          }
+        }
+      },
+      "post": {
+        "operationId": "Review_ExportReviews",
+        "tags": [
+          "ReviewService"
+        ],
+        "requestBody": {
+          "required": true,
+          "content": {
+            "application/json": {
+              "schema": {
+                "$ref": "#/components/schemas/reviewtypes.ExportReviewsRequest"
+              }
+            },
+            "application/protobuf": {
+              "schema": {
+                "type": "string",
+                "format": "binary"
+              }
+            },
+            "application/x-www-form-urlencoded": {
+              "schema": {
+                "$ref": "#/components/schemas/reviewtypes.ExportReviewsRequest"
+              }
+            }
+          }
+        },
+        "responses": {
+          "200": {
+            "description": "OK",
+            "content": {
+              "application/json": {
+                "schema": {
+                  "$ref": "#/components/schemas/reviewtypes.ExportReviewsResponse"
+                }
+              },
+              "application/protobuf": {
+                "schema": {
+                  "type": "string",
+                  "format": "binary"
+                }
+              },
+              "application/x-www-form-urlencoded": {
+                "schema": {
+                  "$ref": "#/components/schemas/reviewtypes.ExportReviewsResponse"
+                }
+              }
+            }
+          },
+          "default": {
+            "description": "Error",
+            "content": {
+              "application/json": {
+                "schema": {
+                  "$ref": "#/components/schemas/Error"
+                }
+              }
+            }
+          }
+        }
+      }
+    },
+    "/api/review/GetReviewSummary": {
+      "get": {
+        "operationId": "Review_GetReviewSummary_GET",
+        "tags": [
+          "ReviewService"
+        ],
+        "parameters": [
+          {
+            "name": "page_id",
+            "in": "query",
+            "schema": {
+              "type": "string",
+              "format": "int64"
+            }
+          }
+        ],
+        "responses": {
+          "200": {
+            "description": "OK",
+            "content": {
+              "application/json": {
+                "schema": {
+                  "$ref": "#/components/schemas/reviewtypes.ReviewSummaryResponse"
+                }
+              },
+              "application/protobuf": {
+                "schema": {
+                  "type": "string",
+                  "format": "binary"
+                }
+              },
+              "application/x-www-form-urlencoded": {
+                "schema": {
+                  "$ref": "#/components/schemas/reviewtypes.ReviewSummaryResponse"
+                }
+              }
+            }
+          },
+          "default": {
+            "description": "Error",
+            "content": {
+              "application/json": {
+                "schema": {
+                  "$ref": "#/components/schemas/Error"
+                }
+              }
+            }
+          }
+        }
+      },
+      "post": {
+        "operationId": "Review_GetReviewSummary",
+        "tags": [
+          "ReviewService"
+        ],
+        "requestBody": {
+          "required": true,
+          "content": {
+            "application/json": {
+              "schema": {
+                "$ref": "#/components/schemas/reviewtypes.GetReviewSummaryRequest"
+              }
+            },
+            "application/protobuf": {
+              "schema": {
+                "type": "string",
+                "format": "binary"
+              }
+            },
+            "application/x-www-form-urlencoded": {
+              "schema": {
+                "$ref": "#/components/schemas/reviewtypes.GetReviewSummaryRequest"
+              }
+            }
+          }
+        },
+        "responses": {
+          "200": {
+            "description": "OK",
+            "content": {
+              "application/json": {
+                "schema": {
+                  "$ref": "#/components/schemas/reviewtypes.ReviewSummaryResponse"
+                }
+              },
+              "application/protobuf": {
+                "schema": {
+                  "type": "string",
+                  "format": "binary"
+                }
+              },
+              "application/x-www-form-urlencoded": {
+                "schema": {
+                  "$ref": "#/components/schemas/reviewtypes.ReviewSummaryResponse"
+                }
+              }
+            }
+          },
+          "default": {
+            "description": "Error",
+            "content": {
+              "application/json": {
+                "schema": {
+                  "$ref": "#/components/schemas/Error"
+                }
+              }
+            }
+          }
+        }
+      }
+    },
+    "/api/review/ListReviews": {
+      "get": {
+        "operationId": "Review_ListReviews_GET",
+        "tags": [
+          "ReviewService"
+        ],
+        "parameters": [
+          {
+            "name": "page_id",
+            "in": "query",
+            "schema": {
+              "type": "string",
+              "format": "int64"
+            }
+          },
+          {
+            "name": "order_id",
+            "in": "query",
+            "schema": {
+              "type": "string"
+            }
+          },
+          {
+            "name": "min_rating",
+            "in": "query",
+            "schema": {
+              "type": "integer"
+            }
+          },
+          {
+            "name": "max_rating",
+            "in": "query",
+            "schema": {
+              "type": "integer"
+            }
+          }
+        ],
+        "responses": {
+          "200": {
+            "description": "OK",
+            "content": {
+              "application/json": {
+                "schema": {
+                  "$ref": "#/components/schemas/reviewtypes.ListReviewsResponse"
+                }
+              },
+              "application/protobuf": {
+                "schema": {
+                  "type": "string",
+                  "format": "binary"
+                }
+              },
+              "application/x-www-form-urlencoded": {
+                "schema": {
+                  "$ref": "#/components/schemas/reviewtypes.ListReviewsResponse"
+                }
+              }
+            }
+          },
+          "default": {
+            "description": "Error",
+            "content": {
+              "application/json": {
+                "schema": {
+                  "$ref": "#/components/schemas/Error"
+                }
+              }
+            }
+          }
+        }
+      },
+      "post": {
+        "operationId": "Review_ListReviews",
+        "tags": [
+          "ReviewService"
+        ],
+        "requestBody": {
+          "required": true,
+          "content": {
+            "application/json": {
+              "schema": {
+                "$ref": "#/components/schemas/reviewtypes.ListReviewsRequest"
+              }
+            },
+            "application/protobuf": {
+              "schema": {
+                "type": "string",
+                "format": "binary"
+              }
+            },
+            "application/x-www-form-urlencoded": {
+              "schema": {
+                "$ref": "#/components/schemas/reviewtypes.ListReviewsRequest"
+              }
+            }
+          }
+        },
+        "responses": {
+          "200": {
+            "description": "OK",
+            "content": {
+              "application/json": {
+                "schema": {
+                  "$ref": "#/components/schemas/reviewtypes.ListReviewsResponse"
+                }
+              },
+              "application/protobuf": {
+                "schema": {
+                  "type": "string",
+                  "format": "binary"
+                }
+              },
+              "application/x-www-form-urlencoded": {
+                "schema": {
+                  "$ref": "#/components/schemas/reviewtypes.ListReviewsResponse"
+                }
+              }
+            }
+          },
+          "default": {
+            "description": "Error",
+            "content": {
+              "application/json": {
+                "schema": {
+                  "$ref": "#/components/schemas/Error"
+                }
+              }
+            }
+          }
+        }
+      }
+    },
+    "/api/review/SubmitReview": {
+      "post": {
+        "operationId": "Review_SubmitReview",
+        "tags": [
+          "ReviewService"
+        ],
+        "requestBody": {
+          "required": true,
+          "content": {
+            "application/json": {
+              "schema": {
+                "$ref": "#/components/schemas/reviewtypes.SubmitReviewRequest"
+              }
+            },
+            "application/protobuf": {
+              "schema": {
+                "type": "string",
+                "format": "binary"
+              }
+            },
+            "application/x-www-form-urlencoded": {
+              "schema": {
+                "$ref": "#/components/schemas/reviewtypes.SubmitReviewRequest"
+              }
+            }
+          }
+        },
+        "responses": {
+          "200": {
+            "description": "OK",
+            "content": {
+              "application/json": {
+                "schema": {
+                  "$ref": "#/components/schemas/reviewtypes.ReviewResponse"
+                }
+              },
+              "application/protobuf": {
+                "schema": {
+                  "type": "string",
+                  "format": "binary"
+                }
+              },
+              "application/x-www-form-urlencoded": {
+                "schema": {
+                  "$ref": "#/components/schemas/reviewtypes.ReviewResponse"
+                }
+              }
+            }
+          },
+          "default": {
+            "description": "Error",
+            "content": {
+              "application/json": {
+                "schema": {
+                  "$ref": "#/components/schemas/Error"
+                }
+              }
+            }
+          }
+        }
+      }
+    }
+  },
+  "components": {
+    "schemas": {
+      "reviewtypes.ExportFormat": {
+        "type": "string",
+        "enum": [
+          "csv",
+          "json"
+        ]
+      },
+      "reviewtypes.ExportReviewsRequest": {
+        "type": "object",
+        "properties": {
+          "format": {
+            "$ref": "#/components/schemas/reviewtypes.ExportFormat"
+          },
+          "page_id": {
+            "type": "string",
+            "format": "int64"
+          }
+        },
+        "required": [
+          "page_id",
+          "format"
+        ]
+      },
+      "reviewtypes.ExportReviewsResponse": {
+        "type": "object",
+        "properties": {
+          "content": {
+            "type": "string"
+          },
+          "content_type": {
+            "type": "string"
+          },
+          "format": {
+            "$ref": "#/components/schemas/reviewtypes.ExportFormat"
+          }
+        },
+        "required": [
+          "format",
+          "content_type",
+          "content"
+        ]
+      },
+      "reviewtypes.GetReviewSummaryRequest": {
+        "type": "object",
+        "properties": {
+          "page_id": {
+            "type": "string",
+            "format": "int64"
+          }
+        },
+        "required": [
+          "page_id"
+        ]
+      },
+      "reviewtypes.ListReviewsRequest": {
+        "type": "object",
+        "properties": {
+          "max_rating": {
+            "type": "integer"
+          },
+          "min_rating": {
+            "type": "integer"
+          },
+          "order_id": {
+            "type": "string"
+          },
+          "page_id": {
+            "type": "string",
+            "format": "int64"
+          }
+        },
+        "required": [
+          "page_id",
+          "order_id",
+          "min_rating",
+          "max_rating"
+        ]
+      },
+      "reviewtypes.ListReviewsResponse": {
+        "type": "object",
+        "properties": {
+          "reviews": {
+            "type": "array",
+            "items": {
+              "$ref": "#/components/schemas/reviewtypes.Review"
+            }
+          }
+        },
+        "required": [
+          "reviews"
+        ]
+      },
+      "reviewtypes.Review": {
+        "type": "object",
+        "properties": {
+          "comment": {
+            "type": "string"
+          },
+          "created_at": {
+            "type": "string",
+            "description": "milliseconds from 1970"
+          },
+          "customer_ref": {
+            "type": "string"
+          },
+          "flow_id": {
+            "type": "string",
+            "format": "int64"
+          },
+          "id": {
+            "type": "string",
+            "format": "int64"
+          },
+          "node_id": {
+            "type": "string",
+            "format": "int64"
+          },
+          "order_id": {
+            "type": "string"
+          },
+          "page_id": {
+            "type": "string",
+            "format": "int64"
+          },
+          "psid": {
+            "type": "string",
+            "format": "int64"
+          },
+          "rating": {
+            "type": "integer"
+          },
+          "updated_at": {
+            "type": "string",
+            "description": "milliseconds from 1970"
+          },
+          "workspace_id": {
+            "type": "string",
+            "format": "int64"
+          }
+        },
+        "required": [
+          "id",
+          "page_id",
+          "flow_id",
+          "node_id",
+          "psid",
+          "rating",
+          "created_at",
+          "updated_at"
+        ]
+      },
+      "reviewtypes.ReviewResponse": {
+        "type": "object",
+        "properties": {
+          "review": {
+            "$ref": "#/components/schemas/reviewtypes.Review"
+          }
+        }
+      },
+      "reviewtypes.ReviewSummaryResponse": {
+        "type": "object",
+        "properties": {
+          "average": {
+            "type": "number"
+          },
+          "count": {
+            "type": "integer"
+          },
+          "distribution": {
+            "type": "array",
+            "items": {
+              "type": "integer"
+            }
+          },
+          "page_id": {
+            "type": "string",
+            "format": "int64"
+          }
+        },
+        "required": [
+          "page_id",
+          "count",
+          "average",
+          "distribution"
+        ]
+      },
+      "reviewtypes.SubmitReviewRequest": {
+        "type": "object",
+        "properties": {
+          "comment": {
+            "type": "string"
+          },
+          "customer_ref": {
+            "type": "string"
+          },
+          "flow_id": {
+            "type": "string",
+            "format": "int64"
+          },
+          "node_id": {
+            "type": "string",
+            "format": "int64"
+          },
+          "order_id": {
+            "type": "string"
+          },
+          "page_id": {
+            "type": "string",
+            "format": "int64"
+          },
+          "psid": {
+            "type": "string",
+            "format": "int64"
+          },
+          "rating": {
+            "type": "integer"
+          }
+        },
+        "required": [
+          "page_id",
+          "flow_id",
+          "node_id",
+          "psid",
+          "order_id",
+          "customer_ref",
+          "rating",
+          "comment"
+        ]
+      }
+    }
+  }
+}`
@@ -0,0 +1,17 @@
+package workspace
+
+import (
+	"context"
+
+	"github.com/olvrng/rbot/be/com/workspace/types"
+)
+
+// +gen:api
+
+// +api:path=/api/workspace
+type WorkspaceService interface {
+	// +api:get
+	GetWorkspace(ctx context.Context, req *types.GetWorkspaceRequest) (*types.WorkspaceResponse, error)
+
+	ReleasePage(ctx context.Context, req *types.ReleasePageRequest) (*types.WorkspaceResponse, error)
+}
@@ -0,0 +1,44 @@
+package workspace
+
+import (
+	"context"
+
+	"github.com/olvrng/rbot/be/pkg/dot"
+	"github.com/olvrng/rbot/be/pkg/xerrors"
+)
+
+// DefaultID is the workspace of the requests without workspace, such as all
+// the requests of a server without auth.
+const DefaultID dot.IntID = 0
+
+type workspaceKey struct{}
+
+// WithID returns a context which is scoped to the workspace. The stores only
+// read and write the data of the workspace of the context.
+func WithID(ctx context.Context, id dot.IntID) context.Context {
+	return context.WithValue(ctx, workspaceKey{}, id)
+}
+
+// GetID returns the workspace of the context, or DefaultID.
+func GetID(ctx context.Context) dot.IntID {
+	id, ok := ctx.Value(workspaceKey{}).(dot.IntID)
+	if !ok {
+		return DefaultID
+	}
+	return id
+}
+
+// Match tells whether the data of the workspace is visible in the context.
+func Match(ctx context.Context, id dot.IntID) bool {
+	return GetID(ctx) == id
+}
+
+// CheckOwner returns an error when the data of the workspace may not be
+// replaced in the context, such as the conversation of a page which belongs to
+// another workspace.
+func CheckOwner(ctx context.Context, id dot.IntID) error {
+	if !Match(ctx, id) {
+		return xerrors.Errorf(xerrors.PermissionDenied, nil, "the data belongs to another workspace")
+	}
+	return nil
+}
@@ -0,0 +1,55 @@
+package service
+
+import (
+	"context"
+
+	"github.com/olvrng/rbot/be/com/flowdef"
+	flowdeftypes "github.com/olvrng/rbot/be/com/flowdef/types"
+	"github.com/olvrng/rbot/be/com/workspace"
+	"github.com/olvrng/rbot/be/com/workspace/store"
+	"github.com/olvrng/rbot/be/com/workspace/types"
+	"github.com/olvrng/rbot/be/pkg/xerrors"
+)
+
+var _ workspace.WorkspaceService = (*WorkspaceService)(nil)
+
+type WorkspaceService struct {
+	Store     *store.WorkspaceStore
+	FlowQuery flowdef.QueryService
+}
+
+func NewWorkspaceService(workspaceStore *store.WorkspaceStore, flowQuery flowdef.QueryService) *WorkspaceService {
+	s := &WorkspaceService{
+		Store:     workspaceStore,
+		FlowQuery: flowQuery,
+	}
+	return s
+}
+
+func (s *WorkspaceService) GetWorkspace(ctx context.Context, req *types.GetWorkspaceRequest) (*types.WorkspaceResponse, error) {
+	w, err := s.Store.GetWorkspace(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return &types.WorkspaceResponse{Workspace: w}, nil
+}
+
+// ReleasePage unbinds the page. It fails while a flow of the workspace uses the
+// page, so that a page is never used by the flows of two workspaces.
+func (s *WorkspaceService) ReleasePage(ctx context.Context, req *types.ReleasePageRequest) (*types.WorkspaceResponse, error) {
+	if req.PageID == 0 {
+		return nil, xerrors.Errorf(xerrors.InvalidArgument, nil, "page_id is required")
+	}
+	_, err := s.FlowQuery.GetFlowByParam(ctx, &flowdeftypes.GetFlowByParamRequest{FBPageID: req.PageID})
+	switch {
+	case err == nil:
+		return nil, xerrors.Errorf(xerrors.FailedPrecondition, nil, "page %v is used by a flow", req.PageID)
+	case xerrors.GetCode(err) != xerrors.NotFound:
+		return nil, err
+	}
+	w, err := s.Store.ReleasePage(ctx, req.PageID)
+	if err != nil {
+		return nil, err
+	}
+	return &types.WorkspaceResponse{Workspace: w}, nil
+}
@@ -0,0 +1,162 @@
+package store
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"sync"
+
+	"github.com/olvrng/rbot/be/com/workspace"
+	"github.com/olvrng/rbot/be/com/workspace/types"
+	"github.com/olvrng/rbot/be/pkg/dot"
+	"github.com/olvrng/rbot/be/pkg/xerrors"
+)
+
+type WorkspaceFile struct {
+	Workspaces []*types.Workspace `json:"workspaces"`
+}
+
+// WorkspaceStore keeps the pages of the workspaces. A page is bound to one
+// workspace at most.
+type WorkspaceStore struct {
+	FilePath string
+	Data     *WorkspaceFile
+
+	m sync.Mutex
+}
+
+func NewWorkspaceStore(filePath string) (*WorkspaceStore, error) {
+	s := &WorkspaceStore{
+		FilePath: filePath,
+	}
+
+	_, err := os.Stat(filePath)
+	switch {
+	case err == nil: // load from storage
+		data, err := ioutil.ReadFile(filePath)
+		if err != nil {
+			return nil, err
+		}
+		err = json.Unmarshal(data, &s.Data)
+		return s, err
+
+	case os.IsNotExist(err): // try creating one
+		s.Data = &WorkspaceFile{}
+		err = storeFile(filePath, s.Data)
+		return s, err
+
+	default:
+		return nil, err
+	}
+}
+
+// GetWorkspace returns the workspace of the context. A workspace without page
+// is not stored.
+func (s *WorkspaceStore) GetWorkspace(ctx context.Context) (*types.Workspace, error) {
+	s.m.Lock()
+	defer s.m.Unlock()
+
+	id := workspace.GetID(ctx)
+	if w := s.find(id); w != nil {
+		return w, nil
+	}
+	return &types.Workspace{ID: id, PageIDs: []dot.IntID{}}, nil
+}
+
+// GetWorkspaceByPage returns the workspace which owns the page, whatever the
+// workspace of the context. It binds the events which come from outside, such
+// as the messages of the page, to the workspace.
+func (s *WorkspaceStore) GetWorkspaceByPage(ctx context.Context, pageID dot.IntID) (*types.Workspace, error) {
+	s.m.Lock()
+	defer s.m.Unlock()
+
+	if w := s.findByPage(pageID); w != nil {
+		return w, nil
+	}
+	return nil, xerrors.Errorf(xerrors.NotFound, nil, "page %v is not bound to a workspace", pageID)
+}
+
+// BindPages binds the pages to the workspace of the context. It fails when a
+// page belongs to another workspace.
+func (s *WorkspaceStore) BindPages(ctx context.Context, pageIDs []dot.IntID) error {
+	s.m.Lock()
+	defer s.m.Unlock()
+
+	id := workspace.GetID(ctx)
+	for _, pageID := range pageIDs {
+		if owner := s.findByPage(pageID); owner != nil && owner.ID != id {
+			return xerrors.Errorf(xerrors.PermissionDenied, nil, "page %v belongs to another workspace", pageID)
+		}
+	}
+
+	now := dot.Now()
+	w := s.find(id)
+	if w == nil {
+		w = &types.Workspace{ID: id, CreatedAt: now}
+		s.Data.Workspaces = append(s.Data.Workspaces, w)
+	}
+	changed := false
+	for _, pageID := range pageIDs {
+		if !w.HasPage(pageID) {
+			w.PageIDs = append(w.PageIDs, pageID)
+			changed = true
+		}
+	}
+	if !changed {
+		return nil
+	}
+	w.UpdatedAt = now
+	return storeFile(s.FilePath, s.Data)
+}
+
+// ReleasePage unbinds the page from the workspace of the context.
+func (s *WorkspaceStore) ReleasePage(ctx context.Context, pageID dot.IntID) (*types.Workspace, error) {
+	s.m.Lock()
+	defer s.m.Unlock()
+
+	w := s.find(workspace.GetID(ctx))
+	if w == nil || !w.HasPage(pageID) {
+		return nil, xerrors.Errorf(xerrors.NotFound, nil, "page not found")
+	}
+	pageIDs := make([]dot.IntID, 0, len(w.PageIDs))
+	for _, id := range w.PageIDs {
+		if id != pageID {
+			pageIDs = append(pageIDs, id)
+		}
+	}
+	w.PageIDs = pageIDs
+	w.UpdatedAt = dot.Now()
+	return w, storeFile(s.FilePath, s.Data)
+}
+
+func (s *WorkspaceStore) find(id dot.IntID) *types.Workspace {
+	for _, w := range s.Data.Workspaces {
+		if w.ID == id {
+			return w
+		}
+	}
+	return nil
+}
+
+func (s *WorkspaceStore) findByPage(pageID dot.IntID) *types.Workspace {
+	for _, w := range s.Data.Workspaces {
+		if w.HasPage(pageID) {
+			return w
+		}
+	}
+	return nil
+}
+
+// storeFile writes the data to the file. The store is kept in memory only when
+// the file path is empty.
+func storeFile(filePath string, data interface{}) error {
+	if filePath == "" {
+		return nil
+	}
+	out, err := json.MarshalIndent(data, "", "\t")
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(filePath, out, 0644)
+}
@@ -0,0 +1,39 @@
+package types
+
+import "github.com/olvrng/rbot/be/pkg/dot"
+
+// Workspace is a tenant. It owns its pages, and the flows, conversations and
+// contacts of these pages. A page belongs to one workspace at most: it is bound
+// to the workspace of the first flow which uses it.
+//
+// The page access tokens are not owned by the workspaces yet: the messages of
+// all the pages are sent with the page access token of the config.
+type Workspace struct {
+	ID      dot.IntID   `json:"id"`
+	PageIDs []dot.IntID `json:"page_ids"`
+
+	CreatedAt dot.Timestamp `json:"created_at"`
+	UpdatedAt dot.Timestamp `json:"updated_at"`
+}
+
+func (w *Workspace) HasPage(pageID dot.IntID) bool {
+	for _, id := range w.PageIDs {
+		if id == pageID {
+			return true
+		}
+	}
+	return false
+}
+
+type GetWorkspaceRequest struct{}
+
+type WorkspaceResponse struct {
+	Workspace *Workspace `json:"workspace"`
+}
+
+// ReleasePageRequest unbinds the page from the workspace, so that the flows of
+// another workspace may use it.
+type ReleasePageRequest struct {
+	// +validate:required
+	PageID dot.IntID `json:"page_id"`
+}
@@ -0,0 +1,159 @@
+// +build !generator
+
+// Code generated by generator api. DO NOT EDIT.
+
+package workspace
+
+import (
+	context "context"
+	fmt "fmt"
+	http "net/http"
+
+	workspacetypes "github.com/olvrng/rbot/be/com/workspace/types"
+	httprpc "github.com/olvrng/rbot/be/pkg/httprpc"
+)
+
+func init() {
+	httprpc.Register(NewServer)
+	httprpc.RegisterSchemas(
+		&httprpc.Schema{Name: "github.com/olvrng/rbot/be/com/workspace/types.GetWorkspaceRequest", Fields: []httprpc.Field{}},
+		&httprpc.Schema{Name: "github.com/olvrng/rbot/be/com/workspace/types.ReleasePageRequest", Fields: []httprpc.Field{
+			{Name: "page_id", Number: 1, Kind: httprpc.KindInt},
+		}},
+		&httprpc.Schema{Name: "github.com/olvrng/rbot/be/com/workspace/types.Workspace", Fields: []httprpc.Field{
+			{Name: "id", Number: 1, Kind: httprpc.KindInt},
+			{Name: "page_ids", Number: 2, Kind: httprpc.KindInt, Repeated: true},
+			{Name: "created_at", Number: 3, Kind: httprpc.KindInt},
+			{Name: "updated_at", Number: 4, Kind: httprpc.KindInt},
+		}},
+		&httprpc.Schema{Name: "github.com/olvrng/rbot/be/com/workspace/types.WorkspaceResponse", Fields: []httprpc.Field{
+			{Name: "workspace", Number: 1, Kind: httprpc.KindMessage, Message: "github.com/olvrng/rbot/be/com/workspace/types.Workspace"},
+		}},
+	)
+}
+
+func NewServer(builder interface{}, hooks ...httprpc.HooksBuilder) (httprpc.Server, bool) {
+	switch builder := builder.(type) {
+	case func() WorkspaceService:
+		return NewWorkspaceServiceServer(builder, hooks...), true
+	case WorkspaceService:
+		fn := func() WorkspaceService { return builder }
+		return NewWorkspaceServiceServer(fn, hooks...), true
+	default:
+		return nil, false
+	}
+}
+
+type WorkspaceServiceServer struct {
+	hooks   httprpc.HooksBuilder
+	builder func() WorkspaceService
+}
+
+func NewWorkspaceServiceServer(builder func() WorkspaceService, hooks ...httprpc.HooksBuilder) httprpc.Server {
+	return &WorkspaceServiceServer{
+		hooks:   httprpc.ChainHooks(hooks...),
+		builder: builder,
+	}
+}
+
+const WorkspaceServicePathPrefix = "/api/workspace/"
+
+const Path_Workspace_GetWorkspace = "/api/workspace/GetWorkspace"
+const Path_Workspace_ReleasePage = "/api/workspace/ReleasePage"
+
+func (s *WorkspaceServiceServer) PathPrefix() string {
+	return WorkspaceServicePathPrefix
+}
+
+func (s *WorkspaceServiceServer) WithHooks(hooks httprpc.HooksBuilder) httprpc.Server {
+	result := *s
+	result.hooks = httprpc.ChainHooks(s.hooks, hooks)
+	return &result
+}
+
+func (s *WorkspaceServiceServer) ServeHTTP(resp http.ResponseWriter, req *http.Request) {
+	hooks := httprpc.WrapHooks(s.hooks)
+	ctx, info := req.Context(), &httprpc.HookInfo{Route: req.URL.Path, HTTPRequest: req}
+	ctx, err := hooks.RequestReceived(ctx, *info)
+	if err != nil {
+		httprpc.WriteError(ctx, resp, hooks, *info, err)
+		return
+	}
+	reqMsg, exec, allowGET, err := s.parseRoute(req.URL.Path, hooks, info)
+	if err != nil {
+		httprpc.WriteError(ctx, resp, hooks, *info, err)
+		return
+	}
+	serve, err := httprpc.ParseRequestHeader(req, allowGET)
+	if err != nil {
+		httprpc.WriteError(ctx, resp, hooks, *info, err)
+		return
+	}
+	serve(ctx, resp, req, hooks, info, reqMsg, exec)
+}
+
+func (s *WorkspaceServiceServer) parseRoute(path string, hooks httprpc.Hooks, info *httprpc.HookInfo) (reqMsg httprpc.Message, _ httprpc.ExecFunc, allowGET bool, _ error) {
+	switch path {
+	case "/api/workspace/GetWorkspace":
+		msg := &workspacetypes.GetWorkspaceRequest{}
+		fn := func(ctx context.Context) (newCtx context.Context, resp httprpc.Message, err error) {
+			inner := s.builder()
+			info.Request, info.Inner = msg, inner
+			newCtx, err = hooks.RequestRouted(ctx, *info)
+			if err != nil {
+				return
+			}
+			resp, err = inner.GetWorkspace(newCtx, msg)
+			return
+		}
+		return msg, fn, true, nil
+	case "/api/workspace/ReleasePage":
+		msg := &workspacetypes.ReleasePageRequest{}
+		fn := func(ctx context.Context) (newCtx context.Context, resp httprpc.Message, err error) {
+			inner := s.builder()
+			info.Request, info.Inner = msg, inner
+			newCtx, err = hooks.RequestRouted(ctx, *info)
+			if err != nil {
+				return
+			}
+			if err = msg.Validate(); err != nil {
+				return
+			}
+			resp, err = inner.ReleasePage(newCtx, msg)
+			return
+		}
+		return msg, fn, false, nil
+	default:
+		msg := fmt.Sprintf("no handler for path %q", path)
+		return nil, nil, false, httprpc.BadRouteError(msg, "POST", path)
+	}
+}
+
+// WorkspaceServiceClient calls WorkspaceService on the server at baseURL.
+type WorkspaceServiceClient struct {
+	client *httprpc.Client
+}
+
+var _ WorkspaceService = &WorkspaceServiceClient{}
+
+func NewWorkspaceServiceClient(baseURL string, httpClient *http.Client, hooks ...httprpc.ClientHooks) *WorkspaceServiceClient {
+	return &WorkspaceServiceClient{
+		client: httprpc.NewClient(baseURL, httpClient, hooks...),
+	}
+}
+
+func (c *WorkspaceServiceClient) GetWorkspace(ctx context.Context, req *workspacetypes.GetWorkspaceRequest) (*workspacetypes.WorkspaceResponse, error) {
+	resp := &workspacetypes.WorkspaceResponse{}
+	if err := c.client.Call(ctx, Path_Workspace_GetWorkspace, req, resp); err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+func (c *WorkspaceServiceClient) ReleasePage(ctx context.Context, req *workspacetypes.ReleasePageRequest) (*workspacetypes.WorkspaceResponse, error) {
+	resp := &workspacetypes.WorkspaceResponse{}
+	if err := c.client.Call(ctx, Path_Workspace_ReleasePage, req, resp); err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
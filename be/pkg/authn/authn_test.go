@@ -0,0 +1,56 @@
+package authn
+
+import (
+	"encoding/hex"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestToken(t *testing.T) {
+	secret := []byte("secret")
+	now := time.Date(2021, 6, 1, 10, 0, 0, 0, time.UTC)
+	token := SignToken(secret, "ann@example.com", now, time.Hour)
+
+	claims, err := ParseToken(secret, token, now.Add(59*time.Minute))
+	require.NoError(t, err)
+	require.Equal(t, "ann@example.com", claims.Subject)
+	require.Equal(t, now.Unix(), claims.IssuedAt)
+
+	_, err = ParseToken(secret, token, now.Add(time.Hour))
+	require.Equal(t, ErrExpiredToken, err)
+
+	_, err = ParseToken([]byte("other"), token, now)
+	require.Equal(t, ErrInvalidToken, err)
+
+	parts := strings.Split(token, ".")
+	forged := parts[0] + "." + b64.EncodeToString([]byte(`{"sub":"bob@example.com","exp":9999999999}`)) + "." + parts[2]
+	_, err = ParseToken(secret, forged, now)
+	require.Equal(t, ErrInvalidToken, err)
+
+	none := b64.EncodeToString([]byte(`{"alg":"none"}`)) + "." + parts[1] + "."
+	_, err = ParseToken(secret, none, now)
+	require.Equal(t, ErrInvalidToken, err)
+}
+
+func TestPassword(t *testing.T) {
+	hash, err := HashPassword("p4ssw0rd")
+	require.NoError(t, err)
+	require.True(t, strings.HasPrefix(hash, "pbkdf2-sha256$100000$"))
+	require.True(t, VerifyPassword(hash, "p4ssw0rd"))
+	require.False(t, VerifyPassword(hash, "password"))
+	require.False(t, VerifyPassword("plain", "plain"))
+
+	other, err := HashPassword("p4ssw0rd")
+	require.NoError(t, err)
+	require.NotEqual(t, hash, other, "the salt is random")
+}
+
+func TestPBKDF2(t *testing.T) {
+	// RFC 7914, section 11
+	key := pbkdf2([]byte("passwd"), []byte("salt"), 1, 64)
+	require.Equal(t, "55ac046e56e3089fec1691c22544b605f94185216dde0465e68b9d57c20dacbc"+
+		"49ca9cccf179b645991664b39d77ef317c71b845b1e30bd509112041d3a19783", hex.EncodeToString(key))
+}
@@ -0,0 +1,77 @@
+// Package authn signs the session tokens, as JWT with HS256, and hashes the
+// passwords with PBKDF2-SHA256. It only depends on the standard library.
+package authn
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"strings"
+	"time"
+)
+
+var (
+	ErrInvalidToken = errors.New("invalid token")
+	ErrExpiredToken = errors.New("token is expired")
+)
+
+var b64 = base64.RawURLEncoding
+
+// tokenHeader is the only header accepted by ParseToken.
+const tokenHeader = `{"alg":"HS256","typ":"JWT"}`
+
+// Claims are the registered claims of the token which are used by rbot.
+type Claims struct {
+	Subject   string `json:"sub"`
+	IssuedAt  int64  `json:"iat"`
+	ExpiresAt int64  `json:"exp"`
+}
+
+// SignToken returns the token of the subject, valid for ttl from now.
+func SignToken(secret []byte, subject string, now time.Time, ttl time.Duration) string {
+	claims := Claims{
+		Subject:   subject,
+		IssuedAt:  now.Unix(),
+		ExpiresAt: now.Add(ttl).Unix(),
+	}
+	payload, _ := json.Marshal(claims)
+	unsigned := b64.EncodeToString([]byte(tokenHeader)) + "." + b64.EncodeToString(payload)
+	return unsigned + "." + b64.EncodeToString(sign(secret, unsigned))
+}
+
+// ParseToken verifies the signature and the expiry of the token, then returns
+// its claims.
+func ParseToken(secret []byte, token string, now time.Time) (*Claims, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return nil, ErrInvalidToken
+	}
+	header, err := b64.DecodeString(parts[0])
+	if err != nil || string(header) != tokenHeader {
+		return nil, ErrInvalidToken
+	}
+	signature, err := b64.DecodeString(parts[2])
+	if err != nil || !hmac.Equal(signature, sign(secret, parts[0]+"."+parts[1])) {
+		return nil, ErrInvalidToken
+	}
+	payload, err := b64.DecodeString(parts[1])
+	if err != nil {
+		return nil, ErrInvalidToken
+	}
+	var claims Claims
+	if err = json.Unmarshal(payload, &claims); err != nil || claims.Subject == "" {
+		return nil, ErrInvalidToken
+	}
+	if now.Unix() >= claims.ExpiresAt {
+		return nil, ErrExpiredToken
+	}
+	return &claims, nil
+}
+
+func sign(secret []byte, s string) []byte {
+	mac := hmac.New(sha256.New, secret)
+	mac.Write([]byte(s))
+	return mac.Sum(nil)
+}
@@ -0,0 +1,47 @@
+// Package clock provides the current time. It can be replaced with a Mock in
+// tests, so that time-dependent code runs deterministically.
+package clock
+
+import (
+	"sync"
+	"time"
+)
+
+type Clock interface {
+	Now() time.Time
+}
+
+// System is the clock of the operating system.
+var System Clock = systemClock{}
+
+type systemClock struct{}
+
+func (systemClock) Now() time.Time { return time.Now() }
+
+// Mock is a clock which only moves when told to.
+type Mock struct {
+	m sync.Mutex
+	t time.Time
+}
+
+func NewMock(t time.Time) *Mock {
+	return &Mock{t: t}
+}
+
+func (c *Mock) Now() time.Time {
+	c.m.Lock()
+	defer c.m.Unlock()
+	return c.t
+}
+
+func (c *Mock) Set(t time.Time) {
+	c.m.Lock()
+	defer c.m.Unlock()
+	c.t = t
+}
+
+func (c *Mock) Add(d time.Duration) {
+	c.m.Lock()
+	defer c.m.Unlock()
+	c.t = c.t.Add(d)
+}
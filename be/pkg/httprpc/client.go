@@ -0,0 +1,277 @@
+package httprpc
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"strings"
+
+	"github.com/olvrng/rbot/be/pkg/xerrors"
+)
+
+type ClientHookInfo struct {
+	Route        string
+	HTTPRequest  *http.Request
+	HTTPResponse *http.Response
+	Request      Message
+	Response     Message
+}
+
+// ClientHooks are called by the generated clients on each call, such as to set
+// the auth headers or to trace the calls.
+type ClientHooks struct {
+	// RequestPrepared is called before sending the request, and may change its
+	// headers.
+	RequestPrepared func(ctx context.Context, info ClientHookInfo) (context.Context, error)
+
+	// ResponseReceived is called after decoding a successful response.
+	ResponseReceived func(ctx context.Context, info ClientHookInfo)
+
+	// Error is called when the call fails, and returns the error of the call.
+	Error func(ctx context.Context, info ClientHookInfo, err error) error
+}
+
+// ChainClientHooks calls the hooks in order. RequestPrepared stops at the first
+// error.
+func ChainClientHooks(hooks ...ClientHooks) ClientHooks {
+	if len(hooks) == 1 {
+		return hooks[0]
+	}
+	return ClientHooks{
+		RequestPrepared: func(ctx context.Context, info ClientHookInfo) (_ context.Context, err error) {
+			for _, h := range hooks {
+				if h.RequestPrepared != nil {
+					ctx, err = h.RequestPrepared(ctx, info)
+					if err != nil {
+						return ctx, err
+					}
+				}
+			}
+			return ctx, nil
+		},
+		ResponseReceived: func(ctx context.Context, info ClientHookInfo) {
+			for _, h := range hooks {
+				if h.ResponseReceived != nil {
+					h.ResponseReceived(ctx, info)
+				}
+			}
+		},
+		Error: func(ctx context.Context, info ClientHookInfo, err error) error {
+			for _, h := range hooks {
+				if h.Error != nil {
+					err = h.Error(ctx, info, err)
+				}
+			}
+			return err
+		},
+	}
+}
+
+// WithHeader sets the header on each request, such as X-API-Key or
+// Authorization.
+func WithHeader(key, value string) ClientHooks {
+	return ClientHooks{
+		RequestPrepared: func(ctx context.Context, info ClientHookInfo) (context.Context, error) {
+			info.HTTPRequest.Header.Set(key, value)
+			return ctx, nil
+		},
+	}
+}
+
+// Client posts the JSON requests of the generated clients to the server at
+// BaseURL, such as "http://localhost:8080".
+type Client struct {
+	BaseURL string
+	HTTP    *http.Client
+	Hooks   ClientHooks
+}
+
+func NewClient(baseURL string, httpClient *http.Client, hooks ...ClientHooks) *Client {
+	if httpClient == nil {
+		httpClient = http.DefaultClient
+	}
+	return &Client{
+		BaseURL: strings.TrimSuffix(baseURL, "/"),
+		HTTP:    httpClient,
+		Hooks:   ChainClientHooks(hooks...),
+	}
+}
+
+// Call posts the request to the route, then decodes the response into resp.
+// The error responses are decoded back into *xerrors.APIError.
+func (c *Client) Call(ctx context.Context, route string, req, resp Message) error {
+	info := ClientHookInfo{Route: route, Request: req}
+	err := c.call(ctx, &info, req, resp)
+	if err != nil && c.Hooks.Error != nil {
+		err = c.Hooks.Error(ctx, info, err)
+	}
+	return err
+}
+
+func (c *Client) call(ctx context.Context, info *ClientHookInfo, req, resp Message) error {
+	ctx, httpResp, err := c.send(ctx, info, req, "application/json")
+	if err != nil {
+		return err
+	}
+	defer httpResp.Body.Close()
+	respBody, err := ioutil.ReadAll(httpResp.Body)
+	if err != nil {
+		return xerrors.Errorf(xerrors.Unavailable, err, "failed to read response")
+	}
+	if httpResp.StatusCode != http.StatusOK {
+		return errorFromResponse(httpResp.StatusCode, respBody)
+	}
+	if err = json.Unmarshal(respBody, resp); err != nil {
+		return xerrors.Errorf(xerrors.Internal, err, "the json response could not be decoded")
+	}
+	info.Response = resp
+	if c.Hooks.ResponseReceived != nil {
+		c.Hooks.ResponseReceived(ctx, *info)
+	}
+	return nil
+}
+
+// maxEventSize is the max size of the events read by Client.Stream.
+const maxEventSize = 4 << 20
+
+// Stream posts the request to the route of a streaming method, then calls fn
+// with each event, decoded into newMsg(). It returns nil when the server ends
+// the stream, the error of its error event, or the error of fn.
+func (c *Client) Stream(ctx context.Context, route string, req Message, newMsg func() Message, fn func(Message) error) error {
+	info := ClientHookInfo{Route: route, Request: req}
+	err := c.stream(ctx, &info, req, newMsg, fn)
+	if err != nil && c.Hooks.Error != nil {
+		err = c.Hooks.Error(ctx, info, err)
+	}
+	return err
+}
+
+func (c *Client) stream(ctx context.Context, info *ClientHookInfo, req Message, newMsg func() Message, fn func(Message) error) error {
+	ctx, httpResp, err := c.send(ctx, info, req, ContentTypeEventStream)
+	if err != nil {
+		return err
+	}
+	defer httpResp.Body.Close()
+	if httpResp.StatusCode != http.StatusOK {
+		respBody, _ := ioutil.ReadAll(httpResp.Body)
+		return errorFromResponse(httpResp.StatusCode, respBody)
+	}
+
+	scanner := bufio.NewScanner(httpResp.Body)
+	scanner.Buffer(make([]byte, 0, 64<<10), maxEventSize)
+	var event string
+	var data []byte
+	for scanner.Scan() {
+		line := scanner.Text()
+		switch {
+		case line == "":
+			if data != nil {
+				if err = c.dispatch(ctx, info, event, data, newMsg, fn); err != nil {
+					return err
+				}
+			}
+			event, data = "", nil
+		case strings.HasPrefix(line, ":"):
+			// the comments, such as the heartbeats
+		case strings.HasPrefix(line, "event:"):
+			event = strings.TrimSpace(line[len("event:"):])
+		case strings.HasPrefix(line, "data:"):
+			if data != nil {
+				data = append(data, '\n')
+			}
+			data = append(data, strings.TrimPrefix(line[len("data:"):], " ")...)
+		}
+	}
+	if err = scanner.Err(); err != nil {
+		if ctx.Err() != nil {
+			return xerrors.Errorf(xerrors.Canceled, ctx.Err(), "the stream is canceled")
+		}
+		return xerrors.Errorf(xerrors.Unavailable, err, "failed to read the stream")
+	}
+	return nil
+}
+
+func (c *Client) dispatch(ctx context.Context, info *ClientHookInfo, event string, data []byte, newMsg func() Message, fn func(Message) error) error {
+	if event == "error" {
+		return errorFromResponse(http.StatusOK, data)
+	}
+	msg := newMsg()
+	if err := json.Unmarshal(data, msg); err != nil {
+		return xerrors.Errorf(xerrors.Internal, err, "the json event could not be decoded")
+	}
+	info.Response = msg
+	if c.Hooks.ResponseReceived != nil {
+		c.Hooks.ResponseReceived(ctx, *info)
+	}
+	return fn(msg)
+}
+
+// send posts the json request, after the RequestPrepared hook.
+func (c *Client) send(ctx context.Context, info *ClientHookInfo, req Message, accept string) (context.Context, *http.Response, error) {
+	body, err := json.Marshal(req)
+	if err != nil {
+		return ctx, nil, xerrors.Errorf(xerrors.Internal, err, "the json request could not be encoded")
+	}
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, c.BaseURL+info.Route, bytes.NewReader(body))
+	if err != nil {
+		return ctx, nil, xerrors.Errorf(xerrors.Internal, err, "invalid request url")
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+	httpReq.Header.Set("Accept", accept)
+	info.HTTPRequest = httpReq
+	if c.Hooks.RequestPrepared != nil {
+		ctx, err = c.Hooks.RequestPrepared(ctx, *info)
+		if err != nil {
+			return ctx, nil, err
+		}
+		httpReq = httpReq.WithContext(ctx)
+	}
+
+	httpResp, err := c.HTTP.Do(httpReq)
+	if err != nil {
+		return ctx, nil, xerrors.Errorf(xerrors.Unavailable, err, "failed to send request")
+	}
+	info.HTTPResponse = httpResp
+	return ctx, httpResp, nil
+}
+
+// errorFromResponse decodes the error written by WriteError. The responses
+// which do not come from the server, such as from a proxy, get the code of
+// their http status.
+func errorFromResponse(statusCode int, body []byte) *xerrors.APIError {
+	var tj twerrJSON
+	if err := json.Unmarshal(body, &tj); err != nil || tj.Code == "" {
+		code := clientErrorCodeFromHTTPStatus(statusCode)
+		return xerrors.Errorf(code, nil, "unexpected http status %v", statusCode).
+			WithMeta("body", string(body))
+	}
+	xerr := xerrors.Errorf(xerrors.ParseCode(tj.Code), nil, tj.Msg)
+	for k, v := range tj.Meta {
+		xerr.WithMeta(k, v)
+	}
+	return xerr
+}
+
+func clientErrorCodeFromHTTPStatus(status int) xerrors.Code {
+	switch status {
+	case 400: // Bad Request
+		return xerrors.InvalidArgument
+	case 401: // Unauthorized
+		return xerrors.Unauthenticated
+	case 403: // Forbidden
+		return xerrors.PermissionDenied
+	case 404: // Not Found
+		return xerrors.NotFound
+	case 408: // Request Timeout
+		return xerrors.DeadlineExceeded
+	case 429: // Too Many Requests
+		return xerrors.ResourceExhausted
+	case 502, 503, 504: // Bad Gateway, Service Unavailable, Gateway Timeout
+		return xerrors.Unavailable
+	default:
+		return xerrors.Unknown
+	}
+}
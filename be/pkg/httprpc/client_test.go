@@ -0,0 +1,82 @@
+package httprpc
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/olvrng/rbot/be/pkg/xerrors"
+)
+
+type echoMessage struct {
+	Text string `json:"text"`
+}
+
+func TestClient(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req echoMessage
+		_ = json.NewDecoder(r.Body).Decode(&req)
+		switch {
+		case r.URL.Path == "/proxy":
+			http.Error(w, "bad gateway", http.StatusBadGateway)
+		case r.Header.Get("X-API-Key") != "key":
+			err := xerrors.Errorf(xerrors.Unauthenticated, nil, "missing key").WithMeta("header", "X-API-Key")
+			WriteError(r.Context(), w, WrapHooks(nil), HookInfo{}, err)
+		default:
+			_ = json.NewEncoder(w).Encode(&req)
+		}
+	}))
+	defer server.Close()
+	ctx := context.Background()
+
+	t.Run("decode the response", func(t *testing.T) {
+		var routes []string
+		trace := ClientHooks{
+			ResponseReceived: func(ctx context.Context, info ClientHookInfo) {
+				routes = append(routes, info.Route+" "+info.Response.(*echoMessage).Text)
+			},
+		}
+		client := NewClient(server.URL+"/", nil, WithHeader("X-API-Key", "key"), trace)
+		var resp echoMessage
+		require.NoError(t, client.Call(ctx, "/echo", &echoMessage{Text: "hello"}, &resp))
+		require.Equal(t, "hello", resp.Text)
+		require.Equal(t, []string{"/echo hello"}, routes)
+	})
+
+	t.Run("decode the error", func(t *testing.T) {
+		var hookErr error
+		client := NewClient(server.URL, nil, ClientHooks{
+			Error: func(ctx context.Context, info ClientHookInfo, err error) error {
+				hookErr = err
+				return err
+			},
+		})
+		err := client.Call(ctx, "/echo", &echoMessage{}, &echoMessage{})
+		require.Equal(t, hookErr, err)
+		xerr, ok := err.(*xerrors.APIError)
+		require.True(t, ok)
+		require.Equal(t, xerrors.Unauthenticated, xerr.Code)
+		require.Equal(t, "missing key", xerr.Message)
+		require.Equal(t, "X-API-Key", xerr.Meta["header"])
+	})
+
+	t.Run("error from a proxy", func(t *testing.T) {
+		client := NewClient(server.URL, nil)
+		err := client.Call(ctx, "/proxy", &echoMessage{}, &echoMessage{})
+		require.Equal(t, xerrors.Unavailable, xerrors.GetCode(err))
+	})
+
+	t.Run("stop on hook error", func(t *testing.T) {
+		client := NewClient(server.URL, nil, ClientHooks{
+			RequestPrepared: func(ctx context.Context, info ClientHookInfo) (context.Context, error) {
+				return ctx, xerrors.Errorf(xerrors.FailedPrecondition, nil, "no token")
+			},
+		})
+		err := client.Call(ctx, "/echo", &echoMessage{}, &echoMessage{})
+		require.Equal(t, xerrors.FailedPrecondition, xerrors.GetCode(err))
+	})
+}
@@ -0,0 +1,160 @@
+package httprpc
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// Codec decodes the requests and encodes the responses of a content type.
+type Codec interface {
+	// Name is used in the error messages, such as "json".
+	Name() string
+
+	ContentType() string
+
+	Unmarshal(data []byte, msg Message) error
+
+	Marshal(msg Message) ([]byte, error)
+}
+
+const (
+	ContentTypeJSON     = "application/json"
+	ContentTypeForm     = "application/x-www-form-urlencoded"
+	ContentTypeProtobuf = "application/protobuf"
+)
+
+var (
+	JSONCodec     Codec = jsonCodec{}
+	FormCodec     Codec = formCodec{}
+	ProtobufCodec Codec = protobufCodec{}
+)
+
+var codecs = struct {
+	sync.RWMutex
+	m map[string]Codec
+}{m: map[string]Codec{}}
+
+func init() {
+	RegisterCodec(JSONCodec)
+	RegisterCodec(FormCodec)
+	RegisterCodec(ProtobufCodec, "application/x-protobuf")
+}
+
+// RegisterCodec registers the codec by its content type, and the given
+// aliases. It replaces the codec previously registered for them.
+func RegisterCodec(codec Codec, aliases ...string) {
+	codecs.Lock()
+	defer codecs.Unlock()
+	for _, contentType := range append([]string{codec.ContentType()}, aliases...) {
+		codecs.m[strings.ToLower(contentType)] = codec
+	}
+}
+
+// CodecByContentType returns the codec of the header value, such as
+// "application/json; charset=utf-8", or nil.
+func CodecByContentType(header string) Codec {
+	if i := strings.Index(header, ";"); i >= 0 {
+		header = header[:i]
+	}
+	codecs.RLock()
+	defer codecs.RUnlock()
+	return codecs.m[strings.TrimSpace(strings.ToLower(header))]
+}
+
+// CodecByAccept returns the codec of the response, by the preference of the
+// Accept header. It returns JSONCodec when no registered content type is
+// accepted, as the responses were always JSON.
+func CodecByAccept(header string) Codec {
+	type accepted struct {
+		contentType string
+		q           float64
+	}
+	var list []accepted
+	for _, part := range strings.Split(header, ",") {
+		params := strings.Split(part, ";")
+		a := accepted{contentType: strings.TrimSpace(params[0]), q: 1}
+		for _, param := range params[1:] {
+			param = strings.TrimSpace(param)
+			if strings.HasPrefix(param, "q=") {
+				a.q, _ = strconv.ParseFloat(param[2:], 64)
+			}
+		}
+		if a.contentType != "" && a.q > 0 {
+			list = append(list, a)
+		}
+	}
+	sort.SliceStable(list, func(i, j int) bool { return list[i].q > list[j].q })
+	for _, a := range list {
+		if a.contentType == "*/*" || a.contentType == "application/*" {
+			return JSONCodec
+		}
+		if codec := CodecByContentType(a.contentType); codec != nil {
+			return codec
+		}
+	}
+	return JSONCodec
+}
+
+type jsonCodec struct{}
+
+func (jsonCodec) Name() string        { return "json" }
+func (jsonCodec) ContentType() string { return ContentTypeJSON }
+
+func (jsonCodec) Unmarshal(data []byte, msg Message) error {
+	return json.NewDecoder(bytes.NewReader(data)).Decode(msg)
+}
+
+func (jsonCodec) Marshal(msg Message) ([]byte, error) {
+	var buf bytes.Buffer
+	err := json.NewEncoder(&buf).Encode(msg)
+	return buf.Bytes(), err
+}
+
+// The form and protobuf codecs convert the messages from and to their JSON
+// tree, with the registered schemas, so the messages keep their JSON encoding,
+// such as dot.IntID and the unions.
+
+// toTree returns the JSON tree of the message, with json.Number for the
+// numbers.
+func toTree(msg Message) (map[string]interface{}, error) {
+	data, err := json.Marshal(msg)
+	if err != nil {
+		return nil, err
+	}
+	var tree map[string]interface{}
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	if err = dec.Decode(&tree); err != nil {
+		return nil, err
+	}
+	return tree, nil
+}
+
+func fromTree(tree map[string]interface{}, msg Message) error {
+	data, err := json.Marshal(tree)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, msg)
+}
+
+func requireSchemaOf(msg Message) (*Schema, error) {
+	schema := SchemaOf(msg)
+	if schema == nil {
+		return nil, fmt.Errorf("no schema for %T", msg)
+	}
+	return schema, nil
+}
+
+func requireSchema(name string) (*Schema, error) {
+	schema := LookupSchema(name)
+	if schema == nil {
+		return nil, fmt.Errorf("no schema %v", name)
+	}
+	return schema, nil
+}
@@ -0,0 +1,215 @@
+package httprpc
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+type codecItem struct {
+	SKU string `json:"sku"`
+	Qty int    `json:"qty"`
+}
+
+type codecMessage struct {
+	ID     int64             `json:"id,string"`
+	Name   string            `json:"name"`
+	Price  float64           `json:"price"`
+	Active bool              `json:"active"`
+	Tags   []string          `json:"tags"`
+	Counts []int             `json:"counts"`
+	Attrs  map[string]string `json:"attrs"`
+	Items  []*codecItem      `json:"items"`
+	Extra  json.RawMessage   `json:"extra,omitempty"`
+	Data   []byte            `json:"data"`
+}
+
+func init() {
+	RegisterSchemas(
+		&Schema{Name: "github.com/olvrng/rbot/be/pkg/httprpc.codecItem", Fields: []Field{
+			{Name: "sku", Number: 1, Kind: KindString},
+			{Name: "qty", Number: 2, Kind: KindInt},
+		}},
+		&Schema{Name: "github.com/olvrng/rbot/be/pkg/httprpc.codecMessage", Fields: []Field{
+			{Name: "id", Number: 1, Kind: KindInt, AsString: true},
+			{Name: "name", Number: 2, Kind: KindString},
+			{Name: "price", Number: 3, Kind: KindFloat},
+			{Name: "active", Number: 4, Kind: KindBool},
+			{Name: "tags", Number: 5, Kind: KindString, Repeated: true},
+			{Name: "counts", Number: 6, Kind: KindInt, Repeated: true},
+			{Name: "attrs", Number: 7, Kind: KindString, Map: true},
+			{Name: "items", Number: 8, Kind: KindMessage, Repeated: true, Message: "github.com/olvrng/rbot/be/pkg/httprpc.codecItem"},
+			{Name: "extra", Number: 9, Kind: KindJSON},
+			{Name: "data", Number: 10, Kind: KindBytes},
+		}},
+	)
+}
+
+func TestCodecs(t *testing.T) {
+	msg := &codecMessage{
+		ID:     -12,
+		Name:   "Lan & Co",
+		Price:  9.5,
+		Active: true,
+		Tags:   []string{"vip", "new"},
+		Counts: []int{1, -2, 300},
+		Attrs:  map[string]string{"color": "red", "size": ""},
+		Items:  []*codecItem{{SKU: "A1", Qty: 2}, {SKU: "B2"}},
+		Extra:  json.RawMessage(`{"note":"gift"}`),
+		Data:   []byte{0, 1, 2},
+	}
+	for _, codec := range []Codec{JSONCodec, FormCodec, ProtobufCodec} {
+		t.Run(codec.Name(), func(t *testing.T) {
+			data, err := codec.Marshal(msg)
+			require.NoError(t, err)
+			var decoded codecMessage
+			require.NoError(t, codec.Unmarshal(data, &decoded))
+			require.Equal(t, msg, &decoded)
+		})
+	}
+	t.Run("no schema", func(t *testing.T) {
+		_, err := ProtobufCodec.Marshal(&echoMessage{})
+		require.EqualError(t, err, "no schema for *httprpc.echoMessage")
+	})
+}
+
+func TestFormCodec(t *testing.T) {
+	t.Run("php conventions", func(t *testing.T) {
+		form := "id=12&name=Lan&price=&active=on&tags[]=vip&tags[]=new&counts=1&counts=2" +
+			"&attrs[color]=red&items[1][sku]=B2&items[0][sku]=A1&items[0][qty]=2&unknown=x"
+		var msg codecMessage
+		require.NoError(t, FormCodec.Unmarshal([]byte(form), &msg))
+		require.Equal(t, codecMessage{
+			ID:     12,
+			Name:   "Lan",
+			Active: true,
+			Tags:   []string{"vip", "new"},
+			Counts: []int{1, 2},
+			Attrs:  map[string]string{"color": "red"},
+			Items:  []*codecItem{{SKU: "A1", Qty: 2}, {SKU: "B2"}},
+		}, msg)
+	})
+
+	tests := []struct {
+		form string
+		err  string
+	}{
+		{"price=abc", "price: invalid number \"abc\""},
+		{"counts=1.5", "counts: invalid number \"1.5\""},
+		{"active=maybe", "active: invalid bool \"maybe\""},
+		{"items[x][sku]=A", "items: invalid index \"x\""},
+		{"items[0]=A", "items[0] must have nested fields"},
+		{"name[first]=Lan", "name must not have nested fields"},
+		{"items[][sku]=A", "invalid key \"items[][sku]\""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.form, func(t *testing.T) {
+			var msg codecMessage
+			err := FormCodec.Unmarshal([]byte(tt.form), &msg)
+			require.EqualError(t, err, tt.err)
+		})
+	}
+}
+
+func TestProtobufCodec(t *testing.T) {
+	data := []byte{
+		0x08, 0x0c, // id: 12
+		0x12, 0x03, 'L', 'a', 'n', // name: "Lan"
+		0x32, 0x02, 0x01, 0x02, // counts: packed [1, 2]
+		0x30, 0x03, // counts: 3, not packed
+		0x78, 0x05, // unknown field 15
+		0x42, 0x03, 0x0a, 0x01, 'A', // items: [{sku: "A"}]
+		0x3a, 0x05, 0x0a, 0x01, 'k', 0x12, 0x00, // attrs: {k: ""}
+		0x4a, 0x02, '4', '2', // extra: 42
+	}
+	var msg codecMessage
+	require.NoError(t, ProtobufCodec.Unmarshal(data, &msg))
+	require.Equal(t, codecMessage{
+		ID:     12,
+		Name:   "Lan",
+		Counts: []int{1, 2, 3},
+		Items:  []*codecItem{{SKU: "A"}},
+		Attrs:  map[string]string{"k": ""},
+		Extra:  json.RawMessage(`42`),
+	}, msg)
+
+	err := ProtobufCodec.Unmarshal(data[:4], &msg)
+	require.EqualError(t, err, "truncated protobuf message")
+	err = ProtobufCodec.Unmarshal([]byte{0x0a, 0x00}, &msg)
+	require.EqualError(t, err, "id: unexpected wire type 2")
+}
+
+func TestCodecByAccept(t *testing.T) {
+	tests := []struct {
+		accept   string
+		expected Codec
+	}{
+		{"", JSONCodec},
+		{"*/*", JSONCodec},
+		{"text/html", JSONCodec},
+		{"application/protobuf", ProtobufCodec},
+		{"application/x-protobuf", ProtobufCodec},
+		{"application/json;q=0.5, application/protobuf", ProtobufCodec},
+		{"application/protobuf;q=0.5, application/json", JSONCodec},
+		{"application/protobuf;q=0, */*", JSONCodec},
+		{"text/html, application/x-www-form-urlencoded;q=0.8", FormCodec},
+	}
+	for _, tt := range tests {
+		require.Equal(t, tt.expected, CodecByAccept(tt.accept), tt.accept)
+	}
+}
+
+func TestParseRequestHeader(t *testing.T) {
+	exec := func(ctx context.Context) (context.Context, Message, error) {
+		return ctx, &codecItem{SKU: "A1", Qty: 2}, nil
+	}
+	serve := func(method, target, contentType, accept, body string, allowGET bool) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, target, strings.NewReader(body))
+		req.Header.Set("Content-Type", contentType)
+		req.Header.Set("Accept", accept)
+		w := httptest.NewRecorder()
+		hooks := WrapHooks(nil)
+		fn, err := ParseRequestHeader(req, allowGET)
+		if err != nil {
+			WriteError(req.Context(), w, hooks, HookInfo{}, err)
+			return w
+		}
+		fn(req.Context(), w, req, hooks, &HookInfo{}, &codecItem{}, exec)
+		return w
+	}
+	errorMsg := func(w *httptest.ResponseRecorder) string {
+		var tj twerrJSON
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &tj))
+		return tj.Msg
+	}
+
+	w := serve("POST", "/item", "application/json", "", `{"sku":"A1"}`, false)
+	require.Equal(t, http.StatusOK, w.Code)
+	require.Equal(t, "application/json", w.Header().Get("Content-Type"))
+	require.JSONEq(t, `{"sku":"A1","qty":2}`, w.Body.String())
+
+	w = serve("POST", "/item", "application/x-www-form-urlencoded", "application/protobuf", "sku=A1", false)
+	require.Equal(t, http.StatusOK, w.Code)
+	require.Equal(t, "application/protobuf", w.Header().Get("Content-Type"))
+	require.Equal(t, []byte{0x0a, 0x02, 'A', '1', 0x10, 0x02}, w.Body.Bytes())
+
+	w = serve("GET", "/item?sku=A1", "", "", "", true)
+	require.Equal(t, http.StatusOK, w.Code)
+
+	w = serve("GET", "/item?sku=A1", "", "", "", false)
+	require.Equal(t, `unsupported method "GET" (only POST is allowed)`, errorMsg(w))
+
+	w = serve("PUT", "/item", "application/json", "", "{}", true)
+	require.Equal(t, `unsupported method "PUT" (only GET and POST are allowed)`, errorMsg(w))
+
+	w = serve("POST", "/item", "text/plain", "", "", false)
+	require.Equal(t, `unexpected Content-Type: "text/plain"`, errorMsg(w))
+
+	w = serve("POST", "/item", "application/x-www-form-urlencoded", "", "qty=two", false)
+	require.Equal(t, "the form request could not be decoded", errorMsg(w))
+}
@@ -0,0 +1,298 @@
+package httprpc
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// formCodec decodes the application/x-www-form-urlencoded requests and the
+// query of the GET requests, with the PHP conventions for the nested fields:
+//
+//	id=12&customer[name]=Lan&tags[]=vip&tags[]=new&items[0][sku]=A1&attrs[color]=red
+//
+// The repeated fields also accept the repeated keys (tags=vip&tags=new), and
+// the empty numbers are skipped as in the html forms. The responses are
+// encoded with the same conventions.
+type formCodec struct{}
+
+func (formCodec) Name() string        { return "form" }
+func (formCodec) ContentType() string { return ContentTypeForm }
+
+func (formCodec) Unmarshal(data []byte, msg Message) error {
+	schema, err := requireSchemaOf(msg)
+	if err != nil {
+		return err
+	}
+	values, err := url.ParseQuery(string(data))
+	if err != nil {
+		return err
+	}
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	root := &formNode{}
+	for _, key := range keys {
+		path, ok := splitFormKey(key)
+		if !ok {
+			return fmt.Errorf("invalid key %q", key)
+		}
+		root.add(path, values[key])
+	}
+	tree, err := root.message(schema, "")
+	if err != nil {
+		return err
+	}
+	return fromTree(tree, msg)
+}
+
+func (formCodec) Marshal(msg Message) ([]byte, error) {
+	tree, err := toTree(msg)
+	if err != nil {
+		return nil, err
+	}
+	values := url.Values{}
+	flattenForm(values, "", tree)
+	return []byte(values.Encode()), nil
+}
+
+// splitFormKey splits "items[0][sku]" into its path. The trailing "[]" of the
+// repeated fields is dropped.
+func splitFormKey(key string) (path []string, ok bool) {
+	idx := strings.IndexByte(key, '[')
+	if idx < 0 {
+		return []string{key}, key != ""
+	}
+	path = append(path, key[:idx])
+	for rest := key[idx:]; rest != ""; {
+		end := strings.IndexByte(rest, ']')
+		if rest[0] != '[' || end < 0 {
+			return nil, false
+		}
+		path = append(path, rest[1:end])
+		rest = rest[end+1:]
+	}
+	if path[len(path)-1] == "" {
+		path = path[:len(path)-1]
+	}
+	for _, seg := range path {
+		if seg == "" {
+			return nil, false
+		}
+	}
+	return path, true
+}
+
+type formNode struct {
+	values   []string
+	children map[string]*formNode
+}
+
+func (n *formNode) add(path []string, values []string) {
+	for _, seg := range path {
+		if n.children == nil {
+			n.children = map[string]*formNode{}
+		}
+		child := n.children[seg]
+		if child == nil {
+			child = &formNode{}
+			n.children[seg] = child
+		}
+		n = child
+	}
+	n.values = append(n.values, values...)
+}
+
+func (n *formNode) message(schema *Schema, path string) (map[string]interface{}, error) {
+	if len(n.values) != 0 && path != "" {
+		return nil, fmt.Errorf("%v must have nested fields", path)
+	}
+	obj := map[string]interface{}{}
+	for name, child := range n.children {
+		f := schema.FieldByName(name)
+		if f == nil {
+			continue // the unknown fields are ignored, as in json
+		}
+		v, err := child.field(f, joinFormKey(path, name))
+		if err != nil {
+			return nil, err
+		}
+		if v != nil {
+			obj[name] = v
+		}
+	}
+	return obj, nil
+}
+
+func (n *formNode) field(f *Field, path string) (interface{}, error) {
+	switch {
+	case f.Map:
+		obj := map[string]interface{}{}
+		for key, child := range n.children {
+			v, err := child.value(f, joinFormKey(path, key))
+			if err != nil {
+				return nil, err
+			}
+			obj[key] = v
+		}
+		return obj, nil
+
+	case f.Repeated:
+		var list []interface{}
+		indexes := make([]int, 0, len(n.children))
+		for key := range n.children {
+			i, err := strconv.Atoi(key)
+			if err != nil || i < 0 {
+				return nil, fmt.Errorf("%v: invalid index %q", path, key)
+			}
+			indexes = append(indexes, i)
+		}
+		sort.Ints(indexes)
+		for _, i := range indexes {
+			key := strconv.Itoa(i)
+			v, err := n.children[key].value(f, joinFormKey(path, key))
+			if err != nil {
+				return nil, err
+			}
+			list = append(list, v)
+		}
+		for _, s := range n.values {
+			v, err := formScalar(f, s, path)
+			if err != nil {
+				return nil, err
+			}
+			list = append(list, v)
+		}
+		return list, nil
+
+	default:
+		return n.value(f, path)
+	}
+}
+
+func (n *formNode) value(f *Field, path string) (interface{}, error) {
+	switch {
+	case f.Kind == KindMessage:
+		schema, err := requireSchema(f.Message)
+		if err != nil {
+			return nil, err
+		}
+		return n.message(schema, path)
+	case len(n.children) != 0 && f.Kind == KindJSON:
+		return n.generic(), nil
+	case len(n.children) != 0:
+		return nil, fmt.Errorf("%v must not have nested fields", path)
+	case len(n.values) == 0:
+		return nil, nil
+	default:
+		return formScalar(f, n.values[len(n.values)-1], path)
+	}
+}
+
+// generic returns the nested fields of the KindJSON values as strings.
+func (n *formNode) generic() interface{} {
+	if len(n.children) == 0 {
+		if len(n.values) == 0 {
+			return nil
+		}
+		return n.values[len(n.values)-1]
+	}
+	obj := map[string]interface{}{}
+	for key, child := range n.children {
+		obj[key] = child.generic()
+	}
+	return obj
+}
+
+func formScalar(f *Field, s string, path string) (interface{}, error) {
+	switch f.Kind {
+	case KindString, KindBytes: // the bytes are base64, as in json
+		return s, nil
+
+	case KindBool:
+		if s == "" {
+			return nil, nil
+		}
+		if s == "on" {
+			return true, nil
+		}
+		b, err := strconv.ParseBool(s)
+		if err != nil {
+			return nil, fmt.Errorf("%v: invalid bool %q", path, s)
+		}
+		return b, nil
+
+	case KindInt, KindUint, KindFloat:
+		if s == "" {
+			return nil, nil
+		}
+		num, ok := parseNumber(f.Kind, s)
+		if !ok {
+			return nil, fmt.Errorf("%v: invalid number %q", path, s)
+		}
+		if f.AsString {
+			return num, nil
+		}
+		return json.Number(num), nil
+
+	case KindJSON:
+		if json.Valid([]byte(s)) {
+			return json.RawMessage(s), nil
+		}
+		return s, nil
+
+	default:
+		return nil, fmt.Errorf("%v must have nested fields", path)
+	}
+}
+
+// parseNumber returns the number in its canonical form, which is valid in
+// json.
+func parseNumber(kind Kind, s string) (string, bool) {
+	switch kind {
+	case KindInt:
+		i, err := strconv.ParseInt(s, 10, 64)
+		return strconv.FormatInt(i, 10), err == nil
+	case KindUint:
+		u, err := strconv.ParseUint(s, 10, 64)
+		return strconv.FormatUint(u, 10), err == nil
+	default:
+		v, err := strconv.ParseFloat(s, 64)
+		if err != nil || math.IsNaN(v) || math.IsInf(v, 0) {
+			return "", false
+		}
+		return strconv.FormatFloat(v, 'g', -1, 64), true
+	}
+}
+
+func joinFormKey(path, key string) string {
+	if path == "" {
+		return key
+	}
+	return path + "[" + key + "]"
+}
+
+func flattenForm(values url.Values, path string, v interface{}) {
+	switch v := v.(type) {
+	case map[string]interface{}:
+		for key, child := range v {
+			flattenForm(values, joinFormKey(path, key), child)
+		}
+	case []interface{}:
+		for i, child := range v {
+			flattenForm(values, joinFormKey(path, strconv.Itoa(i)), child)
+		}
+	case string:
+		values.Add(path, v)
+	case json.Number:
+		values.Add(path, v.String())
+	case bool:
+		values.Add(path, strconv.FormatBool(v))
+	}
+}
@@ -1,13 +1,12 @@
 package httprpc
 
 import (
-	"bytes"
 	"context"
 	"encoding/json"
 	"fmt"
+	"io/ioutil"
 	"net/http"
 	"strconv"
-	"strings"
 
 	"github.com/olvrng/rbot/be/pkg/xerrors"
 )
@@ -146,21 +145,28 @@ func errFromPanic(p interface{}) error {
 type ExecFunc func(context.Context) (ctx context.Context, respContent Message, err error)
 type ServeFunc func(ctx context.Context, resp http.ResponseWriter, req *http.Request, hooks Hooks, info *HookInfo, reqContent Message, fn ExecFunc)
 
-func ParseRequestHeader(req *http.Request) (ServeFunc, error) {
-	if req.Method != "POST" {
-		msg := fmt.Sprintf("unsupported method %q (only POST is allowed)", req.Method)
-		return nil, BadRouteError(msg, req.Method, req.URL.Path)
-	}
-	header := req.Header.Get("Content-Type")
-	i := strings.Index(header, ";")
-	if i == -1 {
-		i = len(header)
-	}
-	switch strings.TrimSpace(strings.ToLower(header[:i])) {
-	case "application/json":
-		return ServeJSON, nil
+// ParseRequestHeader returns the ServeFunc of the request, with the codec of
+// its Content-Type and the codec of the response by its Accept header. The GET
+// requests are only allowed on the methods with the +api:get directive, with
+// the query as the request.
+func ParseRequestHeader(req *http.Request, allowGET bool) (ServeFunc, error) {
+	switch {
+	case req.Method == http.MethodPost:
+		codec := CodecByContentType(req.Header.Get("Content-Type"))
+		if codec == nil {
+			msg := fmt.Sprintf("unexpected Content-Type: %q", req.Header.Get("Content-Type"))
+			return nil, BadRouteError(msg, req.Method, req.URL.Path)
+		}
+		return Serve(codec, CodecByAccept(req.Header.Get("Accept"))), nil
+
+	case req.Method == http.MethodGet && allowGET:
+		return Serve(FormCodec, CodecByAccept(req.Header.Get("Accept"))), nil
+
 	default:
-		msg := fmt.Sprintf("unexpected Content-Type: %q", req.Header.Get("Content-Type"))
+		msg := fmt.Sprintf("unsupported method %q (only POST is allowed)", req.Method)
+		if allowGET {
+			msg = fmt.Sprintf("unsupported method %q (only GET and POST are allowed)", req.Method)
+		}
 		return nil, BadRouteError(msg, req.Method, req.URL.Path)
 	}
 }
@@ -174,44 +180,75 @@ func ServeJSON(
 	reqContent Message,
 	fn ExecFunc,
 ) {
-	if err := json.NewDecoder(req.Body).Decode(reqContent); err != nil {
-		WriteError(ctx, resp, hooks, *info, malformedRequestError("the json request could not be decoded").WithMeta("cause", err.Error()))
-		return
-	}
-	info.Request = reqContent
-
-	var err error
-	var respContent Message
-	func() {
-		defer ensurePanicResponses(ctx, resp, hooks, *info)
-		ctx, respContent, err = fn(ctx)
-	}()
-	if err != nil {
-		WriteError(ctx, resp, hooks, *info, err)
-		return
-	}
-	if respContent == nil {
-		WriteError(ctx, resp, hooks, *info, internalError("received a nil response"))
-		return
-	}
-	info.Response = respContent
-	ctx, err = hooks.ResponsePrepared(ctx, *info, resp.Header())
-	if err != nil {
-		WriteError(ctx, resp, hooks, *info, err)
-		return
-	}
+	Serve(JSONCodec, JSONCodec)(ctx, resp, req, hooks, info, reqContent, fn)
+}
 
-	var buf bytes.Buffer
-	if err = json.NewEncoder(&buf).Encode(respContent); err != nil {
-		WriteError(ctx, resp, hooks, *info, xerrors.Errorf(xerrors.Internal, err, "failed to marshal json response: %v"))
-		return
-	}
-	respBytes := buf.Bytes()
-	resp.Header().Set("Content-Type", "application/json")
-	resp.Header().Set("Content-Length", strconv.Itoa(len(respBytes)))
-	resp.WriteHeader(http.StatusOK)
-	defer hooks.ResponseSent(ctx, *info)
-	if _, err = resp.Write(respBytes); err != nil {
-		return
+// Serve returns the ServeFunc which decodes the request with reqCodec, and
+// encodes the response with respCodec. The request of the GET methods is
+// their query. The errors are always JSON. The streaming methods start the
+// event stream with StartStream instead of returning a response.
+func Serve(reqCodec, respCodec Codec) ServeFunc {
+	return func(
+		ctx context.Context,
+		resp http.ResponseWriter,
+		req *http.Request,
+		hooks Hooks,
+		info *HookInfo,
+		reqContent Message,
+		fn ExecFunc,
+	) {
+		data := []byte(req.URL.RawQuery)
+		if req.Method != http.MethodGet {
+			var err error
+			if data, err = ioutil.ReadAll(req.Body); err != nil {
+				WriteError(ctx, resp, hooks, *info, malformedRequestError("the request could not be read").WithMeta("cause", err.Error()))
+				return
+			}
+		}
+		if err := reqCodec.Unmarshal(data, reqContent); err != nil {
+			msg := fmt.Sprintf("the %v request could not be decoded", reqCodec.Name())
+			WriteError(ctx, resp, hooks, *info, malformedRequestError(msg).WithMeta("cause", err.Error()))
+			return
+		}
+		info.Request = reqContent
+
+		var err error
+		var respContent Message
+		stream := newEventStream(resp, hooks, info)
+		func() {
+			defer ensurePanicResponses(ctx, resp, hooks, *info)
+			ctx, respContent, err = fn(context.WithValue(ctx, streamKey{}, stream))
+		}()
+		if stream.isStarted() {
+			stream.finish(ctx, err)
+			return
+		}
+		if err != nil {
+			WriteError(ctx, resp, hooks, *info, err)
+			return
+		}
+		if respContent == nil {
+			WriteError(ctx, resp, hooks, *info, internalError("received a nil response"))
+			return
+		}
+		info.Response = respContent
+		ctx, err = hooks.ResponsePrepared(ctx, *info, resp.Header())
+		if err != nil {
+			WriteError(ctx, resp, hooks, *info, err)
+			return
+		}
+
+		respBytes, err := respCodec.Marshal(respContent)
+		if err != nil {
+			WriteError(ctx, resp, hooks, *info, xerrors.Errorf(xerrors.Internal, err, "failed to marshal %v response", respCodec.Name()))
+			return
+		}
+		resp.Header().Set("Content-Type", respCodec.ContentType())
+		resp.Header().Set("Content-Length", strconv.Itoa(len(respBytes)))
+		resp.WriteHeader(http.StatusOK)
+		defer hooks.ResponseSent(ctx, *info)
+		if _, err = resp.Write(respBytes); err != nil {
+			return
+		}
 	}
 }
@@ -0,0 +1,61 @@
+package httprpc
+
+import (
+	"context"
+	"time"
+
+	"github.com/olvrng/rbot/be/pkg/metrics"
+)
+
+// RouteOther is the route label of the requests which are not decoded, such as
+// the unknown paths, to bound the number of series.
+const RouteOther = "other"
+
+// MetricsHooks counts the requests of the servers and observes their latency,
+// by route and error code ("ok" for the successful responses):
+//
+//	rbot_http_requests_total{route="/api/flow/def/query/GetFlowByID",code="ok"} 12
+//	rbot_http_request_duration_seconds_bucket{route="/api/flow/def/query/GetFlowByID",code="ok",le="0.05"} 11
+//
+// The streams are observed when they end.
+type MetricsHooks struct {
+	requests *metrics.Counter
+	duration *metrics.Histogram
+}
+
+var _ HooksBuilder = &MetricsHooks{}
+
+// NewMetricsHooks registers the metrics of the requests on the registry. It is
+// called once per registry, and the hooks are added to all the servers.
+func NewMetricsHooks(r *metrics.Registry) *MetricsHooks {
+	return &MetricsHooks{
+		requests: r.NewCounter("rbot_http_requests_total", "The api requests, by route and error code.", "route", "code"),
+		duration: r.NewHistogram("rbot_http_request_duration_seconds", "The latency of the api requests, by route and error code.", nil, "route", "code"),
+	}
+}
+
+// BuildHooks is called for each request, so the hooks keep its start time.
+func (h *MetricsHooks) BuildHooks() Hooks {
+	start := time.Now()
+	observe := func(info HookInfo, code string) {
+		route := info.Route
+		if info.Request == nil {
+			route = RouteOther
+		}
+		h.requests.Inc(route, code)
+		h.duration.Observe(time.Since(start).Seconds(), route, code)
+	}
+	return Hooks{
+		RequestReceived: func(ctx context.Context, info HookInfo) (context.Context, error) {
+			start = time.Now()
+			return ctx, nil
+		},
+		ResponseSent: func(ctx context.Context, info HookInfo) {
+			observe(info, "ok")
+		},
+		Error: func(ctx context.Context, info HookInfo, err error) (context.Context, error) {
+			observe(info, TwirpError(err).Code().String())
+			return ctx, err
+		},
+	}
+}
@@ -0,0 +1,48 @@
+package httprpc
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/olvrng/rbot/be/pkg/metrics"
+	"github.com/olvrng/rbot/be/pkg/xerrors"
+)
+
+func TestMetricsHooks(t *testing.T) {
+	registry := metrics.NewRegistry()
+	builder := NewMetricsHooks(registry)
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hooks := WrapHooks(builder)
+		ctx, info := r.Context(), &HookInfo{Route: r.URL.Path, HTTPRequest: r}
+		ctx, _ = hooks.RequestReceived(ctx, *info)
+		req := &echoMessage{}
+		Serve(JSONCodec, JSONCodec)(ctx, w, r, hooks, info, req, func(ctx context.Context) (context.Context, Message, error) {
+			if req.Text == "" {
+				return ctx, nil, xerrors.Errorf(xerrors.InvalidArgument, nil, "text is required")
+			}
+			return ctx, req, nil
+		})
+	})
+	post := func(path, body string) {
+		r := httptest.NewRequest(http.MethodPost, path, strings.NewReader(body))
+		handler.ServeHTTP(httptest.NewRecorder(), r)
+	}
+	post("/echo", `{"text":"hello"}`)
+	post("/echo", `{"text":"hello"}`)
+	post("/echo", `{}`)
+	post("/echo", `{`)
+
+	rec := httptest.NewRecorder()
+	metrics.Handler(registry).ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	text := rec.Body.String()
+	require.Contains(t, text, `rbot_http_requests_total{route="/echo",code="ok"} 2`)
+	require.Contains(t, text, `rbot_http_requests_total{route="/echo",code="invalid_argument"} 1`)
+	require.Contains(t, text, `rbot_http_requests_total{route="other",`) // the malformed request
+	require.Contains(t, text, `rbot_http_request_duration_seconds_count{route="/echo",code="ok"} 2`)
+	require.Contains(t, text, `rbot_http_request_duration_seconds_bucket{route="/echo",code="ok",le="+Inf"} 2`)
+}
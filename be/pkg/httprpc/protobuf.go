@@ -0,0 +1,397 @@
+package httprpc
+
+import (
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+)
+
+// protobufCodec encodes the messages in the protobuf wire format (proto3), with
+// the field numbers of their schemas:
+//
+//   - KindInt and KindUint are int64 and uint64, KindFloat is double;
+//   - the named string types, such as the statuses, are strings;
+//   - KindJSON is bytes, with the JSON of the value;
+//   - the maps have string keys;
+//   - the repeated numbers are packed.
+type protobufCodec struct{}
+
+const (
+	wireVarint  = 0
+	wireFixed64 = 1
+	wireBytes   = 2
+	wireFixed32 = 5
+)
+
+var errTruncated = errors.New("truncated protobuf message")
+
+func (protobufCodec) Name() string        { return "protobuf" }
+func (protobufCodec) ContentType() string { return ContentTypeProtobuf }
+
+func (protobufCodec) Unmarshal(data []byte, msg Message) error {
+	schema, err := requireSchemaOf(msg)
+	if err != nil {
+		return err
+	}
+	tree, err := decodeProto(schema, data)
+	if err != nil {
+		return err
+	}
+	return fromTree(tree, msg)
+}
+
+func (protobufCodec) Marshal(msg Message) ([]byte, error) {
+	schema, err := requireSchemaOf(msg)
+	if err != nil {
+		return nil, err
+	}
+	tree, err := toTree(msg)
+	if err != nil {
+		return nil, err
+	}
+	return encodeProto(nil, schema, tree)
+}
+
+// mapEntrySchema returns the schema of the entries of a map field.
+func mapEntrySchema(f *Field) *Schema {
+	return &Schema{Fields: []Field{
+		{Name: "key", Number: 1, Kind: KindString},
+		{Name: "value", Number: 2, Kind: f.Kind, AsString: f.AsString, Message: f.Message},
+	}}
+}
+
+func wireType(kind Kind) int {
+	switch kind {
+	case KindBool, KindInt, KindUint:
+		return wireVarint
+	case KindFloat:
+		return wireFixed64
+	default:
+		return wireBytes
+	}
+}
+
+func decodeProto(schema *Schema, data []byte) (map[string]interface{}, error) {
+	obj := map[string]interface{}{}
+	for len(data) > 0 {
+		tag, n := binary.Uvarint(data)
+		if n <= 0 {
+			return nil, errTruncated
+		}
+		data = data[n:]
+		number, wt := int(tag>>3), int(tag&7)
+		if number <= 0 {
+			return nil, fmt.Errorf("invalid field number %v", number)
+		}
+		v, b, n := consumeProtoValue(wt, data)
+		if n < 0 {
+			return nil, errTruncated
+		}
+		data = data[n:]
+		f := schema.FieldByNumber(number)
+		if f == nil {
+			continue // the unknown fields are skipped
+		}
+
+		switch {
+		case f.Map:
+			if wt != wireBytes {
+				return nil, fmt.Errorf("%v: unexpected wire type %v", f.Name, wt)
+			}
+			entry, err := decodeProto(mapEntrySchema(f), b)
+			if err != nil {
+				return nil, err
+			}
+			m, _ := obj[f.Name].(map[string]interface{})
+			if m == nil {
+				m = map[string]interface{}{}
+				obj[f.Name] = m
+			}
+			key, _ := entry["key"].(string)
+			m[key] = entry["value"]
+
+		case f.Repeated && wt == wireBytes && wireType(f.Kind) != wireBytes:
+			list, _ := obj[f.Name].([]interface{})
+			for ewt := wireType(f.Kind); len(b) > 0; {
+				ev, _, n := consumeProtoValue(ewt, b)
+				if n < 0 {
+					return nil, errTruncated
+				}
+				b = b[n:]
+				item, err := decodeProtoValue(f, ewt, ev, nil)
+				if err != nil {
+					return nil, err
+				}
+				list = append(list, item)
+			}
+			obj[f.Name] = list
+
+		default:
+			item, err := decodeProtoValue(f, wt, v, b)
+			if err != nil {
+				return nil, err
+			}
+			if f.Repeated {
+				list, _ := obj[f.Name].([]interface{})
+				obj[f.Name] = append(list, item)
+			} else {
+				obj[f.Name] = item
+			}
+		}
+	}
+	return obj, nil
+}
+
+// consumeProtoValue returns the value of the wire type at the start of data, as
+// a number or bytes, and its length. The length is negative when data is
+// truncated.
+func consumeProtoValue(wt int, data []byte) (v uint64, b []byte, n int) {
+	switch wt {
+	case wireVarint:
+		v, n = binary.Uvarint(data)
+		if n <= 0 {
+			return 0, nil, -1
+		}
+		return v, nil, n
+	case wireFixed64:
+		if len(data) < 8 {
+			return 0, nil, -1
+		}
+		return binary.LittleEndian.Uint64(data), nil, 8
+	case wireFixed32:
+		if len(data) < 4 {
+			return 0, nil, -1
+		}
+		return uint64(binary.LittleEndian.Uint32(data)), nil, 4
+	case wireBytes:
+		length, n := binary.Uvarint(data)
+		if n <= 0 || uint64(len(data)-n) < length {
+			return 0, nil, -1
+		}
+		return 0, data[n : n+int(length)], n + int(length)
+	default:
+		return 0, nil, -1
+	}
+}
+
+func decodeProtoValue(f *Field, wt int, v uint64, b []byte) (interface{}, error) {
+	expected := wireType(f.Kind)
+	if f.Kind == KindFloat && wt == wireFixed32 {
+		expected = wireFixed32
+	}
+	if wt != expected {
+		return nil, fmt.Errorf("%v: unexpected wire type %v", f.Name, wt)
+	}
+	switch f.Kind {
+	case KindBool:
+		return v != 0, nil
+
+	case KindInt:
+		return protoNumber(f, strconv.FormatInt(int64(v), 10)), nil
+
+	case KindUint:
+		return protoNumber(f, strconv.FormatUint(v, 10)), nil
+
+	case KindFloat:
+		value := math.Float64frombits(v)
+		if wt == wireFixed32 {
+			value = float64(math.Float32frombits(uint32(v)))
+		}
+		if math.IsNaN(value) || math.IsInf(value, 0) {
+			return nil, fmt.Errorf("%v: invalid number %v", f.Name, value)
+		}
+		return protoNumber(f, strconv.FormatFloat(value, 'g', -1, 64)), nil
+
+	case KindString:
+		return string(b), nil
+
+	case KindBytes:
+		return append([]byte{}, b...), nil // encoded as base64 in json
+
+	case KindJSON:
+		if !json.Valid(b) {
+			return nil, fmt.Errorf("%v: invalid json", f.Name)
+		}
+		return json.RawMessage(append([]byte{}, b...)), nil
+
+	case KindMessage:
+		schema, err := requireSchema(f.Message)
+		if err != nil {
+			return nil, err
+		}
+		return decodeProto(schema, b)
+
+	default:
+		return nil, fmt.Errorf("%v: unknown kind %v", f.Name, f.Kind)
+	}
+}
+
+func protoNumber(f *Field, s string) interface{} {
+	if f.AsString {
+		return s
+	}
+	return json.Number(s)
+}
+
+func encodeProto(b []byte, schema *Schema, obj map[string]interface{}) (_ []byte, err error) {
+	for i := range schema.Fields {
+		f := &schema.Fields[i]
+		v := obj[f.Name]
+		if v == nil {
+			continue
+		}
+		switch {
+		case f.Map:
+			m, ok := v.(map[string]interface{})
+			if !ok {
+				return nil, fmt.Errorf("%v: expect an object", f.Name)
+			}
+			keys := make([]string, 0, len(m))
+			for key := range m {
+				keys = append(keys, key)
+			}
+			sort.Strings(keys)
+			entrySchema := mapEntrySchema(f)
+			for _, key := range keys {
+				entry, err := encodeProto(nil, entrySchema, map[string]interface{}{"key": key, "value": m[key]})
+				if err != nil {
+					return nil, err
+				}
+				b = appendProtoBytes(b, f.Number, entry)
+			}
+
+		case f.Repeated:
+			list, ok := v.([]interface{})
+			if !ok {
+				return nil, fmt.Errorf("%v: expect an array", f.Name)
+			}
+			if wireType(f.Kind) != wireBytes {
+				if len(list) == 0 {
+					continue
+				}
+				var packed []byte
+				for _, item := range list {
+					if packed, err = appendProtoScalar(packed, f, item); err != nil {
+						return nil, err
+					}
+				}
+				b = appendProtoBytes(b, f.Number, packed)
+				continue
+			}
+			for _, item := range list {
+				if b, err = encodeProtoValue(b, f, item); err != nil {
+					return nil, err
+				}
+			}
+
+		default:
+			if b, err = encodeProtoValue(b, f, v); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return b, nil
+}
+
+// encodeProtoValue appends the field with the value. The null items of the
+// arrays are encoded as the zero values.
+func encodeProtoValue(b []byte, f *Field, v interface{}) ([]byte, error) {
+	wt := wireType(f.Kind)
+	if wt != wireBytes {
+		b = appendVarint(b, uint64(f.Number)<<3|uint64(wt))
+		return appendProtoScalar(b, f, v)
+	}
+	var data []byte
+	switch f.Kind {
+	case KindString:
+		s, _ := v.(string)
+		data = []byte(s)
+
+	case KindBytes:
+		s, _ := v.(string)
+		var err error
+		if data, err = base64.StdEncoding.DecodeString(s); err != nil {
+			return nil, fmt.Errorf("%v: %v", f.Name, err)
+		}
+
+	case KindJSON:
+		var err error
+		if data, err = json.Marshal(v); err != nil {
+			return nil, err
+		}
+
+	case KindMessage:
+		schema, err := requireSchema(f.Message)
+		if err != nil {
+			return nil, err
+		}
+		obj, _ := v.(map[string]interface{})
+		if data, err = encodeProto(nil, schema, obj); err != nil {
+			return nil, err
+		}
+	}
+	return appendProtoBytes(b, f.Number, data), nil
+}
+
+func appendProtoScalar(b []byte, f *Field, v interface{}) ([]byte, error) {
+	if f.Kind == KindBool {
+		if v == true {
+			return append(b, 1), nil
+		}
+		return append(b, 0), nil
+	}
+	var s string
+	switch v := v.(type) {
+	case json.Number:
+		s = v.String()
+	case string: // such as dot.IntID
+		s = v
+	case nil:
+		s = "0"
+	default:
+		return nil, fmt.Errorf("%v: expect a number", f.Name)
+	}
+	switch f.Kind {
+	case KindInt:
+		i, err := strconv.ParseInt(s, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("%v: invalid int %q", f.Name, s)
+		}
+		return appendVarint(b, uint64(i)), nil
+	case KindUint:
+		u, err := strconv.ParseUint(s, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("%v: invalid uint %q", f.Name, s)
+		}
+		return appendVarint(b, u), nil
+	default:
+		value, err := strconv.ParseFloat(s, 64)
+		if err != nil {
+			return nil, fmt.Errorf("%v: invalid number %q", f.Name, s)
+		}
+		return appendFixed64(b, math.Float64bits(value)), nil
+	}
+}
+
+func appendProtoBytes(b []byte, number int, data []byte) []byte {
+	b = appendVarint(b, uint64(number)<<3|wireBytes)
+	b = appendVarint(b, uint64(len(data)))
+	return append(b, data...)
+}
+
+func appendVarint(b []byte, v uint64) []byte {
+	var buf [binary.MaxVarintLen64]byte
+	n := binary.PutUvarint(buf[:], v)
+	return append(b, buf[:n]...)
+}
+
+func appendFixed64(b []byte, v uint64) []byte {
+	var buf [8]byte
+	binary.LittleEndian.PutUint64(buf[:], v)
+	return append(b, buf[:]...)
+}
@@ -0,0 +1,105 @@
+package httprpc
+
+import (
+	"reflect"
+	"sync"
+)
+
+// Kind is the type of the values of a field, as encoded by the codecs which are
+// not JSON.
+type Kind int
+
+const (
+	KindString Kind = iota + 1
+	KindBytes
+	KindBool
+	KindInt
+	KindUint
+	KindFloat
+
+	// KindMessage is a nested struct, described by its own schema.
+	KindMessage
+
+	// KindJSON is a value with custom JSON encoding, such as a union or
+	// json.RawMessage. The protobuf codec encodes it as bytes, the form codec as
+	// a string.
+	KindJSON
+)
+
+// Field describes a field of a message, by its json name.
+type Field struct {
+	Name   string
+	Number int // the protobuf field number
+	Kind   Kind
+
+	// AsString is set when the number is encoded as a json string (the ",string"
+	// option).
+	AsString bool
+
+	// Repeated is set for the slices. Map is set for the maps, their keys are
+	// strings as in JSON.
+	Repeated bool
+	Map      bool
+
+	// Message is the schema name of the KindMessage values.
+	Message string
+}
+
+// Schema describes the fields of a message, such as the requests and the
+// responses of the services. The schemas are generated by genapi, and
+// registered with the package path and the name of their Go type, such as
+// "github.com/olvrng/rbot/be/com/flowdef/types.GetFlowByIDRequest".
+type Schema struct {
+	Name   string
+	Fields []Field
+}
+
+// FieldByNumber returns the field with the protobuf number, or nil.
+func (s *Schema) FieldByNumber(number int) *Field {
+	for i := range s.Fields {
+		if s.Fields[i].Number == number {
+			return &s.Fields[i]
+		}
+	}
+	return nil
+}
+
+// FieldByName returns the field with the json name, or nil.
+func (s *Schema) FieldByName(name string) *Field {
+	for i := range s.Fields {
+		if s.Fields[i].Name == name {
+			return &s.Fields[i]
+		}
+	}
+	return nil
+}
+
+var schemas sync.Map // map[string]*Schema
+
+// RegisterSchemas is called by the generated code. The packages which share
+// the types register the same schemas, the last one wins.
+func RegisterSchemas(ss ...*Schema) {
+	for _, s := range ss {
+		schemas.Store(s.Name, s)
+	}
+}
+
+// LookupSchema returns the registered schema by its name, or nil.
+func LookupSchema(name string) *Schema {
+	s, _ := schemas.Load(name)
+	schema, _ := s.(*Schema)
+	return schema
+}
+
+// SchemaOf returns the registered schema of the message, such as
+// *types.GetFlowByIDRequest, or nil.
+func SchemaOf(msg Message) *Schema {
+	typ := reflect.TypeOf(msg)
+	for typ != nil && typ.Kind() == reflect.Ptr {
+		typ = typ.Elem()
+	}
+	if typ == nil || typ.Name() == "" {
+		return nil
+	}
+	return LookupSchema(typ.PkgPath() + "." + typ.Name())
+}
@@ -0,0 +1,149 @@
+package httprpc
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/olvrng/rbot/be/pkg/xerrors"
+)
+
+// Stream sends the messages of the streaming methods (+api:stream) to the
+// client, as server-sent events:
+//
+//	data: {"type":"node.entered",...}
+//
+//	event: error
+//	data: {"code":"resource_exhausted","msg":"..."}
+//
+// The error event is written when the method returns an error after the stream
+// is started, then the stream ends. The errors before the stream is started
+// are written as the usual JSON responses.
+type Stream interface {
+	Send(msg Message) error
+}
+
+const ContentTypeEventStream = "text/event-stream"
+
+// StreamHeartbeat is the interval of the comments which keep the idle streams
+// open through the proxies.
+var StreamHeartbeat = 15 * time.Second
+
+type streamKey struct{}
+
+// StartStream calls the ResponsePrepared hook, writes the headers of the event
+// stream and returns it. It is called by the generated servers before the
+// streaming methods.
+func StartStream(ctx context.Context) (Stream, error) {
+	s, _ := ctx.Value(streamKey{}).(*eventStream)
+	if s == nil {
+		return nil, internalError("the request does not support streaming")
+	}
+	if err := s.start(ctx); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+type eventStream struct {
+	resp  http.ResponseWriter
+	hooks Hooks
+	info  *HookInfo
+
+	m       sync.Mutex
+	started bool
+	closed  bool
+	done    chan struct{}
+}
+
+func newEventStream(resp http.ResponseWriter, hooks Hooks, info *HookInfo) *eventStream {
+	return &eventStream{resp: resp, hooks: hooks, info: info, done: make(chan struct{})}
+}
+
+func (s *eventStream) start(ctx context.Context) error {
+	flusher, ok := s.resp.(http.Flusher)
+	if !ok {
+		return internalError("the response writer does not support streaming")
+	}
+	s.m.Lock()
+	defer s.m.Unlock()
+	if s.started {
+		return internalError("the stream is already started")
+	}
+	if _, err := s.hooks.ResponsePrepared(ctx, *s.info, s.resp.Header()); err != nil {
+		return err
+	}
+	header := s.resp.Header()
+	header.Set("Content-Type", ContentTypeEventStream)
+	header.Set("Cache-Control", "no-cache")
+	header.Set("X-Accel-Buffering", "no") // disable the buffering of nginx
+	s.resp.WriteHeader(http.StatusOK)
+	flusher.Flush()
+	s.started = true
+	go s.heartbeat(flusher)
+	return nil
+}
+
+func (s *eventStream) isStarted() bool {
+	s.m.Lock()
+	defer s.m.Unlock()
+	return s.started
+}
+
+func (s *eventStream) Send(msg Message) error {
+	data, err := json.Marshal(msg)
+	if err != nil {
+		return xerrors.Errorf(xerrors.Internal, err, "failed to marshal json event")
+	}
+	s.m.Lock()
+	defer s.m.Unlock()
+	switch {
+	case !s.started:
+		return internalError("the stream is not started")
+	case s.closed:
+		return internalError("the stream is closed")
+	}
+	if _, err = fmt.Fprintf(s.resp, "data: %s\n\n", data); err != nil {
+		return xerrors.Errorf(xerrors.Canceled, err, "the stream is closed by the client")
+	}
+	s.resp.(http.Flusher).Flush()
+	return nil
+}
+
+func (s *eventStream) heartbeat(flusher http.Flusher) {
+	ticker := time.NewTicker(StreamHeartbeat)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-s.done:
+			return
+		case <-ticker.C:
+			s.m.Lock()
+			if !s.closed {
+				_, _ = fmt.Fprint(s.resp, ": ping\n\n")
+				flusher.Flush()
+			}
+			s.m.Unlock()
+		}
+	}
+}
+
+// finish ends the started stream, with the error event when the method
+// returns an error.
+func (s *eventStream) finish(ctx context.Context, err error) {
+	s.m.Lock()
+	defer s.m.Unlock()
+	s.closed = true
+	close(s.done)
+	if err != nil {
+		ctx, err = s.hooks.Error(ctx, *s.info, err)
+		data := marshalErrorToJSON(TwirpError(err))
+		_, _ = fmt.Fprintf(s.resp, "event: error\ndata: %s\n\n", data)
+		s.resp.(http.Flusher).Flush()
+		return
+	}
+	s.hooks.ResponseSent(ctx, *s.info)
+}
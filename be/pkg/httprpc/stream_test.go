@@ -0,0 +1,102 @@
+package httprpc
+
+import (
+	"context"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/olvrng/rbot/be/pkg/xerrors"
+)
+
+func TestStream(t *testing.T) {
+	var calls []string
+	hooks := WrapHooks(HooksFunc(func() Hooks {
+		return Hooks{
+			ResponsePrepared: func(ctx context.Context, info HookInfo, header http.Header) (context.Context, error) {
+				calls = append(calls, "prepared")
+				header.Set("X-Trace", "1")
+				return ctx, nil
+			},
+			ResponseSent: func(ctx context.Context, info HookInfo) {
+				calls = append(calls, "sent")
+			},
+			Error: func(ctx context.Context, info HookInfo, err error) (context.Context, error) {
+				calls = append(calls, "error")
+				return ctx, err
+			},
+		}
+	}))
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		req := &echoMessage{}
+		Serve(JSONCodec, JSONCodec)(r.Context(), w, r, hooks, &HookInfo{}, req, func(ctx context.Context) (context.Context, Message, error) {
+			if req.Text == "denied" {
+				return ctx, nil, xerrors.Errorf(xerrors.PermissionDenied, nil, "denied")
+			}
+			stream, err := StartStream(ctx)
+			if err != nil {
+				return ctx, nil, err
+			}
+			for _, word := range strings.Fields(req.Text) {
+				if err = stream.Send(&echoMessage{Text: word}); err != nil {
+					return ctx, nil, err
+				}
+			}
+			if strings.HasSuffix(req.Text, "!") {
+				return ctx, nil, xerrors.Errorf(xerrors.ResourceExhausted, nil, "too many events")
+			}
+			return ctx, nil, nil
+		})
+	}))
+	defer server.Close()
+	client := NewClient(server.URL, nil)
+	ctx := context.Background()
+
+	stream := func(text string) (words []string, _ error) {
+		calls = nil
+		err := client.Stream(ctx, "/stream", &echoMessage{Text: text},
+			func() Message { return &echoMessage{} },
+			func(msg Message) error {
+				words = append(words, msg.(*echoMessage).Text)
+				return nil
+			})
+		return words, err
+	}
+
+	t.Run("send the events", func(t *testing.T) {
+		words, err := stream("hello world")
+		require.NoError(t, err)
+		require.Equal(t, []string{"hello", "world"}, words)
+		require.Equal(t, []string{"prepared", "sent"}, calls)
+	})
+
+	t.Run("end with the error event", func(t *testing.T) {
+		words, err := stream("hello world!")
+		require.Equal(t, []string{"hello", "world!"}, words)
+		require.Equal(t, xerrors.ResourceExhausted, err.(*xerrors.APIError).Code)
+		require.Equal(t, "too many events", err.(*xerrors.APIError).Message)
+		require.Equal(t, []string{"prepared", "error"}, calls)
+	})
+
+	t.Run("write the error before the stream as json", func(t *testing.T) {
+		words, err := stream("denied")
+		require.Empty(t, words)
+		require.Equal(t, xerrors.PermissionDenied, err.(*xerrors.APIError).Code)
+		require.Equal(t, []string{"error"}, calls)
+	})
+
+	t.Run("write the events", func(t *testing.T) {
+		resp, err := http.Post(server.URL, ContentTypeJSON, strings.NewReader(`{"text":"a b"}`))
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		body, err := ioutil.ReadAll(resp.Body)
+		require.NoError(t, err)
+		require.Equal(t, ContentTypeEventStream, resp.Header.Get("Content-Type"))
+		require.Equal(t, "1", resp.Header.Get("X-Trace"))
+		require.Equal(t, "data: {\"text\":\"a\"}\n\ndata: {\"text\":\"b\"}\n\n", string(body))
+	})
+}
@@ -0,0 +1,360 @@
+// Package metrics counts what the services do, and exposes it in the
+// Prometheus text format. The metrics are declared once, usually as package
+// variables, then updated concurrently:
+//
+//	var transitions = metrics.NewCounter("rbot_flow_transitions_total", "The nodes entered.", "node_type")
+//
+//	transitions.Inc("message")
+package metrics
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// DefBuckets are the default buckets of the histograms, in seconds.
+var DefBuckets = []float64{.005, .01, .025, .05, .1, .25, .5, 1, 2.5, 5, 10}
+
+// Registry holds the metrics which are written together, such as by Handler.
+type Registry struct {
+	m       sync.RWMutex
+	metrics map[string]metric
+}
+
+// Default is the registry of the package functions, which is served by the
+// servers on /metrics.
+var Default = NewRegistry()
+
+func NewRegistry() *Registry {
+	return &Registry{metrics: map[string]metric{}}
+}
+
+type metric interface {
+	write(w *bufio.Writer)
+}
+
+func (r *Registry) register(name string, m metric) {
+	r.m.Lock()
+	defer r.m.Unlock()
+	if r.metrics[name] != nil {
+		panic(fmt.Sprintf("metrics: %v is already registered", name))
+	}
+	r.metrics[name] = m
+}
+
+// WriteText writes the metrics in the Prometheus text format, sorted by name.
+func (r *Registry) WriteText(w io.Writer) error {
+	r.m.RLock()
+	names := make([]string, 0, len(r.metrics))
+	for name := range r.metrics {
+		names = append(names, name)
+	}
+	metrics := make([]metric, len(names))
+	sort.Strings(names)
+	for i, name := range names {
+		metrics[i] = r.metrics[name]
+	}
+	r.m.RUnlock()
+
+	bw := bufio.NewWriter(w)
+	for _, m := range metrics {
+		m.write(bw)
+	}
+	return bw.Flush()
+}
+
+// Handler serves the metrics of the registry to the Prometheus scrapers.
+func Handler(r *Registry) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+		_ = r.WriteText(w)
+	})
+}
+
+// desc is the name and the labels of a metric, and its series by the values of
+// the labels.
+type desc struct {
+	name   string
+	help   string
+	typ    string
+	labels []string
+
+	m      sync.Mutex
+	series map[string]interface{}
+}
+
+func newDesc(name, help, typ string, labels []string) *desc {
+	return &desc{name: name, help: help, typ: typ, labels: labels, series: map[string]interface{}{}}
+}
+
+// get returns the series of the label values, created by newSeries, or nil
+// when newSeries is nil. It must be called with the lock.
+func (d *desc) get(values []string, newSeries func() interface{}) interface{} {
+	if len(values) != len(d.labels) {
+		panic(fmt.Sprintf("metrics: %v expects %v label values, got %v", d.name, len(d.labels), len(values)))
+	}
+	key := strings.Join(values, "\xff")
+	s := d.series[key]
+	if s == nil && newSeries != nil {
+		s = newSeries()
+		d.series[key] = s
+	}
+	return s
+}
+
+// value returns the value of the series of the counters and the gauges, 0 when
+// there is none.
+func (d *desc) value(values []string) float64 {
+	d.m.Lock()
+	defer d.m.Unlock()
+	if v, ok := d.get(values, nil).(*float64); ok {
+		return *v
+	}
+	return 0
+}
+
+// each calls fn with the series, sorted by their label values. It must be
+// called with the lock.
+func (d *desc) each(fn func(values []string, s interface{})) {
+	keys := make([]string, 0, len(d.series))
+	for key := range d.series {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		var values []string
+		if len(d.labels) != 0 {
+			values = strings.Split(key, "\xff")
+		}
+		fn(values, d.series[key])
+	}
+}
+
+func (d *desc) writeHeader(w *bufio.Writer) {
+	fmt.Fprintf(w, "# HELP %v %v\n", d.name, escapeHelp(d.help))
+	fmt.Fprintf(w, "# TYPE %v %v\n", d.name, d.typ)
+}
+
+// writeSample writes a line of the series, with the extra label, such as the
+// le of the buckets.
+func (d *desc) writeSample(w *bufio.Writer, suffix string, values []string, extraName, extraValue string, v float64) {
+	w.WriteString(d.name + suffix)
+	if len(values) != 0 || extraName != "" {
+		w.WriteByte('{')
+		for i, label := range d.labels {
+			if i > 0 {
+				w.WriteByte(',')
+			}
+			w.WriteString(label + `="` + escapeLabel(values[i]) + `"`)
+		}
+		if extraName != "" {
+			if len(values) != 0 {
+				w.WriteByte(',')
+			}
+			w.WriteString(extraName + `="` + extraValue + `"`)
+		}
+		w.WriteByte('}')
+	}
+	w.WriteByte(' ')
+	w.WriteString(formatFloat(v))
+	w.WriteByte('\n')
+}
+
+func formatFloat(v float64) string {
+	switch {
+	case math.IsInf(v, 1):
+		return "+Inf"
+	case math.IsInf(v, -1):
+		return "-Inf"
+	default:
+		return strconv.FormatFloat(v, 'g', -1, 64)
+	}
+}
+
+var (
+	helpEscaper  = strings.NewReplacer(`\`, `\\`, "\n", `\n`)
+	labelEscaper = strings.NewReplacer(`\`, `\\`, "\n", `\n`, `"`, `\"`)
+)
+
+func escapeHelp(s string) string  { return helpEscaper.Replace(s) }
+func escapeLabel(s string) string { return labelEscaper.Replace(s) }
+
+// Counter is a value which only goes up, such as the number of requests.
+type Counter struct {
+	desc *desc
+}
+
+func NewCounter(name, help string, labels ...string) *Counter {
+	return Default.NewCounter(name, help, labels...)
+}
+
+func (r *Registry) NewCounter(name, help string, labels ...string) *Counter {
+	c := &Counter{desc: newDesc(name, help, "counter", labels)}
+	if len(labels) == 0 {
+		c.Add(0) // the metrics without labels are written from the start
+	}
+	r.register(name, c)
+	return c
+}
+
+func (c *Counter) Inc(labelValues ...string) {
+	c.Add(1, labelValues...)
+}
+
+// Add adds v, which must not be negative, to the series of the label values.
+func (c *Counter) Add(v float64, labelValues ...string) {
+	if v < 0 {
+		panic(fmt.Sprintf("metrics: counter %v can not decrease", c.desc.name))
+	}
+	c.desc.m.Lock()
+	defer c.desc.m.Unlock()
+	value := c.desc.get(labelValues, func() interface{} { return new(float64) }).(*float64)
+	*value += v
+}
+
+// Value returns the value of the series, such as for the tests.
+func (c *Counter) Value(labelValues ...string) float64 {
+	return c.desc.value(labelValues)
+}
+
+func (c *Counter) write(w *bufio.Writer) {
+	c.desc.m.Lock()
+	defer c.desc.m.Unlock()
+	c.desc.writeHeader(w)
+	c.desc.each(func(values []string, s interface{}) {
+		c.desc.writeSample(w, "", values, "", "", *s.(*float64))
+	})
+}
+
+// Gauge is a value which goes up and down, such as the length of a queue.
+type Gauge struct {
+	desc *desc
+}
+
+func NewGauge(name, help string, labels ...string) *Gauge {
+	return Default.NewGauge(name, help, labels...)
+}
+
+func (r *Registry) NewGauge(name, help string, labels ...string) *Gauge {
+	g := &Gauge{desc: newDesc(name, help, "gauge", labels)}
+	if len(labels) == 0 {
+		g.Set(0)
+	}
+	r.register(name, g)
+	return g
+}
+
+func (g *Gauge) Set(v float64, labelValues ...string) {
+	g.update(labelValues, func(value *float64) { *value = v })
+}
+
+func (g *Gauge) Add(v float64, labelValues ...string) {
+	g.update(labelValues, func(value *float64) { *value += v })
+}
+
+func (g *Gauge) Inc(labelValues ...string) { g.Add(1, labelValues...) }
+func (g *Gauge) Dec(labelValues ...string) { g.Add(-1, labelValues...) }
+
+func (g *Gauge) update(labelValues []string, fn func(value *float64)) {
+	g.desc.m.Lock()
+	defer g.desc.m.Unlock()
+	fn(g.desc.get(labelValues, func() interface{} { return new(float64) }).(*float64))
+}
+
+// Value returns the value of the series, such as for the tests.
+func (g *Gauge) Value(labelValues ...string) float64 {
+	return g.desc.value(labelValues)
+}
+
+func (g *Gauge) write(w *bufio.Writer) {
+	g.desc.m.Lock()
+	defer g.desc.m.Unlock()
+	g.desc.writeHeader(w)
+	g.desc.each(func(values []string, s interface{}) {
+		g.desc.writeSample(w, "", values, "", "", *s.(*float64))
+	})
+}
+
+// Histogram counts the observed values, such as the latencies, in buckets.
+type Histogram struct {
+	desc    *desc
+	buckets []float64 // the upper bounds, sorted
+}
+
+type histogramSeries struct {
+	counts []uint64 // by bucket, the last one is +Inf
+	sum    float64
+	count  uint64
+}
+
+// NewHistogram returns the histogram with the buckets, DefBuckets when nil.
+func NewHistogram(name, help string, buckets []float64, labels ...string) *Histogram {
+	return Default.NewHistogram(name, help, buckets, labels...)
+}
+
+func (r *Registry) NewHistogram(name, help string, buckets []float64, labels ...string) *Histogram {
+	if buckets == nil {
+		buckets = DefBuckets
+	}
+	h := &Histogram{
+		desc:    newDesc(name, help, "histogram", labels),
+		buckets: append([]float64{}, buckets...),
+	}
+	sort.Float64s(h.buckets)
+	if len(labels) == 0 {
+		h.desc.get(nil, h.newSeries)
+	}
+	r.register(name, h)
+	return h
+}
+
+func (h *Histogram) newSeries() interface{} {
+	return &histogramSeries{counts: make([]uint64, len(h.buckets)+1)}
+}
+
+func (h *Histogram) Observe(v float64, labelValues ...string) {
+	h.desc.m.Lock()
+	defer h.desc.m.Unlock()
+	s := h.desc.get(labelValues, h.newSeries).(*histogramSeries)
+	s.counts[sort.SearchFloat64s(h.buckets, v)]++
+	s.sum += v
+	s.count++
+}
+
+// Count returns the number of the observed values of the series, such as for
+// the tests.
+func (h *Histogram) Count(labelValues ...string) uint64 {
+	h.desc.m.Lock()
+	defer h.desc.m.Unlock()
+	if s, ok := h.desc.get(labelValues, nil).(*histogramSeries); ok {
+		return s.count
+	}
+	return 0
+}
+
+func (h *Histogram) write(w *bufio.Writer) {
+	h.desc.m.Lock()
+	defer h.desc.m.Unlock()
+	h.desc.writeHeader(w)
+	h.desc.each(func(values []string, s interface{}) {
+		series := s.(*histogramSeries)
+		var cumulative uint64
+		for i, count := range series.counts {
+			cumulative += count
+			le := math.Inf(1)
+			if i < len(h.buckets) {
+				le = h.buckets[i]
+			}
+			h.desc.writeSample(w, "_bucket", values, "le", formatFloat(le), float64(cumulative))
+		}
+		h.desc.writeSample(w, "_sum", values, "", "", series.sum)
+		h.desc.writeSample(w, "_count", values, "", "", float64(series.count))
+	})
+}
@@ -0,0 +1,72 @@
+package metrics
+
+import (
+	"io/ioutil"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func scrape(t *testing.T, r *Registry) string {
+	rec := httptest.NewRecorder()
+	Handler(r).ServeHTTP(rec, httptest.NewRequest("GET", "/metrics", nil))
+	require.Equal(t, "text/plain; version=0.0.4; charset=utf-8", rec.Header().Get("Content-Type"))
+	body, err := ioutil.ReadAll(rec.Body)
+	require.NoError(t, err)
+	return string(body)
+}
+
+func TestRegistry(t *testing.T) {
+	r := NewRegistry()
+	requests := r.NewCounter("test_requests_total", "The requests, by route and code.", "route", "code")
+	queue := r.NewGauge("test_queue_depth", "The queued items.")
+	latency := r.NewHistogram("test_latency_seconds", "The latency.", []float64{1, 0.1}, "route")
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			requests.Inc("/a", "ok")
+			queue.Inc()
+			latency.Observe(0.05, "/a")
+		}()
+	}
+	wg.Wait()
+	requests.Add(2, `/b"\`, "not_found")
+	queue.Dec()
+	latency.Observe(0.5, "/a")
+	latency.Observe(3, "/a")
+
+	require.Equal(t, float64(10), requests.Value("/a", "ok"))
+	require.Equal(t, float64(0), requests.Value("/a", "internal"))
+	require.Equal(t, float64(9), queue.Value())
+	require.Equal(t, uint64(12), latency.Count("/a"))
+
+	expected := `# HELP test_latency_seconds The latency.
+# TYPE test_latency_seconds histogram
+test_latency_seconds_bucket{route="/a",le="0.1"} 10
+test_latency_seconds_bucket{route="/a",le="1"} 11
+test_latency_seconds_bucket{route="/a",le="+Inf"} 12
+test_latency_seconds_sum{route="/a"} 4
+test_latency_seconds_count{route="/a"} 12
+# HELP test_queue_depth The queued items.
+# TYPE test_queue_depth gauge
+test_queue_depth 9
+# HELP test_requests_total The requests, by route and code.
+# TYPE test_requests_total counter
+test_requests_total{route="/a",code="ok"} 10
+test_requests_total{route="/b\"\\",code="not_found"} 2
+`
+	require.Equal(t, expected, scrape(t, r))
+}
+
+func TestRegistryErrors(t *testing.T) {
+	r := NewRegistry()
+	c := r.NewCounter("test_total", "", "label")
+	require.Panics(t, func() { r.NewGauge("test_total", "") })
+	require.Panics(t, func() { c.Inc() })
+	require.Panics(t, func() { c.Add(-1, "a") })
+}
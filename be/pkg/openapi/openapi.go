@@ -0,0 +1,213 @@
+// Package openapi builds the OpenAPI 3 document of the httprpc services. Each
+// package with +gen:api registers the paths and the schemas of its services in
+// its generated zz_generated.openapi.go.
+package openapi
+
+import (
+	"encoding/json"
+	"net/http"
+	"sort"
+	"sync"
+
+	"gopkg.in/yaml.v2"
+
+	"github.com/olvrng/rbot/be/pkg/xerrors"
+)
+
+const Version = "3.0.3"
+
+// ErrorSchemaRef is the schema of the error responses, written by
+// httprpc.WriteError.
+const ErrorSchemaRef = "#/components/schemas/Error"
+
+type Document struct {
+	OpenAPI    string                `json:"openapi,omitempty"`
+	Info       *Info                 `json:"info,omitempty"`
+	Paths      map[string]*PathItem  `json:"paths"`
+	Components Components            `json:"components"`
+	Security   []map[string][]string `json:"security,omitempty"`
+}
+
+type Info struct {
+	Title       string `json:"title"`
+	Description string `json:"description,omitempty"`
+	Version     string `json:"version"`
+}
+
+type PathItem struct {
+	Get  *Operation `json:"get,omitempty"`
+	Post *Operation `json:"post,omitempty"`
+}
+
+type Operation struct {
+	OperationID string               `json:"operationId"`
+	Tags        []string             `json:"tags,omitempty"`
+	Summary     string               `json:"summary,omitempty"`
+	Description string               `json:"description,omitempty"`
+	Parameters  []*Parameter         `json:"parameters,omitempty"`
+	RequestBody *RequestBody         `json:"requestBody,omitempty"`
+	Responses   map[string]*Response `json:"responses"`
+}
+
+type Parameter struct {
+	Name     string  `json:"name"`
+	In       string  `json:"in"`
+	Required bool    `json:"required,omitempty"`
+	Style    string  `json:"style,omitempty"`
+	Schema   *Schema `json:"schema"`
+}
+
+type RequestBody struct {
+	Required bool                  `json:"required,omitempty"`
+	Content  map[string]*MediaType `json:"content"`
+}
+
+type Response struct {
+	Description string                `json:"description"`
+	Content     map[string]*MediaType `json:"content,omitempty"`
+}
+
+type MediaType struct {
+	Schema *Schema `json:"schema"`
+}
+
+type Components struct {
+	Schemas         map[string]*Schema         `json:"schemas,omitempty"`
+	SecuritySchemes map[string]*SecurityScheme `json:"securitySchemes,omitempty"`
+}
+
+type Schema struct {
+	Ref                  string             `json:"$ref,omitempty"`
+	Type                 string             `json:"type,omitempty"`
+	Format               string             `json:"format,omitempty"`
+	Description          string             `json:"description,omitempty"`
+	Enum                 []interface{}      `json:"enum,omitempty"`
+	Items                *Schema            `json:"items,omitempty"`
+	Properties           map[string]*Schema `json:"properties,omitempty"`
+	Required             []string           `json:"required,omitempty"`
+	AdditionalProperties *Schema            `json:"additionalProperties,omitempty"`
+	AllOf                []*Schema          `json:"allOf,omitempty"`
+	OneOf                []*Schema          `json:"oneOf,omitempty"`
+	Discriminator        *Discriminator     `json:"discriminator,omitempty"`
+}
+
+type Discriminator struct {
+	PropertyName string `json:"propertyName"`
+}
+
+type SecurityScheme struct {
+	Type         string `json:"type"`
+	Scheme       string `json:"scheme,omitempty"`
+	BearerFormat string `json:"bearerFormat,omitempty"`
+	Name         string `json:"name,omitempty"`
+	In           string `json:"in,omitempty"`
+}
+
+var (
+	m         sync.Mutex
+	fragments []string
+)
+
+// Register adds the paths and the schemas of the document fragment, in JSON. It
+// is called by the generated code, the fragment has no info.
+func Register(fragment string) {
+	m.Lock()
+	defer m.Unlock()
+	fragments = append(fragments, fragment)
+}
+
+// Build merges the registered fragments into a document. The schemas with the
+// same name, from the packages using the same types, are kept once.
+func Build(info Info) (*Document, error) {
+	m.Lock()
+	defer m.Unlock()
+	doc := &Document{
+		OpenAPI: Version,
+		Info:    &info,
+		Paths:   map[string]*PathItem{},
+		Components: Components{
+			Schemas: map[string]*Schema{"Error": errorSchema()},
+			SecuritySchemes: map[string]*SecurityScheme{
+				"bearer":  {Type: "http", Scheme: "bearer", BearerFormat: "JWT"},
+				"api_key": {Type: "apiKey", Name: "X-API-Key", In: "header"},
+			},
+		},
+		Security: []map[string][]string{{"bearer": {}}, {"api_key": {}}},
+	}
+	for _, fragment := range fragments {
+		var frag Document
+		if err := json.Unmarshal([]byte(fragment), &frag); err != nil {
+			return nil, xerrors.Errorf(xerrors.Internal, err, "invalid openapi fragment")
+		}
+		for path, item := range frag.Paths {
+			doc.Paths[path] = item
+		}
+		for name, schema := range frag.Components.Schemas {
+			doc.Components.Schemas[name] = schema
+		}
+	}
+	return doc, nil
+}
+
+// errorSchema is the schema of the errors written by httprpc.WriteError.
+func errorSchema() *Schema {
+	var codes []interface{}
+	for c := xerrors.NoError; c <= xerrors.Unauthenticated; c++ {
+		codes = append(codes, c.String())
+	}
+	return &Schema{
+		Type:     "object",
+		Required: []string{"code", "msg"},
+		Properties: map[string]*Schema{
+			"code": {Type: "string", Enum: codes},
+			"msg":  {Type: "string"},
+			"meta": {Type: "object", AdditionalProperties: &Schema{Type: "string"}},
+		},
+	}
+}
+
+// SortedPaths returns the paths of the document, sorted.
+func (d *Document) SortedPaths() []string {
+	paths := make([]string, 0, len(d.Paths))
+	for path := range d.Paths {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+	return paths
+}
+
+// YAML returns the document in YAML.
+func (d *Document) YAML() ([]byte, error) {
+	data, err := json.Marshal(d)
+	if err != nil {
+		return nil, err
+	}
+	var v interface{}
+	if err = json.Unmarshal(data, &v); err != nil {
+		return nil, err
+	}
+	return yaml.Marshal(v)
+}
+
+// Handler serves the document in JSON, or in YAML when yamlFormat is set.
+func Handler(info Info, yamlFormat bool) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		doc, err := Build(info)
+		var data []byte
+		if err == nil && yamlFormat {
+			data, err = doc.YAML()
+		} else if err == nil {
+			data, err = json.MarshalIndent(doc, "", "  ")
+		}
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		if yamlFormat {
+			w.Header().Set("Content-Type", "application/yaml")
+		} else {
+			w.Header().Set("Content-Type", "application/json")
+		}
+		_, _ = w.Write(data)
+	}
+}
@@ -0,0 +1,72 @@
+// Package textnorm normalizes text for matching: case and diacritics are
+// ignored, which matters for Vietnamese where customers often type without
+// accents ("huy don" for "hủy đơn").
+package textnorm
+
+import (
+	"strings"
+	"unicode"
+)
+
+var foldTable = map[rune]rune{}
+
+func init() {
+	groups := map[rune]string{
+		'a': "àáảãạăằắẳẵặâầấẩẫậäåā",
+		'e': "èéẻẽẹêềếểễệëē",
+		'i': "ìíỉĩịïî",
+		'o': "òóỏõọôồốổỗộơờớởỡợöø",
+		'u': "ùúủũụưừứửữựüû",
+		'y': "ỳýỷỹỵÿ",
+		'd': "đ",
+		'c': "ç",
+		'n': "ñ",
+	}
+	for base, chars := range groups {
+		for _, c := range chars {
+			foldTable[c] = base
+		}
+	}
+}
+
+// Fold returns the text in lower case, without diacritics and punctuation,
+// with the words separated by a single space.
+func Fold(s string) string {
+	var b strings.Builder
+	b.Grow(len(s))
+	space := true
+	for _, c := range strings.ToLower(s) {
+		switch {
+		case unicode.Is(unicode.Mn, c):
+			// combining marks of decomposed text
+			continue
+		case unicode.IsLetter(c) || unicode.IsDigit(c):
+			if r, ok := foldTable[c]; ok {
+				c = r
+			}
+			b.WriteRune(c)
+			space = false
+		default:
+			if !space {
+				b.WriteByte(' ')
+				space = true
+			}
+		}
+	}
+	return strings.TrimSuffix(b.String(), " ")
+}
+
+// Words returns the folded words of the text.
+func Words(s string) []string {
+	return strings.Fields(Fold(s))
+}
+
+// ContainsPhrase reports whether the folded text contains the folded phrase
+// as whole words.
+func ContainsPhrase(text, phrase string) bool {
+	phrase = Fold(phrase)
+	if phrase == "" {
+		return false
+	}
+	return strings.Contains(" "+Fold(text)+" ", " "+phrase+" ")
+}
@@ -0,0 +1,35 @@
+package textnorm
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFold(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"", ""},
+		{"  Hello,   World! ", "hello world"},
+		{"Hủy đơn hàng", "huy don hang"},
+		{"ĐẶT HÀNG", "dat hang"},
+		{"Cảm ơn nhiều!!!", "cam on nhieu"},
+		{"Xin chào", "xin chao"},
+		{"Hu\u0309y \u0111o\u031bn", "huy don"}, // decomposed
+		{"order #123", "order 123"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			assert.Equal(t, tt.expected, Fold(tt.input))
+		})
+	}
+}
+
+func TestContainsPhrase(t *testing.T) {
+	assert.True(t, ContainsPhrase("Tôi muốn hủy đơn hàng", "huy don"))
+	assert.True(t, ContainsPhrase("cancel!", "Cancel"))
+	assert.False(t, ContainsPhrase("cancellation", "cancel"))
+	assert.False(t, ContainsPhrase("anything", ""))
+}
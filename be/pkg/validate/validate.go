@@ -0,0 +1,60 @@
+// Package validate collects the invalid fields of the requests, for the Validate
+// methods generated from the +validate directives (see genutil.ValidateRule).
+package validate
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/olvrng/rbot/be/pkg/xerrors"
+)
+
+// Errors collects the invalid fields by their json path, such as "flow.name" or
+// "nodes.2.id".
+type Errors struct {
+	fields []string
+	msgs   []string
+}
+
+func (e *Errors) Add(field, msg string) {
+	e.fields = append(e.fields, field)
+	e.msgs = append(e.msgs, msg)
+}
+
+// Nested adds the invalid fields of the Validate error of a nested struct, under
+// its field.
+func (e *Errors) Nested(field string, err error) {
+	if err == nil {
+		return
+	}
+	xerr, ok := err.(*xerrors.APIError)
+	if !ok || xerr.Code != xerrors.InvalidArgument || len(xerr.Meta) == 0 {
+		e.Add(field, err.Error())
+		return
+	}
+	keys := make([]string, 0, len(xerr.Meta))
+	for key := range xerr.Meta {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		e.Add(field+"."+key, xerr.Meta[key])
+	}
+}
+
+// Err returns nil when all the fields are valid. Otherwise, it returns an
+// InvalidArgument error with the message of each invalid field in its meta.
+func (e *Errors) Err() error {
+	if len(e.fields) == 0 {
+		return nil
+	}
+	msg := e.fields[0] + " " + e.msgs[0]
+	if n := len(e.fields) - 1; n > 0 {
+		msg += fmt.Sprintf(" (and %v more)", n)
+	}
+	err := xerrors.Errorf(xerrors.InvalidArgument, nil, "%s", msg)
+	for i, field := range e.fields {
+		err = err.WithMeta(field, e.msgs[i])
+	}
+	return err
+}
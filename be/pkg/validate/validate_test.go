@@ -0,0 +1,46 @@
+package validate
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/olvrng/rbot/be/pkg/xerrors"
+)
+
+func TestErrors(t *testing.T) {
+	t.Run("valid", func(t *testing.T) {
+		errs := &Errors{}
+		errs.Nested("flow", nil)
+		require.NoError(t, errs.Err())
+	})
+	t.Run("invalid fields", func(t *testing.T) {
+		nested := &Errors{}
+		nested.Add("name", "is required")
+		nested.Add("id", "must be at least 1")
+
+		errs := &Errors{}
+		errs.Add("page_id", "is required")
+		errs.Nested("flow", nested.Err())
+		errs.Nested("nodes.0", errors.New("unexpected"))
+		err := errs.Err()
+
+		xerr, ok := err.(*xerrors.APIError)
+		require.True(t, ok)
+		require.Equal(t, xerrors.InvalidArgument, xerr.Code)
+		require.Equal(t, "page_id is required (and 3 more)", xerr.Message)
+		require.Equal(t, map[string]string{
+			"page_id":   "is required",
+			"flow.id":   "must be at least 1",
+			"flow.name": "is required",
+			"nodes.0":   "unexpected",
+		}, xerr.Meta)
+	})
+	t.Run("message with verbs", func(t *testing.T) {
+		errs := &Errors{}
+		errs.Add("discount", "must be at most 100%")
+		err := errs.Err()
+		require.Equal(t, "discount must be at most 100%", err.(*xerrors.APIError).Message)
+	})
+}
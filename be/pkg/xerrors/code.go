@@ -82,3 +82,14 @@ func DefaultErrorMessage(code Code) string {
 	}
 	return "Internal Error"
 }
+
+// ParseCode returns the code of the given name, as returned by Code.String. It
+// returns Unknown for the unknown names.
+func ParseCode(s string) Code {
+	for c, code := range mapCodes {
+		if code == s {
+			return Code(c)
+		}
+	}
+	return Unknown
+}
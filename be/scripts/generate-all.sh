@@ -2,5 +2,5 @@
 set -eo pipefail
 source "$(dirname "$0")/_init.sh"
 
-# generate all packages
-go generate ./cmd/ggen ./...
+# generate all packages, and the typescript api of the board
+go run ./cmd/ggen -ts-out "$PROJECT_DIR/../apps/board/src/api" ./com/...
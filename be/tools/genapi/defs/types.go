@@ -31,6 +31,20 @@ type Method struct {
 	Request  *Message
 	Response *Message
 
+	// Validate is set when the request has a generated Validate method (see
+	// genutil.HasValidate), which the server calls before the handler.
+	Validate bool
+
+	// GET is set by the +api:get directive, for the idempotent queries. The
+	// server then also accepts the request as the query of a GET request.
+	GET bool
+
+	// Stream is set by the +api:stream directive. The method sends its messages
+	// to the callback as server-sent events, and Response is the message:
+	//
+	//	WatchEvents(ctx context.Context, req *WatchEventsRequest, send func(*FlowEvent) error) error
+	Stream bool
+
 	Method *types.Func
 }
 
@@ -22,6 +22,10 @@ type Opts struct {
 
 func generateServices(printer generator.Printer, opts Opts, services []*defs.Service) error {
 	currentPrinter = printer
+	schemas, err := buildSchemas(services)
+	if err != nil {
+		return err
+	}
 	printer.Import("context", "context")
 	printer.Import("fmt", "fmt")
 	printer.Import("http", "net/http")
@@ -29,6 +33,7 @@ func generateServices(printer generator.Printer, opts Opts, services []*defs.Ser
 	vars := map[string]interface{}{
 		"Services": services,
 		"Opts":     opts,
+		"Schemas":  schemas,
 	}
 	return tpl.Execute(printer, vars)
 }
@@ -0,0 +1,142 @@
+package genapi
+
+import (
+	"go/ast"
+	"go/constant"
+	"go/types"
+	"reflect"
+	"strings"
+
+	"github.com/olvrng/ggen"
+)
+
+// jsonField is a field of a struct, as encoded by encoding/json.
+type jsonField struct {
+	Name     string
+	Var      *types.Var
+	Tag      reflect.StructTag
+	Type     types.Type
+	Optional bool // a pointer, or omitempty
+	AsString bool // the ",string" option
+}
+
+// jsonFields returns the fields of the struct as encoded by encoding/json. The
+// embedded structs without a json name are inlined.
+func jsonFields(st *types.Struct) []jsonField {
+	var result []jsonField
+	for i, n := 0, st.NumFields(); i < n; i++ {
+		field := st.Field(i)
+		structTag := reflect.StructTag(st.Tag(i))
+		tag := structTag.Get("json")
+		if tag == "-" || !field.Exported() && !field.Embedded() {
+			continue
+		}
+		name, opts := tag, ""
+		if idx := strings.Index(tag, ","); idx >= 0 {
+			name, opts = tag[:idx], tag[idx+1:]
+		}
+		if field.Embedded() && name == "" {
+			typ := field.Type()
+			if ptr, ok := typ.(*types.Pointer); ok {
+				typ = ptr.Elem()
+			}
+			if inner, ok := typ.Underlying().(*types.Struct); ok {
+				result = append(result, jsonFields(inner)...)
+				continue
+			}
+		}
+		if name == "" {
+			name = field.Name()
+		}
+		_, isPtr := field.Type().(*types.Pointer)
+		result = append(result, jsonField{
+			Name:     name,
+			Var:      field,
+			Tag:      structTag,
+			Type:     field.Type(),
+			Optional: isPtr || strings.Contains(","+opts+",", ",omitempty,"),
+			AsString: strings.Contains(","+opts+",", ",string,"),
+		})
+	}
+	return result
+}
+
+type unionMember struct {
+	Tag  string
+	Type types.Type
+}
+
+// unionMembers returns the registered data of a type with custom JSON
+// encoding, such as NodePayload. The type has the directive
+//
+//	// +ts:union=RegisterNode
+//
+// and each call RegisterNode(NodeSendMessage, func() NodeData { return &SendMessageNodeData{} })
+// in its package adds a member, tagged with its "type". It returns nil for the
+// other types.
+func unionMembers(ng ggen.Engine, named *types.Named) []unionMember {
+	if ng == nil {
+		return nil
+	}
+	fn := ng.GetDirectives(named.Obj()).GetArg("ts:union")
+	pkg := ng.GetPackageByPath(named.Obj().Pkg().Path())
+	if fn == "" || pkg == nil || pkg.TypesInfo == nil {
+		return nil
+	}
+	var members []unionMember
+	for _, file := range pkg.Syntax {
+		ast.Inspect(file, func(node ast.Node) bool {
+			call, ok := node.(*ast.CallExpr)
+			if !ok || len(call.Args) != 2 || !isFuncName(call.Fun, fn) {
+				return true
+			}
+			ident, ok := call.Args[0].(*ast.Ident)
+			if !ok {
+				return true
+			}
+			c, ok := pkg.TypesInfo.Uses[ident].(*types.Const)
+			if !ok || c.Val().Kind() != constant.String {
+				return true
+			}
+			lit, ok := call.Args[1].(*ast.FuncLit)
+			if !ok || len(lit.Body.List) != 1 {
+				return true
+			}
+			ret, ok := lit.Body.List[0].(*ast.ReturnStmt)
+			if !ok || len(ret.Results) != 1 {
+				return true
+			}
+			members = append(members, unionMember{
+				Tag:  constant.StringVal(c.Val()),
+				Type: pkg.TypesInfo.TypeOf(ret.Results[0]),
+			})
+			return false
+		})
+	}
+	return members
+}
+
+func isFuncName(expr ast.Expr, name string) bool {
+	switch expr := expr.(type) {
+	case *ast.Ident:
+		return expr.Name == name
+	case *ast.SelectorExpr:
+		return expr.Sel.Name == name
+	}
+	return false
+}
+
+func hasMarshalJSON(named *types.Named) bool {
+	mset := types.NewMethodSet(types.NewPointer(named))
+	return mset.Lookup(nil, "MarshalJSON") != nil
+}
+
+// aliasRhs returns the type of an alias, such as fbmsg.IntID, with the go/types
+// of go1.22 and later.
+func aliasRhs(typ types.Type) (types.Type, bool) {
+	alias, ok := typ.(interface{ Rhs() types.Type })
+	if !ok {
+		return nil, false
+	}
+	return alias.Rhs(), true
+}
@@ -0,0 +1,319 @@
+package genapi
+
+import (
+	"encoding/json"
+	"fmt"
+	"go/constant"
+	"go/types"
+	"strconv"
+	"strings"
+
+	"golang.org/x/tools/go/packages"
+
+	"github.com/olvrng/ggen"
+	"github.com/olvrng/rbot/be/pkg/httprpc"
+	"github.com/olvrng/rbot/be/pkg/openapi"
+	"github.com/olvrng/rbot/be/tools/genapi/defs"
+	"github.com/olvrng/rbot/be/tools/genapi/parse"
+	"github.com/olvrng/rbot/be/tools/genutil"
+)
+
+var _ ggen.Plugin = &openAPIPlugin{}
+
+var oaKnownTypes = map[string]*openapi.Schema{
+	"github.com/olvrng/rbot/be/pkg/dot.IntID":              {Type: "string", Format: "int64"},
+	"github.com/olvrng/rbot/be/pkg/dot.Timestamp":          {Type: "string", Description: "milliseconds from 1970"},
+	"github.com/olvrng/rbot/be/com/flowdef/types.Duration": {Type: "string", Description: "such as 30m, 24h or 3d"},
+	"time.Time":                {Type: "string", Format: "date-time"},
+	"time.Duration":            {Type: "integer", Format: "int64"},
+	"encoding/json.RawMessage": {},
+}
+
+type openAPIPlugin struct {
+	ggen.Filterer
+	ggen.Qualifier
+}
+
+// NewOpenAPI returns the plugin which generates the OpenAPI paths and schemas
+// of the services. The generated code registers them with openapi.Register.
+func NewOpenAPI() ggen.Plugin {
+	return &openAPIPlugin{
+		Filterer:  ggen.FilterByCommand("gen:api"),
+		Qualifier: genutil.Qualifier{},
+	}
+}
+
+func (p *openAPIPlugin) Name() string { return "openapi" }
+
+func (p *openAPIPlugin) Generate(ng ggen.Engine) error {
+	return ng.GenerateEachPackage(p.generatePackage)
+}
+
+func (p *openAPIPlugin) generatePackage(ng ggen.Engine, pkg *packages.Package, printer ggen.Printer) error {
+	ls.Debugf("openapi: generating package %v", pkg.PkgPath)
+	services, err := parse.Services(ng, pkg, []defs.Kind{defs.KindService})
+	if err != nil {
+		return err
+	}
+	if len(services) == 0 {
+		return nil
+	}
+	doc := buildOpenAPI(newOASchemas(ng), services)
+	data, err := json.MarshalIndent(doc, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	printer.Import("openapi", "github.com/olvrng/rbot/be/pkg/openapi")
+	text := string(data)
+	if strings.Contains(text, "`") {
+		text = strconv.Quote(text)
+	} else {
+		text = "`" + text + "`"
+	}
+	_, err = fmt.Fprintf(printer, `
+func init() {
+	openapi.Register(openAPIFragment)
+}
+
+const openAPIFragment = %v
+`, text)
+	return err
+}
+
+// buildOpenAPI returns the paths of the services, and the schemas of their
+// requests and responses.
+func buildOpenAPI(oa *oaSchemas, services []*defs.Service) *openapi.Document {
+	doc := &openapi.Document{
+		Paths:      map[string]*openapi.PathItem{},
+		Components: openapi.Components{Schemas: oa.schemas},
+	}
+	for _, s := range services {
+		for _, m := range s.Methods {
+			summary, description := splitComment(m.Comment)
+			request, response := m.Request.Items[0].Type, m.Response.Items[0].Type
+			responses := map[string]*openapi.Response{
+				"200": {
+					Description: "OK",
+					Content:     bodyContent(oa.Ref(response)),
+				},
+				"default": {
+					Description: "Error",
+					Content:     jsonContent(&openapi.Schema{Ref: openapi.ErrorSchemaRef}),
+				},
+			}
+			if m.Stream {
+				responses["200"] = &openapi.Response{
+					Description: "The server-sent events, each with the json of a message as data. An error event ends the stream.",
+					Content: map[string]*openapi.MediaType{
+						httprpc.ContentTypeEventStream: {Schema: oa.Ref(response)},
+					},
+				}
+			}
+			item := &openapi.PathItem{
+				Post: &openapi.Operation{
+					OperationID: s.Name + "_" + m.Name,
+					Tags:        []string{s.FullName},
+					Summary:     summary,
+					Description: description,
+					RequestBody: &openapi.RequestBody{
+						Required: true,
+						Content:  bodyContent(oa.Ref(request)),
+					},
+					Responses: responses,
+				},
+			}
+			if m.GET {
+				item.Get = &openapi.Operation{
+					OperationID: s.Name + "_" + m.Name + "_GET",
+					Tags:        []string{s.FullName},
+					Summary:     summary,
+					Description: description,
+					Parameters:  oa.queryParameters(request),
+					Responses:   responses,
+				}
+			}
+			doc.Paths["/"+s.APIPath+"/"+m.APIPath] = item
+		}
+	}
+	return doc
+}
+
+func jsonContent(schema *openapi.Schema) map[string]*openapi.MediaType {
+	return map[string]*openapi.MediaType{httprpc.ContentTypeJSON: {Schema: schema}}
+}
+
+// bodyContent returns the content types of the requests and the responses, by
+// the codecs of httprpc. The protobuf messages have the fields of the schema,
+// numbered in order.
+func bodyContent(schema *openapi.Schema) map[string]*openapi.MediaType {
+	return map[string]*openapi.MediaType{
+		httprpc.ContentTypeJSON:     {Schema: schema},
+		httprpc.ContentTypeForm:     {Schema: schema},
+		httprpc.ContentTypeProtobuf: {Schema: &openapi.Schema{Type: "string", Format: "binary"}},
+	}
+}
+
+// queryParameters returns the fields of the request as the query parameters of
+// the GET requests. The nested fields use the form conventions, such as
+// customer[name].
+func (o *oaSchemas) queryParameters(typ types.Type) []*openapi.Parameter {
+	st, ok := derefType(typ).Underlying().(*types.Struct)
+	if !ok {
+		return nil
+	}
+	var params []*openapi.Parameter
+	for _, field := range jsonFields(st) {
+		param := &openapi.Parameter{Name: field.Name, In: "query", Schema: o.Ref(field.Type)}
+		if field.AsString {
+			param.Schema = &openapi.Schema{Type: "string"}
+		}
+		if _, isStruct := derefType(field.Type).Underlying().(*types.Struct); isStruct {
+			param.Style = "deepObject"
+		}
+		params = append(params, param)
+	}
+	return params
+}
+
+// splitComment returns the first sentence of the comment as the summary, and
+// the whole comment as the description when it is longer.
+func splitComment(comment string) (summary, description string) {
+	comment = strings.TrimSpace(comment)
+	summary = comment
+	if idx := strings.Index(summary, ". "); idx >= 0 {
+		summary = summary[:idx+1]
+	}
+	if idx := strings.Index(summary, "\n\n"); idx >= 0 {
+		summary = summary[:idx]
+	}
+	summary = strings.Join(strings.Fields(summary), " ")
+	if summary != strings.Join(strings.Fields(comment), " ") {
+		description = comment
+	}
+	return summary, description
+}
+
+// oaSchemas walks the request and response types of the services, and declares
+// them in the components of the document, by their qualified name such as
+// flowdeftypes.Flow.
+type oaSchemas struct {
+	// ng looks up the directives and the syntax of the packages. It may be nil,
+	// then the union types are declared as any.
+	ng      ggen.Engine
+	schemas map[string]*openapi.Schema
+}
+
+func newOASchemas(ng ggen.Engine) *oaSchemas {
+	return &oaSchemas{ng: ng, schemas: map[string]*openapi.Schema{}}
+}
+
+// Ref returns the schema of typ, as a reference for the named types.
+func (o *oaSchemas) Ref(typ types.Type) *openapi.Schema {
+	switch typ := typ.(type) {
+	case *types.Named:
+		obj := typ.Obj()
+		if obj.Pkg() == nil {
+			return &openapi.Schema{} // error
+		}
+		if known, ok := oaKnownTypes[obj.Pkg().Path()+"."+obj.Name()]; ok {
+			schema := *known
+			return &schema
+		}
+		name := tsModuleName(obj.Pkg()) + "." + obj.Name()
+		if o.schemas[name] == nil {
+			o.schemas[name] = &openapi.Schema{} // the recursive types refer to it
+			o.schemas[name] = o.declare(typ)
+		}
+		return &openapi.Schema{Ref: "#/components/schemas/" + name}
+
+	case *types.Basic:
+		switch {
+		case typ.Info()&types.IsString != 0:
+			return &openapi.Schema{Type: "string"}
+		case typ.Info()&types.IsBoolean != 0:
+			return &openapi.Schema{Type: "boolean"}
+		case typ.Info()&types.IsInteger != 0:
+			if typ.Kind() == types.Int64 || typ.Kind() == types.Uint64 {
+				return &openapi.Schema{Type: "integer", Format: "int64"}
+			}
+			return &openapi.Schema{Type: "integer"}
+		case typ.Info()&types.IsNumeric != 0:
+			return &openapi.Schema{Type: "number"}
+		}
+		return &openapi.Schema{}
+
+	case *types.Pointer:
+		return o.Ref(typ.Elem())
+
+	case *types.Slice:
+		if basic, ok := typ.Elem().(*types.Basic); ok && basic.Kind() == types.Byte {
+			return &openapi.Schema{Type: "string", Format: "byte"}
+		}
+		return &openapi.Schema{Type: "array", Items: o.Ref(typ.Elem())}
+
+	case *types.Array:
+		return &openapi.Schema{Type: "array", Items: o.Ref(typ.Elem())}
+
+	case *types.Map:
+		return &openapi.Schema{Type: "object", AdditionalProperties: o.Ref(typ.Elem())}
+
+	case *types.Struct:
+		return o.object(typ)
+
+	default:
+		if rhs, ok := aliasRhs(typ); ok {
+			return o.Ref(rhs)
+		}
+		return &openapi.Schema{}
+	}
+}
+
+func (o *oaSchemas) declare(named *types.Named) *openapi.Schema {
+	switch underlying := named.Underlying().(type) {
+	case *types.Struct:
+		if !hasMarshalJSON(named) {
+			return o.object(underlying)
+		}
+		schema := &openapi.Schema{}
+		for _, m := range unionMembers(o.ng, named) {
+			tag := &openapi.Schema{
+				Type:       "object",
+				Required:   []string{"type"},
+				Properties: map[string]*openapi.Schema{"type": {Type: "string", Enum: []interface{}{m.Tag}}},
+			}
+			schema.OneOf = append(schema.OneOf, &openapi.Schema{AllOf: []*openapi.Schema{o.Ref(m.Type), tag}})
+		}
+		return schema
+
+	case *types.Basic:
+		schema := o.Ref(underlying)
+		for _, v := range genutil.EnumValues(named) {
+			switch v.Kind() {
+			case constant.String:
+				schema.Enum = append(schema.Enum, constant.StringVal(v))
+			default:
+				schema.Enum = append(schema.Enum, json.Number(v.ExactString()))
+			}
+		}
+		return schema
+
+	default:
+		return o.Ref(underlying)
+	}
+}
+
+func (o *oaSchemas) object(st *types.Struct) *openapi.Schema {
+	schema := &openapi.Schema{Type: "object", Properties: map[string]*openapi.Schema{}}
+	for _, field := range jsonFields(st) {
+		prop := o.Ref(field.Type)
+		if field.AsString {
+			prop = &openapi.Schema{Type: "string"}
+		}
+		schema.Properties[field.Name] = prop
+		if !field.Optional {
+			schema.Required = append(schema.Required, field.Name)
+		}
+	}
+	return schema
+}
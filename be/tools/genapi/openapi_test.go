@@ -0,0 +1,76 @@
+package genapi
+
+import (
+	"encoding/json"
+	"go/ast"
+	"go/importer"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/olvrng/rbot/be/pkg/openapi"
+)
+
+func TestOASchemas(t *testing.T) {
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, "types.go", testTSSource, 0)
+	require.NoError(t, err)
+	cfg := &types.Config{Importer: importer.ForCompiler(fset, "source", nil)}
+	pkg, err := cfg.Check("example.com/item/types", fset, []*ast.File{file}, nil)
+	require.NoError(t, err)
+
+	oa := newOASchemas(nil)
+	ref := oa.Ref(types.NewPointer(pkg.Scope().Lookup("Response").Type()))
+	require.Equal(t, &openapi.Schema{Ref: "#/components/schemas/itemtypes.Response"}, ref)
+
+	data, err := json.MarshalIndent(oa.schemas, "", "  ")
+	require.NoError(t, err)
+	require.JSONEq(t, `{
+  "itemtypes.Custom": {},
+  "itemtypes.Item": {
+    "type": "object",
+    "properties": {
+      "attrs": {"type": "object", "additionalProperties": {"$ref": "#/components/schemas/itemtypes.Status"}},
+      "created_at": {"type": "string", "format": "date-time"},
+      "data": {"type": "string", "format": "byte"},
+      "id": {"type": "string"},
+      "name": {"type": "string"},
+      "parent": {"$ref": "#/components/schemas/itemtypes.Item"},
+      "status": {"$ref": "#/components/schemas/itemtypes.Status"},
+      "tags": {"type": "array", "items": {"type": "string"}}
+    },
+    "required": ["created_at", "id", "status", "tags", "attrs", "data"]
+  },
+  "itemtypes.Response": {
+    "type": "object",
+    "properties": {
+      "custom": {"$ref": "#/components/schemas/itemtypes.Custom"},
+      "items": {"type": "array", "items": {"$ref": "#/components/schemas/itemtypes.Item"}}
+    },
+    "required": ["items", "custom"]
+  },
+  "itemtypes.Status": {"type": "string", "enum": ["closed", "open"]}
+}`, string(data))
+}
+
+func TestSplitComment(t *testing.T) {
+	tests := []struct {
+		comment     string
+		summary     string
+		description string
+	}{
+		{"", "", ""},
+		{"Lists the flows.\n", "Lists the flows.", ""},
+		{"Lists the flows of\nthe page.", "Lists the flows of the page.", ""},
+		{"Lists the flows. The deleted flows\nare skipped.", "Lists the flows.", "Lists the flows. The deleted flows\nare skipped."},
+		{"Lists the flows\n\nof the page", "Lists the flows", "Lists the flows\n\nof the page"},
+	}
+	for _, tt := range tests {
+		summary, description := splitComment(tt.comment)
+		require.Equal(t, tt.summary, summary, tt.comment)
+		require.Equal(t, tt.description, description, tt.comment)
+	}
+}
@@ -90,7 +90,8 @@ func parseService(ng ggen.Engine, iface *types.Interface) ([]*defs.Method, error
 }
 
 func parseMethod(ng ggen.Engine, method *types.Func) (_ *defs.Method, err error) {
-	apiPath := ng.GetDirectives(method).GetArg("api:path")
+	directives := ng.GetDirectives(method)
+	apiPath := directives.GetArg("api:path")
 	if apiPath == "" {
 		apiPath = method.Name()
 	}
@@ -100,10 +101,20 @@ func parseMethod(ng ggen.Engine, method *types.Func) (_ *defs.Method, err error)
 	styp := mtyp.(*types.Signature)
 	params := styp.Params()
 	results := styp.Results()
-	requests, responses, err := checkMethodSignature(method.Name(), params, results)
+	_, stream := directives.Get("api:stream")
+	check := checkMethodSignature
+	if stream {
+		check = checkStreamSignature
+	}
+	requests, responses, err := check(method.Name(), params, results)
 	if err != nil {
 		return nil, fmt.Errorf("%v: %v", method.Name(), err)
 	}
+	_, get := directives.Get("api:get")
+	validate := false
+	if named := genutil.ValidateNested(requests.Items[0].Type); named != nil {
+		validate = genutil.HasValidate(ng, named)
+	}
 	return &defs.Method{
 		Name:     method.Name(),
 		APIPath:  apiPath,
@@ -111,6 +122,9 @@ func parseMethod(ng ggen.Engine, method *types.Func) (_ *defs.Method, err error)
 		Method:   method,
 		Request:  requests,
 		Response: responses,
+		Validate: validate,
+		GET:      get,
+		Stream:   stream,
 	}, nil
 }
 
@@ -173,6 +187,33 @@ func checkMethodSignature(name string, params *types.Tuple, results *types.Tuple
 	return request, response, nil
 }
 
+// checkStreamSignature checks the methods with the +api:stream directive:
+//
+//	WatchEvents(ctx context.Context, req *Request, send func(*Event) error) error
+//
+// The response is the type of the events.
+func checkStreamSignature(name string, params *types.Tuple, results *types.Tuple) (request, response *defs.Message, err error) {
+	if params.Len() != 3 || params.At(0).Type().String() != "context.Context" ||
+		results.Len() != 1 || results.At(0).Type().String() != "error" {
+		return nil, nil, ggen.Errorf(nil, "%v: expect (ctx context.Context, req *Request, send func(*Event) error) error", name)
+	}
+	req, err := checkArg(params.At(1), false)
+	if err != nil {
+		return nil, nil, ggen.Errorf(err, "%v: %v", name, err)
+	}
+	send, ok := params.At(2).Type().Underlying().(*types.Signature)
+	if !ok || send.Params().Len() != 1 || send.Results().Len() != 1 || send.Results().At(0).Type().String() != "error" {
+		return nil, nil, ggen.Errorf(nil, "%v: expect the last param is func(*Event) error", name)
+	}
+	event := send.Params().At(0)
+	if _, _, err = checkStruct(event.Type()); err != nil {
+		return nil, nil, ggen.Errorf(err, "%v: the event must be a pointer to struct: %v", name, err)
+	}
+	request = &defs.Message{Items: []*defs.ArgItem{req}}
+	response = &defs.Message{Items: []*defs.ArgItem{{Name: "Event", Var: event, Type: event.Type()}}}
+	return request, response, nil
+}
+
 func checkArg(v *types.Var, autoInline bool) (*defs.ArgItem, error) {
 	arg := &defs.ArgItem{
 		Inline: v.Name() == "_" || v.Name() == "" && autoInline,
@@ -0,0 +1,208 @@
+package genapi
+
+import (
+	"fmt"
+	"go/types"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/olvrng/rbot/be/tools/genapi/defs"
+)
+
+// schemaKnownKinds are the kinds of the types with custom JSON encoding, which
+// are not KindJSON.
+var schemaKnownKinds = map[string]string{
+	"github.com/olvrng/rbot/be/pkg/dot.IntID":              "KindInt",
+	"github.com/olvrng/rbot/be/pkg/dot.Timestamp":          "KindInt",
+	"github.com/olvrng/rbot/be/com/flowdef/types.Duration": "KindString",
+	"time.Time":                "KindString",
+	"encoding/json.RawMessage": "KindJSON",
+}
+
+type schemaDecl struct {
+	Name   string
+	Fields []string
+}
+
+type schemaField struct {
+	Kind     string
+	AsString bool
+	Repeated bool
+	Map      bool
+	Message  string
+}
+
+// schemaGen declares the schemas of the requests and the responses of the
+// services, and their nested structs, for the codecs of httprpc. The fields are
+// numbered from 1 in the order of the json fields, or by their proto tag:
+//
+//	Amount float64 `json:"amount" proto:"7"`
+type schemaGen struct {
+	schemas map[string]*schemaDecl
+}
+
+func buildSchemas(services []*defs.Service) ([]*schemaDecl, error) {
+	g := &schemaGen{schemas: map[string]*schemaDecl{}}
+	for _, s := range services {
+		for _, m := range s.Methods {
+			for _, typ := range []types.Type{m.Request.Items[0].Type, m.Response.Items[0].Type} {
+				named, ok := derefType(typ).(*types.Named)
+				if !ok {
+					return nil, fmt.Errorf("method %v: expect named structs", m.Name)
+				}
+				if _, err := g.declare(named); err != nil {
+					return nil, fmt.Errorf("method %v: %v", m.Name, err)
+				}
+			}
+		}
+	}
+	result := make([]*schemaDecl, 0, len(g.schemas))
+	for _, s := range g.schemas {
+		result = append(result, s)
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].Name < result[j].Name })
+	return result, nil
+}
+
+func schemaName(named *types.Named) string {
+	return named.Obj().Pkg().Path() + "." + named.Obj().Name()
+}
+
+func (g *schemaGen) declare(named *types.Named) (string, error) {
+	name := schemaName(named)
+	if g.schemas[name] != nil {
+		return name, nil
+	}
+	st, ok := named.Underlying().(*types.Struct)
+	if !ok {
+		return "", fmt.Errorf("type %v is not a struct", name)
+	}
+	decl := &schemaDecl{Name: name}
+	g.schemas[name] = decl // the recursive types refer to it
+
+	numbers := map[int]string{}
+	for i, jf := range jsonFields(st) {
+		number := i + 1
+		if tag := jf.Tag.Get("proto"); tag != "" {
+			n, err := strconv.Atoi(tag)
+			if err != nil || n <= 0 {
+				return "", fmt.Errorf("type %v: invalid proto tag %q of %v", name, tag, jf.Name)
+			}
+			number = n
+		}
+		if other := numbers[number]; other != "" {
+			return "", fmt.Errorf("type %v: %v and %v have the same proto number %v", name, other, jf.Name, number)
+		}
+		numbers[number] = jf.Name
+
+		f, err := g.field(jf.Type)
+		if err != nil {
+			return "", fmt.Errorf("type %v, field %v: %v", name, jf.Name, err)
+		}
+		if jf.AsString && (f.Kind == "KindInt" || f.Kind == "KindUint" || f.Kind == "KindFloat") {
+			f.AsString = true
+		}
+		decl.Fields = append(decl.Fields, f.render(jf.Name, number))
+	}
+	return name, nil
+}
+
+func (g *schemaGen) field(typ types.Type) (schemaField, error) {
+	for {
+		rhs, ok := aliasRhs(typ)
+		if !ok {
+			break
+		}
+		typ = rhs
+	}
+	typ = derefType(typ)
+	if named, ok := typ.(*types.Named); ok {
+		if pkg := named.Obj().Pkg(); pkg != nil {
+			if kind := schemaKnownKinds[pkg.Path()+"."+named.Obj().Name()]; kind != "" {
+				return schemaField{Kind: kind}, nil
+			}
+		}
+		if hasMarshalJSON(named) {
+			return schemaField{Kind: "KindJSON"}, nil
+		}
+	}
+
+	switch u := typ.Underlying().(type) {
+	case *types.Basic:
+		switch info := u.Info(); {
+		case info&types.IsString != 0:
+			return schemaField{Kind: "KindString"}, nil
+		case info&types.IsBoolean != 0:
+			return schemaField{Kind: "KindBool"}, nil
+		case info&types.IsUnsigned != 0:
+			return schemaField{Kind: "KindUint"}, nil
+		case info&types.IsInteger != 0:
+			return schemaField{Kind: "KindInt"}, nil
+		case info&types.IsFloat != 0:
+			return schemaField{Kind: "KindFloat"}, nil
+		}
+		return schemaField{}, fmt.Errorf("unsupported type %v", typ)
+
+	case *types.Slice:
+		if basic, ok := u.Elem().(*types.Basic); ok && basic.Kind() == types.Byte {
+			return schemaField{Kind: "KindBytes"}, nil
+		}
+		return g.container(u.Elem(), false)
+
+	case *types.Array:
+		return g.container(u.Elem(), false)
+
+	case *types.Map:
+		return g.container(u.Elem(), true)
+
+	case *types.Struct:
+		named, ok := typ.(*types.Named)
+		if !ok {
+			return schemaField{Kind: "KindJSON"}, nil // anonymous struct
+		}
+		name, err := g.declare(named)
+		return schemaField{Kind: "KindMessage", Message: name}, err
+
+	case *types.Interface:
+		return schemaField{Kind: "KindJSON"}, nil
+
+	default:
+		return schemaField{}, fmt.Errorf("unsupported type %v", typ)
+	}
+}
+
+// container returns the field of the slices and maps. The nested containers,
+// such as [][]string, are KindJSON.
+func (g *schemaGen) container(elem types.Type, isMap bool) (schemaField, error) {
+	f, err := g.field(elem)
+	if err != nil {
+		return f, err
+	}
+	if f.Repeated || f.Map {
+		return schemaField{Kind: "KindJSON"}, nil
+	}
+	f.Repeated, f.Map = !isMap, isMap
+	return f, nil
+}
+
+func (f schemaField) render(name string, number int) string {
+	parts := []string{
+		"Name: " + strconv.Quote(name),
+		"Number: " + strconv.Itoa(number),
+		"Kind: httprpc." + f.Kind,
+	}
+	if f.AsString {
+		parts = append(parts, "AsString: true")
+	}
+	if f.Repeated {
+		parts = append(parts, "Repeated: true")
+	}
+	if f.Map {
+		parts = append(parts, "Map: true")
+	}
+	if f.Message != "" {
+		parts = append(parts, "Message: "+strconv.Quote(f.Message))
+	}
+	return "{" + strings.Join(parts, ", ") + "}"
+}
@@ -0,0 +1,81 @@
+package genapi
+
+import (
+	"go/ast"
+	"go/importer"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func checkTestPackage(t *testing.T, src string) *types.Package {
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, "types.go", src, 0)
+	require.NoError(t, err)
+	cfg := &types.Config{Importer: importer.ForCompiler(fset, "source", nil)}
+	pkg, err := cfg.Check("example.com/item/types", fset, []*ast.File{file}, nil)
+	require.NoError(t, err)
+	return pkg
+}
+
+func TestSchemas(t *testing.T) {
+	pkg := checkTestPackage(t, testTSSource)
+	g := &schemaGen{schemas: map[string]*schemaDecl{}}
+	name, err := g.declare(pkg.Scope().Lookup("Response").Type().(*types.Named))
+	require.NoError(t, err)
+	require.Equal(t, "example.com/item/types.Response", name)
+
+	require.Equal(t, map[string]*schemaDecl{
+		"example.com/item/types.Item": {
+			Name: "example.com/item/types.Item",
+			Fields: []string{
+				`{Name: "created_at", Number: 1, Kind: httprpc.KindString}`,
+				`{Name: "id", Number: 2, Kind: httprpc.KindInt, AsString: true}`,
+				`{Name: "name", Number: 3, Kind: httprpc.KindString}`,
+				`{Name: "status", Number: 4, Kind: httprpc.KindString}`,
+				`{Name: "parent", Number: 5, Kind: httprpc.KindMessage, Message: "example.com/item/types.Item"}`,
+				`{Name: "tags", Number: 6, Kind: httprpc.KindString, Repeated: true}`,
+				`{Name: "attrs", Number: 7, Kind: httprpc.KindString, Map: true}`,
+				`{Name: "data", Number: 8, Kind: httprpc.KindBytes}`,
+			},
+		},
+		"example.com/item/types.Response": {
+			Name: "example.com/item/types.Response",
+			Fields: []string{
+				`{Name: "items", Number: 1, Kind: httprpc.KindMessage, Repeated: true, Message: "example.com/item/types.Item"}`,
+				`{Name: "custom", Number: 2, Kind: httprpc.KindJSON}`,
+			},
+		},
+	}, g.schemas)
+}
+
+func TestSchemaNumbers(t *testing.T) {
+	pkg := checkTestPackage(t, `
+package types
+
+type Pinned struct {
+	A string `+"`json:\"a\"`"+`
+	B [][]int `+"`json:\"b\" proto:\"5\"`"+`
+	C int `+"`json:\"c\"`"+`
+}
+
+type Duplicated struct {
+	A string `+"`json:\"a\"`"+`
+	B string `+"`json:\"b\" proto:\"1\"`"+`
+}
+`)
+	g := &schemaGen{schemas: map[string]*schemaDecl{}}
+	_, err := g.declare(pkg.Scope().Lookup("Pinned").Type().(*types.Named))
+	require.NoError(t, err)
+	require.Equal(t, []string{
+		`{Name: "a", Number: 1, Kind: httprpc.KindString}`,
+		`{Name: "b", Number: 5, Kind: httprpc.KindJSON}`,
+		`{Name: "c", Number: 3, Kind: httprpc.KindInt}`,
+	}, g.schemas["example.com/item/types.Pinned"].Fields)
+
+	_, err = g.declare(pkg.Scope().Lookup("Duplicated").Type().(*types.Named))
+	require.EqualError(t, err, "type example.com/item/types.Duplicated: a and b have the same proto number 1")
+}
@@ -3,6 +3,17 @@ package genapi
 const tplText = `
 func init() {
 	httprpc.Register(NewServer)
+{{- if .Schemas}}
+	httprpc.RegisterSchemas(
+{{- range .Schemas}}
+		&httprpc.Schema{Name: "{{.Name}}", Fields: []httprpc.Field{
+{{- range .Fields}}
+			{{.}},
+{{- end}}
+		}},
+{{- end}}
+	)
+{{- end}}
 }
 
 func NewServer(builder interface{}, hooks ...httprpc.HooksBuilder) (httprpc.Server, bool) {
@@ -59,12 +70,12 @@ func (s *{{$s.Name}}ServiceServer) ServeHTTP(resp http.ResponseWriter, req *http
 		httprpc.WriteError(ctx, resp, hooks, *info, err)
 		return
 	}
-	serve, err := httprpc.ParseRequestHeader(req)
+	reqMsg, exec, allowGET, err := s.parseRoute(req.URL.Path, hooks, info)
 	if err != nil {
 		httprpc.WriteError(ctx, resp, hooks, *info, err)
 		return
 	}
-	reqMsg, exec, err := s.parseRoute(req.URL.Path, hooks, info)
+	serve, err := httprpc.ParseRequestHeader(req, allowGET)
 	if err != nil {
 		httprpc.WriteError(ctx, resp, hooks, *info, err)
 		return
@@ -72,7 +83,7 @@ func (s *{{$s.Name}}ServiceServer) ServeHTTP(resp http.ResponseWriter, req *http
 	serve(ctx, resp, req, hooks, info, reqMsg, exec)
 }
 
-func (s *{{$s.Name}}ServiceServer) parseRoute(path string, hooks httprpc.Hooks, info *httprpc.HookInfo) (reqMsg httprpc.Message, _ httprpc.ExecFunc, _ error) {
+func (s *{{$s.Name}}ServiceServer) parseRoute(path string, hooks httprpc.Hooks, info *httprpc.HookInfo) (reqMsg httprpc.Message, _ httprpc.ExecFunc, allowGET bool, _ error) {
 	switch path {
 {{range $m := .Methods -}}
 	case "/{{$s.APIPath}}/{{.APIPath}}":
@@ -84,15 +95,61 @@ func (s *{{$s.Name}}ServiceServer) parseRoute(path string, hooks httprpc.Hooks,
 		if err != nil {
 			return
 		}
+{{- if .Validate}}
+		if err = msg.Validate(); err != nil {
+			return
+		}
+{{- end}}
+{{- if .Stream}}
+		stream, err := httprpc.StartStream(newCtx)
+		if err != nil {
+			return
+		}
+		err = inner.{{.Name}}(newCtx, msg, func(event {{(index .Response.Items 0).Type|type}}) error {
+			return stream.Send(event)
+		})
+{{- else}}
 		resp, err = inner.{{.Name}}(newCtx, msg)
+{{- end}}
 		return
 	}
-	return msg, fn, nil
+	return msg, fn, {{.GET}}, nil
 {{end -}}
 	default:
 		msg := fmt.Sprintf("no handler for path %q", path)
-		return nil, nil, httprpc.BadRouteError(msg, "POST", path)
+		return nil, nil, false, httprpc.BadRouteError(msg, "POST", path)
 	}
 }
+
+// {{.Name}}ServiceClient calls {{.Name}}Service on the server at baseURL.
+type {{.Name}}ServiceClient struct {
+	client *httprpc.Client
+}
+
+var _ {{.Name}}Service = &{{.Name}}ServiceClient{}
+
+func New{{.Name}}ServiceClient(baseURL string, httpClient *http.Client, hooks ...httprpc.ClientHooks) *{{.Name}}ServiceClient {
+	return &{{.Name}}ServiceClient{
+		client: httprpc.NewClient(baseURL, httpClient, hooks...),
+	}
+}
+{{range $m := .Methods}}
+{{if .Stream -}}
+func (c *{{$s.Name}}ServiceClient) {{.Name}}(ctx context.Context, req {{(index .Request.Items 0).Type|type}}, send func({{(index .Response.Items 0).Type|type}}) error) error {
+	newEvent := func() httprpc.Message { return {{(index .Response.Items 0).Type|new}} }
+	return c.client.Stream(ctx, Path_{{$s.Name}}_{{.Name}}, req, newEvent, func(event httprpc.Message) error {
+		return send(event.({{(index .Response.Items 0).Type|type}}))
+	})
+}
+{{- else -}}
+func (c *{{$s.Name}}ServiceClient) {{.Name}}(ctx context.Context, req {{(index .Request.Items 0).Type|type}}) ({{(index .Response.Items 0).Type|type}}, error) {
+	resp := {{(index .Response.Items 0).Type|new}}
+	if err := c.client.Call(ctx, Path_{{$s.Name}}_{{.Name}}, req, resp); err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+{{- end}}
+{{end -}}
 {{end}}
 `
@@ -0,0 +1,122 @@
+package genapi
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"text/template"
+
+	"github.com/olvrng/ggen"
+	"github.com/olvrng/rbot/be/tools/genapi/defs"
+	"github.com/olvrng/rbot/be/tools/genapi/parse"
+	"github.com/olvrng/rbot/be/tools/genutil"
+)
+
+var _ ggen.Plugin = &tsPlugin{}
+
+var tsTpl = template.Must(template.New("ts").Parse(tsTplText))
+
+type tsPlugin struct {
+	ggen.Filterer
+	ggen.Qualifier
+	outDir string
+}
+
+// NewTypeScript returns the plugin which generates the TypeScript types and
+// the fetch clients of the services into outDir, one module per Go package.
+func NewTypeScript(outDir string) ggen.Plugin {
+	return &tsPlugin{
+		Filterer:  ggen.FilterByCommand("gen:api"),
+		Qualifier: genutil.Qualifier{},
+		outDir:    outDir,
+	}
+}
+
+func (p *tsPlugin) Name() string { return "ts" }
+
+type tsService struct {
+	Name    string
+	Methods []*tsMethod
+}
+
+type tsMethod struct {
+	Name     string
+	Path     string
+	Request  string
+	Response string
+	Stream   bool
+}
+
+func (p *tsPlugin) Generate(ng ggen.Engine) error {
+	if p.outDir == "" {
+		return nil
+	}
+	tt := newTSTypes(ng)
+	for _, gpkg := range ng.GeneratingPackages() {
+		pkg := gpkg.Package
+		ls.Debugf("ts: generating package %v", pkg.PkgPath)
+		services, err := parse.Services(ng, pkg, []defs.Kind{defs.KindService})
+		if err != nil {
+			return err
+		}
+		if len(services) == 0 {
+			continue
+		}
+		from := pkg.Name
+		module := tt.module(from)
+		module.Imports[tsRuntimeModule] = true
+		for _, s := range services {
+			service := &tsService{Name: s.FullName}
+			for _, m := range s.Methods {
+				service.Methods = append(service.Methods, &tsMethod{
+					Name:     strings.ToLower(m.Name[:1]) + m.Name[1:],
+					Path:     "/" + s.APIPath + "/" + m.APIPath,
+					Request:  tt.Ref(from, m.Request.Items[0].Type),
+					Response: tt.Ref(from, m.Response.Items[0].Type),
+					Stream:   m.Stream,
+				})
+			}
+			module.Services = append(module.Services, service)
+		}
+	}
+	if len(tt.modules) == 0 {
+		return nil
+	}
+
+	if err := os.MkdirAll(p.outDir, 0755); err != nil {
+		return ggen.Errorf(err, "create directory %v: %v", p.outDir, err)
+	}
+	if err := p.writeFile(tsRuntimeModule, []byte(tsRuntimeText)); err != nil {
+		return err
+	}
+	for _, module := range tt.sortedModules() {
+		var imports []string
+		for name := range module.Imports {
+			imports = append(imports, name)
+		}
+		sort.Strings(imports)
+		var b bytes.Buffer
+		vars := map[string]interface{}{
+			"Module":  module,
+			"Imports": imports,
+		}
+		if err := tsTpl.Execute(&b, vars); err != nil {
+			return err
+		}
+		if err := p.writeFile(module.Name, b.Bytes()); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (p *tsPlugin) writeFile(module string, data []byte) error {
+	filePath := filepath.Join(p.outDir, module+".ts")
+	if err := ioutil.WriteFile(filePath, data, 0644); err != nil {
+		return ggen.Errorf(err, "write file %v: %v", filePath, err)
+	}
+	return nil
+}
@@ -0,0 +1,232 @@
+package genapi
+
+const tsTplText = `// Code generated by ggen. DO NOT EDIT.
+{{if .Imports}}
+{{range .Imports -}}
+import type * as {{.}} from './{{.}}';
+{{end -}}
+{{end -}}
+{{range .Module.Decls}}
+{{.}}
+{{end -}}
+{{range $s := .Module.Services}}
+export interface {{.Name}} {
+{{- range .Methods}}
+{{- if .Stream}}
+  {{.Name}}(req: {{.Request}}, onEvent: (event: {{.Response}}) => void, signal?: AbortSignal): Promise<void>;
+{{- else}}
+  {{.Name}}(req: {{.Request}}): Promise<{{.Response}}>;
+{{- end}}
+{{- end}}
+}
+
+export class {{.Name}}Client implements {{.Name}} {
+  constructor(readonly client: rpc.Client) {}
+{{range .Methods}}
+{{- if .Stream}}
+  {{.Name}}(req: {{.Request}}, onEvent: (event: {{.Response}}) => void, signal?: AbortSignal): Promise<void> {
+    return this.client.stream<{{.Response}}>('{{.Path}}', req, onEvent, signal);
+  }
+{{- else}}
+  {{.Name}}(req: {{.Request}}): Promise<{{.Response}}> {
+    return this.client.call<{{.Response}}>('{{.Path}}', req);
+  }
+{{- end}}
+{{end -}}
+}
+{{end -}}
+`
+
+const tsRuntimeText = `// Code generated by ggen. DO NOT EDIT.
+
+// IntID is an int64 id, encoded as a string to keep its precision.
+export type IntID = string;
+
+// Timestamp is the number of milliseconds from 1970, encoded as a string.
+export type Timestamp = string;
+
+// ErrorCode is the code of xerrors.Code, as written by the server.
+export type ErrorCode =
+  | 'canceled' | 'unknown' | 'invalid_argument' | 'deadline_exceeded'
+  | 'not_found' | 'already_exists' | 'permission_denied' | 'resource_exhausted'
+  | 'failed_precondition' | 'aborted' | 'out_of_range' | 'unimplemented'
+  | 'internal' | 'unavailable' | 'data_loss' | 'unauthenticated';
+
+// APIError is the error of a call, decoded from the error response of the
+// server.
+export class APIError extends Error {
+  constructor(
+    readonly code: ErrorCode,
+    readonly msg: string,
+    readonly meta: Record<string, string> = {},
+    readonly status = 0,
+  ) {
+    super(msg);
+    this.name = 'APIError';
+  }
+}
+
+export type HookInfo = {
+  route: string;
+  request: unknown;
+  init: RequestInit & {headers: Headers};
+};
+
+// Hooks are called on each call, such as to set the auth headers.
+export type Hooks = {
+  requestPrepared?: (info: HookInfo) => void | Promise<void>;
+  responseReceived?: (info: HookInfo, response: unknown) => void;
+  error?: (info: HookInfo, err: APIError) => APIError;
+};
+
+// Client posts the JSON requests of the generated clients to the server at
+// baseURL, such as '' for the same origin.
+export class Client {
+  constructor(
+    readonly baseURL = '',
+    readonly hooks: Hooks = {},
+    readonly fetchFn: typeof fetch = (input, init) => fetch(input, init),
+  ) {}
+
+  async call<Resp>(route: string, request: unknown): Promise<Resp> {
+    const info: HookInfo = {
+      route,
+      request,
+      init: {
+        method: 'POST',
+        headers: new Headers({'Content-Type': 'application/json'}),
+        body: JSON.stringify(request),
+        credentials: 'same-origin',
+      },
+    };
+    try {
+      await this.hooks.requestPrepared?.(info);
+      const resp = await this.fetchFn(this.baseURL + route, info.init);
+      const body = await resp.text();
+      if (!resp.ok) {
+        throw errorFromResponse(resp.status, body);
+      }
+      const result = JSON.parse(body) as Resp;
+      this.hooks.responseReceived?.(info, result);
+      return result;
+    } catch (e) {
+      throw this.fail(info, e);
+    }
+  }
+
+  // stream posts the request of a streaming method, then calls onEvent with
+  // each server-sent event. It resolves when the server ends the stream or the
+  // signal is aborted, and rejects with the error event of the server.
+  async stream<Event>(
+    route: string,
+    request: unknown,
+    onEvent: (event: Event) => void,
+    signal?: AbortSignal,
+  ): Promise<void> {
+    const info: HookInfo = {
+      route,
+      request,
+      init: {
+        method: 'POST',
+        headers: new Headers({'Content-Type': 'application/json', Accept: 'text/event-stream'}),
+        body: JSON.stringify(request),
+        credentials: 'same-origin',
+        signal,
+      },
+    };
+    try {
+      await this.hooks.requestPrepared?.(info);
+      const resp = await this.fetchFn(this.baseURL + route, info.init);
+      if (!resp.ok || !resp.body) {
+        throw errorFromResponse(resp.status, await resp.text());
+      }
+      const reader = resp.body.getReader();
+      const decoder = new TextDecoder();
+      let buffer = '';
+      for (;;) {
+        const {done, value} = await reader.read();
+        if (done) {
+          return;
+        }
+        buffer += decoder.decode(value, {stream: true});
+        let end: number;
+        while ((end = buffer.indexOf('\n\n')) >= 0) {
+          const event = parseEvent(buffer.slice(0, end));
+          buffer = buffer.slice(end + 2);
+          if (event.data === undefined) {
+            continue; // the heartbeats
+          }
+          if (event.name === 'error') {
+            throw errorFromResponse(resp.status, event.data);
+          }
+          const result = JSON.parse(event.data) as Event;
+          this.hooks.responseReceived?.(info, result);
+          onEvent(result);
+        }
+      }
+    } catch (e) {
+      if (signal?.aborted) {
+        return;
+      }
+      throw this.fail(info, e);
+    }
+  }
+
+  private fail(info: HookInfo, e: unknown): APIError {
+    let err = e instanceof APIError ? e : new APIError('unavailable', String(e));
+    if (this.hooks.error) {
+      err = this.hooks.error(info, err);
+    }
+    return err;
+  }
+}
+
+function parseEvent(block: string): {name: string; data?: string} {
+  let name = 'message';
+  let data: string | undefined;
+  for (const line of block.split('\n')) {
+    if (line.startsWith('event:')) {
+      name = line.slice('event:'.length).trim();
+    } else if (line.startsWith('data:')) {
+      const value = line.slice('data:'.length).replace(/^ /, '');
+      data = data === undefined ? value : data + '\n' + value;
+    }
+  }
+  return {name, data};
+}
+
+function errorFromResponse(status: number, body: string): APIError {
+  try {
+    const e = JSON.parse(body);
+    if (e && typeof e.code === 'string') {
+      return new APIError(e.code, e.msg ?? '', e.meta ?? {}, status);
+    }
+  } catch (_) {
+    // not written by the server, such as by a proxy
+  }
+  return new APIError(codeFromStatus(status), ` + "`unexpected http status ${status}`" + `, {body}, status);
+}
+
+function codeFromStatus(status: number): ErrorCode {
+  switch (status) {
+    case 400:
+      return 'invalid_argument';
+    case 401:
+      return 'unauthenticated';
+    case 403:
+      return 'permission_denied';
+    case 404:
+      return 'not_found';
+    case 408:
+      return 'deadline_exceeded';
+    case 429:
+      return 'resource_exhausted';
+    case 502:
+    case 503:
+    case 504:
+      return 'unavailable';
+    default:
+      return 'unknown';
+  }
+}
+`
@@ -0,0 +1,243 @@
+package genapi
+
+import (
+	"go/constant"
+	"go/types"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/olvrng/ggen"
+	"github.com/olvrng/rbot/be/tools/genutil"
+)
+
+// tsRuntimeModule is the module of the fetch client and of the types in
+// tsKnownTypes.
+const tsRuntimeModule = "rpc"
+
+// tsKnownType is the TypeScript type of a Go type with a custom JSON encoding.
+type tsKnownType struct {
+	Module string
+	Name   string
+}
+
+var tsKnownTypes = map[string]tsKnownType{
+	"github.com/olvrng/rbot/be/pkg/dot.IntID":              {tsRuntimeModule, "IntID"},
+	"github.com/olvrng/rbot/be/pkg/dot.Timestamp":          {tsRuntimeModule, "Timestamp"},
+	"github.com/olvrng/rbot/be/com/flowdef/types.Duration": {"", "string"},
+	"time.Time":                {"", "string"},
+	"time.Duration":            {"", "number"},
+	"encoding/json.RawMessage": {"", "unknown"},
+}
+
+type tsModule struct {
+	Name     string
+	Imports  map[string]bool
+	Decls    []string
+	Services []*tsService
+}
+
+// tsTypes walks the request and response types of the services, and declares
+// them in the TypeScript module of their package (see genutil.Qualifier).
+type tsTypes struct {
+	// ng looks up the directives and the syntax of the packages. It may be nil,
+	// then the union types are declared as unknown.
+	ng ggen.Engine
+
+	modules  map[string]*tsModule
+	declared map[*types.TypeName]bool
+}
+
+func newTSTypes(ng ggen.Engine) *tsTypes {
+	return &tsTypes{
+		ng:       ng,
+		modules:  map[string]*tsModule{},
+		declared: map[*types.TypeName]bool{},
+	}
+}
+
+func (t *tsTypes) module(name string) *tsModule {
+	m := t.modules[name]
+	if m == nil {
+		m = &tsModule{Name: name, Imports: map[string]bool{}}
+		t.modules[name] = m
+	}
+	return m
+}
+
+// sortedModules returns the modules by name.
+func (t *tsTypes) sortedModules() []*tsModule {
+	result := make([]*tsModule, 0, len(t.modules))
+	for _, m := range t.modules {
+		result = append(result, m)
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].Name < result[j].Name })
+	return result
+}
+
+func tsModuleName(pkg *types.Package) string {
+	return genutil.Qualifier{}.Qualify(pkg)
+}
+
+// Ref returns the TypeScript type of typ, as written in the module from.
+func (t *tsTypes) Ref(from string, typ types.Type) string {
+	switch typ := typ.(type) {
+	case *types.Named:
+		obj := typ.Obj()
+		if obj.Pkg() == nil {
+			return "unknown" // error
+		}
+		if known, ok := tsKnownTypes[obj.Pkg().Path()+"."+obj.Name()]; ok {
+			return t.qualify(from, known.Module, known.Name)
+		}
+		t.declare(typ)
+		return t.qualify(from, tsModuleName(obj.Pkg()), obj.Name())
+
+	case *types.Basic:
+		switch {
+		case typ.Info()&types.IsString != 0:
+			return "string"
+		case typ.Info()&types.IsBoolean != 0:
+			return "boolean"
+		case typ.Info()&types.IsNumeric != 0:
+			return "number"
+		}
+		return "unknown"
+
+	case *types.Pointer:
+		return t.Ref(from, typ.Elem())
+
+	case *types.Slice:
+		if basic, ok := typ.Elem().(*types.Basic); ok && basic.Kind() == types.Byte {
+			return "string" // base64
+		}
+		return tsArray(t.Ref(from, typ.Elem()))
+
+	case *types.Array:
+		return tsArray(t.Ref(from, typ.Elem()))
+
+	case *types.Map:
+		return "Record<string, " + t.Ref(from, typ.Elem()) + ">"
+
+	case *types.Struct:
+		return "{" + strings.Join(t.fields(from, typ), "; ") + "}"
+
+	default:
+		if rhs, ok := aliasRhs(typ); ok {
+			return t.Ref(from, rhs)
+		}
+		return "unknown"
+	}
+}
+
+func (t *tsTypes) qualify(from, module, name string) string {
+	if module == "" || module == from {
+		return name
+	}
+	t.module(from).Imports[module] = true
+	return module + "." + name
+}
+
+func tsArray(elem string) string {
+	if strings.ContainsAny(elem, "|&") {
+		return "Array<" + elem + ">"
+	}
+	return elem + "[]"
+}
+
+// declare adds the declaration of the named type to its module.
+func (t *tsTypes) declare(named *types.Named) {
+	obj := named.Obj()
+	if t.declared[obj] {
+		return
+	}
+	t.declared[obj] = true
+
+	from := tsModuleName(obj.Pkg())
+	var decl string
+	switch underlying := named.Underlying().(type) {
+	case *types.Struct:
+		switch {
+		case !hasMarshalJSON(named):
+			fields := t.fields(from, underlying)
+			decl = "export interface " + obj.Name() + " {\n"
+			for _, field := range fields {
+				decl += "  " + field + ";\n"
+			}
+			decl += "}"
+		default:
+			members := t.union(from, named)
+			if len(members) == 0 {
+				decl = "export type " + obj.Name() + " = unknown;"
+				break
+			}
+			decl = "export type " + obj.Name() + " =\n  | " + strings.Join(members, "\n  | ") + ";"
+		}
+
+	case *types.Basic:
+		decl = "export type " + obj.Name() + " = " + t.enum(from, named, underlying) + ";"
+
+	default:
+		decl = "export type " + obj.Name() + " = " + t.Ref(from, underlying) + ";"
+	}
+	m := t.module(from)
+	m.Decls = append(m.Decls, decl)
+}
+
+// fields returns the fields of the struct as encoded by encoding/json.
+func (t *tsTypes) fields(from string, st *types.Struct) []string {
+	var result []string
+	for _, field := range jsonFields(st) {
+		typ := t.Ref(from, field.Type)
+		if field.AsString {
+			typ = "string"
+		}
+		optional := ""
+		if field.Optional {
+			optional = "?"
+		}
+		result = append(result, tsFieldName(field.Name)+optional+": "+typ)
+	}
+	return result
+}
+
+func tsFieldName(name string) string {
+	for _, c := range name {
+		if !(c == '_' || c == '$' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9') {
+			return strconv.Quote(name)
+		}
+	}
+	return name
+}
+
+// enum returns the union of the constants of the named type, such as the roles,
+// or the basic type when there is no constant.
+func (t *tsTypes) enum(from string, named *types.Named, basic *types.Basic) string {
+	var values []string
+	for _, v := range genutil.EnumValues(named) {
+		switch v.Kind() {
+		case constant.String:
+			values = append(values, tsQuote(constant.StringVal(v)))
+		default:
+			values = append(values, v.ExactString())
+		}
+	}
+	if len(values) == 0 {
+		return t.Ref(from, basic)
+	}
+	return strings.Join(values, " | ")
+}
+
+// union returns the members of the union of a type with custom JSON encoding
+// (see unionMembers), tagged with their type.
+func (t *tsTypes) union(from string, named *types.Named) []string {
+	var members []string
+	for _, m := range unionMembers(t.ng, named) {
+		members = append(members, "("+t.Ref(from, m.Type)+" & {type: "+tsQuote(m.Tag)+"})")
+	}
+	return members
+}
+
+func tsQuote(s string) string {
+	return "'" + strings.NewReplacer(`\`, `\\`, `'`, `\'`).Replace(s) + "'"
+}
@@ -0,0 +1,84 @@
+package genapi
+
+import (
+	"go/ast"
+	"go/importer"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+const testTSSource = `
+package types
+
+import "time"
+
+type Status string
+
+const (
+	StatusOpen   Status = "open"
+	StatusClosed Status = "closed"
+)
+
+type Base struct {
+	CreatedAt time.Time ` + "`json:\"created_at\"`" + `
+}
+
+type Item struct {
+	Base
+	ID      int64             ` + "`json:\"id,string\"`" + `
+	Name    string            ` + "`json:\"name,omitempty\"`" + `
+	Status  Status            ` + "`json:\"status\"`" + `
+	Parent  *Item             ` + "`json:\"parent\"`" + `
+	Tags    []string          ` + "`json:\"tags\"`" + `
+	Attrs   map[string]Status ` + "`json:\"attrs\"`" + `
+	Data    []byte            ` + "`json:\"data\"`" + `
+	Secret  string            ` + "`json:\"-\"`" + `
+	private int
+}
+
+type Custom struct{}
+
+func (c Custom) MarshalJSON() ([]byte, error) { return nil, nil }
+
+type Response struct {
+	Items  []*Item ` + "`json:\"items\"`" + `
+	Custom Custom  ` + "`json:\"custom\"`" + `
+}
+`
+
+func TestTSTypes(t *testing.T) {
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, "types.go", testTSSource, 0)
+	require.NoError(t, err)
+	cfg := &types.Config{Importer: importer.ForCompiler(fset, "source", nil)}
+	pkg, err := cfg.Check("example.com/item/types", fset, []*ast.File{file}, nil)
+	require.NoError(t, err)
+
+	tt := newTSTypes(nil)
+	ref := tt.Ref("item", types.NewPointer(pkg.Scope().Lookup("Response").Type()))
+	require.Equal(t, "itemtypes.Response", ref)
+	require.Equal(t, map[string]bool{"itemtypes": true}, tt.modules["item"].Imports)
+
+	require.Equal(t, []string{
+		`export type Status = 'closed' | 'open';`,
+		`export interface Item {
+  created_at: string;
+  id: string;
+  name?: string;
+  status: Status;
+  parent?: Item;
+  tags: string[];
+  attrs: Record<string, Status>;
+  data: string;
+}`,
+		`export type Custom = unknown;`,
+		`export interface Response {
+  items: Item[];
+  custom: Custom;
+}`,
+	}, tt.modules["itemtypes"].Decls)
+}
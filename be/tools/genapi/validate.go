@@ -0,0 +1,229 @@
+package genapi
+
+import (
+	"fmt"
+	"go/types"
+	"strconv"
+	"strings"
+	"text/template"
+
+	"golang.org/x/tools/go/packages"
+
+	"github.com/olvrng/ggen"
+	"github.com/olvrng/rbot/be/tools/genutil"
+)
+
+var _ ggen.Plugin = &validatePlugin{}
+
+var validateTpl = template.Must(template.New("validate").Parse(validateTplText))
+
+type validatePlugin struct {
+	ggen.Qualifier
+}
+
+// NewValidate returns the plugin which generates the Validate methods of the
+// structs with +validate directives on their fields (see genutil.ValidateRule).
+// The generated servers call them before the handlers.
+func NewValidate() ggen.Plugin {
+	return &validatePlugin{Qualifier: genutil.Qualifier{}}
+}
+
+func (p *validatePlugin) Name() string { return "validate" }
+
+// Filter includes all the packages: the +validate directives are on the indented
+// fields, which ggen does not collect as package or inline directives. The
+// packages without them get no generated file.
+func (p *validatePlugin) Filter(ng ggen.FilterEngine) error {
+	for _, pkg := range ng.ParsingPackages() {
+		pkg.Include()
+	}
+	return nil
+}
+
+func (p *validatePlugin) Generate(ng ggen.Engine) error {
+	return ng.GenerateEachPackage(p.generatePackage)
+}
+
+type validateType struct {
+	Name   string
+	Checks []string
+}
+
+type validateRegexp struct {
+	Name string
+	Expr string
+}
+
+// Literal returns the expression as a Go string literal.
+func (re validateRegexp) Literal() string {
+	if strings.Contains(re.Expr, "`") {
+		return strconv.Quote(re.Expr)
+	}
+	return "`" + re.Expr + "`"
+}
+
+type validateGen struct {
+	ng      ggen.Engine
+	printer ggen.Printer
+	regexps []validateRegexp
+}
+
+func (p *validatePlugin) generatePackage(ng ggen.Engine, pkg *packages.Package, printer ggen.Printer) error {
+	ls.Debugf("validate: generating package %v", pkg.PkgPath)
+	g := &validateGen{ng: ng, printer: printer}
+	var typs []validateType
+	scope := pkg.Types.Scope()
+	for _, name := range scope.Names() {
+		obj, ok := scope.Lookup(name).(*types.TypeName)
+		if !ok || obj.IsAlias() {
+			continue
+		}
+		named, ok := obj.Type().(*types.Named)
+		if !ok || !genutil.HasValidate(ng, named) {
+			continue
+		}
+		checks, err := g.checks(named.Underlying().(*types.Struct))
+		if err != nil {
+			return ggen.Errorf(err, "type %v: %v", name, err)
+		}
+		typs = append(typs, validateType{Name: name, Checks: checks})
+	}
+	if len(typs) == 0 {
+		return nil
+	}
+	printer.Import("validate", "github.com/olvrng/rbot/be/pkg/validate")
+	return validateTpl.Execute(printer, map[string]interface{}{
+		"Regexps": g.regexps,
+		"Types":   typs,
+	})
+}
+
+// checks returns the statements which validate the fields of the struct m.
+func (g *validateGen) checks(st *types.Struct) ([]string, error) {
+	var checks []string
+	for _, field := range jsonFields(st) {
+		rules, err := genutil.ParseValidateRules(field.Var, g.ng.GetDirectives(field.Var))
+		if err != nil {
+			return nil, err
+		}
+		for _, rule := range rules {
+			checks = append(checks, g.check(field, rule))
+		}
+		if nested := genutil.ValidateNested(field.Type); nested != nil && genutil.HasValidate(g.ng, nested) {
+			checks = append(checks, g.nested(field))
+		}
+	}
+	return checks, nil
+}
+
+func (g *validateGen) check(field jsonField, rule *genutil.ValidateRule) string {
+	x := "m." + field.Var.Name()
+	_, isPtr := field.Type.(*types.Pointer)
+	value, guard := x, ""
+	if isPtr {
+		value, guard = "*"+x, x+" != nil"
+	} else if basic, ok := field.Type.Underlying().(*types.Basic); ok {
+		// the zero value is only rejected by required
+		if basic.Info()&types.IsString != 0 {
+			guard = x + ` != ""`
+		} else {
+			guard = x + " != 0"
+		}
+	}
+
+	var cond string
+	switch rule.Kind {
+	case genutil.ValidateRequired:
+		guard = ""
+		switch field.Type.Underlying().(type) {
+		case *types.Pointer, *types.Interface:
+			cond = x + " == nil"
+		case *types.Slice, *types.Map:
+			cond = "len(" + x + ") == 0"
+		default:
+			if basic := field.Type.Underlying().(*types.Basic); basic.Info()&types.IsString != 0 {
+				cond = x + ` == ""`
+			} else {
+				cond = x + " == 0"
+			}
+		}
+
+	case genutil.ValidateMin:
+		cond = value + " < " + rule.Arg
+
+	case genutil.ValidateMax:
+		cond = value + " > " + rule.Arg
+
+	case genutil.ValidateMaxLen:
+		if _, isString := derefType(field.Type).Underlying().(*types.Basic); isString {
+			g.printer.Import("utf8", "unicode/utf8")
+			cond = "utf8.RuneCountInString(" + asString(field.Type, value) + ") > " + rule.Arg
+		} else {
+			cond = "len(" + value + ") > " + rule.Arg
+		}
+		if !isPtr {
+			guard = ""
+		}
+
+	case genutil.ValidateEnum:
+		var conds []string
+		for _, v := range rule.Values {
+			conds = append(conds, value+" != "+v)
+		}
+		cond = strings.Join(conds, " && ")
+
+	case genutil.ValidateRegex:
+		g.printer.Import("regexp", "regexp")
+		cond = "!" + g.regexp(rule.Arg) + ".MatchString(" + asString(field.Type, value) + ")"
+	}
+	if guard != "" {
+		cond = guard + " && " + cond
+	}
+	return fmt.Sprintf("if %v {\n\t\terrs.Add(%q, %q)\n\t}", cond, field.Name, rule.Message)
+}
+
+// regexp returns the name of the compiled expr, declared once per package.
+func (g *validateGen) regexp(expr string) string {
+	for _, re := range g.regexps {
+		if re.Expr == expr {
+			return re.Name
+		}
+	}
+	name := fmt.Sprintf("validateRegexp%v", len(g.regexps))
+	g.regexps = append(g.regexps, validateRegexp{Name: name, Expr: expr})
+	return name
+}
+
+// asString converts the value of a named string type, such as ExportFormat.
+func asString(typ types.Type, value string) string {
+	if derefType(typ) == types.Typ[types.String] {
+		return value
+	}
+	return "string(" + value + ")"
+}
+
+func derefType(typ types.Type) types.Type {
+	if ptr, ok := typ.(*types.Pointer); ok {
+		return ptr.Elem()
+	}
+	return typ
+}
+
+// nested returns the statement which validates the nested struct of the field,
+// with its own Validate method.
+func (g *validateGen) nested(field jsonField) string {
+	x := "m." + field.Var.Name()
+	sl, isSlice := field.Type.(*types.Slice)
+	if !isSlice {
+		if _, isPtr := field.Type.(*types.Pointer); isPtr {
+			return fmt.Sprintf("if %v != nil {\n\t\terrs.Nested(%q, %v.Validate())\n\t}", x, field.Name, x)
+		}
+		return fmt.Sprintf("errs.Nested(%q, %v.Validate())", field.Name, x)
+	}
+	g.printer.Import("strconv", "strconv")
+	stmt := fmt.Sprintf("errs.Nested(%q+strconv.Itoa(i), %v[i].Validate())", field.Name+".", x)
+	if _, isPtr := sl.Elem().(*types.Pointer); isPtr {
+		stmt = fmt.Sprintf("if %v[i] != nil {\n\t\t\t%v\n\t\t}", x, stmt)
+	}
+	return fmt.Sprintf("for i := range %v {\n\t\t%v\n\t}", x, stmt)
+}
@@ -0,0 +1,16 @@
+package genapi
+
+const validateTplText = `
+{{range .Regexps -}}
+var {{.Name}} = regexp.MustCompile({{.Literal}})
+{{end -}}
+{{range .Types}}
+func (m *{{.Name}}) Validate() error {
+	errs := &validate.Errors{}
+{{- range .Checks}}
+	{{.}}
+{{- end}}
+	return errs.Err()
+}
+{{end -}}
+`
@@ -16,3 +16,20 @@ var A []*NamedStruct
 var B []*NamedStruct
 var C NamedSliceOfPtrNamedStruct
 var D NamedSliceOfPtrNamedStruct2
+
+type Status string
+
+const (
+	StatusOpen   Status = "open"
+	StatusClosed Status = "closed"
+)
+
+type ValidateStruct struct {
+	Name    string
+	Count   int
+	Ratio   *float64
+	Status  Status
+	Tags    []string
+	Nested  *NamedStruct
+	Enabled bool
+}
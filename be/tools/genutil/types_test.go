@@ -8,7 +8,7 @@ import (
 	"golang.org/x/tools/go/packages"
 )
 
-const testPath = "github.com/olvrng/rbot/tools/genutil/testdata"
+const testPath = "github.com/olvrng/rbot/be/tools/genutil/testdata"
 
 var initialized bool
 var testPkg *packages.Package
@@ -90,7 +90,7 @@ func TestCheckType(t *testing.T) {
 		namedStruct := getType(t, "NamedStruct")
 
 		_, err := CheckType(namedSlice, namedStruct)
-		require.EqualError(t, err, "must be named type o.o/backend/tools/pkg/genutil/testdata.NamedStruct")
+		require.EqualError(t, err, "must be named type github.com/olvrng/rbot/be/tools/genutil/testdata.NamedStruct")
 	})
 	t.Run("named slice of ptr named struct", func(t *testing.T) {
 		result, err := CheckType(namedSlice, Named, Slice, Pointer, Named, Struct)
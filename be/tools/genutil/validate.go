@@ -0,0 +1,266 @@
+package genutil
+
+import (
+	"go/constant"
+	"go/token"
+	"go/types"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/olvrng/ggen"
+)
+
+// The rules of the +validate directives on the fields of the requests:
+//
+//	// +validate:required
+//	// +validate:min=1 +validate:max=100
+//	// +validate:max-len=200
+//	// +validate:enum=csv,json
+//	// +validate:regex: ^[a-z_]+$
+//
+// The enum without values accepts the constants of the named type of the field.
+// The rules other than required only apply to the set values: the zero value of
+// a field, or its nil pointer, is only rejected by required.
+const (
+	ValidateRequired = "required"
+	ValidateMin      = "min"
+	ValidateMax      = "max"
+	ValidateMaxLen   = "max-len"
+	ValidateEnum     = "enum"
+	ValidateRegex    = "regex"
+)
+
+const validateCommand = "validate"
+
+type ValidateRule struct {
+	Kind string
+
+	// Arg is the argument of the directive. For min, max and max-len, it is a
+	// Go literal of the bound.
+	Arg string
+
+	// Values are the values of enum, as Go literals.
+	Values []string
+
+	// Message describes the rule to the caller, such as "must be at most 100".
+	Message string
+}
+
+// ParseValidateRules returns the rules of the +validate directives of the
+// field, and checks that they apply to its type.
+func ParseValidateRules(field *types.Var, ds ggen.Directives) ([]*ValidateRule, error) {
+	var rules []*ValidateRule
+	for _, d := range ds.FilterBy(validateCommand) {
+		rule, err := parseValidateRule(field.Type(), d)
+		if err != nil {
+			return nil, ggen.Errorf(err, "field %v: invalid directive %v: %v", field.Name(), d.Raw, err)
+		}
+		for _, r := range rules {
+			if r.Kind == rule.Kind {
+				return nil, ggen.Errorf(nil, "field %v: duplicated directive %v", field.Name(), d.Raw)
+			}
+		}
+		rules = append(rules, rule)
+	}
+	return rules, nil
+}
+
+func parseValidateRule(typ types.Type, d ggen.Directive) (*ValidateRule, error) {
+	kind := strings.TrimPrefix(d.Cmd, validateCommand+":")
+	rule := &ValidateRule{Kind: kind, Arg: d.Arg}
+	if kind != ValidateEnum && kind != ValidateRequired && d.Arg == "" {
+		return nil, ggen.Errorf(nil, "missing argument")
+	}
+
+	elem := typ
+	if ptr, ok := typ.(*types.Pointer); ok {
+		elem = ptr.Elem()
+	}
+	basic, _ := elem.Underlying().(*types.Basic)
+	isString := basic != nil && basic.Info()&types.IsString != 0
+	isInteger := basic != nil && basic.Info()&types.IsInteger != 0
+	isNumber := basic != nil && basic.Info()&(types.IsInteger|types.IsFloat) != 0
+
+	switch kind {
+	case ValidateRequired:
+		if d.Arg != "" {
+			return nil, ggen.Errorf(nil, "unexpected argument")
+		}
+		switch underlying := typ.Underlying().(type) {
+		case *types.Pointer, *types.Slice, *types.Map, *types.Interface:
+		case *types.Basic:
+			if underlying.Info()&(types.IsString|types.IsInteger|types.IsFloat) == 0 {
+				return nil, ggen.Errorf(nil, "required does not apply to %v", typ)
+			}
+		default:
+			return nil, ggen.Errorf(nil, "required does not apply to %v", typ)
+		}
+		rule.Message = "is required"
+
+	case ValidateMin, ValidateMax:
+		if !isNumber {
+			return nil, ggen.Errorf(nil, "%v only applies to numbers", kind)
+		}
+		if err := checkNumber(d.Arg, isInteger); err != nil {
+			return nil, err
+		}
+		if kind == ValidateMin {
+			rule.Message = "must be at least " + d.Arg
+		} else {
+			rule.Message = "must be at most " + d.Arg
+		}
+
+	case ValidateMaxLen:
+		n, err := strconv.Atoi(d.Arg)
+		if err != nil || n < 0 {
+			return nil, ggen.Errorf(nil, "max-len must be a positive integer")
+		}
+		switch elem.Underlying().(type) {
+		case *types.Slice, *types.Map:
+			rule.Message = "must have at most " + d.Arg + " items"
+		default:
+			if !isString {
+				return nil, ggen.Errorf(nil, "max-len only applies to strings, slices and maps")
+			}
+			rule.Message = "must have at most " + d.Arg + " characters"
+		}
+
+	case ValidateEnum:
+		if !isString && !isInteger {
+			return nil, ggen.Errorf(nil, "enum only applies to strings and integers")
+		}
+		var values, display []string
+		if d.Arg == "" {
+			named, ok := elem.(*types.Named)
+			if !ok {
+				return nil, ggen.Errorf(nil, "enum without values only applies to named types")
+			}
+			for _, v := range EnumValues(named) {
+				values = append(values, v.ExactString())
+				if v.Kind() == constant.String {
+					display = append(display, constant.StringVal(v))
+				} else {
+					display = append(display, v.ExactString())
+				}
+			}
+			if len(values) == 0 {
+				return nil, ggen.Errorf(nil, "no constant of type %v", named)
+			}
+		} else {
+			for _, s := range strings.Split(d.Arg, ",") {
+				if isString {
+					values = append(values, strconv.Quote(s))
+				} else {
+					if err := checkNumber(s, true); err != nil {
+						return nil, err
+					}
+					values = append(values, s)
+				}
+				display = append(display, s)
+			}
+		}
+		rule.Values = values
+		rule.Message = "must be one of " + strings.Join(display, ", ")
+
+	case ValidateRegex:
+		if !isString {
+			return nil, ggen.Errorf(nil, "regex only applies to strings")
+		}
+		if _, err := regexp.Compile(d.Arg); err != nil {
+			return nil, ggen.Errorf(err, "invalid regex: %v", err)
+		}
+		rule.Message = "must match " + d.Arg
+
+	default:
+		return nil, ggen.Errorf(nil, "unknown rule %v", kind)
+	}
+	return rule, nil
+}
+
+func checkNumber(s string, integer bool) error {
+	if integer {
+		if _, err := strconv.ParseInt(s, 10, 64); err != nil {
+			return ggen.Errorf(nil, "%v is not an integer", s)
+		}
+		return nil
+	}
+	if _, err := strconv.ParseFloat(s, 64); err != nil {
+		return ggen.Errorf(nil, "%v is not a number", s)
+	}
+	return nil
+}
+
+// EnumValues returns the constants of the named type, such as the roles, in the
+// order of their values.
+func EnumValues(named *types.Named) []constant.Value {
+	scope := named.Obj().Pkg().Scope()
+	var values []constant.Value
+	for _, name := range scope.Names() {
+		c, ok := scope.Lookup(name).(*types.Const)
+		if !ok || c.Type() != named {
+			continue
+		}
+		dup := false
+		for _, v := range values {
+			dup = dup || constant.Compare(v, token.EQL, c.Val())
+		}
+		if !dup {
+			values = append(values, c.Val())
+		}
+	}
+	sort.Slice(values, func(i, j int) bool {
+		return constant.Compare(values[i], token.LSS, values[j])
+	})
+	return values
+}
+
+// HasValidate reports whether the struct gets a generated Validate method: it
+// has +validate directives on its fields, or a nested struct of the same package
+// has them. The nested structs of other packages are validated by their own
+// Validate methods.
+func HasValidate(ng ggen.Engine, named *types.Named) bool {
+	return hasValidate(ng, named, map[*types.Named]bool{})
+}
+
+func hasValidate(ng ggen.Engine, named *types.Named, visited map[*types.Named]bool) bool {
+	if visited[named] {
+		return false
+	}
+	visited[named] = true
+	st, ok := named.Underlying().(*types.Struct)
+	if !ok {
+		return false
+	}
+	for i, n := 0, st.NumFields(); i < n; i++ {
+		field := st.Field(i)
+		if len(ng.GetDirectives(field).FilterBy(validateCommand)) != 0 {
+			return true
+		}
+		nested := ValidateNested(field.Type())
+		if nested != nil && nested.Obj().Pkg() == named.Obj().Pkg() && hasValidate(ng, nested, visited) {
+			return true
+		}
+	}
+	return false
+}
+
+// ValidateNested returns the named struct of a field which may have its own
+// Validate method: the struct, a pointer to it, or a slice of them.
+func ValidateNested(typ types.Type) *types.Named {
+	if sl, ok := typ.(*types.Slice); ok {
+		typ = sl.Elem()
+	}
+	if ptr, ok := typ.(*types.Pointer); ok {
+		typ = ptr.Elem()
+	}
+	named, ok := typ.(*types.Named)
+	if !ok {
+		return nil
+	}
+	if _, ok := named.Underlying().(*types.Struct); !ok {
+		return nil
+	}
+	return named
+}
@@ -0,0 +1,135 @@
+package genutil
+
+import (
+	"go/types"
+	"testing"
+
+	"github.com/olvrng/ggen"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseValidateRules(t *testing.T) {
+	initOnce(t)
+	st := getType(t, "ValidateStruct").Underlying().(*types.Struct)
+	field := func(name string) *types.Var {
+		for i := 0; i < st.NumFields(); i++ {
+			if st.Field(i).Name() == name {
+				return st.Field(i)
+			}
+		}
+		t.Fatalf("field %v not found", name)
+		return nil
+	}
+
+	tests := []struct {
+		name      string
+		field     string
+		directive string
+		expected  []*ValidateRule
+		err       string
+	}{
+		{
+			name: "required", field: "Name", directive: "+validate:required",
+			expected: []*ValidateRule{{Kind: "required", Message: "is required"}},
+		},
+		{
+			name: "required pointer", field: "Nested", directive: "+validate:required",
+			expected: []*ValidateRule{{Kind: "required", Message: "is required"}},
+		},
+		{
+			name: "min and max", field: "Count", directive: "+validate:min=1 +validate:max=100",
+			expected: []*ValidateRule{
+				{Kind: "min", Arg: "1", Message: "must be at least 1"},
+				{Kind: "max", Arg: "100", Message: "must be at most 100"},
+			},
+		},
+		{
+			name: "min of pointer to float", field: "Ratio", directive: "+validate:min=0.5",
+			expected: []*ValidateRule{{Kind: "min", Arg: "0.5", Message: "must be at least 0.5"}},
+		},
+		{
+			name: "max-len of string", field: "Name", directive: "+validate:max-len=20",
+			expected: []*ValidateRule{{Kind: "max-len", Arg: "20", Message: "must have at most 20 characters"}},
+		},
+		{
+			name: "max-len of slice", field: "Tags", directive: "+validate:max-len=3",
+			expected: []*ValidateRule{{Kind: "max-len", Arg: "3", Message: "must have at most 3 items"}},
+		},
+		{
+			name: "enum with values", field: "Name", directive: "+validate:enum=csv,json",
+			expected: []*ValidateRule{{Kind: "enum", Arg: "csv,json", Values: []string{`"csv"`, `"json"`}, Message: "must be one of csv, json"}},
+		},
+		{
+			name: "enum of constants", field: "Status", directive: "+validate:enum",
+			expected: []*ValidateRule{{Kind: "enum", Values: []string{`"closed"`, `"open"`}, Message: "must be one of closed, open"}},
+		},
+		{
+			name: "enum of integers", field: "Count", directive: "+validate:enum=1,2",
+			expected: []*ValidateRule{{Kind: "enum", Arg: "1,2", Values: []string{"1", "2"}, Message: "must be one of 1, 2"}},
+		},
+		{
+			name: "regex with spaces", field: "Name", directive: "+validate:regex: ^[a-z]+ [a-z]+$",
+			expected: []*ValidateRule{{Kind: "regex", Arg: "^[a-z]+ [a-z]+$", Message: "must match ^[a-z]+ [a-z]+$"}},
+		},
+		{
+			name: "other directives are skipped", field: "Name", directive: "+api:path=/foo",
+		},
+		{
+			name: "required bool (error)", field: "Enabled", directive: "+validate:required",
+			err: "required does not apply to bool",
+		},
+		{
+			name: "min of string (error)", field: "Name", directive: "+validate:min=1",
+			err: "min only applies to numbers",
+		},
+		{
+			name: "min not integer (error)", field: "Count", directive: "+validate:min=1.5",
+			err: "1.5 is not an integer",
+		},
+		{
+			name: "max-len of int (error)", field: "Count", directive: "+validate:max-len=1",
+			err: "max-len only applies to strings, slices and maps",
+		},
+		{
+			name: "enum without constants (error)", field: "Name", directive: "+validate:enum",
+			err: "enum without values only applies to named types",
+		},
+		{
+			name: "invalid regex (error)", field: "Name", directive: "+validate:regex: [a-z",
+			err: "invalid regex",
+		},
+		{
+			name: "unknown rule (error)", field: "Name", directive: "+validate:email=true",
+			err: "unknown rule email",
+		},
+		{
+			name: "duplicated (error)", field: "Count", directive: "+validate:min=1 +validate:min=2",
+			err: "field Count: duplicated directive +validate:min=2",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ds, err := ggen.ParseDirective(tt.directive)
+			require.NoError(t, err)
+			rules, err := ParseValidateRules(field(tt.field), ds)
+			if tt.err != "" {
+				require.Error(t, err)
+				require.Contains(t, err.Error(), tt.err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tt.expected, rules)
+		})
+	}
+}
+
+func TestValidateNested(t *testing.T) {
+	initOnce(t)
+	namedStruct := getType(t, "NamedStruct")
+
+	require.Equal(t, namedStruct, ValidateNested(namedStruct))
+	require.Equal(t, namedStruct, ValidateNested(types.NewPointer(namedStruct)))
+	require.Equal(t, namedStruct, ValidateNested(testScope.Lookup("A").Type()))
+	require.Nil(t, ValidateNested(getType(t, "NamedInt")))
+	require.Nil(t, ValidateNested(types.NewMap(types.Typ[types.String], namedStruct)))
+}